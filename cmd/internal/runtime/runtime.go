@@ -0,0 +1,520 @@
+// Package runtime provides the components shared by token-monitor commands.
+//
+// A Runtime loads configuration once and creates the logger, session
+// manager, reader, discoverer, and rollup, baseline, focus, and ticket
+// stores on first use, so each command asks only for what it needs and
+// every command wires components the same way. Close releases whatever
+// was opened.
+//
+// Example usage:
+//
+//	rt := runtime.New(runtime.Options{ConfigPath: path})
+//	defer rt.Close()
+//
+//	disc, err := rt.Discoverer()
+//	if err != nil {
+//	    return err
+//	}
+//	r, err := rt.Reader()
+//	if err != nil {
+//	    return err
+//	}
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/0xmhha/token-monitor/pkg/baseline"
+	"github.com/0xmhha/token-monitor/pkg/config"
+	"github.com/0xmhha/token-monitor/pkg/discovery"
+	"github.com/0xmhha/token-monitor/pkg/focus"
+	"github.com/0xmhha/token-monitor/pkg/logger"
+	"github.com/0xmhha/token-monitor/pkg/parser"
+	"github.com/0xmhha/token-monitor/pkg/reader"
+	"github.com/0xmhha/token-monitor/pkg/rollup"
+	"github.com/0xmhha/token-monitor/pkg/session"
+	"github.com/0xmhha/token-monitor/pkg/ticket"
+)
+
+// ErrTemporaryDatabase is returned by Persistent when the session database
+// could not be opened and a temporary one stands in for it.
+var ErrTemporaryDatabase = errors.New("session database unavailable, changes cannot be saved")
+
+// Options configures a Runtime.
+type Options struct {
+	// ConfigPath is an explicit configuration file. Empty searches the
+	// default locations.
+	ConfigPath string
+
+	// NewLogger builds the command logger from the loaded configuration.
+	// Nil uses the logging section of the configuration as is.
+	NewLogger func(cfg *config.Config) logger.Logger
+
+	// NoCache disables the discovery cache.
+	NoCache bool
+
+	// Force ignores the large-file limits (performance.max_file_size_mb
+	// and performance.max_entries_per_read).
+	Force bool
+
+	// ClaudeDirs replaces the configured Claude directories when set.
+	ClaudeDirs []string
+}
+
+// Runtime lazily creates and owns command components.
+//
+// Each accessor initializes its component on first call and returns the
+// same instance (or the same error) afterwards.
+//
+// Thread-safety: Safe for concurrent use.
+type Runtime struct {
+	opts Options
+
+	mu        sync.Mutex
+	cfg       *config.Config
+	cfgErr    error
+	log       logger.Logger
+	disc      discovery.Discoverer
+	sessions  session.Manager
+	sessErr   error
+	sessTried bool
+	sessTemp  error // why a temporary database stands in, if one does
+	positions reader.PositionStore
+	reader    reader.Reader
+	rollups   rollup.Store
+	baselines baseline.Store
+	focus     focus.Store
+	tickets   ticket.Store
+}
+
+// New creates a runtime. Nothing is loaded until first use.
+func New(opts Options) *Runtime {
+	return &Runtime{opts: opts}
+}
+
+// Config returns the loaded configuration.
+func (rt *Runtime) Config() (*config.Config, error) {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	return rt.config()
+}
+
+// Logger returns the command logger.
+func (rt *Runtime) Logger() (logger.Logger, error) {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	return rt.logger()
+}
+
+// Discoverer returns a discoverer for the configured directories and
+// session patterns.
+func (rt *Runtime) Discoverer() (discovery.Discoverer, error) {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+
+	if rt.disc != nil {
+		return rt.disc, nil
+	}
+
+	cfg, err := rt.config()
+	if err != nil {
+		return nil, err
+	}
+	log, err := rt.logger()
+	if err != nil {
+		return nil, err
+	}
+
+	rt.disc = NewDiscoverer(cfg, log, rt.opts.NoCache)
+	return rt.disc, nil
+}
+
+// Sessions returns the session manager, opening the BoltDB database.
+// The database is locked while open, so this fails when another process
+// such as watch holds it. When the database cannot be opened for another
+// reason, such as a read-only file system, a temporary database stands in
+// with a warning, so commands that only read keep working; see Persistent.
+func (rt *Runtime) Sessions() (session.Manager, error) {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	return rt.sessionManager()
+}
+
+// Persistent returns an error wrapping ErrTemporaryDatabase when Sessions
+// uses a temporary database, or the error of Sessions. Commands that save
+// state call it first, so they fail instead of discarding the change.
+func (rt *Runtime) Persistent() error {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+
+	if _, err := rt.sessionManager(); err != nil {
+		return err
+	}
+	if rt.sessTemp != nil {
+		return fmt.Errorf("%w: %w", ErrTemporaryDatabase, rt.sessTemp)
+	}
+	return nil
+}
+
+// PositionStore returns the BoltDB position store, or an in-memory store
+// when the database is unavailable. The fallback is logged, not returned,
+// so read-only commands keep working while watch holds the database.
+func (rt *Runtime) PositionStore() (reader.PositionStore, error) {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	return rt.positionStore()
+}
+
+// Reader returns a reader that tracks file positions in PositionStore,
+// so repeated reads return only new entries.
+func (rt *Runtime) Reader() (reader.Reader, error) {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+
+	if rt.reader != nil {
+		return rt.reader, nil
+	}
+
+	log, err := rt.logger()
+	if err != nil {
+		return nil, err
+	}
+	positions, err := rt.positionStore()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := rt.config()
+	if err != nil {
+		return nil, err
+	}
+	r, err := reader.New(rt.readerConfig(cfg, positions), log)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize reader: %w", err)
+	}
+	rt.reader = r
+	return r, nil
+}
+
+// NewReader returns a new reader with a private in-memory position store,
+// so every file is read from the start. The caller closes it.
+func (rt *Runtime) NewReader() (reader.Reader, error) {
+	log, err := rt.Logger()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := rt.Config()
+	if err != nil {
+		return nil, err
+	}
+	r, err := reader.New(rt.readerConfig(cfg, reader.NewMemoryPositionStore()), log)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize reader: %w", err)
+	}
+	return r, nil
+}
+
+// readerConfig returns the reader configuration with the configured
+// large-file limits, or none when Force is set.
+func (rt *Runtime) readerConfig(cfg *config.Config, positions reader.PositionStore) reader.Config {
+	pc := rt.parserConfig(cfg)
+	if !rt.opts.Force {
+		pc.MaxEntries = cfg.Performance.MaxEntriesPerRead
+	}
+	return reader.Config{
+		PositionStore: positions,
+		Parser:        parser.NewWithConfig(pc),
+		MaxFileSize:   pc.MaxFileSize,
+	}
+}
+
+// Parser returns a parser with the configured file size limit and
+// missing session ID policy, for commands that parse whole files
+// directly rather than through a reader.
+func (rt *Runtime) Parser() (parser.Parser, error) {
+	cfg, err := rt.Config()
+	if err != nil {
+		return nil, err
+	}
+	return parser.NewWithConfig(rt.parserConfig(cfg)), nil
+}
+
+// parserConfig returns the parser configuration without an entry limit.
+func (rt *Runtime) parserConfig(cfg *config.Config) parser.Config {
+	maxSize := int64(cfg.Performance.MaxFileSizeMB) * 1024 * 1024
+	if rt.opts.Force {
+		maxSize = -1
+	}
+	return parser.Config{
+		MaxFileSize:      maxSize,
+		MissingSessionID: parser.MissingSessionIDPolicy(cfg.Discovery.MissingSessionID),
+		Attribution:      parser.Attribution(cfg.Discovery.SessionAttribution),
+	}
+}
+
+// Rollups returns the rollup store. It requires the session database.
+func (rt *Runtime) Rollups() (rollup.Store, error) {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+
+	if rt.rollups != nil {
+		return rt.rollups, nil
+	}
+
+	mgr, err := rt.sessionManager()
+	if err != nil {
+		return nil, err
+	}
+
+	store, err := rollup.New(mgr.DB())
+	if err != nil {
+		return nil, err
+	}
+	rt.rollups = store
+	return store, nil
+}
+
+// Baselines returns the baseline store. It requires the session database.
+func (rt *Runtime) Baselines() (baseline.Store, error) {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+
+	if rt.baselines != nil {
+		return rt.baselines, nil
+	}
+
+	mgr, err := rt.sessionManager()
+	if err != nil {
+		return nil, err
+	}
+
+	store, err := baseline.New(mgr.DB())
+	if err != nil {
+		return nil, err
+	}
+	rt.baselines = store
+	return store, nil
+}
+
+// Focus returns the focus window store. It requires the session database.
+func (rt *Runtime) Focus() (focus.Store, error) {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+
+	if rt.focus != nil {
+		return rt.focus, nil
+	}
+
+	mgr, err := rt.sessionManager()
+	if err != nil {
+		return nil, err
+	}
+
+	store, err := focus.New(mgr.DB())
+	if err != nil {
+		return nil, err
+	}
+	rt.focus = store
+	return store, nil
+}
+
+// Tickets returns the session ticket store. It requires the session
+// database.
+func (rt *Runtime) Tickets() (ticket.Store, error) {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+
+	if rt.tickets != nil {
+		return rt.tickets, nil
+	}
+
+	mgr, err := rt.sessionManager()
+	if err != nil {
+		return nil, err
+	}
+
+	store, err := ticket.New(mgr.DB())
+	if err != nil {
+		return nil, err
+	}
+	rt.tickets = store
+	return store, nil
+}
+
+// Close releases the reader and session database. It is safe to call
+// more than once.
+func (rt *Runtime) Close() error {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+
+	var firstErr error
+	if rt.reader != nil {
+		if err := rt.reader.Close(); err != nil {
+			firstErr = fmt.Errorf("failed to close reader: %w", err)
+		}
+		rt.reader = nil
+	}
+	if rt.sessions != nil {
+		if err := rt.sessions.Close(); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("failed to close session manager: %w", err)
+		}
+		rt.sessions = nil
+	}
+	rt.sessTried = false
+	rt.sessTemp = nil
+	rt.positions = nil
+	rt.rollups = nil
+	rt.baselines = nil
+	rt.focus = nil
+	rt.tickets = nil
+	return firstErr
+}
+
+// config loads the configuration once. Must be called with rt.mu held.
+func (rt *Runtime) config() (*config.Config, error) {
+	if rt.cfg == nil && rt.cfgErr == nil {
+		cfg, err := config.NewLoader(rt.opts.ConfigPath).Load()
+		if err != nil {
+			rt.cfgErr = fmt.Errorf("failed to load config: %w", err)
+		} else {
+			if len(rt.opts.ClaudeDirs) > 0 {
+				cfg.ClaudeConfigDirs = config.ClaudeDirs(rt.opts.ClaudeDirs...)
+			}
+			rt.cfg = cfg
+			rt.warnUnknownKeys(cfg)
+		}
+	}
+	return rt.cfg, rt.cfgErr
+}
+
+// warnUnknownKeys logs the keys of the config file that are not settings,
+// which are otherwise silently ignored. Must be called with rt.mu held.
+func (rt *Runtime) warnUnknownKeys(cfg *config.Config) {
+	unknown := cfg.UnknownKeys()
+	if len(unknown) == 0 {
+		return
+	}
+	log, err := rt.logger()
+	if err != nil {
+		return
+	}
+	for _, key := range unknown {
+		if key.Suggestion != "" {
+			log.Warn("unknown config key", "key", key.Key, "line", key.Line, "did_you_mean", key.Suggestion)
+		} else {
+			log.Warn("unknown config key", "key", key.Key, "line", key.Line)
+		}
+	}
+}
+
+// logger creates the logger once. Must be called with rt.mu held.
+func (rt *Runtime) logger() (logger.Logger, error) {
+	if rt.log != nil {
+		return rt.log, nil
+	}
+
+	cfg, err := rt.config()
+	if err != nil {
+		return nil, err
+	}
+
+	if rt.opts.NewLogger != nil {
+		rt.log = rt.opts.NewLogger(cfg)
+	} else {
+		rt.log = logger.New(logger.Config{
+			Level:  cfg.Logging.Level,
+			Format: cfg.Logging.Format,
+			Output: cfg.Logging.Output,
+		})
+	}
+	return rt.log, nil
+}
+
+// sessionManager opens the session database once. Must be called with
+// rt.mu held.
+func (rt *Runtime) sessionManager() (session.Manager, error) {
+	if rt.sessTried {
+		return rt.sessions, rt.sessErr
+	}
+
+	cfg, err := rt.config()
+	if err != nil {
+		return nil, err
+	}
+	log, err := rt.logger()
+	if err != nil {
+		return nil, err
+	}
+
+	rt.sessTried = true
+	rt.sessions, rt.sessErr = session.New(SessionConfig(cfg), log)
+	if rt.sessErr == nil || errors.Is(rt.sessErr, session.ErrDatabaseLocked) {
+		return rt.sessions, rt.sessErr
+	}
+
+	temp, err := session.NewTemporary(SessionConfig(cfg), log)
+	if err != nil {
+		log.Debug("temporary session database unavailable", "error", err)
+		return rt.sessions, rt.sessErr
+	}
+	log.Warn("session database unavailable, using a temporary one: session names and saved state are missing and changes are discarded",
+		"db_path", cfg.Storage.DBPath,
+		"error", rt.sessErr)
+	rt.sessions, rt.sessTemp, rt.sessErr = temp, rt.sessErr, nil
+	return rt.sessions, nil
+}
+
+// positionStore picks the position store once. Must be called with rt.mu
+// held.
+func (rt *Runtime) positionStore() (reader.PositionStore, error) {
+	if rt.positions != nil {
+		return rt.positions, nil
+	}
+
+	log, err := rt.logger()
+	if err != nil {
+		return nil, err
+	}
+
+	mgr, err := rt.sessionManager()
+	if err != nil {
+		log.Warn("BoltDB unavailable, using in-memory position store", "error", err)
+		rt.positions = reader.NewMemoryPositionStore()
+		return rt.positions, nil
+	}
+
+	rt.positions, err = reader.NewBoltPositionStore(mgr.DB())
+	if err != nil {
+		log.Warn("failed to create BoltDB position store, using in-memory", "error", err)
+		rt.positions = reader.NewMemoryPositionStore()
+	}
+	return rt.positions, nil
+}
+
+// NewDiscoverer returns a session discoverer for the configured
+// directories, session patterns, and extra files.
+func NewDiscoverer(cfg *config.Config, log logger.Logger, noCache bool) discovery.Discoverer {
+	discCfg := discovery.Config{
+		BaseDirs:        cfg.ClaudeDirPaths(),
+		Labels:          cfg.ClaudeDirLabels(),
+		SessionPatterns: cfg.Discovery.SessionPatterns,
+		AnyJSONL:        cfg.Discovery.AnyJSONL,
+		Files:           cfg.Discovery.Files,
+	}
+	if !noCache {
+		discCfg.CacheDir = cfg.Storage.CacheDir
+	}
+	return discovery.NewWithConfig(discCfg, log)
+}
+
+// SessionConfig returns the session manager configuration for cfg.
+func SessionConfig(cfg *config.Config) session.Config {
+	return session.Config{
+		DBPath:            cfg.Storage.DBPath,
+		NameValidation:    cfg.Session.NameValidation,
+		NameNormalization: cfg.Session.NameNormalization,
+	}
+}
@@ -0,0 +1,154 @@
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// boltStoreType is the dynamic type of the BoltDB position store.
+const boltStoreType = "*reader.boltPositionStore"
+
+// setupEnv points configuration at temporary directories.
+func setupEnv(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("CLAUDE_CONFIG_DIR", dir)
+	t.Setenv("TOKEN_MONITOR_DB", filepath.Join(dir, "sessions.db"))
+	t.Setenv("TOKEN_MONITOR_LOG_LEVEL", "error")
+	return dir
+}
+
+func TestRuntimeReusesComponents(t *testing.T) {
+	setupEnv(t)
+
+	rt := New(Options{NoCache: true})
+	defer func() { _ = rt.Close() }() //nolint:errcheck
+
+	mgr1, err := rt.Sessions()
+	if err != nil {
+		t.Fatalf("Sessions() error = %v", err)
+	}
+	mgr2, err := rt.Sessions()
+	if err != nil {
+		t.Fatalf("Sessions() error = %v", err)
+	}
+	if mgr1 != mgr2 {
+		t.Error("Sessions() returned different managers")
+	}
+
+	r1, err := rt.Reader()
+	if err != nil {
+		t.Fatalf("Reader() error = %v", err)
+	}
+	r2, err := rt.Reader()
+	if err != nil {
+		t.Fatalf("Reader() error = %v", err)
+	}
+	if r1 != r2 {
+		t.Error("Reader() returned different readers")
+	}
+
+	store, err := rt.PositionStore()
+	if err != nil {
+		t.Fatalf("PositionStore() error = %v", err)
+	}
+	if got := fmt.Sprintf("%T", store); got != boltStoreType {
+		t.Errorf("PositionStore() = %s, want %s", got, boltStoreType)
+	}
+
+	if _, err := rt.Rollups(); err != nil {
+		t.Errorf("Rollups() error = %v", err)
+	}
+	if _, err := rt.Discoverer(); err != nil {
+		t.Errorf("Discoverer() error = %v", err)
+	}
+}
+
+func TestRuntimePositionStoreFallback(t *testing.T) {
+	setupEnv(t)
+
+	// The first runtime holds the database lock.
+	holder := New(Options{})
+	defer func() { _ = holder.Close() }() //nolint:errcheck
+	if _, err := holder.Sessions(); err != nil {
+		t.Fatalf("Sessions() error = %v", err)
+	}
+
+	rt := New(Options{})
+	defer func() { _ = rt.Close() }() //nolint:errcheck
+
+	if _, err := rt.Sessions(); err == nil {
+		t.Fatal("Sessions() succeeded while the database is locked")
+	}
+
+	store, err := rt.PositionStore()
+	if err != nil {
+		t.Fatalf("PositionStore() error = %v", err)
+	}
+	if got := fmt.Sprintf("%T", store); got == boltStoreType {
+		t.Error("PositionStore() returned the BoltDB store while the database is locked")
+	}
+	if _, err := rt.Reader(); err != nil {
+		t.Errorf("Reader() error = %v", err)
+	}
+}
+
+func TestRuntimeTemporaryDatabase(t *testing.T) {
+	dir := setupEnv(t)
+
+	// A file where the database directory should be, like a read-only
+	// mount, makes the database impossible to create.
+	blocker := filepath.Join(dir, "blocker")
+	if err := os.WriteFile(blocker, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("TOKEN_MONITOR_DB", filepath.Join(blocker, "sessions.db"))
+
+	rt := New(Options{})
+	defer func() { _ = rt.Close() }() //nolint:errcheck
+
+	mgr, err := rt.Sessions()
+	if err != nil {
+		t.Fatalf("Sessions() error = %v, want a temporary database", err)
+	}
+	if _, err := mgr.List(); err != nil {
+		t.Errorf("List() error = %v", err)
+	}
+	if _, err := rt.Rollups(); err != nil {
+		t.Errorf("Rollups() error = %v", err)
+	}
+	if err := rt.Persistent(); !errors.Is(err, ErrTemporaryDatabase) {
+		t.Errorf("Persistent() error = %v, want ErrTemporaryDatabase", err)
+	}
+
+	other := New(Options{})
+	defer func() { _ = other.Close() }() //nolint:errcheck
+	t.Setenv("TOKEN_MONITOR_DB", filepath.Join(dir, "sessions.db"))
+	if err := other.Persistent(); err != nil {
+		t.Errorf("Persistent() with a usable database error = %v", err)
+	}
+}
+
+func TestRuntimeCloseReleasesDatabase(t *testing.T) {
+	setupEnv(t)
+
+	rt := New(Options{})
+	if _, err := rt.Sessions(); err != nil {
+		t.Fatalf("Sessions() error = %v", err)
+	}
+	if err := rt.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if err := rt.Close(); err != nil {
+		t.Errorf("second Close() error = %v", err)
+	}
+
+	other := New(Options{})
+	defer func() { _ = other.Close() }() //nolint:errcheck
+	if _, err := other.Sessions(); err != nil {
+		t.Errorf("Sessions() after Close() error = %v", err)
+	}
+}
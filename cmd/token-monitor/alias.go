@@ -0,0 +1,144 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/0xmhha/token-monitor/pkg/config"
+)
+
+// builtinCommands lists the commands handled by the dispatcher in run.
+// Built-in commands always take precedence over aliases of the same name.
+var builtinCommands = map[string]bool{
+	"tui":           true,
+	"stats":         true,
+	"list":          true,
+	"watch":         true,
+	"session":       true,
+	"config":        true,
+	"query":         true,
+	"status":        true,
+	"serve":         true,
+	"install":       true,
+	"repl":          true,
+	"report":        true,
+	"fsck":          true,
+	"health":        true,
+	"debug":         true,
+	"logs":          true,
+	"baseline":      true,
+	"focus":         true,
+	"tickets":       true,
+	"team":          true,
+	"whatif":        true,
+	"api":           true,
+	"calendar":      true,
+	"today":         true,
+	"dump":          true,
+	"history":       true,
+	"project":       true,
+	"hook-receiver": true,
+	"notify":        true,
+	"annotate":      true,
+	"telemetry":     true,
+	"version":       true,
+	"help":          true,
+}
+
+// maxAliasDepth bounds alias expansion as a backstop to cycle detection.
+const maxAliasDepth = 10
+
+// loadAliases returns the aliases defined in configuration.
+// Config errors are ignored here; commands report them when they load config.
+func (g globalOptions) loadAliases() map[string]string {
+	cfg, err := config.NewLoader(g.configPath).Load()
+	if err != nil {
+		return nil
+	}
+	return cfg.Aliases
+}
+
+// expandAliases rewrites args while args[0] names a user-defined alias.
+// The alias value is split into words as a shell would (see
+// splitAliasWords) and the remaining arguments are appended, so
+// "work -compact" with work: "stats -group-by date" becomes
+// "stats -group-by date -compact". Aliases may refer to other aliases;
+// a cycle is reported as errAliasLoop.
+func expandAliases(aliases map[string]string, args []string) ([]string, error) {
+	seen := make(map[string]bool)
+	for len(args) > 0 && !builtinCommands[args[0]] {
+		name := args[0]
+		value, ok := aliases[name]
+		if !ok {
+			break
+		}
+		if seen[name] || len(seen) >= maxAliasDepth {
+			return nil, fmt.Errorf("%w: %s", errAliasLoop, name)
+		}
+		seen[name] = true
+
+		fields, err := splitAliasWords(value)
+		if err != nil {
+			return nil, fmt.Errorf("alias %s: %w", name, err)
+		}
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("alias %s is empty", name)
+		}
+		args = append(fields, args[1:]...)
+	}
+	return args, nil
+}
+
+// splitAliasWords splits an alias value into words on unquoted
+// whitespace. Single quotes keep their content as is; within double
+// quotes and outside quotes, a backslash escapes the next character. So
+// `query "sum(tokens) by model"` yields two words.
+func splitAliasWords(value string) ([]string, error) {
+	var (
+		words   []string
+		word    strings.Builder
+		inWord  bool
+		quote   rune
+		escaped bool
+	)
+	for _, r := range value {
+		switch {
+		case escaped:
+			word.WriteRune(r)
+			escaped = false
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\\':
+			escaped, inWord = true, true
+		case quote == '"':
+			if r == '"' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote, inWord = r, true
+		case unicode.IsSpace(r):
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 || escaped {
+		return nil, errAliasQuote
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestExpandAliases(t *testing.T) {
+	aliases := map[string]string{
+		"work":  "stats -group-by date -cost",
+		"daily": "work -compact",
+		"loopa": "loopb",
+		"loopb": "loopa",
+		"stats": "list",
+		"empty": "  ",
+		"q":     `query "sum(tokens) by model" -where 'model LIKE "%opus%"' a\ b`,
+		"open":  `query "sum(tokens)`,
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    []string
+		wantErr bool
+	}{
+		{"not an alias", []string{"unknown"}, []string{"unknown"}, false},
+		{"builtin wins", []string{"stats", "-top", "5"}, []string{"stats", "-top", "5"}, false},
+		{"appends extra args", []string{"work", "-rate"}, []string{"stats", "-group-by", "date", "-cost", "-rate"}, false},
+		{"nested alias", []string{"daily"}, []string{"stats", "-group-by", "date", "-cost", "-compact"}, false},
+		{"loop", []string{"loopa"}, nil, true},
+		{"empty alias", []string{"empty"}, nil, true},
+		{"quoted arguments", []string{"q"}, []string{"query", "sum(tokens) by model", "-where", `model LIKE "%opus%"`, "a b"}, false},
+		{"unterminated quote", []string{"open"}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := expandAliases(aliases, tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expandAliases() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expandAliases() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := expandAliases(aliases, []string{"loopb"}); !errors.Is(err, errAliasLoop) {
+		t.Errorf("expandAliases() error = %v, want errAliasLoop", err)
+	}
+}
+
+func TestSplitAliasWords(t *testing.T) {
+	tests := []struct {
+		value string
+		want  []string
+	}{
+		{"", nil},
+		{"  stats   -top 5 ", []string{"stats", "-top", "5"}},
+		{`-name ""`, []string{"-name", ""}},
+		{`a"b c"d`, []string{"ab cd"}},
+		{`"say \"hi\""`, []string{`say "hi"`}},
+		{`'no \escape'`, []string{`no \escape`}},
+	}
+	for _, tt := range tests {
+		got, err := splitAliasWords(tt.value)
+		if err != nil {
+			t.Errorf("splitAliasWords(%q) error: %v", tt.value, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitAliasWords(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+
+	for _, value := range []string{`"open`, `'open`, `trailing\`} {
+		if _, err := splitAliasWords(value); !errors.Is(err, errAliasQuote) {
+			t.Errorf("splitAliasWords(%q) error = %v, want errAliasQuote", value, err)
+		}
+	}
+}
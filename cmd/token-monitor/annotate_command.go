@@ -0,0 +1,327 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/0xmhha/token-monitor/pkg/discovery"
+	"github.com/0xmhha/token-monitor/pkg/display"
+	"github.com/0xmhha/token-monitor/pkg/parser"
+	"github.com/0xmhha/token-monitor/pkg/rollup"
+)
+
+// annotateCommand attributes session usage to git commits.
+type annotateCommand struct {
+	repo       string
+	since      string
+	format     string
+	globalOpts globalOptions
+}
+
+// gitCommit is a commit in the annotated range.
+type gitCommit struct {
+	SHA     string
+	Subject string
+	Time    time.Time
+}
+
+// commitUsage is the usage attributed to one commit.
+type commitUsage struct {
+	SHA      string        `json:"sha"`
+	Subject  string        `json:"subject"`
+	Time     time.Time     `json:"time"`
+	Sessions int           `json:"sessions"`
+	Totals   rollup.Totals `json:"totals"`
+}
+
+// annotation is the result of annotate.
+type annotation struct {
+	Repo  string    `json:"repo"`
+	Since string    `json:"since"`
+	Start time.Time `json:"start"`
+
+	Commits []commitUsage `json:"commits"`
+
+	// Uncommitted is usage after the last commit, not yet part of the
+	// change's history.
+	Uncommitted rollup.Totals `json:"uncommitted"`
+
+	// Total and Sessions cover the commits only.
+	Total    rollup.Totals `json:"total"`
+	Sessions int           `json:"sessions"`
+}
+
+// runAnnotateCommand runs the annotate command.
+func runAnnotateCommand(globalOpts globalOptions, args []string) error {
+	fs := flag.NewFlagSet("annotate", flag.ExitOnError)
+	repo := fs.String("repo", ".", "git repository")
+	since := fs.String("since", "HEAD~1", "base ref; commits after its merge base with HEAD are annotated")
+	format := fs.String("format", "text", "output format (text, markdown, trailer, json)")
+
+	if err := globalOpts.applyFlagDefaults(fs); err != nil {
+		return err
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if globalOpts.jsonOutput {
+		*format = "json"
+	}
+	switch *format {
+	case "text", "markdown", "trailer", "json":
+	default:
+		return fmt.Errorf("invalid -format %q (want text, markdown, trailer, or json)", *format)
+	}
+
+	cmd := &annotateCommand{repo: *repo, since: *since, format: *format, globalOpts: globalOpts}
+	return cmd.Execute()
+}
+
+// Execute correlates session activity with the commits and prints the
+// summary.
+func (c *annotateCommand) Execute() error {
+	root, err := git(c.repo, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return err
+	}
+	base, err := git(root, "merge-base", c.since, "HEAD")
+	if err != nil {
+		return err
+	}
+	start, err := commitTime(root, base)
+	if err != nil {
+		return err
+	}
+	commits, err := gitCommits(root, base)
+	if err != nil {
+		return err
+	}
+
+	rt := c.globalOpts.newRuntime("")
+	defer func() {
+		_ = rt.Close() //nolint:errcheck // best effort cleanup
+	}()
+
+	if _, err := rt.Config(); err != nil {
+		return err
+	}
+	disc, err := rt.Discoverer()
+	if err != nil {
+		return err
+	}
+	discovered, err := disc.Discover()
+	if err != nil {
+		return fmt.Errorf("failed to discover sessions: %w", err)
+	}
+	files := projectSessionFiles(discovered, root)
+	if len(files) == 0 {
+		return fmt.Errorf("%w for project %s", discovery.ErrNoSessionsFound, root)
+	}
+
+	p, err := rt.Parser()
+	if err != nil {
+		return err
+	}
+	var entries []parser.UsageEntry
+	for _, f := range files {
+		parsed, _, err := p.ParseFile(f.FilePath, 0)
+		if err != nil {
+			c.globalOpts.infof("Skipping %s: %v\n", f.FilePath, err)
+			continue
+		}
+		entries = append(entries, parsed...)
+	}
+
+	result := attributeCommits(commits, start, entries)
+	result.Repo = root
+	result.Since = c.since
+
+	switch c.format {
+	case "json":
+		if result.Commits == nil {
+			result.Commits = []commitUsage{}
+		}
+		return printJSON(result)
+	case "markdown":
+		fmt.Print(annotationMarkdown(result))
+		return nil
+	case "trailer":
+		fmt.Print(annotationTrailers(result))
+		return nil
+	default:
+		return c.displayText(result)
+	}
+}
+
+// displayText prints the commits as a table with a total row.
+func (c *annotateCommand) displayText(result annotation) error {
+	out := c.globalOpts.output()
+	out.Printf("Repository: %s (since %s, %d commits)\n\n", result.Repo, result.Since, len(result.Commits))
+
+	header := []string{"COMMIT", "SUBJECT", "SESSIONS", "REQUESTS", "TOKENS", "COST"}
+	rows := make([][]string, 0, len(result.Commits)+2)
+	for _, commit := range result.Commits {
+		rows = append(rows, annotationRow(commit.SHA[:min(7, len(commit.SHA))], commit.Subject, commit.Sessions, commit.Totals))
+	}
+	rows = append(rows, annotationRow("TOTAL", "", result.Sessions, result.Total))
+	if err := display.WriteTable(os.Stdout, header, rows, false); err != nil {
+		return err
+	}
+
+	if result.Uncommitted.Entries > 0 {
+		out.Printf("\nUncommitted work since the last commit: %s tokens, %s\n",
+			display.FormatNumber(result.Uncommitted.TotalTokens()), display.FormatCost(result.Uncommitted.CostUSD))
+	}
+	return nil
+}
+
+// annotationRow formats one table row.
+func annotationRow(commit, subject string, sessions int, totals rollup.Totals) []string {
+	return []string{
+		commit,
+		truncateRunes(subject, 50),
+		display.FormatNumber(sessions),
+		display.FormatNumber(totals.Entries),
+		display.FormatNumber(totals.TotalTokens()),
+		display.FormatCost(totals.CostUSD),
+	}
+}
+
+// annotationMarkdown renders the result as a PR comment body.
+func annotationMarkdown(result annotation) string {
+	var b strings.Builder
+	b.WriteString("### AI usage for this change\n\n")
+	b.WriteString("| Commit | Subject | Requests | Tokens | Cost |\n")
+	b.WriteString("|--------|---------|---------:|-------:|-----:|\n")
+	for _, commit := range result.Commits {
+		fmt.Fprintf(&b, "| `%s` | %s | %s | %s | %s |\n",
+			commit.SHA[:min(7, len(commit.SHA))],
+			strings.ReplaceAll(commit.Subject, "|", `\|`),
+			display.FormatNumber(commit.Totals.Entries),
+			display.FormatNumber(commit.Totals.TotalTokens()),
+			display.FormatCost(commit.Totals.CostUSD))
+	}
+	fmt.Fprintf(&b, "| **Total** | %d session(s) | **%s** | **%s** | **%s** |\n\n",
+		result.Sessions,
+		display.FormatNumber(result.Total.Entries),
+		display.FormatNumber(result.Total.TotalTokens()),
+		display.FormatCost(result.Total.CostUSD))
+	b.WriteString("_Estimated by token-monitor from Claude Code sessions in this repository, " +
+		"attributing each request to the next commit._\n")
+	return b.String()
+}
+
+// annotationTrailers renders the totals as git trailer lines.
+func annotationTrailers(result annotation) string {
+	return fmt.Sprintf("AI-Sessions: %d\nAI-Requests: %d\nAI-Tokens: %d\nAI-Cost-USD: %.2f\n",
+		result.Sessions, result.Total.Entries, result.Total.TotalTokens(), result.Total.CostUSD)
+}
+
+// attributeCommits assigns each entry after start to the first commit made
+// at or after it. commits must be oldest first. Entries after the last
+// commit count as uncommitted.
+func attributeCommits(commits []gitCommit, start time.Time, entries []parser.UsageEntry) annotation {
+	result := annotation{Start: start, Commits: make([]commitUsage, len(commits))}
+	for i, commit := range commits {
+		result.Commits[i] = commitUsage{SHA: commit.SHA, Subject: commit.Subject, Time: commit.Time}
+	}
+
+	sessions := make([]map[string]bool, len(commits))
+	all := make(map[string]bool)
+	for _, entry := range entries {
+		if !entry.Timestamp.After(start) {
+			continue
+		}
+		i := sort.Search(len(commits), func(i int) bool {
+			return !commits[i].Time.Before(entry.Timestamp)
+		})
+		if i == len(commits) {
+			result.Uncommitted.AddEntry(entry)
+			continue
+		}
+		result.Commits[i].Totals.AddEntry(entry)
+		result.Total.AddEntry(entry)
+		if sessions[i] == nil {
+			sessions[i] = make(map[string]bool)
+		}
+		sessions[i][entry.SessionID] = true
+		all[entry.SessionID] = true
+	}
+
+	for i := range result.Commits {
+		result.Commits[i].Sessions = len(sessions[i])
+	}
+	result.Sessions = len(all)
+	return result
+}
+
+// gitCommits returns the commits after base up to HEAD, oldest first.
+// Commit times are committer times, which is when the work was committed
+// locally.
+func gitCommits(root, base string) ([]gitCommit, error) {
+	out, err := git(root, "log", "--reverse", "--format=%H%x1f%ct%x1f%s", base+"..HEAD")
+	if err != nil || out == "" {
+		return nil, err
+	}
+
+	var commits []gitCommit
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.SplitN(line, "\x1f", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		sec, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		commits = append(commits, gitCommit{SHA: fields[0], Time: time.Unix(sec, 0), Subject: fields[2]})
+	}
+	// Rebases can leave committer times out of order.
+	sort.SliceStable(commits, func(i, j int) bool { return commits[i].Time.Before(commits[j].Time) })
+	return commits, nil
+}
+
+// commitTime returns the committer time of ref.
+func commitTime(root, ref string) (time.Time, error) {
+	out, err := git(root, "log", "-1", "--format=%ct", ref)
+	if err != nil {
+		return time.Time{}, err
+	}
+	sec, err := strconv.ParseInt(out, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: unexpected commit time %q", errGit, out)
+	}
+	return time.Unix(sec, 0), nil
+}
+
+// git runs git in dir and returns its trimmed output.
+func git(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...) //nolint:gosec // fixed binary, user-supplied refs
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return "", fmt.Errorf("%w: git %s: %s", errGit, strings.Join(args, " "), msg)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// truncateRunes shortens s to at most n runes.
+func truncateRunes(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-1]) + "…"
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0xmhha/token-monitor/pkg/parser"
+)
+
+func TestAttributeCommits(t *testing.T) {
+	base := time.Date(2025, 11, 30, 9, 0, 0, 0, time.UTC)
+	commits := []gitCommit{
+		{SHA: "aaaaaaa1", Subject: "first", Time: base.Add(time.Hour)},
+		{SHA: "bbbbbbb2", Subject: "second", Time: base.Add(2 * time.Hour)},
+	}
+	entry := func(session string, offset time.Duration, tokens int) parser.UsageEntry {
+		return parser.UsageEntry{
+			Timestamp: base.Add(offset),
+			SessionID: session,
+			Message:   parser.Message{Model: "claude-sonnet-4", Usage: parser.Usage{InputTokens: tokens}},
+		}
+	}
+	entries := []parser.UsageEntry{
+		entry("old", -time.Minute, 1000),  // before the base commit
+		entry("s1", 10*time.Minute, 100),  // first
+		entry("s1", time.Hour, 200),       // first: at the commit time
+		entry("s2", 90*time.Minute, 400),  // second
+		entry("s2", 150*time.Minute, 800), // uncommitted
+	}
+
+	got := attributeCommits(commits, base, entries)
+
+	if got.Commits[0].Totals.InputTokens != 300 || got.Commits[0].Sessions != 1 {
+		t.Errorf("first = %+v, want 300 tokens in 1 session", got.Commits[0])
+	}
+	if got.Commits[1].Totals.InputTokens != 400 || got.Commits[1].Sessions != 1 {
+		t.Errorf("second = %+v, want 400 tokens in 1 session", got.Commits[1])
+	}
+	if got.Total.InputTokens != 700 || got.Total.Entries != 3 || got.Sessions != 2 {
+		t.Errorf("total = %+v (%d sessions), want 700 tokens, 3 requests, 2 sessions", got.Total, got.Sessions)
+	}
+	if got.Uncommitted.InputTokens != 800 {
+		t.Errorf("uncommitted = %+v, want 800 tokens", got.Uncommitted)
+	}
+
+	trailers := annotationTrailers(got)
+	if !strings.Contains(trailers, "AI-Sessions: 2\n") || !strings.Contains(trailers, "AI-Tokens: 700\n") {
+		t.Errorf("trailers = %q", trailers)
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/0xmhha/token-monitor/pkg/apiclient"
+)
+
+// apiCommand describes the HTTP API of serve -http.
+type apiCommand struct {
+	globalOpts globalOptions
+}
+
+// runAPICommand runs the api command.
+func runAPICommand(globalOpts globalOptions, args []string) error {
+	cmd := &apiCommand{globalOpts: globalOpts}
+	return cmd.Execute(args)
+}
+
+// Execute dispatches an api subcommand.
+func (c *apiCommand) Execute(args []string) error {
+	if len(args) == 0 {
+		return c.showHelp()
+	}
+
+	switch args[0] {
+	case "spec":
+		return c.runSpec(args[1:])
+	case "help":
+		return c.showHelp()
+	default:
+		return fmt.Errorf("unknown api subcommand: %s", args[0])
+	}
+}
+
+// runSpec prints the OpenAPI specification, or writes it to -output.
+func (c *apiCommand) runSpec(args []string) error {
+	fs := flag.NewFlagSet("api spec", flag.ExitOnError)
+	output := fs.String("output", "", "output file path (default: stdout)")
+	if err := c.globalOpts.applyFlagDefaults(fs); err != nil {
+		return err
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	spec := apiclient.Spec()
+	if *output == "" {
+		_, err := os.Stdout.Write(spec)
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(*output), 0750); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	if err := os.WriteFile(*output, spec, 0600); err != nil {
+		return fmt.Errorf("failed to write spec: %w", err)
+	}
+	c.globalOpts.infof("Wrote OpenAPI specification to %s\n", *output)
+	return nil
+}
+
+// showHelp displays api command help.
+func (c *apiCommand) showHelp() error {
+	help := `API - HTTP API of serve -http
+
+Usage:
+  token-monitor api spec [-output FILE]
+
+Subcommands:
+  spec          Print the OpenAPI 3 specification of the endpoints of
+                serve -http (also served at GET /openapi.json)
+
+Go programs can use the typed client in pkg/apiclient instead of
+calling the endpoints by hand.
+`
+	fmt.Print(help)
+	return nil
+}
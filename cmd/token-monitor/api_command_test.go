@@ -0,0 +1,29 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/0xmhha/token-monitor/pkg/apiclient"
+)
+
+func TestAPISpecOutput(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "api", "openapi.json")
+	cmd := &apiCommand{globalOpts: globalOptions{quiet: true}}
+	if err := cmd.Execute([]string{"spec", "-output", path}); err != nil {
+		t.Fatalf("api spec error: %v", err)
+	}
+	data, err := os.ReadFile(path) // #nosec G304 -- test temp file
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, apiclient.Spec()) {
+		t.Error("written spec differs from the embedded one")
+	}
+
+	if err := cmd.Execute([]string{"bogus"}); err == nil {
+		t.Error("api bogus succeeded, want error")
+	}
+}
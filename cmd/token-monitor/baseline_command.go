@@ -0,0 +1,354 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"time"
+
+	"github.com/0xmhha/token-monitor/cmd/internal/runtime"
+	"github.com/0xmhha/token-monitor/pkg/aggregator"
+	"github.com/0xmhha/token-monitor/pkg/baseline"
+	"github.com/0xmhha/token-monitor/pkg/display"
+	"github.com/0xmhha/token-monitor/pkg/i18n"
+)
+
+// defaultBaselineDays is the period captured by "baseline save".
+const defaultBaselineDays = 7
+
+// baselineCommand handles saved stats baselines.
+type baselineCommand struct {
+	globalOpts globalOptions
+}
+
+// runBaselineCommand runs the baseline command.
+func runBaselineCommand(globalOpts globalOptions, args []string) error {
+	cmd := &baselineCommand{globalOpts: globalOpts}
+	return cmd.Execute(args)
+}
+
+// Execute dispatches a baseline subcommand.
+func (c *baselineCommand) Execute(args []string) error {
+	if len(args) == 0 {
+		return c.showHelp()
+	}
+
+	switch args[0] {
+	case "save":
+		return c.runSave(args[1:])
+	case "list":
+		return c.runList(args[1:])
+	case "delete":
+		return c.runDelete(args[1:])
+	case "help":
+		return c.showHelp()
+	default:
+		return fmt.Errorf("unknown baseline subcommand: %s", args[0])
+	}
+}
+
+// showHelp displays help for the baseline command.
+func (c *baselineCommand) showHelp() error {
+	help := `Baseline - Saved stats snapshots to compare against
+
+Usage:
+  token-monitor baseline <subcommand> [flags]
+
+Subcommands:
+  save <name>     Capture totals for the last N days under name
+  list            List saved baselines
+  delete <name>   Remove a baseline
+
+Save Flags:
+  -days         Period to capture, ending now (default: 7; 0 for all data)
+  -model        Filter by model (comma-separated globs or /regex/)
+  -force        Overwrite an existing baseline
+
+Compare a later period with "token-monitor stats -against-baseline <name>".
+The current period has the same length and model filter as the baseline,
+and per-request and per-day rates are shown next to the totals, e.g. to
+measure the effect of enabling prompt caching or switching models.
+`
+	fmt.Print(help)
+	return nil
+}
+
+// runSave captures current stats as a named baseline.
+func (c *baselineCommand) runSave(args []string) error {
+	fs := flag.NewFlagSet("baseline save", flag.ExitOnError)
+	days := fs.Int("days", defaultBaselineDays, "period to capture, ending now (0 for all data)")
+	model := fs.String("model", "", "filter by model (comma-separated globs or /regex/)")
+	force := fs.Bool("force", false, "overwrite an existing baseline")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: baseline save <name> [-days N] [-model M] [-force]")
+	}
+	if *days < 0 {
+		return fmt.Errorf("invalid -days: %d", *days)
+	}
+
+	name := fs.Arg(0)
+	if err := baseline.ValidateName(name); err != nil {
+		return fmt.Errorf("%w: %q", err, name)
+	}
+
+	rt := c.globalOpts.newRuntime("")
+	defer func() {
+		_ = rt.Close() //nolint:errcheck // best effort cleanup
+	}()
+
+	if err := rt.Persistent(); err != nil {
+		return err
+	}
+	store, err := rt.Baselines()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	stats, err := periodStats(rt, c.globalOpts, *model, *days, now)
+	if err != nil {
+		return err
+	}
+
+	b := baseline.Baseline{
+		Name:      name,
+		CreatedAt: now,
+		Days:      *days,
+		Model:     *model,
+		Snapshot:  baseline.FromStatistics(stats, *days),
+	}
+	if err := store.Save(b, *force); err != nil {
+		return err
+	}
+
+	if c.globalOpts.jsonOutput {
+		return printJSON(b)
+	}
+	c.globalOpts.output().Printf("✓ Saved baseline %s: %s entries, %s tokens, %s over %s\n",
+		name, display.FormatNumber(stats.Count), display.FormatNumber(stats.TotalTokens),
+		display.FormatCost(stats.CostUSD), describePeriod(*days))
+	return nil
+}
+
+// runList prints saved baselines.
+func (c *baselineCommand) runList(args []string) error {
+	fs := flag.NewFlagSet("baseline list", flag.ExitOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	rt := c.globalOpts.newRuntime("")
+	defer func() {
+		_ = rt.Close() //nolint:errcheck // best effort cleanup
+	}()
+
+	store, err := rt.Baselines()
+	if err != nil {
+		return err
+	}
+	list, err := store.List()
+	if err != nil {
+		return err
+	}
+
+	if c.globalOpts.jsonOutput {
+		if list == nil {
+			list = []baseline.Baseline{}
+		}
+		return printJSON(list)
+	}
+
+	if len(list) == 0 {
+		c.globalOpts.infof("No baselines saved\n")
+		return nil
+	}
+
+	header := []string{"NAME", "SAVED", "PERIOD", "MODEL", "ENTRIES", "TOKENS", "COST"}
+	rows := make([][]string, 0, len(list))
+	for _, b := range list {
+		model := b.Model
+		if model == "" {
+			model = "*"
+		}
+		rows = append(rows, []string{
+			b.Name,
+			b.CreatedAt.Local().Format("2006-01-02 15:04"),
+			describePeriod(b.Days),
+			model,
+			display.FormatNumber(b.Snapshot.Entries),
+			display.FormatNumber(b.Snapshot.TotalTokens),
+			display.FormatCost(b.Snapshot.CostUSD),
+		})
+	}
+	return display.WriteTable(os.Stdout, header, rows, false)
+}
+
+// runDelete removes a baseline.
+func (c *baselineCommand) runDelete(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: baseline delete <name>")
+	}
+
+	rt := c.globalOpts.newRuntime("")
+	defer func() {
+		_ = rt.Close() //nolint:errcheck // best effort cleanup
+	}()
+
+	if err := rt.Persistent(); err != nil {
+		return err
+	}
+	store, err := rt.Baselines()
+	if err != nil {
+		return err
+	}
+	if err := store.Delete(args[0]); err != nil {
+		return err
+	}
+
+	c.globalOpts.infof("✓ Deleted baseline %s\n", args[0])
+	return nil
+}
+
+// periodStats aggregates entries from the last days days (all data when
+// zero) that match model. Files are read from the start with a private
+// position store, so the result does not depend on earlier reads.
+func periodStats(rt *runtime.Runtime, globalOpts globalOptions, model string, days int, now time.Time) (aggregator.Statistics, error) {
+	r, err := rt.NewReader()
+	if err != nil {
+		return aggregator.Statistics{}, err
+	}
+	defer func() {
+		_ = r.Close() //nolint:errcheck // best effort cleanup
+	}()
+
+	cmd := &statsCommand{model: model, globalOpts: globalOpts}
+	if days > 0 {
+		cmd.since = now.AddDate(0, 0, -days)
+	}
+
+	agg, err := cmd.collectStats(rt, r)
+	if err != nil || agg == nil {
+		return aggregator.Statistics{}, err
+	}
+	return agg.Stats(), nil
+}
+
+// describePeriod describes a baseline period length.
+func describePeriod(days int) string {
+	switch days {
+	case 0:
+		return "all data"
+	case 1:
+		return "1 day"
+	default:
+		return fmt.Sprintf("%d days", days)
+	}
+}
+
+// printJSON writes v to stdout as indented JSON.
+func printJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// baselineComparison is the JSON document for stats -against-baseline.
+type baselineComparison struct {
+	Baseline baseline.Baseline `json:"baseline"`
+	Current  baseline.Snapshot `json:"current"`
+	Changes  []baseline.Change `json:"changes"`
+}
+
+// runAgainstBaseline compares the current period with a saved baseline.
+// The period length and model filter come from the baseline unless -model
+// was given.
+func (c *statsCommand) runAgainstBaseline(rt *runtime.Runtime) error {
+	store, err := rt.Baselines()
+	if err != nil {
+		return err
+	}
+	b, err := store.Get(c.againstBaseline)
+	if err != nil {
+		return err
+	}
+
+	model := c.model
+	if model == "" {
+		model = b.Model
+	}
+
+	stats, err := periodStats(rt, c.globalOpts, model, b.Days, time.Now())
+	if err != nil {
+		return err
+	}
+	current := baseline.FromStatistics(stats, b.Days)
+	changes := baseline.Compare(b.Snapshot, current)
+
+	if c.format == "json" {
+		return printJSON(baselineComparison{Baseline: b, Current: current, Changes: changes})
+	}
+
+	out := c.globalOpts.output()
+	out.Printf("%s\n", i18n.Tf("baseline.title", b.Name, b.CreatedAt.Local().Format("2006-01-02 15:04"), describePeriod(b.Days)))
+	if !c.compact {
+		out.Println()
+	}
+
+	header := []string{
+		i18n.T("stats.metric"),
+		i18n.T("col.baseline"),
+		i18n.T("col.current"),
+		i18n.T("col.change"),
+		i18n.T("col.change_pct"),
+	}
+	rows := make([][]string, 0, len(changes))
+	for _, change := range changes {
+		pct := "-"
+		if change.Percent != nil {
+			pct = fmt.Sprintf("%+.1f%%", *change.Percent)
+		}
+		rows = append(rows, []string{
+			i18n.T("baseline." + change.Metric),
+			formatChangeValue(change.Kind, change.Baseline),
+			formatChangeValue(change.Kind, change.Current),
+			formatChangeDelta(change.Kind, change.Delta),
+			pct,
+		})
+	}
+	return display.WriteTable(os.Stdout, header, rows, c.compact)
+}
+
+// formatChangeValue formats a compared value of the given kind.
+func formatChangeValue(kind baseline.Kind, v float64) string {
+	switch kind {
+	case baseline.KindCount:
+		return fmtNum(int(math.Round(v)))
+	case baseline.KindCost:
+		return fmtUSD(v)
+	case baseline.KindPercent:
+		return fmt.Sprintf("%.1f%%", v)
+	default:
+		return fmt.Sprintf("%.1f", v)
+	}
+}
+
+// formatChangeDelta formats a signed difference of the given kind.
+// Percentages change by points, not percent.
+func formatChangeDelta(kind baseline.Kind, d float64) string {
+	switch kind {
+	case baseline.KindCount:
+		return fmtDiff(int(math.Round(d)))
+	case baseline.KindCost:
+		return fmtUSDDiff(d)
+	case baseline.KindPercent:
+		return i18n.Tf("baseline.points", d)
+	default:
+		return fmt.Sprintf("%+.1f", d)
+	}
+}
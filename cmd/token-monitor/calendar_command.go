@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/0xmhha/token-monitor/pkg/calendar"
+	"github.com/0xmhha/token-monitor/pkg/display"
+	"github.com/0xmhha/token-monitor/pkg/i18n"
+	"github.com/0xmhha/token-monitor/pkg/rollup"
+)
+
+// calendarCommand prints a month of daily token usage as a heat map.
+type calendarCommand struct {
+	month      time.Time
+	format     string
+	globalOpts globalOptions
+}
+
+// runCalendarCommand parses flags and runs the calendar command.
+func runCalendarCommand(globalOpts globalOptions, args []string) error {
+	fs := flag.NewFlagSet("calendar", flag.ExitOnError)
+	month := fs.String("month", "", "month to show (YYYY-MM, default: current month)")
+	format := fs.String("format", "table", "output format (table, json)")
+
+	if err := globalOpts.applyFlagDefaults(fs); err != nil {
+		return err
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	start, err := calendarMonth(*month, time.Now())
+	if err != nil {
+		return err
+	}
+
+	outputFormat := *format
+	if globalOpts.jsonOutput {
+		outputFormat = "json"
+	}
+
+	cmd := &calendarCommand{
+		month:      start,
+		format:     outputFormat,
+		globalOpts: globalOpts,
+	}
+	return cmd.Execute()
+}
+
+// calendarMonth returns the month to show: month (YYYY-MM), or the one
+// containing now when empty. Months are in UTC, whose days the rollup
+// dates are, so that each cell and the month's edges hold whole days.
+func calendarMonth(month string, now time.Time) (time.Time, error) {
+	if month == "" {
+		return now.UTC(), nil
+	}
+	return calendar.ParseMonth(month, time.UTC)
+}
+
+// Execute catches the rollups up with new entries and prints the month.
+func (c *calendarCommand) Execute() error {
+	rt := c.globalOpts.newRuntime("")
+	defer func() {
+		_ = rt.Close() //nolint:errcheck // best effort cleanup
+	}()
+
+	if _, err := rt.Config(); err != nil {
+		return err
+	}
+	if _, err := rt.Sessions(); err != nil {
+		return fmt.Errorf("rollup store unavailable (is watch running?): %w", err)
+	}
+	store, err := rt.Rollups()
+	if err != nil {
+		return err
+	}
+	if _, err := ingestRollups(context.Background(), rt, store); err != nil {
+		return err
+	}
+
+	first := time.Date(c.month.Year(), c.month.Month(), 1, 0, 0, 0, 0, c.month.Location())
+	last := first.AddDate(0, 1, -1)
+	rows, err := store.Rows(first.Format(rollup.DateLayout), last.Format(rollup.DateLayout))
+	if err != nil {
+		return fmt.Errorf("failed to read rollups: %w", err)
+	}
+
+	daily := make(map[string]rollup.Totals)
+	for _, row := range rollup.Summarize(rows, []rollup.Dimension{rollup.DimDate}) {
+		daily[row.Date] = row.Totals
+	}
+	month := calendar.Build(first, daily)
+
+	if c.format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(month)
+	}
+
+	out := c.globalOpts.output()
+	out.Println(i18n.Tf("calendar.summary", month.Name,
+		display.FormatNumber(month.Total.TotalTokens()),
+		display.FormatCost(month.Total.CostUSD),
+		month.ActiveDays))
+	out.Println()
+
+	// A grid means nothing to a screen reader; list the active days instead.
+	if c.globalOpts.accessible {
+		for _, day := range month.Days {
+			if day.Totals.Entries == 0 {
+				continue
+			}
+			out.Printf("%s: %s tokens, %s\n", day.Name,
+				display.FormatNumber(day.Totals.TotalTokens()), display.FormatCost(day.Totals.CostUSD))
+		}
+		return nil
+	}
+
+	out.Printf("%s", renderCalendar(month))
+	if busiest := month.Busiest(); busiest != nil {
+		out.Println()
+		out.Println(i18n.Tf("calendar.busiest", busiest.Name,
+			display.FormatNumber(busiest.Totals.TotalTokens()),
+			display.FormatCost(busiest.Totals.CostUSD)))
+	}
+	return nil
+}
+
+// renderCalendar draws the month grid: a header with the first day of
+// each week column, one row per weekday, and a shading legend.
+func renderCalendar(month calendar.Month) string {
+	grid := month.Grid()
+	labels := strings.Fields(i18n.T("calendar.weekdays"))
+	labelWidth := 0
+	for _, label := range labels {
+		labelWidth = max(labelWidth, display.TextWidth(label))
+	}
+
+	var b strings.Builder
+	b.WriteString(strings.Repeat(" ", labelWidth+1))
+	for week := range grid[0] {
+		for _, row := range grid {
+			if day := row[week]; day != nil {
+				fmt.Fprintf(&b, "%2d ", day.Date.Day())
+				break
+			}
+		}
+	}
+	b.WriteString("\n")
+
+	for i, row := range grid {
+		label := ""
+		if i < len(labels) {
+			label = labels[i]
+		}
+		b.WriteString(display.PadRight(label, labelWidth) + " ")
+		for _, day := range row {
+			if day == nil {
+				b.WriteString("   ")
+				continue
+			}
+			glyph := calendar.Glyphs[day.Level]
+			b.WriteString(glyph + glyph + " ")
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n" + strings.Repeat(" ", labelWidth+1) + i18n.T("calendar.less") + " ")
+	for _, glyph := range calendar.Glyphs {
+		b.WriteString(glyph + glyph + " ")
+	}
+	b.WriteString(i18n.T("calendar.more") + "\n")
+	return b.String()
+}
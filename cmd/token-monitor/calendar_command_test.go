@@ -0,0 +1,65 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0xmhha/token-monitor/pkg/calendar"
+	"github.com/0xmhha/token-monitor/pkg/display"
+	"github.com/0xmhha/token-monitor/pkg/rollup"
+)
+
+func TestRenderCalendar(t *testing.T) {
+	month := calendar.Build(time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC), map[string]rollup.Totals{
+		"2025-11-01": {Entries: 1, InputTokens: 100},
+		"2025-11-03": {Entries: 2, InputTokens: 400},
+	})
+
+	lines := strings.Split(display.ASCII(renderCalendar(month)), "\n")
+	want := []string{
+		"     1  3 10 17 24 ",
+		"Mon    ## .. .. .. ",
+		"Tue    .. .. .. .. ",
+		"Wed    .. .. .. .. ",
+		"Thu    .. .. .. .. ",
+		"Fri    .. .. .. .. ",
+		"Sat :: .. .. .. .. ",
+		"Sun .. .. .. .. .. ",
+		"",
+		"    Less .. :: ++ ** ## More",
+	}
+	for i, line := range want {
+		if i >= len(lines) || lines[i] != line {
+			t.Fatalf("renderCalendar() line %d = %q, want %q\n%s", i, lines[i], line, strings.Join(lines, "\n"))
+		}
+	}
+}
+
+func TestCalendarMonth(t *testing.T) {
+	kst := time.FixedZone("KST", 9*3600)
+	// 08:00 on December 1 in Seoul is still November in UTC, the zone of
+	// the rollup dates.
+	now := time.Date(2025, 12, 1, 8, 0, 0, 0, kst)
+
+	tests := []struct {
+		month string
+		want  string
+	}{
+		{"", "2025-11"},
+		{"2025-10", "2025-10"},
+	}
+	for _, tt := range tests {
+		got, err := calendarMonth(tt.month, now)
+		if err != nil {
+			t.Fatalf("calendarMonth(%q) error = %v", tt.month, err)
+		}
+		if got.Location() != time.UTC || got.Format(calendar.MonthLayout) != tt.want {
+			t.Errorf("calendarMonth(%q) = %s, want %s in UTC", tt.month, got, tt.want)
+		}
+	}
+
+	if _, err := calendarMonth("Nov", now); err == nil {
+		t.Error("calendarMonth(Nov) = nil error")
+	}
+}
@@ -9,16 +9,21 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/charmbracelet/lipgloss"
 	"golang.org/x/term"
 
+	"github.com/0xmhha/token-monitor/cmd/internal/runtime"
 	"github.com/0xmhha/token-monitor/pkg/aggregator"
+	"github.com/0xmhha/token-monitor/pkg/analysis"
 	"github.com/0xmhha/token-monitor/pkg/config"
 	"github.com/0xmhha/token-monitor/pkg/discovery"
 	"github.com/0xmhha/token-monitor/pkg/display"
 	"github.com/0xmhha/token-monitor/pkg/logger"
 	"github.com/0xmhha/token-monitor/pkg/monitor"
+	"github.com/0xmhha/token-monitor/pkg/overhead"
 	"github.com/0xmhha/token-monitor/pkg/parser"
 	"github.com/0xmhha/token-monitor/pkg/reader"
+	"github.com/0xmhha/token-monitor/pkg/rollup"
 	"github.com/0xmhha/token-monitor/pkg/session"
 	"github.com/0xmhha/token-monitor/pkg/watcher"
 )
@@ -31,101 +36,103 @@ type statsCommand struct {
 	topN       int
 	format     string
 	compact    bool
+	showCost   bool
+	showRate   bool
+	units      display.Units
+	detailed   bool
+	since      time.Time     // entries before since are skipped when set
+	tail       int           // read only the last tail entries per file when > 0
+	state      session.State // only sessions in this state when set
+	verify     bool          // compare the results with ground truth
+	footprint  analysis.FootprintRates
 	configPath string
 	globalOpts globalOptions
+
+	// againstBaseline names a saved baseline to compare with instead of
+	// showing plain statistics.
+	againstBaseline string
+
+	// read lists the session files collectStats read, for -verify.
+	read []discovery.SessionFile
 }
 
 // Execute runs the stats command.
 func (c *statsCommand) Execute() error {
-	// Load configuration and initialize components.
-	cfg, log, sessionMgr, r, err := c.initialize()
+	rt := c.globalOpts.newRuntime("")
+	defer func() {
+		_ = rt.Close() //nolint:errcheck // best effort cleanup
+	}()
+
+	if c.againstBaseline != "" {
+		return c.runAgainstBaseline(rt)
+	}
+
+	// The reader falls back to in-memory positions if BoltDB is locked
+	// by another process.
+	r, err := rt.Reader()
 	if err != nil {
 		return err
 	}
-	defer c.cleanup(sessionMgr, r, log)
+
+	// Resolve session names to UUIDs.
+	sessionMgr, _ := rt.Sessions() //nolint:errcheck // names are optional
+	c.sessionID = resolveSessionIdentifier(sessionMgr, c.sessionID)
 
 	// Discover and collect data.
-	agg, err := c.collectStats(cfg, log, r)
+	agg, err := c.collectStats(rt, r)
 	if err != nil {
 		return err
 	}
 
 	// Display results.
-	return c.displayResults(agg)
+	if err := c.displayResults(agg); err != nil {
+		return err
+	}
+	if c.verify {
+		return c.verifyStats(rt, agg)
+	}
+	return nil
 }
 
-// initialize sets up configuration and components.
-// Falls back to in-memory position store if BoltDB is locked by another process.
-func (c *statsCommand) initialize() (*config.Config, logger.Logger, session.Manager, reader.Reader, error) {
-	cfg, err := config.Load()
-	if err != nil {
-		return nil, nil, nil, nil, fmt.Errorf("failed to load config: %w", err)
+// resolveSessionIdentifier resolves a session name to its UUID.
+// Identifiers that are not registered names are returned unchanged so raw
+// UUIDs keep working, including when the session manager is unavailable.
+func resolveSessionIdentifier(mgr session.Manager, identifier string) string {
+	if identifier == "" || mgr == nil {
+		return identifier
 	}
 
-	// Use global log level if set, otherwise use config.
-	logLevel := cfg.Logging.Level
-	if c.globalOpts.logLevel != "" {
-		logLevel = c.globalOpts.logLevel
+	metadata, err := mgr.GetByName(identifier)
+	if err != nil {
+		return identifier
 	}
 
-	log := logger.New(logger.Config{
-		Level:  logLevel,
-		Format: cfg.Logging.Format,
-		Output: cfg.Logging.Output,
-	})
-
-	var sessionMgr session.Manager
-	var positionStore reader.PositionStore
+	return metadata.UUID
+}
 
-	sessionMgr, err = session.New(session.Config{
-		DBPath: cfg.Storage.DBPath,
-	}, log)
+// collectStats discovers sessions and aggregates statistics.
+func (c *statsCommand) collectStats(rt *runtime.Runtime, r reader.Reader) (aggregator.Aggregator, error) {
+	cfg, err := rt.Config()
 	if err != nil {
-		log.Warn("BoltDB unavailable, using in-memory position store", "error", err)
-		positionStore = reader.NewMemoryPositionStore()
-	} else {
-		positionStore, err = reader.NewBoltPositionStore(sessionMgr.DB())
-		if err != nil {
-			log.Warn("failed to create BoltDB position store, using in-memory", "error", err)
-			positionStore = reader.NewMemoryPositionStore()
-		}
+		return nil, err
 	}
+	c.footprint = cfg.Footprint.Rates()
 
-	r, err := reader.New(reader.Config{
-		PositionStore: positionStore,
-		Parser:        parser.New(),
-	}, log)
+	log, err := rt.Logger()
 	if err != nil {
-		return nil, nil, nil, nil, fmt.Errorf("failed to initialize reader: %w", err)
-	}
-
-	return cfg, log, sessionMgr, r, nil
-}
-
-// cleanup closes resources.
-func (c *statsCommand) cleanup(sessionMgr session.Manager, r reader.Reader, log logger.Logger) {
-	if r != nil {
-		if err := r.Close(); err != nil {
-			log.Error("failed to close reader", "error", err)
-		}
+		return nil, err
 	}
-	if sessionMgr != nil {
-		if err := sessionMgr.Close(); err != nil {
-			log.Error("failed to close session manager", "error", err)
-		}
+	disc, err := rt.Discoverer()
+	if err != nil {
+		return nil, err
 	}
-}
-
-// collectStats discovers sessions and aggregates statistics.
-func (c *statsCommand) collectStats(cfg *config.Config, log logger.Logger, r reader.Reader) (aggregator.Aggregator, error) {
-	disc := discovery.New(cfg.ClaudeConfigDirs, log)
 	sessions, err := disc.Discover()
 	if err != nil {
 		return nil, fmt.Errorf("failed to discover sessions: %w", err)
 	}
 
 	if len(sessions) == 0 {
-		fmt.Println("No session files found")
+		c.globalOpts.infof("No session files found\n")
 		return nil, nil
 	}
 
@@ -134,18 +141,33 @@ func (c *statsCommand) collectStats(cfg *config.Config, log logger.Logger, r rea
 		return nil, err
 	}
 
-	agg := aggregator.New(aggregator.Config{
-		GroupBy:          dimensions,
-		TrackPercentiles: true,
-	})
+	modelFilter, err := aggregator.ParseModelFilter(c.model)
+	if err != nil {
+		return nil, err
+	}
 
+	if c.tail > 0 {
+		c.globalOpts.infof("Approximate statistics: only the last %d entries of each session file are read\n", c.tail)
+	}
+
+	var loaded []loadedSession
 	ctx := context.Background()
+	now := time.Now()
 	for _, sess := range sessions {
 		if c.sessionID != "" && sess.SessionID != c.sessionID {
 			continue
 		}
+		if c.state != "" && fileState(sess, cfg.Session.Thresholds(), now) != c.state {
+			continue
+		}
 
-		entries, readErr := r.Read(ctx, sess.FilePath)
+		var entries []parser.UsageEntry
+		var readErr error
+		if c.tail > 0 {
+			entries, readErr = r.ReadTail(ctx, sess.FilePath, c.tail)
+		} else {
+			entries, readErr = r.Read(ctx, sess.FilePath)
+		}
 		if readErr != nil {
 			log.Warn("failed to read session",
 				"session", sess.SessionID,
@@ -154,15 +176,59 @@ func (c *statsCommand) collectStats(cfg *config.Config, log logger.Logger, r rea
 			continue
 		}
 
-		for _, entry := range entries {
-			if c.model != "" && entry.Message.Model != c.model {
+		loaded = append(loaded, loadedSession{file: sess, entries: entries})
+		c.read = append(c.read, sess)
+	}
+
+	return c.aggregate(loaded, dimensions, modelFilter), nil
+}
+
+// fileState derives a session's lifecycle state from the last write to its
+// file.
+func fileState(sess discovery.SessionFile, th session.Thresholds, now time.Time) session.State {
+	var last time.Time
+	if sess.ModTime > 0 {
+		last = time.Unix(sess.ModTime, 0)
+	}
+	return th.StateAt(last, now)
+}
+
+// loadedSession is a discovered session file together with its parsed entries.
+type loadedSession struct {
+	file    discovery.SessionFile
+	entries []parser.UsageEntry
+}
+
+// aggregate builds statistics from already-read sessions, applying the
+// session, model, and time filters.
+func (c *statsCommand) aggregate(
+	sessions []loadedSession,
+	dimensions []aggregator.Dimension,
+	modelFilter *aggregator.ModelFilter,
+) aggregator.Aggregator {
+	agg := aggregator.New(aggregator.Config{
+		GroupBy:          dimensions,
+		TrackPercentiles: true,
+		Footprint:        c.footprint,
+	})
+
+	for _, sess := range sessions {
+		if c.sessionID != "" && sess.file.SessionID != c.sessionID {
+			continue
+		}
+		for _, entry := range sess.entries {
+			if !modelFilter.Match(entry.Message.Model) {
+				continue
+			}
+			if !c.since.IsZero() && entry.Timestamp.Before(c.since) {
 				continue
 			}
+			entry.Source = sess.file.Source
 			agg.Add(entry)
 		}
 	}
 
-	return agg, nil
+	return agg
 }
 
 // parseDimensions converts dimension strings to types.
@@ -178,6 +244,10 @@ func (c *statsCommand) parseDimensions() ([]aggregator.Dimension, error) {
 			dimensions = append(dimensions, aggregator.DimDate)
 		case "hour":
 			dimensions = append(dimensions, aggregator.DimHour)
+		case "source":
+			dimensions = append(dimensions, aggregator.DimSource)
+		case "subagent":
+			dimensions = append(dimensions, aggregator.DimSubAgent)
 		default:
 			return nil, fmt.Errorf("invalid dimension: %s", dim)
 		}
@@ -206,6 +276,11 @@ func (c *statsCommand) displayResults(agg aggregator.Aggregator) error {
 		ShowPercentiles: true,
 		ShowTimestamps:  true,
 		Compact:         c.compact,
+		ShowCost:        c.showCost,
+		ShowRate:        c.showRate,
+		Units:           c.units,
+		Detailed:        c.detailed,
+		Accessible:      c.globalOpts.accessible,
 	})
 
 	if c.topN > 0 {
@@ -227,82 +302,79 @@ func (c *statsCommand) displayResults(agg aggregator.Aggregator) error {
 	return formatter.FormatStats(os.Stdout, stats)
 }
 
-// listCommand lists all discovered sessions.
-type listCommand struct {
-	configPath string
-	globalOpts globalOptions
-}
-
-// Execute runs the list command.
-func (c *listCommand) Execute() error {
-	// Load configuration.
-	cfg, err := config.Load()
-	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
-	}
+// Low-power mode settings for watch.
+const (
+	// lowPowerRefresh is the minimum refresh interval.
+	lowPowerRefresh = 5 * time.Second
 
-	// Initialize logger.
-	// Use global log level if set, otherwise use config.
-	logLevel := cfg.Logging.Level
-	if c.globalOpts.logLevel != "" {
-		logLevel = c.globalOpts.logLevel
-	}
-
-	log := logger.New(logger.Config{
-		Level:  logLevel,
-		Format: cfg.Logging.Format,
-		Output: cfg.Logging.Output,
-	})
-
-	// Discover session files.
-	disc := discovery.New(cfg.ClaudeConfigDirs, log)
-	sessions, err := disc.Discover()
-	if err != nil {
-		return fmt.Errorf("failed to discover sessions: %w", err)
-	}
-
-	if len(sessions) == 0 {
-		fmt.Println("No session files found")
-		return nil
-	}
-
-	// Display sessions.
-	fmt.Printf("Found %d session(s):\n\n", len(sessions))
-	for _, sess := range sessions {
-		fmt.Printf("  %s\n", sess.SessionID)
-		fmt.Printf("    Path: %s\n", sess.FilePath)
-		if sess.ProjectPath != "" {
-			fmt.Printf("    Project: %s\n", sess.ProjectPath)
-		}
-		fmt.Println()
-	}
+	// lowPowerMaxPoll is the minimum idle re-read backoff limit.
+	lowPowerMaxPoll = 5 * time.Minute
 
-	return nil
-}
+	// lowPowerRollupInterval replaces rollupInterval, so sessions are
+	// re-discovered less often.
+	lowPowerRollupInterval = 10 * time.Minute
+)
 
 // watchCommand provides live token usage monitoring.
 type watchCommand struct {
 	sessionID   string
+	model       string
 	refresh     time.Duration
 	format      string
 	clearScreen bool
+	eco         bool
+	noInput     bool          // no raw mode or key bindings (also monitoring.no_input)
+	compact     bool          // one summary line per update
+	detailed    bool          // also cache token totals
+	silentAfter time.Duration // alert on sessions silent this long (0: off)
+	errorSpike  int           // alert on this many API errors in 5m (0: off)
 	configPath  string
 	globalOpts  globalOptions
 
+	// Burn-down bar settings from configuration
+	blockLimit int  // monitoring.block_token_limit
+	color      bool // display.color_enabled without -no-color
+
+	// Context estimate settings from configuration
+	contextWindow int           // monitoring.context_window
+	idleAfter     time.Duration // session.idle_after
+
 	// Internal state for keyboard handling
 	showHelp   bool
 	lastUpdate *monitor.Update
+
+	// termState is the terminal state before raw mode (nil outside raw
+	// mode); jobSignals receives SIGCONT
+	termState  *term.State
+	jobSignals chan os.Signal
+
+	// silence tracks silent sessions for -silent-after (nil when off);
+	// sessions names them (nil without the session database)
+	silence  *silenceWatch
+	sessions session.Manager
+
+	// errors detects spikes of failed requests for -error-spike (nil when
+	// off)
+	errors *errorSpike
+
+	// ticker builds the daily cost footer
+	ticker *dayTicker
 }
 
 // watchRuntime holds all runtime dependencies for the watch command.
 // This structure enables dependency injection and easier testing.
 type watchRuntime struct {
-	config     *config.Config
-	log        logger.Logger
-	sessionMgr session.Manager
-	reader     reader.Reader
-	watcher    watcher.Watcher
-	monitor    monitor.LiveMonitor
+	shared  *runtime.Runtime
+	config  *config.Config
+	log     logger.Logger
+	reader  reader.Reader
+	watcher watcher.Watcher
+	monitor monitor.LiveMonitor
+	rollups rollup.Store
+	mqtt    *mqttPublisher // nil unless mqtt.broker is set
+
+	// Low-power mode (watch -eco or performance.low_power)
+	lowPower bool
 }
 
 // Close releases all runtime resources.
@@ -310,12 +382,7 @@ func (rt *watchRuntime) Close() {
 	if rt.watcher != nil {
 		_ = rt.watcher.Close() //nolint:errcheck // best effort cleanup
 	}
-	if rt.reader != nil {
-		_ = rt.reader.Close() //nolint:errcheck // best effort cleanup
-	}
-	if rt.sessionMgr != nil {
-		_ = rt.sessionMgr.Close() //nolint:errcheck // best effort cleanup
-	}
+	_ = rt.shared.Close() //nolint:errcheck // best effort cleanup
 }
 
 // Execute runs the watch command.
@@ -330,25 +397,75 @@ func (c *watchCommand) Execute() error {
 		return err
 	}
 
+	if rt.rollups != nil {
+		stop := make(chan struct{})
+		done := make(chan struct{})
+		defer func() {
+			close(stop)
+			<-done
+		}()
+		go func() {
+			defer close(done)
+			c.maintainRollups(rt, stop)
+		}()
+	}
+
+	if rt.mqtt != nil {
+		stop := make(chan struct{})
+		done := make(chan struct{})
+		defer func() {
+			close(stop)
+			<-done
+		}()
+		go func() {
+			defer close(done)
+			rt.mqtt.run(stop)
+		}()
+	}
+
 	return c.runEventLoop(rt)
 }
 
 // initializeRuntime creates and configures all required components.
 func (c *watchCommand) initializeRuntime() (*watchRuntime, error) {
-	rt := &watchRuntime{}
+	// Quiet mode for live monitoring by default.
+	rt := &watchRuntime{shared: c.globalOpts.newRuntime("error")}
 
-	cfg, err := config.Load()
-	if err != nil {
-		return nil, fmt.Errorf("failed to load config: %w", err)
+	var err error
+	if rt.config, err = rt.shared.Config(); err != nil {
+		return nil, err
+	}
+	if rt.log, err = rt.shared.Logger(); err != nil {
+		return nil, err
 	}
-	rt.config = cfg
 
-	rt.log = c.createLogger(cfg)
+	if rt.mqtt, err = newMQTTPublisher(rt.config, rt.log); err != nil {
+		return nil, err
+	}
+
+	c.blockLimit = rt.config.Monitoring.BlockTokenLimit
+	c.contextWindow = rt.config.Monitoring.ContextWindow
+	c.idleAfter = rt.config.Session.IdleAfter
+	c.color = rt.config.Display.ColorEnabled && !c.globalOpts.noColor
+	c.noInput = c.noInput || rt.config.Monitoring.NoInput || !term.IsTerminal(int(os.Stdin.Fd()))
+
+	rt.lowPower = c.eco || rt.config.Performance.LowPower
+	if rt.lowPower {
+		c.refresh = max(c.refresh, lowPowerRefresh)
+	}
 
 	if err := c.initializeStorage(rt); err != nil {
 		rt.Close()
 		return nil, err
 	}
+	c.ticker = &dayTicker{store: rt.rollups, load: rt.loadEntries}
+	if c.silentAfter > 0 {
+		c.silence = newSilenceWatch(c.silentAfter)
+		c.sessions, _ = rt.shared.Sessions() //nolint:errcheck // names are optional
+	}
+	if c.errorSpike > 0 {
+		c.errors = newErrorSpike(c.errorSpike)
+	}
 
 	if err := c.initializeWatcher(rt); err != nil {
 		rt.Close()
@@ -363,51 +480,24 @@ func (c *watchCommand) initializeRuntime() (*watchRuntime, error) {
 	return rt, nil
 }
 
-// createLogger creates a logger with appropriate settings.
-func (c *watchCommand) createLogger(cfg *config.Config) logger.Logger {
-	logLevel := "error" // Quiet mode for live monitoring by default
-	if c.globalOpts.logLevel != "" {
-		logLevel = c.globalOpts.logLevel
-	}
-
-	return logger.New(logger.Config{
-		Level:  logLevel,
-		Format: cfg.Logging.Format,
-		Output: cfg.Logging.Output,
-	})
-}
-
-// initializeStorage sets up session manager and reader.
-// Falls back to in-memory position store if BoltDB is locked by another process
-// (e.g., MCP serve), so watch can still run in read-only mode.
+// initializeStorage sets up the reader and rollup store.
+// The reader falls back to in-memory positions if BoltDB is locked by another
+// process (e.g., MCP serve), so watch can still run in read-only mode.
 func (c *watchCommand) initializeStorage(rt *watchRuntime) error {
-	sessionMgr, err := session.New(session.Config{
-		DBPath: rt.config.Storage.DBPath,
-	}, rt.log)
-
-	var positionStore reader.PositionStore
+	r, err := rt.shared.Reader()
 	if err != nil {
-		rt.log.Warn("BoltDB unavailable, using in-memory position store",
-			"error", err)
-		positionStore = reader.NewMemoryPositionStore()
-	} else {
-		rt.sessionMgr = sessionMgr
-		positionStore, err = reader.NewBoltPositionStore(sessionMgr.DB())
-		if err != nil {
-			rt.log.Warn("failed to create BoltDB position store, using in-memory",
-				"error", err)
-			positionStore = reader.NewMemoryPositionStore()
-		}
+		return err
 	}
+	rt.reader = r
 
-	r, err := reader.New(reader.Config{
-		PositionStore: positionStore,
-		Parser:        parser.New(),
-	}, rt.log)
+	// Rollups need BoltDB; its absence was already logged above.
+	if _, err := rt.shared.Sessions(); err != nil {
+		return nil
+	}
+	rt.rollups, err = rt.shared.Rollups()
 	if err != nil {
-		return fmt.Errorf("failed to initialize reader: %w", err)
+		rt.log.Warn("rollup store unavailable", "error", err)
 	}
-	rt.reader = r
 
 	return nil
 }
@@ -426,17 +516,37 @@ func (c *watchCommand) initializeWatcher(rt *watchRuntime) error {
 
 // initializeMonitor creates the live monitor.
 func (c *watchCommand) initializeMonitor(rt *watchRuntime) error {
-	disc := discovery.New(rt.config.ClaudeConfigDirs, rt.log)
+	disc, err := rt.shared.Discoverer()
+	if err != nil {
+		return err
+	}
 
 	var sessionIDs []string
 	if c.sessionID != "" {
 		sessionIDs = []string{c.sessionID}
 	}
 
+	modelFilter, err := aggregator.ParseModelFilter(c.model)
+	if err != nil {
+		return err
+	}
+
+	maxPoll := rt.config.Monitoring.MaxPollInterval
+	if rt.lowPower {
+		maxPoll = max(maxPoll, lowPowerMaxPoll)
+	}
+
 	mon, err := monitor.New(monitor.Config{
 		SessionIDs:      sessionIDs,
 		RefreshInterval: c.refresh,
+		MaxPollInterval: maxPoll,
 		ClearScreen:     c.clearScreen,
+		ModelFilter:     modelFilter,
+		BatchWindow:     rt.config.Performance.BatchWindow,
+		ReadRetryDelay:  rt.config.Performance.ReadRetryDelay,
+		ReadRetries:     rt.config.Performance.ReadRetries,
+		Workers:         rt.config.Performance.WorkerPoolSize,
+		SkipPercentiles: rt.lowPower,
 	}, rt.watcher, rt.reader, disc, rt.log)
 	if err != nil {
 		return fmt.Errorf("failed to create monitor: %w", err)
@@ -464,6 +574,73 @@ func (c *watchCommand) startMonitor(rt *watchRuntime) error {
 	}
 }
 
+// maintainRollups keeps the daily rollups current while watch runs,
+// so reports read pre-aggregated data instead of re-parsing files.
+// After each ingest it updates the heartbeat file read by health, once
+// every retentionInterval it applies the retention policy, and with
+// team.share set it pushes the daily totals to the team server.
+func (c *watchCommand) maintainRollups(rt *watchRuntime, stop <-chan struct{}) {
+	interval := rollupInterval
+	if rt.lowPower {
+		interval = lowPowerRollupInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	hbPath := heartbeatPath(rt.config)
+	hb := heartbeat{PID: os.Getpid(), StartedAt: time.Now(), Interval: interval}
+	defer func() {
+		_ = os.Remove(hbPath) //nolint:errcheck // best effort cleanup
+	}()
+
+	policy := rt.config.Retention.Policy()
+	var lastPrune, lastShare time.Time
+
+	start := overhead.Take()
+	prev := start
+	for {
+		if _, err := ingestRollups(context.Background(), rt.shared, rt.rollups); err != nil {
+			rt.log.Warn("rollup ingest failed", "error", err)
+		} else {
+			hb.LastIngest = time.Now()
+			if rt.config.Team.Share.Endpoint != "" && time.Since(lastShare) >= teamShareInterval(rt.config) {
+				lastShare = time.Now()
+				if report, err := shareTeamUsage(context.Background(), rt.config, rt.rollups, lastShare); err != nil {
+					rt.log.Warn("team usage push failed", "endpoint", rt.config.Team.Share.Endpoint, "error", err)
+				} else {
+					rt.log.Info("team usage pushed", "endpoint", rt.config.Team.Share.Endpoint, "days", len(report.Days))
+				}
+			}
+		}
+
+		if policy.Enabled() && time.Since(lastPrune) >= retentionInterval {
+			if result, err := applyRetention(rt.shared, policy, time.Now(), false); err != nil {
+				rt.log.Warn("retention prune failed", "error", err)
+			} else {
+				lastPrune = time.Now()
+				rt.log.Info("retention applied",
+					"rollups", result.Rollups,
+					"baselines", result.Baselines,
+					"focus_windows", result.Focus)
+			}
+		}
+
+		now := overhead.Take()
+		last, total := now.Since(prev), now.Since(start)
+		hb.Overhead, hb.OverheadTotal = &last, &total
+		prev = now
+		if err := writeHeartbeat(hbPath, hb); err != nil {
+			rt.log.Warn("failed to update heartbeat", "error", err)
+		}
+
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
 // runEventLoop handles signals, keyboard input, and monitor updates.
 func (c *watchCommand) runEventLoop(rt *watchRuntime) error {
 	sigChan := c.setupSignalHandler()
@@ -471,6 +648,8 @@ func (c *watchCommand) runEventLoop(rt *watchRuntime) error {
 	if cleanup != nil {
 		defer cleanup()
 	}
+	c.setupJobControl()
+	defer signal.Stop(c.jobSignals)
 
 	updatesChan := c.getUpdatesChannel(rt.monitor)
 	if updatesChan == nil {
@@ -479,7 +658,7 @@ func (c *watchCommand) runEventLoop(rt *watchRuntime) error {
 
 	c.displayInitialScreen()
 
-	return c.processEvents(rt, sigChan, keyChan, updatesChan)
+	return c.processEvents(rt, sigChan, c.jobSignals, keyChan, updatesChan)
 }
 
 // setupSignalHandler configures OS signal handling.
@@ -491,30 +670,98 @@ func (c *watchCommand) setupSignalHandler() <-chan os.Signal {
 
 // setupKeyboardInput configures terminal for raw input mode.
 // Returns a channel for key events and a cleanup function.
+//
+// With noInput the terminal is left untouched and no key ever arrives;
+// signals are the only control path then.
 func (c *watchCommand) setupKeyboardInput() (<-chan byte, func()) {
 	keyChan := make(chan byte, 10)
 
-	if !term.IsTerminal(int(os.Stdin.Fd())) {
+	if c.noInput {
 		return keyChan, nil
 	}
 
-	oldState, err := term.MakeRaw(int(os.Stdin.Fd()))
-	if err != nil {
+	if err := c.enterRawMode(); err != nil {
 		return keyChan, nil
 	}
 
+	// Start keyboard reader goroutine
+	go c.readKeyboardInput(keyChan)
+
+	return keyChan, c.restoreTerminal
+}
+
+// enterRawMode puts stdin in raw mode. Only the state before the first
+// call is kept, so that resuming from a suspend does not overwrite it with
+// a raw state.
+func (c *watchCommand) enterRawMode() error {
+	fd := int(os.Stdin.Fd())
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return err
+	}
+	if c.termState == nil {
+		c.termState = state
+	}
+
 	// Re-enable output processing so \n is converted to \r\n.
 	// term.MakeRaw disables OPOST, causing staircase output in tables.
-	enableOutputProcessing(int(os.Stdin.Fd()))
+	enableOutputProcessing(fd)
+	return nil
+}
 
-	// Start keyboard reader goroutine
-	go c.readKeyboardInput(keyChan)
+// restoreTerminal restores the terminal state saved by enterRawMode.
+func (c *watchCommand) restoreTerminal() {
+	if c.termState != nil {
+		_ = term.Restore(int(os.Stdin.Fd()), c.termState) //nolint:errcheck
+	}
+}
 
-	cleanup := func() {
-		_ = term.Restore(int(os.Stdin.Fd()), oldState) //nolint:errcheck
+// setupJobControl starts handling SIGCONT, so that watch re-enters raw
+// mode and repaints whenever it is continued, including after a SIGTSTP
+// from outside that stopped it in raw mode. Without job control (Windows)
+// jobSignals never receives.
+func (c *watchCommand) setupJobControl() {
+	c.jobSignals = make(chan os.Signal, 1)
+	if continueSignal != nil {
+		signal.Notify(c.jobSignals, continueSignal)
 	}
+}
+
+// suspend restores the terminal and stops the process (Ctrl+Z). It
+// returns once the process is continued, with the screen repainted.
+func (c *watchCommand) suspend() {
+	if continueSignal == nil {
+		return
+	}
+	c.restoreTerminal()
+	fmt.Print("\n")
 
-	return keyChan, cleanup
+	stopProcess()
+	c.resume()
+}
+
+// resume re-enters raw mode, if watch was using it, and repaints the
+// screen. The shell may have changed the terminal while watch was stopped,
+// and other programs may have drawn over it.
+func (c *watchCommand) resume() {
+	if c.termState != nil {
+		_ = c.enterRawMode() //nolint:errcheck // keys stay in cooked mode
+	}
+	c.repaint()
+}
+
+// repaint redraws the header and the last update, or the help overlay.
+func (c *watchCommand) repaint() {
+	if c.clearScreen {
+		fmt.Print("\033[2J\033[H")
+		c.displayHeader()
+	}
+	switch {
+	case c.showHelp:
+		c.displayHelpOverlay()
+	case c.lastUpdate != nil:
+		c.displayUpdate(*c.lastUpdate)
+	}
 }
 
 // readKeyboardInput reads bytes from stdin and sends to channel.
@@ -553,6 +800,7 @@ func (c *watchCommand) displayInitialScreen() {
 func (c *watchCommand) processEvents(
 	rt *watchRuntime,
 	sigChan <-chan os.Signal,
+	jobChan <-chan os.Signal,
 	keyChan <-chan byte,
 	updatesChan <-chan monitor.Update,
 ) error { //nolint:unparam // error return kept for future error handling
@@ -562,20 +810,57 @@ func (c *watchCommand) processEvents(
 			c.handleQuit(rt.monitor, rt.log)
 			return nil
 
+		case <-jobChan:
+			c.resume()
+
 		case key := <-keyChan:
 			if c.handleKeyPress(key, rt.monitor, rt.log) == "quit" {
 				return nil
 			}
 
 		case update := <-updatesChan:
-			c.handleUpdate(update)
+			c.handleUpdate(update, c.checkAlerts(rt, update))
+			if rt.mqtt != nil {
+				rt.mqtt.Offer(newMQTTStatus(update, rt.rollups, time.Now()))
+			}
 		}
 	}
 }
 
+// checkAlerts alerts on the sessions that have just gone silent for
+// -silent-after and on a spike of failed requests (-error-spike), and
+// reports whether there were any. Both are shown by displayUpdate while
+// they last.
+func (c *watchCommand) checkAlerts(rt *watchRuntime, update monitor.Update) bool {
+	alerted := false
+	if c.silence != nil {
+		for _, s := range c.silence.observe(update) {
+			label := sessionLabel(c.sessions, s.SessionID)
+			rt.log.Warn("session went silent", "session", s.SessionID, "name", label,
+				"last_usage", s.LastSeen)
+			postWatchAlert(rt.config, rt.log, silenceMessage(s, label, c.silentAfter))
+			alerted = true
+		}
+	}
+	if c.errors != nil && c.errors.observe(update) {
+		rt.log.Warn("API error spike", "errors", c.errors.count(), "window", errorSpikeWindow)
+		postWatchAlert(rt.config, rt.log, errorSpikeMessage(c.errors.count(), update.Timestamp))
+		alerted = true
+	}
+	return alerted
+}
+
 // handleUpdate processes a monitor update event.
-func (c *watchCommand) handleUpdate(update monitor.Update) {
+//
+// Accessible output is appended rather than redrawn, so it is only
+// printed when the entry count changes or an alert has just been raised;
+// a screen reader would otherwise re-read the same figures on every tick.
+func (c *watchCommand) handleUpdate(update monitor.Update, alerted bool) {
+	unchanged := !alerted && c.lastUpdate != nil && c.lastUpdate.Stats.Count == update.Stats.Count
 	c.lastUpdate = &update
+	if c.globalOpts.accessible && unchanged {
+		return
+	}
 	if c.showHelp {
 		c.displayHelpOverlay()
 	} else {
@@ -585,15 +870,23 @@ func (c *watchCommand) handleUpdate(update monitor.Update) {
 
 // displayHeader shows the initial header for the watch command.
 func (c *watchCommand) displayHeader() {
-	fmt.Println("🔍 Live Token Monitor - Press ? for help, q to quit")
+	out := c.globalOpts.output()
+
+	if c.noInput {
+		out.Println(c.globalOpts.decorate("🔍", "Live Token Monitor - Press Ctrl+C to quit"))
+	} else {
+		out.Println(c.globalOpts.decorate("🔍", "Live Token Monitor - Press ? for help, q to quit"))
+	}
 	if c.sessionID != "" {
-		fmt.Printf("Session: %s | ", c.sessionID)
+		out.Printf("Session: %s | ", c.sessionID)
 	} else {
-		fmt.Print("All Sessions | ")
+		out.Printf("All Sessions | ")
 	}
-	fmt.Printf("Refresh: %s\n", c.refresh)
-	fmt.Println(strings.Repeat("─", 80))
-	fmt.Println()
+	out.Printf("Refresh: %s\n", c.refresh)
+	if !c.globalOpts.accessible {
+		out.Println(strings.Repeat("─", 80))
+	}
+	out.Println()
 }
 
 // handleKeyPress processes keyboard input and returns an action.
@@ -607,6 +900,10 @@ func (c *watchCommand) handleKeyPress(key byte, mon monitor.LiveMonitor, log log
 		c.handleReset(mon, log)
 		return "reset"
 
+	case 26: // Ctrl+Z, which raw mode delivers as a key instead of SIGTSTP
+		c.suspend()
+		return "suspend"
+
 	case '?', 'h', 'H':
 		c.showHelp = !c.showHelp
 		if c.showHelp {
@@ -656,7 +953,7 @@ func (c *watchCommand) handleKeyPress(key byte, mon monitor.LiveMonitor, log log
 func (c *watchCommand) handleQuit(mon monitor.LiveMonitor, log logger.Logger) {
 	// Move cursor down and print exit message
 	fmt.Print("\n\n")
-	fmt.Println("Stopping monitor...")
+	c.globalOpts.infof("Stopping monitor...\n")
 	if err := mon.Stop(); err != nil {
 		log.Error("failed to stop monitor", "error", err)
 	}
@@ -664,6 +961,8 @@ func (c *watchCommand) handleQuit(mon monitor.LiveMonitor, log logger.Logger) {
 
 // handleReset resets the monitor statistics.
 func (c *watchCommand) handleReset(mon monitor.LiveMonitor, log logger.Logger) {
+	out := c.globalOpts.output()
+
 	// Try to reset using the Resettable interface
 	if resettable, ok := mon.(interface{ ResetStats() }); ok {
 		resettable.ResetStats()
@@ -675,28 +974,41 @@ func (c *watchCommand) handleReset(mon monitor.LiveMonitor, log logger.Logger) {
 		fmt.Print("\033[2J\033[H")
 		c.displayHeader()
 	}
-	fmt.Println("📊 Statistics have been reset")
-	fmt.Println()
+	out.Println(c.globalOpts.decorate("📊", "Statistics have been reset"))
+	out.Println()
 }
 
 // displayHelpOverlay shows the keyboard shortcuts help.
 func (c *watchCommand) displayHelpOverlay() {
+	out := c.globalOpts.output()
+
 	if c.clearScreen {
 		fmt.Print("\033[5;1H\033[J") // Move to line 5 and clear
 	}
 
-	fmt.Println()
-	fmt.Println("┌─────────────────────────────────────────────────────────┐")
-	fmt.Println("│                  Keyboard Shortcuts                     │")
-	fmt.Println("├─────────────────────────────────────────────────────────┤")
-	fmt.Println("│  q, Q, Ctrl+C    Quit the monitor                       │")
-	fmt.Println("│  r, R            Reset statistics                       │")
-	fmt.Println("│  ?, h, H         Toggle this help overlay               │")
-	fmt.Println("│  ESC             Close this help overlay                │")
-	fmt.Println("├─────────────────────────────────────────────────────────┤")
-	fmt.Println("│  Press any key to close this help and return to stats   │")
-	fmt.Println("└─────────────────────────────────────────────────────────┘")
-	fmt.Println()
+	out.Println()
+	if c.globalOpts.accessible {
+		out.Println("Keyboard shortcuts:")
+		out.Println("q, Q, or Ctrl+C: quit the monitor")
+		out.Println("r or R: reset statistics")
+		out.Println("Ctrl+Z: suspend; fg resumes and redraws")
+		out.Println("?, h, or H: toggle this help")
+		out.Println("Escape or any other key: close this help")
+		out.Println()
+		return
+	}
+	out.Println("┌─────────────────────────────────────────────────────────┐")
+	out.Println("│                  Keyboard Shortcuts                     │")
+	out.Println("├─────────────────────────────────────────────────────────┤")
+	out.Println("│  q, Q, Ctrl+C    Quit the monitor                       │")
+	out.Println("│  r, R            Reset statistics                       │")
+	out.Println("│  Ctrl+Z          Suspend (fg resumes and redraws)       │")
+	out.Println("│  ?, h, H         Toggle this help overlay               │")
+	out.Println("│  ESC             Close this help overlay                │")
+	out.Println("├─────────────────────────────────────────────────────────┤")
+	out.Println("│  Press any key to close this help and return to stats   │")
+	out.Println("└─────────────────────────────────────────────────────────┘")
+	out.Println()
 }
 
 // displayUpdate renders a live monitoring update.
@@ -707,166 +1019,399 @@ func (c *watchCommand) displayUpdate(update monitor.Update) {
 		fmt.Print("\033[5;1H\033[J")
 	}
 
-	// Format based on configured format
-	switch c.format {
-	case "simple":
+	// Format based on configured format; accessible output is always the
+	// labeled-line format.
+	switch {
+	case c.compact:
+		c.globalOpts.output().Println(c.compactLine(update))
+	case c.globalOpts.accessible, c.format == "simple":
 		c.displaySimple(update)
 	default:
 		c.displayTable(update)
 	}
+
+	if c.ticker != nil {
+		if line := c.ticker.line(update.Timestamp); line != "" {
+			out := c.globalOpts.output()
+			out.Printf("\n%s\n", c.globalOpts.decorate("💰", line))
+		}
+	}
+
+	if c.silence != nil {
+		out := c.globalOpts.output()
+		for _, s := range c.silence.current() {
+			text := silenceText(sessionLabel(c.sessions, s.SessionID), update.Timestamp.Sub(s.LastSeen))
+			out.Printf("%s\n", c.globalOpts.decorate("⚠️", text))
+		}
+	}
+	if c.errors != nil && c.errors.active {
+		c.globalOpts.output().Printf("%s\n", c.globalOpts.decorate("⚠️", errorSpikeText(c.errors.count())))
+	}
 }
 
 // displaySimple shows a simple text format.
 func (c *watchCommand) displaySimple(update monitor.Update) {
+	out := c.globalOpts.output()
+
 	stats := update.Stats
 	delta := update.Delta
 	cumulative := update.Cumulative
 
-	fmt.Printf("📊 Token Usage Statistics (Last updated: %s)\n\n",
+	out.Printf("%s (Last updated: %s)\n\n",
+		c.globalOpts.decorate("📊", "Token Usage Statistics"),
 		update.Timestamp.Format("15:04:05"))
 
-	fmt.Printf("Total Requests:  %d (session: %+d, now: %+d)\n",
+	out.Printf("Total Requests:  %d (session: %+d, now: %+d)\n",
 		stats.Count, cumulative.NewEntries, delta.NewEntries)
-	fmt.Printf("Input Tokens:    %d (session: %+d, now: %+d)\n",
+	out.Printf("Input Tokens:    %d (session: %+d, now: %+d)\n",
 		stats.InputTokens, cumulative.InputTokens, delta.InputTokens)
-	fmt.Printf("Output Tokens:   %d (session: %+d, now: %+d)\n",
+	out.Printf("Output Tokens:   %d (session: %+d, now: %+d)\n",
 		stats.OutputTokens, cumulative.OutputTokens, delta.OutputTokens)
-	fmt.Printf("Total Tokens:    %d (session: %+d, now: %+d)\n",
+	out.Printf("Total Tokens:    %d (session: %+d, now: %+d)\n",
 		stats.TotalTokens, cumulative.TotalTokens, delta.TotalTokens)
+	if c.detailed {
+		out.Printf("Cache Creation:  %d\n", stats.CacheCreationTokens)
+		out.Printf("Cache Read:      %d\n", stats.CacheReadTokens)
+	}
+	if stats.ErrorCount > 0 {
+		out.Printf("API Errors:      %d (%.1f%%, session: %+d)\n",
+			stats.ErrorCount, stats.ErrorRate(), cumulative.Errors)
+	}
 
-	fmt.Println()
-	fmt.Printf("Average/Request: %.0f\n", stats.AvgTokens)
-	fmt.Printf("Min Tokens:      %d\n", stats.MinTokens)
-	fmt.Printf("Max Tokens:      %d\n", stats.MaxTokens)
+	out.Println()
+	out.Printf("Average/Request: %.0f\n", stats.AvgTokens)
+	out.Printf("Min Tokens:      %d\n", stats.MinTokens)
+	out.Printf("Max Tokens:      %d\n", stats.MaxTokens)
 
 	if stats.P50Tokens > 0 {
-		fmt.Printf("P50 Tokens:      %d\n", stats.P50Tokens)
-		fmt.Printf("P95 Tokens:      %d\n", stats.P95Tokens)
-		fmt.Printf("P99 Tokens:      %d\n", stats.P99Tokens)
+		out.Printf("P50 Tokens:      %d\n", stats.P50Tokens)
+		out.Printf("P95 Tokens:      %d\n", stats.P95Tokens)
+		out.Printf("P99 Tokens:      %d\n", stats.P99Tokens)
 	}
 
 	if !stats.FirstSeen.IsZero() {
-		fmt.Printf("\nFirst Activity:  %s\n", stats.FirstSeen.Format("2006-01-02 15:04:05"))
-		fmt.Printf("Last Activity:   %s\n", stats.LastSeen.Format("2006-01-02 15:04:05"))
+		out.Printf("\nFirst Activity:  %s\n", stats.FirstSeen.Format("2006-01-02 15:04:05"))
+		out.Printf("Last Activity:   %s\n", stats.LastSeen.Format("2006-01-02 15:04:05"))
 		duration := stats.LastSeen.Sub(stats.FirstSeen)
 		if duration > 0 {
-			fmt.Printf("Duration:        %s\n", duration.Round(time.Second))
+			out.Printf("Duration:        %s\n", display.FormatDurationLong(duration, 3))
 		}
 	}
+	if line := c.contextLine(update); line != "" {
+		out.Printf("Context:         %s\n", line)
+	}
 
 	// Burn rate
 	burnRate := update.BurnRate
 	if burnRate.EntryCount > 0 {
-		fmt.Printf("\n🔥 Burn Rate (5m window)\n")
-		fmt.Printf("Tokens/min:      %.1f\n", burnRate.TokensPerMinute)
-		fmt.Printf("Tokens/hour:     %.0f (projected)\n", burnRate.TokensPerHour)
-		fmt.Printf("Entries:         %d\n", burnRate.EntryCount)
+		out.Printf("\n%s\n", c.globalOpts.decorate("🔥", "Burn Rate (5m window)"))
+		out.Printf("Tokens/min:      %.1f\n", burnRate.TokensPerMinute)
+		out.Printf("Tokens/hour:     %.0f (projected)\n", burnRate.TokensPerHour)
+		out.Printf("Entries:         %d\n", burnRate.EntryCount)
 	}
 
 	// Current billing block
 	block := update.CurrentBlock
 	if block.EntryCount > 0 {
-		fmt.Printf("\n📊 Current Billing Block (%s - %s UTC)\n",
+		out.Printf("\n%s (%s - %s UTC)\n",
+			c.globalOpts.decorate("📊", "Current Billing Block"),
 			block.StartTime.UTC().Format("15:04"),
 			block.EndTime.UTC().Format("15:04"))
-		fmt.Printf("Block Tokens:    %d\n", block.TotalTokens)
-		fmt.Printf("Block Entries:   %d\n", block.EntryCount)
+		out.Printf("Block Tokens:    %d\n", block.TotalTokens)
+		out.Printf("Block Entries:   %d\n", block.EntryCount)
 
 		// Calculate time remaining in block
 		remaining := block.EndTime.Sub(time.Now().UTC())
 		if remaining > 0 {
-			fmt.Printf("Time Remaining:  %s\n", remaining.Round(time.Minute))
+			out.Printf("Time Remaining:  %s\n", display.FormatDurationLong(remaining, 2))
+		}
+		if line := c.burnDownLine(update); line != "" {
+			out.Printf("Burn-down:       %s\n", line)
+		}
+		if block.RateLimits > 0 {
+			out.Printf("Rate Limits:     %s\n", rateLimitText(block))
+		}
+
+		if len(block.Models) > 0 {
+			out.Printf("\n%s\n", c.globalOpts.decorate("🧠", "Models This Block"))
+			for _, m := range block.Models {
+				out.Printf("%-28s %d tokens (%.1f%%), last %s\n", m.Model+":", m.TotalTokens,
+					blockShare(m.TotalTokens, block.TotalTokens), m.LastSeen.Local().Format("15:04:05"))
+			}
 		}
 	}
 }
 
 // displayTable shows a table format.
 func (c *watchCommand) displayTable(update monitor.Update) {
+	out := c.globalOpts.output()
+
 	stats := update.Stats
 	delta := update.Delta
 	cumulative := update.Cumulative
 
-	fmt.Printf("📊 Live Token Monitor - %s\n\n",
+	out.Printf("📊 Live Token Monitor - %s\n\n",
 		update.Timestamp.Format("2006-01-02 15:04:05"))
 
 	// Token counts table with session cumulative and real-time delta
-	fmt.Println("┌─────────────────┬──────────────┬──────────────┬────────────┐")
-	fmt.Println("│ Metric          │ Total        │ Session +    │ Now +      │")
-	fmt.Println("├─────────────────┼──────────────┼──────────────┼────────────┤")
-	fmt.Printf("│ Requests        │ %12d │ %+12d │ %+10d │\n", stats.Count, cumulative.NewEntries, delta.NewEntries)
-	fmt.Printf("│ Input Tokens    │ %12d │ %+12d │ %+10d │\n", stats.InputTokens, cumulative.InputTokens, delta.InputTokens)
-	fmt.Printf("│ Output Tokens   │ %12d │ %+12d │ %+10d │\n", stats.OutputTokens, cumulative.OutputTokens, delta.OutputTokens)
-	fmt.Printf("│ Total Tokens    │ %12d │ %+12d │ %+10d │\n", stats.TotalTokens, cumulative.TotalTokens, delta.TotalTokens)
-	fmt.Println("└─────────────────┴──────────────┴──────────────┴────────────┘")
+	out.Println("┌─────────────────┬──────────────┬──────────────┬────────────┐")
+	out.Println("│ Metric          │ Total        │ Session +    │ Now +      │")
+	out.Println("├─────────────────┼──────────────┼──────────────┼────────────┤")
+	out.Printf("│ Requests        │ %12d │ %+12d │ %+10d │\n", stats.Count, cumulative.NewEntries, delta.NewEntries)
+	out.Printf("│ Input Tokens    │ %12d │ %+12d │ %+10d │\n", stats.InputTokens, cumulative.InputTokens, delta.InputTokens)
+	out.Printf("│ Output Tokens   │ %12d │ %+12d │ %+10d │\n", stats.OutputTokens, cumulative.OutputTokens, delta.OutputTokens)
+	out.Printf("│ Total Tokens    │ %12d │ %+12d │ %+10d │\n", stats.TotalTokens, cumulative.TotalTokens, delta.TotalTokens)
+	if c.detailed {
+		out.Printf("│ Cache Creation  │ %12d │ %12s │ %10s │\n", stats.CacheCreationTokens, "", "")
+		out.Printf("│ Cache Read      │ %12d │ %12s │ %10s │\n", stats.CacheReadTokens, "", "")
+	}
+	if stats.ErrorCount > 0 {
+		out.Printf("│ API Errors      │ %12d │ %+12d │ %+10d │\n", stats.ErrorCount, cumulative.Errors, delta.Errors)
+	}
+	out.Println("└─────────────────┴──────────────┴──────────────┴────────────┘")
 
 	// Statistics table
-	fmt.Println()
-	fmt.Println("┌─────────────────┬──────────────┐")
-	fmt.Println("│ Statistic       │ Value        │")
-	fmt.Println("├─────────────────┼──────────────┤")
-	fmt.Printf("│ Average         │ %12.0f │\n", stats.AvgTokens)
-	fmt.Printf("│ Min             │ %12d │\n", stats.MinTokens)
-	fmt.Printf("│ Max             │ %12d │\n", stats.MaxTokens)
+	out.Println()
+	out.Println("┌─────────────────┬──────────────┐")
+	out.Println("│ Statistic       │ Value        │")
+	out.Println("├─────────────────┼──────────────┤")
+	out.Printf("│ Average         │ %12.0f │\n", stats.AvgTokens)
+	out.Printf("│ Min             │ %12d │\n", stats.MinTokens)
+	out.Printf("│ Max             │ %12d │\n", stats.MaxTokens)
 
 	if stats.P50Tokens > 0 {
-		fmt.Printf("│ P50             │ %12d │\n", stats.P50Tokens)
-		fmt.Printf("│ P95             │ %12d │\n", stats.P95Tokens)
-		fmt.Printf("│ P99             │ %12d │\n", stats.P99Tokens)
+		out.Printf("│ P50             │ %12d │\n", stats.P50Tokens)
+		out.Printf("│ P95             │ %12d │\n", stats.P95Tokens)
+		out.Printf("│ P99             │ %12d │\n", stats.P99Tokens)
 	}
-	fmt.Println("└─────────────────┴──────────────┘")
+	out.Println("└─────────────────┴──────────────┘")
 
 	// Activity timeline
 	if !stats.FirstSeen.IsZero() {
-		fmt.Println()
-		fmt.Printf("⏱️  First: %s | Last: %s",
+		out.Println()
+		out.Printf("⏱️  First: %s | Last: %s",
 			stats.FirstSeen.Format("15:04:05"),
 			stats.LastSeen.Format("15:04:05"))
 
 		duration := stats.LastSeen.Sub(stats.FirstSeen)
 		if duration > 0 {
-			fmt.Printf(" | Duration: %s", duration.Round(time.Second))
+			out.Printf(" | Duration: %s", display.FormatDurationLong(duration, 3))
 		}
-		fmt.Println()
+		out.Println()
+	}
+	if line := c.contextLine(update); line != "" {
+		out.Printf("🧩 Context: %s\n", line)
 	}
 
 	// Burn rate table
 	burnRate := update.BurnRate
 	if burnRate.EntryCount > 0 {
-		fmt.Println()
-		fmt.Println("🔥 Burn Rate (5-minute window)")
-		fmt.Println("┌─────────────────┬──────────────┐")
-		fmt.Println("│ Metric          │ Value        │")
-		fmt.Println("├─────────────────┼──────────────┤")
-		fmt.Printf("│ Tokens/min      │ %12.1f │\n", burnRate.TokensPerMinute)
-		fmt.Printf("│ Tokens/hour     │ %12.0f │\n", burnRate.TokensPerHour)
-		fmt.Printf("│ Input/min       │ %12.1f │\n", burnRate.InputTokensPerMinute)
-		fmt.Printf("│ Output/min      │ %12.1f │\n", burnRate.OutputTokensPerMinute)
-		fmt.Printf("│ Entries         │ %12d │\n", burnRate.EntryCount)
-		fmt.Println("└─────────────────┴──────────────┘")
+		out.Println()
+		out.Println("🔥 Burn Rate (5-minute window)")
+		out.Println("┌─────────────────┬──────────────┐")
+		out.Println("│ Metric          │ Value        │")
+		out.Println("├─────────────────┼──────────────┤")
+		out.Printf("│ Tokens/min      │ %12.1f │\n", burnRate.TokensPerMinute)
+		out.Printf("│ Tokens/hour     │ %12.0f │\n", burnRate.TokensPerHour)
+		out.Printf("│ Input/min       │ %12.1f │\n", burnRate.InputTokensPerMinute)
+		out.Printf("│ Output/min      │ %12.1f │\n", burnRate.OutputTokensPerMinute)
+		out.Printf("│ Entries         │ %12d │\n", burnRate.EntryCount)
+		out.Println("└─────────────────┴──────────────┘")
 	}
 
 	// Billing block table
 	block := update.CurrentBlock
 	if block.EntryCount > 0 {
-		fmt.Println()
-		fmt.Printf("📊 Current Billing Block (%s - %s UTC)\n",
+		out.Println()
+		out.Printf("📊 Current Billing Block (%s - %s UTC)\n",
 			block.StartTime.UTC().Format("15:04"),
 			block.EndTime.UTC().Format("15:04"))
-		fmt.Println("┌─────────────────┬──────────────┐")
-		fmt.Println("│ Metric          │ Value        │")
-		fmt.Println("├─────────────────┼──────────────┤")
-		fmt.Printf("│ Total Tokens    │ %12d │\n", block.TotalTokens)
-		fmt.Printf("│ Input Tokens    │ %12d │\n", block.InputTokens)
-		fmt.Printf("│ Output Tokens   │ %12d │\n", block.OutputTokens)
-		fmt.Printf("│ Entries         │ %12d │\n", block.EntryCount)
+		out.Println("┌─────────────────┬──────────────┐")
+		out.Println("│ Metric          │ Value        │")
+		out.Println("├─────────────────┼──────────────┤")
+		out.Printf("│ Total Tokens    │ %12d │\n", block.TotalTokens)
+		out.Printf("│ Input Tokens    │ %12d │\n", block.InputTokens)
+		out.Printf("│ Output Tokens   │ %12d │\n", block.OutputTokens)
+		out.Printf("│ Entries         │ %12d │\n", block.EntryCount)
 
 		// Calculate time remaining in block
 		remaining := block.EndTime.Sub(time.Now().UTC())
 		if remaining > 0 {
 			hours := int(remaining.Hours())
 			mins := int(remaining.Minutes()) % 60
-			fmt.Printf("│ Time Left       │ %9dh%02dm │\n", hours, mins)
+			out.Printf("│ Time Left       │ %9dh%02dm │\n", hours, mins)
+		}
+		out.Println("└─────────────────┴──────────────┘")
+		if line := c.burnDownLine(update); line != "" {
+			out.Printf("%s\n", line)
 		}
-		fmt.Println("└─────────────────┴──────────────┘")
+		if block.RateLimits > 0 {
+			out.Printf("🚦 %s\n", rateLimitText(block))
+		}
+
+		c.displayBlockModels(block)
+	}
+}
+
+// compactLine renders an update as one line, e.g. "15:04:05  12 req (+1)
+// · 1.2M tokens (+3.4K) · 5.2K/min · block 340K, 2h10m left, 30% of 1.2M
+// quota used".
+func (c *watchCommand) compactLine(update monitor.Update) string {
+	stats := update.Stats
+	parts := []string{
+		fmt.Sprintf("%d req (%+d)", stats.Count, update.Cumulative.NewEntries),
+		fmt.Sprintf("%s tokens (+%s)", display.FormatCompact(stats.TotalTokens),
+			display.FormatCompact(update.Cumulative.TotalTokens)),
+	}
+	if update.BurnRate.EntryCount > 0 {
+		parts = append(parts, display.FormatCompact(int(update.BurnRate.TokensPerMinute))+"/min")
+	}
+
+	block := update.CurrentBlock
+	if block.EntryCount > 0 {
+		part := "block " + display.FormatCompact(block.TotalTokens)
+		if remaining := block.EndTime.Sub(update.Timestamp); remaining > 0 {
+			part += ", " + display.FormatDurationLong(remaining, 2) + " left"
+		}
+		limit, reference := c.blockLimit, "quota"
+		if limit <= 0 {
+			limit, reference = update.PeakBlockTokens, "busiest block"
+		}
+		if pace, ok := block.Pace(limit, update.Timestamp); ok {
+			part += fmt.Sprintf(", %.0f%% of %s %s used", pace.Used*100, display.FormatCompact(limit), reference)
+		}
+		if block.RateLimits > 0 {
+			part += ", " + rateLimitCount(block.RateLimits)
+		}
+		parts = append(parts, part)
+	}
+	if usage := recentContext(update.LatestEntry, update.Timestamp, c.idleAfter, c.contextWindow); usage != nil {
+		parts = append(parts, "context "+contextText(*usage))
+	}
+	return update.Timestamp.Format("15:04:05") + "  " + strings.Join(parts, " · ")
+}
+
+// displayBlockModels shows the current billing block's usage per model, so
+// switching models shows up live.
+func (c *watchCommand) displayBlockModels(block aggregator.BillingBlock) {
+	if len(block.Models) == 0 {
+		return
+	}
+	out := c.globalOpts.output()
+
+	out.Println()
+	out.Println("🧠 Models This Block")
+	out.Println("┌──────────────────────────────┬──────────────┬─────────┬──────────┐")
+	out.Println("│ Model                        │ Tokens       │ Share   │ Last     │")
+	out.Println("├──────────────────────────────┼──────────────┼─────────┼──────────┤")
+	for _, m := range block.Models {
+		out.Printf("│ %-28s │ %12d │ %6.1f%% │ %8s │\n",
+			truncateRunes(m.Model, 28), m.TotalTokens,
+			blockShare(m.TotalTokens, block.TotalTokens), m.LastSeen.Local().Format("15:04:05"))
+	}
+	out.Println("└──────────────────────────────┴──────────────┴─────────┴──────────┘")
+}
+
+// burnDownWidth is the width of the burn-down bar in watch output.
+const burnDownWidth = 30
+
+// burnDownLine shows the current block's pace against the block quota, or
+// against the busiest earlier block when none is configured, e.g.
+// "███░░│░░░░ 30% of 1.2M quota used, 50% of block elapsed (ahead of
+// pace)". The bar is left out in accessible mode. It returns "" when there
+// is nothing to measure against.
+func (c *watchCommand) burnDownLine(update monitor.Update) string {
+	limit, reference := c.blockLimit, "quota"
+	if limit <= 0 {
+		limit, reference = update.PeakBlockTokens, "busiest block"
+	}
+	pace, ok := update.CurrentBlock.Pace(limit, update.Timestamp)
+	if !ok {
+		return ""
+	}
+
+	status := "ahead of pace"
+	if pace.OverPace() {
+		status = "over pace"
+	}
+	line := fmt.Sprintf("%.0f%% of %s %s used, %.0f%% of block elapsed (%s)",
+		pace.Used*100, display.FormatCompact(limit), reference, pace.Elapsed*100, status)
+	if c.globalOpts.accessible {
+		return line
+	}
+
+	bar := display.BurnDownBar(pace, burnDownWidth)
+	if c.color {
+		bar = paceStyle(pace).Render(bar)
+	}
+	return bar + " " + line
+}
+
+// contextLine estimates how full the active session's context window is,
+// e.g. "~62% full (124.0K of 200.0K tokens)", with a warning once
+// compaction is near. It returns "" when no session has been active
+// within session.idle_after.
+func (c *watchCommand) contextLine(update monitor.Update) string {
+	usage := recentContext(update.LatestEntry, update.Timestamp, c.idleAfter, c.contextWindow)
+	if usage == nil {
+		return ""
+	}
+	line := contextText(*usage)
+	if usage.Warn() {
+		warning := c.globalOpts.decorate("⚠️", "compaction near")
+		if c.color {
+			warning = lipgloss.NewStyle().Foreground(lipgloss.Color("11")).Render(warning)
+		}
+		line += " " + warning
+	}
+	return line
+}
+
+// recentContext estimates the context window use after entry, the newest
+// main-conversation entry, or returns nil if there is none or it is older
+// than idle: an idle session's context may have been cleared since.
+func recentContext(entry parser.UsageEntry, now time.Time, idle time.Duration, window int) *analysis.ContextUsage {
+	if entry.Timestamp.IsZero() || (idle > 0 && now.Sub(entry.Timestamp) > idle) {
+		return nil
+	}
+	usage := analysis.EstimateContext(entry, window)
+	return &usage
+}
+
+// contextText formats a context estimate, e.g. "~62% full (124.0K of
+// 200.0K tokens)".
+func contextText(u analysis.ContextUsage) string {
+	return fmt.Sprintf("~%.0f%% full (%s of %s tokens)", u.Percent(),
+		display.FormatCompact(u.Tokens), display.FormatCompact(u.Window))
+}
+
+// rateLimitText describes the rate limits of a block, e.g. "3 rate-limit
+// events in the current block, last at 14:05; expect slower responses".
+func rateLimitText(block aggregator.BillingBlock) string {
+	return fmt.Sprintf("%s in the current block, last at %s; expect slower responses",
+		rateLimitCount(block.RateLimits), block.LastRateLimit.Local().Format("15:04"))
+}
+
+// rateLimitCount formats a number of rate-limit events.
+func rateLimitCount(n int) string {
+	if n == 1 {
+		return "1 rate-limit event"
+	}
+	return fmt.Sprintf("%d rate-limit events", n)
+}
+
+// paceStyle colors a burn-down bar green ahead of pace and red over it.
+func paceStyle(pace aggregator.BlockPace) lipgloss.Style {
+	if pace.OverPace() {
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
+	}
+	return lipgloss.NewStyle().Foreground(lipgloss.Color("10"))
+}
+
+// blockShare returns tokens as a percentage of the block total.
+func blockShare(tokens, total int) float64 {
+	if total == 0 {
+		return 0
 	}
+	return float64(tokens) / float64(total) * 100
 }
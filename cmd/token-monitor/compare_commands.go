@@ -9,10 +9,8 @@ import (
 	"strings"
 
 	"github.com/0xmhha/token-monitor/pkg/analysis"
-	"github.com/0xmhha/token-monitor/pkg/config"
 	"github.com/0xmhha/token-monitor/pkg/discovery"
-	"github.com/0xmhha/token-monitor/pkg/logger"
-	"github.com/0xmhha/token-monitor/pkg/parser"
+	"github.com/0xmhha/token-monitor/pkg/display"
 	"github.com/0xmhha/token-monitor/pkg/session"
 )
 
@@ -31,28 +29,22 @@ func (c *sessionCommand) runCompare(args []string) error {
 		return err
 	}
 
-	cfg, log, mgr, err := c.initializeSessionComponents()
+	mgr, err := c.sessionManager()
 	if err != nil {
 		return err
 	}
-	defer func() {
-		if mgr != nil {
-			mgr.Close() //nolint:errcheck,gosec // best-effort cleanup
-		}
-	}()
 
-	disc := discovery.New(cfg.ClaudeConfigDirs, log)
-	discovered, err := disc.Discover()
+	discovered, err := c.discoverSessions()
 	if err != nil {
-		return fmt.Errorf("failed to discover sessions: %w", err)
+		return err
 	}
 
-	analysisA, err := c.loadAndAnalyze(cfg, log, mgr, discovered, opts.identifierA)
+	analysisA, err := c.loadAndAnalyze(mgr, discovered, opts.identifierA)
 	if err != nil {
 		return fmt.Errorf("session A: %w", err)
 	}
 
-	analysisB, err := c.loadAndAnalyze(cfg, log, mgr, discovered, opts.identifierB)
+	analysisB, err := c.loadAndAnalyze(mgr, discovered, opts.identifierB)
 	if err != nil {
 		return fmt.Errorf("session B: %w", err)
 	}
@@ -88,8 +80,6 @@ func (c *sessionCommand) parseCompareOptions(args []string) (*compareOptions, er
 
 // loadAndAnalyze finds, parses, and analyzes a session.
 func (c *sessionCommand) loadAndAnalyze(
-	cfg *config.Config,
-	log logger.Logger,
 	mgr session.Manager,
 	discovered []discovery.SessionFile,
 	identifier string,
@@ -100,7 +90,10 @@ func (c *sessionCommand) loadAndAnalyze(
 		return analysis.SessionAnalysis{}, fmt.Errorf("session file not found: %s", identifier)
 	}
 
-	p := parser.New()
+	p, err := c.rt.Parser()
+	if err != nil {
+		return analysis.SessionAnalysis{}, err
+	}
 	entries, _, err := p.ParseFile(sessionFile.FilePath, 0)
 	if err != nil {
 		return analysis.SessionAnalysis{}, fmt.Errorf("failed to parse: %w", err)
@@ -115,9 +108,6 @@ func (c *sessionCommand) loadAndAnalyze(
 		displayName = metadata.Name
 	}
 
-	_ = cfg
-	_ = log
-
 	return analysis.Analyze(sessionFile.SessionID, displayName, sessionFile.ProjectPath, entries), nil
 }
 
@@ -190,24 +180,28 @@ func (c *sessionCommand) displayCompareReport(a, b analysis.SessionAnalysis, max
 }
 
 func (c *sessionCommand) displayCompareHeader(a, b analysis.SessionAnalysis) {
-	fmt.Println()
-	fmt.Println("Session Comparison Report")
-	fmt.Println(strings.Repeat("═", 70))
-
-	fmt.Println()
-	fmt.Printf("  A: %-20s │ %s\n", a.Label, truncateProjectPath(a.Project, 40))
-	fmt.Printf("  B: %-20s │ %s\n", b.Label, truncateProjectPath(b.Project, 40))
-	fmt.Println()
+	out := c.globalOpts.output()
+
+	out.Println()
+	out.Println("Session Comparison Report")
+	out.Println(strings.Repeat("═", 70))
+
+	out.Println()
+	out.Printf("  A: %-20s │ %s\n", a.Label, truncateProjectPath(a.Project, 40))
+	out.Printf("  B: %-20s │ %s\n", b.Label, truncateProjectPath(b.Project, 40))
+	out.Println()
 }
 
 func (c *sessionCommand) displayTokenComparison(a, b analysis.SessionAnalysis) {
-	fmt.Println("Token Usage")
-	fmt.Println(cmpHeader)
-	fmt.Printf("│ %-24s │ %12s │ %12s │ %12s │\n", "Metric", "Session A", "Session B", "Diff")
-	fmt.Println(cmpSep)
+	out := c.globalOpts.output()
+
+	out.Println("Token Usage")
+	out.Println(cmpHeader)
+	out.Printf("│ %-24s │ %12s │ %12s │ %12s │\n", "Metric", "Session A", "Session B", "Diff")
+	out.Println(cmpSep)
 
 	printRow := func(label string, va, vb int) {
-		fmt.Printf("│ %-24s │ %12s │ %12s │ %12s │\n",
+		out.Printf("│ %-24s │ %12s │ %12s │ %12s │\n",
 			label, fmtNum(va), fmtNum(vb), fmtDiff(va-vb))
 	}
 
@@ -216,28 +210,30 @@ func (c *sessionCommand) displayTokenComparison(a, b analysis.SessionAnalysis) {
 	printRow("Output Tokens", a.OutputTokens, b.OutputTokens)
 	printRow("Cache Creation", a.CacheCreation, b.CacheCreation)
 	printRow("Cache Read", a.CacheRead, b.CacheRead)
-	fmt.Println(cmpSep)
+	out.Println(cmpSep)
 	printRow("Total Tokens", a.TotalTokens, b.TotalTokens)
 	printRow("Real Input", a.RealInput, b.RealInput)
-	fmt.Println(cmpFooter)
+	out.Println(cmpFooter)
 
 	// Duration
-	fmt.Printf("\n  Duration:  A = %s  │  B = %s\n\n",
-		formatDuration(a.Duration), formatDuration(b.Duration))
+	out.Printf("\n  Duration:  A = %s  │  B = %s\n\n",
+		display.FormatDurationLong(a.Duration, 2), display.FormatDurationLong(b.Duration, 2))
 }
 
 func (c *sessionCommand) displayCacheComparison(a, b analysis.SessionAnalysis) {
-	fmt.Println("Cache Efficiency")
-	fmt.Println(cmpHeader2)
-	fmt.Printf("│ %-24s │ %12s │ %12s │\n", "Metric", "Session A", "Session B")
-	fmt.Println(cmpSep2)
+	out := c.globalOpts.output()
+
+	out.Println("Cache Efficiency")
+	out.Println(cmpHeader2)
+	out.Printf("│ %-24s │ %12s │ %12s │\n", "Metric", "Session A", "Session B")
+	out.Println(cmpSep2)
 
-	fmt.Printf("│ %-24s │ %11.1f%% │ %11.1f%% │\n",
+	out.Printf("│ %-24s │ %11.1f%% │ %11.1f%% │\n",
 		"Cache Hit Rate", a.CacheHitRate, b.CacheHitRate)
 
 	cacheA := a.CacheCreation + a.CacheRead
 	cacheB := b.CacheCreation + b.CacheRead
-	fmt.Printf("│ %-24s │ %12s │ %12s │\n",
+	out.Printf("│ %-24s │ %12s │ %12s │\n",
 		"Cache Total", fmtNum(cacheA), fmtNum(cacheB))
 
 	// First turn cache_creation (proxy for system prompt size)
@@ -248,14 +244,16 @@ func (c *sessionCommand) displayCacheComparison(a, b analysis.SessionAnalysis) {
 	if len(b.Turns) > 0 {
 		firstCCB = b.Turns[0].CacheCreation
 	}
-	fmt.Printf("│ %-24s │ %12s │ %12s │\n",
+	out.Printf("│ %-24s │ %12s │ %12s │\n",
 		"1st Turn Cache Create", fmtNum(firstCCA), fmtNum(firstCCB))
 
-	fmt.Println(cmpFooter2)
-	fmt.Println()
+	out.Println(cmpFooter2)
+	out.Println()
 }
 
 func (c *sessionCommand) displayCostComparison(a, b analysis.SessionAnalysis) {
+	out := c.globalOpts.output()
+
 	inA, outA, cwA, crA := analysis.CostBreakdown(a)
 	inB, outB, cwB, crB := analysis.CostBreakdown(b)
 
@@ -264,13 +262,13 @@ func (c *sessionCommand) displayCostComparison(a, b analysis.SessionAnalysis) {
 		costLabel = "Cost (from API)"
 	}
 
-	fmt.Println(costLabel)
-	fmt.Println(cmpHeader)
-	fmt.Printf("│ %-24s │ %12s │ %12s │ %12s │\n", "Component", "Session A", "Session B", "Diff")
-	fmt.Println(cmpSep)
+	out.Println(costLabel)
+	out.Println(cmpHeader)
+	out.Printf("│ %-24s │ %12s │ %12s │ %12s │\n", "Component", "Session A", "Session B", "Diff")
+	out.Println(cmpSep)
 
 	printCostRow := func(label string, va, vb float64) {
-		fmt.Printf("│ %-24s │ %11s │ %11s │ %11s │\n",
+		out.Printf("│ %-24s │ %11s │ %11s │ %11s │\n",
 			label, fmtUSD(va), fmtUSD(vb), fmtUSDDiff(va-vb))
 	}
 
@@ -279,43 +277,47 @@ func (c *sessionCommand) displayCostComparison(a, b analysis.SessionAnalysis) {
 		printCostRow("Output", outA, outB)
 		printCostRow("Cache Write", cwA, cwB)
 		printCostRow("Cache Read", crA, crB)
-		fmt.Println(cmpSep)
+		out.Println(cmpSep)
 	}
 
 	printCostRow("Total", a.CostUSD, b.CostUSD)
-	fmt.Println(cmpFooter)
+	out.Println(cmpFooter)
 
 	// Show dominant model for pricing context
-	fmt.Printf("  Pricing: A=%s  │  B=%s\n\n", dominantModel(a), dominantModel(b))
+	out.Printf("  Pricing: A=%s  │  B=%s\n\n", dominantModel(a), dominantModel(b))
 }
 
 func (c *sessionCommand) displayToolComparison(a, b analysis.SessionAnalysis) {
+	out := c.globalOpts.output()
+
 	allTools := mergeToolKeys(a.ToolUsage, b.ToolUsage)
 	if len(allTools) == 0 {
 		return
 	}
 
-	fmt.Println("Tool Usage")
-	fmt.Println(cmpHeader)
-	fmt.Printf("│ %-24s │ %12s │ %12s │ %12s │\n", "Tool", "Session A", "Session B", "Diff")
-	fmt.Println(cmpSep)
+	out.Println("Tool Usage")
+	out.Println(cmpHeader)
+	out.Printf("│ %-24s │ %12s │ %12s │ %12s │\n", "Tool", "Session A", "Session B", "Diff")
+	out.Println(cmpSep)
 
 	for _, tool := range allTools {
 		va := a.ToolUsage[tool]
 		vb := b.ToolUsage[tool]
-		fmt.Printf("│ %-24s │ %12s │ %12s │ %12s │\n",
+		out.Printf("│ %-24s │ %12s │ %12s │ %12s │\n",
 			truncStr(tool, 24), fmtNum(va), fmtNum(vb), fmtDiff(va-vb))
 	}
 
 	totalA, totalB := sumMap(a.ToolUsage), sumMap(b.ToolUsage)
-	fmt.Println(cmpSep)
-	fmt.Printf("│ %-24s │ %12s │ %12s │ %12s │\n",
+	out.Println(cmpSep)
+	out.Printf("│ %-24s │ %12s │ %12s │ %12s │\n",
 		"Total", fmtNum(totalA), fmtNum(totalB), fmtDiff(totalA-totalB))
-	fmt.Println(cmpFooter)
-	fmt.Println()
+	out.Println(cmpFooter)
+	out.Println()
 }
 
 func (c *sessionCommand) displayTurnComparison(a, b analysis.SessionAnalysis, maxTurns int) {
+	out := c.globalOpts.output()
+
 	maxLen := max(len(a.Turns), len(b.Turns))
 	if maxLen == 0 {
 		return
@@ -323,11 +325,11 @@ func (c *sessionCommand) displayTurnComparison(a, b analysis.SessionAnalysis, ma
 
 	shown := min(maxTurns, maxLen)
 
-	fmt.Printf("Turn-by-Turn (first %d of %d)\n", shown, maxLen)
-	fmt.Println(cmpTurnHeader)
-	fmt.Printf("│ %4s │ %12s │ %12s │ %12s │ %-24s │\n",
+	out.Printf("Turn-by-Turn (first %d of %d)\n", shown, maxLen)
+	out.Println(cmpTurnHeader)
+	out.Printf("│ %4s │ %12s │ %12s │ %12s │ %-24s │\n",
 		"Turn", "A Tokens", "B Tokens", "Diff", "A Tools")
-	fmt.Println(cmpTurnSep)
+	out.Println(cmpTurnSep)
 
 	for i := 0; i < shown; i++ {
 		var va, vb int
@@ -343,16 +345,16 @@ func (c *sessionCommand) displayTurnComparison(a, b analysis.SessionAnalysis, ma
 			vb = b.Turns[i].TotalTokens
 		}
 
-		fmt.Printf("│ %4d │ %12s │ %12s │ %12s │ %-24s │\n",
+		out.Printf("│ %4d │ %12s │ %12s │ %12s │ %-24s │\n",
 			i+1, fmtNum(va), fmtNum(vb), fmtDiff(va-vb), toolDesc)
 	}
 
-	fmt.Println(cmpTurnFooter)
+	out.Println(cmpTurnFooter)
 
 	if maxLen > shown {
-		fmt.Printf("  ... %d more turns not shown (use -turns %d to see all)\n", maxLen-shown, maxLen)
+		out.Printf("  ... %d more turns not shown (use -turns %d to see all)\n", maxLen-shown, maxLen)
 	}
-	fmt.Println()
+	out.Println()
 }
 
 // ──────────────────────────────────────────────────────────────────────
@@ -4,12 +4,14 @@ import (
 	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
-	"time"
 
 	"github.com/0xmhha/token-monitor/pkg/config"
+	"github.com/0xmhha/token-monitor/pkg/display"
+	"github.com/0xmhha/token-monitor/pkg/i18n"
 	"gopkg.in/yaml.v3"
 )
 
@@ -38,7 +40,11 @@ func (c *configCommand) Execute(args []string) error {
 	case "set":
 		return c.runSet(subargs)
 	case "validate":
-		return c.runValidate()
+		return c.runValidate(subargs)
+	case "export-rules":
+		return c.runExportRules(subargs)
+	case "import-rules":
+		return c.runImportRules(subargs)
 	case "help":
 		return c.showHelp()
 	default:
@@ -55,16 +61,17 @@ func (c *configCommand) runShow(args []string) error {
 		return err
 	}
 
-	cfg, err := config.Load()
+	cfg, err := c.globalOpts.newRuntime("").Config()
 	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
+		return err
 	}
 
+	// Plaintext secrets are redacted; references are shown as written.
 	switch *format {
 	case "json":
-		return c.showJSON(cfg)
+		return c.showJSON(cfg.Redacted())
 	default:
-		return c.showYAML(cfg)
+		return c.showYAML(cfg.Redacted())
 	}
 }
 
@@ -134,20 +141,21 @@ func (c *configCommand) runReset(args []string) error {
 	}
 
 	// Check if file exists
+	out := c.globalOpts.output()
 	if _, err := os.Stat(outputPath); err == nil && !*force {
-		fmt.Printf("Configuration file already exists at: %s\n", outputPath)
-		fmt.Print("Overwrite? [y/N]: ")
+		out.Warnf("Configuration file already exists at: %s\n", outputPath)
+		out.Warnf("Overwrite? [y/N]: ")
 
 		var response string
 		if _, err := fmt.Scanln(&response); err != nil {
 			// If Scanln fails, treat as "no"
-			fmt.Println("\nReset cancelled.")
+			out.Warnf("\nReset cancelled.\n")
 			return nil
 		}
 		response = strings.ToLower(strings.TrimSpace(response))
 
 		if response != "y" && response != "yes" {
-			fmt.Println("Reset cancelled.")
+			out.Warnf("Reset cancelled.\n")
 			return nil
 		}
 	}
@@ -172,245 +180,324 @@ func (c *configCommand) runReset(args []string) error {
 		return fmt.Errorf("failed to write config file: %w", err)
 	}
 
-	fmt.Printf("Configuration reset to defaults at: %s\n", outputPath)
+	c.globalOpts.infof("Configuration reset to defaults at: %s\n", outputPath)
 	return nil
 }
 
-// runSet updates a configuration value.
+// runSet updates a setting in the config file. The file is edited in
+// place, so its comments and layout are kept.
 func (c *configCommand) runSet(args []string) error {
-	if len(args) < 2 {
-		return fmt.Errorf("usage: token-monitor config set <key> <value>")
+	key, op, value, err := parseSetArgs(args)
+	if err != nil {
+		return err
 	}
 
-	key := args[0]
-	value := args[1]
-
 	// Determine config file path
-	configPath := c.getConfigSource()
+	configPath := c.globalOpts.configPath
+	if configPath == "" {
+		configPath = c.getConfigSource()
+	}
 	if configPath == "defaults (no config file found)" {
 		configPath = filepath.Join(os.Getenv("HOME"), ".config", "token-monitor", "config.yaml")
 	}
 
-	// Load current configuration
-	cfg, err := config.Load()
-	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
+	data, err := os.ReadFile(configPath) // nolint:gosec
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read config: %w", err)
 	}
 
-	// Update the configuration value
-	if err := c.setConfigValue(cfg, key, value); err != nil {
+	updated, err := config.Edit(data, key, op, value)
+	if err != nil {
 		return err
 	}
 
 	// Validate the updated configuration
-	if err := cfg.Validate(); err != nil {
+	if _, err := config.Parse(updated); err != nil {
 		return fmt.Errorf("invalid configuration after update: %w", err)
 	}
 
 	// Save to file
-	if err := config.Save(cfg, configPath); err != nil {
-		return fmt.Errorf("failed to save config: %w", err)
+	if err := os.MkdirAll(filepath.Dir(configPath), 0700); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := os.WriteFile(configPath, updated, 0600); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
 	}
 
-	fmt.Printf("✓ Set %s = %s\n", key, value)
-	fmt.Printf("✓ Configuration saved to: %s\n", configPath)
+	switch op {
+	case config.EditAppend:
+		c.globalOpts.infof("%s\n", i18n.Tf("msg.config_added", key, value))
+	case config.EditRemove:
+		c.globalOpts.infof("%s\n", i18n.Tf("msg.config_removed", key, value))
+	default:
+		c.globalOpts.infof("%s\n", i18n.Tf("msg.config_set", key, value))
+	}
+	c.globalOpts.infof("%s\n", i18n.Tf("msg.config_saved", configPath))
 	return nil
 }
 
+// parseSetArgs splits config set arguments into the key, the edit, and the
+// value. The value follows the key as a separate argument or after =, +=
+// (append to a list), or -= (remove from a list or map).
+func parseSetArgs(args []string) (string, config.EditOp, string, error) {
+	const usage = "usage: token-monitor config set <key> <value> | <key>=<value> | <key>+=<value> | <key>-=<value>"
+
+	switch len(args) {
+	case 2:
+		return args[0], config.EditSet, args[1], nil
+	case 1:
+		key, value, ok := strings.Cut(args[0], "=")
+		if !ok || key == "" {
+			return "", 0, "", fmt.Errorf(usage)
+		}
+		switch {
+		case strings.HasSuffix(key, "+"):
+			return strings.TrimSuffix(key, "+"), config.EditAppend, value, nil
+		case strings.HasSuffix(key, "-"):
+			return strings.TrimSuffix(key, "-"), config.EditRemove, value, nil
+		}
+		return key, config.EditSet, value, nil
+	default:
+		return "", 0, "", fmt.Errorf(usage)
+	}
+}
+
 // runValidate validates the current configuration.
-func (c *configCommand) runValidate() error {
-	cfg, err := config.Load()
+func (c *configCommand) runValidate(args []string) error {
+	fs := flag.NewFlagSet("config validate", flag.ExitOnError)
+	jsonOut := fs.Bool("json", false, "print the validation errors as JSON")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *jsonOut || c.globalOpts.jsonOutput {
+		return c.validateJSON()
+	}
+
+	out := c.globalOpts.output()
+
+	cfg, err := config.NewLoader(c.globalOpts.configPath).LoadUnvalidated()
 	if err != nil {
-		fmt.Println("✗ Configuration validation failed:")
-		fmt.Printf("  Error: %v\n", err)
+		out.Warnf("✗ Configuration validation failed:\n")
+		out.Warnf("  Error: %v\n", err)
 		return err
 	}
+	c.printUnknownKeys(out, cfg.UnknownKeys())
 
 	if err := cfg.Validate(); err != nil {
-		fmt.Println("✗ Configuration validation failed:")
-		fmt.Printf("  Error: %v\n", err)
-		fmt.Println()
-		fmt.Println("Suggestions:")
-		c.printValidationSuggestions(err)
+		out.Warnf("✗ Configuration validation failed:\n")
+		out.Warnf("  Error: %v\n", err)
+		out.Warnf("\nSuggestions:\n")
+		c.printValidationSuggestions(out, err)
 		return err
 	}
 
-	fmt.Println("✓ Configuration is valid")
-	fmt.Println()
-	fmt.Println("Configuration summary:")
-	fmt.Printf("  Claude directories: %d configured\n", len(cfg.ClaudeConfigDirs))
-	fmt.Printf("  Watch interval: %v\n", cfg.Monitoring.WatchInterval)
-	fmt.Printf("  Log level: %s\n", cfg.Logging.Level)
-	fmt.Printf("  Database path: %s\n", cfg.Storage.DBPath)
+	out.Println("✓ Configuration is valid")
+	out.Println()
+	out.Println("Configuration summary:")
+	out.Printf("  Claude directories: %d configured\n", len(cfg.ClaudeConfigDirs))
+	out.Printf("  Watch interval: %v\n", cfg.Monitoring.WatchInterval)
+	out.Printf("  Log level: %s\n", cfg.Logging.Level)
+	out.Printf("  Database path: %s\n", cfg.Storage.DBPath)
 	return nil
 }
 
-// setConfigValue updates a configuration value by key.
-func (c *configCommand) setConfigValue(cfg *config.Config, key, value string) error {
-	parts := strings.Split(key, ".")
-	if len(parts) != 2 {
-		return fmt.Errorf("invalid key format: %s (expected format: section.field)", key)
-	}
-
-	section := parts[0]
-	field := parts[1]
-
-	switch section {
-	case "logging":
-		return c.setLoggingValue(cfg, field, value)
-	case "monitoring":
-		return c.setMonitoringValue(cfg, field, value)
-	case "performance":
-		return c.setPerformanceValue(cfg, field, value)
-	case "display":
-		return c.setDisplayValue(cfg, field, value)
-	case "storage":
-		return c.setStorageValue(cfg, field, value)
-	default:
-		return fmt.Errorf("unknown config section: %s", section)
+// printUnknownKeys warns about keys of the config file that are not
+// settings. They do not make the configuration invalid.
+func (c *configCommand) printUnknownKeys(out display.Output, keys []config.UnknownKey) {
+	if len(keys) == 0 {
+		return
+	}
+	out.Warnf("⚠ Unknown keys (ignored):\n")
+	for _, key := range keys {
+		if key.Suggestion != "" {
+			out.Warnf("  - %s (line %d): did you mean %s?\n", key.Key, key.Line, key.Suggestion)
+		} else {
+			out.Warnf("  - %s (line %d)\n", key.Key, key.Line)
+		}
 	}
+	out.Warnf("\n")
 }
 
-// setLoggingValue updates a logging configuration value.
-func (c *configCommand) setLoggingValue(cfg *config.Config, field, value string) error {
-	switch field {
-	case "level":
-		validLevels := []string{"debug", "info", "warn", "error"}
-		if !contains(validLevels, value) {
-			return fmt.Errorf("invalid log level: %s (must be one of: %s)", value, strings.Join(validLevels, ", "))
-		}
-		cfg.Logging.Level = value
-	case "format":
-		validFormats := []string{"text", "json"}
-		if !contains(validFormats, value) {
-			return fmt.Errorf("invalid log format: %s (must be one of: %s)", value, strings.Join(validFormats, ", "))
-		}
-		cfg.Logging.Format = value
-	case "output":
-		cfg.Logging.Output = value
-	default:
-		return fmt.Errorf("unknown logging field: %s", field)
-	}
-	return nil
+// configValidation is the JSON document for config validate -json.
+// Unknown keys are reported but do not make the configuration invalid.
+type configValidation struct {
+	Valid       bool                `json:"valid"`
+	Source      string              `json:"source"`
+	Errors      []configProblem     `json:"errors"`
+	UnknownKeys []config.UnknownKey `json:"unknown_keys"`
 }
 
-// setMonitoringValue updates a monitoring configuration value.
-func (c *configCommand) setMonitoringValue(cfg *config.Config, field, value string) error {
-	switch field {
-	case "watch_interval":
-		duration, err := parseDuration(value)
-		if err != nil {
-			return fmt.Errorf("invalid watch_interval: %w", err)
-		}
-		cfg.Monitoring.WatchInterval = duration
-	case "update_frequency":
-		duration, err := parseDuration(value)
-		if err != nil {
-			return fmt.Errorf("invalid update_frequency: %w", err)
-		}
-		cfg.Monitoring.UpdateFrequency = duration
-	case "session_retention":
-		duration, err := parseDuration(value)
-		if err != nil {
-			return fmt.Errorf("invalid session_retention: %w", err)
+// configProblem is one validation error. Key is empty for errors that
+// are not about a single setting, such as malformed YAML.
+type configProblem struct {
+	Code string `json:"code"`
+	config.Problem
+}
+
+// validateJSON prints every validation error with its key path and a
+// suggested value. Like the text form, it fails if the configuration is
+// invalid.
+func (c *configCommand) validateJSON() error {
+	doc := configValidation{
+		Source:      c.globalOpts.configPath,
+		Errors:      []configProblem{},
+		UnknownKeys: []config.UnknownKey{},
+	}
+	if doc.Source == "" {
+		doc.Source = c.getConfigSource()
+	}
+
+	cfg, err := config.NewLoader(c.globalOpts.configPath).LoadUnvalidated()
+	if err != nil {
+		doc.Errors = append(doc.Errors, configProblem{
+			Code:    errorCode(err),
+			Problem: config.Problem{Message: err.Error(), Err: err},
+		})
+	} else {
+		doc.UnknownKeys = append(doc.UnknownKeys, cfg.UnknownKeys()...)
+		for _, problem := range cfg.Problems() {
+			doc.Errors = append(doc.Errors, configProblem{Code: errorCode(problem.Err), Problem: problem})
 		}
-		cfg.Monitoring.SessionRetention = duration
-	default:
-		return fmt.Errorf("unknown monitoring field: %s", field)
+	}
+	doc.Valid = len(doc.Errors) == 0
+
+	if err := printJSON(doc); err != nil {
+		return err
+	}
+	if !doc.Valid {
+		return doc.Errors[0].Err
 	}
 	return nil
 }
 
-// setPerformanceValue updates a performance configuration value.
-func (c *configCommand) setPerformanceValue(cfg *config.Config, field, value string) error {
-	switch field {
-	case "worker_pool_size":
-		size, err := parseInt(value)
-		if err != nil {
-			return fmt.Errorf("invalid worker_pool_size: %w", err)
-		}
-		cfg.Performance.WorkerPoolSize = size
-	case "cache_size":
-		size, err := parseInt(value)
-		if err != nil {
-			return fmt.Errorf("invalid cache_size: %w", err)
-		}
-		cfg.Performance.CacheSize = size
-	case "batch_window":
-		duration, err := parseDuration(value)
-		if err != nil {
-			return fmt.Errorf("invalid batch_window: %w", err)
-		}
-		cfg.Performance.BatchWindow = duration
-	default:
-		return fmt.Errorf("unknown performance field: %s", field)
+// runExportRules writes the shareable rules (flag defaults and aliases)
+// as a YAML snippet for config import-rules.
+func (c *configCommand) runExportRules(args []string) error {
+	fs := flag.NewFlagSet("config export-rules", flag.ExitOnError)
+	output := fs.String("output", "", "write the snippet to a file instead of stdout")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := c.globalOpts.newRuntime("").Config()
+	if err != nil {
+		return err
 	}
+
+	data, err := yaml.Marshal(cfg.ExportRules())
+	if err != nil {
+		return fmt.Errorf("failed to marshal rules: %w", err)
+	}
+	data = append([]byte("# token-monitor rules; apply with: token-monitor config import-rules <file>\n"), data...)
+
+	if *output == "" {
+		fmt.Print(string(data))
+		return nil
+	}
+	if err := os.WriteFile(*output, data, 0600); err != nil {
+		return fmt.Errorf("failed to write rules: %w", err)
+	}
+	c.globalOpts.infof("Rules exported to: %s\n", *output)
 	return nil
 }
 
-// setDisplayValue updates a display configuration value.
-func (c *configCommand) setDisplayValue(cfg *config.Config, field, value string) error {
-	switch field {
-	case "default_mode":
-		validModes := []string{"live", "compact", "table", "json"}
-		if !contains(validModes, value) {
-			return fmt.Errorf("invalid default_mode: %s (must be one of: %s)", value, strings.Join(validModes, ", "))
-		}
-		cfg.Display.DefaultMode = value
-	case "color_enabled":
-		enabled, err := parseBool(value)
-		if err != nil {
-			return fmt.Errorf("invalid color_enabled: %w", err)
+// runImportRules merges a rules snippet into the configuration file.
+func (c *configCommand) runImportRules(args []string) error {
+	fs := flag.NewFlagSet("config import-rules", flag.ExitOnError)
+	replace := fs.Bool("replace", false, "remove flag defaults and aliases missing from the file")
+	dryRun := fs.Bool("dry-run", false, "show the changes without saving them")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: token-monitor config import-rules [-replace] [-dry-run] <file|->")
+	}
+
+	var data []byte
+	var err error
+	if path := fs.Arg(0); path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read rules: %w", err)
+	}
+
+	rules, err := config.ParseRules(data)
+	if err != nil {
+		return err
+	}
+	for name := range rules.Aliases {
+		if builtinCommands[name] {
+			return fmt.Errorf("%w: alias %q shadows a built-in command", config.ErrInvalidRules, name)
 		}
-		cfg.Display.ColorEnabled = enabled
-	case "refresh_rate":
-		duration, err := parseDuration(value)
-		if err != nil {
-			return fmt.Errorf("invalid refresh_rate: %w", err)
+	}
+
+	configPath := c.getConfigSource()
+	if configPath == "defaults (no config file found)" {
+		configPath = filepath.Join(os.Getenv("HOME"), ".config", "token-monitor", "config.yaml")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	changes := cfg.ImportRules(rules, *replace)
+
+	out := c.globalOpts.output()
+	if len(changes) == 0 {
+		out.Println("No changes: the rules are already in place")
+		return nil
+	}
+	for _, change := range changes {
+		switch {
+		case change.Old == "":
+			out.Printf("  + %s: %s\n", change.Key, change.New)
+		case change.New == "":
+			out.Printf("  - %s: %s\n", change.Key, change.Old)
+		default:
+			out.Printf("  ~ %s: %s -> %s\n", change.Key, change.Old, change.New)
 		}
-		cfg.Display.RefreshRate = duration
-	default:
-		return fmt.Errorf("unknown display field: %s", field)
 	}
-	return nil
-}
 
-// setStorageValue updates a storage configuration value.
-func (c *configCommand) setStorageValue(cfg *config.Config, field, value string) error {
-	switch field {
-	case "db_path":
-		cfg.Storage.DBPath = value
-	case "cache_dir":
-		cfg.Storage.CacheDir = value
-	default:
-		return fmt.Errorf("unknown storage field: %s", field)
+	if *dryRun {
+		c.globalOpts.infof("Dry run: %d change(s) not saved\n", len(changes))
+		return nil
+	}
+	if err := config.Save(cfg, configPath); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
 	}
+	c.globalOpts.infof("%s\n", i18n.Tf("msg.config_saved", configPath))
 	return nil
 }
 
 // printValidationSuggestions prints helpful suggestions based on validation errors.
-func (c *configCommand) printValidationSuggestions(err error) {
+func (c *configCommand) printValidationSuggestions(out display.Output, err error) {
 	errStr := err.Error()
 
 	switch {
 	case strings.Contains(errStr, "log level"):
-		fmt.Println("  - Valid log levels: debug, info, warn, error")
-		fmt.Println("  - Example: token-monitor config set logging.level info")
+		out.Warnf("  - Valid log levels: debug, info, warn, error\n")
+		out.Warnf("  - Example: token-monitor config set logging.level info\n")
 	case strings.Contains(errStr, "log format"):
-		fmt.Println("  - Valid log formats: text, json")
-		fmt.Println("  - Example: token-monitor config set logging.format text")
+		out.Warnf("  - Valid log formats: text, json\n")
+		out.Warnf("  - Example: token-monitor config set logging.format text\n")
 	case strings.Contains(errStr, "display mode"):
-		fmt.Println("  - Valid display modes: live, compact, table, json")
-		fmt.Println("  - Example: token-monitor config set display.default_mode live")
+		out.Warnf("  - Valid display modes: live, compact, table, json\n")
+		out.Warnf("  - Example: token-monitor config set display.default_mode live\n")
 	case strings.Contains(errStr, "watch interval"):
-		fmt.Println("  - Watch interval must be greater than 0")
-		fmt.Println("  - Example: token-monitor config set monitoring.watch_interval 1s")
+		out.Warnf("  - Watch interval must be greater than 0\n")
+		out.Warnf("  - Example: token-monitor config set monitoring.watch_interval 1s\n")
 	case strings.Contains(errStr, "worker pool"):
-		fmt.Println("  - Worker pool size must be greater than 0")
-		fmt.Println("  - Example: token-monitor config set performance.worker_pool_size 5")
+		out.Warnf("  - Worker pool size must be greater than 0\n")
+		out.Warnf("  - Example: token-monitor config set performance.worker_pool_size 5\n")
 	default:
-		fmt.Println("  - Run 'token-monitor config show' to see current configuration")
-		fmt.Println("  - Run 'token-monitor config reset' to restore defaults")
+		out.Warnf("  - Run 'token-monitor config show' to see current configuration\n")
+		out.Warnf("  - Run 'token-monitor config reset' to restore defaults\n")
 	}
 }
 
@@ -444,32 +531,89 @@ Subcommands:
   reset         Reset configuration to defaults
   set           Set a configuration value
   validate      Validate current configuration
+  export-rules  Print flag defaults and aliases as a shareable YAML snippet
+  import-rules  Merge a rules snippet into the configuration file
 
 Show Flags:
   -format       Output format (yaml, json) (default: yaml)
 
+Validate Flags:
+  -json         Print every error with its key path and a suggested value
+
+  Keys that are not settings, usually typos, are listed as warnings with
+  the closest setting; they are also logged whenever the config is loaded.
+
 Reset Flags:
   -force        Skip confirmation prompt
   -output       Output path for config file
 
+Export Rules Flags:
+  -output       Write the snippet to a file instead of stdout
+
+Import Rules Usage:
+  token-monitor config import-rules [-replace] [-dry-run] <file|->
+
+  -replace      Also remove flag defaults and aliases missing from the file
+  -dry-run      Show the changes without saving them
+
+  Rules are the defaults and aliases sections, e.g. a team-wide watch
+  profile:
+    defaults:
+      watch: {format: simple, eco: true}
+    aliases:
+      office: "watch -format table -refresh 5s"
+
 Set Usage:
   token-monitor config set <key> <value>
-
-  Supported keys:
+  token-monitor config set <key>=<value>
+  token-monitor config set <key>+=<item>    Append to a list
+  token-monitor config set <key>-=<item>    Remove from a list or map
+
+  Keys are dotted YAML paths into the config file, at any depth (e.g.
+  defaults.watch.format, notify.channels.0.events); list items are
+  addressed by index. Values are YAML, so list items may be mappings
+  (notify.channels+='{name: ops, type: discord}'), and are removed by
+  value or by their path or name. The file is edited in place, keeping
+  its comments.
+
+  Common keys:
     logging.level           Log level (debug, info, warn, error)
     logging.format          Log format (text, json)
     logging.output          Log output (stdout, stderr, or file path)
     monitoring.watch_interval        Watch interval (e.g., 1s, 500ms)
     monitoring.update_frequency      Update frequency (e.g., 1s)
     monitoring.session_retention     Session retention (e.g., 720h)
+    monitoring.max_poll_interval     Idle re-read backoff limit (e.g., 30s)
+    monitoring.block_token_limit     Token quota per billing block (0: busiest earlier block)
+    monitoring.context_window        Context window size in tokens (0: 200000)
+    monitoring.no_input              Watch without keyboard raw mode (true, false)
     performance.worker_pool_size     Worker pool size (integer > 0)
     performance.cache_size           Cache size (integer > 0)
     performance.batch_window         Batch window (e.g., 100ms)
+    performance.read_retry_delay     Re-read delay for writes with no entries (e.g., 250ms, 0 = off)
+    performance.read_retries         Re-reads before giving up (integer >= 0)
+    performance.low_power            Low-power watch and tui (true, false)
+    performance.max_file_size_mb     Largest session file read, in MB (integer > 0)
+    performance.max_entries_per_read Entries parsed per file read (0 = no limit)
     display.default_mode             Display mode (live, compact, table, json)
     display.color_enabled            Color enabled (true, false)
     display.refresh_rate             Refresh rate (e.g., 1s)
+    display.locale                   Output language (auto, en, ko)
+    display.accessible               Screen-reader friendly output (true, false)
+    display.ascii                    ASCII-only borders, no emoji (true, false)
+    display.units                    Primary table measure (tokens, k, cost)
     storage.db_path                  Database file path
     storage.cache_dir                Cache directory path
+    retention.rollup_days            Days of rollups kept (0 = forever)
+    retention.baseline_days          Days a baseline is kept (0 = forever)
+    retention.focus_days             Days a closed focus window is kept (0 = forever)
+    session.name_validation          Session name rules (strict, relaxed)
+    session.name_normalization       Session name rewriting (trim, lower, none)
+    session.idle_after               Quiet period before a session is idle (e.g., 30m)
+    session.archive_after            Quiet period before a session is archived (e.g., 168h)
+    discovery.any_jsonl              Include .jsonl files not named by a session ID (true, false)
+    discovery.missing_session_id     Entries without a sessionId (drop, filename)
+    discovery.session_attribution    Sub-agent entries count toward (embedded, file)
 
 Examples:
   # Show current configuration
@@ -490,6 +634,12 @@ Examples:
   # Set worker pool size to 10
   token-monitor config set performance.worker_pool_size 10
 
+  # Monitor another Claude directory
+  token-monitor config set claude_config_dirs+=~/work/.claude/projects
+
+  # Stop monitoring it
+  token-monitor config set claude_config_dirs-=~/work/.claude/projects
+
   # Validate current configuration
   token-monitor config validate
 
@@ -498,49 +648,11 @@ Examples:
 
   # Reset without confirmation
   token-monitor config reset -force
+
+  # Share monitoring settings with a teammate
+  token-monitor config export-rules -output team-rules.yaml
+  token-monitor config import-rules team-rules.yaml
 `
 	fmt.Print(help)
 	return nil
 }
-
-// Helper functions
-
-// contains checks if a slice contains a string.
-func contains(slice []string, item string) bool {
-	for _, s := range slice {
-		if s == item {
-			return true
-		}
-	}
-	return false
-}
-
-// parseDuration parses a duration string.
-func parseDuration(s string) (time.Duration, error) {
-	duration, err := time.ParseDuration(s)
-	if err != nil {
-		return 0, fmt.Errorf("invalid duration format: %s (examples: 1s, 500ms, 1h)", s)
-	}
-	return duration, nil
-}
-
-// parseInt parses an integer string.
-func parseInt(s string) (int, error) {
-	var n int
-	if _, err := fmt.Sscanf(s, "%d", &n); err != nil {
-		return 0, fmt.Errorf("invalid integer: %s", s)
-	}
-	return n, nil
-}
-
-// parseBool parses a boolean string.
-func parseBool(s string) (bool, error) {
-	switch strings.ToLower(s) {
-	case "true", "yes", "1", "on":
-		return true, nil
-	case "false", "no", "0", "off":
-		return false, nil
-	default:
-		return false, fmt.Errorf("invalid boolean: %s (use true/false, yes/no, 1/0, or on/off)", s)
-	}
-}
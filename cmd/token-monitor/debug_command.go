@@ -0,0 +1,416 @@
+package main
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	goruntime "runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/0xmhha/token-monitor/cmd/internal/runtime"
+	"github.com/0xmhha/token-monitor/pkg/config"
+	"github.com/0xmhha/token-monitor/pkg/discovery"
+	"github.com/0xmhha/token-monitor/pkg/i18n"
+	"github.com/0xmhha/token-monitor/pkg/parser"
+	"github.com/0xmhha/token-monitor/pkg/sanitize"
+	"github.com/0xmhha/token-monitor/pkg/session"
+)
+
+// Debug bundle limits.
+const (
+	// defaultBundleSamples is the default number of failing lines collected.
+	defaultBundleSamples = 20
+
+	// defaultBundleLogBytes is how much of the end of the log file is kept.
+	defaultBundleLogBytes = 256 * 1024
+
+	// maxBundleScanFiles bounds how many session files are scanned for
+	// failing lines, newest first.
+	maxBundleScanFiles = 50
+)
+
+// bundleFile is one file in the debug bundle.
+type bundleFile struct {
+	name string
+	data []byte
+}
+
+// failingLine is a session line that could not be used, with its content
+// sanitized.
+type failingLine struct {
+	Session string `json:"session"`
+	Line    int    `json:"line"`
+	Error   string `json:"error"`
+	Data    string `json:"data,omitempty"`
+}
+
+// debugCommand handles diagnostic subcommands.
+type debugCommand struct {
+	globalOpts globalOptions
+}
+
+// runDebugCommand runs the debug command.
+func runDebugCommand(globalOpts globalOptions, args []string) error {
+	cmd := &debugCommand{globalOpts: globalOpts}
+	return cmd.Execute(args)
+}
+
+// Execute dispatches a debug subcommand.
+func (c *debugCommand) Execute(args []string) error {
+	if len(args) == 0 {
+		return c.showHelp()
+	}
+
+	switch args[0] {
+	case "bundle":
+		return c.runBundle(args[1:])
+	case "help":
+		return c.showHelp()
+	default:
+		return fmt.Errorf("unknown debug subcommand: %s", args[0])
+	}
+}
+
+// showHelp displays help for the debug command.
+func (c *debugCommand) showHelp() error {
+	help := `Debug - Diagnostics for bug reports
+
+Usage:
+  token-monitor debug bundle [flags]
+
+Subcommands:
+  bundle        Write a tarball to attach to an issue: version and platform,
+                redacted config, health checks, the end of the log file,
+                and sanitized samples of session lines that failed to parse
+
+Bundle Flags:
+  -output       Output file (default: token-monitor-debug-<time>.tar.gz)
+  -samples      Maximum failing session lines to include (default: 20)
+  -log-bytes    Bytes from the end of the log file to include (default: 262144)
+
+Session lines keep their structure, token counts, models, and timestamps;
+prompts, file contents, paths, and IDs are replaced. Review the bundle
+before sharing it.
+`
+	fmt.Print(help)
+	return nil
+}
+
+// runBundle collects diagnostics into a gzipped tarball.
+func (c *debugCommand) runBundle(args []string) error {
+	fs := flag.NewFlagSet("debug bundle", flag.ExitOnError)
+	output := fs.String("output", "", "output file (default: token-monitor-debug-<time>.tar.gz)")
+	samples := fs.Int("samples", defaultBundleSamples, "maximum failing session lines to include")
+	logBytes := fs.Int64("log-bytes", defaultBundleLogBytes, "bytes from the end of the log file to include")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	path := *output
+	if path == "" {
+		path = fmt.Sprintf("token-monitor-debug-%s.tar.gz", now.Format("20060102-150405"))
+	}
+
+	rt := c.globalOpts.newRuntime("error")
+	defer func() {
+		_ = rt.Close() //nolint:errcheck // best effort cleanup
+	}()
+
+	files := []bundleFile{{name: "version.txt", data: bundleVersion()}}
+
+	// A broken config is often the bug being reported, so it is recorded
+	// rather than aborting the bundle.
+	cfg, cfgErr := rt.Config()
+	if cfgErr != nil {
+		cfg = config.Default()
+	}
+
+	cfgData, err := bundleConfig(cfg)
+	if err != nil {
+		return err
+	}
+	files = append(files, bundleFile{name: "config.yaml", data: cfgData})
+
+	var sessions []discovery.SessionFile
+	if cfgErr == nil {
+		disc, discErr := rt.Discoverer()
+		if discErr == nil {
+			sessions, _ = disc.Discover() //nolint:errcheck // reported by the checks below
+		}
+	}
+
+	files = append(files, bundleFile{name: "checks.txt", data: bundleChecks(rt, cfg, cfgErr, sessions)})
+
+	if logData := bundleLog(cfg, *logBytes); logData != nil {
+		files = append(files, bundleFile{name: "log.txt", data: logData})
+	}
+
+	lines, err := collectFailingLines(sessions, *samples)
+	if err != nil {
+		return err
+	}
+	files = append(files, bundleFile{name: "failing-lines.jsonl", data: lines})
+
+	if err := writeBundle(path, files, now); err != nil {
+		return err
+	}
+
+	fmt.Println(path)
+	c.globalOpts.infof("%s\n", i18n.T("msg.review_bundle"))
+	return nil
+}
+
+// bundleVersion describes the build and platform.
+func bundleVersion() []byte {
+	var b bytes.Buffer
+	_ = currentBuild().writeText(&b) //nolint:errcheck // bytes.Buffer writes do not fail
+	fmt.Fprintf(&b, "cpus: %d\n", goruntime.NumCPU())
+	return b.Bytes()
+}
+
+// bundleConfig returns the effective configuration with credentials and
+// the home directory redacted.
+func bundleConfig(cfg *config.Config) ([]byte, error) {
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal config: %w", err)
+	}
+	return sanitize.Config(data)
+}
+
+// bundleChecks runs the same checks a user would be asked for first:
+// config validity, directory access, database state, and watch health.
+func bundleChecks(rt *runtime.Runtime, cfg *config.Config, cfgErr error, sessions []discovery.SessionFile) []byte {
+	var b bytes.Buffer
+
+	switch {
+	case cfgErr != nil:
+		fmt.Fprintf(&b, "config: %v (defaults used below)\n", cfgErr)
+	default:
+		fmt.Fprintf(&b, "config: ok (%s)\n", (&configCommand{}).getConfigSource())
+	}
+
+	for _, dir := range cfg.ClaudeDirPaths() {
+		status := "ok"
+		if info, err := os.Stat(expandHome(dir)); err != nil {
+			status = err.Error()
+		} else if !info.IsDir() {
+			status = "not a directory"
+		}
+		fmt.Fprintf(&b, "claude dir %s: %s\n", dir, status)
+	}
+
+	var size int64
+	for _, s := range sessions {
+		size += s.Size
+	}
+	fmt.Fprintf(&b, "sessions: %d file(s), %d bytes\n", len(sessions), size)
+
+	locked := false
+	if cfgErr == nil {
+		_, err := rt.Sessions()
+		if err == nil {
+			err = rt.Persistent()
+		}
+		locked = errors.Is(err, session.ErrDatabaseLocked)
+		switch {
+		case locked:
+			b.WriteString("database: locked (watch or another command is running)\n")
+		case err != nil:
+			fmt.Fprintf(&b, "database: %v\n", err)
+		default:
+			b.WriteString("database: ok\n")
+		}
+		_ = rt.Close() //nolint:errcheck // release the lock
+	}
+
+	hb, hbErr := readHeartbeat(heartbeatPath(cfg))
+	for _, check := range []healthCheck{
+		checkWatch(locked, hb, hbErr == nil),
+		checkIngest(locked, hb, hbErr == nil, 0, time.Now()),
+	} {
+		fmt.Fprintf(&b, "%s: %s (%s)\n", check.Name, check.Status, check.Message)
+	}
+
+	return []byte(sanitize.Home(b.String()))
+}
+
+// bundleLog returns the end of the log file, or nil when logs go to a
+// terminal stream.
+func bundleLog(cfg *config.Config, maxBytes int64) []byte {
+	output := cfg.Logging.Output
+	if output == "" || output == "stdout" || output == "stderr" || maxBytes <= 0 {
+		return nil
+	}
+
+	f, err := os.Open(expandHome(output)) //nolint:gosec // path from user config
+	if err != nil {
+		return []byte(sanitize.Home(fmt.Sprintf("log file unavailable: %v\n", err)))
+	}
+	defer func() {
+		_ = f.Close() //nolint:errcheck // read-only file
+	}()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil
+	}
+	offset := max(info.Size()-maxBytes, 0)
+
+	data, err := io.ReadAll(io.NewSectionReader(f, offset, info.Size()-offset))
+	if err != nil {
+		return nil
+	}
+	if offset > 0 {
+		// Drop the partial first line.
+		if i := bytes.IndexByte(data, '\n'); i >= 0 {
+			data = data[i+1:]
+		}
+	}
+	return []byte(sanitize.Home(string(data)))
+}
+
+// collectFailingLines scans the newest session files for lines that are
+// not valid JSON, or that carry usage but fail to parse. Other lines
+// (user messages, summaries) are expected not to parse and are ignored.
+// Sessions are numbered instead of named, since file names and project
+// directories reveal paths.
+func collectFailingLines(sessions []discovery.SessionFile, limit int) ([]byte, error) {
+	if limit <= 0 {
+		return nil, nil
+	}
+
+	files := append([]discovery.SessionFile(nil), sessions...)
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].ModTime > files[j].ModTime
+	})
+	if len(files) > maxBundleScanFiles {
+		files = files[:maxBundleScanFiles]
+	}
+
+	p := parser.New()
+	var found []failingLine
+	for i, file := range files {
+		name := fmt.Sprintf("session-%d", i+1)
+		lines, err := scanFailingLines(p, file.FilePath, name, limit-len(found))
+		if err != nil {
+			found = append(found, failingLine{Session: name, Error: sanitize.Home(err.Error())})
+		}
+		found = append(found, lines...)
+		if len(found) >= limit {
+			break
+		}
+	}
+
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	for _, line := range found {
+		if err := enc.Encode(line); err != nil {
+			return nil, fmt.Errorf("failed to encode failing line: %w", err)
+		}
+	}
+	return b.Bytes(), nil
+}
+
+// scanFailingLines returns up to limit failing lines from one file.
+func scanFailingLines(p parser.Parser, path, name string, limit int) ([]failingLine, error) {
+	f, err := os.Open(path) //nolint:gosec // path from discovery
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = f.Close() //nolint:errcheck // read-only file
+	}()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), parser.MaxLineLength)
+
+	var found []failingLine
+	lineNum := 0
+	for scanner.Scan() && len(found) < limit {
+		lineNum++
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		_, parseErr := p.ParseLine(line)
+		if parseErr == nil || (json.Valid([]byte(line)) && !hasUsage(line)) {
+			continue
+		}
+		found = append(found, failingLine{
+			Session: name,
+			Line:    lineNum,
+			Error:   parseErr.Error(),
+			Data:    sanitize.Line(line),
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return found, fmt.Errorf("line %d: %w", lineNum+1, err)
+	}
+	return found, nil
+}
+
+// hasUsage reports whether a JSON line carries a message.usage object.
+func hasUsage(line string) bool {
+	var probe struct {
+		Message struct {
+			Usage json.RawMessage `json:"usage"`
+		} `json:"message"`
+	}
+	if err := json.Unmarshal([]byte(line), &probe); err != nil {
+		return false
+	}
+	return len(probe.Message.Usage) > 0 && string(probe.Message.Usage) != "null"
+}
+
+// writeBundle writes files into a gzipped tarball at path.
+func writeBundle(path string, files []bundleFile, modTime time.Time) (err error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600) //nolint:gosec // path from flag
+	if err != nil {
+		return fmt.Errorf("failed to create bundle: %w", err)
+	}
+	defer func() {
+		if closeErr := f.Close(); closeErr != nil && err == nil {
+			err = fmt.Errorf("failed to write bundle: %w", closeErr)
+		}
+	}()
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	for _, file := range files {
+		hdr := &tar.Header{
+			Name:    "token-monitor-debug/" + file.name,
+			Mode:    0600,
+			Size:    int64(len(file.data)),
+			ModTime: modTime.Truncate(time.Second),
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return fmt.Errorf("failed to write bundle: %w", err)
+		}
+		if _, err := tw.Write(file.data); err != nil {
+			return fmt.Errorf("failed to write bundle: %w", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+	return nil
+}
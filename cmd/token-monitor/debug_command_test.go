@@ -0,0 +1,105 @@
+package main
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0xmhha/token-monitor/pkg/discovery"
+)
+
+func TestCollectFailingLines(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "session.jsonl")
+	content := strings.Join([]string{
+		// A valid usage entry.
+		`{"timestamp":"2025-11-01T10:00:00Z","sessionId":"s1","message":{"model":"claude-sonnet-4","usage":{"input_tokens":1}}}`,
+		// A user message without usage is expected not to parse.
+		`{"type":"user","sessionId":"s1","message":{"content":"private prompt"}}`,
+		// Usage without a model fails validation.
+		`{"timestamp":"2025-11-01T10:01:00Z","sessionId":"s1","cwd":"/home/alice","message":{"usage":{"input_tokens":2}}}`,
+		// Truncated JSON.
+		`{"timestamp":"2025-11-01T10:02:00Z","sessionId":"s1","message":{"mod`,
+	}, "\n") + "\n"
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	data, err := collectFailingLines([]discovery.SessionFile{{FilePath: path}}, 10)
+	if err != nil {
+		t.Fatalf("collectFailingLines() error = %v", err)
+	}
+	if strings.Contains(string(data), "alice") || strings.Contains(string(data), "private prompt") {
+		t.Errorf("collectFailingLines() leaked personal data:\n%s", data)
+	}
+
+	var lines []failingLine
+	dec := json.NewDecoder(strings.NewReader(string(data)))
+	for dec.More() {
+		var line failingLine
+		if err := dec.Decode(&line); err != nil {
+			t.Fatalf("invalid failing line: %v", err)
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) != 2 || lines[0].Line != 3 || lines[1].Line != 4 {
+		t.Fatalf("collectFailingLines() = %+v, want lines 3 and 4", lines)
+	}
+	if lines[0].Session != "session-1" || lines[0].Data == "" {
+		t.Errorf("failing line = %+v, want session-1 with sanitized data", lines[0])
+	}
+
+	// The limit stops the scan early.
+	data, err = collectFailingLines([]discovery.SessionFile{{FilePath: path}}, 1)
+	if err != nil {
+		t.Fatalf("collectFailingLines() error = %v", err)
+	}
+	if got := strings.Count(string(data), "\n"); got != 1 {
+		t.Errorf("collectFailingLines(limit 1) returned %d lines", got)
+	}
+}
+
+func TestWriteBundle(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	files := []bundleFile{
+		{name: "version.txt", data: []byte("token-monitor dev\n")},
+		{name: "failing-lines.jsonl", data: nil},
+	}
+	if err := writeBundle(path, files, time.Now()); err != nil {
+		t.Fatalf("writeBundle() error = %v", err)
+	}
+
+	f, err := os.Open(path) //nolint:gosec // test file
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer func() { _ = f.Close() }() //nolint:errcheck
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatalf("gzip.NewReader() error = %v", err)
+	}
+	tr := tar.NewReader(gz)
+
+	var names []string
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("tar Next() error = %v", err)
+		}
+		names = append(names, hdr.Name)
+	}
+	want := "token-monitor-debug/version.txt,token-monitor-debug/failing-lines.jsonl"
+	if got := strings.Join(names, ","); got != want {
+		t.Errorf("bundle entries = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,241 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/0xmhha/token-monitor/pkg/analysis"
+	"github.com/0xmhha/token-monitor/pkg/discovery"
+	"github.com/0xmhha/token-monitor/pkg/logger"
+	"github.com/0xmhha/token-monitor/pkg/parser"
+	"github.com/0xmhha/token-monitor/pkg/reader"
+	"github.com/0xmhha/token-monitor/pkg/rollup"
+)
+
+// dumpCommand writes every usage entry as one JSON object per line.
+type dumpCommand struct {
+	output     string
+	from, to   time.Time // zero for no bound; to is exclusive
+	globalOpts globalOptions
+}
+
+// dumpEntry is the flat, normalized record written for each usage entry.
+// Field names stay stable so that loaders (pandas.read_json(lines=True),
+// jq, DuckDB) keep working across releases.
+type dumpEntry struct {
+	Timestamp           time.Time `json:"timestamp"`
+	SessionID           string    `json:"session_id"`
+	SubAgentID          string    `json:"sub_agent_id,omitempty"`
+	Sidechain           bool      `json:"sidechain"`
+	Project             string    `json:"project"`
+	Cwd                 string    `json:"cwd,omitempty"`
+	Source              string    `json:"source,omitempty"`
+	Model               string    `json:"model"`
+	MessageID           string    `json:"message_id,omitempty"`
+	RequestID           string    `json:"request_id,omitempty"`
+	Version             string    `json:"version,omitempty"`
+	InputTokens         int       `json:"input_tokens"`
+	OutputTokens        int       `json:"output_tokens"`
+	CacheCreationTokens int       `json:"cache_creation_tokens"`
+	CacheReadTokens     int       `json:"cache_read_tokens"`
+	TotalTokens         int       `json:"total_tokens"`
+	CostUSD             float64   `json:"cost_usd"`
+}
+
+// runDumpCommand parses flags and runs the dump command.
+func runDumpCommand(globalOpts globalOptions, args []string) error {
+	fs := flag.NewFlagSet("dump", flag.ExitOnError)
+	output := fs.String("output", "", "output file (default: stdout)")
+	from := fs.String("from", "", "start date (YYYY-MM-DD, inclusive)")
+	to := fs.String("to", "", "end date (YYYY-MM-DD, inclusive)")
+
+	if err := globalOpts.applyFlagDefaults(fs); err != nil {
+		return err
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cmd := &dumpCommand{
+		output:     *output,
+		globalOpts: globalOpts,
+	}
+	if *from != "" {
+		t, err := time.ParseInLocation(rollup.DateLayout, *from, time.Local)
+		if err != nil {
+			return fmt.Errorf("invalid -from %q (want YYYY-MM-DD)", *from)
+		}
+		cmd.from = t
+	}
+	if *to != "" {
+		t, err := time.ParseInLocation(rollup.DateLayout, *to, time.Local)
+		if err != nil {
+			return fmt.Errorf("invalid -to %q (want YYYY-MM-DD)", *to)
+		}
+		cmd.to = t.AddDate(0, 0, 1)
+	}
+	return cmd.Execute()
+}
+
+// Execute reads all session files and writes their entries, without
+// duplicates and sorted by time, to the output.
+func (c *dumpCommand) Execute() error {
+	rt := c.globalOpts.newRuntime("")
+	defer func() {
+		_ = rt.Close() //nolint:errcheck // best effort cleanup
+	}()
+
+	log, err := rt.Logger()
+	if err != nil {
+		return err
+	}
+	disc, err := rt.Discoverer()
+	if err != nil {
+		return err
+	}
+	sessions, err := disc.Discover()
+	if err != nil {
+		return fmt.Errorf("failed to discover sessions: %w", err)
+	}
+	// Files last written before -from hold no entries in the range.
+	if !c.from.IsZero() {
+		sessions = recentSessions(sessions, c.from)
+	}
+
+	r, err := rt.NewReader()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = r.Close() //nolint:errcheck // best effort cleanup
+	}()
+
+	entries := c.load(context.Background(), r, sessions, log)
+
+	if c.output == "" || c.output == "-" {
+		return writeDump(os.Stdout, entries)
+	}
+	if err := os.MkdirAll(filepath.Dir(c.output), 0750); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	f, err := os.Create(c.output) //nolint:gosec // output path comes from the -output flag
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	if err := writeDump(f, entries); err != nil {
+		_ = f.Close() //nolint:errcheck // the write error is reported
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", c.output, err)
+	}
+	c.globalOpts.infof("Wrote %d entries from %d session files to %s\n", len(entries), len(sessions), c.output)
+	return nil
+}
+
+// load reads the entries of each session in the date range and returns
+// them deduplicated and sorted. Unreadable files are logged and skipped.
+func (c *dumpCommand) load(
+	ctx context.Context,
+	r reader.Reader,
+	sessions []discovery.SessionFile,
+	log logger.Logger,
+) []dumpEntry {
+	var entries []dumpEntry
+	for _, sess := range sessions {
+		read, _, err := r.ReadFrom(ctx, sess.FilePath, 0)
+		if err != nil {
+			log.Warn("failed to read session", "session", sess.SessionID, "path", sess.FilePath, "error", err)
+			continue
+		}
+		for _, entry := range read {
+			if !c.from.IsZero() && entry.Timestamp.Before(c.from) {
+				continue
+			}
+			if !c.to.IsZero() && !entry.Timestamp.Before(c.to) {
+				continue
+			}
+			entry.Source = sess.Source
+			entries = append(entries, newDumpEntry(entry, sess.ProjectPath))
+		}
+	}
+	return sortDump(dedupeDump(entries))
+}
+
+// newDumpEntry flattens a usage entry read from a file of project.
+func newDumpEntry(entry parser.UsageEntry, project string) dumpEntry {
+	usage := entry.Message.Usage
+	d := dumpEntry{
+		Timestamp:           entry.Timestamp,
+		SessionID:           entry.SessionID,
+		SubAgentID:          entry.SubAgentID,
+		Sidechain:           entry.IsSidechain,
+		Project:             project,
+		Cwd:                 entry.CurrentDir,
+		Source:              entry.Source,
+		Model:               entry.Message.Model,
+		MessageID:           entry.Message.ID,
+		Version:             entry.Version,
+		InputTokens:         usage.InputTokens,
+		OutputTokens:        usage.OutputTokens,
+		CacheCreationTokens: usage.CacheCreationInputTokens,
+		CacheReadTokens:     usage.CacheReadInputTokens,
+		TotalTokens:         usage.TotalTokens(),
+		CostUSD:             analysis.EntryCost(entry),
+	}
+	if entry.RequestID != nil {
+		d.RequestID = *entry.RequestID
+	}
+	return d
+}
+
+// dedupeDump drops repeats of the same message and request, which Claude
+// Code writes when it logs a response more than once or a sub-agent's
+// entries also appear in the parent session's file. The first copy is
+// kept; entries without a message ID are never dropped.
+func dedupeDump(entries []dumpEntry) []dumpEntry {
+	seen := make(map[string]bool, len(entries))
+	kept := entries[:0]
+	for _, e := range entries {
+		if e.MessageID != "" {
+			key := e.MessageID + "\x00" + e.RequestID
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+		}
+		kept = append(kept, e)
+	}
+	return kept
+}
+
+// sortDump sorts entries by time, keeping file order for equal times.
+func sortDump(entries []dumpEntry) []dumpEntry {
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Timestamp.Before(entries[j].Timestamp)
+	})
+	return entries
+}
+
+// writeDump writes entries to w as JSON Lines.
+func writeDump(w io.Writer, entries []dumpEntry) error {
+	buf := bufio.NewWriter(w)
+	enc := json.NewEncoder(buf)
+	for i := range entries {
+		if err := enc.Encode(&entries[i]); err != nil {
+			return fmt.Errorf("failed to write entry: %w", err)
+		}
+	}
+	if err := buf.Flush(); err != nil {
+		return fmt.Errorf("failed to write entries: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0xmhha/token-monitor/pkg/parser"
+)
+
+func TestNewDumpEntry(t *testing.T) {
+	requestID := "req_1"
+	entry := parser.UsageEntry{
+		Timestamp:  time.Date(2025, 11, 3, 10, 0, 0, 0, time.UTC),
+		SessionID:  "s1",
+		CurrentDir: "/work/app",
+		Source:     "work",
+		RequestID:  &requestID,
+		Message: parser.Message{ID: "msg_1", Model: "claude-sonnet-4", Usage: parser.Usage{
+			InputTokens: 100, OutputTokens: 20, CacheCreationInputTokens: 5, CacheReadInputTokens: 300,
+		}},
+	}
+
+	d := newDumpEntry(entry, "/home/u/.claude/projects/app")
+	if d.SessionID != "s1" || d.Project != "/home/u/.claude/projects/app" || d.Cwd != "/work/app" || d.Source != "work" {
+		t.Errorf("newDumpEntry() = %+v, want session, project, cwd, and source set", d)
+	}
+	if d.MessageID != "msg_1" || d.RequestID != "req_1" {
+		t.Errorf("newDumpEntry() IDs = %q, %q, want msg_1, req_1", d.MessageID, d.RequestID)
+	}
+	if d.TotalTokens != 425 || d.CostUSD <= 0 {
+		t.Errorf("newDumpEntry() total = %d, cost = %v, want 425 and a positive cost", d.TotalTokens, d.CostUSD)
+	}
+}
+
+func TestDedupeAndSortDump(t *testing.T) {
+	base := time.Date(2025, 11, 3, 10, 0, 0, 0, time.UTC)
+	entries := []dumpEntry{
+		{Timestamp: base.Add(2 * time.Minute), MessageID: "m1", RequestID: "r1", InputTokens: 1},
+		{Timestamp: base, MessageID: "m2", RequestID: "r2"},
+		{Timestamp: base.Add(2 * time.Minute), MessageID: "m1", RequestID: "r1", InputTokens: 2}, // repeat
+		{Timestamp: base.Add(time.Minute)},
+		{Timestamp: base.Add(time.Minute)}, // no message ID: kept
+		{Timestamp: base.Add(3 * time.Minute), MessageID: "m1", RequestID: "r3"},
+	}
+
+	got := sortDump(dedupeDump(entries))
+	if len(got) != 5 {
+		t.Fatalf("got %d entries, want 5: %+v", len(got), got)
+	}
+	for i := 1; i < len(got); i++ {
+		if got[i].Timestamp.Before(got[i-1].Timestamp) {
+			t.Errorf("entries not sorted at %d: %v before %v", i, got[i-1].Timestamp, got[i].Timestamp)
+		}
+	}
+	if got[0].MessageID != "m2" || got[3].InputTokens != 1 {
+		t.Errorf("got %+v, want m2 first and the first copy of m1/r1 kept", got)
+	}
+}
+
+func TestWriteDump(t *testing.T) {
+	entries := []dumpEntry{
+		{Timestamp: time.Date(2025, 11, 3, 10, 0, 0, 0, time.UTC), SessionID: "s1", Model: "claude-sonnet-4", InputTokens: 10},
+		{Timestamp: time.Date(2025, 11, 3, 11, 0, 0, 0, time.UTC), SessionID: "s2", Model: "claude-opus-4"},
+	}
+
+	var buf bytes.Buffer
+	if err := writeDump(&buf, entries); err != nil {
+		t.Fatalf("writeDump() error = %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("writeDump() wrote %d lines, want 2:\n%s", len(lines), buf.String())
+	}
+	var first map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &first); err != nil {
+		t.Fatalf("line is not JSON: %v", err)
+	}
+	for _, key := range []string{"timestamp", "session_id", "project", "model", "input_tokens", "total_tokens", "cost_usd"} {
+		if _, ok := first[key]; !ok {
+			t.Errorf("line missing %q: %s", key, lines[0])
+		}
+	}
+}
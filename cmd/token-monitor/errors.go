@@ -0,0 +1,164 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+
+	"github.com/0xmhha/token-monitor/cmd/internal/runtime"
+	"github.com/0xmhha/token-monitor/pkg/baseline"
+	"github.com/0xmhha/token-monitor/pkg/config"
+	"github.com/0xmhha/token-monitor/pkg/discovery"
+	"github.com/0xmhha/token-monitor/pkg/focus"
+	"github.com/0xmhha/token-monitor/pkg/monitor"
+	"github.com/0xmhha/token-monitor/pkg/session"
+	"github.com/0xmhha/token-monitor/pkg/ticket"
+)
+
+// errUnknownCommand is returned when the command name is not recognized.
+var errUnknownCommand = errors.New("unknown command")
+
+// errAliasLoop is returned when alias expansion refers back to itself.
+var errAliasLoop = errors.New("alias loop detected")
+
+// errAliasQuote is returned when an alias value has an unterminated quote
+// or a trailing backslash.
+var errAliasQuote = errors.New("unterminated quote or escape")
+
+// errIntegrity is returned when fsck finds problems it did not repair.
+var errIntegrity = errors.New("integrity check failed")
+
+// errDrift is returned when stats -verify finds results that differ from
+// the session files.
+var errDrift = errors.New("verification failed")
+
+// errUnhealthy is returned when a health check fails.
+var errUnhealthy = errors.New("health check failed")
+
+// errInvalidHookPayload is returned for hook payloads without a session.
+var errInvalidHookPayload = errors.New("invalid hook payload")
+
+// errGit is returned when a git command fails.
+var errGit = errors.New("git command failed")
+
+// errNoLogFile is returned by logs when logging does not go to a file.
+var errNoLogFile = errors.New("no log file")
+
+// errorCodes maps known sentinel errors to stable machine-readable codes.
+// The first matching entry wins.
+var errorCodes = []struct {
+	err  error
+	code string
+}{
+	{errUnknownCommand, "unknown_command"},
+	{errAliasLoop, "alias_loop"},
+	{errIntegrity, "integrity_error"},
+	{errDrift, "stats_drift"},
+	{errUnhealthy, "unhealthy"},
+	{errInvalidHookPayload, "invalid_hook_payload"},
+	{errGit, "git_failed"},
+	{errNoLogFile, "no_log_file"},
+	{session.ErrSessionNotFound, "session_not_found"},
+	{session.ErrInvalidUUID, "invalid_session_id"},
+	{session.ErrNameConflict, "name_conflict"},
+	{session.ErrConflict, "conflict"},
+	{session.ErrDatabaseLocked, "database_locked"},
+	{runtime.ErrTemporaryDatabase, "database_unavailable"},
+	{session.ErrEmptyName, "invalid_name"},
+	{session.ErrInvalidName, "invalid_name"},
+	{baseline.ErrNotFound, "baseline_not_found"},
+	{baseline.ErrExists, "baseline_exists"},
+	{baseline.ErrInvalidName, "invalid_name"},
+	{focus.ErrActive, "focus_active"},
+	{focus.ErrNotActive, "focus_not_active"},
+	{focus.ErrInvalidLabel, "invalid_name"},
+	{ticket.ErrInvalid, "invalid_ticket"},
+	{discovery.ErrNoSessionsFound, "no_sessions"},
+	{monitor.ErrNoSessions, "no_sessions"},
+	{discovery.ErrNoCurrentSession, "no_current_session"},
+	{config.ErrConfigNotFound, "config_not_found"},
+	{config.ErrInvalidYAML, "invalid_config"},
+	{config.ErrInvalidRules, "invalid_config"},
+	{config.ErrSecretNotFound, "secret_not_found"},
+	{config.ErrUnknownKey, "invalid_config"},
+	{config.ErrInvalidValue, "invalid_config"},
+}
+
+// configValidationErrors are the config errors reported as "invalid_config".
+var configValidationErrors = []error{
+	config.ErrNoClaudeDirs,
+	config.ErrInvalidClaudeDir,
+	config.ErrInvalidSessionPattern,
+	config.ErrInvalidDiscoveryFile,
+	config.ErrInvalidMissingSessionID,
+	config.ErrInvalidSessionAttribution,
+	config.ErrInvalidWatchInterval,
+	config.ErrInvalidUpdateFrequency,
+	config.ErrInvalidSessionRetention,
+	config.ErrInvalidMaxPollInterval,
+	config.ErrInvalidBlockTokenLimit,
+	config.ErrInvalidContextWindow,
+	config.ErrInvalidWorkerPoolSize,
+	config.ErrInvalidReadLimits,
+	config.ErrInvalidCacheSize,
+	config.ErrInvalidBatchWindow,
+	config.ErrInvalidReadRetry,
+	config.ErrInvalidDisplayMode,
+	config.ErrInvalidRefreshRate,
+	config.ErrInvalidLocale,
+	config.ErrInvalidUnits,
+	config.ErrInvalidNameValidation,
+	config.ErrInvalidNameNormalization,
+	config.ErrInvalidSessionStates,
+	config.ErrInvalidLogLevel,
+	config.ErrInvalidLogFormat,
+	config.ErrInvalidBasePath,
+	config.ErrInvalidCORSOrigin,
+	config.ErrInvalidServeToken,
+	config.ErrInvalidMQTT,
+	config.ErrInvalidFootprint,
+	config.ErrInvalidRetention,
+	config.ErrInvalidPricing,
+	config.ErrInvalidTeam,
+	config.ErrInvalidTelemetry,
+	config.ErrInvalidNotifyChannel,
+}
+
+// errorResponse is the JSON document emitted when a command fails in JSON mode.
+type errorResponse struct {
+	Error errorDetail `json:"error"`
+}
+
+// errorDetail describes a command failure.
+type errorDetail struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// errorCode returns the machine-readable code for err.
+// Unrecognized errors are reported as "error".
+func errorCode(err error) string {
+	for _, ec := range errorCodes {
+		if errors.Is(err, ec.err) {
+			return ec.code
+		}
+	}
+	for _, validationErr := range configValidationErrors {
+		if errors.Is(err, validationErr) {
+			return "invalid_config"
+		}
+	}
+	return "error"
+}
+
+// writeJSONError writes err as a structured JSON error object.
+func writeJSONError(w io.Writer, err error) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(errorResponse{
+		Error: errorDetail{
+			Code:    errorCode(err),
+			Message: err.Error(),
+		},
+	})
+}
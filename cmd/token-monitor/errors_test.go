@@ -0,0 +1,52 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/0xmhha/token-monitor/pkg/config"
+	"github.com/0xmhha/token-monitor/pkg/session"
+)
+
+func TestErrorCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"unknown command", fmt.Errorf("%w: foo", errUnknownCommand), "unknown_command"},
+		{"wrapped session not found", fmt.Errorf("failed: %w", session.ErrSessionNotFound), "session_not_found"},
+		{"config validation", fmt.Errorf("invalid configuration after update: %w", config.ErrInvalidLogLevel), "invalid_config"},
+		{"unrecognized", errors.New("boom"), "error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errorCode(tt.err); got != tt.want {
+				t.Errorf("errorCode() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteJSONError(t *testing.T) {
+	var buf bytes.Buffer
+	err := fmt.Errorf("%w: abc", session.ErrSessionNotFound)
+	if writeErr := writeJSONError(&buf, err); writeErr != nil {
+		t.Fatalf("writeJSONError() error = %v", writeErr)
+	}
+
+	var got errorResponse
+	if decodeErr := json.Unmarshal(buf.Bytes(), &got); decodeErr != nil {
+		t.Fatalf("output is not valid JSON: %v\n%s", decodeErr, buf.String())
+	}
+	if got.Error.Code != "session_not_found" {
+		t.Errorf("code = %q, want %q", got.Error.Code, "session_not_found")
+	}
+	if got.Error.Message != "session not found: abc" {
+		t.Errorf("message = %q, want %q", got.Error.Message, "session not found: abc")
+	}
+}
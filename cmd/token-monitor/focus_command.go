@@ -0,0 +1,380 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/0xmhha/token-monitor/cmd/internal/runtime"
+	"github.com/0xmhha/token-monitor/pkg/display"
+	"github.com/0xmhha/token-monitor/pkg/focus"
+	"github.com/0xmhha/token-monitor/pkg/parser"
+	"github.com/0xmhha/token-monitor/pkg/ticket"
+)
+
+// defaultFocusDays is the period covered by "focus list" and "focus report".
+const defaultFocusDays = 7
+
+// focusCommand handles labeled focus windows.
+type focusCommand struct {
+	globalOpts globalOptions
+}
+
+// runFocusCommand runs the focus command.
+func runFocusCommand(globalOpts globalOptions, args []string) error {
+	cmd := &focusCommand{globalOpts: globalOpts}
+	return cmd.Execute(args)
+}
+
+// Execute dispatches a focus subcommand.
+func (c *focusCommand) Execute(args []string) error {
+	if len(args) == 0 {
+		return c.showHelp()
+	}
+
+	switch args[0] {
+	case "start":
+		return c.runStart(args[1:])
+	case "stop":
+		return c.runStop(args[1:])
+	case "status":
+		return c.runStatus(args[1:])
+	case "list":
+		return c.runList(args[1:])
+	case "report":
+		return c.runReport(args[1:])
+	case "help":
+		return c.showHelp()
+	default:
+		return fmt.Errorf("unknown focus subcommand: %s", args[0])
+	}
+}
+
+// showHelp displays help for the focus command.
+func (c *focusCommand) showHelp() error {
+	help := `Focus - Track spend per task with labeled focus windows
+
+Usage:
+  token-monitor focus <subcommand> [flags]
+
+Subcommands:
+  start -label <name>   Open a focus window for a task
+  stop                  Close the open window
+  status                Show the open window and its usage so far
+  list                  List the windows of the last N days
+  report                Usage per label over the last N days
+
+Flags:
+  -label        Task label (start; letters, digits, '.', '_', '/', '-')
+  -ticket       Ticket the window's usage counts toward in "tickets" (start)
+  -switch       Close the open window first instead of failing (start)
+  -days         Period for list and report (default: 7)
+
+All usage from any Claude session while a window is open counts toward
+its label. Only one window is open at a time. Windows are stored in the
+BoltDB database, which a running watch holds locked.
+`
+	fmt.Print(help)
+	return nil
+}
+
+// runStart opens a focus window.
+func (c *focusCommand) runStart(args []string) error {
+	fs := flag.NewFlagSet("focus start", flag.ExitOnError)
+	label := fs.String("label", "", "task label")
+	ticketLabel := fs.String("ticket", "", "ticket to attribute the window's usage to")
+	switchWindow := fs.Bool("switch", false, "close the open window first")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *label == "" && fs.NArg() == 1 {
+		*label = fs.Arg(0)
+	}
+	if *label == "" {
+		return fmt.Errorf("usage: focus start -label <name> [-ticket <ticket>] [-switch]")
+	}
+	if *ticketLabel != "" {
+		if err := ticket.Validate(*ticketLabel); err != nil {
+			return fmt.Errorf("%w: %q", err, *ticketLabel)
+		}
+	}
+
+	rt := c.globalOpts.newRuntime("")
+	defer func() {
+		_ = rt.Close() //nolint:errcheck // best effort cleanup
+	}()
+
+	if err := rt.Persistent(); err != nil {
+		return err
+	}
+	store, err := rt.Focus()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if *switchWindow {
+		if prev, err := store.Stop(now); err == nil {
+			c.globalOpts.infof("✓ Stopped %s after %s\n", prev.Label, display.FormatDurationLong(prev.Duration(now), 2))
+		}
+	}
+	w, err := store.Start(focus.Window{Label: *label, Ticket: *ticketLabel, Start: now})
+	if err != nil {
+		return err
+	}
+
+	if c.globalOpts.jsonOutput {
+		return printJSON(w)
+	}
+	c.globalOpts.output().Printf("✓ Focusing on %s since %s\n", w.Label, w.Start.Local().Format("15:04"))
+	return nil
+}
+
+// runStop closes the open focus window and prints its usage.
+func (c *focusCommand) runStop(args []string) error {
+	if len(args) != 0 {
+		return fmt.Errorf("usage: focus stop")
+	}
+
+	rt := c.globalOpts.newRuntime("")
+	defer func() {
+		_ = rt.Close() //nolint:errcheck // best effort cleanup
+	}()
+
+	if err := rt.Persistent(); err != nil {
+		return err
+	}
+	store, err := rt.Focus()
+	if err != nil {
+		return err
+	}
+	w, err := store.Stop(time.Now())
+	if err != nil {
+		return err
+	}
+
+	return c.printWindowUsage(rt, w, "Stopped")
+}
+
+// runStatus shows the open focus window.
+func (c *focusCommand) runStatus(args []string) error {
+	if len(args) != 0 {
+		return fmt.Errorf("usage: focus status")
+	}
+
+	rt := c.globalOpts.newRuntime("")
+	defer func() {
+		_ = rt.Close() //nolint:errcheck // best effort cleanup
+	}()
+
+	store, err := rt.Focus()
+	if err != nil {
+		return err
+	}
+	w, ok, err := store.Active()
+	if err != nil {
+		return err
+	}
+	if !ok {
+		if c.globalOpts.jsonOutput {
+			return printJSON(nil)
+		}
+		c.globalOpts.infof("No active focus window\n")
+		return nil
+	}
+
+	return c.printWindowUsage(rt, w, "Focusing on")
+}
+
+// printWindowUsage prints the usage of one window.
+func (c *focusCommand) printWindowUsage(rt *runtime.Runtime, w focus.Window, verb string) error {
+	now := time.Now()
+	entries, err := focusEntries(rt, w.Start)
+	if err != nil {
+		return err
+	}
+	usage := focus.Attribute([]focus.Window{w}, entries, now)[0]
+
+	if c.globalOpts.jsonOutput {
+		return printJSON(struct {
+			focus.Window
+			Usage focus.Usage `json:"usage"`
+		}{w, usage})
+	}
+	c.globalOpts.output().Printf("%s %s for %s: %s requests, %s tokens, %s\n",
+		verb, w.Label, display.FormatDurationLong(w.Duration(now), 2),
+		display.FormatNumber(usage.Totals.Entries),
+		display.FormatNumber(usage.Totals.TotalTokens()),
+		display.FormatCost(usage.Totals.CostUSD))
+	return nil
+}
+
+// runList prints the windows of the last days days.
+func (c *focusCommand) runList(args []string) error {
+	fs := flag.NewFlagSet("focus list", flag.ExitOnError)
+	days := fs.Int("days", defaultFocusDays, "period to list")
+
+	if err := c.globalOpts.applyFlagDefaults(fs); err != nil {
+		return err
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *days <= 0 {
+		return fmt.Errorf("invalid -days: %d", *days)
+	}
+
+	rt := c.globalOpts.newRuntime("")
+	defer func() {
+		_ = rt.Close() //nolint:errcheck // best effort cleanup
+	}()
+
+	store, err := rt.Focus()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	windows, err := store.List(now.AddDate(0, 0, -*days), time.Time{})
+	if err != nil {
+		return err
+	}
+
+	if c.globalOpts.jsonOutput {
+		if windows == nil {
+			windows = []focus.Window{}
+		}
+		return printJSON(windows)
+	}
+	if len(windows) == 0 {
+		c.globalOpts.infof("No focus windows in the last %d days\n", *days)
+		return nil
+	}
+
+	header := []string{"LABEL", "START", "END", "DURATION"}
+	rows := make([][]string, 0, len(windows))
+	for _, w := range windows {
+		end := "(active)"
+		if !w.Active() {
+			end = w.End.Local().Format("2006-01-02 15:04")
+		}
+		rows = append(rows, []string{
+			w.Label,
+			w.Start.Local().Format("2006-01-02 15:04"),
+			end,
+			display.FormatDurationLong(w.Duration(now), 3),
+		})
+	}
+	return display.WriteTable(os.Stdout, header, rows, false)
+}
+
+// runReport prints the usage per label over the last days days.
+func (c *focusCommand) runReport(args []string) error {
+	fs := flag.NewFlagSet("focus report", flag.ExitOnError)
+	days := fs.Int("days", defaultFocusDays, "period to report")
+
+	if err := c.globalOpts.applyFlagDefaults(fs); err != nil {
+		return err
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *days <= 0 {
+		return fmt.Errorf("invalid -days: %d", *days)
+	}
+
+	rt := c.globalOpts.newRuntime("")
+	defer func() {
+		_ = rt.Close() //nolint:errcheck // best effort cleanup
+	}()
+
+	store, err := rt.Focus()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	since := now.AddDate(0, 0, -*days)
+	windows, err := store.List(since, time.Time{})
+	if err != nil {
+		return err
+	}
+	// Windows that began before the period still count in full.
+	if len(windows) > 0 && windows[0].Start.Before(since) {
+		since = windows[0].Start
+	}
+	entries, err := focusEntries(rt, since)
+	if err != nil {
+		return err
+	}
+	usage := focus.Attribute(windows, entries, now)
+
+	if c.globalOpts.jsonOutput {
+		return printJSON(usage)
+	}
+	if len(usage) == 0 {
+		c.globalOpts.infof("No focus windows in the last %d days\n", *days)
+		return nil
+	}
+
+	header := []string{"LABEL", "WINDOWS", "TIME", "SESSIONS", "REQUESTS", "TOKENS", "COST"}
+	rows := make([][]string, 0, len(usage))
+	for _, u := range usage {
+		rows = append(rows, []string{
+			u.Label,
+			display.FormatNumber(u.Windows),
+			display.FormatDurationLong(u.Duration, 3),
+			display.FormatNumber(u.Sessions),
+			display.FormatNumber(u.Totals.Entries),
+			display.FormatNumber(u.Totals.TotalTokens()),
+			display.FormatCost(u.Totals.CostUSD),
+		})
+	}
+	return display.WriteTable(os.Stdout, header, rows, false)
+}
+
+// focusEntries reads the entries of all sessions from since on, tagged
+// with their source. Files are read from the start with a private
+// position store.
+func focusEntries(rt *runtime.Runtime, since time.Time) ([]parser.UsageEntry, error) {
+	log, err := rt.Logger()
+	if err != nil {
+		return nil, err
+	}
+	disc, err := rt.Discoverer()
+	if err != nil {
+		return nil, err
+	}
+	sessions, err := disc.Discover()
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover sessions: %w", err)
+	}
+	r, err := rt.NewReader()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = r.Close() //nolint:errcheck // best effort cleanup
+	}()
+
+	var entries []parser.UsageEntry
+	ctx := context.Background()
+	for _, sess := range sessions {
+		if sess.ModTime < since.Unix() {
+			continue
+		}
+		read, err := r.Read(ctx, sess.FilePath)
+		if err != nil {
+			log.Warn("failed to read session", "session", sess.SessionID, "path", sess.FilePath, "error", err)
+			continue
+		}
+		for _, entry := range read {
+			if !entry.Timestamp.Before(since) {
+				entry.Source = sess.Source
+				entries = append(entries, entry)
+			}
+		}
+	}
+	return entries, nil
+}
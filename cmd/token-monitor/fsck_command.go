@@ -0,0 +1,329 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/0xmhha/token-monitor/cmd/internal/runtime"
+	"github.com/0xmhha/token-monitor/pkg/display"
+	"github.com/0xmhha/token-monitor/pkg/i18n"
+	"github.com/0xmhha/token-monitor/pkg/reader"
+	"github.com/0xmhha/token-monitor/pkg/rollup"
+)
+
+// fsckIssue is one integrity problem found by fsck.
+type fsckIssue struct {
+	Check    string `json:"check"`
+	Subject  string `json:"subject"`
+	Problem  string `json:"problem"`
+	Repaired bool   `json:"repaired"`
+
+	// Warning marks findings fsck cannot repair and that do not fail it,
+	// such as session files with future-dated entries.
+	Warning bool `json:"warning,omitempty"`
+}
+
+// fsckCommand validates cross-references in the BoltDB database.
+type fsckCommand struct {
+	repair     bool
+	globalOpts globalOptions
+}
+
+// runFsckCommand parses flags and runs the fsck command.
+func runFsckCommand(globalOpts globalOptions, args []string) error {
+	fs := flag.NewFlagSet("fsck", flag.ExitOnError)
+	repair := fs.Bool("repair", false, "fix orphaned entries and recompute inconsistent rollups")
+
+	if err := globalOpts.applyFlagDefaults(fs); err != nil {
+		return err
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cmd := &fsckCommand{
+		repair:     *repair,
+		globalOpts: globalOpts,
+	}
+	return cmd.Execute()
+}
+
+// Execute runs all integrity checks and reports the findings.
+func (c *fsckCommand) Execute() error {
+	rt := c.globalOpts.newRuntime("")
+	defer func() {
+		_ = rt.Close() //nolint:errcheck // best effort cleanup
+	}()
+
+	if _, err := rt.Config(); err != nil {
+		return err
+	}
+	sessionMgr, err := rt.Sessions()
+	if err == nil {
+		err = rt.Persistent()
+	}
+	if err != nil {
+		return fmt.Errorf("database unavailable (is watch running?): %w", err)
+	}
+
+	var issues []fsckIssue
+
+	indexIssues, err := sessionMgr.CheckIndex(c.repair)
+	if err != nil {
+		return fmt.Errorf("failed to check session names: %w", err)
+	}
+	for _, issue := range indexIssues {
+		subject := issue.Name
+		if subject == "" {
+			subject = issue.UUID
+		}
+		issues = append(issues, fsckIssue{
+			Check:    "names",
+			Subject:  subject,
+			Problem:  issue.Problem,
+			Repaired: issue.Repaired,
+		})
+	}
+
+	positions, err := reader.NewBoltPositionStore(sessionMgr.DB())
+	if err != nil {
+		return err
+	}
+	offsets, err := positions.Positions()
+	if err != nil {
+		return fmt.Errorf("failed to read file positions: %w", err)
+	}
+	found, err := c.checkOffsets("positions", offsets, positions.DeletePosition)
+	if err != nil {
+		return err
+	}
+	issues = append(issues, found...)
+
+	store, err := rt.Rollups()
+	if err != nil {
+		return err
+	}
+	if offsets, err = store.Offsets(); err != nil {
+		return fmt.Errorf("failed to read rollup offsets: %w", err)
+	}
+	found, err = c.checkOffsets("rollup offsets", offsets, store.DeleteOffset)
+	if err != nil {
+		return err
+	}
+	issues = append(issues, found...)
+
+	found, err = c.checkRollups(rt, store)
+	if err != nil {
+		return err
+	}
+	issues = append(issues, found...)
+
+	found, err = c.checkSessionFiles(rt)
+	if err != nil {
+		return err
+	}
+	issues = append(issues, found...)
+
+	if err := c.display(issues); err != nil {
+		return err
+	}
+
+	unrepaired := 0
+	for _, issue := range issues {
+		if !issue.Repaired && !issue.Warning {
+			unrepaired++
+		}
+	}
+	if unrepaired > 0 {
+		return fmt.Errorf("%w: %d problem(s) found", errIntegrity, unrepaired)
+	}
+	return nil
+}
+
+// checkOffsets reports stored file offsets whose file is missing or shorter
+// than the offset. In repair mode the entries are deleted, so the file is
+// re-read from the start if it reappears.
+func (c *fsckCommand) checkOffsets(check string, offsets map[string]int64, remove func(string) error) ([]fsckIssue, error) {
+	paths := make([]string, 0, len(offsets))
+	for path := range offsets {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var issues []fsckIssue
+	for _, path := range paths {
+		var problem string
+		info, err := os.Stat(path)
+		switch {
+		case os.IsNotExist(err):
+			problem = "file no longer exists"
+		case err != nil:
+			problem = fmt.Sprintf("cannot stat file: %v", err)
+		case offsets[path] > info.Size():
+			problem = fmt.Sprintf("offset %d beyond file size %d", offsets[path], info.Size())
+		default:
+			continue
+		}
+
+		issue := fsckIssue{Check: check, Subject: path, Problem: problem}
+		if c.repair {
+			if err := remove(path); err != nil {
+				return nil, fmt.Errorf("failed to remove %s entry: %w", check, err)
+			}
+			issue.Repaired = true
+		}
+		issues = append(issues, issue)
+	}
+	return issues, nil
+}
+
+// checkRollups compares stored rollups with totals recomputed from the raw
+// session files. In repair mode the rollups of sessions that still have
+// files are rebuilt; sessions whose files were deleted are left alone.
+func (c *fsckCommand) checkRollups(rt *runtime.Runtime, store rollup.Store) ([]fsckIssue, error) {
+	disc, err := rt.Discoverer()
+	if err != nil {
+		return nil, err
+	}
+	sessions, err := disc.Discover()
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover sessions: %w", err)
+	}
+
+	r, err := rt.NewReader()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = r.Close() //nolint:errcheck // best effort cleanup
+	}()
+
+	ctx := context.Background()
+	result, err := store.Verify(ctx, sessions, r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify rollups: %w", err)
+	}
+	if result.Pending > 0 {
+		c.globalOpts.infof("%d session file(s) have entries not yet rolled up; their sessions were skipped\n", result.Pending)
+	}
+	if result.Archived > 0 {
+		c.globalOpts.infof("%d session(s) are kept only in the rollups because their files were deleted\n", result.Archived)
+	}
+
+	issues := make([]fsckIssue, 0, len(result.Mismatches))
+	for _, m := range result.Mismatches {
+		issues = append(issues, fsckIssue{
+			Check:   "rollups",
+			Subject: fmt.Sprintf("%s %s %s", m.Date, m.Model, m.SessionID),
+			Problem: fmt.Sprintf("stored %d entries / %d tokens, raw data has %d / %d",
+				m.Stored.Entries, m.Stored.TotalTokens(), m.Expected.Entries, m.Expected.TotalTokens()),
+		})
+	}
+
+	if c.repair && len(issues) > 0 {
+		if _, err := rebuildRollups(ctx, rt, store); err != nil {
+			return nil, err
+		}
+		for i := range issues {
+			issues[i].Repaired = true
+		}
+	}
+	return issues, nil
+}
+
+// checkSessionFiles reports session files skipped as copies of another
+// file of the same session, and files with entries dated after the file
+// was last written, which happens when the clock was wrong while Claude
+// ran. Both are handled when reading (copies are counted once, future
+// timestamps are clamped to the file's modification time), so the
+// findings are warnings.
+func (c *fsckCommand) checkSessionFiles(rt *runtime.Runtime) ([]fsckIssue, error) {
+	disc, err := rt.Discoverer()
+	if err != nil {
+		return nil, err
+	}
+	sessions, err := disc.Discover()
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover sessions: %w", err)
+	}
+
+	var issues []fsckIssue
+	for _, dup := range disc.Duplicates() {
+		issues = append(issues, fsckIssue{
+			Check:   "duplicates",
+			Subject: dup.File.FilePath,
+			Problem: fmt.Sprintf("copy of %s, counted once", dup.Of),
+			Warning: true,
+		})
+	}
+
+	r, err := rt.NewReader()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = r.Close() //nolint:errcheck // best effort cleanup
+	}()
+
+	ctx := context.Background()
+	for _, f := range sessions {
+		entries, _, err := r.ReadFrom(ctx, f.FilePath, 0)
+		if err != nil {
+			continue // unreadable files are reported by the rollup check
+		}
+		clamped := 0
+		var maxSkew time.Duration
+		for _, e := range entries {
+			if skew := e.Skew(); skew > 0 {
+				clamped++
+				if skew > maxSkew {
+					maxSkew = skew
+				}
+			}
+		}
+		if clamped > 0 {
+			issues = append(issues, fsckIssue{
+				Check:   "clock skew",
+				Subject: f.FilePath,
+				Problem: fmt.Sprintf("%d future-dated entries (up to %s ahead), counted at the file's modification time",
+					clamped, display.FormatDurationLong(maxSkew, 2)),
+				Warning: true,
+			})
+		}
+	}
+	return issues, nil
+}
+
+// display prints the issues as a list or JSON.
+func (c *fsckCommand) display(issues []fsckIssue) error {
+	if c.globalOpts.jsonOutput {
+		if issues == nil {
+			issues = []fsckIssue{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(issues)
+	}
+
+	if len(issues) == 0 {
+		fmt.Println(i18n.T("msg.no_problems"))
+		return nil
+	}
+
+	for _, issue := range issues {
+		status := ""
+		switch {
+		case issue.Repaired:
+			status = " (repaired)"
+		case issue.Warning:
+			status = " (warning)"
+		}
+		fmt.Printf("[%s] %s: %s%s\n", issue.Check, issue.Subject, issue.Problem, status)
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFsckCheckOffsets(t *testing.T) {
+	dir := t.TempDir()
+	present := filepath.Join(dir, "present.jsonl")
+	if err := os.WriteFile(present, []byte("0123456789"), 0600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	missing := filepath.Join(dir, "missing.jsonl")
+
+	offsets := map[string]int64{
+		present: 10,
+		missing: 5,
+	}
+
+	issues, err := (&fsckCommand{}).checkOffsets("positions", offsets, nil)
+	if err != nil {
+		t.Fatalf("checkOffsets() error = %v", err)
+	}
+	if len(issues) != 1 || issues[0].Subject != missing || issues[0].Repaired {
+		t.Fatalf("checkOffsets() = %+v, want one unrepaired issue for the missing file", issues)
+	}
+
+	// An offset past the end of the file is also reported, and repair
+	// removes both entries.
+	offsets[present] = 11
+	var removed []string
+	remove := func(path string) error {
+		removed = append(removed, path)
+		return nil
+	}
+	issues, err = (&fsckCommand{repair: true}).checkOffsets("positions", offsets, remove)
+	if err != nil {
+		t.Fatalf("checkOffsets() error = %v", err)
+	}
+	if len(issues) != 2 || len(removed) != 2 || !issues[0].Repaired || !issues[1].Repaired {
+		t.Errorf("checkOffsets(repair) = %+v, removed %v; want both repaired", issues, removed)
+	}
+}
@@ -0,0 +1,333 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/0xmhha/token-monitor/pkg/config"
+	"github.com/0xmhha/token-monitor/pkg/overhead"
+	"github.com/0xmhha/token-monitor/pkg/session"
+)
+
+// heartbeatSuffix is appended to the database path to name the file watch
+// updates after every rollup ingest.
+const heartbeatSuffix = ".heartbeat"
+
+// defaultHealthTimeout bounds each HTTP probe made by health.
+const defaultHealthTimeout = 5 * time.Second
+
+// heartbeat records that watch is running and when it last ingested,
+// with the resources watch used in the last interval and since start.
+type heartbeat struct {
+	PID           int                `json:"pid"`
+	StartedAt     time.Time          `json:"started_at"`
+	LastIngest    time.Time          `json:"last_ingest"`
+	Interval      time.Duration      `json:"interval"`
+	Overhead      *overhead.Interval `json:"overhead,omitempty"`
+	OverheadTotal *overhead.Interval `json:"overhead_total,omitempty"`
+}
+
+// heartbeatPath returns the heartbeat file for the configured database.
+func heartbeatPath(cfg *config.Config) string {
+	return expandHome(cfg.Storage.DBPath) + heartbeatSuffix
+}
+
+// writeHeartbeat replaces the heartbeat file atomically, so health never
+// reads a partial document.
+func writeHeartbeat(path string, hb heartbeat) error {
+	data, err := json.Marshal(hb)
+	if err != nil {
+		return fmt.Errorf("failed to encode heartbeat: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to write heartbeat: %w", err)
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			_ = os.Remove(tmp.Name()) //nolint:errcheck // best effort cleanup
+		}
+	}()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close() //nolint:errcheck // already failing
+		return fmt.Errorf("failed to write heartbeat: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write heartbeat: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write heartbeat: %w", err)
+	}
+	committed = true
+	return nil
+}
+
+// readHeartbeat reads the heartbeat file written by watch.
+func readHeartbeat(path string) (heartbeat, error) {
+	var hb heartbeat
+	data, err := os.ReadFile(path) //nolint:gosec // path derives from configured DB path
+	if err != nil {
+		return hb, err
+	}
+	if err := json.Unmarshal(data, &hb); err != nil {
+		return hb, fmt.Errorf("invalid heartbeat %s: %w", path, err)
+	}
+	return hb, nil
+}
+
+// healthCheck is the outcome of one health check.
+type healthCheck struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"` // ok, fail, or skip
+	Message string `json:"message"`
+
+	// Overhead and OverheadTotal are set on the perf check.
+	Overhead      *overhead.Interval `json:"overhead,omitempty"`
+	OverheadTotal *overhead.Interval `json:"overhead_total,omitempty"`
+}
+
+// healthCommand checks that the background daemon is alive and current.
+type healthCommand struct {
+	watch      bool
+	perf       bool
+	url        string
+	maxAge     time.Duration
+	timeout    time.Duration
+	globalOpts globalOptions
+}
+
+// runHealthCommand parses flags and runs the health command.
+func runHealthCommand(globalOpts globalOptions, args []string) error {
+	fs := flag.NewFlagSet("health", flag.ExitOnError)
+	watch := fs.Bool("watch", true, "check that watch is running and ingesting (disable for serve-only deployments)")
+	perf := fs.Bool("perf", false, "report the CPU time, bytes read, and allocations of watch itself")
+	url := fs.String("url", "", "serve -http base URL to probe (default: serve.addr when set)")
+	maxAge := fs.Duration("max-age", 0, "maximum age of the last ingest (default: 3x the watch rollup interval)")
+	timeout := fs.Duration("timeout", defaultHealthTimeout, "timeout for each HTTP probe")
+
+	if err := globalOpts.applyFlagDefaults(fs); err != nil {
+		return err
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cmd := &healthCommand{
+		watch:      *watch,
+		perf:       *perf,
+		url:        *url,
+		maxAge:     *maxAge,
+		timeout:    *timeout,
+		globalOpts: globalOpts,
+	}
+	return cmd.Execute()
+}
+
+// Execute runs the checks and exits non-zero if any failed.
+func (c *healthCommand) Execute() error {
+	rt := c.globalOpts.newRuntime("")
+	defer func() {
+		_ = rt.Close() //nolint:errcheck // best effort cleanup
+	}()
+
+	cfg, err := rt.Config()
+	if err != nil {
+		return err
+	}
+
+	var checks []healthCheck
+	if c.watch {
+		// watch holds the database lock for as long as it runs.
+		_, dbErr := rt.Sessions()
+		locked := errors.Is(dbErr, session.ErrDatabaseLocked)
+		if dbErr != nil && !locked {
+			return fmt.Errorf("failed to open database: %w", dbErr)
+		}
+		_ = rt.Close() //nolint:errcheck // release the lock before probing
+
+		hb, hbErr := readHeartbeat(heartbeatPath(cfg))
+		if hbErr != nil && !os.IsNotExist(hbErr) {
+			return hbErr
+		}
+		checks = append(checks,
+			checkWatch(locked, hb, hbErr == nil),
+			checkIngest(locked, hb, hbErr == nil, c.maxAge, time.Now()),
+		)
+		if c.perf {
+			checks = append(checks, checkPerf(locked, hb, hbErr == nil))
+		}
+	}
+	checks = append(checks, c.checkServe(cfg))
+
+	if err := c.display(checks); err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, check := range checks {
+		if check.Status == "fail" {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%w: %d check(s) failed", errUnhealthy, failed)
+	}
+	return nil
+}
+
+// checkWatch reports whether watch is running: it holds the database lock
+// and has written a heartbeat.
+func checkWatch(locked bool, hb heartbeat, hasHeartbeat bool) healthCheck {
+	check := healthCheck{Name: "watch"}
+	switch {
+	case locked && hasHeartbeat:
+		check.Status = "ok"
+		check.Message = fmt.Sprintf("running (pid %d)", hb.PID)
+	case locked:
+		check.Status = "fail"
+		check.Message = "database is locked but no heartbeat was written; is another command running?"
+	case hasHeartbeat:
+		check.Status = "fail"
+		check.Message = fmt.Sprintf("not running (stale heartbeat from pid %d)", hb.PID)
+	default:
+		check.Status = "fail"
+		check.Message = "not running"
+	}
+	return check
+}
+
+// checkIngest reports whether watch ingested recently. With maxAge zero the
+// limit is three rollup intervals as reported by watch.
+func checkIngest(locked bool, hb heartbeat, hasHeartbeat bool, maxAge time.Duration, now time.Time) healthCheck {
+	check := healthCheck{Name: "ingest"}
+	if !locked || !hasHeartbeat {
+		check.Status = "skip"
+		check.Message = "watch is not running"
+		return check
+	}
+	if hb.LastIngest.IsZero() {
+		check.Status = "fail"
+		check.Message = fmt.Sprintf("no ingest since start %s ago", now.Sub(hb.StartedAt).Round(time.Second))
+		return check
+	}
+
+	if maxAge <= 0 {
+		maxAge = 3 * hb.Interval
+	}
+	if maxAge <= 0 {
+		maxAge = 3 * rollupInterval
+	}
+
+	age := now.Sub(hb.LastIngest).Round(time.Second)
+	check.Message = fmt.Sprintf("last ingest %s ago (limit %s)", age, maxAge)
+	if age > maxAge {
+		check.Status = "fail"
+	} else {
+		check.Status = "ok"
+	}
+	return check
+}
+
+// checkPerf reports the resources watch used in its last rollup interval
+// and since it started. It never fails: the numbers are for judging
+// whether watch stays lightweight, not a limit.
+func checkPerf(locked bool, hb heartbeat, hasHeartbeat bool) healthCheck {
+	check := healthCheck{Name: "perf"}
+	if !locked || !hasHeartbeat {
+		check.Status = "skip"
+		check.Message = "watch is not running"
+		return check
+	}
+	if hb.Overhead == nil || hb.OverheadTotal == nil {
+		check.Status = "skip"
+		check.Message = "watch does not report its overhead (restart it with this version)"
+		return check
+	}
+
+	check.Status = "ok"
+	check.Message = fmt.Sprintf("last %s; since start %s", hb.Overhead, hb.OverheadTotal)
+	check.Overhead = hb.Overhead
+	check.OverheadTotal = hb.OverheadTotal
+	return check
+}
+
+// checkServe probes /healthz and /readyz of an HTTP MCP server. It is
+// skipped when no address is given or configured.
+func (c *healthCommand) checkServe(cfg *config.Config) healthCheck {
+	check := healthCheck{Name: "serve"}
+	base := c.url
+	if base == "" {
+		base = cfg.Serve.Addr
+	}
+	if base == "" {
+		check.Status = "skip"
+		check.Message = "no address (use -url or serve.addr)"
+		return check
+	}
+	base = serveURL(base)
+
+	client := &http.Client{Timeout: c.timeout}
+	for _, path := range []string{"/healthz", "/readyz"} {
+		if err := probe(client, base+path); err != nil {
+			check.Status = "fail"
+			check.Message = fmt.Sprintf("%s%s: %v", base, path, err)
+			return check
+		}
+	}
+	check.Status = "ok"
+	check.Message = base + " is ready"
+	return check
+}
+
+// serveURL turns a listen address such as ":8080" into a base URL.
+func serveURL(addr string) string {
+	if strings.Contains(addr, "://") {
+		return strings.TrimSuffix(addr, "/")
+	}
+	if strings.HasPrefix(addr, ":") {
+		addr = "127.0.0.1" + addr
+	}
+	return "http://" + addr
+}
+
+// probe returns an error unless url answers 200.
+func probe(client *http.Client, url string) error {
+	resp, err := client.Get(url) //nolint:gosec,noctx // URL comes from flags or config
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close() //nolint:errcheck // read-only body
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 256)) //nolint:errcheck // reason is optional
+		return fmt.Errorf("%s %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// display prints the checks as a list or JSON.
+func (c *healthCommand) display(checks []healthCheck) error {
+	if c.globalOpts.jsonOutput {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(checks)
+	}
+
+	for _, check := range checks {
+		fmt.Printf("%-4s %-7s %s\n", check.Status, check.Name, check.Message)
+	}
+	return nil
+}
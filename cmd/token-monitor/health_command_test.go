@@ -0,0 +1,137 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/0xmhha/token-monitor/pkg/config"
+	"github.com/0xmhha/token-monitor/pkg/overhead"
+)
+
+func TestHeartbeatRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sessions.db"+heartbeatSuffix)
+	want := heartbeat{
+		PID:        42,
+		StartedAt:  time.Date(2025, 11, 1, 9, 0, 0, 0, time.UTC),
+		LastIngest: time.Date(2025, 11, 1, 9, 0, 30, 0, time.UTC),
+		Interval:   rollupInterval,
+	}
+
+	if err := writeHeartbeat(path, want); err != nil {
+		t.Fatalf("writeHeartbeat() error = %v", err)
+	}
+	got, err := readHeartbeat(path)
+	if err != nil {
+		t.Fatalf("readHeartbeat() error = %v", err)
+	}
+	if got != want {
+		t.Errorf("readHeartbeat() = %+v, want %+v", got, want)
+	}
+}
+
+func TestHealthChecks(t *testing.T) {
+	now := time.Date(2025, 11, 1, 12, 0, 0, 0, time.UTC)
+	hb := heartbeat{PID: 7, StartedAt: now.Add(-time.Hour), Interval: time.Minute}
+
+	tests := []struct {
+		name       string
+		locked     bool
+		hasHB      bool
+		lastIngest time.Duration // age of the last ingest; 0 means never
+		maxAge     time.Duration
+		wantWatch  string
+		wantIngest string
+	}{
+		{"running and current", true, true, 2 * time.Minute, 0, "ok", "ok"},
+		{"running but stale", true, true, 4 * time.Minute, 0, "ok", "fail"},
+		{"max-age overrides interval", true, true, 4 * time.Minute, 5 * time.Minute, "ok", "ok"},
+		{"never ingested", true, true, 0, 0, "ok", "fail"},
+		{"stale heartbeat", false, true, time.Minute, 0, "fail", "skip"},
+		{"locked by another command", true, false, 0, 0, "fail", "skip"},
+		{"not running", false, false, 0, 0, "fail", "skip"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hb := hb
+			if tt.lastIngest > 0 {
+				hb.LastIngest = now.Add(-tt.lastIngest)
+			}
+			if got := checkWatch(tt.locked, hb, tt.hasHB); got.Status != tt.wantWatch {
+				t.Errorf("checkWatch() = %+v, want status %s", got, tt.wantWatch)
+			}
+			if got := checkIngest(tt.locked, hb, tt.hasHB, tt.maxAge, now); got.Status != tt.wantIngest {
+				t.Errorf("checkIngest() = %+v, want status %s", got, tt.wantIngest)
+			}
+		})
+	}
+}
+
+func TestHealthCheckPerf(t *testing.T) {
+	last := overhead.Interval{DurationMs: 60000, CPUMs: 120, CPUPercent: 0.2, BytesRead: 2048}
+	total := overhead.Interval{DurationMs: 3600000, CPUMs: 900, CPUPercent: 0.025, BytesRead: 1 << 20}
+	hb := heartbeat{PID: 7, Overhead: &last, OverheadTotal: &total}
+
+	got := checkPerf(true, hb, true)
+	if got.Status != "ok" || got.Overhead != &last || got.OverheadTotal != &total {
+		t.Errorf("checkPerf() = %+v, want ok with the heartbeat's overhead", got)
+	}
+	if want := "cpu 120ms (0.2%), read 2.0 KB"; !strings.Contains(got.Message, want) {
+		t.Errorf("checkPerf() message = %q, want it to contain %q", got.Message, want)
+	}
+
+	if got := checkPerf(false, hb, true); got.Status != "skip" {
+		t.Errorf("checkPerf() when not running = %+v, want skip", got)
+	}
+	if got := checkPerf(true, heartbeat{PID: 7}, true); got.Status != "skip" {
+		t.Errorf("checkPerf() without overhead = %+v, want skip", got)
+	}
+}
+
+func TestHealthCheckServe(t *testing.T) {
+	var notReady atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/readyz" && notReady.Load() {
+			writeProbe(w, errors.New("not ready"))
+			return
+		}
+		writeProbe(w, nil)
+	}))
+	defer srv.Close()
+
+	cfg := config.Default()
+	cmd := &healthCommand{url: srv.URL, timeout: time.Second}
+
+	if got := cmd.checkServe(cfg); got.Status != "ok" {
+		t.Errorf("checkServe() = %+v, want ok", got)
+	}
+
+	notReady.Store(true)
+	if got := cmd.checkServe(cfg); got.Status != "fail" {
+		t.Errorf("checkServe() while not ready = %+v, want fail", got)
+	}
+
+	if got := (&healthCommand{}).checkServe(cfg); got.Status != "skip" {
+		t.Errorf("checkServe() without address = %+v, want skip", got)
+	}
+}
+
+func TestServeURL(t *testing.T) {
+	tests := map[string]string{
+		":8080":                  "http://127.0.0.1:8080",
+		"localhost:9000":         "http://localhost:9000",
+		"http://example.test/":   "http://example.test",
+		"https://example.test:1": "https://example.test:1",
+	}
+	for addr, want := range tests {
+		if got := serveURL(addr); got != want {
+			t.Errorf("serveURL(%q) = %q, want %q", addr, got, want)
+		}
+	}
+}
@@ -0,0 +1,337 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/0xmhha/token-monitor/cmd/internal/runtime"
+	"github.com/0xmhha/token-monitor/pkg/display"
+	"github.com/0xmhha/token-monitor/pkg/i18n"
+	"github.com/0xmhha/token-monitor/pkg/retention"
+	"github.com/0xmhha/token-monitor/pkg/rollup"
+)
+
+// retentionInterval is how often a running watch applies the retention
+// policy.
+const retentionInterval = 24 * time.Hour
+
+// historyCommand reports how long usage history is retained and where.
+type historyCommand struct {
+	globalOpts globalOptions
+}
+
+// runHistoryCommand runs the history command.
+func runHistoryCommand(globalOpts globalOptions, args []string) error {
+	cmd := &historyCommand{globalOpts: globalOpts}
+	return cmd.Execute(args)
+}
+
+// Execute dispatches a history subcommand.
+func (c *historyCommand) Execute(args []string) error {
+	if len(args) == 0 {
+		return c.showHelp()
+	}
+
+	switch args[0] {
+	case "coverage":
+		return c.runCoverage(args[1:])
+	case "prune":
+		return c.runPrune(args[1:])
+	case "help":
+		return c.showHelp()
+	default:
+		return fmt.Errorf("unknown history subcommand: %s", args[0])
+	}
+}
+
+// showHelp displays help for the history command.
+func (c *historyCommand) showHelp() error {
+	help := `History - Usage history retention
+
+Usage:
+  token-monitor history <subcommand> [flags]
+
+Subcommands:
+  coverage        Show which date ranges are backed by session files and
+                  which only by the rollups
+  prune           Remove data older than the retention configured per
+                  class (retention.rollup_days, baseline_days, focus_days)
+
+Coverage Flags:
+  -format       Output format (table, json)
+
+Prune Flags:
+  -dry-run      Count what would be removed without removing it
+  -format       Output format (table, json)
+
+Claude deletes old session files (cleanupPeriodDays, 30 days by default).
+The daily rollups kept by a running watch, report, or calendar outlive
+them, so reports and the calendar keep that history. Ranges marked
+"rollups only" can no longer be re-read from raw entries; stats, list,
+and session show cover only the files that remain.
+
+Nothing in the database expires unless a retention is configured; a
+running watch then prunes once a day. Pruned rollup days are not
+re-ingested from the session files that remain.
+`
+	fmt.Print(help)
+	return nil
+}
+
+// historyCoverage is the JSON document for history coverage.
+type historyCoverage struct {
+	Ranges []rollup.CoverageRange `json:"ranges"`
+
+	// Days is the number of days with usage; FileDays of them still have
+	// raw entries and StoreOnlyDays are kept only in the rollups.
+	Days          int `json:"days"`
+	FileDays      int `json:"file_days"`
+	StoreOnlyDays int `json:"store_only_days"`
+}
+
+// runCoverage compares the rollups with the raw session files.
+func (c *historyCommand) runCoverage(args []string) error {
+	fs := flag.NewFlagSet("history coverage", flag.ExitOnError)
+	format := fs.String("format", "table", "output format (table, json)")
+
+	if err := c.globalOpts.applyFlagDefaults(fs); err != nil {
+		return err
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if c.globalOpts.jsonOutput {
+		*format = "json"
+	}
+
+	rt := c.globalOpts.newRuntime("")
+	defer func() {
+		_ = rt.Close() //nolint:errcheck // best effort cleanup
+	}()
+
+	if _, err := rt.Config(); err != nil {
+		return err
+	}
+	if _, err := rt.Sessions(); err != nil {
+		return fmt.Errorf("rollup store unavailable (is watch running?): %w", err)
+	}
+	store, err := rt.Rollups()
+	if err != nil {
+		return err
+	}
+
+	// Catch up first so entries still in files are not reported as
+	// missing from the rollups.
+	ctx := context.Background()
+	if _, err := ingestRollups(ctx, rt, store); err != nil {
+		return err
+	}
+
+	rows, err := store.Rows("", "")
+	if err != nil {
+		return fmt.Errorf("failed to read rollups: %w", err)
+	}
+	stored := make(map[string]rollup.Totals)
+	for _, row := range rollup.Summarize(rows, []rollup.Dimension{rollup.DimDate}) {
+		stored[row.Date] = row.Totals
+	}
+
+	raw, err := rawDailyTotals(ctx, rt)
+	if err != nil {
+		return err
+	}
+	// Days removed by retention are not waiting to be rolled up.
+	pruned, err := store.PrunedBefore()
+	if err != nil {
+		return err
+	}
+	for date := range raw {
+		if date < pruned {
+			delete(raw, date)
+		}
+	}
+
+	coverage := historyCoverage{Ranges: rollup.Coverage(stored, raw)}
+	for _, r := range coverage.Ranges {
+		coverage.Days += r.Days
+		if r.Status == rollup.CoverageStoreOnly {
+			coverage.StoreOnlyDays += r.Days
+		} else {
+			coverage.FileDays += r.Days
+		}
+	}
+
+	if *format == "json" {
+		if coverage.Ranges == nil {
+			coverage.Ranges = []rollup.CoverageRange{}
+		}
+		return printJSON(coverage)
+	}
+
+	if len(coverage.Ranges) == 0 {
+		c.globalOpts.infof("%s\n", i18n.T("history.empty"))
+		return nil
+	}
+
+	header := []string{
+		i18n.T("history.from"),
+		i18n.T("history.to"),
+		i18n.T("history.days"),
+		i18n.T("history.coverage"),
+		i18n.T("report.entries"),
+		i18n.T("report.total"),
+		i18n.T("report.cost"),
+	}
+	table := make([][]string, 0, len(coverage.Ranges))
+	for _, r := range coverage.Ranges {
+		table = append(table, []string{
+			r.From,
+			r.To,
+			fmt.Sprintf("%d", r.Days),
+			i18n.T("history." + string(r.Status)),
+			display.FormatNumber(r.Totals.Entries),
+			display.FormatNumber(r.Totals.TotalTokens()),
+			display.FormatCost(r.Totals.CostUSD),
+		})
+	}
+	if err := display.WriteTable(os.Stdout, header, table, false); err != nil {
+		return err
+	}
+
+	c.globalOpts.output().Println()
+	c.globalOpts.output().Println(i18n.Tf("history.summary", coverage.Days, coverage.FileDays, coverage.StoreOnlyDays))
+	return nil
+}
+
+// historyPrune is the JSON document for history prune.
+type historyPrune struct {
+	DryRun  bool             `json:"dry_run"`
+	Policy  retention.Policy `json:"policy"`
+	Removed retention.Result `json:"removed"`
+}
+
+// runPrune applies the retention policy to the database.
+func (c *historyCommand) runPrune(args []string) error {
+	fs := flag.NewFlagSet("history prune", flag.ExitOnError)
+	dryRun := fs.Bool("dry-run", false, "count what would be removed without removing it")
+	format := fs.String("format", "table", "output format (table, json)")
+
+	if err := c.globalOpts.applyFlagDefaults(fs); err != nil {
+		return err
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if c.globalOpts.jsonOutput {
+		*format = "json"
+	}
+
+	rt := c.globalOpts.newRuntime("")
+	defer func() {
+		_ = rt.Close() //nolint:errcheck // best effort cleanup
+	}()
+
+	cfg, err := rt.Config()
+	if err != nil {
+		return err
+	}
+	if err := rt.Persistent(); err != nil {
+		return fmt.Errorf("database unavailable (is watch running?): %w", err)
+	}
+
+	policy := cfg.Retention.Policy()
+	result, err := applyRetention(rt, policy, time.Now(), *dryRun)
+	if err != nil {
+		return err
+	}
+
+	if *format == "json" {
+		return printJSON(historyPrune{DryRun: *dryRun, Policy: policy, Removed: result})
+	}
+	if !policy.Enabled() {
+		c.globalOpts.infof("%s\n", i18n.T("history.no_retention"))
+		return nil
+	}
+
+	removed := i18n.T("history.removed")
+	if *dryRun {
+		removed = i18n.T("history.would_remove")
+	}
+	header := []string{i18n.T("history.data"), i18n.T("history.kept"), removed}
+	table := [][]string{
+		{i18n.T("history.class_rollups"), keptDays(policy.RollupDays), display.FormatNumber(result.Rollups)},
+		{i18n.T("history.class_baselines"), keptDays(policy.BaselineDays), display.FormatNumber(result.Baselines)},
+		{i18n.T("history.class_focus"), keptDays(policy.FocusDays), display.FormatNumber(result.Focus)},
+	}
+	return display.WriteTable(os.Stdout, header, table, false)
+}
+
+// keptDays describes a retention period in days.
+func keptDays(days int) string {
+	if days <= 0 {
+		return i18n.T("history.forever")
+	}
+	return i18n.Tf("history.kept_days", days)
+}
+
+// applyRetention applies policy to the stores in rt's database. Only the
+// stores of classes with a retention are opened.
+func applyRetention(rt *runtime.Runtime, policy retention.Policy, now time.Time, dryRun bool) (retention.Result, error) {
+	var stores retention.Stores
+	var err error
+	if policy.RollupDays > 0 {
+		if stores.Rollups, err = rt.Rollups(); err != nil {
+			return retention.Result{}, err
+		}
+	}
+	if policy.BaselineDays > 0 {
+		if stores.Baselines, err = rt.Baselines(); err != nil {
+			return retention.Result{}, err
+		}
+	}
+	if policy.FocusDays > 0 {
+		if stores.Focus, err = rt.Focus(); err != nil {
+			return retention.Result{}, err
+		}
+	}
+	return retention.Apply(policy, stores, now, dryRun)
+}
+
+// rawDailyTotals sums the entries still present in session files by date,
+// keyed like the rollups. Unreadable files are skipped.
+func rawDailyTotals(ctx context.Context, rt *runtime.Runtime) (map[string]rollup.Totals, error) {
+	disc, err := rt.Discoverer()
+	if err != nil {
+		return nil, err
+	}
+	sessions, err := disc.Discover()
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover sessions: %w", err)
+	}
+
+	r, err := rt.NewReader()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = r.Close() //nolint:errcheck // best effort cleanup
+	}()
+
+	daily := make(map[string]rollup.Totals)
+	for _, session := range sessions {
+		entries, _, err := r.ReadFrom(ctx, session.FilePath, 0)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			date := entry.Timestamp.Format(rollup.DateLayout)
+			totals := daily[date]
+			totals.AddEntry(entry)
+			daily[date] = totals
+		}
+	}
+	return daily, nil
+}
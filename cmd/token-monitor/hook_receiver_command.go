@@ -0,0 +1,248 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/0xmhha/token-monitor/cmd/internal/runtime"
+	"github.com/0xmhha/token-monitor/pkg/apiclient"
+	"github.com/0xmhha/token-monitor/pkg/discovery"
+	"github.com/0xmhha/token-monitor/pkg/logger"
+	"github.com/0xmhha/token-monitor/pkg/rollup"
+	"github.com/0xmhha/token-monitor/pkg/session"
+)
+
+// hookTokenEnv names the environment variable holding the bearer token
+// hook-receiver sends to serve -http.
+const hookTokenEnv = "TOKEN_MONITOR_HOOK_TOKEN"
+
+// maxHookPayload bounds the hook payload read from stdin or a request.
+const maxHookPayload = 1 << 20
+
+// defaultHookTimeout bounds forwarding a payload to serve -http, so a
+// stopped server never stalls Claude Code.
+const defaultHookTimeout = 2 * time.Second
+
+// hookPayload is the subset of the JSON object Claude Code passes to hook
+// commands on stdin that token-monitor uses.
+type hookPayload struct {
+	SessionID      string `json:"session_id"`
+	TranscriptPath string `json:"transcript_path"`
+	HookEventName  string `json:"hook_event_name"`
+}
+
+// parseHookPayload decodes a hook payload. It needs the session ID or the
+// transcript path to find the session file.
+func parseHookPayload(data []byte) (hookPayload, error) {
+	var p hookPayload
+	if err := json.Unmarshal(data, &p); err != nil {
+		return p, fmt.Errorf("%w: %v", errInvalidHookPayload, err)
+	}
+	if p.SessionID == "" && p.TranscriptPath == "" {
+		return p, fmt.Errorf("%w: no session_id or transcript_path", errInvalidHookPayload)
+	}
+	return p, nil
+}
+
+// hookSessionFile finds the session file named by p among the discovered
+// sessions. Only files under the configured Claude directories are read,
+// whatever path the payload names.
+func hookSessionFile(sessions []discovery.SessionFile, p hookPayload) (discovery.SessionFile, bool) {
+	transcript := filepath.Clean(p.TranscriptPath)
+	for _, s := range sessions {
+		if p.TranscriptPath != "" && filepath.Clean(s.FilePath) == transcript {
+			return s, true
+		}
+		if p.TranscriptPath == "" && s.SessionID == p.SessionID {
+			return s, true
+		}
+	}
+	return discovery.SessionFile{}, false
+}
+
+// ingestHook folds the entries appended to the hook's session file since
+// the last ingest into store.
+func ingestHook(ctx context.Context, rt *runtime.Runtime, store rollup.Store, p hookPayload) (rollup.IngestStats, error) {
+	disc, err := rt.Discoverer()
+	if err != nil {
+		return rollup.IngestStats{}, err
+	}
+	sessions, err := disc.Discover()
+	if err != nil {
+		return rollup.IngestStats{}, fmt.Errorf("failed to discover sessions: %w", err)
+	}
+	file, ok := hookSessionFile(sessions, p)
+	if !ok {
+		return rollup.IngestStats{}, fmt.Errorf("%w: session %s", discovery.ErrNoSessionsFound, p.SessionID)
+	}
+
+	r, err := rt.NewReader()
+	if err != nil {
+		return rollup.IngestStats{}, err
+	}
+	defer func() {
+		_ = r.Close() //nolint:errcheck // best effort cleanup
+	}()
+
+	stats, err := store.Ingest(ctx, []discovery.SessionFile{file}, r)
+	if err != nil {
+		return stats, fmt.Errorf("failed to update rollups: %w", err)
+	}
+	return stats, nil
+}
+
+// hookHandler serves POST /hooks for serve -http. The rollup store is
+// opened on the first payload, so serve does not hold the database
+// unless hooks are used.
+func hookHandler(rt *runtime.Runtime, log logger.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		data, err := io.ReadAll(io.LimitReader(r.Body, maxHookPayload))
+		if err != nil {
+			http.Error(w, "failed to read body", http.StatusBadRequest)
+			return
+		}
+		p, err := parseHookPayload(data)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		store, err := rt.Rollups()
+		if err == nil {
+			err = rt.Persistent()
+		}
+		if err != nil {
+			log.Warn("hook ingest unavailable", "error", err)
+			http.Error(w, "rollup store unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		stats, err := ingestHook(r.Context(), rt, store, p)
+		switch {
+		case errors.Is(err, discovery.ErrNoSessionsFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		case err != nil:
+			log.Warn("hook ingest failed", "session", p.SessionID, "error", err)
+			http.Error(w, "ingest failed", http.StatusInternalServerError)
+			return
+		}
+
+		log.Debug("hook ingested", "session", p.SessionID, "event", p.HookEventName, "entries", stats.Entries)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(apiclient.HookResult{SessionID: p.SessionID, Entries: stats.Entries}) //nolint:errcheck // client may have gone
+	})
+}
+
+// hookReceiverCommand ingests usage pushed by a Claude Code hook.
+type hookReceiverCommand struct {
+	url        string
+	timeout    time.Duration
+	globalOpts globalOptions
+}
+
+// runHookReceiverCommand runs the hook-receiver command.
+func runHookReceiverCommand(globalOpts globalOptions, args []string) error {
+	fs := flag.NewFlagSet("hook-receiver", flag.ExitOnError)
+	url := fs.String("url", "", "serve -http base URL to forward the payload to (default: ingest locally)")
+	timeout := fs.Duration("timeout", defaultHookTimeout, "timeout for forwarding the payload")
+
+	if err := globalOpts.applyFlagDefaults(fs); err != nil {
+		return err
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cmd := &hookReceiverCommand{url: *url, timeout: *timeout, globalOpts: globalOpts}
+	return cmd.Execute(os.Stdin)
+}
+
+// Execute reads one hook payload from in and ingests it locally or
+// forwards it to serve -http.
+func (c *hookReceiverCommand) Execute(in io.Reader) error {
+	data, err := io.ReadAll(io.LimitReader(in, maxHookPayload))
+	if err != nil {
+		return fmt.Errorf("failed to read hook payload: %w", err)
+	}
+	p, err := parseHookPayload(data)
+	if err != nil {
+		return err
+	}
+
+	if c.url != "" {
+		return c.forward(data)
+	}
+
+	rt := c.globalOpts.newRuntime("")
+	defer func() {
+		_ = rt.Close() //nolint:errcheck // best effort cleanup
+	}()
+
+	if _, err := rt.Config(); err != nil {
+		return err
+	}
+	log, err := rt.Logger()
+	if err != nil {
+		return err
+	}
+	store, err := rt.Rollups()
+	if errors.Is(err, session.ErrDatabaseLocked) {
+		// watch holds the database and ingests on its own schedule.
+		log.Debug("database locked, leaving the session to watch", "session", p.SessionID)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	stats, err := ingestHook(context.Background(), rt, store, p)
+	if err != nil {
+		return err
+	}
+	log.Debug("hook ingested", "session", p.SessionID, "event", p.HookEventName, "entries", stats.Entries)
+	return nil
+}
+
+// forward posts the payload to the /hooks endpoint of serve -http.
+func (c *hookReceiverCommand) forward(data []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	endpoint := strings.TrimSuffix(c.url, "/") + "/hooks"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("invalid -url: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token := os.Getenv(hookTokenEnv); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to forward hook payload: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close() //nolint:errcheck // best effort cleanup
+	}()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512)) //nolint:errcheck // best effort detail
+		return fmt.Errorf("failed to forward hook payload: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/0xmhha/token-monitor/cmd/internal/runtime"
+	"github.com/0xmhha/token-monitor/pkg/discovery"
+	"github.com/0xmhha/token-monitor/pkg/logger"
+)
+
+func TestParseHookPayload(t *testing.T) {
+	p, err := parseHookPayload([]byte(`{"session_id":"abc","transcript_path":"/p/abc.jsonl","hook_event_name":"Stop","cwd":"/w"}`))
+	if err != nil {
+		t.Fatalf("parseHookPayload() error = %v", err)
+	}
+	if p.SessionID != "abc" || p.TranscriptPath != "/p/abc.jsonl" || p.HookEventName != "Stop" {
+		t.Errorf("parseHookPayload() = %+v", p)
+	}
+
+	for _, data := range []string{`not json`, `{"hook_event_name":"Stop"}`} {
+		if _, err := parseHookPayload([]byte(data)); !errors.Is(err, errInvalidHookPayload) {
+			t.Errorf("parseHookPayload(%s) error = %v, want errInvalidHookPayload", data, err)
+		}
+	}
+}
+
+func TestHookSessionFile(t *testing.T) {
+	sessions := []discovery.SessionFile{
+		{SessionID: "aaa", FilePath: "/claude/projects/app/aaa.jsonl"},
+		{SessionID: "bbb", FilePath: "/claude/projects/app/bbb.jsonl"},
+	}
+
+	tests := []struct {
+		name    string
+		payload hookPayload
+		want    string
+	}{
+		{"by transcript", hookPayload{SessionID: "aaa", TranscriptPath: "/claude/projects/app/../app/bbb.jsonl"}, "bbb"},
+		{"by session id", hookPayload{SessionID: "aaa"}, "aaa"},
+		{"outside Claude dirs", hookPayload{SessionID: "aaa", TranscriptPath: "/etc/aaa.jsonl"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := hookSessionFile(sessions, tt.payload)
+			if ok != (tt.want != "") || got.SessionID != tt.want {
+				t.Errorf("hookSessionFile() = %q, %v; want %q", got.SessionID, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestHookReceiverForward(t *testing.T) {
+	var auth, body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/hooks" {
+			http.NotFound(w, r)
+			return
+		}
+		auth = r.Header.Get("Authorization")
+		data, _ := io.ReadAll(r.Body) //nolint:errcheck // test server
+		body = string(data)
+	}))
+	defer srv.Close()
+
+	t.Setenv(hookTokenEnv, "h00ks")
+	payload := `{"session_id":"abc"}`
+	cmd := &hookReceiverCommand{url: srv.URL + "/", timeout: defaultHookTimeout}
+	if err := cmd.Execute(strings.NewReader(payload)); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if auth != "Bearer h00ks" || body != payload {
+		t.Errorf("forwarded auth = %q, body = %q", auth, body)
+	}
+
+	cmd.url = srv.URL + "/missing"
+	if err := cmd.Execute(strings.NewReader(payload)); err == nil {
+		t.Error("Execute() = nil for a failing endpoint")
+	}
+}
+
+func TestHookHandlerConcurrentPosts(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("CLAUDE_CONFIG_DIR", dir)
+	t.Setenv("TOKEN_MONITOR_DB", filepath.Join(dir, "sessions.db"))
+	t.Setenv("TOKEN_MONITOR_CACHE_DIR", filepath.Join(dir, "cache"))
+	t.Setenv("TOKEN_MONITOR_LOG_LEVEL", "error")
+
+	const sessionID = "a1b2c3d4-e5f6-7890-abcd-ef1234567890"
+	project := filepath.Join(dir, "project-a")
+	if err := os.MkdirAll(project, 0700); err != nil {
+		t.Fatal(err)
+	}
+	var lines strings.Builder
+	for i := 0; i < 30; i++ {
+		fmt.Fprintf(&lines, `{"timestamp":"2025-11-01T10:%02d:00Z","sessionId":%q,"message":{"id":"m%d","model":"claude-sonnet-4","usage":{"input_tokens":10,"output_tokens":5}}}`+"\n",
+			i, sessionID, i)
+	}
+	if err := os.WriteFile(filepath.Join(project, sessionID+".jsonl"), []byte(lines.String()), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	rt := runtime.New(runtime.Options{NoCache: true})
+	defer func() { _ = rt.Close() }() //nolint:errcheck
+	h := hookHandler(rt, logger.New(logger.Config{Level: "error", Writer: io.Discard}))
+
+	// Hooks of parallel tool calls arrive together; each entry must still
+	// be counted once.
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rec := httptest.NewRecorder()
+			body := strings.NewReader(`{"session_id":"` + sessionID + `","hook_event_name":"PostToolUse"}`)
+			h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/hooks", body))
+			if rec.Code != http.StatusOK {
+				t.Errorf("POST /hooks = %d: %s", rec.Code, rec.Body.String())
+			}
+		}()
+	}
+	wg.Wait()
+
+	store, err := rt.Rollups()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows, err := store.Rows("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries := 0
+	for _, row := range rows {
+		entries += row.Entries
+	}
+	if entries != 30 {
+		t.Errorf("rollups hold %d entries, want 30", entries)
+	}
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// logFollowInterval is how often logs -f checks the log file for new lines.
+const logFollowInterval = 500 * time.Millisecond
+
+// maxLogLineSize bounds a single log line read by logs.
+const maxLogLineSize = 1024 * 1024
+
+// logsCommand prints the end of the tool's own log file.
+type logsCommand struct {
+	lines      int
+	follow     bool
+	minLevel   *slog.Level // nil shows every line
+	path       bool
+	globalOpts globalOptions
+}
+
+// runLogsCommand parses flags and runs the logs command.
+func runLogsCommand(globalOpts globalOptions, args []string) error {
+	fs := flag.NewFlagSet("logs", flag.ExitOnError)
+	lines := fs.Int("n", 10, "number of lines to show from the end of the log")
+	follow := fs.Bool("f", false, "keep printing lines as they are written (Ctrl+C to stop)")
+	level := fs.String("level", "", "show only lines at or above this level (debug, info, warn, error)")
+	path := fs.Bool("path", false, "print the log destination and exit")
+
+	if err := globalOpts.applyFlagDefaults(fs); err != nil {
+		return err
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *lines < 0 {
+		return fmt.Errorf("invalid -n %d (want 0 or more)", *lines)
+	}
+
+	cmd := &logsCommand{
+		lines:      *lines,
+		follow:     *follow,
+		path:       *path,
+		globalOpts: globalOpts,
+	}
+	if *level != "" {
+		var minLevel slog.Level
+		if err := minLevel.UnmarshalText([]byte(*level)); err != nil {
+			return fmt.Errorf("invalid -level %q (want debug, info, warn, or error)", *level)
+		}
+		cmd.minLevel = &minLevel
+	}
+	return cmd.Execute()
+}
+
+// Execute prints the log destination, or the end of the log file.
+func (c *logsCommand) Execute() error {
+	cfg, err := c.globalOpts.newRuntime("").Config()
+	if err != nil {
+		return err
+	}
+
+	output := cfg.Logging.Output
+	toFile := output != "" && !strings.EqualFold(output, "stdout") && !strings.EqualFold(output, "stderr")
+	if c.path {
+		if !toFile {
+			output = strings.ToLower(output)
+			if output == "" {
+				output = "stderr"
+			}
+			c.globalOpts.output().Printf("%s (logging.output is not a file)\n", output)
+			return nil
+		}
+		c.globalOpts.output().Println(expandHome(output))
+		return nil
+	}
+	if !toFile {
+		return fmt.Errorf("%w: logging.output is %q; set it to a file path (token-monitor config set logging.output ~/.config/token-monitor/token-monitor.log)", errNoLogFile, output)
+	}
+
+	path := expandHome(output)
+	f, err := os.Open(path) //nolint:gosec // path from user config
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("%w: %s does not exist yet", errNoLogFile, path)
+		}
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	lines, err := tailLog(f, c.lines, c.minLevel)
+	if err != nil {
+		_ = f.Close() //nolint:errcheck // read-only file
+		return fmt.Errorf("failed to read log file: %w", err)
+	}
+	out := c.globalOpts.output()
+	for _, line := range lines {
+		out.Println(line)
+	}
+
+	// tailLog read to the end of the file.
+	offset, err := f.Seek(0, io.SeekCurrent)
+	if err != nil || !c.follow {
+		_ = f.Close() //nolint:errcheck // read-only file
+		return err
+	}
+	return c.followLog(path, f, offset)
+}
+
+// followLog prints lines appended to the log file f after offset until
+// interrupted, and closes f. A truncated or replaced file (log rotation)
+// is read again from the start.
+func (c *logsCommand) followLog(path string, f *os.File, offset int64) error {
+	defer func() {
+		_ = f.Close() //nolint:errcheck // read-only file
+	}()
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
+
+	ticker := time.NewTicker(logFollowInterval)
+	defer ticker.Stop()
+
+	out := c.globalOpts.output()
+	filter := logFilter{minLevel: c.minLevel}
+	var partial []byte
+	for {
+		select {
+		case <-sigChan:
+			return nil
+		case <-ticker.C:
+		}
+
+		info, pathErr := os.Stat(path)
+		current, err := f.Stat()
+		switch {
+		case err != nil:
+			return fmt.Errorf("failed to read log file: %w", err)
+		case pathErr == nil && !os.SameFile(info, current):
+			reopened, err := os.Open(path) //nolint:gosec // path from user config
+			if err != nil {
+				continue
+			}
+			_ = f.Close() //nolint:errcheck // read-only file
+			f, offset, partial = reopened, 0, nil
+		case current.Size() < offset:
+			offset, partial = 0, nil
+		}
+
+		data, err := io.ReadAll(io.NewSectionReader(f, offset, 1<<62))
+		if err != nil {
+			return fmt.Errorf("failed to read log file: %w", err)
+		}
+		offset += int64(len(data))
+
+		data = append(partial, data...)
+		end := bytes.LastIndexByte(data, '\n')
+		if end < 0 {
+			partial = data
+			continue
+		}
+		for _, line := range strings.Split(string(data[:end]), "\n") {
+			if filter.keep(line) {
+				out.Println(line)
+			}
+		}
+		partial = append([]byte(nil), data[end+1:]...)
+	}
+}
+
+// tailLog reads r to the end and returns its last n lines at or above
+// minLevel.
+func tailLog(r io.Reader, n int, minLevel *slog.Level) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLogLineSize)
+
+	filter := logFilter{minLevel: minLevel}
+	var lines []string
+	for scanner.Scan() {
+		line := scanner.Text()
+		if n == 0 || !filter.keep(line) {
+			continue
+		}
+		if len(lines) == n {
+			lines = lines[1:]
+		}
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
+
+// logFilter selects log lines by level. Lines without a level, such as
+// the continuation of a multi-line message, go with the line before.
+type logFilter struct {
+	minLevel *slog.Level
+	last     bool
+}
+
+// keep reports whether line is shown.
+func (f *logFilter) keep(line string) bool {
+	if f.minLevel == nil {
+		return true
+	}
+	if level, ok := lineLevel(line); ok {
+		f.last = level >= *f.minLevel
+	}
+	return f.last
+}
+
+// lineLevel returns the level of a log line in the text or json format.
+func lineLevel(line string) (slog.Level, bool) {
+	var level slog.Level
+	if strings.HasPrefix(line, "{") {
+		var entry struct {
+			Level string `json:"level"`
+		}
+		if json.Unmarshal([]byte(line), &entry) != nil || entry.Level == "" {
+			return level, false
+		}
+		return level, level.UnmarshalText([]byte(entry.Level)) == nil
+	}
+
+	i := strings.Index(line, "level=")
+	if i < 0 || (i > 0 && line[i-1] != ' ') {
+		return level, false
+	}
+	value, _, _ := strings.Cut(line[i+len("level="):], " ")
+	return level, level.UnmarshalText([]byte(value)) == nil
+}
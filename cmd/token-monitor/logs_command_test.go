@@ -0,0 +1,54 @@
+package main
+
+import (
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestLineLevel(t *testing.T) {
+	tests := []struct {
+		line string
+		want slog.Level
+		ok   bool
+	}{
+		{`time=2025-11-02T10:00:00Z level=WARN msg="directory not found"`, slog.LevelWarn, true},
+		{`time=2025-11-02T10:00:00Z level=ERROR+2 msg=boom`, slog.LevelError + 2, true},
+		{`{"time":"2025-11-02T10:00:00Z","level":"DEBUG","msg":"scan"}`, slog.LevelDebug, true},
+		{`  at main.go:12`, 0, false},
+		{`msg="sublevel=WARN"`, 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := lineLevel(tt.line)
+		if ok != tt.ok || (ok && got != tt.want) {
+			t.Errorf("lineLevel(%q) = %v, %v; want %v, %v", tt.line, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestTailLog(t *testing.T) {
+	log := strings.Join([]string{
+		"time=t1 level=INFO msg=start",
+		"time=t2 level=ERROR msg=failed",
+		"  detail of the failure",
+		"time=t3 level=DEBUG msg=scan",
+		"time=t4 level=WARN msg=slow",
+		"time=t5 level=INFO msg=done",
+	}, "\n") + "\n"
+
+	lines, err := tailLog(strings.NewReader(log), 2, nil)
+	if err != nil || strings.Join(lines, "|") != "time=t4 level=WARN msg=slow|time=t5 level=INFO msg=done" {
+		t.Errorf("tailLog(2) = %q, %v; want the last two lines", lines, err)
+	}
+
+	warn := slog.LevelWarn
+	lines, err = tailLog(strings.NewReader(log), 10, &warn)
+	want := "time=t2 level=ERROR msg=failed|  detail of the failure|time=t4 level=WARN msg=slow"
+	if err != nil || strings.Join(lines, "|") != want {
+		t.Errorf("tailLog(warn) = %q, %v; want errors and warnings with their continuation lines", lines, err)
+	}
+
+	if lines, _ := tailLog(strings.NewReader(log), 0, nil); len(lines) != 0 {
+		t.Errorf("tailLog(0) = %q, want no lines", lines)
+	}
+}
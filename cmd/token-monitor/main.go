@@ -11,11 +11,27 @@ import (
 	"strings"
 	"time"
 
+	"golang.org/x/term"
+
+	"github.com/0xmhha/token-monitor/cmd/internal/runtime"
+	"github.com/0xmhha/token-monitor/pkg/analysis"
+	"github.com/0xmhha/token-monitor/pkg/config"
+	"github.com/0xmhha/token-monitor/pkg/display"
+	"github.com/0xmhha/token-monitor/pkg/i18n"
+	"github.com/0xmhha/token-monitor/pkg/logger"
+	"github.com/0xmhha/token-monitor/pkg/session"
 	"github.com/0xmhha/token-monitor/pkg/tui"
 )
 
-// version is set during build time.
-var version = "dev"
+// Build metadata, set at build time with -ldflags "-X main.version=...
+// -X main.commit=... -X main.date=..." (see the Makefile and
+// .goreleaser.yml). Builds without them fall back to the module version
+// and VCS stamp Go records in the binary (see currentBuild).
+var (
+	version = "dev"
+	commit  = ""
+	date    = ""
+)
 
 // globalOptions holds global flags that apply to all commands.
 type globalOptions struct {
@@ -23,15 +39,177 @@ type globalOptions struct {
 	logLevel   string
 	jsonOutput bool
 	noColor    bool
+	quiet      bool
+	accessible bool
+	ascii      bool
+	units      string // display.units, the default for -units
+}
+
+// resolveLogLevel returns the log level to use for a command logging to
+// output (logging.output). An explicit -log-level wins; quiet mode raises
+// the level to "error" for logs written to the terminal streams, but not
+// for a log file, which daemons run without a terminal still need;
+// otherwise the command's default is used.
+func (g globalOptions) resolveLogLevel(defaultLevel, output string) string {
+	if g.logLevel != "" {
+		return g.logLevel
+	}
+	if g.quiet && isStdStream(output) {
+		return "error"
+	}
+	return defaultLevel
+}
+
+// isStdStream reports whether the log output is stdout or stderr, the
+// default.
+func isStdStream(output string) bool {
+	return output == "" || strings.EqualFold(output, "stdout") || strings.EqualFold(output, "stderr")
+}
+
+// output returns the command output streams: results to stdout,
+// diagnostics to stderr.
+func (g globalOptions) output() display.Output {
+	out := display.StdOutput(g.quiet)
+	out.ASCII = g.ascii
+	return out
+}
+
+// infof prints a non-essential informational message to stderr.
+// Nothing is printed in quiet mode.
+func (g globalOptions) infof(format string, args ...interface{}) {
+	g.output().Infof(format, args...)
+}
+
+// decorate prefixes title with icon, or returns title alone in accessible
+// mode, where emoji would be read out by name, and in ASCII mode.
+func (g globalOptions) decorate(icon, title string) string {
+	if g.accessible || g.ascii {
+		return title
+	}
+	return icon + " " + title
+}
+
+// displayConfig returns the display settings from configuration. When the
+// configuration cannot be loaded, the locale still comes from
+// TOKEN_MONITOR_LANG, so a broken config file is reported in the user's
+// language.
+func (g globalOptions) displayConfig() config.DisplayConfig {
+	cfg, err := config.NewLoader(g.configPath).Load()
+	if err != nil {
+		return config.DisplayConfig{Locale: os.Getenv("TOKEN_MONITOR_LANG")}
+	}
+	return cfg.Display
+}
+
+// priceRules returns the configured pricing overrides, or none when the
+// configuration cannot be loaded.
+func (g globalOptions) priceRules() analysis.PriceRules {
+	cfg, err := config.NewLoader(g.configPath).Load()
+	if err != nil {
+		return nil
+	}
+	return cfg.Pricing.PriceRules()
+}
+
+// newLogger creates a command logger from configuration.
+// Logs configured for stdout are redirected to stderr so they never
+// interleave with command results.
+func (g globalOptions) newLogger(cfg *config.Config, defaultLevel string) logger.Logger {
+	logCfg := logger.Config{
+		Level:  g.resolveLogLevel(defaultLevel, cfg.Logging.Output),
+		Format: cfg.Logging.Format,
+		Output: cfg.Logging.Output,
+	}
+	if strings.EqualFold(cfg.Logging.Output, "stdout") {
+		logCfg.Writer = g.output().Diag
+	}
+	return logger.New(logCfg)
+}
+
+// newRuntime returns the shared command runtime. Its logger uses
+// defaultLevel, or the configured level when defaultLevel is empty.
+func (g globalOptions) newRuntime(defaultLevel string) *runtime.Runtime {
+	return runtime.New(g.runtimeOptions(defaultLevel))
+}
+
+// runtimeOptions returns the runtime options newRuntime uses, for commands
+// that adjust them before creating the runtime.
+func (g globalOptions) runtimeOptions(defaultLevel string) runtime.Options {
+	return runtime.Options{
+		ConfigPath: g.configPath,
+		NewLogger: func(cfg *config.Config) logger.Logger {
+			level := defaultLevel
+			if level == "" {
+				level = cfg.Logging.Level
+			}
+			return g.newLogger(cfg, level)
+		},
+		NoCache: noCacheMode(),
+		Force:   forceMode(),
+	}
+}
+
+// applyFlagDefaults seeds fs with the defaults configured for its command
+// (defaults.<command> in the config file). It must be called before
+// fs.Parse so explicitly passed flags still win. Config load errors are
+// ignored here; the command reports them when it loads config itself.
+func (g globalOptions) applyFlagDefaults(fs *flag.FlagSet) error {
+	cfg, err := config.NewLoader(g.configPath).Load()
+	if err != nil {
+		return nil
+	}
+	return setFlagDefaults(fs, cfg.Defaults.For(defaultsKey(fs.Name())))
+}
+
+// defaultsKey maps a flag set name to its config defaults key
+// ("session list" -> "session_list").
+func defaultsKey(name string) string {
+	return strings.ReplaceAll(name, " ", "_")
+}
+
+// setFlagDefaults applies flag-name/value defaults to fs.
+func setFlagDefaults(fs *flag.FlagSet, defaults map[string]string) error {
+	for name, value := range defaults {
+		if fs.Lookup(name) == nil {
+			return fmt.Errorf("invalid default %s.%s: unknown flag", defaultsKey(fs.Name()), name)
+		}
+		if err := fs.Set(name, value); err != nil {
+			return fmt.Errorf("invalid default %s.%s: %w", defaultsKey(fs.Name()), name, err)
+		}
+	}
+	return nil
 }
 
 func main() {
 	if err := run(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		if jsonMode() {
+			// Machine-readable failure on stdout for wrappers parsing --json output.
+			_ = writeJSONError(os.Stdout, err) //nolint:errcheck // exiting anyway
+		} else {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
 		os.Exit(1)
 	}
 }
 
+// jsonMode reports whether the global -json flag was set.
+func jsonMode() bool {
+	f := flag.Lookup("json")
+	return f != nil && f.Value.String() == "true"
+}
+
+// noCacheMode reports whether the global -no-cache flag was set.
+func noCacheMode() bool {
+	f := flag.Lookup("no-cache")
+	return f != nil && f.Value.String() == "true"
+}
+
+// forceMode reports whether the global -force flag was set.
+func forceMode() bool {
+	f := flag.Lookup("force")
+	return f != nil && f.Value.String() == "true"
+}
+
 // run executes the main application logic.
 func run() error {
 	// Define global flags.
@@ -40,13 +218,19 @@ func run() error {
 	logLevel := flag.String("log-level", "", "log level (debug, info, warn, error)")
 	jsonOutput := flag.Bool("json", false, "output in JSON format (applies to all commands)")
 	noColor := flag.Bool("no-color", false, "disable colored output")
+	accessible := flag.Bool("accessible", false, "screen-reader friendly output: labeled lines, no box drawing or emoji")
+	ascii := flag.Bool("ascii", false, "ASCII-only output: plain table borders and no emoji")
+	quiet := flag.Bool("quiet", false, "suppress informational output and logging to the terminal (implied when stdout is not a terminal)")
+	flag.Bool("no-cache", false, "disable the discovery cache and rescan all directories")
+	flag.Bool("force", false, "read files past the large-file limits (performance.max_file_size_mb, performance.max_entries_per_read)")
+	noPager := flag.Bool("no-pager", false, "do not pipe long output into $PAGER")
 
 	// Parse command.
 	flag.Parse()
 
 	// Handle version flag.
 	if *showVersion {
-		fmt.Printf("token-monitor %s\n", version)
+		fmt.Println(currentBuild())
 		return nil
 	}
 
@@ -56,8 +240,22 @@ func run() error {
 		logLevel:   *logLevel,
 		jsonOutput: *jsonOutput,
 		noColor:    *noColor,
+		quiet:      *quiet || !term.IsTerminal(int(os.Stdout.Fd())),
+		accessible: *accessible,
+		ascii:      *ascii,
 	}
 
+	// Pick the output language and style before any command prints.
+	displayCfg := globalOpts.displayConfig()
+	i18n.SetLocale(i18n.Detect(displayCfg.Locale))
+	globalOpts.accessible = globalOpts.accessible || displayCfg.Accessible
+	// Accessible output never uses box drawing either.
+	globalOpts.ascii = globalOpts.ascii || displayCfg.ASCII || globalOpts.accessible
+	globalOpts.units = displayCfg.Units
+
+	// Apply cost overrides before any command estimates costs.
+	analysis.SetPriceRules(globalOpts.priceRules())
+
 	// Get command.
 	args := flag.Args()
 	if len(args) == 0 {
@@ -65,15 +263,32 @@ func run() error {
 		return runTUICommand(globalOpts, nil)
 	}
 
+	// Expand user-defined aliases before dispatching.
+	if !builtinCommands[args[0]] {
+		expanded, err := expandAliases(globalOpts.loadAliases(), args)
+		if err != nil {
+			return err
+		}
+		args = expanded
+	}
+
 	command := args[0]
 
+	// Time the command for opt-in telemetry.
+	defer globalOpts.recordTelemetry(command, time.Now())
+
+	// Page long output on a terminal, as git does.
+	if !*noPager && pagedCommand(args) && term.IsTerminal(int(os.Stdout.Fd())) {
+		defer startPager()()
+	}
+
 	switch command {
 	case "tui":
 		return runTUICommand(globalOpts, args[1:])
 	case "stats":
 		return runStatsCommand(globalOpts, args[1:])
 	case "list":
-		return runListCommand(globalOpts)
+		return runListCommand(globalOpts, args[1:])
 	case "watch":
 		return runWatchCommand(globalOpts, args[1:])
 	case "session":
@@ -88,10 +303,54 @@ func run() error {
 		return runServeCommand(globalOpts, args[1:])
 	case "install":
 		return runInstallCommand(globalOpts, args[1:])
+	case "repl":
+		return runReplCommand(globalOpts, args[1:])
+	case "report":
+		return runReportCommand(globalOpts, args[1:])
+	case "fsck":
+		return runFsckCommand(globalOpts, args[1:])
+	case "health":
+		return runHealthCommand(globalOpts, args[1:])
+	case "debug":
+		return runDebugCommand(globalOpts, args[1:])
+	case "logs":
+		return runLogsCommand(globalOpts, args[1:])
+	case "baseline":
+		return runBaselineCommand(globalOpts, args[1:])
+	case "calendar":
+		return runCalendarCommand(globalOpts, args[1:])
+	case "today":
+		return runTodayCommand(globalOpts, args[1:])
+	case "dump":
+		return runDumpCommand(globalOpts, args[1:])
+	case "history":
+		return runHistoryCommand(globalOpts, args[1:])
+	case "project":
+		return runProjectCommand(globalOpts, args[1:])
+	case "hook-receiver":
+		return runHookReceiverCommand(globalOpts, args[1:])
+	case "notify":
+		return runNotifyCommand(globalOpts, args[1:])
+	case "annotate":
+		return runAnnotateCommand(globalOpts, args[1:])
+	case "focus":
+		return runFocusCommand(globalOpts, args[1:])
+	case "tickets":
+		return runTicketsCommand(globalOpts, args[1:])
+	case "team":
+		return runTeamCommand(globalOpts, args[1:])
+	case "whatif":
+		return runWhatifCommand(globalOpts, args[1:])
+	case "api":
+		return runAPICommand(globalOpts, args[1:])
+	case "telemetry":
+		return runTelemetryCommand(globalOpts, args[1:])
+	case "version":
+		return runVersionCommand(globalOpts, args[1:])
 	case "help":
 		return showUsage()
 	default:
-		return fmt.Errorf("unknown command: %s", command)
+		return fmt.Errorf("%w: %s", errUnknownCommand, command)
 	}
 }
 
@@ -100,6 +359,11 @@ func runTUICommand(globalOpts globalOptions, args []string) error {
 	fs := flag.NewFlagSet("tui", flag.ExitOnError)
 	sessionID := fs.String("session", "", "monitor specific session ID")
 	refresh := fs.Duration("refresh", time.Second, "refresh interval (e.g., 1s, 500ms)")
+	eco := fs.Bool("eco", false, "low-power mode: slower refresh, no percentiles, less re-discovery")
+
+	if err := globalOpts.applyFlagDefaults(fs); err != nil {
+		return err
+	}
 
 	if args != nil {
 		if err := fs.Parse(args); err != nil {
@@ -110,23 +374,72 @@ func runTUICommand(globalOpts globalOptions, args []string) error {
 	return tui.New(tui.Options{
 		SessionID: *sessionID,
 		Refresh:   *refresh,
-		LogLevel:  globalOpts.logLevel,
+		LogLevel:  globalOpts.resolveLogLevel("", ""),
+		NoCache:   noCacheMode(),
+		Force:     forceMode(),
+		LowPower:  *eco,
+		ASCII:     globalOpts.ascii,
 	})
 }
 
 // runStatsCommand runs the stats command.
 func runStatsCommand(globalOpts globalOptions, args []string) error {
+	cmd, err := parseStatsCommand(globalOpts, args, flag.ExitOnError)
+	if err != nil {
+		return err
+	}
+	return cmd.Execute()
+}
+
+// parseStatsCommand parses stats flags into a statsCommand.
+// The REPL reuses it with flag.ContinueOnError so bad flags don't exit.
+func parseStatsCommand(globalOpts globalOptions, args []string, handling flag.ErrorHandling) (*statsCommand, error) {
 	// Define stats-specific flags.
-	fs := flag.NewFlagSet("stats", flag.ExitOnError)
-	sessionID := fs.String("session", "", "filter by session ID")
-	model := fs.String("model", "", "filter by model name")
-	groupBy := fs.String("group-by", "", "group by dimensions (comma-separated: model,session,date,hour)")
+	fs := flag.NewFlagSet("stats", handling)
+	sessionID := fs.String("session", "", "filter by session ID or name")
+	model := fs.String("model", "", "filter by model (comma-separated globs or /regex/)")
+	groupBy := fs.String("group-by", "", "group by dimensions (comma-separated: model,session,date,hour,source,subagent)")
 	topN := fs.Int("top", 0, "show top N sessions by token usage")
 	format := fs.String("format", "table", "output format (table, json, simple)")
 	compact := fs.Bool("compact", false, "compact output")
-
+	showCost := fs.Bool("cost", false, "show estimated cost column in grouped output")
+	showRate := fs.Bool("rate", false, "show requests/day column in grouped output")
+	units := fs.String("units", globalOpts.units, "primary table measure (tokens, k, cost)")
+	detailed := fs.Bool("detailed", false, "also show the measures -units leaves out")
+	againstBaseline := fs.String("against-baseline", "", "compare with a saved baseline (see \"baseline save\")")
+	tail := fs.Int("tail", 0, "read only the last N entries of each session file (approximate, fast on large files)")
+	state := fs.String("state", "", "only sessions in lifecycle state: active, idle, archived")
+	verify := fs.Bool("verify", false, "recompute totals from the session files and report drift in stats and rollups")
+
+	if err := globalOpts.applyFlagDefaults(fs); err != nil {
+		return nil, err
+	}
 	if err := fs.Parse(args); err != nil {
-		return err
+		return nil, err
+	}
+
+	if *againstBaseline != "" && (*sessionID != "" || *groupBy != "" || *topN > 0 || *state != "") {
+		return nil, fmt.Errorf("-against-baseline cannot be combined with -session, -group-by, -top, or -state")
+	}
+
+	var sessionState session.State
+	if *state != "" {
+		var err error
+		if sessionState, err = session.ParseState(*state); err != nil {
+			return nil, err
+		}
+	}
+
+	if *tail < 0 {
+		return nil, fmt.Errorf("-tail must be a non-negative number of entries")
+	}
+	if *verify && (*tail > 0 || *againstBaseline != "") {
+		return nil, fmt.Errorf("-verify cannot be combined with -tail or -against-baseline")
+	}
+
+	tableUnits, err := display.ParseUnits(*units)
+	if err != nil {
+		return nil, err
 	}
 
 	// Parse group-by dimensions.
@@ -144,35 +457,43 @@ func runStatsCommand(globalOpts globalOptions, args []string) error {
 		outputFormat = "json"
 	}
 
-	cmd := &statsCommand{
+	return &statsCommand{
 		sessionID:  *sessionID,
 		model:      *model,
 		groupBy:    dimensions,
 		topN:       *topN,
 		format:     outputFormat,
 		compact:    *compact,
+		showCost:   *showCost,
+		showRate:   *showRate,
+		units:      tableUnits,
+		detailed:   *detailed,
+		tail:       *tail,
+		state:      sessionState,
+		verify:     *verify,
 		configPath: globalOpts.configPath,
 		globalOpts: globalOpts,
-	}
 
-	return cmd.Execute()
+		againstBaseline: *againstBaseline,
+	}, nil
 }
 
-// runListCommand runs the list command.
-func runListCommand(globalOpts globalOptions) error {
-	cmd := &listCommand{
-		configPath: globalOpts.configPath,
-		globalOpts: globalOpts,
-	}
-	return cmd.Execute()
+// runListCommand runs the list command. It is "session list" showing all
+// sessions, newest first.
+func runListCommand(globalOpts globalOptions, args []string) error {
+	cmd := newSessionCommand(globalOpts)
+	defer func() {
+		_ = cmd.rt.Close() //nolint:errcheck // best effort cleanup
+	}()
+	return cmd.listSessions("list", args, listOptions{sortBy: "date", showAll: true})
 }
 
 // runSessionCommand runs the session command.
 func runSessionCommand(globalOpts globalOptions, args []string) error {
-	cmd := &sessionCommand{
-		configPath: globalOpts.configPath,
-		globalOpts: globalOpts,
-	}
+	cmd := newSessionCommand(globalOpts)
+	defer func() {
+		_ = cmd.rt.Close() //nolint:errcheck // best effort cleanup
+	}()
 	return cmd.Execute(args)
 }
 
@@ -190,13 +511,32 @@ func runWatchCommand(globalOpts globalOptions, args []string) error {
 	// Define watch-specific flags.
 	fs := flag.NewFlagSet("watch", flag.ExitOnError)
 	sessionID := fs.String("session", "", "monitor specific session ID")
+	model := fs.String("model", "", "filter by model (comma-separated globs or /regex/)")
 	refresh := fs.Duration("refresh", time.Second, "refresh interval (e.g., 1s, 500ms)")
 	format := fs.String("format", "table", "output format (table, simple)")
 	history := fs.Bool("history", false, "keep history of updates (append mode)")
-
+	eco := fs.Bool("eco", false, "low-power mode: slower refresh, no percentiles, less re-discovery")
+	noInput := fs.Bool("no-input", false, "leave the terminal alone: no raw mode or key bindings, quit with Ctrl+C")
+	compact := fs.Bool("compact", false, "one summary line per update")
+	detailed := fs.Bool("detailed", false, "also show cache token totals")
+	silentAfter := fs.Duration("silent-after", 0, "alert when a session with usage goes silent this long (e.g., 45m; 0: off)")
+	errorSpike := fs.Int("error-spike", 5, "alert when this many API errors arrive within 5 minutes (0: off)")
+
+	if err := globalOpts.applyFlagDefaults(fs); err != nil {
+		return err
+	}
 	if err := fs.Parse(args); err != nil {
 		return err
 	}
+	if *compact && *detailed {
+		return fmt.Errorf("-compact cannot be combined with -detailed")
+	}
+	if *silentAfter < 0 {
+		return fmt.Errorf("invalid -silent-after %s (must be >= 0)", *silentAfter)
+	}
+	if *errorSpike < 0 {
+		return fmt.Errorf("invalid -error-spike %d (must be >= 0)", *errorSpike)
+	}
 
 	// Override format if global --json flag is set.
 	outputFormat := *format
@@ -206,9 +546,16 @@ func runWatchCommand(globalOpts globalOptions, args []string) error {
 
 	cmd := &watchCommand{
 		sessionID:   *sessionID,
+		model:       *model,
 		refresh:     *refresh,
 		format:      outputFormat,
-		clearScreen: !*history, // clear screen unless history mode
+		clearScreen: !*history && !globalOpts.accessible, // screen readers cannot follow redraws
+		eco:         *eco,
+		noInput:     *noInput,
+		compact:     *compact,
+		detailed:    *detailed,
+		silentAfter: *silentAfter,
+		errorSpike:  *errorSpike,
 		configPath:  globalOpts.configPath,
 		globalOpts:  globalOpts,
 	}
@@ -225,6 +572,9 @@ func runQueryCommand(globalOpts globalOptions, args []string) error {
 	jsonOut := fs.Bool("json", false, "output all metrics as JSON")
 	format := fs.String("format", "", "output format (hook)")
 
+	if err := globalOpts.applyFlagDefaults(fs); err != nil {
+		return err
+	}
 	if err := fs.Parse(args); err != nil {
 		return err
 	}
@@ -235,6 +585,7 @@ func runQueryCommand(globalOpts globalOptions, args []string) error {
 		metric:     *metric,
 		jsonOutput: *jsonOut,
 		format:     *format,
+		expression: strings.Join(fs.Args(), " "),
 		configPath: globalOpts.configPath,
 		globalOpts: globalOpts,
 	}
@@ -242,46 +593,104 @@ func runQueryCommand(globalOpts globalOptions, args []string) error {
 	return cmd.Execute()
 }
 
-// showUsage displays usage information.
+// usageCommands lists the commands shown in help, in display order.
+// Descriptions come from the message catalog (usage.<command>).
+var usageCommands = []string{
+	"tui", "today", "stats", "list", "watch", "session", "project", "config", "query",
+	"status", "serve", "api", "hook-receiver", "notify", "annotate", "install", "repl",
+	"report", "calendar", "dump", "history", "baseline", "focus", "tickets", "team", "whatif", "fsck", "health", "debug",
+	"logs", "telemetry", "version", "help",
+}
+
+// showUsage displays usage information. The title and command list are
+// localized; flag details are English only.
 func showUsage() error {
-	usage := `Token Monitor - Claude Code CLI token usage monitoring tool
-
-Usage:
-  token-monitor [flags] <command> [command flags]
-
-Commands:
-  tui         Interactive TUI dashboard (default when no command given)
-  stats       Display token usage statistics
-  list        List all discovered sessions
-  watch       Live monitoring of token usage
-  session     Session management (name, list, show, delete)
-  config      Configuration management (show, path, set, validate, reset)
-  query       Fast single-metric token lookup (for hooks)
-  status      Compact status line output (for Claude Code status)
-  serve       MCP server mode (for Claude Code MCP integration)
-  install     Install token-monitor into Claude Code (statusline, mcp, hook)
-  help        Show this help message
+	fmt.Printf("%s\n\n%s\n  token-monitor [flags] <command> [command flags]\n\n%s\n",
+		i18n.T("usage.title"), i18n.T("usage.usage"), i18n.T("usage.commands"))
+	for _, name := range usageCommands {
+		fmt.Printf("  %-13s %s\n", name, i18n.T("usage."+name))
+	}
 
+	usage := `
 Global Flags:
   -config       Path to configuration file
   -version      Show version information
   -log-level    Set log level (debug, info, warn, error)
-  -json         Output in JSON format (overrides command-specific format flags)
+  -json         Output in JSON format (overrides command-specific format flags);
+                failures are written to stdout as {"error": {"code": ..., "message": ...}}
   -no-color     Disable colored output
+  -accessible   Screen-reader friendly output: labeled lines instead of
+                tables, no box drawing or emoji (or display.accessible)
+  -ascii        ASCII-only output: +-| table borders, plain-text labels
+                instead of emoji (or display.ascii)
+  -quiet        Suppress informational output and logging to the terminal;
+                a logging.output file keeps its level
+                (implied when stdout is not a terminal)
+  -no-cache     Disable the discovery cache and rescan all directories
+  -force        Ignore the large-file limits (performance.max_file_size_mb,
+                performance.max_entries_per_read)
+  -no-pager     Do not pipe long output (stats, list, report, session list/show,
+                ...) into $TOKEN_MONITOR_PAGER or $PAGER (default: less)
 
 Stats Command Flags:
-  -session    Filter by session ID
-  -model      Filter by model name
-  -group-by   Group by dimensions (comma-separated: model,session,date,hour)
+  -session    Filter by session ID or name
+  -model      Filter by model (comma-separated globs or /regex/, e.g. "claude-3-5*,*opus*")
+  -group-by   Group by dimensions (comma-separated: model,session,date,hour,source,
+              subagent)
   -top        Show top N sessions by token usage
   -format     Output format (table, json, simple)
   -compact    Compact output
+  -cost       Show estimated cost column in grouped output
+  -rate       Show requests/day column in grouped output
+  -units      Primary table measure: tokens, k (thousands), or cost
+              (default: display.units)
+  -detailed   Also show the measures -units leaves out (cost for token
+              units, tokens for cost)
+  -against-baseline
+              Compare the baseline's period length, ending now, with a
+              saved baseline: totals plus per-request and per-day rates
+  -tail       Read only the last N entries of each session file, for
+              quick approximate statistics over very large files
+  -state      Only sessions in a lifecycle state: active, idle, archived
+              (by last write; see session.idle_after, session.archive_after)
+  -verify     Recompute totals from the session files with a separate
+              reader and compare them, and the stored rollups, with the
+              results; drift is reported on stderr and exits non-zero
 
 Watch Command Flags:
   -session    Monitor specific session ID
+  -model      Filter by model (comma-separated globs or /regex/)
   -refresh    Refresh interval (default: 1s, e.g., 500ms, 2s)
   -format     Output format (table, simple)
   -history    Keep history of updates (append mode, default: false)
+  -eco        Low-power mode: refresh at least every 5s, skip percentiles,
+              re-discover sessions every 10m (also performance.low_power;
+              the tui command accepts -eco too)
+  -no-input   Never put the terminal in raw mode (also monitoring.no_input).
+              Use it under multiplexers or capture tools that break in raw
+              mode. Key bindings are off; Ctrl+C or SIGTERM stops watch.
+              Raw mode is also skipped when stdin is not a terminal.
+  -compact    One summary line per update (requests, tokens, burn rate,
+              billing block) instead of the panels
+  -detailed   Also show cache creation and cache read totals
+  -silent-after
+              Alert when a session with usage during watch has had none
+              for this long (e.g., 45m; default 0: off). The alert stays
+              on screen until usage resumes and is posted once to
+              notify.channels with the alert event. Set it per profile
+              with defaults.watch or an alias.
+  -error-spike
+              Alert when this many failed requests (API errors, rate
+              limits, aborts) arrive within 5 minutes (default: 5; 0: off),
+              on screen and once per spike to notify.channels
+  Ctrl+Z suspends watch with the terminal restored; fg (or SIGCONT)
+  resumes it and redraws the screen.
+  With mqtt.broker configured, each update is also published to
+  mqtt.topic as JSON (block %, burn rate, tokens and cost today).
+
+List Command Flags:
+  Same as "session list" (see "token-monitor session help"), but shows all
+  sessions sorted by date by default (-all=true -sort date).
 
 Query Command Flags:
   -current    Auto-detect current session
@@ -290,6 +699,181 @@ Query Command Flags:
   -json       Output all metrics as JSON
   -format     Output format (hook)
 
+  Query expressions (positional argument) run over all sessions, or over
+  one session with -current/-session; -json prints rows as objects:
+    SELECT <col|agg(col)> [AS name], ... [FROM entries]
+      [WHERE col <op> 'value' [AND ...]] [GROUP BY col, ...]
+      [ORDER BY name [ASC|DESC]] [LIMIT n]
+  Columns: session, subagent, model, project, version, ts, date, hour,
+           input, output, cache_creation, cache_read, total, cost
+           (subagent: the sub-agent ID, main, or sidechain)
+  Aggregates: count, sum, avg, min, max. Operators: = != < <= > >= LIKE
+
+Report Command Flags:
+  -days       Report the last N days (default: 30; ignored when -from is set)
+  -from       Start date (YYYY-MM-DD, inclusive)
+  -to         End date (YYYY-MM-DD, inclusive)
+  -group-by   Group by dimensions (comma-separated: date,model,session; default: date)
+  -rebuild    Recompute rollups from session files; sessions whose files
+              were deleted keep their stored rollups
+  -format     Output format (table, json)
+  -units      Primary table measure: tokens, k (thousands), or cost
+  -detailed   With -units cost, also show token columns
+  -compact    Compact table: no separator line, single-space columns
+  -weekday    Average daily usage per weekday instead of totals, to spot
+              weekday patterns and weekend automation runs
+  -weeks      With -weekday, average over the last N complete weeks ending
+              yesterday (default: 4)
+  Rollups are stored in the BoltDB database and kept current by a running
+  watch; report also folds in any entries appended since the last update.
+  They outlive the session files, so history survives Claude's log cleanup.
+  Progress is checkpointed per file, so an interrupted ingest or -rebuild
+  resumes where it stopped when rerun.
+  With footprint.grams_per_mtok configured, a CO2E column (and stats'
+  summary) shows the estimated carbon footprint.
+
+Today Command Flags:
+  -format     Output format (card, json)
+  -compact    Print the summary as one line instead of a card
+  Today's totals, sessions, and current billing block are read from the
+  session files written since midnight; the comparison with the daily
+  average over the previous 30 days uses the rollups and is left out while
+  watch holds the database.
+
+Calendar Command Flags:
+  -month      Month to show (YYYY-MM, default: current month)
+  -format     Output format (table, json)
+  Days are shaded by tokens relative to the busiest day of the month; the
+  tui Calendar tab shows each day's totals as you move between days.
+
+Dump Command Flags:
+  -output     Output file (default: stdout)
+  -from       Start date (YYYY-MM-DD, inclusive)
+  -to         End date (YYYY-MM-DD, inclusive)
+  Writes every usage entry as one JSON object per line, sorted by time, with
+  repeats of the same message and request dropped. Each line has the same
+  flat fields (timestamp, session_id, project, model, token counts,
+  cost_usd), e.g. for pandas.read_json(path, lines=True).
+
+Baseline Command:
+  baseline save <name> Capture totals for the last N days (-days, default: 7;
+                       0 for all data), optionally for -model; -force overwrites
+  baseline list        List saved baselines
+  baseline delete <name>
+                       Remove a baseline
+
+Project Command:
+  project compare [path]
+                       List every session of the project at path (default:
+                       current directory) with duration, requests, tokens,
+                       cache hit rate, cost, and share of the project cost
+
+History Command:
+  history coverage     Show which date ranges are backed by session files,
+                       by the rollups only (files deleted), or both
+                       (-format table|json)
+
+Focus Command:
+  focus start -label <name>
+                       Open a focus window; usage from any session while it
+                       is open counts toward the label (-switch closes an
+                       open window first)
+  focus stop           Close the window and print its usage
+  focus status         Show the open window and its usage so far
+  focus list           List the windows of the last -days days (default: 7)
+  focus report         Requests, tokens, and cost per label (-days)
+  focus start -ticket <ticket> also attributes the window to a ticket.
+
+Tickets Command Flags:
+  -days       Report the last N days (default: 30)
+  -format     Output format (table, csv, json)
+  Spend per ticket. A session labeled with "session label <name|uuid>
+  <ticket>" counts toward its ticket; other usage counts toward the ticket
+  of the focus window it falls in, or (none).
+
+Team Command Flags:
+  -month      Month to report (YYYY-MM, default: current month)
+  -format     Output format (table, json)
+  Usage per member (claude_config_dirs label) with plan quota utilization
+  and spend in team.currency. Members without a plan are charged their
+  API-equivalent cost. Plans are configured under team.plans.
+  team stats           Leaderboard of members by usage over -window (today,
+                       all, Nd, Nh; default 7d), ranked -by tokens or cost,
+                       with -format table or json; -shared ranks the usage
+                       members pushed to this team server
+  team push            Push this member's daily totals to team.share.endpoint
+                       once; watch pushes every team.share.interval (1h)
+
+Whatif Command Flags:
+  -map        FROM=TO model switch (repeatable, comma-separated). FROM is a
+              model class (opus, sonnet, haiku) or a model glob; TO is the
+              model or class to price the requests as
+  -cache      Also compare the cost without prompt caching (cache tokens
+              priced as input) and with every input token cached
+  -window     Period to reprice: today, all, Nd, Nh (default: 30d)
+  -format     Output format (table, json)
+  Recomputes the API-equivalent cost of past requests as if they had used
+  another model, to size the savings of a model-routing change. Token
+  counts are kept as recorded; the first matching -map applies. Needs
+  -map, -cache, or both.
+
+Notify Command:
+  notify daily         Post a day's usage summary (-date, default yesterday)
+                       to channels with the daily event
+  notify test          Post a test alert to channels with the alert event
+  Both take -channel <name> to pick one channel and -dry-run to print the
+  message instead. Channels are configured under notify.channels.
+
+Annotate Command Flags:
+  -repo       Git repository (default: current directory)
+  -since      Base ref; commits after its merge base with HEAD are annotated
+              (default: HEAD~1)
+  -format     Output format (text, markdown, trailer, json)
+  Each request in the repository's sessions counts toward the first commit
+  made after it. markdown prints a PR comment body, e.g. for
+  "gh pr comment --body-file -"; trailer prints AI-* commit trailers.
+
+Fsck Command Flags:
+  -repair     Remove orphaned names and file offsets, rebuild inconsistent rollups
+  Also warns about duplicate session files (counted once) and files with
+  future-dated entries (clock skew; counted at the file's modification time).
+  Exits non-zero when problems remain unrepaired.
+
+Health Command Flags:
+  -watch      Check that watch holds the database and ingested recently (default: true)
+  -max-age    Maximum age of the last ingest (default: 3x the watch rollup interval)
+  -url        serve -http base URL to probe at /healthz and /readyz
+              (default: serve.addr; skipped when neither is set)
+  -timeout    Timeout for each HTTP probe (default: 5s)
+  -perf       Also report watch's own CPU time, bytes read, and allocations
+              for its last rollup interval and since it started
+  Exits non-zero when any check fails, for systemd or container health checks.
+
+Debug Command:
+  debug bundle         Write a tarball for bug reports: version/platform, redacted
+                       config, health checks, log tail, sanitized failing lines
+  Bundle flags: -output, -samples (default: 20), -log-bytes (default: 262144)
+
+Logs Command Flags:
+  -n          Lines to show from the end of the log (default: 10)
+  -f          Keep printing new lines, following log rotation (Ctrl+C to stop)
+  -level      Show only lines at or above a level (debug, info, warn, error)
+  -path       Print the log destination (logging.output) and exit
+  Reads the log file set by logging.output; fails when logs go to stderr
+  or stdout.
+
+Telemetry Command (opt-in, off by default):
+  telemetry status             Show the setting and the exact next report
+  telemetry enable [-endpoint URL]
+                               Record version, platform, and command timings;
+                               with an endpoint, post them at most once a day
+  telemetry disable            Stop recording and delete recorded timings
+  Never records usage data. DO_NOT_TRACK=1 overrides the configuration.
+
+Version Command Flags:
+  -json       Print version, commit, build date, Go version, platform, and
+              compiled-in features as JSON (also with the global -json)
+
 Status Command Flags:
   -current      Auto-detect current session
   -session      Specify session ID directly
@@ -305,6 +889,28 @@ Status Command Flags:
 
 Serve Command Flags:
   -stdio      Use stdio for MCP communication (default: true)
+  -http       Serve MCP over HTTP on this address instead (POST /mcp, plus
+              /healthz and /readyz probes and /metrics; also serve.addr or
+              TOKEN_MONITOR_SERVE_ADDR). SIGTERM drains in-flight requests.
+  -claude-dir Claude projects directory to read (repeatable, comma-separated;
+              replaces claude_config_dirs)
+  With serve.tokens configured, POST /mcp requires a bearer token whose
+  scopes (read, ingest, admin) decide which tools it may list and call.
+  serve.base_path, serve.cors_origins, and serve.trust_proxy let the
+  endpoints sit behind a reverse proxy (see README). POST /hooks ingests
+  hook payloads forwarded by hook-receiver, and POST /team/usage merges
+  usage pushed by team members (both ingest scope). GET /api/blocks and
+  GET /api/burn-rate serve plain JSON for polling dashboards, with cursor
+  pagination and If-Modified-Since (read scope). GET /openapi.json serves
+  the OpenAPI specification of all endpoints (see api spec).
+
+Hook Receiver Flags:
+  -url        serve -http base URL to forward the payload to instead of
+              ingesting locally (bearer token from TOKEN_MONITOR_HOOK_TOKEN)
+  -timeout    Timeout for forwarding the payload (default: 2s)
+  Reads the Claude Code hook JSON from stdin and folds the session's new
+  entries into the rollups right away. While watch holds the database,
+  the payload is left to watch.
 
 Install Command:
   install statusline   Patch ~/.claude/statusline-command.sh with managed block
@@ -315,6 +921,26 @@ Install Command:
                        Remove all integrations
   Common flags: --dry-run, --uninstall, --print (statusline), --absolute (mcp)
 
+Command Defaults:
+  The config file's "defaults" section seeds command flags; explicit flags win.
+  Keys are command names (session subcommands as session_list, session_show),
+  flag names may use underscores, and lists are joined with commas:
+
+    defaults:
+      stats:
+        group_by: [model]
+      watch:
+        refresh: 2s
+
+Aliases:
+  The config file's "aliases" section defines shortcut commands. Extra
+  arguments are appended; built-in commands cannot be overridden:
+
+    aliases:
+      daily: "stats -group-by date -cost"
+
+  token-monitor daily -compact   # runs: stats -group-by date -cost -compact
+
 Examples:
   # Show overall statistics
   token-monitor stats
@@ -322,18 +948,28 @@ Examples:
   # Show statistics grouped by model
   token-monitor stats -group-by model
 
+  # Show per-model cost and request rate
+  token-monitor stats -group-by model -cost -rate
+
   # Show top 10 sessions
   token-monitor stats -top 10
 
   # Show statistics in JSON format
   token-monitor stats -format json
 
-  # Filter by session ID
+  # Filter by model patterns
+  token-monitor stats -model "claude-3-5*,*opus*"
+
+  # Filter by session ID or name
   token-monitor stats -session abc123...
+  token-monitor stats -session my-project
 
   # List all sessions
   token-monitor list
 
+  # Explore a large corpus interactively without re-parsing per command
+  token-monitor repl
+
   # Live monitoring of all sessions
   token-monitor watch
 
@@ -368,6 +1004,9 @@ Integration Examples:
   # All metrics as JSON
   token-monitor query --current --json
 
+  # Ad-hoc query over all entries
+  token-monitor query "SELECT model, sum(total) FROM entries WHERE ts > '2025-11-01' GROUP BY model"
+
   # Compact status for Claude Code status line
   token-monitor status --current --compact
 
@@ -2,9 +2,18 @@ package main
 
 import (
 	"flag"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/0xmhha/token-monitor/pkg/aggregator"
+	"github.com/0xmhha/token-monitor/pkg/analysis"
+	"github.com/0xmhha/token-monitor/pkg/i18n"
+	"github.com/0xmhha/token-monitor/pkg/logger"
+	"github.com/0xmhha/token-monitor/pkg/monitor"
+	"github.com/0xmhha/token-monitor/pkg/parser"
+	"github.com/0xmhha/token-monitor/pkg/session"
 )
 
 // TestRunStatsCommand tests stats command flag parsing.
@@ -324,6 +333,73 @@ func TestRunWatchCommand(t *testing.T) {
 	}
 }
 
+func TestWatchCompactLine(t *testing.T) {
+	now := time.Date(2025, 11, 3, 12, 0, 0, 0, time.UTC)
+	update := monitor.Update{
+		Timestamp:  now,
+		Stats:      aggregator.Statistics{Count: 12, TotalTokens: 1_200_000},
+		Cumulative: monitor.DeltaStats{NewEntries: 1, TotalTokens: 3400},
+		BurnRate:   aggregator.BurnRate{EntryCount: 3, TokensPerMinute: 5200},
+		CurrentBlock: aggregator.BillingBlock{
+			StartTime:   now.Add(-150 * time.Minute),
+			EndTime:     now.Add(150 * time.Minute),
+			TotalTokens: 340_000,
+			EntryCount:  12,
+		},
+	}
+
+	c := &watchCommand{blockLimit: 1_000_000}
+	want := "12:00:00  12 req (+1) · 1.2M tokens (+3.4K) · 5.2K/min · block 340.0K, 2h 30m left, 34% of 1.0M quota used"
+	if got := c.compactLine(update); got != want {
+		t.Errorf("compactLine() = %q, want %q", got, want)
+	}
+
+	update.BurnRate, update.CurrentBlock = aggregator.BurnRate{}, aggregator.BillingBlock{}
+	if got := c.compactLine(update); got != "12:00:00  12 req (+1) · 1.2M tokens (+3.4K)" {
+		t.Errorf("compactLine(idle) = %q", got)
+	}
+
+	c.idleAfter = 30 * time.Minute
+	update.LatestEntry = parser.UsageEntry{
+		Timestamp: now.Add(-time.Minute),
+		Message:   parser.Message{Usage: parser.Usage{InputTokens: 4000, CacheReadInputTokens: 120000}},
+	}
+	want = "12:00:00  12 req (+1) · 1.2M tokens (+3.4K) · context ~62% full (124.0K of 200.0K tokens)"
+	if got := c.compactLine(update); got != want {
+		t.Errorf("compactLine(context) = %q, want %q", got, want)
+	}
+}
+
+func TestRateLimitText(t *testing.T) {
+	last := time.Date(2025, 11, 3, 14, 5, 0, 0, time.Local)
+	block := aggregator.BillingBlock{RateLimits: 3, LastRateLimit: last}
+	if got := rateLimitText(block); got != "3 rate-limit events in the current block, last at 14:05; expect slower responses" {
+		t.Errorf("rateLimitText() = %q", got)
+	}
+	if got := rateLimitCount(1); got != "1 rate-limit event" {
+		t.Errorf("rateLimitCount(1) = %q", got)
+	}
+}
+
+func TestRecentContext(t *testing.T) {
+	now := time.Date(2025, 11, 3, 12, 0, 0, 0, time.UTC)
+	entry := parser.UsageEntry{
+		Timestamp: now.Add(-10 * time.Minute),
+		Message:   parser.Message{Usage: parser.Usage{InputTokens: 1000, OutputTokens: 500}},
+	}
+
+	usage := recentContext(entry, now, 30*time.Minute, 0)
+	if usage == nil || usage.Tokens != 1500 || usage.Window != analysis.DefaultContextWindow {
+		t.Errorf("recentContext() = %+v, want 1500 tokens of the default window", usage)
+	}
+	if usage := recentContext(entry, now, 5*time.Minute, 0); usage != nil {
+		t.Errorf("recentContext(idle) = %+v, want nil", usage)
+	}
+	if usage := recentContext(parser.UsageEntry{}, now, 30*time.Minute, 0); usage != nil {
+		t.Errorf("recentContext(none) = %+v, want nil", usage)
+	}
+}
+
 // TestParseDimensions tests dimension string parsing.
 func TestParseDimensions(t *testing.T) {
 	tests := []struct {
@@ -450,6 +526,23 @@ func TestCommandRouting(t *testing.T) {
 	}
 }
 
+// TestUsageCommands checks that help lists every built-in command with a
+// catalog description.
+func TestUsageCommands(t *testing.T) {
+	listed := make(map[string]bool, len(usageCommands))
+	for _, name := range usageCommands {
+		listed[name] = true
+		if key := "usage." + name; i18n.T(key) == key {
+			t.Errorf("no description for %s in the message catalog", name)
+		}
+	}
+	for name := range builtinCommands {
+		if !listed[name] {
+			t.Errorf("built-in command %s is missing from usageCommands", name)
+		}
+	}
+}
+
 // TestVersionFlag tests version flag handling.
 func TestVersionFlag(t *testing.T) {
 	// Set version
@@ -464,13 +557,160 @@ func TestVersionFlag(t *testing.T) {
 	version = "dev"
 }
 
-// TestListCommand tests list command structure.
-func TestListCommand(t *testing.T) {
-	cmd := &listCommand{
-		configPath: "/test/config.yaml",
+// TestParseListOptions tests that list and session list share flags but
+// keep their own defaults.
+func TestParseListOptions(t *testing.T) {
+	c := &sessionCommand{}
+
+	opts, err := c.parseListOptions("list", nil, listOptions{sortBy: "date", showAll: true})
+	if err != nil {
+		t.Fatalf("parseListOptions() error = %v", err)
+	}
+	if !opts.showAll || opts.sortBy != "date" {
+		t.Errorf("list defaults = %+v, want showAll and sort by date", opts)
+	}
+
+	opts, err = c.parseListOptions("session list", []string{"-sort", "tokens", "-project", "api"}, listOptions{sortBy: "name"})
+	if err != nil {
+		t.Fatalf("parseListOptions() error = %v", err)
+	}
+	if opts.showAll || opts.sortBy != "tokens" || opts.project != "api" || !opts.showTokens {
+		t.Errorf("session list options = %+v", opts)
+	}
+}
+
+// TestListStateFilter tests that sessions get a state from their last
+// activity and that -state keeps only sessions in that state.
+func TestListStateFilter(t *testing.T) {
+	c := &sessionCommand{}
+	now := time.Now()
+	sessions := []displaySession{
+		{UUID: "a", LastActive: now.Add(-time.Minute)},
+		{UUID: "b", LastActive: now.Add(-3 * time.Hour)},
+		{UUID: "c", UpdatedAt: now.Add(-30 * 24 * time.Hour)}, // no file time
+	}
+	assignSessionStates(sessions, session.Thresholds{IdleAfter: time.Hour, ArchiveAfter: 24 * time.Hour}, now)
+
+	want := []session.State{session.StateActive, session.StateIdle, session.StateArchived}
+	for i, s := range sessions {
+		if s.State != want[i] {
+			t.Errorf("session %s state = %q, want %q", s.UUID, s.State, want[i])
+		}
 	}
 
-	if cmd.configPath != "/test/config.yaml" {
-		t.Errorf("configPath = %q, want %q", cmd.configPath, "/test/config.yaml")
+	opts, err := c.parseListOptions("list", []string{"-state", "idle"}, listOptions{})
+	if err != nil {
+		t.Fatalf("parseListOptions() error = %v", err)
 	}
+	got := c.filterSessions(sessions, opts)
+	if len(got) != 1 || got[0].UUID != "b" {
+		t.Errorf("filterSessions(-state idle) = %+v, want only b", got)
+	}
+
+	if _, err := c.parseListOptions("list", []string{"-state", "paused"}, listOptions{}); err == nil {
+		t.Error("parseListOptions(-state paused) error = nil, want error")
+	}
+}
+
+// TestResolveSessionIdentifier tests name-to-UUID resolution for -session.
+func TestResolveSessionIdentifier(t *testing.T) {
+	const uuid = "12345678-1234-1234-1234-123456789abc"
+
+	mgr, err := session.New(session.Config{
+		DBPath: filepath.Join(t.TempDir(), "test.db"),
+	}, logger.Noop())
+	if err != nil {
+		t.Fatalf("session.New() error = %v", err)
+	}
+	defer func() { _ = mgr.Close() }() //nolint:errcheck
+
+	if err := mgr.Create(&session.Metadata{UUID: uuid, Name: "my-project"}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		mgr        session.Manager
+		identifier string
+		want       string
+	}{
+		{"empty identifier", mgr, "", ""},
+		{"named session", mgr, "my-project", uuid},
+		{"raw UUID", mgr, uuid, uuid},
+		{"unknown name passes through", mgr, "other", "other"},
+		{"nil manager", nil, "my-project", "my-project"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := resolveSessionIdentifier(tt.mgr, tt.identifier); got != tt.want {
+				t.Errorf("resolveSessionIdentifier(%q) = %q, want %q", tt.identifier, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestResolveLogLevel tests log level precedence with quiet mode.
+func TestResolveLogLevel(t *testing.T) {
+	tests := []struct {
+		name         string
+		opts         globalOptions
+		defaultLevel string
+		output       string
+		want         string
+	}{
+		{"command default", globalOptions{}, "info", "", "info"},
+		{"quiet raises level", globalOptions{quiet: true}, "info", "", "error"},
+		{"quiet raises stdout level", globalOptions{quiet: true}, "info", "stdout", "error"},
+		{"quiet keeps log file level", globalOptions{quiet: true}, "info", "/var/log/token-monitor.log", "info"},
+		{"explicit level wins over quiet", globalOptions{quiet: true, logLevel: "debug"}, "info", "stderr", "debug"},
+		{"explicit level wins over default", globalOptions{logLevel: "warn"}, "error", "", "warn"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.opts.resolveLogLevel(tt.defaultLevel, tt.output); got != tt.want {
+				t.Errorf("resolveLogLevel(%q, %q) = %q, want %q", tt.defaultLevel, tt.output, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetFlagDefaults(t *testing.T) {
+	t.Run("seeds defaults before parse", func(t *testing.T) {
+		fs := flag.NewFlagSet("stats", flag.ContinueOnError)
+		groupBy := fs.String("group-by", "", "")
+		format := fs.String("format", "table", "")
+
+		err := setFlagDefaults(fs, map[string]string{"group-by": "model", "format": "simple"})
+		if err != nil {
+			t.Fatalf("setFlagDefaults() error = %v", err)
+		}
+		if err := fs.Parse([]string{"-format", "json"}); err != nil {
+			t.Fatalf("Parse() error = %v", err)
+		}
+
+		if *groupBy != "model" {
+			t.Errorf("group-by = %q, want model", *groupBy)
+		}
+		if *format != "json" {
+			t.Errorf("format = %q, want explicit json to win", *format)
+		}
+	})
+
+	t.Run("unknown flag", func(t *testing.T) {
+		fs := flag.NewFlagSet("session list", flag.ContinueOnError)
+		err := setFlagDefaults(fs, map[string]string{"bogus": "1"})
+		if err == nil || !strings.Contains(err.Error(), "session_list.bogus") {
+			t.Errorf("setFlagDefaults() error = %v, want unknown flag error", err)
+		}
+	})
+
+	t.Run("invalid value", func(t *testing.T) {
+		fs := flag.NewFlagSet("watch", flag.ContinueOnError)
+		fs.Duration("refresh", 0, "")
+		if err := setFlagDefaults(fs, map[string]string{"refresh": "soon"}); err == nil {
+			t.Error("setFlagDefaults() expected error for invalid duration")
+		}
+	})
 }
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"time"
+
+	"github.com/0xmhha/token-monitor/pkg/config"
+	"github.com/0xmhha/token-monitor/pkg/logger"
+	"github.com/0xmhha/token-monitor/pkg/monitor"
+	"github.com/0xmhha/token-monitor/pkg/mqtt"
+	"github.com/0xmhha/token-monitor/pkg/rollup"
+)
+
+// mqttRetryDelay is how long the publisher waits after a failed connect
+// or publish before trying the broker again.
+const mqttRetryDelay = 30 * time.Second
+
+// mqttStatus is the JSON published on each watch update.
+type mqttStatus struct {
+	// BlockPercent is the elapsed share of the active 5-hour billing
+	// block (0 when no block is active).
+	BlockPercent          float64 `json:"block_percent"`
+	BlockRemainingMinutes int     `json:"block_remaining_minutes"`
+	BlockTokens           int     `json:"block_tokens"`
+
+	// BurnRate is in tokens per minute.
+	BurnRate float64 `json:"burn_rate"`
+
+	// TokensToday and CostToday come from the rollups of the current
+	// rollup day (UTC) and are omitted when they are unavailable.
+	TokensToday *int     `json:"tokens_today,omitempty"`
+	CostToday   *float64 `json:"cost_today,omitempty"`
+
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// newMQTTStatus builds the status for update. store may be nil.
+func newMQTTStatus(update monitor.Update, store rollup.Store, now time.Time) mqttStatus {
+	status := mqttStatus{
+		BurnRate:  math.Round(update.BurnRate.TokensPerMinute*10) / 10,
+		UpdatedAt: now.UTC().Truncate(time.Second),
+	}
+
+	block := update.CurrentBlock
+	if block.IsActive && block.EndTime.After(block.StartTime) {
+		elapsed := now.Sub(block.StartTime).Seconds() / block.EndTime.Sub(block.StartTime).Seconds()
+		status.BlockPercent = math.Round(min(max(elapsed, 0), 1)*1000) / 10
+		status.BlockRemainingMinutes = int(max(block.EndTime.Sub(now), 0).Minutes())
+		status.BlockTokens = block.TotalTokens
+	}
+
+	if store != nil {
+		today := rollup.Date(now)
+		if rows, err := store.Rows(today, today); err == nil {
+			var totals rollup.Totals
+			for _, row := range rows {
+				totals.Merge(row.Totals)
+			}
+			tokens := totals.TotalTokens()
+			cost := math.Round(totals.CostUSD*100) / 100
+			status.TokensToday, status.CostToday = &tokens, &cost
+		}
+	}
+	return status
+}
+
+// mqttPublisher publishes watch status to an MQTT broker in the
+// background, so a slow or unreachable broker never stalls the display.
+type mqttPublisher struct {
+	cfg    mqtt.Config
+	topic  string
+	retain bool
+	log    logger.Logger
+
+	// pending holds the latest status not yet published.
+	pending chan mqttStatus
+
+	client  *mqtt.Client
+	last    []byte
+	retryAt time.Time
+}
+
+// newMQTTPublisher returns a publisher for cfg.MQTT, or nil when no broker
+// is configured.
+func newMQTTPublisher(cfg *config.Config, log logger.Logger) (*mqttPublisher, error) {
+	if cfg.MQTT.Broker == "" {
+		return nil, nil
+	}
+
+	var password string
+	if env := cfg.MQTT.PasswordEnv; env != "" {
+		if password = os.Getenv(env); password == "" {
+			return nil, fmt.Errorf("%w: environment variable %s is not set", config.ErrInvalidMQTT, env)
+		}
+	}
+
+	return &mqttPublisher{
+		cfg: mqtt.Config{
+			Broker:   cfg.MQTT.Broker,
+			ClientID: cfg.MQTT.ClientID,
+			Username: cfg.MQTT.Username,
+			Password: password,
+		},
+		topic:   cfg.MQTT.Topic,
+		retain:  cfg.MQTT.Retain,
+		log:     log,
+		pending: make(chan mqttStatus, 1),
+	}, nil
+}
+
+// Offer queues status for publishing, replacing any status still queued.
+// It must be called from a single goroutine.
+func (p *mqttPublisher) Offer(status mqttStatus) {
+	select {
+	case <-p.pending:
+	default:
+	}
+	p.pending <- status
+}
+
+// run publishes queued statuses until stop is closed.
+func (p *mqttPublisher) run(stop <-chan struct{}) {
+	defer func() {
+		if p.client != nil {
+			_ = p.client.Close() //nolint:errcheck // best effort cleanup
+		}
+	}()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case status := <-p.pending:
+			p.publish(status)
+		}
+	}
+}
+
+// publish sends status unless it matches the last published one. After a
+// failure it drops statuses until the retry delay has passed.
+func (p *mqttPublisher) publish(status mqttStatus) {
+	key := status
+	key.UpdatedAt = time.Time{}
+	keyData, err := json.Marshal(key)
+	if err != nil || bytes.Equal(keyData, p.last) {
+		return
+	}
+	if time.Now().Before(p.retryAt) {
+		return
+	}
+
+	if p.client == nil {
+		client, err := mqtt.Dial(p.cfg)
+		if err != nil {
+			p.fail(err)
+			return
+		}
+		p.client = client
+		p.log.Info("connected to MQTT broker", "broker", p.cfg.Broker, "topic", p.topic)
+	}
+
+	payload, err := json.Marshal(status)
+	if err != nil {
+		return
+	}
+	if err := p.client.Publish(p.topic, payload, p.retain); err != nil {
+		_ = p.client.Close() //nolint:errcheck // reconnecting
+		p.client = nil
+		p.fail(err)
+		return
+	}
+	p.last = keyData
+}
+
+// fail logs err and delays the next attempt.
+func (p *mqttPublisher) fail(err error) {
+	p.log.Warn("MQTT publish failed", "broker", p.cfg.Broker, "retry_in", mqttRetryDelay.String(), "error", err)
+	p.retryAt = time.Now().Add(mqttRetryDelay)
+}
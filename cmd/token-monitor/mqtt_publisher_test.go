@@ -0,0 +1,98 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0xmhha/token-monitor/pkg/aggregator"
+	"github.com/0xmhha/token-monitor/pkg/config"
+	"github.com/0xmhha/token-monitor/pkg/logger"
+	"github.com/0xmhha/token-monitor/pkg/monitor"
+	"github.com/0xmhha/token-monitor/pkg/rollup"
+)
+
+func TestNewMQTTStatus(t *testing.T) {
+	start := time.Date(2026, 10, 15, 9, 0, 0, 0, time.UTC)
+	now := start.Add(90 * time.Minute)
+	update := monitor.Update{
+		BurnRate: aggregator.BurnRate{TokensPerMinute: 1234.56},
+		CurrentBlock: aggregator.BillingBlock{
+			StartTime:   start,
+			EndTime:     start.Add(5 * time.Hour),
+			TotalTokens: 42000,
+			IsActive:    true,
+		},
+	}
+
+	status := newMQTTStatus(update, nil, now)
+	if status.BlockPercent != 30 || status.BlockRemainingMinutes != 210 || status.BlockTokens != 42000 {
+		t.Errorf("block = %.1f%%, %d min, %d tokens; want 30%%, 210 min, 42000 tokens",
+			status.BlockPercent, status.BlockRemainingMinutes, status.BlockTokens)
+	}
+	if status.BurnRate != 1234.6 {
+		t.Errorf("BurnRate = %v, want 1234.6", status.BurnRate)
+	}
+
+	// Without rollups the daily figures are left out rather than sent as 0.
+	data, err := json.Marshal(status)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if strings.Contains(string(data), "cost_today") {
+		t.Errorf("status without rollups includes cost_today: %s", data)
+	}
+
+	update.CurrentBlock.IsActive = false
+	if status := newMQTTStatus(update, nil, now); status.BlockPercent != 0 || status.BlockTokens != 0 {
+		t.Errorf("inactive block status = %+v, want zero block fields", status)
+	}
+}
+
+func TestNewMQTTStatus_LocalZone(t *testing.T) {
+	kst := time.FixedZone("KST", 9*3600)
+	// 08:00 on November 3 in Seoul is still the rollup day 2025-11-02.
+	now := time.Date(2025, 11, 3, 8, 0, 0, 0, kst)
+	store := todayStore{rows: []rollup.Row{
+		{Key: rollup.Key{Date: "2025-11-02"}, Totals: rollup.Totals{InputTokens: 100, CostUSD: 1}},
+		{Key: rollup.Key{Date: "2025-11-03"}, Totals: rollup.Totals{InputTokens: 900, CostUSD: 9}},
+	}}
+
+	status := newMQTTStatus(monitor.Update{}, store, now)
+	if status.TokensToday == nil || *status.TokensToday != 100 || *status.CostToday != 1 {
+		t.Errorf("TokensToday, CostToday = %v, %v; want the 100 tokens and $1 of 2025-11-02", status.TokensToday, status.CostToday)
+	}
+}
+
+func TestNewMQTTPublisher(t *testing.T) {
+	cfg := config.Default()
+	if p, err := newMQTTPublisher(cfg, logger.Noop()); p != nil || err != nil {
+		t.Errorf("newMQTTPublisher() without broker = %v, %v; want nil, nil", p, err)
+	}
+
+	cfg.MQTT.Broker = "tcp://localhost:1883"
+	cfg.MQTT.Username = "ha"
+	cfg.MQTT.PasswordEnv = "TEST_MQTT_PASSWORD"
+	t.Setenv("TEST_MQTT_PASSWORD", "")
+	if _, err := newMQTTPublisher(cfg, logger.Noop()); !errors.Is(err, config.ErrInvalidMQTT) {
+		t.Errorf("newMQTTPublisher() with unset password env error = %v, want ErrInvalidMQTT", err)
+	}
+
+	t.Setenv("TEST_MQTT_PASSWORD", "s3cret")
+	p, err := newMQTTPublisher(cfg, logger.Noop())
+	if err != nil {
+		t.Fatalf("newMQTTPublisher() error = %v", err)
+	}
+	if p.cfg.Password != "s3cret" || p.topic != "token-monitor/status" {
+		t.Errorf("publisher = %+v", p)
+	}
+
+	// Only the latest status stays queued.
+	p.Offer(mqttStatus{BurnRate: 1})
+	p.Offer(mqttStatus{BurnRate: 2})
+	if got := <-p.pending; got.BurnRate != 2 {
+		t.Errorf("queued BurnRate = %v, want 2", got.BurnRate)
+	}
+}
@@ -0,0 +1,262 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/0xmhha/token-monitor/pkg/config"
+	"github.com/0xmhha/token-monitor/pkg/notify"
+	"github.com/0xmhha/token-monitor/pkg/rollup"
+)
+
+// Notification events a channel can subscribe to.
+const (
+	notifyEventAlert = "alert"
+	notifyEventDaily = "daily"
+)
+
+// notifyTimeout bounds delivering one message to all channels.
+const notifyTimeout = 30 * time.Second
+
+// notifyCommand sends notifications to the configured channels.
+type notifyCommand struct {
+	globalOpts globalOptions
+}
+
+// runNotifyCommand runs the notify command.
+func runNotifyCommand(globalOpts globalOptions, args []string) error {
+	cmd := &notifyCommand{globalOpts: globalOpts}
+	return cmd.Execute(args)
+}
+
+// Execute dispatches a notify subcommand.
+func (c *notifyCommand) Execute(args []string) error {
+	if len(args) == 0 {
+		return c.showHelp()
+	}
+
+	switch args[0] {
+	case "daily":
+		return c.runDaily(args[1:])
+	case "test":
+		return c.runTest(args[1:])
+	case "help":
+		return c.showHelp()
+	default:
+		return fmt.Errorf("unknown notify subcommand: %s", args[0])
+	}
+}
+
+// showHelp displays help for the notify command.
+func (c *notifyCommand) showHelp() error {
+	help := `Notify - Send usage notifications to chat channels
+
+Usage:
+  token-monitor notify <subcommand> [flags]
+
+Subcommands:
+  daily         Post a day's usage summary to channels with the daily event
+  test          Post a test alert to channels with the alert event
+
+Flags:
+  -channel      Send to this channel only, whatever its events
+  -date         Day to summarize, YYYY-MM-DD (daily; default: yesterday)
+  -dry-run      Print the message instead of sending it
+
+Channels are configured under notify.channels:
+
+  notify:
+    channels:
+      - name: dev
+        type: discord
+        webhook_url_env: DISCORD_WEBHOOK_URL
+        username: Token Monitor
+        events: [alert, daily]
+
+Run "notify daily" from cron after midnight for a daily post.
+`
+	fmt.Print(help)
+	return nil
+}
+
+// runDaily posts the usage summary of one day.
+func (c *notifyCommand) runDaily(args []string) error {
+	fs := flag.NewFlagSet("notify daily", flag.ExitOnError)
+	channel := fs.String("channel", "", "send to this channel only")
+	date := fs.String("date", "", "day to summarize, YYYY-MM-DD (default: yesterday)")
+	dryRun := fs.Bool("dry-run", false, "print the message instead of sending it")
+
+	if err := c.globalOpts.applyFlagDefaults(fs); err != nil {
+		return err
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	day := time.Now().AddDate(0, 0, -1)
+	if *date != "" {
+		var err error
+		if day, err = time.ParseInLocation(rollup.DateLayout, *date, time.Local); err != nil {
+			return fmt.Errorf("invalid -date %q (want YYYY-MM-DD)", *date)
+		}
+	}
+	name := day.Format(rollup.DateLayout)
+	previous := day.AddDate(0, 0, -1).Format(rollup.DateLayout)
+
+	rt := c.globalOpts.newRuntime("")
+	defer func() {
+		_ = rt.Close() //nolint:errcheck // best effort cleanup
+	}()
+
+	cfg, err := rt.Config()
+	if err != nil {
+		return err
+	}
+	if _, err := rt.Sessions(); err != nil {
+		return fmt.Errorf("rollup store unavailable (is watch running?): %w", err)
+	}
+	store, err := rt.Rollups()
+	if err != nil {
+		return err
+	}
+	if _, err := ingestRollups(context.Background(), rt, store); err != nil {
+		return err
+	}
+
+	rows, err := store.Rows(previous, name)
+	if err != nil {
+		return fmt.Errorf("failed to read rollups: %w", err)
+	}
+	var dayRows []rollup.Row
+	var prevTotals rollup.Totals
+	for _, row := range rows {
+		if row.Date == name {
+			dayRows = append(dayRows, row)
+		} else {
+			prevTotals.Merge(row.Totals)
+		}
+	}
+
+	return c.send(cfg, notifyEventDaily, *channel, *dryRun, notify.DailySummary(name, dayRows, prevTotals))
+}
+
+// runTest posts a test alert to check channel settings.
+func (c *notifyCommand) runTest(args []string) error {
+	fs := flag.NewFlagSet("notify test", flag.ExitOnError)
+	channel := fs.String("channel", "", "send to this channel only")
+	dryRun := fs.Bool("dry-run", false, "print the message instead of sending it")
+
+	if err := c.globalOpts.applyFlagDefaults(fs); err != nil {
+		return err
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	rt := c.globalOpts.newRuntime("")
+	defer func() {
+		_ = rt.Close() //nolint:errcheck // best effort cleanup
+	}()
+	cfg, err := rt.Config()
+	if err != nil {
+		return err
+	}
+
+	msg := notify.Message{
+		Title: "Test alert",
+		Text:  "token-monitor can post alerts to this channel.",
+		Level: notify.LevelWarning,
+	}
+	return c.send(cfg, notifyEventAlert, *channel, *dryRun, msg)
+}
+
+// send delivers msg to the channels selected by event or name. Every
+// channel is tried; the first failure is returned.
+func (c *notifyCommand) send(cfg *config.Config, event, name string, dryRun bool, msg notify.Message) error {
+	channels, err := notifyChannels(cfg, event, name)
+	if err != nil {
+		return err
+	}
+
+	if dryRun {
+		printNotification(c.globalOpts, msg)
+		for _, ch := range channels {
+			c.globalOpts.infof("Would send to %s (%s)\n", ch.name, event)
+		}
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+	defer cancel()
+
+	var firstErr error
+	for _, ch := range channels {
+		if err := ch.notifier.Notify(ctx, msg); err != nil {
+			c.globalOpts.infof("%s: %v\n", ch.name, err)
+			if firstErr == nil {
+				firstErr = fmt.Errorf("channel %s: %w", ch.name, err)
+			}
+			continue
+		}
+		c.globalOpts.infof("Sent to %s\n", ch.name)
+	}
+	return firstErr
+}
+
+// namedNotifier is a configured channel ready to send.
+type namedNotifier struct {
+	name     string
+	notifier notify.Notifier
+}
+
+// notifyChannels returns the channels named name, or with name empty,
+// the channels subscribed to event.
+func notifyChannels(cfg *config.Config, event, name string) ([]namedNotifier, error) {
+	var channels []namedNotifier
+	for _, ch := range cfg.Notify.Channels {
+		if name != "" && ch.Name != name {
+			continue
+		}
+		if name == "" && !ch.Wants(event) {
+			continue
+		}
+
+		url, err := config.ResolveSecret(ch.WebhookURL)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", config.ErrInvalidNotifyChannel, ch.Name, err)
+		}
+		if ch.WebhookURLEnv != "" {
+			if url = os.Getenv(ch.WebhookURLEnv); url == "" {
+				return nil, fmt.Errorf("%w: %s: environment variable %s is not set", config.ErrInvalidNotifyChannel, ch.Name, ch.WebhookURLEnv)
+			}
+		}
+		channels = append(channels, namedNotifier{
+			name:     ch.Name,
+			notifier: notify.NewDiscord(notify.DiscordConfig{WebhookURL: url, Username: ch.Username}),
+		})
+	}
+
+	switch {
+	case len(channels) > 0:
+		return channels, nil
+	case name != "":
+		return nil, fmt.Errorf("%w: no channel named %s", config.ErrInvalidNotifyChannel, name)
+	default:
+		return nil, fmt.Errorf("%w: no channel receives %s notifications (see notify.channels)", config.ErrInvalidNotifyChannel, event)
+	}
+}
+
+// printNotification prints msg as plain text.
+func printNotification(g globalOptions, msg notify.Message) {
+	out := g.output()
+	out.Printf("[%s] %s\n", msg.Level, msg.Title)
+	if msg.Text != "" {
+		out.Println(msg.Text)
+	}
+	for _, f := range msg.Fields {
+		out.Printf("%s: %s\n", f.Name, f.Value)
+	}
+}
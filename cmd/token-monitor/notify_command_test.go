@@ -0,0 +1,55 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/0xmhha/token-monitor/pkg/config"
+)
+
+func TestNotifyChannels(t *testing.T) {
+	cfg := config.Default()
+	cfg.Notify.Channels = []config.NotifyChannel{
+		{Name: "alerts", Type: "discord", WebhookURL: "https://discord.test/a", Events: []string{"alert"}},
+		{Name: "digest", Type: "discord", WebhookURLEnv: "TEST_DISCORD_WEBHOOK", Events: []string{"daily"}},
+	}
+	t.Setenv("TEST_DISCORD_WEBHOOK", "https://discord.test/d")
+
+	names := func(channels []namedNotifier) []string {
+		var out []string
+		for _, ch := range channels {
+			out = append(out, ch.name)
+		}
+		return out
+	}
+
+	channels, err := notifyChannels(cfg, notifyEventDaily, "")
+	if err != nil || len(channels) != 1 || channels[0].name != "digest" {
+		t.Errorf("daily channels = %v, %v; want [digest]", names(channels), err)
+	}
+
+	// A named channel is used whatever its events.
+	channels, err = notifyChannels(cfg, notifyEventDaily, "alerts")
+	if err != nil || len(channels) != 1 || channels[0].name != "alerts" {
+		t.Errorf("named channel = %v, %v; want [alerts]", names(channels), err)
+	}
+
+	if _, err := notifyChannels(cfg, notifyEventDaily, "missing"); !errors.Is(err, config.ErrInvalidNotifyChannel) {
+		t.Errorf("unknown channel error = %v, want ErrInvalidNotifyChannel", err)
+	}
+
+	t.Setenv("TEST_DISCORD_WEBHOOK", "")
+	if _, err := notifyChannels(cfg, notifyEventDaily, ""); !errors.Is(err, config.ErrInvalidNotifyChannel) {
+		t.Errorf("unset webhook env error = %v, want ErrInvalidNotifyChannel", err)
+	}
+
+	// A webhook_url reference is resolved when the channel is used.
+	cfg.Notify.Channels[0].WebhookURL = "${ENV:TEST_ALERT_WEBHOOK}"
+	if _, err := notifyChannels(cfg, notifyEventAlert, ""); !errors.Is(err, config.ErrSecretNotFound) {
+		t.Errorf("unset webhook reference error = %v, want ErrSecretNotFound", err)
+	}
+	t.Setenv("TEST_ALERT_WEBHOOK", "https://discord.test/a")
+	if channels, err := notifyChannels(cfg, notifyEventAlert, ""); err != nil || len(channels) != 1 {
+		t.Errorf("alert channels = %v, %v; want [alerts]", names(channels), err)
+	}
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// defaultPager is used when neither TOKEN_MONITOR_PAGER nor PAGER is set.
+const defaultPager = "less"
+
+// pagerEnv holds environment defaults for common pagers, as git sets them:
+// less quits when the output fits on one screen (F), keeps colors (R), and
+// leaves the output on the terminal (X).
+var pagerEnv = map[string]string{
+	"LESS": "FRX",
+	"LV":   "-c",
+}
+
+// pagedSubcommands lists the commands whose output can run past one
+// screen. A nil value pages every subcommand; otherwise only the listed
+// ones are paged, so interactive prompts (session delete) and file output
+// (session export) never go through the pager.
+var pagedSubcommands = map[string][]string{
+	"stats":    nil,
+	"list":     nil,
+	"report":   nil,
+	"calendar": nil,
+	"history":  nil,
+	"query":    nil,
+	"tickets":  nil,
+	"team":     nil,
+	"session":  {"list", "show"},
+	"project":  {"compare"},
+	"focus":    {"list", "report"},
+}
+
+// pagedCommand reports whether the command in args pages its output.
+func pagedCommand(args []string) bool {
+	subcommands, ok := pagedSubcommands[args[0]]
+	if !ok {
+		return false
+	}
+	if subcommands == nil {
+		return true
+	}
+	if len(args) < 2 {
+		return false
+	}
+	for _, sub := range subcommands {
+		if args[1] == sub {
+			return true
+		}
+	}
+	return false
+}
+
+// pagerCommand returns the pager to run: TOKEN_MONITOR_PAGER, then PAGER,
+// then less. An empty value or "cat" disables paging.
+func pagerCommand() string {
+	pager := defaultPager
+	for _, name := range []string{"TOKEN_MONITOR_PAGER", "PAGER"} {
+		if value, ok := os.LookupEnv(name); ok {
+			pager = strings.TrimSpace(value)
+			break
+		}
+	}
+	if pager == "cat" {
+		return ""
+	}
+	return pager
+}
+
+// startPager redirects stdout into the pager until the returned function
+// is called, which waits for the user to quit the pager and restores
+// stdout. Without a usable pager, stdout is left alone.
+func startPager() (stop func()) {
+	noop := func() {}
+	args := strings.Fields(pagerCommand())
+	if len(args) == 0 {
+		return noop
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		return noop
+	}
+	cmd := exec.Command(args[0], args[1:]...) //nolint:gosec // the pager is chosen by the user
+	cmd.Stdin = r
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = os.Environ()
+	for name, value := range pagerEnv {
+		if _, ok := os.LookupEnv(name); !ok {
+			cmd.Env = append(cmd.Env, name+"="+value)
+		}
+	}
+	if err := cmd.Start(); err != nil {
+		_ = r.Close() //nolint:errcheck // pager unavailable
+		_ = w.Close() //nolint:errcheck // pager unavailable
+		return noop
+	}
+	_ = r.Close() //nolint:errcheck // the pager holds its own copy
+
+	stdout := os.Stdout
+	os.Stdout = w
+	return func() {
+		os.Stdout = stdout
+		_ = w.Close()  //nolint:errcheck // signals end of output
+		_ = cmd.Wait() //nolint:errcheck // the pager's exit status is irrelevant
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestPagedCommand(t *testing.T) {
+	tests := []struct {
+		args []string
+		want bool
+	}{
+		{[]string{"stats"}, true},
+		{[]string{"report", "-days", "90"}, true},
+		{[]string{"session", "list", "-all"}, true},
+		{[]string{"session", "show", "my-session"}, true},
+		{[]string{"session", "delete", "my-session"}, false},
+		{[]string{"session"}, false},
+		{[]string{"watch"}, false},
+		{[]string{"serve"}, false},
+	}
+	for _, tt := range tests {
+		if got := pagedCommand(tt.args); got != tt.want {
+			t.Errorf("pagedCommand(%q) = %v, want %v", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestPagerCommand(t *testing.T) {
+	t.Setenv("TOKEN_MONITOR_PAGER", "more")
+	t.Setenv("PAGER", "most")
+	if got := pagerCommand(); got != "more" {
+		t.Errorf("pagerCommand() = %q, want TOKEN_MONITOR_PAGER", got)
+	}
+
+	os.Unsetenv("TOKEN_MONITOR_PAGER") //nolint:errcheck // restored by t.Setenv
+	if got := pagerCommand(); got != "most" {
+		t.Errorf("pagerCommand() = %q, want PAGER", got)
+	}
+
+	for _, disabled := range []string{"", "cat"} {
+		t.Setenv("PAGER", disabled)
+		if got := pagerCommand(); got != "" {
+			t.Errorf("pagerCommand() with PAGER=%q = %q, want none", disabled, got)
+		}
+	}
+
+	os.Unsetenv("PAGER") //nolint:errcheck // restored by t.Setenv
+	if got := pagerCommand(); got != defaultPager {
+		t.Errorf("pagerCommand() = %q, want %q", got, defaultPager)
+	}
+}
+
+func TestStartPager(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("no cp pager on Windows")
+	}
+	path := filepath.Join(t.TempDir(), "paged")
+	t.Setenv("TOKEN_MONITOR_PAGER", "cp /dev/stdin "+path)
+
+	stdout := os.Stdout
+	stop := startPager()
+	fmt.Fprint(os.Stdout, "paged output")
+	stop()
+
+	if os.Stdout != stdout {
+		t.Error("startPager() did not restore stdout")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != "paged output" {
+		t.Errorf("pager received %q, %v; want the output", data, err)
+	}
+}
@@ -0,0 +1,278 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/0xmhha/token-monitor/pkg/analysis"
+	"github.com/0xmhha/token-monitor/pkg/discovery"
+	"github.com/0xmhha/token-monitor/pkg/display"
+)
+
+// projectCommand handles per-project views.
+type projectCommand struct {
+	globalOpts globalOptions
+}
+
+// runProjectCommand runs the project command.
+func runProjectCommand(globalOpts globalOptions, args []string) error {
+	cmd := &projectCommand{globalOpts: globalOpts}
+	return cmd.Execute(args)
+}
+
+// Execute dispatches a project subcommand.
+func (c *projectCommand) Execute(args []string) error {
+	if len(args) == 0 {
+		return c.showHelp()
+	}
+
+	switch args[0] {
+	case "compare":
+		return c.runCompare(args[1:])
+	case "help":
+		return c.showHelp()
+	default:
+		return fmt.Errorf("unknown project subcommand: %s", args[0])
+	}
+}
+
+// showHelp displays help for the project command.
+func (c *projectCommand) showHelp() error {
+	help := `Project - Per-project session views
+
+Usage:
+  token-monitor project <subcommand> [flags]
+
+Subcommands:
+  compare [path]  List every session of a project side by side with totals
+                  (default path: current directory)
+
+The path is the project's working directory (e.g. ~/work/app), which
+Claude Code stores as ~/.claude/projects/-Users-me-work-app; the Claude
+project directory itself or its name is accepted too.
+
+Sessions are listed oldest first with duration, requests, tokens, cache
+hit rate, cost, and each session's share of the project cost, to see
+what each iteration of work on a repository cost.
+`
+	fmt.Print(help)
+	return nil
+}
+
+// projectSession is one session in a project comparison.
+type projectSession struct {
+	SessionID    string        `json:"session_id"`
+	Label        string        `json:"label"`
+	FirstSeen    time.Time     `json:"first_seen"`
+	LastSeen     time.Time     `json:"last_seen"`
+	Duration     time.Duration `json:"-"`
+	DurationSec  float64       `json:"duration_seconds"`
+	Requests     int           `json:"requests"`
+	TotalTokens  int           `json:"total_tokens"`
+	CacheHitRate float64       `json:"cache_hit_rate"`
+	CostUSD      float64       `json:"cost_usd"`
+}
+
+// projectComparison is the result of project compare.
+type projectComparison struct {
+	Project  string           `json:"project"`
+	Sessions []projectSession `json:"sessions"`
+	Total    projectSession   `json:"total"`
+}
+
+// runCompare lists all sessions of a project side by side.
+func (c *projectCommand) runCompare(args []string) error {
+	fs := flag.NewFlagSet("project compare", flag.ExitOnError)
+	if err := c.globalOpts.applyFlagDefaults(fs); err != nil {
+		return err
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 1 {
+		return fmt.Errorf("usage: token-monitor project compare [path]")
+	}
+	path := "."
+	if fs.NArg() == 1 {
+		path = fs.Arg(0)
+	}
+
+	rt := c.globalOpts.newRuntime("")
+	defer func() {
+		_ = rt.Close() //nolint:errcheck // best effort cleanup
+	}()
+
+	if _, err := rt.Config(); err != nil {
+		return err
+	}
+	disc, err := rt.Discoverer()
+	if err != nil {
+		return err
+	}
+	discovered, err := disc.Discover()
+	if err != nil {
+		return fmt.Errorf("failed to discover sessions: %w", err)
+	}
+
+	files := projectSessionFiles(discovered, path)
+	if len(files) == 0 {
+		return fmt.Errorf("%w for project %s", discovery.ErrNoSessionsFound, path)
+	}
+
+	// Names are a convenience; without the database, sessions are shown
+	// by short ID.
+	names := make(map[string]string)
+	if mgr, err := rt.Sessions(); err == nil {
+		for _, f := range files {
+			if metadata, err := mgr.GetByUUID(f.SessionID); err == nil && metadata.Name != "" {
+				names[f.SessionID] = metadata.Name
+			}
+		}
+	}
+
+	p, err := rt.Parser()
+	if err != nil {
+		return err
+	}
+	var analyses []analysis.SessionAnalysis
+	for _, f := range files {
+		entries, _, err := p.ParseFile(f.FilePath, 0)
+		if err != nil {
+			c.globalOpts.infof("Skipping %s: %v\n", f.FilePath, err)
+			continue
+		}
+		if len(entries) == 0 {
+			continue
+		}
+		label := names[f.SessionID]
+		if label == "" {
+			label = shortSessionID(f.SessionID)
+		}
+		analyses = append(analyses, analysis.Analyze(f.SessionID, label, f.ProjectPath, entries))
+	}
+	if len(analyses) == 0 {
+		return fmt.Errorf("%w for project %s", discovery.ErrNoSessionsFound, path)
+	}
+
+	result := compareProjectSessions(files[0].ProjectPath, analyses)
+	if c.globalOpts.jsonOutput {
+		return printJSON(result)
+	}
+	return c.displayCompare(result)
+}
+
+// displayCompare prints the sessions as a table with a total row.
+func (c *projectCommand) displayCompare(result projectComparison) error {
+	out := c.globalOpts.output()
+	out.Printf("Project: %s (%d sessions)\n\n", result.Project, len(result.Sessions))
+
+	header := []string{"#", "SESSION", "STARTED", "DURATION", "REQUESTS", "TOKENS", "CACHE HIT", "COST", "SHARE"}
+	rows := make([][]string, 0, len(result.Sessions)+1)
+	for i, s := range result.Sessions {
+		rows = append(rows, projectRow(fmt.Sprintf("%d", i+1), s, result.Total.CostUSD))
+	}
+	rows = append(rows, projectRow("", result.Total, result.Total.CostUSD))
+	return display.WriteTable(os.Stdout, header, rows, false)
+}
+
+// projectRow formats one table row; share is the session's fraction of
+// the project cost.
+func projectRow(rank string, s projectSession, totalCost float64) []string {
+	share := "-"
+	if totalCost > 0 {
+		share = fmt.Sprintf("%.1f%%", s.CostUSD/totalCost*100)
+	}
+	return []string{
+		rank,
+		s.Label,
+		s.FirstSeen.Local().Format("2006-01-02 15:04"),
+		display.FormatDurationLong(s.Duration, 3),
+		display.FormatNumber(s.Requests),
+		display.FormatNumber(s.TotalTokens),
+		fmt.Sprintf("%.1f%%", s.CacheHitRate),
+		display.FormatCost(s.CostUSD),
+		share,
+	}
+}
+
+// projectSessionFiles returns the discovered sessions of the project at
+// path. path may be the project's working directory, the Claude project
+// directory, or that directory's name.
+func projectSessionFiles(discovered []discovery.SessionFile, path string) []discovery.SessionFile {
+	names := map[string]bool{path: true}
+	if abs, err := filepath.Abs(path); err == nil {
+		names[discovery.ProjectDirName(abs)] = true
+		names[abs] = true
+	}
+
+	var files []discovery.SessionFile
+	for _, f := range discovered {
+		if names[f.ProjectPath] || names[filepath.Base(f.ProjectPath)] {
+			files = append(files, f)
+		}
+	}
+	return files
+}
+
+// compareProjectSessions orders the sessions by start time and totals
+// them. The total duration is the sum of session durations, and its
+// cache hit rate is computed from the summed cache tokens.
+func compareProjectSessions(project string, analyses []analysis.SessionAnalysis) projectComparison {
+	sort.Slice(analyses, func(i, j int) bool {
+		return analyses[i].FirstSeen.Before(analyses[j].FirstSeen)
+	})
+
+	result := projectComparison{
+		Project:  project,
+		Sessions: make([]projectSession, 0, len(analyses)),
+		Total:    projectSession{Label: "TOTAL"},
+	}
+	var cacheCreation, cacheRead int
+	for _, a := range analyses {
+		s := projectSession{
+			SessionID:    a.SessionID,
+			Label:        a.Label,
+			FirstSeen:    a.FirstSeen,
+			LastSeen:     a.LastSeen,
+			Duration:     a.Duration,
+			DurationSec:  a.Duration.Seconds(),
+			Requests:     a.EntryCount,
+			TotalTokens:  a.TotalTokens,
+			CacheHitRate: a.CacheHitRate,
+			CostUSD:      a.CostUSD,
+		}
+		result.Sessions = append(result.Sessions, s)
+
+		t := &result.Total
+		if t.FirstSeen.IsZero() || s.FirstSeen.Before(t.FirstSeen) {
+			t.FirstSeen = s.FirstSeen
+		}
+		if s.LastSeen.After(t.LastSeen) {
+			t.LastSeen = s.LastSeen
+		}
+		t.Duration += s.Duration
+		t.Requests += s.Requests
+		t.TotalTokens += s.TotalTokens
+		t.CostUSD += s.CostUSD
+		cacheCreation += a.CacheCreation
+		cacheRead += a.CacheRead
+	}
+
+	result.Total.DurationSec = result.Total.Duration.Seconds()
+	if cacheTotal := cacheCreation + cacheRead; cacheTotal > 0 {
+		result.Total.CacheHitRate = float64(cacheRead) / float64(cacheTotal) * 100
+	}
+	return result
+}
+
+// shortSessionID abbreviates a session UUID to its first block.
+func shortSessionID(id string) string {
+	if len(id) > 8 {
+		return id[:8]
+	}
+	return id
+}
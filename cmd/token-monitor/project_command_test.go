@@ -0,0 +1,63 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/0xmhha/token-monitor/pkg/analysis"
+	"github.com/0xmhha/token-monitor/pkg/discovery"
+)
+
+func TestProjectSessionFiles(t *testing.T) {
+	work := t.TempDir()
+	projectDir := filepath.Join("/home/me/.claude/projects", discovery.ProjectDirName(work))
+	discovered := []discovery.SessionFile{
+		{SessionID: "a", ProjectPath: projectDir},
+		{SessionID: "b", ProjectPath: "/home/me/.claude/projects/-other"},
+		{SessionID: "c", ProjectPath: projectDir},
+	}
+
+	for _, path := range []string{work, projectDir, filepath.Base(projectDir)} {
+		files := projectSessionFiles(discovered, path)
+		if len(files) != 2 || files[0].SessionID != "a" || files[1].SessionID != "c" {
+			t.Errorf("projectSessionFiles(%q) = %+v, want sessions a and c", path, files)
+		}
+	}
+	if files := projectSessionFiles(discovered, filepath.Join(work, "nested")); len(files) != 0 {
+		t.Errorf("projectSessionFiles(nested) = %+v, want none", files)
+	}
+}
+
+func TestCompareProjectSessions(t *testing.T) {
+	start := time.Date(2025, 11, 1, 10, 0, 0, 0, time.UTC)
+	analyses := []analysis.SessionAnalysis{
+		{
+			SessionID: "late", FirstSeen: start.Add(24 * time.Hour), LastSeen: start.Add(25 * time.Hour),
+			Duration: time.Hour, EntryCount: 3, TotalTokens: 300, CacheRead: 90, CacheCreation: 10,
+			CacheHitRate: 90, CostUSD: 3,
+		},
+		{
+			SessionID: "early", FirstSeen: start, LastSeen: start.Add(30 * time.Minute),
+			Duration: 30 * time.Minute, EntryCount: 1, TotalTokens: 100, CacheCreation: 100,
+			CostUSD: 1,
+		},
+	}
+
+	result := compareProjectSessions("p", analyses)
+	if len(result.Sessions) != 2 || result.Sessions[0].SessionID != "early" {
+		t.Fatalf("Sessions = %+v, want oldest first", result.Sessions)
+	}
+
+	total := result.Total
+	if total.Requests != 4 || total.TotalTokens != 400 || total.CostUSD != 4 || total.Duration != 90*time.Minute {
+		t.Errorf("Total = %+v, want 4 requests, 400 tokens, $4, 90m", total)
+	}
+	if !total.FirstSeen.Equal(start) || !total.LastSeen.Equal(start.Add(25*time.Hour)) {
+		t.Errorf("Total span = %v..%v", total.FirstSeen, total.LastSeen)
+	}
+	// 90 cache reads out of 200 cache tokens.
+	if total.CacheHitRate != 45 {
+		t.Errorf("Total.CacheHitRate = %v, want 45", total.CacheHitRate)
+	}
+}
@@ -8,13 +8,13 @@ import (
 	"strings"
 	"time"
 
+	"github.com/0xmhha/token-monitor/cmd/internal/runtime"
 	"github.com/0xmhha/token-monitor/pkg/aggregator"
-	"github.com/0xmhha/token-monitor/pkg/config"
 	"github.com/0xmhha/token-monitor/pkg/discovery"
 	"github.com/0xmhha/token-monitor/pkg/display"
-	"github.com/0xmhha/token-monitor/pkg/logger"
-	"github.com/0xmhha/token-monitor/pkg/parser"
-	"github.com/0xmhha/token-monitor/pkg/reader"
+	"github.com/0xmhha/token-monitor/pkg/query"
+	"github.com/0xmhha/token-monitor/pkg/session"
+	"github.com/0xmhha/token-monitor/pkg/sessionloader"
 )
 
 // validMetrics is the set of accepted --metric values.
@@ -51,29 +51,32 @@ type queryCommand struct {
 	metric     string
 	jsonOutput bool
 	format     string
+	expression string
 	configPath string
 	globalOpts globalOptions
 }
 
 // Execute runs the query command.
 func (c *queryCommand) Execute() error {
-	if !c.current && c.sessionID == "" {
-		return fmt.Errorf("specify --current or --session <id>\n  Example: token-monitor query --current --metric total")
+	if c.expression != "" {
+		return c.runExpression()
 	}
 
-	cfg, err := config.NewLoader(c.configPath).Load()
-	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
+	if !c.current && c.sessionID == "" {
+		return fmt.Errorf("specify --current or --session <id>\n  Example: token-monitor query --current --metric total")
 	}
 
-	log := c.buildLogger(cfg)
+	rt := c.newRuntime()
+	defer func() {
+		_ = rt.Close() //nolint:errcheck // best effort cleanup
+	}()
 
-	sessFile, err := c.resolveSession(cfg, log)
+	sessFile, err := c.resolveSession(rt)
 	if err != nil {
 		return err
 	}
 
-	agg, err := c.parseAndAggregate(sessFile.FilePath, log)
+	agg, err := c.parseAndAggregate(rt, sessFile.FilePath)
 	if err != nil {
 		return err
 	}
@@ -93,22 +96,17 @@ func (c *queryCommand) Execute() error {
 	return c.printMetric(c.metric, agg)
 }
 
-// buildLogger creates a silent logger suitable for hook use.
-func (c *queryCommand) buildLogger(cfg *config.Config) logger.Logger {
-	level := "error" // suppress noise during hook execution
-	if c.globalOpts.logLevel != "" {
-		level = c.globalOpts.logLevel
-	}
-	return logger.New(logger.Config{
-		Level:  level,
-		Format: cfg.Logging.Format,
-		Output: cfg.Logging.Output,
-	})
+// newRuntime creates a runtime with a silent logger suitable for hook use.
+func (c *queryCommand) newRuntime() *runtime.Runtime {
+	return c.globalOpts.newRuntime("error") // suppress noise during hook execution
 }
 
 // resolveSession returns the session file based on flags.
-func (c *queryCommand) resolveSession(cfg *config.Config, log logger.Logger) (*discovery.SessionFile, error) {
-	disc := discovery.New(cfg.ClaudeConfigDirs, log)
+func (c *queryCommand) resolveSession(rt *runtime.Runtime) (*discovery.SessionFile, error) {
+	disc, err := rt.Discoverer()
+	if err != nil {
+		return nil, err
+	}
 
 	if c.current {
 		return disc.FindCurrentSession()
@@ -130,17 +128,14 @@ func (c *queryCommand) findByID(disc discovery.Discoverer, sessionID string) (*d
 		}
 	}
 
-	return nil, fmt.Errorf("session not found: %s", sessionID)
+	return nil, fmt.Errorf("%w: %s", session.ErrSessionNotFound, sessionID)
 }
 
 // parseAndAggregate reads the file and returns a populated aggregator.
-func (c *queryCommand) parseAndAggregate(filePath string, log logger.Logger) (aggregator.Aggregator, error) {
-	r, err := reader.New(reader.Config{
-		PositionStore: reader.NewMemoryPositionStore(),
-		Parser:        parser.New(),
-	}, log)
+func (c *queryCommand) parseAndAggregate(rt *runtime.Runtime, filePath string) (aggregator.Aggregator, error) {
+	r, err := rt.NewReader()
 	if err != nil {
-		return nil, fmt.Errorf("failed to create reader: %w", err)
+		return nil, err
 	}
 	defer r.Close() //nolint:errcheck
 
@@ -230,3 +225,66 @@ func (c *queryCommand) printHook(agg aggregator.Aggregator) error {
 	fmt.Printf("Total: %d | Rate: %.1f/min\n", stats.TotalTokens, burnRate.TokensPerMinute)
 	return nil
 }
+
+// runExpression evaluates a query-language expression over all sessions,
+// or over one session when --current or --session is given.
+func (c *queryCommand) runExpression() error {
+	q, err := query.Parse(c.expression)
+	if err != nil {
+		return err
+	}
+
+	rt := c.newRuntime()
+	defer func() {
+		_ = rt.Close() //nolint:errcheck // best effort cleanup
+	}()
+
+	log, err := rt.Logger()
+	if err != nil {
+		return err
+	}
+
+	var sessions []discovery.SessionFile
+	if c.current || c.sessionID != "" {
+		sessFile, resolveErr := c.resolveSession(rt)
+		if resolveErr != nil {
+			return resolveErr
+		}
+		sessions = []discovery.SessionFile{*sessFile}
+	} else {
+		disc, discErr := rt.Discoverer()
+		if discErr != nil {
+			return discErr
+		}
+		sessions, err = disc.Discover()
+		if err != nil {
+			return fmt.Errorf("failed to discover sessions: %w", err)
+		}
+	}
+
+	entries, err := sessionloader.LoadEntries(context.Background(), sessions, rt.NewReader, log)
+	if err != nil {
+		return err
+	}
+
+	result, err := q.Run(entries)
+	if err != nil {
+		return err
+	}
+
+	if c.jsonOutput || c.globalOpts.jsonOutput {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(result.Records())
+	}
+
+	rows := make([][]string, len(result.Rows))
+	for i, row := range result.Rows {
+		cells := make([]string, len(row))
+		for j, v := range row {
+			cells[j] = query.FormatValue(v)
+		}
+		rows[i] = cells
+	}
+	return display.WriteTable(os.Stdout, result.Columns, rows, false)
+}
@@ -0,0 +1,385 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/term"
+
+	"github.com/0xmhha/token-monitor/cmd/internal/runtime"
+	"github.com/0xmhha/token-monitor/pkg/aggregator"
+	"github.com/0xmhha/token-monitor/pkg/logger"
+	"github.com/0xmhha/token-monitor/pkg/session"
+)
+
+// replPrompt is shown before each REPL input line.
+const replPrompt = "token-monitor> "
+
+// replCommands are the commands understood inside the REPL.
+var replCommands = []string{"stats", "list", "reload", "history", "help", "exit", "quit"}
+
+// replStatsFlags are the stats flags offered by tab completion.
+var replStatsFlags = []string{"-session", "-model", "-group-by", "-top", "-format", "-compact", "-cost", "-rate"}
+
+// replFlagValues are completion candidates for stats flag values.
+var replFlagValues = map[string][]string{
+	"-group-by": {"model", "session", "date", "hour", "source", "subagent"},
+	"-format":   {"table", "json", "simple"},
+}
+
+// replCommand runs an interactive prompt against a dataset loaded once.
+type replCommand struct {
+	configPath string
+	globalOpts globalOptions
+
+	rt         *runtime.Runtime
+	log        logger.Logger
+	sessionMgr session.Manager
+	sessions   []loadedSession
+	names      []string
+	history    []string
+}
+
+// runReplCommand runs the repl command.
+func runReplCommand(globalOpts globalOptions, _ []string) error {
+	cmd := &replCommand{
+		configPath: globalOpts.configPath,
+		globalOpts: globalOpts,
+	}
+	return cmd.Execute()
+}
+
+// Execute loads the dataset and runs the read-eval-print loop until
+// exit, quit, or end of input.
+func (c *replCommand) Execute() error {
+	c.rt = c.globalOpts.newRuntime("")
+	defer func() {
+		_ = c.rt.Close() //nolint:errcheck // best effort cleanup
+	}()
+
+	var err error
+	if c.log, err = c.rt.Logger(); err != nil {
+		return err
+	}
+
+	// Session names are optional; the REPL works on raw UUIDs without BoltDB.
+	c.sessionMgr, err = c.rt.Sessions()
+	if err != nil {
+		c.log.Warn("session names unavailable", "error", err)
+		c.sessionMgr = nil
+	}
+
+	if err := c.load(); err != nil {
+		return err
+	}
+
+	fd := int(os.Stdin.Fd())
+	if term.IsTerminal(fd) {
+		return c.loop(newTerminalLineReader(fd, c.complete))
+	}
+	return c.loop(&scannerLineReader{scanner: bufio.NewScanner(os.Stdin)})
+}
+
+// load discovers and parses all sessions into memory.
+// Every file is read from the start so the dataset is complete.
+func (c *replCommand) load() error {
+	start := time.Now()
+
+	disc, err := c.rt.Discoverer()
+	if err != nil {
+		return err
+	}
+	files, err := disc.Discover()
+	if err != nil {
+		return fmt.Errorf("failed to discover sessions: %w", err)
+	}
+
+	r, err := c.rt.NewReader()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = r.Close() //nolint:errcheck // best effort cleanup
+	}()
+
+	ctx := context.Background()
+	sessions := make([]loadedSession, 0, len(files))
+	entryCount := 0
+	for _, file := range files {
+		entries, readErr := r.Read(ctx, file.FilePath)
+		if readErr != nil {
+			c.log.Warn("failed to read session",
+				"session", file.SessionID,
+				"path", file.FilePath,
+				"error", readErr)
+			continue
+		}
+		sessions = append(sessions, loadedSession{file: file, entries: entries})
+		entryCount += len(entries)
+	}
+	c.sessions = sessions
+
+	c.names = nil
+	if c.sessionMgr != nil {
+		if metadata, listErr := c.sessionMgr.List(); listErr == nil {
+			for _, m := range metadata {
+				if m.Name != "" {
+					c.names = append(c.names, m.Name)
+				}
+			}
+		}
+	}
+
+	c.globalOpts.infof("Loaded %d session(s), %d entries in %s\n",
+		len(sessions), entryCount, time.Since(start).Round(time.Millisecond))
+	return nil
+}
+
+// lineReader reads one line of REPL input.
+type lineReader interface {
+	ReadLine() (string, error)
+}
+
+// loop reads and executes lines until exit or end of input.
+// Command errors are reported and the loop continues.
+func (c *replCommand) loop(in lineReader) error {
+	out := c.globalOpts.output()
+	for {
+		line, err := in.ReadLine()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read input: %w", err)
+		}
+
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		c.history = append(c.history, line)
+
+		quit, err := c.execLine(line)
+		if err != nil {
+			out.Warnf("Error: %v\n", err)
+		}
+		if quit {
+			return nil
+		}
+	}
+}
+
+// execLine runs a single REPL command line.
+// Returns true when the REPL should exit.
+func (c *replCommand) execLine(line string) (bool, error) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return false, nil
+	}
+
+	switch fields[0] {
+	case "exit", "quit":
+		return true, nil
+	case "help":
+		fmt.Print(replHelp)
+		return false, nil
+	case "stats":
+		return false, c.runStats(fields[1:])
+	case "list":
+		c.listSessions()
+		return false, nil
+	case "reload":
+		return false, c.load()
+	case "history":
+		for i, entry := range c.history {
+			fmt.Printf("%4d  %s\n", i+1, entry)
+		}
+		return false, nil
+	default:
+		return false, fmt.Errorf("%w: %s (type help for commands)", errUnknownCommand, fields[0])
+	}
+}
+
+// runStats runs a stats command line against the loaded dataset.
+func (c *replCommand) runStats(args []string) error {
+	cmd, err := parseStatsCommand(c.globalOpts, args, flag.ContinueOnError)
+	if errors.Is(err, flag.ErrHelp) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if cmd.againstBaseline != "" {
+		return fmt.Errorf("-against-baseline is not available in repl")
+	}
+	cmd.sessionID = resolveSessionIdentifier(c.sessionMgr, cmd.sessionID)
+	if cfg, err := c.rt.Config(); err == nil {
+		cmd.footprint = cfg.Footprint.Rates()
+	}
+
+	dimensions, err := cmd.parseDimensions()
+	if err != nil {
+		return err
+	}
+	modelFilter, err := aggregator.ParseModelFilter(cmd.model)
+	if err != nil {
+		return err
+	}
+
+	return cmd.displayResults(cmd.aggregate(c.sessions, dimensions, modelFilter))
+}
+
+// listSessions prints the loaded sessions with their entry counts.
+func (c *replCommand) listSessions() {
+	for _, sess := range c.sessions {
+		fmt.Printf("  %s  %6d entries  %s\n", sess.file.SessionID, len(sess.entries), sess.file.ProjectPath)
+	}
+	fmt.Printf("%d session(s)\n", len(c.sessions))
+}
+
+// complete is the tab-completion callback for the terminal line editor.
+// It completes command names, stats flags, and known flag values.
+func (c *replCommand) complete(line string, pos int, key rune) (string, int, bool) {
+	if key != '\t' {
+		return "", 0, false
+	}
+
+	head := line[:pos]
+	start := strings.LastIndexAny(head, " \t") + 1
+	word := head[start:]
+	previous := strings.Fields(head[:start])
+
+	var candidates []string
+	switch {
+	case len(previous) == 0:
+		candidates = replCommands
+	case previous[0] != "stats":
+		return "", 0, false
+	case strings.HasPrefix(word, "-"):
+		candidates = replStatsFlags
+	case previous[len(previous)-1] == "-session":
+		candidates = c.sessionCandidates()
+	default:
+		candidates = replFlagValues[previous[len(previous)-1]]
+	}
+
+	completion, ok := completeWord(word, candidates)
+	if !ok {
+		return "", 0, false
+	}
+	return head[:start] + completion + line[pos:], start + len(completion), true
+}
+
+// sessionCandidates returns session names and IDs for -session completion.
+func (c *replCommand) sessionCandidates() []string {
+	candidates := append([]string(nil), c.names...)
+	for _, sess := range c.sessions {
+		candidates = append(candidates, sess.file.SessionID)
+	}
+	return candidates
+}
+
+// completeWord extends word to the longest common prefix of the matching
+// candidates. A unique match is completed with a trailing space.
+func completeWord(word string, candidates []string) (string, bool) {
+	var matches []string
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, word) {
+			matches = append(matches, candidate)
+		}
+	}
+	if len(matches) == 0 {
+		return "", false
+	}
+	if len(matches) == 1 {
+		return matches[0] + " ", true
+	}
+
+	sort.Strings(matches)
+	prefix := commonPrefix(matches[0], matches[len(matches)-1])
+	if len(prefix) <= len(word) {
+		return "", false
+	}
+	return prefix, true
+}
+
+// commonPrefix returns the longest common prefix of a and b.
+func commonPrefix(a, b string) string {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+	return a[:n]
+}
+
+// terminalLineReader reads lines with editing, history (up/down), and
+// tab completion. Raw mode is only held while a line is being read so
+// command output is printed normally.
+type terminalLineReader struct {
+	fd       int
+	terminal *term.Terminal
+}
+
+// newTerminalLineReader creates a line editor on stdin/stdout.
+func newTerminalLineReader(fd int, complete func(string, int, rune) (string, int, bool)) *terminalLineReader {
+	rw := struct {
+		io.Reader
+		io.Writer
+	}{os.Stdin, os.Stdout}
+
+	t := term.NewTerminal(rw, replPrompt)
+	t.AutoCompleteCallback = complete
+	if width, height, err := term.GetSize(fd); err == nil {
+		_ = t.SetSize(width, height) //nolint:errcheck // keeps default size on failure
+	}
+	return &terminalLineReader{fd: fd, terminal: t}
+}
+
+// ReadLine implements lineReader.
+func (r *terminalLineReader) ReadLine() (string, error) {
+	oldState, err := term.MakeRaw(r.fd)
+	if err != nil {
+		return "", fmt.Errorf("failed to enable raw mode: %w", err)
+	}
+	defer func() {
+		_ = term.Restore(r.fd, oldState) //nolint:errcheck // best-effort terminal restoration
+	}()
+	return r.terminal.ReadLine()
+}
+
+// scannerLineReader reads lines from non-interactive input such as a pipe.
+type scannerLineReader struct {
+	scanner *bufio.Scanner
+}
+
+// ReadLine implements lineReader.
+func (r *scannerLineReader) ReadLine() (string, error) {
+	if r.scanner.Scan() {
+		return r.scanner.Text(), nil
+	}
+	if err := r.scanner.Err(); err != nil {
+		return "", err
+	}
+	return "", io.EOF
+}
+
+// replHelp describes the REPL commands.
+const replHelp = `Commands:
+  stats [flags]   Statistics over the loaded dataset (same flags as "token-monitor stats")
+  list            List loaded sessions
+  reload          Re-discover and re-read all session files
+  history         Show commands entered this session
+  help            Show this help
+  exit, quit      Leave the REPL (Ctrl-D also works)
+
+Use Tab to complete commands, stats flags, sessions, and flag values;
+Up/Down recall previous commands.
+`
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	"github.com/0xmhha/token-monitor/pkg/discovery"
+)
+
+func TestCompleteWord(t *testing.T) {
+	tests := []struct {
+		word       string
+		candidates []string
+		want       string
+		wantOK     bool
+	}{
+		{"st", []string{"stats", "list"}, "stats ", true},
+		{"-c", []string{"-compact", "-cost"}, "-co", true},
+		{"-co", []string{"-compact", "-cost"}, "", false},
+		{"x", []string{"stats"}, "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := completeWord(tt.word, tt.candidates)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("completeWord(%q) = %q, %v; want %q, %v", tt.word, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestReplComplete(t *testing.T) {
+	c := &replCommand{
+		sessions: []loadedSession{{file: discovery.SessionFile{SessionID: "abc-123"}}},
+		names:    []string{"work"},
+	}
+
+	tests := []struct {
+		line   string
+		want   string
+		wantOK bool
+	}{
+		{"sta", "stats ", true},
+		{"stats -gr", "stats -group-by ", true},
+		{"stats -group-by mo", "stats -group-by model ", true},
+		{"stats -session ab", "stats -session abc-123 ", true},
+		{"stats -session wo", "stats -session work ", true},
+		{"list -x", "", false},
+	}
+
+	for _, tt := range tests {
+		got, pos, ok := c.complete(tt.line, len(tt.line), '\t')
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("complete(%q) = %q, %v; want %q, %v", tt.line, got, ok, tt.want, tt.wantOK)
+		}
+		if ok && pos != len(tt.want) {
+			t.Errorf("complete(%q) pos = %d, want %d", tt.line, pos, len(tt.want))
+		}
+	}
+
+	if _, _, ok := c.complete("sta", 3, 'a'); ok {
+		t.Error("complete() should ignore keys other than Tab")
+	}
+}
+
+func TestReplLoop(t *testing.T) {
+	c := &replCommand{globalOpts: globalOptions{quiet: true}}
+	input := "\nhistory\nbogus\nquit\nlist\n"
+
+	if err := c.loop(&scannerLineReader{scanner: bufio.NewScanner(strings.NewReader(input))}); err != nil {
+		t.Fatalf("loop() error = %v", err)
+	}
+
+	want := []string{"history", "bogus", "quit"}
+	if strings.Join(c.history, ",") != strings.Join(want, ",") {
+		t.Errorf("history = %v, want %v (loop should stop at quit)", c.history, want)
+	}
+}
@@ -0,0 +1,460 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/0xmhha/token-monitor/cmd/internal/runtime"
+	"github.com/0xmhha/token-monitor/pkg/analysis"
+	"github.com/0xmhha/token-monitor/pkg/discovery"
+	"github.com/0xmhha/token-monitor/pkg/display"
+	"github.com/0xmhha/token-monitor/pkg/i18n"
+	"github.com/0xmhha/token-monitor/pkg/reader"
+	"github.com/0xmhha/token-monitor/pkg/rollup"
+)
+
+// rollupInterval is how often watch folds new entries into the rollups.
+const rollupInterval = 30 * time.Second
+
+// progressInterval is how often report prints progress while ingesting.
+const progressInterval = 2 * time.Second
+
+// reportCommand prints usage totals from the pre-aggregated rollup store.
+type reportCommand struct {
+	days       int
+	from       string
+	to         string
+	groupBy    []rollup.Dimension
+	rebuild    bool
+	format     string
+	units      display.Units
+	detailed   bool
+	compact    bool
+	weekday    bool
+	weeks      int
+	configPath string
+	globalOpts globalOptions
+}
+
+// runReportCommand parses flags and runs the report command.
+func runReportCommand(globalOpts globalOptions, args []string) error {
+	fs := flag.NewFlagSet("report", flag.ExitOnError)
+	days := fs.Int("days", 30, "report the last N days (ignored when -from is set)")
+	from := fs.String("from", "", "start date (YYYY-MM-DD, inclusive)")
+	to := fs.String("to", "", "end date (YYYY-MM-DD, inclusive)")
+	groupBy := fs.String("group-by", "date", "group by dimensions (comma-separated: date,model,session)")
+	rebuild := fs.Bool("rebuild", false, "recompute rollups of sessions that still have session files")
+	format := fs.String("format", "table", "output format (table, json)")
+	units := fs.String("units", globalOpts.units, "primary table measure (tokens, k, cost)")
+	detailed := fs.Bool("detailed", false, "with -units cost, also show token columns")
+	compact := fs.Bool("compact", false, "compact table: no separator line, single-space columns")
+	weekday := fs.Bool("weekday", false, "show average daily usage per weekday instead of totals")
+	weeks := fs.Int("weeks", 4, "with -weekday, average over the last N complete weeks")
+
+	if err := globalOpts.applyFlagDefaults(fs); err != nil {
+		return err
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	tableUnits, err := display.ParseUnits(*units)
+	if err != nil {
+		return err
+	}
+	if *weekday && *weeks < 1 {
+		return fmt.Errorf("invalid -weeks: %d (must be at least 1)", *weeks)
+	}
+
+	dims, err := parseRollupDimensions(*groupBy)
+	if err != nil {
+		return err
+	}
+
+	outputFormat := *format
+	if globalOpts.jsonOutput {
+		outputFormat = "json"
+	}
+
+	cmd := &reportCommand{
+		days:       *days,
+		from:       *from,
+		to:         *to,
+		groupBy:    dims,
+		rebuild:    *rebuild,
+		format:     outputFormat,
+		units:      tableUnits,
+		detailed:   *detailed,
+		compact:    *compact,
+		weekday:    *weekday,
+		weeks:      *weeks,
+		configPath: globalOpts.configPath,
+		globalOpts: globalOpts,
+	}
+	return cmd.Execute()
+}
+
+// parseRollupDimensions parses a comma-separated rollup dimension list.
+func parseRollupDimensions(spec string) ([]rollup.Dimension, error) {
+	var dims []rollup.Dimension
+	for _, part := range strings.Split(spec, ",") {
+		switch dim := rollup.Dimension(strings.TrimSpace(part)); dim {
+		case "":
+			continue
+		case rollup.DimDate, rollup.DimModel, rollup.DimSession:
+			dims = append(dims, dim)
+		default:
+			return nil, fmt.Errorf("invalid dimension: %s", dim)
+		}
+	}
+	return dims, nil
+}
+
+// Execute catches the rollups up with new entries and prints the report.
+func (c *reportCommand) Execute() error {
+	rt := c.globalOpts.newRuntime("")
+	defer func() {
+		_ = rt.Close() //nolint:errcheck // best effort cleanup
+	}()
+
+	cfg, err := rt.Config()
+	if err != nil {
+		return err
+	}
+	if _, err := rt.Sessions(); err != nil {
+		return fmt.Errorf("rollup store unavailable (is watch running?): %w", err)
+	}
+	store, err := rt.Rollups()
+	if err != nil {
+		return err
+	}
+
+	update := ingestRollups
+	if c.rebuild {
+		update = rebuildRollups
+	}
+	stop := c.showProgress(rt, store)
+	stats, err := update(context.Background(), rt, store)
+	stop()
+	if err != nil {
+		return err
+	}
+	if stats.Entries > 0 {
+		c.globalOpts.infof("Ingested %d new entries from %d file(s)\n", stats.Entries, stats.Files)
+	}
+
+	if c.weekday {
+		from, to := c.weekdayRange(time.Now())
+		rows, err := store.Rows(from.Format(rollup.DateLayout), to.Format(rollup.DateLayout))
+		if err != nil {
+			return fmt.Errorf("failed to read rollups: %w", err)
+		}
+		return c.displayWeekdays(rollup.WeekdayAverages(rows, from, to))
+	}
+
+	from, to := c.dateRange(time.Now())
+	rows, err := store.Rows(from, to)
+	if err != nil {
+		return fmt.Errorf("failed to read rollups: %w", err)
+	}
+
+	if err := c.display(rollup.Summarize(rows, c.groupBy), rowFootprints(rows, c.groupBy, cfg.Footprint.Rates())); err != nil {
+		return err
+	}
+	if c.format != "json" {
+		c.displayCaching(rowCaching(rows))
+	}
+	return nil
+}
+
+// rowCaching prices rows with prompt caching as recorded, without it, and
+// with every input cached. Rows keep their model and date, so each is
+// priced as of its day.
+func rowCaching(rows []rollup.Row) analysis.CacheWhatIf {
+	var caching analysis.CacheWhatIf
+	for _, row := range rows {
+		day, err := time.ParseInLocation(rollup.DateLayout, row.Date, time.Local)
+		if err != nil {
+			continue
+		}
+		caching.Add(row.Model, day, row.InputTokens, row.OutputTokens, row.CacheCreationTokens, row.CacheReadTokens)
+	}
+	return caching
+}
+
+// displayCaching prints what prompt caching saved over the report period,
+// when any cache was used.
+func (c *reportCommand) displayCaching(caching analysis.CacheWhatIf) {
+	if caching.Saved() <= 0 {
+		return
+	}
+	c.globalOpts.output().Printf(i18n.T("report.cache_saved")+"\n",
+		display.FormatCost(caching.Saved()), display.FormatCost(caching.NoCacheCost))
+}
+
+// rowFootprints estimates the footprint of each group Summarize forms
+// from rows. Rows keep their model, so each is estimated at its model's
+// rate before grouping. It returns nil when no rates are configured.
+func rowFootprints(rows []rollup.Row, dims []rollup.Dimension, rates analysis.FootprintRates) map[rollup.Key]float64 {
+	if len(rates) == 0 {
+		return nil
+	}
+	grams := make(map[rollup.Key]float64)
+	for _, row := range rows {
+		grams[row.Key.Project(dims)] += rates.Grams(row.Model, row.TotalTokens())
+	}
+	return grams
+}
+
+// footprintRow is a report row with its estimated footprint, for JSON
+// output when footprint rates are configured.
+type footprintRow struct {
+	rollup.Row
+	CO2eGrams float64 `json:"co2e_grams"`
+}
+
+// dateRange returns the inclusive report date bounds.
+func (c *reportCommand) dateRange(now time.Time) (string, string) {
+	from := c.from
+	if from == "" && c.days > 0 {
+		from = now.AddDate(0, 0, -(c.days - 1)).Format(rollup.DateLayout)
+	}
+	return from, c.to
+}
+
+// weekdayRange returns the last c.weeks complete weeks, ending yesterday,
+// so every weekday occurs exactly c.weeks times and today's partial usage
+// does not drag its weekday down.
+func (c *reportCommand) weekdayRange(now time.Time) (time.Time, time.Time) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	to := today.AddDate(0, 0, -1)
+	return to.AddDate(0, 0, -(c.weeks*7 - 1)), to
+}
+
+// displayWeekdays prints per-weekday averages as a table or JSON.
+func (c *reportCommand) displayWeekdays(avgs []rollup.WeekdayAverage) error {
+	if c.format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(avgs)
+	}
+
+	costFirst := c.units == display.UnitsCost
+	showTokens := !costFirst || c.detailed
+
+	header := []string{i18n.T("report.weekday"), i18n.T("report.active_days"), i18n.T("report.avg_entries")}
+	if costFirst {
+		header = append(header, i18n.T("report.avg_cost"))
+	}
+	if showTokens {
+		header = append(header, i18n.T("report.avg_total"))
+	}
+	if !costFirst {
+		header = append(header, i18n.T("report.avg_cost"))
+	}
+
+	table := make([][]string, 0, len(avgs))
+	for _, avg := range avgs {
+		cells := []string{
+			i18n.T("weekday." + strings.ToLower(avg.Name)),
+			fmt.Sprintf("%d/%d", avg.ActiveDays, avg.Days),
+			fmt.Sprintf("%.1f", avg.Entries),
+		}
+		if costFirst {
+			cells = append(cells, display.FormatCost(avg.CostUSD))
+		}
+		if showTokens {
+			cells = append(cells, display.FormatTokens(int(math.Round(avg.TotalTokens)), c.units))
+		}
+		if !costFirst {
+			cells = append(cells, display.FormatCost(avg.CostUSD))
+		}
+		table = append(table, cells)
+	}
+
+	return display.WriteTable(os.Stdout, header, table, c.compact)
+}
+
+// display prints report rows as a table or JSON. footprint holds the
+// estimated CO2e per row key; nil leaves the footprint out.
+func (c *reportCommand) display(rows []rollup.Row, footprint map[rollup.Key]float64) error {
+	if c.format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if footprint == nil {
+			return enc.Encode(rows)
+		}
+		withFootprint := make([]footprintRow, len(rows))
+		for i, row := range rows {
+			withFootprint[i] = footprintRow{Row: row, CO2eGrams: footprint[row.Key]}
+		}
+		return enc.Encode(withFootprint)
+	}
+
+	var header []string
+	for _, dim := range c.groupBy {
+		header = append(header, i18n.T("report."+string(dim)))
+	}
+	// Token units keep the cost column, which the report always had; cost
+	// units lead with it and show tokens only when detailed.
+	costFirst := c.units == display.UnitsCost
+	showTokens := !costFirst || c.detailed
+
+	header = append(header, i18n.T("report.entries"))
+	if costFirst {
+		header = append(header, i18n.T("report.cost"))
+	}
+	if showTokens {
+		header = append(header,
+			i18n.T("report.input"),
+			i18n.T("report.output"),
+			i18n.T("report.cache"),
+			i18n.T("report.total"),
+		)
+	}
+	if !costFirst {
+		header = append(header, i18n.T("report.cost"))
+	}
+	if footprint != nil {
+		header = append(header, i18n.T("report.co2e"))
+	}
+
+	table := make([][]string, 0, len(rows))
+	for _, row := range rows {
+		var cells []string
+		for _, dim := range c.groupBy {
+			switch dim {
+			case rollup.DimDate:
+				cells = append(cells, row.Date)
+			case rollup.DimModel:
+				cells = append(cells, row.Model)
+			case rollup.DimSession:
+				cells = append(cells, row.SessionID)
+			}
+		}
+		cells = append(cells, display.FormatNumber(row.Entries))
+		if costFirst {
+			cells = append(cells, display.FormatCost(row.CostUSD))
+		}
+		if showTokens {
+			cells = append(cells,
+				display.FormatTokens(row.InputTokens, c.units),
+				display.FormatTokens(row.OutputTokens, c.units),
+				display.FormatTokens(row.CacheCreationTokens+row.CacheReadTokens, c.units),
+				display.FormatTokens(row.TotalTokens(), c.units),
+			)
+		}
+		if !costFirst {
+			cells = append(cells, display.FormatCost(row.CostUSD))
+		}
+		if footprint != nil {
+			cells = append(cells, display.FormatCO2e(footprint[row.Key]))
+		}
+		table = append(table, cells)
+	}
+
+	return display.WriteTable(os.Stdout, header, table, c.compact)
+}
+
+// showProgress prints the total ingest progress across all session files
+// every progressInterval until the returned function is called, and notes
+// when an interrupted rebuild or ingest is being resumed.
+func (c *reportCommand) showProgress(rt *runtime.Runtime, store rollup.Store) (stop func()) {
+	disc, err := rt.Discoverer()
+	if err != nil {
+		return func() {}
+	}
+	files, err := disc.Discover()
+	if err != nil {
+		return func() {}
+	}
+	p, err := store.Progress(files)
+	if err != nil {
+		return func() {}
+	}
+	switch {
+	case c.rebuild && p.Rebuilding:
+		c.globalOpts.infof("Resuming interrupted rebuild: %s\n", p)
+	case !c.rebuild && p.BytesDone > 0 && p.Percent() < 100:
+		c.globalOpts.infof("Resuming ingest: %s\n", p)
+	}
+
+	done := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		ticker := time.NewTicker(progressInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if p, err := store.Progress(files); err == nil {
+					c.globalOpts.infof("Ingesting: %s\n", p)
+				}
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-finished
+	}
+}
+
+// ingestRollups folds entries appended since the last ingest into store.
+// Files are read with a private in-memory position store; the rollup store
+// tracks its own offsets so other readers cannot cause skipped entries.
+func ingestRollups(ctx context.Context, rt *runtime.Runtime, store rollup.Store) (rollup.IngestStats, error) {
+	return updateRollups(ctx, rt, store.Ingest)
+}
+
+// rebuildRollups recomputes the rollups of sessions that still have session
+// files. Rollups of sessions whose files were deleted are kept.
+func rebuildRollups(ctx context.Context, rt *runtime.Runtime, store rollup.Store) (rollup.IngestStats, error) {
+	return updateRollups(ctx, rt, store.Rebuild)
+}
+
+// updateRollups runs update (Store.Ingest or Store.Rebuild) over all
+// discovered session files.
+func updateRollups(ctx context.Context, rt *runtime.Runtime,
+	update func(context.Context, []discovery.SessionFile, reader.Reader) (rollup.IngestStats, error)) (rollup.IngestStats, error) {
+	log, err := rt.Logger()
+	if err != nil {
+		return rollup.IngestStats{}, err
+	}
+	disc, err := rt.Discoverer()
+	if err != nil {
+		return rollup.IngestStats{}, err
+	}
+	sessions, err := disc.Discover()
+	if err != nil {
+		return rollup.IngestStats{}, fmt.Errorf("failed to discover sessions: %w", err)
+	}
+
+	r, err := rt.NewReader()
+	if err != nil {
+		return rollup.IngestStats{}, err
+	}
+	defer func() {
+		_ = r.Close() //nolint:errcheck // best effort cleanup
+	}()
+
+	stats, err := update(ctx, sessions, r)
+	if err != nil {
+		return stats, fmt.Errorf("failed to update rollups: %w", err)
+	}
+	if stats.Skipped > 0 {
+		log.Warn("some session files could not be read for rollups", "skipped", stats.Skipped)
+	}
+	if stats.Changed > 0 {
+		log.Warn("some session files were rewritten since they were rolled up; run 'report -rebuild'", "changed", stats.Changed)
+	}
+	return stats, nil
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/0xmhha/token-monitor/pkg/analysis"
+	"github.com/0xmhha/token-monitor/pkg/rollup"
+)
+
+func TestParseRollupDimensions(t *testing.T) {
+	dims, err := parseRollupDimensions("date, model,session")
+	if err != nil {
+		t.Fatalf("parseRollupDimensions() error = %v", err)
+	}
+	want := []rollup.Dimension{rollup.DimDate, rollup.DimModel, rollup.DimSession}
+	if len(dims) != len(want) {
+		t.Fatalf("got %v, want %v", dims, want)
+	}
+	for i := range want {
+		if dims[i] != want[i] {
+			t.Errorf("dims[%d] = %s, want %s", i, dims[i], want[i])
+		}
+	}
+
+	if _, err := parseRollupDimensions("hour"); err == nil {
+		t.Error("parseRollupDimensions(hour) expected error")
+	}
+}
+
+func TestReportDateRange(t *testing.T) {
+	now := time.Date(2025, 11, 30, 15, 0, 0, 0, time.UTC)
+
+	from, to := (&reportCommand{days: 30}).dateRange(now)
+	if from != "2025-11-01" || to != "" {
+		t.Errorf("dateRange(days=30) = %q, %q; want 2025-11-01, open end", from, to)
+	}
+
+	from, to = (&reportCommand{days: 30, from: "2025-10-01", to: "2025-10-31"}).dateRange(now)
+	if from != "2025-10-01" || to != "2025-10-31" {
+		t.Errorf("explicit range = %q, %q", from, to)
+	}
+
+	if from, _ = (&reportCommand{}).dateRange(now); from != "" {
+		t.Errorf("days=0 should be unbounded, got %q", from)
+	}
+}
+
+func TestReportWeekdayRange(t *testing.T) {
+	// Wednesday afternoon; the range ends on Tuesday.
+	now := time.Date(2025, 11, 19, 15, 0, 0, 0, time.UTC)
+
+	from, to := (&reportCommand{weeks: 2}).weekdayRange(now)
+	if got := from.Format(rollup.DateLayout); got != "2025-11-05" {
+		t.Errorf("from = %s, want 2025-11-05", got)
+	}
+	if got := to.Format(rollup.DateLayout); got != "2025-11-18" {
+		t.Errorf("to = %s, want 2025-11-18", got)
+	}
+}
+
+func TestRowFootprints(t *testing.T) {
+	rows := []rollup.Row{
+		{Key: rollup.Key{Date: "2025-11-30", Model: "claude-opus-4"}, Totals: rollup.Totals{InputTokens: 1_000_000}},
+		{Key: rollup.Key{Date: "2025-11-30", Model: "claude-sonnet-4"}, Totals: rollup.Totals{InputTokens: 2_000_000}},
+		{Key: rollup.Key{Date: "2025-12-01", Model: "claude-haiku-3-5"}, Totals: rollup.Totals{OutputTokens: 1_000_000}},
+	}
+	rates := analysis.FootprintRates{analysis.ClassOpus: 300, analysis.ClassSonnet: 100, analysis.ClassDefault: 10}
+
+	got := rowFootprints(rows, []rollup.Dimension{rollup.DimDate}, rates)
+	if got[rollup.Key{Date: "2025-11-30"}] != 500 || got[rollup.Key{Date: "2025-12-01"}] != 10 {
+		t.Errorf("rowFootprints() = %v, want 500 g and 10 g per date", got)
+	}
+	if rowFootprints(rows, nil, nil) != nil {
+		t.Error("rowFootprints() without rates should be nil")
+	}
+}
+
+func TestRowCaching(t *testing.T) {
+	rows := []rollup.Row{
+		{Key: rollup.Key{Date: "2025-11-30", Model: "claude-opus-4"}, Totals: rollup.Totals{CacheReadTokens: 1_000_000}},
+		{Key: rollup.Key{Date: "2025-12-01", Model: "claude-sonnet-4"}, Totals: rollup.Totals{InputTokens: 1_000_000}},
+	}
+
+	got := rowCaching(rows)
+	// Opus cache reads: $1.50 recorded, $15 as input. Sonnet input: $3,
+	// or $0.30 from the cache.
+	near := func(a, b float64) bool { return math.Abs(a-b) < 1e-9 }
+	if !near(got.Cost, 4.5) || !near(got.NoCacheCost, 18) || !near(got.FullCacheCost, 1.8) {
+		t.Errorf("rowCaching() = %+v, want 4.5, 18, 1.8", got)
+	}
+}
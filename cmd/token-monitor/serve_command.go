@@ -2,48 +2,85 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
+	"net"
+	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"syscall"
+	"time"
 
+	"github.com/0xmhha/token-monitor/cmd/internal/runtime"
+	"github.com/0xmhha/token-monitor/pkg/apiclient"
 	"github.com/0xmhha/token-monitor/pkg/config"
-	"github.com/0xmhha/token-monitor/pkg/discovery"
 	"github.com/0xmhha/token-monitor/pkg/logger"
 	"github.com/0xmhha/token-monitor/pkg/mcp"
-	"github.com/0xmhha/token-monitor/pkg/parser"
-	"github.com/0xmhha/token-monitor/pkg/reader"
+	"github.com/0xmhha/token-monitor/pkg/overhead"
+	"github.com/0xmhha/token-monitor/pkg/teamshare"
 )
 
+// serveReadHeaderTimeout bounds how long HTTP clients may take to send
+// request headers.
+const serveReadHeaderTimeout = 10 * time.Second
+
 // serveCommand runs an MCP server that exposes token monitoring data as tools.
 type serveCommand struct {
 	stdio      bool
+	httpAddr   string
+	claudeDirs []string
 	configPath string
 	globalOpts globalOptions
 }
 
 // Execute starts the MCP server loop.
 func (c *serveCommand) Execute() error {
-	cfg, err := config.NewLoader(c.configPath).Load()
-	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
+	// MCP protocol uses stdout; logs go to stderr to avoid interference.
+	opts := c.globalOpts.runtimeOptions("")
+	opts.ClaudeDirs = c.claudeDirs
+	opts.NewLogger = func(cfg *config.Config) logger.Logger {
+		g := c.globalOpts
+		if c.listenAddr(cfg) != "" {
+			// An HTTP server's stdout is rarely a terminal, so the implied
+			// quiet mode would hide its logs.
+			g.quiet = false
+		}
+		return g.newLogger(cfg, cfg.Logging.Level)
 	}
+	rt := runtime.New(opts)
+	defer func() {
+		_ = rt.Close() //nolint:errcheck // best effort cleanup
+	}()
 
-	log := c.buildLogger(cfg)
+	cfg, err := rt.Config()
+	if err != nil {
+		return err
+	}
+	log, err := rt.Logger()
+	if err != nil {
+		return err
+	}
 	log.Info("starting MCP server", "version", version)
 
-	disc := discovery.New(cfg.ClaudeConfigDirs, log)
-
-	readerFactory := func() (reader.Reader, error) {
-		return reader.New(reader.Config{
-			PositionStore: reader.NewMemoryPositionStore(),
-			Parser:        parser.New(),
-		}, log)
+	disc, err := rt.Discoverer()
+	if err != nil {
+		return err
 	}
 
 	registry := mcp.NewToolRegistry()
-	mcp.RegisterTokenTools(registry, disc, readerFactory, log)
+	mcp.RegisterTokenTools(registry, disc, rt.NewReader, log)
+
+	if addr := c.listenAddr(cfg); addr != "" {
+		srv := mcp.NewServer(nil, nil, registry, version, log)
+		api := mcp.NewAPIHandler(disc, rt.NewReader, log)
+		return c.serveHTTP(addr, srv, api, rt, cfg, log)
+	}
 
 	srv := mcp.NewServer(os.Stdin, os.Stdout, registry, version, log)
 
@@ -72,24 +109,243 @@ func (c *serveCommand) Execute() error {
 	}
 }
 
-// buildLogger creates a logger for the serve command.
-func (c *serveCommand) buildLogger(cfg *config.Config) logger.Logger {
-	level := cfg.Logging.Level
-	if c.globalOpts.logLevel != "" {
-		level = c.globalOpts.logLevel
-	}
-	// MCP protocol uses stdout; log to stderr to avoid interference.
-	return logger.New(logger.Config{
-		Level:  level,
-		Format: cfg.Logging.Format,
-		Output: "stderr",
+// listenAddr returns the HTTP listen address: -http, then serve.addr.
+// Empty means stdio.
+func (c *serveCommand) listenAddr(cfg *config.Config) string {
+	if c.httpAddr != "" {
+		return c.httpAddr
+	}
+	return cfg.Serve.Addr
+}
+
+// serveHTTP serves MCP at /mcp with /healthz and /readyz probes until
+// SIGINT or SIGTERM. On shutdown /readyz starts failing and in-flight
+// requests get serve.shutdown_timeout to finish. With serve.tokens set,
+// /mcp requires a bearer token and its scopes limit the tools.
+// POST /hooks ingests usage pushed by hook-receiver, and POST /team/usage
+// merges aggregate usage pushed by team members (see team.share), each
+// with the serve.tokens token named after the member. api
+// serves the read-only REST endpoints under /api/.
+// GET /openapi.json serves the specification of all of them.
+// serve.base_path, serve.cors_origins, and serve.trust_proxy let the
+// endpoints sit behind a reverse proxy on a shared host.
+func (c *serveCommand) serveHTTP(addr string, srv *mcp.Server, api http.Handler, rt *runtime.Runtime, cfg *config.Config, log logger.Logger) error {
+	var draining atomic.Bool
+
+	tokens, err := serveTokens(cfg)
+	if err != nil {
+		return err
+	}
+	shared, err := teamshare.Open(teamSharePath(cfg))
+	if err != nil {
+		return err
+	}
+	var handler http.Handler = srv
+	hooks := hookHandler(rt, log)
+	teamUsage := teamShareHandler(shared, log)
+	if len(tokens) > 0 {
+		handler = mcp.Authenticate(tokens, srv)
+		hooks = mcp.Authenticate(tokens, mcp.RequireScope(mcp.ScopeIngest, hooks))
+		teamUsage = mcp.Authenticate(tokens, mcp.RequireScope(mcp.ScopeIngest, teamUsage))
+		api = mcp.Authenticate(tokens, mcp.RequireScope(mcp.ScopeRead, api))
+		log.Info("API token authentication enabled", "tokens", len(tokens))
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/mcp", handler)
+	mux.Handle("/hooks", hooks)
+	mux.Handle(teamshare.Path, teamUsage)
+	mux.Handle(mcp.APIPath, api)
+	mux.HandleFunc(apiclient.SpecPath, func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(apiclient.Spec()) //nolint:errcheck // client may be gone
+	})
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
+		writeProbe(w, nil)
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, _ *http.Request) {
+		if draining.Load() {
+			writeProbe(w, errors.New("shutting down"))
+			return
+		}
+		writeProbe(w, checkClaudeDirs(cfg))
+	})
+	start := overhead.Take()
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, _ *http.Request) {
+		writeMetrics(w, overhead.Take().Since(start), heartbeatPath(cfg))
 	})
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	if cfg.Serve.BasePath != "" {
+		log.Info("serving under base path", "base_path", cfg.Serve.BasePath)
+	}
+	root := withCORS(cfg.Serve.CORSOrigins, mux)
+	root = withBasePath(cfg.Serve.BasePath, root)
+	root = withForwarded(cfg.Serve.TrustProxy, withAccessLog(log, root))
+
+	httpSrv := &http.Server{
+		Handler:           root,
+		ReadHeaderTimeout: serveReadHeaderTimeout,
+	}
+
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- httpSrv.Serve(listener)
+	}()
+	log.Info("serving MCP over HTTP", "addr", listener.Addr().String())
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
+
+	select {
+	case err := <-errChan:
+		return fmt.Errorf("server error: %w", err)
+	case sig := <-sigChan:
+		log.Info("received shutdown signal, draining", "signal", sig.String(), "timeout", cfg.Serve.ShutdownTimeout.String())
+	}
+
+	draining.Store(true)
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Serve.ShutdownTimeout)
+	defer cancel()
+	if err := httpSrv.Shutdown(ctx); err != nil {
+		return fmt.Errorf("failed to drain HTTP server: %w", err)
+	}
+	log.Info("HTTP server stopped")
+	return nil
+}
+
+// serveTokens resolves the configured API tokens, reading secrets given
+// by token_env or a secret reference from the environment or keychain.
+func serveTokens(cfg *config.Config) ([]mcp.Token, error) {
+	tokens := make([]mcp.Token, 0, len(cfg.Serve.Tokens))
+	for _, t := range cfg.Serve.Tokens {
+		secret, err := config.ResolveSecret(t.Token)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", config.ErrInvalidServeToken, t.Name, err)
+		}
+		if t.TokenEnv != "" {
+			if secret = os.Getenv(t.TokenEnv); secret == "" {
+				return nil, fmt.Errorf("%w: %s: environment variable %s is not set", config.ErrInvalidServeToken, t.Name, t.TokenEnv)
+			}
+		}
+		scopes := make(mcp.Scopes, len(t.Scopes))
+		for _, scope := range t.Scopes {
+			scopes[mcp.Scope(scope)] = true
+		}
+		tokens = append(tokens, mcp.Token{Name: t.Name, Secret: secret, Scopes: scopes})
+	}
+	return tokens, nil
+}
+
+// checkClaudeDirs returns an error unless at least one configured Claude
+// directory is readable, e.g. because a volume is not mounted yet.
+func checkClaudeDirs(cfg *config.Config) error {
+	for _, dir := range cfg.ClaudeDirPaths() {
+		if info, err := os.Stat(expandHome(dir)); err == nil && info.IsDir() {
+			return nil
+		}
+	}
+	return fmt.Errorf("no Claude directory is accessible: %s", strings.Join(cfg.ClaudeDirPaths(), ", "))
+}
+
+// expandHome expands ~ in file paths to the user's home directory.
+func expandHome(path string) string {
+	if !strings.HasPrefix(path, "~") {
+		return path
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+
+	if path == "~" {
+		return homeDir
+	}
+	return filepath.Join(homeDir, path[1:])
+}
+
+// writeProbe writes a plain-text probe response: 200 "ok" when err is nil,
+// otherwise 503 with the reason.
+func writeProbe(w http.ResponseWriter, err error) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if err != nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = fmt.Fprintln(w, err) //nolint:errcheck // client may be gone
+		return
+	}
+	_, _ = fmt.Fprintln(w, "ok") //nolint:errcheck // client may be gone
+}
+
+// writeMetrics writes the resources used by serve since start, and by
+// watch as reported in its heartbeat file, in the Prometheus text format.
+func writeMetrics(w http.ResponseWriter, serve overhead.Interval, hbPath string) {
+	type process struct {
+		name  string
+		usage overhead.Interval
+	}
+	processes := []process{{"serve", serve}}
+	if hb, err := readHeartbeat(hbPath); err == nil && hb.OverheadTotal != nil {
+		processes = append(processes, process{"watch", *hb.OverheadTotal})
+	}
+
+	metrics := []struct {
+		name, kind, help string
+		value            func(overhead.Interval) float64
+	}{
+		{"token_monitor_cpu_seconds_total", "counter", "CPU time used by token-monitor itself.",
+			func(i overhead.Interval) float64 { return float64(i.CPUMs) / 1000 }},
+		{"token_monitor_read_bytes_total", "counter", "Bytes read from session files.",
+			func(i overhead.Interval) float64 { return float64(i.BytesRead) }},
+		{"token_monitor_allocs_total", "counter", "Heap objects allocated.",
+			func(i overhead.Interval) float64 { return float64(i.Allocs) }},
+		{"token_monitor_alloc_bytes_total", "counter", "Heap bytes allocated.",
+			func(i overhead.Interval) float64 { return float64(i.AllocBytes) }},
+		{"token_monitor_uptime_seconds", "gauge", "Time covered by the other metrics.",
+			func(i overhead.Interval) float64 { return float64(i.DurationMs) / 1000 }},
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	var b strings.Builder
+	for _, m := range metrics {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s %s\n", m.name, m.help, m.name, m.kind)
+		for _, p := range processes {
+			fmt.Fprintf(&b, "%s{process=%q} %s\n", m.name, p.name, strconv.FormatFloat(m.value(p.usage), 'f', -1, 64))
+		}
+	}
+	_, _ = io.WriteString(w, b.String()) //nolint:errcheck // client may be gone
+}
+
+// listFlag is a repeatable flag whose values may also be comma-separated.
+type listFlag []string
+
+// String implements flag.Value.
+func (f *listFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+// Set implements flag.Value.
+func (f *listFlag) Set(value string) error {
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			*f = append(*f, item)
+		}
+	}
+	return nil
 }
 
 // runServeCommand parses flags and runs the serve command.
 func runServeCommand(globalOpts globalOptions, args []string) error {
 	fs := flag.NewFlagSet("serve", flag.ExitOnError)
 	stdio := fs.Bool("stdio", true, "use stdin/stdout transport (required for MCP)")
+	httpAddr := fs.String("http", "", "serve MCP over HTTP on this address instead (e.g. :8080)")
+	var claudeDirs listFlag
+	fs.Var(&claudeDirs, "claude-dir", "Claude projects directory to read (repeatable; replaces claude_config_dirs)")
 
 	if err := fs.Parse(args); err != nil {
 		return err
@@ -97,6 +353,8 @@ func runServeCommand(globalOpts globalOptions, args []string) error {
 
 	cmd := &serveCommand{
 		stdio:      *stdio,
+		httpAddr:   *httpAddr,
+		claudeDirs: claudeDirs,
 		configPath: globalOpts.configPath,
 		globalOpts: globalOpts,
 	}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/0xmhha/token-monitor/pkg/config"
+	"github.com/0xmhha/token-monitor/pkg/mcp"
+	"github.com/0xmhha/token-monitor/pkg/overhead"
+)
+
+func TestListFlag(t *testing.T) {
+	var dirs listFlag
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Var(&dirs, "claude-dir", "")
+
+	if err := fs.Parse([]string{"-claude-dir", "/a, /b", "-claude-dir", "/c"}); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if want := (listFlag{"/a", "/b", "/c"}); !reflect.DeepEqual(dirs, want) {
+		t.Errorf("dirs = %v, want %v", dirs, want)
+	}
+}
+
+func TestCheckClaudeDirs(t *testing.T) {
+	existing := t.TempDir()
+	missing := filepath.Join(existing, "missing")
+
+	cfg := config.Default()
+	cfg.ClaudeConfigDirs = config.ClaudeDirs(missing)
+	if err := checkClaudeDirs(cfg); err == nil {
+		t.Error("checkClaudeDirs() = nil with no accessible directory")
+	}
+
+	cfg.ClaudeConfigDirs = config.ClaudeDirs(missing, existing)
+	if err := checkClaudeDirs(cfg); err != nil {
+		t.Errorf("checkClaudeDirs() error = %v", err)
+	}
+}
+
+func TestWriteMetrics(t *testing.T) {
+	hbPath := filepath.Join(t.TempDir(), "sessions.db"+heartbeatSuffix)
+	serve := overhead.Interval{DurationMs: 10000, CPUMs: 1500, BytesRead: 4096, Allocs: 10, AllocBytes: 640}
+
+	rec := httptest.NewRecorder()
+	writeMetrics(rec, serve, hbPath)
+	body := rec.Body.String()
+	for _, want := range []string{
+		"# TYPE token_monitor_cpu_seconds_total counter\n",
+		`token_monitor_cpu_seconds_total{process="serve"} 1.5` + "\n",
+		`token_monitor_read_bytes_total{process="serve"} 4096` + "\n",
+		`token_monitor_alloc_bytes_total{process="serve"} 640` + "\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics missing %q:\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, `process="watch"`) {
+		t.Errorf("metrics report watch without a heartbeat:\n%s", body)
+	}
+
+	total := overhead.Interval{DurationMs: 60000, CPUMs: 250, BytesRead: 1 << 20}
+	if err := writeHeartbeat(hbPath, heartbeat{PID: 7, OverheadTotal: &total}); err != nil {
+		t.Fatalf("writeHeartbeat() error = %v", err)
+	}
+	rec = httptest.NewRecorder()
+	writeMetrics(rec, serve, hbPath)
+	if want := `token_monitor_read_bytes_total{process="watch"} 1048576` + "\n"; !strings.Contains(rec.Body.String(), want) {
+		t.Errorf("metrics missing %q:\n%s", want, rec.Body.String())
+	}
+}
+
+func TestServeTokens(t *testing.T) {
+	cfg := config.Default()
+	cfg.Serve.Tokens = []config.ServeToken{
+		{Name: "dashboard", TokenEnv: "TEST_DASHBOARD_TOKEN", Scopes: []string{"read"}},
+		{Name: "ops", Token: "admin-secret", Scopes: []string{"admin"}},
+	}
+
+	t.Setenv("TEST_DASHBOARD_TOKEN", "read-secret")
+	tokens, err := serveTokens(cfg)
+	if err != nil {
+		t.Fatalf("serveTokens() error = %v", err)
+	}
+	want := []mcp.Token{
+		{Name: "dashboard", Secret: "read-secret", Scopes: mcp.Scopes{mcp.ScopeRead: true}},
+		{Name: "ops", Secret: "admin-secret", Scopes: mcp.Scopes{mcp.ScopeAdmin: true}},
+	}
+	if !reflect.DeepEqual(tokens, want) {
+		t.Errorf("serveTokens() = %+v, want %+v", tokens, want)
+	}
+
+	t.Setenv("TEST_DASHBOARD_TOKEN", "")
+	if _, err := serveTokens(cfg); !errors.Is(err, config.ErrInvalidServeToken) {
+		t.Errorf("serveTokens() with unset env error = %v, want ErrInvalidServeToken", err)
+	}
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/0xmhha/token-monitor/pkg/logger"
+)
+
+// corsMaxAge is how long browsers may cache a preflight response.
+const corsMaxAge = "600"
+
+// withBasePath serves h under prefix. Requests without the prefix are
+// served too, so a reverse proxy may forward the path as is or strip it.
+func withBasePath(prefix string, h http.Handler) http.Handler {
+	if prefix == "" {
+		return h
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if rest, ok := strings.CutPrefix(r.URL.Path, prefix); ok && (rest == "" || strings.HasPrefix(rest, "/")) {
+			r2 := r.Clone(r.Context())
+			r2.URL.Path = rest
+			r2.URL.RawPath = ""
+			if r2.URL.Path == "" {
+				r2.URL.Path = "/"
+			}
+			r = r2
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// withCORS lets browsers on origins call h: it answers preflight requests
+// itself, before authentication, and adds the CORS headers to responses.
+// Requests from other origins are served without them, so browsers block
+// reading the response.
+func withCORS(origins []string, h http.Handler) http.Handler {
+	if len(origins) == 0 {
+		return h
+	}
+	allowed := make(map[string]bool, len(origins))
+	for _, origin := range origins {
+		allowed[strings.TrimSuffix(origin, "/")] = true
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		w.Header().Add("Vary", "Origin")
+		if origin == "" || !(allowed["*"] || allowed[origin]) {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+			w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type")
+			w.Header().Set("Access-Control-Max-Age", corsMaxAge)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// withForwarded applies X-Forwarded-For, X-Forwarded-Proto, and
+// X-Forwarded-Host to the request when the server sits behind a trusted
+// proxy, so logs show the real client.
+func withForwarded(trust bool, h http.Handler) http.Handler {
+	if !trust {
+		return h
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r2 := r.Clone(r.Context())
+		if fwd := r.Header.Get("X-Forwarded-For"); fwd != "" {
+			// The first entry is the original client; proxies append.
+			client := strings.TrimSpace(strings.Split(fwd, ",")[0])
+			if net.ParseIP(client) != nil {
+				r2.RemoteAddr = net.JoinHostPort(client, "0")
+			}
+		}
+		if proto := r.Header.Get("X-Forwarded-Proto"); proto == "http" || proto == "https" {
+			r2.URL.Scheme = proto
+		}
+		if host := r.Header.Get("X-Forwarded-Host"); host != "" {
+			r2.Host = host
+		}
+		h.ServeHTTP(w, r2)
+	})
+}
+
+// statusRecorder captures the response status for logging.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+// WriteHeader implements http.ResponseWriter.
+func (s *statusRecorder) WriteHeader(code int) {
+	s.status = code
+	s.ResponseWriter.WriteHeader(code)
+}
+
+// withAccessLog logs each request at debug level and rejected credentials
+// at warn level, with the client address.
+func withAccessLog(log logger.Logger, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		h.ServeHTTP(rec, r)
+
+		client, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			client = r.RemoteAddr
+		}
+		if rec.status == http.StatusUnauthorized {
+			log.Warn("rejected unauthorized request", "path", r.URL.Path, "client", client)
+		}
+		log.Debug("http request", "method", r.Method, "path", r.URL.Path,
+			"status", rec.status, "client", client, "duration", time.Since(start).String())
+	})
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWithBasePath(t *testing.T) {
+	var got string
+	h := withBasePath("/token-monitor", http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		got = r.URL.Path
+	}))
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/token-monitor/mcp", "/mcp"},
+		{"/token-monitor", "/"},
+		{"/mcp", "/mcp"},
+		{"/token-monitoring/mcp", "/token-monitoring/mcp"},
+	}
+	for _, tt := range tests {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if got != tt.want {
+			t.Errorf("path %s served as %s, want %s", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestWithCORS(t *testing.T) {
+	called := false
+	h := withCORS([]string{"https://dash.example.com"}, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		called = true
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+
+	// Preflight is answered before the wrapped handler's authentication.
+	req := httptest.NewRequest(http.MethodOptions, "/mcp", nil)
+	req.Header.Set("Origin", "https://dash.example.com")
+	req.Header.Set("Access-Control-Request-Method", "POST")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNoContent || called {
+		t.Errorf("preflight status = %d, handler called = %v; want 204 without handler", rec.Code, called)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "https://dash.example.com" {
+		t.Errorf("Access-Control-Allow-Origin = %q", got)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/mcp", nil)
+	req.Header.Set("Origin", "https://evil.example.com")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("Access-Control-Allow-Origin for other origin = %q, want empty", got)
+	}
+	if !called {
+		t.Error("request from other origin was not passed on")
+	}
+}
+
+func TestWithForwarded(t *testing.T) {
+	var remote, scheme, host string
+	inner := http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		remote, scheme, host = r.RemoteAddr, r.URL.Scheme, r.Host
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	req.RemoteAddr = "10.0.0.2:5000"
+	req.Header.Set("X-Forwarded-For", "203.0.113.7, 10.0.0.1")
+	req.Header.Set("X-Forwarded-Proto", "https")
+	req.Header.Set("X-Forwarded-Host", "stats.example.com")
+
+	withForwarded(false, inner).ServeHTTP(httptest.NewRecorder(), req)
+	if remote != "10.0.0.2:5000" {
+		t.Errorf("untrusted RemoteAddr = %s, want 10.0.0.2:5000", remote)
+	}
+
+	withForwarded(true, inner).ServeHTTP(httptest.NewRecorder(), req)
+	if remote != "203.0.113.7:0" || scheme != "https" || host != "stats.example.com" {
+		t.Errorf("trusted request = (%s, %s, %s), want forwarded values", remote, scheme, host)
+	}
+}
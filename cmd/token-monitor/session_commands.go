@@ -13,9 +13,11 @@ import (
 	"text/tabwriter"
 	"time"
 
+	"github.com/0xmhha/token-monitor/cmd/internal/runtime"
 	"github.com/0xmhha/token-monitor/pkg/aggregator"
-	"github.com/0xmhha/token-monitor/pkg/config"
 	"github.com/0xmhha/token-monitor/pkg/discovery"
+	"github.com/0xmhha/token-monitor/pkg/display"
+	"github.com/0xmhha/token-monitor/pkg/i18n"
 	"github.com/0xmhha/token-monitor/pkg/logger"
 	"github.com/0xmhha/token-monitor/pkg/parser"
 	"github.com/0xmhha/token-monitor/pkg/session"
@@ -25,6 +27,17 @@ import (
 type sessionCommand struct {
 	configPath string
 	globalOpts globalOptions
+	rt         *runtime.Runtime
+}
+
+// newSessionCommand returns a session command with its own runtime.
+// The caller closes the runtime.
+func newSessionCommand(globalOpts globalOptions) *sessionCommand {
+	return &sessionCommand{
+		configPath: globalOpts.configPath,
+		globalOpts: globalOpts,
+		rt:         globalOpts.newRuntime(""),
+	}
 }
 
 // Execute runs the session command.
@@ -49,6 +62,8 @@ func (c *sessionCommand) Execute(args []string) error {
 		return c.runExport(subargs)
 	case "compare":
 		return c.runCompare(subargs)
+	case "label":
+		return c.runLabel(subargs)
 	case "help":
 		return c.showHelp()
 	default:
@@ -69,19 +84,14 @@ func (c *sessionCommand) runName(args []string) error {
 		return err
 	}
 
-	cfg, log, mgr, err := c.initializeSessionComponents()
+	mgr, err := c.writableSessionManager()
 	if err != nil {
 		return err
 	}
-	defer func() {
-		if mgr != nil {
-			_ = mgr.Close() //nolint:errcheck // best effort cleanup
-		}
-	}()
 
 	existing, err := mgr.GetByUUID(nameArgs.uuid)
 	if err == session.ErrSessionNotFound {
-		return c.createNewSession(cfg, log, mgr, nameArgs)
+		return c.createNewSession(mgr, nameArgs)
 	}
 	if err != nil {
 		return fmt.Errorf("failed to get session: %w", err)
@@ -93,6 +103,9 @@ func (c *sessionCommand) runName(args []string) error {
 // parseNameArgs parses and validates name command arguments.
 func (c *sessionCommand) parseNameArgs(args []string) (*nameArgs, error) {
 	fs := flag.NewFlagSet("session name", flag.ExitOnError)
+	if err := c.globalOpts.applyFlagDefaults(fs); err != nil {
+		return nil, err
+	}
 	if err := fs.Parse(args); err != nil {
 		return nil, err
 	}
@@ -110,13 +123,8 @@ func (c *sessionCommand) parseNameArgs(args []string) (*nameArgs, error) {
 }
 
 // createNewSession creates a new session with the given name.
-func (c *sessionCommand) createNewSession(
-	cfg *config.Config,
-	log logger.Logger,
-	mgr session.Manager,
-	args *nameArgs,
-) error {
-	projectPath, err := c.findProjectPath(cfg, log, args.uuid)
+func (c *sessionCommand) createNewSession(mgr session.Manager, args *nameArgs) error {
+	projectPath, err := c.findProjectPath(args.uuid)
 	if err != nil {
 		return err
 	}
@@ -131,16 +139,15 @@ func (c *sessionCommand) createNewSession(
 		return fmt.Errorf("failed to create session: %w", err)
 	}
 
-	fmt.Printf("Created session '%s' with name '%s'\n", args.uuid[:8], args.name)
+	c.globalOpts.infof("Created session '%s' with name '%s'\n", args.uuid[:8], args.name)
 	return nil
 }
 
 // findProjectPath discovers the project path for a session UUID.
-func (c *sessionCommand) findProjectPath(cfg *config.Config, log logger.Logger, uuid string) (string, error) {
-	disc := discovery.New(cfg.ClaudeConfigDirs, log)
-	sessions, err := disc.Discover()
+func (c *sessionCommand) findProjectPath(uuid string) (string, error) {
+	sessions, err := c.discoverSessions()
 	if err != nil {
-		return "", fmt.Errorf("failed to discover sessions: %w", err)
+		return "", err
 	}
 
 	for _, s := range sessions {
@@ -170,11 +177,11 @@ func (c *sessionCommand) printNameUpdateResult(uuid, oldName, newName string) {
 	shortUUID := uuid[:8]
 	switch {
 	case oldName == newName:
-		fmt.Printf("Session '%s' already has name '%s'\n", shortUUID, newName)
+		c.globalOpts.infof("Session '%s' already has name '%s'\n", shortUUID, newName)
 	case oldName == "":
-		fmt.Printf("Set name '%s' for session '%s'\n", newName, shortUUID)
+		c.globalOpts.infof("Set name '%s' for session '%s'\n", newName, shortUUID)
 	default:
-		fmt.Printf("Renamed session '%s' from '%s' to '%s'\n", shortUUID, oldName, newName)
+		c.globalOpts.infof("Renamed session '%s' from '%s' to '%s'\n", shortUUID, oldName, newName)
 	}
 }
 
@@ -184,11 +191,17 @@ type displaySession struct {
 	Name        string
 	ProjectPath string
 	UpdatedAt   time.Time
+	LastActive  time.Time     // session file's last write, zero if unknown
+	State       session.State // derived from LastActive
 	TotalTokens int
 	EntryCount  int
 	FilePath    string
+	Activity    []int // tokens per day, oldest first
 }
 
+// activityDays is the number of days shown in the session list sparkline.
+const activityDays = 7
+
 // listOptions holds parsed options for the list command.
 type listOptions struct {
 	sortBy     string
@@ -197,27 +210,30 @@ type listOptions struct {
 	from       string
 	to         string
 	minTokens  int
+	state      session.State // empty for any state
 	showTokens bool
+	absolute   bool
 }
 
-// runList lists all sessions with metadata.
+// runList lists named sessions with metadata.
 func (c *sessionCommand) runList(args []string) error {
-	opts, err := c.parseListOptions(args)
+	return c.listSessions("session list", args, listOptions{sortBy: "name"})
+}
+
+// listSessions implements both "session list" and the top-level "list"
+// command, which differ only in flag set name and defaults.
+func (c *sessionCommand) listSessions(name string, args []string, defaults listOptions) error {
+	opts, err := c.parseListOptions(name, args, defaults)
 	if err != nil {
 		return err
 	}
 
-	cfg, log, mgr, err := c.initializeSessionComponents()
+	mgr, err := c.sessionManager()
 	if err != nil {
 		return err
 	}
-	defer func() {
-		if mgr != nil {
-			_ = mgr.Close() //nolint:errcheck // best effort cleanup
-		}
-	}()
 
-	sessions, err := c.collectSessions(cfg, log, mgr, opts.showAll)
+	sessions, err := c.collectSessions(mgr, opts.showAll)
 	if err != nil {
 		return err
 	}
@@ -233,21 +249,34 @@ func (c *sessionCommand) runList(args []string) error {
 	return c.displaySessionListWithOptions(sessions, opts)
 }
 
-// parseListOptions parses command line flags for list command.
-func (c *sessionCommand) parseListOptions(args []string) (*listOptions, error) {
-	fs := flag.NewFlagSet("session list", flag.ExitOnError)
-	sortBy := fs.String("sort", "name", "sort by: name, date, uuid, tokens")
-	showAll := fs.Bool("all", false, "show all sessions including unnamed")
+// parseListOptions parses command line flags for the list commands.
+func (c *sessionCommand) parseListOptions(name string, args []string, defaults listOptions) (*listOptions, error) {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	sortBy := fs.String("sort", defaults.sortBy, "sort by: name, date, uuid, tokens")
+	showAll := fs.Bool("all", defaults.showAll, "show all sessions including unnamed")
 	project := fs.String("project", "", "filter by project path (substring match)")
 	from := fs.String("from", "", "filter sessions updated after date (YYYY-MM-DD)")
 	to := fs.String("to", "", "filter sessions updated before date (YYYY-MM-DD)")
 	minTokens := fs.Int("min-tokens", 0, "filter sessions with at least N tokens")
+	state := fs.String("state", "", "filter by lifecycle state: active, idle, archived")
 	showTokens := fs.Bool("tokens", false, "show token counts in output")
+	absolute := fs.Bool("absolute", false, "show absolute timestamps instead of relative times")
 
+	if err := c.globalOpts.applyFlagDefaults(fs); err != nil {
+		return nil, err
+	}
 	if err := fs.Parse(args); err != nil {
 		return nil, err
 	}
 
+	var st session.State
+	if *state != "" {
+		var err error
+		if st, err = session.ParseState(*state); err != nil {
+			return nil, err
+		}
+	}
+
 	return &listOptions{
 		sortBy:     *sortBy,
 		showAll:    *showAll,
@@ -255,44 +284,58 @@ func (c *sessionCommand) parseListOptions(args []string) (*listOptions, error) {
 		from:       *from,
 		to:         *to,
 		minTokens:  *minTokens,
+		state:      st,
 		showTokens: *showTokens || *minTokens > 0 || *sortBy == "tokens",
+		absolute:   *absolute,
 	}, nil
 }
 
-// initializeSessionComponents sets up common session command dependencies.
-func (c *sessionCommand) initializeSessionComponents() (*config.Config, logger.Logger, session.Manager, error) {
-	cfg, err := config.Load()
+// sessionManager opens the session database through the command runtime.
+// The runtime closes it.
+func (c *sessionCommand) sessionManager() (session.Manager, error) {
+	if _, err := c.rt.Config(); err != nil {
+		return nil, err
+	}
+
+	mgr, err := c.rt.Sessions()
 	if err != nil {
-		return nil, nil, nil, fmt.Errorf("failed to load config: %w", err)
+		return nil, fmt.Errorf("failed to initialize session manager: %w", err)
 	}
+	return mgr, nil
+}
 
-	log := logger.New(logger.Config{
-		Level:  cfg.Logging.Level,
-		Format: cfg.Logging.Format,
-		Output: cfg.Logging.Output,
-	})
+// writableSessionManager is sessionManager for commands that save changes:
+// it fails rather than use a temporary database that discards them.
+func (c *sessionCommand) writableSessionManager() (session.Manager, error) {
+	mgr, err := c.sessionManager()
+	if err != nil {
+		return nil, err
+	}
+	if err := c.rt.Persistent(); err != nil {
+		return nil, err
+	}
+	return mgr, nil
+}
 
-	mgr, err := session.New(session.Config{
-		DBPath: cfg.Storage.DBPath,
-	}, log)
+// discoverSessions lists the session files in the configured directories.
+func (c *sessionCommand) discoverSessions() ([]discovery.SessionFile, error) {
+	disc, err := c.rt.Discoverer()
 	if err != nil {
-		return nil, nil, nil, fmt.Errorf("failed to initialize session manager: %w", err)
+		return nil, err
 	}
 
-	return cfg, log, mgr, nil
+	sessions, err := disc.Discover()
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover sessions: %w", err)
+	}
+	return sessions, nil
 }
 
 // collectSessions gathers and combines discovered and named sessions.
-func (c *sessionCommand) collectSessions(
-	cfg *config.Config,
-	log logger.Logger,
-	mgr session.Manager,
-	showAll bool,
-) ([]displaySession, error) {
-	disc := discovery.New(cfg.ClaudeConfigDirs, log)
-	discoveredSessions, err := disc.Discover()
+func (c *sessionCommand) collectSessions(mgr session.Manager, showAll bool) ([]displaySession, error) {
+	discoveredSessions, err := c.discoverSessions()
 	if err != nil {
-		return nil, fmt.Errorf("failed to discover sessions: %w", err)
+		return nil, err
 	}
 
 	namedSessions, err := mgr.List()
@@ -303,15 +346,71 @@ func (c *sessionCommand) collectSessions(
 	namedMap := buildNamedSessionMap(namedSessions)
 	sessions := combineSessionsForDisplay(discoveredSessions, namedMap, showAll)
 
+	cfg, err := c.rt.Config()
+	if err != nil {
+		return nil, err
+	}
+	assignSessionStates(sessions, cfg.Session.Thresholds(), time.Now())
+	c.recordSessionStates(mgr, sessions, namedMap)
+
 	// Enrich sessions with token counts.
 	c.enrichSessionsWithTokenCounts(sessions)
 
 	return sessions, nil
 }
 
+// assignSessionStates derives each session's lifecycle state from its
+// last activity, falling back to the metadata update time when the file's
+// write time is unknown.
+func assignSessionStates(sessions []displaySession, th session.Thresholds, now time.Time) {
+	for i := range sessions {
+		last := sessions[i].LastActive
+		if last.IsZero() {
+			last = sessions[i].UpdatedAt
+		}
+		sessions[i].State = th.StateAt(last, now)
+	}
+}
+
+// recordSessionStates saves changed states of named sessions in their
+// metadata. It is best effort: nothing is saved when the database is
+// temporary, and failures are only logged.
+func (c *sessionCommand) recordSessionStates(
+	mgr session.Manager,
+	sessions []displaySession,
+	namedMap map[string]*session.Metadata,
+) {
+	if c.rt.Persistent() != nil {
+		return
+	}
+	// A session with several files takes the state of its latest write.
+	states := make(map[string]session.State)
+	for _, s := range sessions {
+		if st, ok := states[s.UUID]; !ok || s.State.Rank() < st.Rank() {
+			states[s.UUID] = s.State
+		}
+	}
+
+	log, _ := c.rt.Logger() //nolint:errcheck // config already loaded
+	now := time.Now()
+	for uuid, st := range states {
+		named, ok := namedMap[uuid]
+		if !ok || named.State == st {
+			continue
+		}
+		if err := mgr.SetState(uuid, st, now); err != nil {
+			log.Warn("failed to record session state", "session", uuid, "error", err)
+		}
+	}
+}
+
 // enrichSessionsWithTokenCounts adds token usage data to sessions.
 func (c *sessionCommand) enrichSessionsWithTokenCounts(sessions []displaySession) {
-	p := parser.New()
+	p, err := c.rt.Parser()
+	if err != nil {
+		return
+	}
+	now := time.Now()
 
 	for i := range sessions {
 		if sessions[i].FilePath == "" {
@@ -330,6 +429,7 @@ func (c *sessionCommand) enrichSessionsWithTokenCounts(sessions []displaySession
 
 		sessions[i].TotalTokens = totalTokens
 		sessions[i].EntryCount = len(entries)
+		sessions[i].Activity = aggregator.DailyTokens(entries, now, activityDays)
 	}
 }
 
@@ -353,7 +453,7 @@ func (c *sessionCommand) filterSessions(sessions []displaySession, opts *listOpt
 
 // hasActiveFilters checks if any filters are enabled.
 func (c *sessionCommand) hasActiveFilters(opts *listOptions) bool {
-	return opts.project != "" || opts.from != "" || opts.to != "" || opts.minTokens > 0
+	return opts.project != "" || opts.from != "" || opts.to != "" || opts.minTokens > 0 || opts.state != ""
 }
 
 // parseDateFilters parses from and to date strings.
@@ -396,6 +496,11 @@ func (c *sessionCommand) sessionMatchesFilters(s displaySession, opts *listOptio
 		return false
 	}
 
+	// Filter by lifecycle state.
+	if opts.state != "" && s.State != opts.state {
+		return false
+	}
+
 	return true
 }
 
@@ -417,20 +522,27 @@ func combineSessionsForDisplay(
 	var sessions []displaySession
 
 	for _, ds := range discovered {
+		var lastActive time.Time
+		if ds.ModTime > 0 {
+			lastActive = time.Unix(ds.ModTime, 0)
+		}
 		if named, ok := namedMap[ds.SessionID]; ok {
 			sessions = append(sessions, displaySession{
 				UUID:        ds.SessionID,
 				Name:        named.Name,
 				ProjectPath: ds.ProjectPath,
 				UpdatedAt:   named.UpdatedAt,
+				LastActive:  lastActive,
 				FilePath:    ds.FilePath,
 			})
 		} else if showAll {
+			// Unnamed sessions have no metadata; use the file's mtime.
 			sessions = append(sessions, displaySession{
 				UUID:        ds.SessionID,
 				Name:        "(unnamed)",
 				ProjectPath: ds.ProjectPath,
-				UpdatedAt:   time.Time{},
+				UpdatedAt:   lastActive,
+				LastActive:  lastActive,
 				FilePath:    ds.FilePath,
 			})
 		}
@@ -442,9 +554,9 @@ func combineSessionsForDisplay(
 // displayEmptyListMessage shows appropriate message when no sessions found.
 func (c *sessionCommand) displayEmptyListMessage(showAll bool) error { //nolint:unparam // error return kept for consistency
 	if showAll {
-		fmt.Println("No sessions found")
+		c.globalOpts.infof("%s\n", i18n.T("msg.no_sessions"))
 	} else {
-		fmt.Println("No named sessions found. Use -all to show all sessions.")
+		c.globalOpts.infof("No named sessions found. Use -all to show all sessions.\n")
 	}
 	return nil
 }
@@ -511,17 +623,20 @@ func (c *sessionCommand) displayFilterSummary(count int, opts *listOptions) {
 	if opts.minTokens > 0 {
 		filters = append(filters, fmt.Sprintf("min %d tokens", opts.minTokens))
 	}
+	if opts.state != "" {
+		filters = append(filters, fmt.Sprintf("state %s", opts.state))
+	}
 
 	if len(filters) > 0 {
-		fmt.Printf("\nFilters: %s\n", strings.Join(filters, ", "))
+		c.globalOpts.infof("\n%s\n", i18n.Tf("msg.filters", strings.Join(filters, ", ")))
 	}
-	fmt.Printf("Total: %d session(s)\n", count)
+	c.globalOpts.infof("%s\n", i18n.Tf("msg.session_total", count))
 }
 
 // writeSessionTableHeaderWithOptions writes the table header with optional columns.
 func (c *sessionCommand) writeSessionTableHeaderWithOptions(w *tabwriter.Writer, opts *listOptions) error {
-	header := "NAME\tUUID\tPROJECT\tLAST UPDATED"
-	separator := "----\t----\t-------\t------------"
+	header := "NAME\tUUID\tPROJECT\tLAST UPDATED\tSTATE\tLAST 7D"
+	separator := "----\t----\t-------\t------------\t-----\t-------"
 
 	if opts.showTokens {
 		header += "\tTOKENS\tREQUESTS"
@@ -541,9 +656,17 @@ func (c *sessionCommand) writeSessionTableHeaderWithOptions(w *tabwriter.Writer,
 func (c *sessionCommand) writeSessionRowWithOptions(w *tabwriter.Writer, s displaySession, opts *listOptions) error {
 	shortUUID := s.UUID[:8] + "..."
 	projectName := truncateProjectPath(s.ProjectPath, 30)
-	updated := formatUpdateTime(s.UpdatedAt)
+	updated := display.FormatTime(s.UpdatedAt, opts.absolute)
 
-	row := fmt.Sprintf("%s\t%s\t%s\t%s", s.Name, shortUUID, projectName, updated)
+	activity := display.Sparkline(s.Activity)
+	if c.globalOpts.ascii {
+		activity = display.ASCII(activity)
+	}
+	if len(s.Activity) == 0 {
+		activity = "-"
+	}
+
+	row := fmt.Sprintf("%s\t%s\t%s\t%s\t%s\t%s", s.Name, shortUUID, projectName, updated, s.State, activity)
 
 	if opts.showTokens {
 		row += fmt.Sprintf("\t%d\t%d", s.TotalTokens, s.EntryCount)
@@ -563,18 +686,13 @@ func truncateProjectPath(path string, maxLen int) string {
 	return path
 }
 
-// formatUpdateTime formats the update time for display.
-func formatUpdateTime(t time.Time) string {
-	if t.IsZero() {
-		return "-"
-	}
-	return t.Format("2006-01-02 15:04")
-}
-
 // showOptions holds parsed options for the show command.
 type showOptions struct {
 	identifier string
-	detailed   bool
+	compact    bool // metadata and a one-line usage summary
+	detailed   bool // also the per-model breakdown
+	absolute   bool
+	format     string
 }
 
 // runShow displays detailed session information.
@@ -584,25 +702,25 @@ func (c *sessionCommand) runShow(args []string) error {
 		return err
 	}
 
-	cfg, log, mgr, err := c.initializeSessionComponents()
+	mgr, err := c.sessionManager()
 	if err != nil {
 		return err
 	}
-	defer func() {
-		if mgr != nil {
-			_ = mgr.Close() //nolint:errcheck // best effort cleanup
-		}
-	}()
 
-	metadata, sessionFile, err := c.findSessionForShow(cfg, log, mgr, opts.identifier)
+	metadata, sessionFile, err := c.findSessionForShow(mgr, opts.identifier)
 	if err != nil {
 		return err
 	}
 
-	c.displaySessionMetadata(metadata)
+	if opts.format == "json" {
+		return c.printSessionShow(metadata, sessionFile)
+	}
+
+	c.displaySessionMetadata(metadata, opts.absolute)
 
 	if sessionFile != nil {
-		if err := c.displaySessionStats(sessionFile, metadata.UUID); err != nil {
+		if err := c.displaySessionStats(sessionFile, metadata.UUID, opts); err != nil {
+			log, _ := c.rt.Logger() //nolint:errcheck // config already loaded
 			log.Warn("failed to display session stats", "error", err)
 		}
 	}
@@ -613,7 +731,13 @@ func (c *sessionCommand) runShow(args []string) error {
 // parseShowOptions parses command line flags for show command.
 func (c *sessionCommand) parseShowOptions(args []string) (*showOptions, error) {
 	fs := flag.NewFlagSet("session show", flag.ExitOnError)
-	detailed := fs.Bool("detailed", true, "show detailed statistics")
+	compact := fs.Bool("compact", false, "show a one-line usage summary instead of the tables")
+	detailed := fs.Bool("detailed", false, "also show usage per model")
+	absolute := fs.Bool("absolute", false, "show absolute timestamps instead of relative times")
+	format := fs.String("format", "table", "output format (table, json)")
+	if err := c.globalOpts.applyFlagDefaults(fs); err != nil {
+		return nil, err
+	}
 	if err := fs.Parse(args); err != nil {
 		return nil, err
 	}
@@ -621,14 +745,55 @@ func (c *sessionCommand) parseShowOptions(args []string) (*showOptions, error) {
 	if fs.NArg() < 1 {
 		return nil, fmt.Errorf("usage: token-monitor session show <name|uuid>")
 	}
+	if *compact && *detailed {
+		return nil, fmt.Errorf("-compact cannot be combined with -detailed")
+	}
+	if c.globalOpts.jsonOutput {
+		*format = "json"
+	}
+	switch *format {
+	case "table", "json":
+	default:
+		return nil, fmt.Errorf("invalid -format %q (want table or json)", *format)
+	}
 
-	return &showOptions{identifier: fs.Arg(0), detailed: *detailed}, nil
+	return &showOptions{
+		identifier: fs.Arg(0),
+		compact:    *compact,
+		detailed:   *detailed,
+		absolute:   *absolute,
+		format:     *format,
+	}, nil
+}
+
+// printSessionShow prints the session as a JSON document. sessionFile may
+// be nil when the session's file was not found.
+func (c *sessionCommand) printSessionShow(metadata *session.Metadata, sessionFile *discovery.SessionFile) error {
+	if sessionFile == nil {
+		return printJSON(buildSessionShow(metadata, "", nil))
+	}
+	entries, err := c.sessionEntries(sessionFile)
+	if err != nil {
+		return err
+	}
+	return printJSON(buildSessionShow(metadata, sessionFile.FilePath, entries))
+}
+
+// sessionEntries parses all entries of a session file.
+func (c *sessionCommand) sessionEntries(sessionFile *discovery.SessionFile) ([]parser.UsageEntry, error) {
+	p, err := c.rt.Parser()
+	if err != nil {
+		return nil, err
+	}
+	entries, _, err := p.ParseFile(sessionFile.FilePath, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse session file: %w", err)
+	}
+	return entries, nil
 }
 
 // findSessionForShow finds session metadata and file for the show command.
 func (c *sessionCommand) findSessionForShow(
-	cfg *config.Config,
-	log logger.Logger,
 	mgr session.Manager,
 	identifier string,
 ) (*session.Metadata, *discovery.SessionFile, error) {
@@ -638,7 +803,7 @@ func (c *sessionCommand) findSessionForShow(
 		if err == session.ErrSessionNotFound {
 			metadata, err = mgr.GetByUUID(identifier)
 			if err != nil {
-				return nil, nil, fmt.Errorf("session not found: %s", identifier)
+				return nil, nil, fmt.Errorf("%w: %s", session.ErrSessionNotFound, identifier)
 			}
 		} else {
 			return nil, nil, fmt.Errorf("failed to get session: %w", err)
@@ -646,8 +811,7 @@ func (c *sessionCommand) findSessionForShow(
 	}
 
 	// Find the session file.
-	disc := discovery.New(cfg.ClaudeConfigDirs, log)
-	discoveredSessions, err := disc.Discover()
+	discoveredSessions, err := c.discoverSessions()
 	if err != nil {
 		return metadata, nil, nil // Return metadata even if discovery fails
 	}
@@ -662,35 +826,44 @@ func (c *sessionCommand) findSessionForShow(
 }
 
 // displaySessionMetadata shows basic session metadata.
-func (c *sessionCommand) displaySessionMetadata(metadata *session.Metadata) {
-	fmt.Println("📋 Session Details")
-	fmt.Println(strings.Repeat("─", 60))
-	fmt.Printf("UUID:        %s\n", metadata.UUID)
-	fmt.Printf("Name:        %s\n", metadata.Name)
-	fmt.Printf("Project:     %s\n", metadata.ProjectPath)
-	fmt.Printf("Created:     %s\n", metadata.CreatedAt.Format("2006-01-02 15:04:05"))
-	fmt.Printf("Updated:     %s\n", metadata.UpdatedAt.Format("2006-01-02 15:04:05"))
+func (c *sessionCommand) displaySessionMetadata(metadata *session.Metadata, absolute bool) {
+	out := c.globalOpts.output()
+
+	out.Println(c.globalOpts.decorate("📋", "Session Details"))
+	if !c.globalOpts.accessible {
+		out.Println(strings.Repeat("─", 60))
+	}
+	out.Printf("UUID:        %s\n", metadata.UUID)
+	out.Printf("Name:        %s\n", metadata.Name)
+	out.Printf("Project:     %s\n", metadata.ProjectPath)
+	out.Printf("Created:     %s\n", display.FormatTime(metadata.CreatedAt, absolute))
+	out.Printf("Updated:     %s\n", display.FormatTime(metadata.UpdatedAt, absolute))
+	if metadata.State != "" {
+		out.Printf("State:       %s (since %s)\n", metadata.State, display.FormatTime(metadata.StateChangedAt, absolute))
+	}
 
 	if len(metadata.Tags) > 0 {
-		fmt.Printf("Tags:        %s\n", strings.Join(metadata.Tags, ", "))
+		out.Printf("Tags:        %s\n", strings.Join(metadata.Tags, ", "))
 	}
 
 	if metadata.Description != "" {
-		fmt.Printf("Description: %s\n", metadata.Description)
+		out.Printf("Description: %s\n", metadata.Description)
 	}
 }
 
-// displaySessionStats shows token statistics, billing blocks, and activity timeline.
-func (c *sessionCommand) displaySessionStats(sessionFile *discovery.SessionFile, sessionID string) error {
-	// Parse the session file.
-	p := parser.New()
-	entries, _, err := p.ParseFile(sessionFile.FilePath, 0)
+// displaySessionStats shows token statistics, billing blocks, and activity
+// timeline, a single summary line with -compact, and usage per model as
+// well with -detailed.
+func (c *sessionCommand) displaySessionStats(sessionFile *discovery.SessionFile, sessionID string, opts *showOptions) error {
+	out := c.globalOpts.output()
+
+	entries, err := c.sessionEntries(sessionFile)
 	if err != nil {
-		return fmt.Errorf("failed to parse session file: %w", err)
+		return err
 	}
 
 	if len(entries) == 0 {
-		fmt.Println("\nNo usage data found for this session.")
+		out.Println("\nNo usage data found for this session.")
 		return nil
 	}
 
@@ -704,15 +877,56 @@ func (c *sessionCommand) displaySessionStats(sessionFile *discovery.SessionFile,
 		agg.Add(entry)
 	}
 
+	blocks := agg.BillingBlocks(sessionID)
+	if opts.compact {
+		out.Printf("Usage:       %s\n", sessionSummaryLine(agg.Stats(), len(blocks), entries))
+		return nil
+	}
+
 	c.displayTokenBreakdown(agg.Stats(), entries)
-	c.displayBillingBlocks(agg.BillingBlocks(sessionID))
+	c.displayAgentBreakdown(agg.Stats())
+	if opts.detailed {
+		c.displayModelBreakdown(sessionModels(entries), agg.Stats().TotalTokens)
+	}
+	c.displayBillingBlocks(blocks)
 	c.displayActivityTimeline(entries)
 
 	return nil
 }
 
+// displayModelBreakdown shows the session's usage per model.
+func (c *sessionCommand) displayModelBreakdown(models []sessionModel, total int) {
+	out := c.globalOpts.output()
+	if total == 0 {
+		total = 1 // Avoid division by zero
+	}
+	share := func(n int) float64 { return float64(n) * 100 / float64(total) }
+
+	out.Println()
+	if c.globalOpts.accessible {
+		out.Println("Model Breakdown")
+		for _, m := range models {
+			out.Printf("%s: %d requests, %d tokens, %.1f percent, %s\n",
+				m.Model, m.Requests, m.TotalTokens, share(m.TotalTokens), display.FormatCost(m.CostUSD))
+		}
+		return
+	}
+
+	out.Println("🧠 Model Breakdown")
+	out.Println("┌──────────────────────────────┬──────────┬──────────────┬─────────┬──────────┐")
+	out.Println("│ Model                        │ Requests │       Tokens │   Share │     Cost │")
+	out.Println("├──────────────────────────────┼──────────┼──────────────┼─────────┼──────────┤")
+	for _, m := range models {
+		out.Printf("│ %-28s │ %8d │ %12d │ %6.1f%% │ %8s │\n",
+			truncateRunes(m.Model, 28), m.Requests, m.TotalTokens, share(m.TotalTokens), display.FormatCost(m.CostUSD))
+	}
+	out.Println("└──────────────────────────────┴──────────┴──────────────┴─────────┴──────────┘")
+}
+
 // displayTokenBreakdown shows token usage breakdown by type.
 func (c *sessionCommand) displayTokenBreakdown(stats aggregator.Statistics, entries []parser.UsageEntry) {
+	out := c.globalOpts.output()
+
 	// Calculate cache token totals.
 	var cacheCreation, cacheRead int
 	for _, entry := range entries {
@@ -720,99 +934,191 @@ func (c *sessionCommand) displayTokenBreakdown(stats aggregator.Statistics, entr
 		cacheRead += entry.Message.Usage.CacheReadInputTokens
 	}
 
-	fmt.Println()
-	fmt.Println("📊 Token Breakdown")
-	fmt.Println("┌────────────────────────┬──────────────┬─────────┐")
-	fmt.Println("│ Token Type             │        Count │   Share │")
-	fmt.Println("├────────────────────────┼──────────────┼─────────┤")
+	if c.globalOpts.accessible {
+		c.displayTokenBreakdownLines(stats, cacheCreation, cacheRead)
+		return
+	}
+
+	out.Println()
+	out.Println("📊 Token Breakdown")
+	out.Println("┌────────────────────────┬──────────────┬─────────┐")
+	out.Println("│ Token Type             │        Count │   Share │")
+	out.Println("├────────────────────────┼──────────────┼─────────┤")
 
 	total := stats.TotalTokens
 	if total == 0 {
 		total = 1 // Avoid division by zero
 	}
 
-	fmt.Printf("│ Input Tokens           │ %12d │ %6.1f%% │\n",
+	out.Printf("│ Input Tokens           │ %12d │ %6.1f%% │\n",
 		stats.InputTokens, float64(stats.InputTokens)*100/float64(total))
-	fmt.Printf("│ Output Tokens          │ %12d │ %6.1f%% │\n",
+	out.Printf("│ Output Tokens          │ %12d │ %6.1f%% │\n",
 		stats.OutputTokens, float64(stats.OutputTokens)*100/float64(total))
-	fmt.Printf("│ Cache Creation Tokens  │ %12d │ %6.1f%% │\n",
+	out.Printf("│ Cache Creation Tokens  │ %12d │ %6.1f%% │\n",
 		cacheCreation, float64(cacheCreation)*100/float64(total))
-	fmt.Printf("│ Cache Read Tokens      │ %12d │ %6.1f%% │\n",
+	out.Printf("│ Cache Read Tokens      │ %12d │ %6.1f%% │\n",
 		cacheRead, float64(cacheRead)*100/float64(total))
-	fmt.Println("├────────────────────────┼──────────────┼─────────┤")
-	fmt.Printf("│ Total Tokens           │ %12d │ %6.1f%% │\n", stats.TotalTokens, 100.0)
-	fmt.Println("└────────────────────────┴──────────────┴─────────┘")
+	out.Println("├────────────────────────┼──────────────┼─────────┤")
+	out.Printf("│ Total Tokens           │ %12d │ %6.1f%% │\n", stats.TotalTokens, 100.0)
+	out.Println("└────────────────────────┴──────────────┴─────────┘")
 
 	// Statistics summary.
-	fmt.Println()
-	fmt.Println("📈 Statistics")
-	fmt.Println("┌────────────────────────┬──────────────┐")
-	fmt.Println("│ Metric                 │        Value │")
-	fmt.Println("├────────────────────────┼──────────────┤")
-	fmt.Printf("│ Total Requests         │ %12d │\n", stats.Count)
-	fmt.Printf("│ Average Tokens/Request │ %12.0f │\n", stats.AvgTokens)
-	fmt.Printf("│ Min Tokens             │ %12d │\n", stats.MinTokens)
-	fmt.Printf("│ Max Tokens             │ %12d │\n", stats.MaxTokens)
+	out.Println()
+	out.Println("📈 Statistics")
+	out.Println("┌────────────────────────┬──────────────┐")
+	out.Println("│ Metric                 │        Value │")
+	out.Println("├────────────────────────┼──────────────┤")
+	out.Printf("│ Total Requests         │ %12d │\n", stats.Count)
+	out.Printf("│ Average Tokens/Request │ %12.0f │\n", stats.AvgTokens)
+	out.Printf("│ Min Tokens             │ %12d │\n", stats.MinTokens)
+	out.Printf("│ Max Tokens             │ %12d │\n", stats.MaxTokens)
+	if stats.P50Tokens > 0 {
+		out.Printf("│ P50 Tokens             │ %12d │\n", stats.P50Tokens)
+		out.Printf("│ P95 Tokens             │ %12d │\n", stats.P95Tokens)
+		out.Printf("│ P99 Tokens             │ %12d │\n", stats.P99Tokens)
+	}
+	out.Println("└────────────────────────┴──────────────┘")
+}
+
+// displayTokenBreakdownLines is the accessible form of the token breakdown
+// and statistics tables: one labeled line per value.
+func (c *sessionCommand) displayTokenBreakdownLines(stats aggregator.Statistics, cacheCreation, cacheRead int) {
+	out := c.globalOpts.output()
+
+	total := stats.TotalTokens
+	if total == 0 {
+		total = 1 // Avoid division by zero
+	}
+	share := func(n int) float64 { return float64(n) * 100 / float64(total) }
+
+	out.Println()
+	out.Println("Token Breakdown")
+	out.Printf("Input Tokens: %d, %.1f percent\n", stats.InputTokens, share(stats.InputTokens))
+	out.Printf("Output Tokens: %d, %.1f percent\n", stats.OutputTokens, share(stats.OutputTokens))
+	out.Printf("Cache Creation Tokens: %d, %.1f percent\n", cacheCreation, share(cacheCreation))
+	out.Printf("Cache Read Tokens: %d, %.1f percent\n", cacheRead, share(cacheRead))
+	out.Printf("Total Tokens: %d\n", stats.TotalTokens)
+
+	out.Println()
+	out.Println("Statistics")
+	out.Printf("Total Requests: %d\n", stats.Count)
+	out.Printf("Average Tokens per Request: %.0f\n", stats.AvgTokens)
+	out.Printf("Min Tokens: %d\n", stats.MinTokens)
+	out.Printf("Max Tokens: %d\n", stats.MaxTokens)
 	if stats.P50Tokens > 0 {
-		fmt.Printf("│ P50 Tokens             │ %12d │\n", stats.P50Tokens)
-		fmt.Printf("│ P95 Tokens             │ %12d │\n", stats.P95Tokens)
-		fmt.Printf("│ P99 Tokens             │ %12d │\n", stats.P99Tokens)
+		out.Printf("P50 Tokens: %d\n", stats.P50Tokens)
+		out.Printf("P95 Tokens: %d\n", stats.P95Tokens)
+		out.Printf("P99 Tokens: %d\n", stats.P99Tokens)
 	}
-	fmt.Println("└────────────────────────┴──────────────┘")
+}
+
+// displayAgentBreakdown shows how the session's tokens split between the
+// main conversation and sub-agents. Sessions without sub-agent usage show
+// nothing.
+func (c *sessionCommand) displayAgentBreakdown(stats aggregator.Statistics) {
+	if stats.SubAgentCount == 0 {
+		return
+	}
+	out := c.globalOpts.output()
+
+	mainCount := stats.Count - stats.SubAgentCount
+	mainTokens := stats.TotalTokens - stats.SubAgentTokens
+	total := stats.TotalTokens
+	if total == 0 {
+		total = 1 // Avoid division by zero
+	}
+	share := func(n int) float64 { return float64(n) * 100 / float64(total) }
+
+	out.Println()
+	if c.globalOpts.accessible {
+		out.Println("Agent Breakdown")
+		out.Printf("Main: %d requests, %d tokens, %.1f percent\n", mainCount, mainTokens, share(mainTokens))
+		out.Printf("Sub-agents: %d requests, %d tokens, %.1f percent\n",
+			stats.SubAgentCount, stats.SubAgentTokens, share(stats.SubAgentTokens))
+		return
+	}
+
+	out.Println("🤖 Agent Breakdown")
+	out.Println("┌────────────────────────┬──────────┬──────────────┬─────────┐")
+	out.Println("│ Agent                  │ Requests │       Tokens │   Share │")
+	out.Println("├────────────────────────┼──────────┼──────────────┼─────────┤")
+	out.Printf("│ Main                   │ %8d │ %12d │ %6.1f%% │\n", mainCount, mainTokens, share(mainTokens))
+	out.Printf("│ Sub-agents             │ %8d │ %12d │ %6.1f%% │\n",
+		stats.SubAgentCount, stats.SubAgentTokens, share(stats.SubAgentTokens))
+	out.Println("└────────────────────────┴──────────┴──────────────┴─────────┘")
 }
 
 // displayBillingBlocks shows the billing blocks timeline.
 func (c *sessionCommand) displayBillingBlocks(blocks []aggregator.BillingBlock) {
+	out := c.globalOpts.output()
+
 	if len(blocks) == 0 {
 		return
 	}
 
-	fmt.Println()
-	fmt.Println("⏰ Billing Blocks (5-hour UTC windows)")
-	fmt.Println("┌─────────────────────────────────┬──────────────┬──────────┬────────┐")
-	fmt.Println("│ Time Window (UTC)               │       Tokens │ Requests │ Status │")
-	fmt.Println("├─────────────────────────────────┼──────────────┼──────────┼────────┤")
-
 	// Show up to 10 most recent blocks.
 	maxBlocks := 10
 	if len(blocks) < maxBlocks {
 		maxBlocks = len(blocks)
 	}
 
+	out.Println()
+	if c.globalOpts.accessible {
+		out.Println("Billing Blocks (5-hour UTC windows)")
+		for _, block := range blocks[:maxBlocks] {
+			status := "past"
+			if block.IsActive {
+				status = "active"
+			}
+			out.Printf("%s to %s UTC: %d tokens, %d requests, %s\n",
+				block.StartTime.Format("2006-01-02 15:04"),
+				block.EndTime.Format("15:04"),
+				block.TotalTokens, block.EntryCount, status)
+		}
+		if len(blocks) > maxBlocks {
+			out.Printf("%d more billing blocks not shown\n", len(blocks)-maxBlocks)
+		}
+		return
+	}
+
+	out.Println("⏰ Billing Blocks (5-hour UTC windows)")
+	out.Println("┌─────────────────────────────────┬──────────────┬──────────┬────────┐")
+	out.Println("│ Time Window (UTC)               │       Tokens │ Requests │ Status │")
+	out.Println("├─────────────────────────────────┼──────────────┼──────────┼────────┤")
+
 	for i := 0; i < maxBlocks; i++ {
 		block := blocks[i]
 		status := "  past"
 		if block.IsActive {
 			status = "🔴 now"
+			if c.globalOpts.ascii {
+				status = "   now" // same width as the emoji form
+			}
 		}
 
 		timeWindow := fmt.Sprintf("%s - %s",
 			block.StartTime.Format("2006-01-02 15:04"),
 			block.EndTime.Format("15:04"))
 
-		fmt.Printf("│ %-31s │ %12d │ %8d │ %s │\n",
+		out.Printf("│ %-31s │ %12d │ %8d │ %s │\n",
 			timeWindow, block.TotalTokens, block.EntryCount, status)
 	}
 
-	fmt.Println("└─────────────────────────────────┴──────────────┴──────────┴────────┘")
+	out.Println("└─────────────────────────────────┴──────────────┴──────────┴────────┘")
 
 	if len(blocks) > maxBlocks {
-		fmt.Printf("  ... and %d more billing blocks\n", len(blocks)-maxBlocks)
+		out.Printf("  ... and %d more billing blocks\n", len(blocks)-maxBlocks)
 	}
 }
 
 // displayActivityTimeline shows recent activity timestamps.
 func (c *sessionCommand) displayActivityTimeline(entries []parser.UsageEntry) {
+	out := c.globalOpts.output()
+
 	if len(entries) == 0 {
 		return
 	}
 
-	fmt.Println()
-	fmt.Println("📅 Activity Timeline")
-	fmt.Println("┌─────────────────────┬────────────────────────────────┬────────────┐")
-	fmt.Println("│ Timestamp           │ Model                          │     Tokens │")
-	fmt.Println("├─────────────────────┼────────────────────────────────┼────────────┤")
-
 	// Show up to 15 most recent entries.
 	maxEntries := 15
 	startIdx := 0
@@ -820,6 +1126,17 @@ func (c *sessionCommand) displayActivityTimeline(entries []parser.UsageEntry) {
 		startIdx = len(entries) - maxEntries
 	}
 
+	out.Println()
+	if c.globalOpts.accessible {
+		c.displayActivityLines(entries, startIdx)
+		return
+	}
+
+	out.Println("📅 Activity Timeline")
+	out.Println("┌─────────────────────┬────────────────────────────────┬────────────┐")
+	out.Println("│ Timestamp           │ Model                          │     Tokens │")
+	out.Println("├─────────────────────┼────────────────────────────────┼────────────┤")
+
 	for i := startIdx; i < len(entries); i++ {
 		entry := entries[i]
 		model := entry.Message.Model
@@ -827,16 +1144,16 @@ func (c *sessionCommand) displayActivityTimeline(entries []parser.UsageEntry) {
 			model = model[:27] + "..."
 		}
 
-		fmt.Printf("│ %s │ %-30s │ %10d │\n",
+		out.Printf("│ %s │ %-30s │ %10d │\n",
 			entry.Timestamp.Format("2006-01-02 15:04"),
 			model,
 			entry.Message.Usage.TotalTokens())
 	}
 
-	fmt.Println("└─────────────────────┴────────────────────────────────┴────────────┘")
+	out.Println("└─────────────────────┴────────────────────────────────┴────────────┘")
 
 	if len(entries) > maxEntries {
-		fmt.Printf("  Showing last %d of %d entries\n", maxEntries, len(entries))
+		out.Printf("  Showing last %d of %d entries\n", maxEntries, len(entries))
 	}
 
 	// Time span.
@@ -844,29 +1161,36 @@ func (c *sessionCommand) displayActivityTimeline(entries []parser.UsageEntry) {
 		first := entries[0].Timestamp
 		last := entries[len(entries)-1].Timestamp
 		duration := last.Sub(first)
-		fmt.Printf("\n  Session span: %s → %s (%s)\n",
+		out.Printf("\n  Session span: %s → %s (%s)\n",
 			first.Format("2006-01-02 15:04"),
 			last.Format("2006-01-02 15:04"),
-			formatDuration(duration))
+			display.FormatDurationLong(duration, 2))
 	}
 }
 
-// formatDuration formats a duration in a human-readable way.
-func formatDuration(d time.Duration) string {
-	if d < time.Minute {
-		return fmt.Sprintf("%ds", int(d.Seconds()))
+// displayActivityLines is the accessible form of the activity timeline,
+// listing entries from startIdx one per line.
+func (c *sessionCommand) displayActivityLines(entries []parser.UsageEntry, startIdx int) {
+	out := c.globalOpts.output()
+
+	out.Println("Activity Timeline")
+	for _, entry := range entries[startIdx:] {
+		out.Printf("%s, %s, %d tokens\n",
+			entry.Timestamp.Format("2006-01-02 15:04"),
+			entry.Message.Model,
+			entry.Message.Usage.TotalTokens())
 	}
-	if d < time.Hour {
-		return fmt.Sprintf("%dm", int(d.Minutes()))
+	if startIdx > 0 {
+		out.Printf("Showing last %d of %d entries\n", len(entries)-startIdx, len(entries))
 	}
-	if d < 24*time.Hour {
-		hours := int(d.Hours())
-		mins := int(d.Minutes()) % 60
-		return fmt.Sprintf("%dh %dm", hours, mins)
+	if len(entries) >= 2 {
+		first := entries[0].Timestamp
+		last := entries[len(entries)-1].Timestamp
+		out.Printf("Session span: %s to %s, %s\n",
+			first.Format("2006-01-02 15:04"),
+			last.Format("2006-01-02 15:04"),
+			display.FormatDurationLong(last.Sub(first), 2))
 	}
-	days := int(d.Hours()) / 24
-	hours := int(d.Hours()) % 24
-	return fmt.Sprintf("%dd %dh", days, hours)
 }
 
 // runDelete removes session metadata.
@@ -883,31 +1207,11 @@ func (c *sessionCommand) runDelete(args []string) error {
 
 	identifier := fs.Arg(0)
 
-	// Load configuration.
-	cfg, err := config.Load()
-	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
-	}
-
-	// Initialize logger.
-	log := logger.New(logger.Config{
-		Level:  cfg.Logging.Level,
-		Format: cfg.Logging.Format,
-		Output: cfg.Logging.Output,
-	})
-
 	// Initialize session manager.
-	mgr, err := session.New(session.Config{
-		DBPath: cfg.Storage.DBPath,
-	}, log)
+	mgr, err := c.sessionManager()
 	if err != nil {
-		return fmt.Errorf("failed to initialize session manager: %w", err)
+		return err
 	}
-	defer func() {
-		if closeErr := mgr.Close(); closeErr != nil {
-			log.Error("failed to close session manager", "error", closeErr)
-		}
-	}()
 
 	// Try to find session by name first, then by UUID.
 	var metadata *session.Metadata
@@ -918,7 +1222,7 @@ func (c *sessionCommand) runDelete(args []string) error {
 			// Try by UUID.
 			metadata, err = mgr.GetByUUID(identifier)
 			if err != nil {
-				return fmt.Errorf("session not found: %s", identifier)
+				return fmt.Errorf("%w: %s", session.ErrSessionNotFound, identifier)
 			}
 		} else {
 			return fmt.Errorf("failed to get session: %w", err)
@@ -926,8 +1230,9 @@ func (c *sessionCommand) runDelete(args []string) error {
 	}
 
 	// Confirm deletion.
+	out := c.globalOpts.output()
 	if !*force {
-		fmt.Printf("Delete session '%s' (%s)? [y/N]: ", metadata.Name, metadata.UUID[:8])
+		out.Warnf("Delete session '%s' (%s)? [y/N]: ", metadata.Name, metadata.UUID[:8])
 		var response string
 		if _, scanErr := fmt.Scanln(&response); scanErr != nil {
 			return fmt.Errorf("cancelled")
@@ -935,7 +1240,7 @@ func (c *sessionCommand) runDelete(args []string) error {
 
 		response = strings.ToLower(strings.TrimSpace(response))
 		if response != "y" && response != "yes" {
-			fmt.Println("Cancelled")
+			out.Warnf("Cancelled\n")
 			return nil
 		}
 	}
@@ -945,8 +1250,8 @@ func (c *sessionCommand) runDelete(args []string) error {
 		return fmt.Errorf("failed to delete session: %w", err)
 	}
 
-	fmt.Printf("Deleted session '%s' (%s)\n", metadata.Name, metadata.UUID[:8])
-	fmt.Println("Note: JSONL data files are preserved.")
+	out.Infof("Deleted session '%s' (%s)\n", metadata.Name, metadata.UUID[:8])
+	out.Infof("Note: JSONL data files are preserved.\n")
 
 	return nil
 }
@@ -994,6 +1299,8 @@ func (c *sessionCommand) runExport(args []string) error {
 	fs := flag.NewFlagSet("session export", flag.ExitOnError)
 	format := fs.String("format", "json", "output format: json, csv, agent-forge")
 	output := fs.String("output", "", "output file path (default: stdout)")
+	split := fs.String("split", "", "write one file per day or billing block to -output-dir: day, block")
+	outputDir := fs.String("output-dir", "", "directory for the files of -split")
 	if err := fs.Parse(args); err != nil {
 		return err
 	}
@@ -1010,25 +1317,35 @@ func (c *sessionCommand) runExport(args []string) error {
 		return fmt.Errorf("invalid format '%s': must be 'json', 'csv', or 'agent-forge'", *format)
 	}
 
-	// Load configuration.
-	cfg, err := config.Load()
-	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
+	// Validate splitting.
+	switch *split {
+	case "":
+		if *outputDir != "" {
+			return fmt.Errorf("-output-dir needs -split day or -split block")
+		}
+	case exportSplitDay, exportSplitBlock:
+		if *outputDir == "" || *output != "" {
+			return fmt.Errorf("-split writes one file per slice: set -output-dir instead of -output")
+		}
+	default:
+		return fmt.Errorf("invalid split '%s': must be 'day' or 'block'", *split)
 	}
 
-	// Initialize logger.
-	log := logger.New(logger.Config{
-		Level:  cfg.Logging.Level,
-		Format: cfg.Logging.Format,
-		Output: cfg.Logging.Output,
-	})
+	log, err := c.rt.Logger()
+	if err != nil {
+		return err
+	}
 
 	// Find session and parse entries.
-	sessionFile, metadata, entries, err := c.findAndParseSession(identifier, cfg, log)
+	sessionFile, metadata, entries, err := c.findAndParseSession(identifier)
 	if err != nil {
 		return err
 	}
 
+	if *split != "" {
+		return c.writeExportSlices(*format, *outputDir, sessionFile, metadata, splitExportEntries(entries, *split), log)
+	}
+
 	// Build export data.
 	exportData := buildExportData(sessionFile, metadata, entries)
 
@@ -1039,27 +1356,17 @@ func (c *sessionCommand) runExport(args []string) error {
 // findAndParseSession finds a session by identifier and parses its entries.
 func (c *sessionCommand) findAndParseSession(
 	identifier string,
-	cfg *config.Config,
-	log logger.Logger,
 ) (*discovery.SessionFile, *session.Metadata, []parser.UsageEntry, error) {
 	// Initialize session manager.
-	mgr, err := session.New(session.Config{
-		DBPath: cfg.Storage.DBPath,
-	}, log)
+	mgr, err := c.sessionManager()
 	if err != nil {
-		return nil, nil, nil, fmt.Errorf("failed to initialize session manager: %w", err)
+		return nil, nil, nil, err
 	}
-	defer func() {
-		if closeErr := mgr.Close(); closeErr != nil {
-			log.Error("failed to close session manager", "error", closeErr)
-		}
-	}()
 
 	// Discover sessions to find the file path.
-	disc := discovery.New(cfg.ClaudeConfigDirs, log)
-	discoveredSessions, err := disc.Discover()
+	discoveredSessions, err := c.discoverSessions()
 	if err != nil {
-		return nil, nil, nil, fmt.Errorf("failed to discover sessions: %w", err)
+		return nil, nil, nil, err
 	}
 
 	// Try to find session metadata by name or UUID.
@@ -1072,7 +1379,10 @@ func (c *sessionCommand) findAndParseSession(
 	}
 
 	// Parse the session file.
-	p := parser.New()
+	p, err := c.rt.Parser()
+	if err != nil {
+		return nil, nil, nil, err
+	}
 	entries, _, err := p.ParseFile(sessionFile.FilePath, 0)
 	if err != nil {
 		return nil, nil, nil, fmt.Errorf("failed to parse session file: %w", err)
@@ -1170,12 +1480,74 @@ func (c *sessionCommand) writeExportOutput(
 	}
 
 	if output != "" {
-		fmt.Printf("Exported %d entries to %s\n", entryCount, output)
+		c.globalOpts.infof("Exported %d entries to %s\n", entryCount, output)
 	}
 
 	return nil
 }
 
+// Values of session export -split.
+const (
+	exportSplitDay   = "day"
+	exportSplitBlock = "block"
+)
+
+// exportSlice is the entries of one day or billing block of a session.
+type exportSlice struct {
+	// Key names the slice: the local date (2006-01-02), or the billing
+	// block ID (see aggregator.BlockID).
+	Key     string
+	Entries []parser.UsageEntry
+}
+
+// splitExportEntries groups entries by local day or by billing block,
+// oldest first.
+func splitExportEntries(entries []parser.UsageEntry, split string) []exportSlice {
+	index := make(map[string]int)
+	var slices []exportSlice
+	for _, entry := range entries {
+		key := entry.Timestamp.Local().Format("2006-01-02")
+		if split == exportSplitBlock {
+			key = aggregator.BlockID(entry.Timestamp)
+		}
+		i, ok := index[key]
+		if !ok {
+			i = len(slices)
+			index[key] = i
+			slices = append(slices, exportSlice{Key: key})
+		}
+		slices[i].Entries = append(slices[i].Entries, entry)
+	}
+	sort.Slice(slices, func(i, j int) bool { return slices[i].Key < slices[j].Key })
+	return slices
+}
+
+// writeExportSlices writes each slice to its own file in dir, named after
+// the session and the slice, e.g. a1b2c3d4-2025-11-03.json.
+func (c *sessionCommand) writeExportSlices(
+	format, dir string,
+	sessionFile *discovery.SessionFile,
+	metadata *session.Metadata,
+	slices []exportSlice,
+	log logger.Logger,
+) error {
+	ext := format
+	if format == "agent-forge" {
+		ext = "json"
+	}
+	for _, slice := range slices {
+		path := filepath.Join(dir, fmt.Sprintf("%s-%s.%s", shortSessionID(sessionFile.SessionID), slice.Key, ext))
+		data := buildExportData(sessionFile, metadata, slice.Entries)
+		if err := c.writeExportOutput(format, path, data, len(slice.Entries), log); err != nil {
+			return err
+		}
+	}
+	if len(slices) == 0 {
+		c.globalOpts.infof("No entries to export\n")
+	}
+	return nil
+}
+
 // buildExportData creates ExportData from session information and entries.
 func buildExportData(sessionFile *discovery.SessionFile, metadata *session.Metadata, entries []parser.UsageEntry) ExportData {
 	data := ExportData{
@@ -1363,6 +1735,9 @@ Subcommands:
   delete <name|uuid>    Remove session metadata (preserves data files)
   export <name|uuid>    Export session data (json, csv, agent-forge)
   compare <a> <b>       Compare two sessions side by side
+  label <name|uuid> <ticket>
+                        Attach a ticket for "token-monitor tickets"
+                        (-clear <name|uuid> removes it)
   help                  Show this help message
 
 List Flags:
@@ -1372,7 +1747,16 @@ List Flags:
   -from        Filter sessions updated after date (YYYY-MM-DD)
   -to          Filter sessions updated before date (YYYY-MM-DD)
   -min-tokens  Filter sessions with at least N tokens
+  -state       Filter by lifecycle state: active, idle, archived
+               (thresholds: session.idle_after, session.archive_after)
   -tokens      Show token counts in output
+  -absolute    Show absolute timestamps instead of relative times ("2h ago")
+
+Show Flags:
+  -compact     Metadata and a one-line usage summary instead of the tables
+  -detailed    Also show usage and cost per model
+  -absolute    Show absolute timestamps instead of relative times
+  -format      Output format: table, json (default: table; -json implies json)
 
 Delete Flags:
   -force   Skip confirmation prompt
@@ -1403,12 +1787,18 @@ Examples:
   # Filter by minimum tokens
   token-monitor session list -min-tokens 10000
 
+  # Sessions still being worked on
+  token-monitor session list -all -state active
+
   # Combine filters
   token-monitor session list -project api -min-tokens 5000 -sort tokens
 
   # Show session details
   token-monitor session show my-project
 
+  # Session details as JSON (metadata, breakdown, blocks, timeline)
+  token-monitor session show -format json my-project
+
   # Delete session metadata
   token-monitor session delete my-project
 
@@ -1421,6 +1811,12 @@ Examples:
   # Export session to CSV file
   token-monitor session export my-project -format csv -output session.csv
 
+  # Export one file per billing block
+  token-monitor session export my-project -split block -output-dir exports/
+
+  # Attribute a session's spend to a ticket
+  token-monitor session label my-project JIRA-123
+
   # Compare two sessions
   token-monitor session compare session-a session-b
 
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/0xmhha/token-monitor/pkg/parser"
+)
+
+func TestSplitExportEntries(t *testing.T) {
+	at := func(day, hour int) parser.UsageEntry {
+		return parser.UsageEntry{Timestamp: time.Date(2025, 11, day, hour, 30, 0, 0, time.UTC)}
+	}
+	entries := []parser.UsageEntry{at(4, 1), at(3, 11), at(3, 12), at(3, 16)}
+
+	blocks := splitExportEntries(entries, exportSplitBlock)
+	want := []struct {
+		key   string
+		count int
+	}{{"2025-11-03T1000Z", 2}, {"2025-11-03T1500Z", 1}, {"2025-11-04T0000Z", 1}}
+	if len(blocks) != len(want) {
+		t.Fatalf("block slices = %d, want %d", len(blocks), len(want))
+	}
+	for i, w := range want {
+		if blocks[i].Key != w.key || len(blocks[i].Entries) != w.count {
+			t.Errorf("block %d = %s with %d entries, want %s with %d", i, blocks[i].Key, len(blocks[i].Entries), w.key, w.count)
+		}
+	}
+
+	days := splitExportEntries(entries, exportSplitDay)
+	total := 0
+	for i, d := range days {
+		if i > 0 && d.Key <= days[i-1].Key {
+			t.Errorf("day slices not sorted: %s after %s", d.Key, days[i-1].Key)
+		}
+		total += len(d.Entries)
+	}
+	if total != len(entries) {
+		t.Errorf("day slices hold %d entries, want %d", total, len(entries))
+	}
+
+	if got := splitExportEntries(nil, exportSplitDay); len(got) != 0 {
+		t.Errorf("splitExportEntries(nil) = %v, want none", got)
+	}
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/0xmhha/token-monitor/pkg/aggregator"
+	"github.com/0xmhha/token-monitor/pkg/analysis"
+	"github.com/0xmhha/token-monitor/pkg/display"
+	"github.com/0xmhha/token-monitor/pkg/parser"
+	"github.com/0xmhha/token-monitor/pkg/session"
+)
+
+// sessionShow is the JSON document for session show -format json. Usage
+// fields are omitted when the session file is not found.
+type sessionShow struct {
+	Metadata  *session.Metadata `json:"metadata"`
+	FilePath  string            `json:"file_path,omitempty"`
+	Breakdown *sessionBreakdown `json:"breakdown,omitempty"`
+	Blocks    []sessionBlock    `json:"blocks,omitempty"`
+	Models    []sessionModel    `json:"models,omitempty"`
+	Timeline  []sessionActivity `json:"timeline,omitempty"`
+}
+
+// sessionBreakdown is the token breakdown and request statistics.
+type sessionBreakdown struct {
+	Requests            int     `json:"requests"`
+	InputTokens         int     `json:"input_tokens"`
+	OutputTokens        int     `json:"output_tokens"`
+	CacheCreationTokens int     `json:"cache_creation_tokens"`
+	CacheReadTokens     int     `json:"cache_read_tokens"`
+	TotalTokens         int     `json:"total_tokens"`
+	CostUSD             float64 `json:"cost_usd"`
+	AvgTokens           float64 `json:"avg_tokens"`
+	MinTokens           int     `json:"min_tokens"`
+	MaxTokens           int     `json:"max_tokens"`
+	P50Tokens           int     `json:"p50_tokens"`
+	P95Tokens           int     `json:"p95_tokens"`
+	P99Tokens           int     `json:"p99_tokens"`
+	SubAgentRequests    int     `json:"sub_agent_requests,omitempty"`
+	SubAgentTokens      int     `json:"sub_agent_tokens,omitempty"`
+}
+
+// sessionBlock is one billing block of the session.
+type sessionBlock struct {
+	Start        time.Time `json:"start"`
+	End          time.Time `json:"end"`
+	Requests     int       `json:"requests"`
+	InputTokens  int       `json:"input_tokens"`
+	OutputTokens int       `json:"output_tokens"`
+	TotalTokens  int       `json:"total_tokens"`
+	Active       bool      `json:"active"`
+}
+
+// sessionModel is the session's usage of one model.
+type sessionModel struct {
+	Model       string  `json:"model"`
+	Requests    int     `json:"requests"`
+	TotalTokens int     `json:"total_tokens"`
+	CostUSD     float64 `json:"cost_usd"`
+}
+
+// sessionActivity is one request in the session timeline.
+type sessionActivity struct {
+	Timestamp   time.Time `json:"timestamp"`
+	Model       string    `json:"model"`
+	TotalTokens int       `json:"total_tokens"`
+	SubAgent    bool      `json:"sub_agent,omitempty"`
+}
+
+// buildSessionShow builds the session show document from the session's
+// entries, covering the whole timeline rather than the last entries the
+// table shows.
+func buildSessionShow(metadata *session.Metadata, filePath string, entries []parser.UsageEntry) sessionShow {
+	doc := sessionShow{Metadata: metadata, FilePath: filePath}
+	if len(entries) == 0 {
+		return doc
+	}
+
+	agg := aggregator.New(aggregator.Config{TrackPercentiles: true})
+	breakdown := &sessionBreakdown{}
+	doc.Timeline = make([]sessionActivity, 0, len(entries))
+	for _, entry := range entries {
+		entry.SessionID = metadata.UUID
+		agg.Add(entry)
+
+		breakdown.CacheCreationTokens += entry.Message.Usage.CacheCreationInputTokens
+		breakdown.CacheReadTokens += entry.Message.Usage.CacheReadInputTokens
+		breakdown.CostUSD += analysis.EntryCost(entry)
+		doc.Timeline = append(doc.Timeline, sessionActivity{
+			Timestamp:   entry.Timestamp,
+			Model:       entry.Message.Model,
+			TotalTokens: entry.Message.Usage.TotalTokens(),
+			SubAgent:    entry.IsSubAgent(),
+		})
+	}
+
+	stats := agg.Stats()
+	breakdown.Requests = stats.Count
+	breakdown.InputTokens = stats.InputTokens
+	breakdown.OutputTokens = stats.OutputTokens
+	breakdown.TotalTokens = stats.TotalTokens
+	breakdown.AvgTokens = stats.AvgTokens
+	breakdown.MinTokens = stats.MinTokens
+	breakdown.MaxTokens = stats.MaxTokens
+	breakdown.P50Tokens = stats.P50Tokens
+	breakdown.P95Tokens = stats.P95Tokens
+	breakdown.P99Tokens = stats.P99Tokens
+	breakdown.SubAgentRequests = stats.SubAgentCount
+	breakdown.SubAgentTokens = stats.SubAgentTokens
+	doc.Breakdown = breakdown
+
+	for _, block := range agg.BillingBlocks(metadata.UUID) {
+		doc.Blocks = append(doc.Blocks, sessionBlock{
+			Start:        block.StartTime,
+			End:          block.EndTime,
+			Requests:     block.EntryCount,
+			InputTokens:  block.InputTokens,
+			OutputTokens: block.OutputTokens,
+			TotalTokens:  block.TotalTokens,
+			Active:       block.IsActive,
+		})
+	}
+	doc.Models = sessionModels(entries)
+	return doc
+}
+
+// sessionModels totals entries per model, most tokens first.
+func sessionModels(entries []parser.UsageEntry) []sessionModel {
+	byModel := make(map[string]*sessionModel)
+	var models []sessionModel
+	for _, entry := range entries {
+		m, ok := byModel[entry.Message.Model]
+		if !ok {
+			m = &sessionModel{Model: entry.Message.Model}
+			byModel[entry.Message.Model] = m
+		}
+		m.Requests++
+		m.TotalTokens += entry.Message.Usage.TotalTokens()
+		m.CostUSD += analysis.EntryCost(entry)
+	}
+	for _, m := range byModel {
+		models = append(models, *m)
+	}
+	sort.Slice(models, func(i, j int) bool {
+		if models[i].TotalTokens != models[j].TotalTokens {
+			return models[i].TotalTokens > models[j].TotalTokens
+		}
+		return models[i].Model < models[j].Model
+	})
+	return models
+}
+
+// sessionSummaryLine is session show -compact's one-line usage summary,
+// e.g. "1.2M tokens (in 10.0K, out 5.0K, cache 1.2M) over 42 requests in
+// 3 billing blocks, 2h 10m".
+func sessionSummaryLine(stats aggregator.Statistics, blocks int, entries []parser.UsageEntry) string {
+	line := fmt.Sprintf("%s tokens (in %s, out %s, cache %s) over %d requests in %d billing blocks",
+		display.FormatCompact(stats.TotalTokens), display.FormatCompact(stats.InputTokens),
+		display.FormatCompact(stats.OutputTokens), display.FormatCompact(stats.CacheCreationTokens+stats.CacheReadTokens),
+		stats.Count, blocks)
+	if len(entries) >= 2 {
+		first, last := entries[0].Timestamp, entries[0].Timestamp
+		for _, entry := range entries[1:] {
+			if entry.Timestamp.Before(first) {
+				first = entry.Timestamp
+			}
+			if entry.Timestamp.After(last) {
+				last = entry.Timestamp
+			}
+		}
+		line += ", " + display.FormatDurationLong(last.Sub(first), 2)
+	}
+	return line
+}
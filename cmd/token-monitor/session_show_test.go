@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0xmhha/token-monitor/pkg/aggregator"
+	"github.com/0xmhha/token-monitor/pkg/parser"
+	"github.com/0xmhha/token-monitor/pkg/session"
+)
+
+func TestBuildSessionShow(t *testing.T) {
+	metadata := &session.Metadata{UUID: "11111111-1111-1111-1111-111111111111", Name: "api"}
+	start := time.Date(2025, 11, 3, 9, 0, 0, 0, time.UTC)
+	entries := []parser.UsageEntry{
+		{
+			Timestamp: start,
+			Message: parser.Message{Model: "claude-sonnet-4", Usage: parser.Usage{
+				InputTokens: 1000, OutputTokens: 200, CacheReadInputTokens: 300,
+			}},
+		},
+		{
+			Timestamp:   start.Add(6 * time.Hour), // next billing block
+			IsSidechain: true,
+			Message:     parser.Message{Model: "claude-opus-4", Usage: parser.Usage{InputTokens: 500}},
+		},
+	}
+
+	doc := buildSessionShow(metadata, "/p/s.jsonl", entries)
+
+	b := doc.Breakdown
+	if b == nil {
+		t.Fatal("Breakdown = nil, want totals")
+	}
+	if b.Requests != 2 || b.InputTokens != 1500 || b.CacheReadTokens != 300 || b.TotalTokens != 2000 {
+		t.Errorf("Breakdown = %+v, want 2 requests, 1500 input, 300 cache read, 2000 total", *b)
+	}
+	if b.SubAgentRequests != 1 || b.SubAgentTokens != 500 || b.CostUSD <= 0 {
+		t.Errorf("Breakdown = %+v, want one 500-token sub-agent request and a cost", *b)
+	}
+	if len(doc.Blocks) != 2 || doc.Blocks[0].TotalTokens != 500 {
+		t.Errorf("Blocks = %+v, want 2 blocks, most recent first", doc.Blocks)
+	}
+	if len(doc.Timeline) != 2 || doc.Timeline[0].TotalTokens != 1500 || !doc.Timeline[1].SubAgent {
+		t.Errorf("Timeline = %+v, want both entries", doc.Timeline)
+	}
+
+	data, err := json.Marshal(buildSessionShow(metadata, "", nil))
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	for _, field := range []string{"breakdown", "blocks", "timeline", "file_path"} {
+		if strings.Contains(string(data), field) {
+			t.Errorf("document without a file = %s, want no %s", data, field)
+		}
+	}
+}
+
+func TestSessionModels(t *testing.T) {
+	entries := []parser.UsageEntry{
+		{Message: parser.Message{Model: "claude-sonnet-4", Usage: parser.Usage{InputTokens: 100}}},
+		{Message: parser.Message{Model: "claude-opus-4", Usage: parser.Usage{InputTokens: 500}}},
+		{Message: parser.Message{Model: "claude-sonnet-4", Usage: parser.Usage{OutputTokens: 50}}},
+	}
+
+	models := sessionModels(entries)
+	if len(models) != 2 {
+		t.Fatalf("sessionModels() = %+v, want 2 models", models)
+	}
+	if models[0].Model != "claude-opus-4" || models[0].TotalTokens != 500 {
+		t.Errorf("models[0] = %+v, want opus with 500 tokens first", models[0])
+	}
+	if models[1].Requests != 2 || models[1].TotalTokens != 150 {
+		t.Errorf("models[1] = %+v, want sonnet with 2 requests and 150 tokens", models[1])
+	}
+}
+
+func TestSessionSummaryLine(t *testing.T) {
+	start := time.Date(2025, 11, 3, 9, 0, 0, 0, time.UTC)
+	// Files are not always in time order.
+	entries := []parser.UsageEntry{{Timestamp: start.Add(2*time.Hour + 10*time.Minute)}, {Timestamp: start}}
+	stats := aggregator.Statistics{Count: 42, InputTokens: 10000, OutputTokens: 5000, CacheReadTokens: 1185000, TotalTokens: 1200000}
+
+	want := "1.2M tokens (in 10.0K, out 5.0K, cache 1.2M) over 42 requests in 3 billing blocks, 2h 10m"
+	if got := sessionSummaryLine(stats, 3, entries); got != want {
+		t.Errorf("sessionSummaryLine() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/0xmhha/token-monitor/cmd/internal/runtime"
+	"github.com/0xmhha/token-monitor/pkg/aggregator"
+	"github.com/0xmhha/token-monitor/pkg/discovery"
+	"github.com/0xmhha/token-monitor/pkg/display"
+	"github.com/0xmhha/token-monitor/pkg/parser"
+)
+
+// verifyTotals are the totals compared by stats -verify.
+type verifyTotals struct {
+	Entries       int
+	Input         int
+	Output        int
+	CacheCreation int
+	CacheRead     int
+}
+
+// Total returns the sum of all token counts.
+func (t verifyTotals) Total() int {
+	return t.Input + t.Output + t.CacheCreation + t.CacheRead
+}
+
+// statsTotals returns the verify totals of aggregated statistics.
+func statsTotals(s aggregator.Statistics) verifyTotals {
+	return verifyTotals{
+		Entries:       s.Count,
+		Input:         s.InputTokens,
+		Output:        s.OutputTokens,
+		CacheCreation: s.CacheCreationTokens,
+		CacheRead:     s.CacheReadTokens,
+	}
+}
+
+// truthLine holds the fields of a session file line that ground truth
+// needs. It is decoded separately from parser.UsageEntry on purpose.
+type truthLine struct {
+	Timestamp time.Time `json:"timestamp"`
+	SessionID string    `json:"sessionId"`
+	Message   struct {
+		Model string `json:"model"`
+		Usage struct {
+			Input         int `json:"input_tokens"`
+			Output        int `json:"output_tokens"`
+			CacheCreation int `json:"cache_creation_input_tokens"`
+			CacheRead     int `json:"cache_read_input_tokens"`
+		} `json:"usage"`
+	} `json:"message"`
+}
+
+// truthFilter selects the lines counted by groundTruth, mirroring the
+// stats filters.
+type truthFilter struct {
+	model         *aggregator.ModelFilter
+	since         time.Time
+	keepMissingID bool // lines without a sessionId count (filename policy)
+}
+
+// groundTruth recomputes totals straight from the session files with a
+// plain line reader, sharing no code with the parser, reader, cache, or
+// aggregator, so that drift in those layers shows up as a difference.
+func groundTruth(files []discovery.SessionFile, filter truthFilter) (verifyTotals, error) {
+	var totals verifyTotals
+	for _, file := range files {
+		if err := addFileTruth(&totals, file.FilePath, filter); err != nil {
+			return totals, err
+		}
+	}
+	return totals, nil
+}
+
+// addFileTruth adds the counted lines of the file at path to totals.
+func addFileTruth(totals *verifyTotals, path string, filter truthFilter) error {
+	f, err := os.Open(path) //nolint:gosec // path comes from discovery
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer func() {
+		_ = f.Close() //nolint:errcheck // read-only file
+	}()
+
+	br := bufio.NewReader(f)
+	for {
+		line, readErr := br.ReadBytes('\n')
+		if len(line) > 0 {
+			countTruthLine(totals, line, filter)
+		}
+		if readErr == io.EOF {
+			return nil
+		}
+		if readErr != nil {
+			return fmt.Errorf("failed to read %s: %w", path, readErr)
+		}
+	}
+}
+
+// countTruthLine adds line to totals if it is a valid usage entry that
+// passes filter. Lines that do not decode are not counted.
+func countTruthLine(totals *verifyTotals, line []byte, filter truthFilter) {
+	line = bytes.TrimPrefix(bytes.TrimSpace(line), []byte("\ufeff"))
+	var l truthLine
+	if err := json.Unmarshal(line, &l); err != nil {
+		return
+	}
+	u := l.Message.Usage
+	if l.Timestamp.IsZero() || l.Message.Model == "" {
+		return
+	}
+	if l.SessionID == "" && !filter.keepMissingID {
+		return
+	}
+	for _, n := range []int{u.Input, u.Output, u.CacheCreation, u.CacheRead} {
+		if n < 0 || n > parser.MaxTokenCount {
+			return
+		}
+	}
+	if !filter.model.Match(l.Message.Model) {
+		return
+	}
+	if !filter.since.IsZero() && l.Timestamp.Before(filter.since) {
+		return
+	}
+
+	totals.Entries++
+	totals.Input += u.Input
+	totals.Output += u.Output
+	totals.CacheCreation += u.CacheCreation
+	totals.CacheRead += u.CacheRead
+}
+
+// verifyStats compares the statistics shown with ground truth from the
+// session files stats read, and the stored rollups with the raw files. The
+// report goes to stderr so that it never mixes with JSON output. It
+// returns an error wrapping errDrift when either differs.
+func (c *statsCommand) verifyStats(rt *runtime.Runtime, agg aggregator.Aggregator) error {
+	cfg, err := rt.Config()
+	if err != nil {
+		return err
+	}
+	modelFilter, err := aggregator.ParseModelFilter(c.model)
+	if err != nil {
+		return err
+	}
+
+	out := c.globalOpts.output()
+	var drift []string
+
+	truth, err := groundTruth(c.read, truthFilter{
+		model:         modelFilter,
+		since:         c.since,
+		keepMissingID: cfg.Discovery.MissingSessionID == string(parser.MissingSessionIDFilename) || cfg.Discovery.SessionAttribution == string(parser.AttributeFile),
+	})
+	if err != nil {
+		return err
+	}
+	var shown verifyTotals
+	if agg != nil {
+		shown = statsTotals(agg.Stats())
+	}
+	if shown != truth {
+		out.Warnf("Drift: stats counted %s entries / %s tokens, the session files hold %s / %s\n",
+			display.FormatNumber(shown.Entries), display.FormatNumber(shown.Total()),
+			display.FormatNumber(truth.Entries), display.FormatNumber(truth.Total()))
+		drift = append(drift, "stats")
+	} else {
+		out.Infof("Verified stats: %s entries / %s tokens match the session files\n",
+			display.FormatNumber(truth.Entries), display.FormatNumber(truth.Total()))
+	}
+
+	mismatches, err := c.verifyRollups(rt)
+	switch {
+	case errors.Is(err, errRollupsUnavailable):
+		out.Infof("Rollups not verified: %v\n", err)
+	case err != nil:
+		return err
+	case mismatches > 0:
+		out.Warnf("Drift: %d rollup row(s) differ from the session files (token-monitor fsck -repair rebuilds them)\n", mismatches)
+		drift = append(drift, "rollups")
+	default:
+		out.Infof("Verified rollups: stored rows match the session files\n")
+	}
+
+	if len(drift) > 0 {
+		return fmt.Errorf("%w: %d check(s) failed", errDrift, len(drift))
+	}
+	return nil
+}
+
+// errRollupsUnavailable is returned by verifyRollups when the rollup store
+// cannot be opened, e.g. because watch holds the database.
+var errRollupsUnavailable = errors.New("rollup store unavailable")
+
+// verifyRollups recomputes the rollups of the files stats read and returns
+// how many stored rows differ.
+func (c *statsCommand) verifyRollups(rt *runtime.Runtime) (int, error) {
+	store, err := rt.Rollups()
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", errRollupsUnavailable, err)
+	}
+	r, err := rt.NewReader()
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_ = r.Close() //nolint:errcheck // best effort cleanup
+	}()
+
+	result, err := store.Verify(context.Background(), c.read, r)
+	if err != nil {
+		return 0, fmt.Errorf("failed to verify rollups: %w", err)
+	}
+	if result.Pending > 0 {
+		c.globalOpts.infof("%d session file(s) have entries not yet rolled up; their sessions were skipped\n", result.Pending)
+	}
+	return len(result.Mismatches), nil
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0xmhha/token-monitor/pkg/aggregator"
+	"github.com/0xmhha/token-monitor/pkg/discovery"
+)
+
+func TestGroundTruth(t *testing.T) {
+	lines := []string{
+		`{"timestamp":"2025-11-03T10:00:00Z","sessionId":"s1","message":{"model":"claude-sonnet-4","usage":{"input_tokens":100,"output_tokens":20,"cache_creation_input_tokens":5,"cache_read_input_tokens":300}}}`,
+		"\ufeff" + `{"timestamp":"2025-11-03T11:00:00Z","sessionId":"s1","message":{"model":"claude-opus-4","usage":{"input_tokens":10,"output_tokens":2}}}`,
+		`{"timestamp":"2025-11-03T12:00:00Z","message":{"model":"claude-sonnet-4","usage":{"input_tokens":7}}}`, // no sessionId
+		`{"timestamp":"2025-11-03T12:00:00Z","sessionId":"s1","message":{"usage":{"input_tokens":7}}}`,          // no model
+		`{"timestamp":"2025-11-03T12:00:00Z","sessionId":"s1","message":{"model":"claude-sonnet-4","usage":{"input_tokens":-1}}}`,
+		`not json`,
+		`{"timestamp":"2025-11-03T13:00:00Z","sessionId":"s1","message":{"model":"claude-sonnet-4","usage":{"output_tokens":1}}}`, // unterminated
+	}
+	path := filepath.Join(t.TempDir(), "s1.jsonl")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0600); err != nil {
+		t.Fatal(err)
+	}
+	files := []discovery.SessionFile{{SessionID: "s1", FilePath: path}}
+
+	got, err := groundTruth(files, truthFilter{})
+	if err != nil {
+		t.Fatalf("groundTruth() error = %v", err)
+	}
+	want := verifyTotals{Entries: 3, Input: 110, Output: 23, CacheCreation: 5, CacheRead: 300}
+	if got != want {
+		t.Errorf("groundTruth() = %+v, want %+v", got, want)
+	}
+
+	got, err = groundTruth(files, truthFilter{keepMissingID: true})
+	if err != nil {
+		t.Fatalf("groundTruth() error = %v", err)
+	}
+	if got.Entries != 4 || got.Input != 117 {
+		t.Errorf("groundTruth(keepMissingID) = %+v, want the line without sessionId counted", got)
+	}
+
+	opus, err := aggregator.ParseModelFilter("*opus*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	since := time.Date(2025, 11, 3, 10, 30, 0, 0, time.UTC)
+	got, err = groundTruth(files, truthFilter{model: opus, since: since})
+	if err != nil {
+		t.Fatalf("groundTruth() error = %v", err)
+	}
+	if got != (verifyTotals{Entries: 1, Input: 10, Output: 2}) {
+		t.Errorf("groundTruth(filtered) = %+v, want only the opus entry", got)
+	}
+
+	if _, err := groundTruth([]discovery.SessionFile{{FilePath: path + ".missing"}}, truthFilter{}); err == nil {
+		t.Error("groundTruth(missing file) error = nil, want error")
+	}
+}
+
+func TestStatsTotals(t *testing.T) {
+	agg := aggregator.New(aggregator.Config{})
+	s := statsTotals(agg.Stats())
+	if s != (verifyTotals{}) || s.Total() != 0 {
+		t.Errorf("statsTotals(empty) = %+v, want zero", s)
+	}
+
+	got := statsTotals(aggregator.Statistics{Count: 2, InputTokens: 1, OutputTokens: 2, CacheCreationTokens: 3, CacheReadTokens: 4})
+	if got.Entries != 2 || got.Total() != 10 {
+		t.Errorf("statsTotals() = %+v, want 2 entries and 10 tokens", got)
+	}
+}
@@ -14,12 +14,10 @@ import (
 	"golang.org/x/term"
 
 	"github.com/0xmhha/token-monitor/pkg/aggregator"
-	"github.com/0xmhha/token-monitor/pkg/config"
 	"github.com/0xmhha/token-monitor/pkg/discovery"
 	"github.com/0xmhha/token-monitor/pkg/display"
-	"github.com/0xmhha/token-monitor/pkg/logger"
 	"github.com/0xmhha/token-monitor/pkg/parser"
-	"github.com/0xmhha/token-monitor/pkg/reader"
+	"github.com/0xmhha/token-monitor/pkg/session"
 	"github.com/0xmhha/token-monitor/pkg/sessionloader"
 )
 
@@ -213,26 +211,27 @@ func (c *statusCommand) collect() (statusData, error) {
 // this discovers every session under the configured Claude config dirs.
 // Per-session read errors are logged and skipped via pkg/sessionloader.
 func (c *statusCommand) collectEntries() ([]parser.UsageEntry, error) {
-	cfg, err := config.Load()
+	// Quiet logger suitable for status output.
+	rt := c.globalOpts.newRuntime("error")
+	defer func() {
+		_ = rt.Close() //nolint:errcheck // best effort cleanup
+	}()
+
+	log, err := rt.Logger()
 	if err != nil {
-		return nil, fmt.Errorf("failed to load config: %w", err)
+		return nil, err
+	}
+	disc, err := rt.Discoverer()
+	if err != nil {
+		return nil, err
 	}
-
-	log := c.buildLogger(cfg)
-	disc := discovery.New(cfg.ClaudeConfigDirs, log)
 
 	sessions, err := c.resolveSessions(disc)
 	if err != nil {
 		return nil, err
 	}
 
-	factory := func() (reader.Reader, error) {
-		return reader.New(reader.Config{
-			PositionStore: reader.NewMemoryPositionStore(),
-			Parser:        parser.New(),
-		}, log)
-	}
-	return sessionloader.LoadEntries(context.Background(), sessions, factory, log)
+	return sessionloader.LoadEntries(context.Background(), sessions, rt.NewReader, log)
 }
 
 // resolveSessions returns the session files to aggregate.
@@ -249,7 +248,7 @@ func (c *statusCommand) resolveSessions(disc discovery.Discoverer) ([]discovery.
 			}
 		}
 		if len(filtered) == 0 {
-			return nil, fmt.Errorf("session not found: %s", c.sessionID)
+			return nil, fmt.Errorf("%w: %s", session.ErrSessionNotFound, c.sessionID)
 		}
 		return filtered, nil
 	}
@@ -271,14 +270,19 @@ func (c *statusCommand) resolveSessions(disc discovery.Discoverer) ([]discovery.
 
 // format renders data into the requested output format string.
 func (c *statusCommand) format(d statusData) string {
+	var line string
 	switch {
 	case c.compact:
-		return c.formatCompact(d)
+		line = c.formatCompact(d)
 	case c.full:
-		return c.formatFull(d)
+		line = c.formatFull(d)
 	default:
-		return c.formatDefault(d)
+		line = c.formatDefault(d)
+	}
+	if c.globalOpts.ascii {
+		return display.ASCII(line)
 	}
+	return line
 }
 
 // formatCompact renders a minimal format of approximately 13 chars.
@@ -311,19 +315,6 @@ func (c *statusCommand) formatFull(d statusData) string {
 		total, rate, in, out, remain)
 }
 
-// buildLogger creates a quiet logger suitable for status output.
-func (c *statusCommand) buildLogger(cfg *config.Config) logger.Logger {
-	level := "error"
-	if c.globalOpts.logLevel != "" {
-		level = c.globalOpts.logLevel
-	}
-	return logger.New(logger.Config{
-		Level:  level,
-		Format: cfg.Logging.Format,
-		Output: cfg.Logging.Output,
-	})
-}
-
 // runStatusCommand parses flags and runs the status command.
 func runStatusCommand(globalOpts globalOptions, args []string) error {
 	fs := flag.NewFlagSet("status", flag.ExitOnError)
@@ -339,6 +330,9 @@ func runStatusCommand(globalOpts globalOptions, args []string) error {
 	window := fs.String("window", "today", "time window for --breakdown: today, all, Nd, or Nh")
 	modelGlob := fs.String("model-glob", "", "filter --breakdown by model glob, e.g. '*sonnet*'")
 
+	if err := globalOpts.applyFlagDefaults(fs); err != nil {
+		return err
+	}
 	if err := fs.Parse(args); err != nil {
 		return err
 	}
@@ -348,7 +342,7 @@ func runStatusCommand(globalOpts globalOptions, args []string) error {
 		sessionID:  *sessionID,
 		compact:    *compact,
 		full:       *full,
-		noEmoji:    *noEmoji,
+		noEmoji:    *noEmoji || globalOpts.accessible,
 		watch:      *watch,
 		interval:   *interval,
 		fromStdin:  *fromStdin,
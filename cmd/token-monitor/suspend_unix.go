@@ -0,0 +1,21 @@
+//go:build !windows
+
+package main
+
+import (
+	"os"
+	"syscall"
+)
+
+// continueSignal is delivered when a stopped watch is resumed (fg, bg, or
+// kill -CONT).
+var continueSignal os.Signal = syscall.SIGCONT
+
+// stopProcess stops the process group the way Ctrl+Z outside raw mode
+// would. SIGTSTP is never passed to signal.Notify, which would keep the Go
+// runtime's handler installed for good and the signal would no longer stop
+// the process. It returns once the process is continued, or at once in an
+// orphaned process group, where the kernel discards SIGTSTP.
+func stopProcess() {
+	_ = syscall.Kill(0, syscall.SIGTSTP) //nolint:errcheck // signalling our own group cannot fail
+}
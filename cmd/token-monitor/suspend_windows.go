@@ -0,0 +1,11 @@
+//go:build windows
+
+package main
+
+import "os"
+
+// continueSignal is nil on Windows, which has no job control.
+var continueSignal os.Signal
+
+// stopProcess is a no-op on Windows.
+func stopProcess() {}
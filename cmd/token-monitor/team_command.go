@@ -0,0 +1,353 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/0xmhha/token-monitor/cmd/internal/runtime"
+	"github.com/0xmhha/token-monitor/pkg/aggregator"
+	"github.com/0xmhha/token-monitor/pkg/config"
+	"github.com/0xmhha/token-monitor/pkg/display"
+	"github.com/0xmhha/token-monitor/pkg/parser"
+	"github.com/0xmhha/token-monitor/pkg/plan"
+	"github.com/0xmhha/token-monitor/pkg/rollup"
+	"github.com/0xmhha/token-monitor/pkg/session"
+	"github.com/0xmhha/token-monitor/pkg/teamshare"
+)
+
+// monthLayout is the format of the team -month flag.
+const monthLayout = "2006-01"
+
+// unlabeledMember is shown for usage from unlabeled Claude directories.
+const unlabeledMember = "(unlabeled)"
+
+// teamCommand reports quota utilization and spend per team member.
+type teamCommand struct {
+	month      time.Time
+	format     string
+	globalOpts globalOptions
+}
+
+// runTeamCommand runs the team command.
+func runTeamCommand(globalOpts globalOptions, args []string) error {
+	if len(args) > 0 {
+		switch args[0] {
+		case "stats":
+			return runTeamStatsCommand(globalOpts, args[1:])
+		case "push":
+			return runTeamPushCommand(globalOpts, args[1:])
+		}
+	}
+
+	fs := flag.NewFlagSet("team", flag.ExitOnError)
+	month := fs.String("month", "", "month to report (YYYY-MM, default: current month)")
+	format := fs.String("format", "table", "output format (table, json)")
+
+	if err := globalOpts.applyFlagDefaults(fs); err != nil {
+		return err
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if globalOpts.jsonOutput {
+		*format = "json"
+	}
+	switch *format {
+	case "table", "json":
+	default:
+		return fmt.Errorf("invalid -format %q (want table or json)", *format)
+	}
+
+	now := time.Now()
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local)
+	if *month != "" {
+		t, err := time.ParseInLocation(monthLayout, *month, time.Local)
+		if err != nil {
+			return fmt.Errorf("invalid -month %q (want YYYY-MM)", *month)
+		}
+		start = t
+	}
+
+	cmd := &teamCommand{month: start, format: *format, globalOpts: globalOpts}
+	return cmd.Execute()
+}
+
+// Execute computes the month's team report and prints it.
+func (c *teamCommand) Execute() error {
+	rt := c.globalOpts.newRuntime("")
+	defer func() {
+		_ = rt.Close() //nolint:errcheck // best effort cleanup
+	}()
+
+	cfg, err := rt.Config()
+	if err != nil {
+		return err
+	}
+	team := cfg.Team.Team()
+	if len(team.Plans) == 0 {
+		c.globalOpts.infof("No team plans configured (team.plans); showing API-equivalent cost per member\n")
+	}
+
+	end := c.month.AddDate(0, 1, 0)
+	read, err := focusEntries(rt, c.month)
+	if err != nil {
+		return err
+	}
+	entries := make([]parser.UsageEntry, 0, len(read))
+	for _, entry := range read {
+		if entry.Timestamp.Before(end) {
+			entries = append(entries, entry)
+		}
+	}
+
+	report, err := team.Report(entries)
+	if err != nil {
+		return err
+	}
+	if c.format == "json" {
+		return printJSON(report)
+	}
+	return c.display(report)
+}
+
+// display prints the report as a table followed by the team totals.
+func (c *teamCommand) display(report plan.Report) error {
+	out := c.globalOpts.output()
+	out.Printf("Team usage for %s (%s)\n\n", c.month.Format(monthLayout), report.Currency)
+
+	header := []string{"MEMBER", "PLAN", "REQUESTS", "TOKENS", "TOKEN QUOTA", "API COST", "COST QUOTA", "SPEND"}
+	rows := make([][]string, 0, len(report.Members))
+	for _, m := range report.Members {
+		member, planName := m.Member, m.Plan
+		if member == "" {
+			member = unlabeledMember
+		}
+		if planName == "" {
+			planName = "-"
+		}
+		rows = append(rows, []string{
+			member,
+			planName,
+			display.FormatNumber(m.Totals.Entries),
+			display.FormatNumber(m.Totals.TotalTokens()),
+			formatQuota(m.TokenUtilization),
+			display.FormatCost(m.Totals.CostUSD),
+			formatQuota(m.CostUtilization),
+			formatAmount(m.Spend, report.Currency),
+		})
+	}
+	if err := display.WriteTable(os.Stdout, header, rows, false); err != nil {
+		return err
+	}
+
+	out.Printf("Total spend: %s (API equivalent: %s)\n",
+		formatAmount(report.Spend, report.Currency), formatAmount(report.APICost, report.Currency))
+	return nil
+}
+
+// formatQuota formats a utilization percentage, "-" without a limit.
+func formatQuota(percent *float64) string {
+	if percent == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f%%", *percent)
+}
+
+// formatAmount formats an amount with its currency code.
+func formatAmount(amount float64, currency string) string {
+	return fmt.Sprintf("%.2f %s", amount, currency)
+}
+
+// teamStatsCommand ranks team members by usage over a window.
+type teamStatsCommand struct {
+	window     string
+	byCost     bool
+	shared     bool
+	format     string
+	globalOpts globalOptions
+}
+
+// runTeamStatsCommand runs the team stats subcommand.
+func runTeamStatsCommand(globalOpts globalOptions, args []string) error {
+	fs := flag.NewFlagSet("team stats", flag.ExitOnError)
+	window := fs.String("window", "7d", "period to rank (today, all, Nd, Nh)")
+	by := fs.String("by", "tokens", "rank by tokens or cost")
+	shared := fs.Bool("shared", false, "rank the usage pushed to this team server instead of local usage")
+	format := fs.String("format", "table", "output format (table, json)")
+
+	if err := globalOpts.applyFlagDefaults(fs); err != nil {
+		return err
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if globalOpts.jsonOutput {
+		*format = "json"
+	}
+	switch *format {
+	case "table", "json":
+	default:
+		return fmt.Errorf("invalid -format %q (want table or json)", *format)
+	}
+	switch *by {
+	case "tokens", "cost":
+	default:
+		return fmt.Errorf("invalid -by %q (want tokens or cost)", *by)
+	}
+
+	cmd := &teamStatsCommand{window: *window, byCost: *by == "cost", shared: *shared, format: *format, globalOpts: globalOpts}
+	return cmd.Execute()
+}
+
+// Execute ranks the members by their usage in the window and prints the
+// leaderboard.
+func (c *teamStatsCommand) Execute() error {
+	since, err := display.ParseWindow(c.window, time.Now())
+	if err != nil {
+		return err
+	}
+
+	rt := c.globalOpts.newRuntime("")
+	defer func() {
+		_ = rt.Close() //nolint:errcheck // best effort cleanup
+	}()
+
+	cfg, err := rt.Config()
+	if err != nil {
+		return err
+	}
+	var board []plan.Standing
+	if c.shared {
+		board, err = sharedStandings(cfg, since, c.byCost)
+	} else {
+		board, err = localStandings(rt, cfg, since, c.byCost)
+	}
+	if err != nil {
+		return err
+	}
+
+	if c.format == "json" {
+		return printJSON(board)
+	}
+	return c.display(board)
+}
+
+// localStandings ranks the members by the usage in the local Claude
+// directories since since, one member per directory label.
+func localStandings(rt *runtime.Runtime, cfg *config.Config, since time.Time, byCost bool) ([]plan.Standing, error) {
+	entries, err := focusEntries(rt, since)
+	if err != nil {
+		return nil, err
+	}
+	return cfg.Team.Team().Leaderboard(aggregator.FilterSince(entries, since), byCost), nil
+}
+
+// sharedStandings ranks the members by the daily usage they pushed to
+// this team server, counting whole days from since's date.
+func sharedStandings(cfg *config.Config, since time.Time, byCost bool) ([]plan.Standing, error) {
+	store, err := teamshare.Open(teamSharePath(cfg))
+	if err != nil {
+		return nil, err
+	}
+	from := ""
+	if !since.IsZero() {
+		from = since.Format(rollup.DateLayout)
+	}
+	usage := store.Usage(from)
+	board := make([]plan.Standing, 0, len(usage))
+	for _, u := range usage {
+		board = append(board, plan.Standing{Member: u.Member, Totals: u.Totals, Sessions: u.Sessions})
+	}
+	return plan.Rank(board, byCost), nil
+}
+
+// display prints the leaderboard as a table.
+func (c *teamStatsCommand) display(board []plan.Standing) error {
+	out := c.globalOpts.output()
+	by := "tokens"
+	if c.byCost {
+		by = "API-equivalent cost"
+	}
+	source := ""
+	if c.shared {
+		source = ", shared usage"
+	}
+	out.Printf("Team leaderboard for %s (by %s%s)\n\n", c.window, by, source)
+	if len(board) == 0 {
+		out.Printf("No usage in this period.\n")
+		return nil
+	}
+
+	header := []string{"RANK", "MEMBER", "SESSIONS", "REQUESTS", "TOKENS", "COST", "SHARE"}
+	rows := make([][]string, 0, len(board))
+	for _, s := range board {
+		member := s.Member
+		if member == "" {
+			member = unlabeledMember
+		}
+		rows = append(rows, []string{
+			fmt.Sprintf("%d", s.Rank),
+			member,
+			display.FormatNumber(s.Sessions),
+			display.FormatNumber(s.Totals.Entries),
+			display.FormatNumber(s.Totals.TotalTokens()),
+			display.FormatCost(s.Totals.CostUSD),
+			fmt.Sprintf("%.1f%%", s.Share),
+		})
+	}
+	return display.WriteTable(os.Stdout, header, rows, false)
+}
+
+// teamPushCommand pushes this member's aggregate usage to the team server.
+type teamPushCommand struct {
+	globalOpts globalOptions
+}
+
+// runTeamPushCommand runs the team push subcommand.
+func runTeamPushCommand(globalOpts globalOptions, args []string) error {
+	fs := flag.NewFlagSet("team push", flag.ExitOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	cmd := &teamPushCommand{globalOpts: globalOpts}
+	return cmd.Execute()
+}
+
+// Execute updates the rollups and pushes their daily totals once, for a
+// scheduler when watch is not running.
+func (c *teamPushCommand) Execute() error {
+	rt := c.globalOpts.newRuntime("")
+	defer func() {
+		_ = rt.Close() //nolint:errcheck // best effort cleanup
+	}()
+
+	cfg, err := rt.Config()
+	if err != nil {
+		return err
+	}
+	if cfg.Team.Share.Endpoint == "" {
+		return fmt.Errorf("%w: team.share.endpoint is not set", config.ErrInvalidTeam)
+	}
+	store, err := rt.Rollups()
+	if errors.Is(err, session.ErrDatabaseLocked) {
+		return fmt.Errorf("%w: watch is running and pushes every %s", err, display.FormatDuration(teamShareInterval(cfg)))
+	}
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	if _, err := ingestRollups(ctx, rt, store); err != nil {
+		return err
+	}
+
+	report, err := shareTeamUsage(ctx, cfg, store, time.Now())
+	if err != nil {
+		return err
+	}
+	c.globalOpts.infof("Shared %d day(s) of usage with %s as %s\n", len(report.Days), cfg.Team.Share.Endpoint, report.Member)
+	return nil
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"github.com/0xmhha/token-monitor/pkg/config"
+	"github.com/0xmhha/token-monitor/pkg/logger"
+	"github.com/0xmhha/token-monitor/pkg/mcp"
+	"github.com/0xmhha/token-monitor/pkg/rollup"
+	"github.com/0xmhha/token-monitor/pkg/teamshare"
+)
+
+// teamShareTimeout bounds one push to the team server.
+const teamShareTimeout = 10 * time.Second
+
+// maxTeamShareReport bounds the report body read by the team server.
+const maxTeamShareReport = 1 << 20
+
+// teamShareInterval returns the time between two pushes by watch.
+func teamShareInterval(cfg *config.Config) time.Duration {
+	if cfg.Team.Share.Interval > 0 {
+		return cfg.Team.Share.Interval
+	}
+	return teamshare.DefaultInterval
+}
+
+// teamSharePath returns the path of the team server's store.
+func teamSharePath(cfg *config.Config) string {
+	return filepath.Join(expandHome(cfg.Storage.CacheDir), teamshare.StoreFile)
+}
+
+// shareTeamUsage pushes the daily totals of the last teamshare.ReportDays
+// days in store to the team server of team.share, and returns the report
+// sent.
+func shareTeamUsage(ctx context.Context, cfg *config.Config, store rollup.Store, now time.Time) (teamshare.Report, error) {
+	share := cfg.Team.Share
+	token, err := config.ResolveSecret(share.Token)
+	if err != nil {
+		return teamshare.Report{}, fmt.Errorf("%w: share.token: %w", config.ErrInvalidTeam, err)
+	}
+
+	// Rollup dates are UTC days; today's may be ahead of the local date.
+	from := rollup.Date(now.AddDate(0, 0, 1-teamshare.ReportDays))
+	rows, err := store.Rows(from, rollup.Date(now))
+	if err != nil {
+		return teamshare.Report{}, fmt.Errorf("failed to read rollups: %w", err)
+	}
+	report := teamshare.Build(share.Member, rows, version, now)
+
+	ctx, cancel := context.WithTimeout(ctx, teamShareTimeout)
+	defer cancel()
+	if err := teamshare.Send(ctx, http.DefaultClient, share.Endpoint, token, report); err != nil {
+		return report, err
+	}
+	return report, nil
+}
+
+// teamShareHandler serves POST /team/usage for serve -http, merging the
+// reports pushed by team members into store. A report is bound to the
+// name of the token it was pushed with, so members cannot overwrite each
+// other's days; without serve.tokens every report is refused.
+func teamShareHandler(store *teamshare.Store, log logger.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		caller := mcp.TokenName(r.Context())
+		if caller == "" {
+			http.Error(w, "team sharing needs serve.tokens", http.StatusForbidden)
+			return
+		}
+		data, err := io.ReadAll(io.LimitReader(r.Body, maxTeamShareReport))
+		if err != nil {
+			http.Error(w, "failed to read body", http.StatusBadRequest)
+			return
+		}
+		var report teamshare.Report
+		if err := json.Unmarshal(data, &report); err != nil {
+			http.Error(w, fmt.Sprintf("%v: %v", teamshare.ErrInvalidReport, err), http.StatusBadRequest)
+			return
+		}
+		if report.Member == "" {
+			report.Member = caller
+		}
+		if report.Member != caller {
+			log.Warn("team usage refused", "member", report.Member, "token", caller)
+			http.Error(w, fmt.Sprintf("token %s may not report for member %s", caller, report.Member), http.StatusForbidden)
+			return
+		}
+
+		err = store.Merge(report, time.Now())
+		switch {
+		case errors.Is(err, teamshare.ErrInvalidReport):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		case err != nil:
+			log.Warn("failed to store team usage", "member", report.Member, "error", err)
+			http.Error(w, "failed to store report", http.StatusInternalServerError)
+			return
+		}
+
+		log.Debug("team usage received", "member", report.Member, "days", len(report.Days))
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0xmhha/token-monitor/pkg/config"
+	"github.com/0xmhha/token-monitor/pkg/logger"
+	"github.com/0xmhha/token-monitor/pkg/mcp"
+	"github.com/0xmhha/token-monitor/pkg/rollup"
+	"github.com/0xmhha/token-monitor/pkg/teamshare"
+)
+
+func TestTeamShareHandler(t *testing.T) {
+	store, err := teamshare.Open(filepath.Join(t.TempDir(), teamshare.StoreFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := teamShareHandler(store, logger.New(logger.Config{Level: "error", Writer: io.Discard}))
+	h := mcp.Authenticate([]mcp.Token{
+		{Name: "alice", Secret: "alice-token", Scopes: mcp.Scopes{mcp.ScopeIngest: true}},
+		{Name: "bob", Secret: "bob-token", Scopes: mcp.Scopes{mcp.ScopeIngest: true}},
+	}, handler)
+
+	postAs := func(token, body string) int {
+		req := httptest.NewRequest(http.MethodPost, teamshare.Path, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	post := func(body string) int { return postAs("bob-token", body) }
+
+	if code := postAs("alice-token", `{"member":"alice","days":[{"date":"2025-11-01","sessions":2,"entries":5,"input_tokens":100}]}`); code != http.StatusNoContent {
+		t.Fatalf("POST report = %d, want 204", code)
+	}
+	if usage := store.Usage(""); len(usage) != 1 || usage[0].Member != "alice" || usage[0].InputTokens != 100 {
+		t.Errorf("Usage() = %+v, want alice with 100 tokens", usage)
+	}
+
+	// A member cannot overwrite another member's days.
+	if code := post(`{"member":"alice","days":[{"date":"2025-11-01","input_tokens":1}]}`); code != http.StatusForbidden {
+		t.Errorf("POST alice's report with bob's token = %d, want 403", code)
+	}
+	if usage := store.Usage(""); len(usage) != 1 || usage[0].InputTokens != 100 {
+		t.Errorf("Usage() = %+v after a refused report, want alice's 100 tokens", usage)
+	}
+
+	// Without serve.tokens, nobody can report.
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, teamshare.Path, strings.NewReader(`{"member":"alice","days":[]}`)))
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("POST without a token = %d, want 403", rec.Code)
+	}
+
+	// The member defaults to the token's name.
+	if code := post(`{"days":[{"date":"2025-11-01","input_tokens":50}]}`); code != http.StatusNoContent {
+		t.Errorf("POST without a member = %d, want 204", code)
+	}
+	if usage := store.Usage(""); len(usage) != 2 {
+		t.Errorf("Usage() = %+v, want alice and bob", usage)
+	}
+
+	for _, body := range []string{`not json`, `{"member":"bob","days":[{"date":"yesterday"}]}`} {
+		if code := post(body); code != http.StatusBadRequest {
+			t.Errorf("POST %s = %d, want 400", body, code)
+		}
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, teamshare.Path, nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("GET = %d, want 405", rec.Code)
+	}
+}
+
+func TestShareTeamUsage_LocalZone(t *testing.T) {
+	var report teamshare.Report
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&report); err != nil {
+			t.Errorf("decode report: %v", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	pst := time.FixedZone("PST", -8*3600)
+	// 20:00 on November 2 in Los Angeles is already the rollup day
+	// 2025-11-03.
+	now := time.Date(2025, 11, 2, 20, 0, 0, 0, pst)
+	store := todayStore{rows: []rollup.Row{
+		{Key: rollup.Key{Date: "2025-11-02", SessionID: "s1"}, Totals: rollup.Totals{Entries: 1, InputTokens: 100}},
+		{Key: rollup.Key{Date: "2025-11-03", SessionID: "s1"}, Totals: rollup.Totals{Entries: 1, InputTokens: 200}},
+	}}
+	cfg := config.Default()
+	cfg.Team.Share = config.TeamShareConfig{Endpoint: srv.URL, Member: "alice"}
+
+	if _, err := shareTeamUsage(context.Background(), cfg, store, now); err != nil {
+		t.Fatalf("shareTeamUsage() error = %v", err)
+	}
+	if len(report.Days) != 2 || report.Days[1].Date != "2025-11-03" {
+		t.Errorf("report days = %+v, want 2025-11-02 and 2025-11-03", report.Days)
+	}
+}
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/0xmhha/token-monitor/pkg/config"
+	"github.com/0xmhha/token-monitor/pkg/telemetry"
+)
+
+// telemetrySendTimeout bounds the report upload at the end of a command.
+const telemetrySendTimeout = 3 * time.Second
+
+// untimedCommands are not timed: they run until interrupted or only
+// print help, so their durations say nothing about performance.
+var untimedCommands = map[string]bool{
+	"tui":           true,
+	"watch":         true,
+	"serve":         true,
+	"repl":          true,
+	"hook-receiver": true,
+	"telemetry":     true,
+	"help":          true,
+}
+
+// recordTelemetry records how long command took since start when
+// telemetry is enabled, and sends the report when it is due. Failures
+// are ignored: telemetry never affects the command's result.
+func (g globalOptions) recordTelemetry(command string, start time.Time) {
+	elapsed := time.Since(start)
+	if !builtinCommands[command] || untimedCommands[command] || telemetry.DoNotTrack() {
+		return
+	}
+	cfg, err := config.NewLoader(g.configPath).Load()
+	if err != nil || !cfg.Telemetry.Enabled {
+		return
+	}
+
+	path := filepath.Join(cfg.Storage.CacheDir, telemetry.StateFile)
+	state, err := telemetry.LoadState(path)
+	if err != nil {
+		state = telemetry.State{}
+	}
+	state.Record(command, elapsed)
+
+	now := time.Now()
+	if cfg.Telemetry.Endpoint != "" && state.Due(now) {
+		ctx, cancel := context.WithTimeout(context.Background(), telemetrySendTimeout)
+		client := &http.Client{Timeout: telemetrySendTimeout}
+		if telemetry.Send(ctx, client, cfg.Telemetry.Endpoint, state.Report(version)) == nil {
+			state.MarkSent(now)
+		}
+		cancel()
+	}
+	_ = state.Save(path) //nolint:errcheck // best effort
+}
+
+// telemetryCommand manages the opt-in telemetry.
+type telemetryCommand struct {
+	globalOpts globalOptions
+}
+
+// runTelemetryCommand runs the telemetry command.
+func runTelemetryCommand(globalOpts globalOptions, args []string) error {
+	cmd := &telemetryCommand{globalOpts: globalOpts}
+	return cmd.Execute(args)
+}
+
+// Execute dispatches a telemetry subcommand.
+func (c *telemetryCommand) Execute(args []string) error {
+	if len(args) == 0 {
+		return c.runStatus()
+	}
+
+	switch args[0] {
+	case "status":
+		return c.runStatus()
+	case "enable":
+		return c.runEnable(args[1:])
+	case "disable":
+		return c.runDisable()
+	case "help":
+		return c.showHelp()
+	default:
+		return fmt.Errorf("unknown telemetry subcommand: %s", args[0])
+	}
+}
+
+// telemetryStatus is the JSON output of telemetry status.
+type telemetryStatus struct {
+	Enabled    bool             `json:"enabled"`
+	DoNotTrack bool             `json:"do_not_track"`
+	Endpoint   string           `json:"endpoint,omitempty"`
+	LastSent   *time.Time       `json:"last_sent,omitempty"`
+	Pending    telemetry.Report `json:"pending"`
+}
+
+// runStatus shows whether telemetry is on and the exact report that
+// would be sent next.
+func (c *telemetryCommand) runStatus() error {
+	cfg, err := config.NewLoader(c.globalOpts.configPath).Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	state, err := telemetry.LoadState(filepath.Join(cfg.Storage.CacheDir, telemetry.StateFile))
+	if err != nil {
+		return err
+	}
+
+	status := telemetryStatus{
+		Enabled:    cfg.Telemetry.Enabled,
+		DoNotTrack: telemetry.DoNotTrack(),
+		Endpoint:   cfg.Telemetry.Endpoint,
+		Pending:    state.Report(version),
+	}
+	if !state.LastSent.IsZero() {
+		status.LastSent = &state.LastSent
+	}
+	if c.globalOpts.jsonOutput {
+		return printJSON(status)
+	}
+
+	switch {
+	case status.DoNotTrack:
+		fmt.Println("Telemetry: disabled (DO_NOT_TRACK is set)")
+	case status.Enabled:
+		fmt.Println("Telemetry: enabled")
+	default:
+		fmt.Println("Telemetry: disabled")
+	}
+	if status.Endpoint != "" {
+		fmt.Printf("Endpoint:  %s\n", status.Endpoint)
+	} else {
+		fmt.Println("Endpoint:  none (timings stay on this machine)")
+	}
+	if status.LastSent != nil {
+		fmt.Printf("Last sent: %s\n", status.LastSent.Local().Format(time.DateTime))
+	}
+	fmt.Println()
+	fmt.Println("Next report (version, platform, and command timings only):")
+	return printJSON(status.Pending)
+}
+
+// runEnable turns telemetry on, optionally setting the endpoint.
+func (c *telemetryCommand) runEnable(args []string) error {
+	fs := flag.NewFlagSet("telemetry enable", flag.ExitOnError)
+	endpoint := fs.String("endpoint", "", "URL that receives the daily report (default: keep timings local)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	_, path, err := c.update(func(cfg *config.Config) {
+		cfg.Telemetry.Enabled = true
+		if *endpoint != "" {
+			cfg.Telemetry.Endpoint = *endpoint
+		}
+	})
+	if err != nil {
+		return err
+	}
+	c.globalOpts.infof("Telemetry enabled in %s\n", path)
+	c.globalOpts.infof("Only the version, platform, and command timings are recorded; see 'token-monitor telemetry status'.\n")
+	return nil
+}
+
+// runDisable turns telemetry off and deletes the recorded timings.
+func (c *telemetryCommand) runDisable() error {
+	cfg, path, err := c.update(func(cfg *config.Config) {
+		cfg.Telemetry.Enabled = false
+	})
+	if err != nil {
+		return err
+	}
+	statePath := filepath.Join(cfg.Storage.CacheDir, telemetry.StateFile)
+	if err := os.Remove(statePath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete telemetry state: %w", err)
+	}
+	c.globalOpts.infof("Telemetry disabled in %s; recorded timings deleted\n", path)
+	return nil
+}
+
+// update applies change to the configuration and saves it to the -config
+// file, the active file, or the default location, in that order. It
+// returns the updated configuration and the file written.
+func (c *telemetryCommand) update(change func(cfg *config.Config)) (*config.Config, string, error) {
+	cfg, err := config.NewLoader(c.globalOpts.configPath).Load()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to load config: %w", err)
+	}
+	change(cfg)
+
+	path := c.globalOpts.configPath
+	if path == "" {
+		path = (&configCommand{globalOpts: c.globalOpts}).getConfigSource()
+		if path == "defaults (no config file found)" {
+			path = filepath.Join(os.Getenv("HOME"), ".config", "token-monitor", "config.yaml")
+		}
+	}
+	if err := config.Save(cfg, path); err != nil {
+		return nil, "", fmt.Errorf("failed to save config: %w", err)
+	}
+	return cfg, path, nil
+}
+
+// showHelp displays help for the telemetry command.
+func (c *telemetryCommand) showHelp() error {
+	help := `Telemetry - Opt-in anonymous performance report
+
+Usage:
+  token-monitor telemetry [status]
+  token-monitor telemetry enable [-endpoint URL]
+  token-monitor telemetry disable
+
+Subcommands:
+  status        Show whether telemetry is on and the exact next report
+  enable        Record command timings (-endpoint: URL for the daily report)
+  disable       Stop recording and delete the recorded timings
+
+Telemetry is off by default. When enabled, it records only the tool
+version, platform, Go version, and per-command run counts and times. It
+never records token usage, costs, session names, paths, or prompts.
+Without an endpoint the timings stay on this machine. Setting
+DO_NOT_TRACK=1 disables telemetry regardless of the configuration.
+`
+	fmt.Print(help)
+	return nil
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/0xmhha/token-monitor/pkg/display"
+	"github.com/0xmhha/token-monitor/pkg/session"
+	"github.com/0xmhha/token-monitor/pkg/ticket"
+)
+
+// defaultTicketDays is the period covered by the tickets command.
+const defaultTicketDays = 30
+
+// noTicketLabel is shown for usage without a ticket.
+const noTicketLabel = "(none)"
+
+// runLabel attaches a ticket to a session, or removes it with -clear.
+func (c *sessionCommand) runLabel(args []string) error {
+	fs := flag.NewFlagSet("session label", flag.ExitOnError)
+	clearTicket := fs.Bool("clear", false, "remove the session's ticket")
+	if err := c.globalOpts.applyFlagDefaults(fs); err != nil {
+		return err
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if (*clearTicket && fs.NArg() != 1) || (!*clearTicket && fs.NArg() != 2) {
+		return fmt.Errorf("usage: token-monitor session label <name|uuid> <ticket> | -clear <name|uuid>")
+	}
+	if !*clearTicket {
+		if err := ticket.Validate(fs.Arg(1)); err != nil {
+			return fmt.Errorf("%w: %q", err, fs.Arg(1))
+		}
+	}
+
+	mgr, err := c.writableSessionManager()
+	if err != nil {
+		return err
+	}
+	uuid, err := c.resolveSessionID(mgr, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	store, err := c.rt.Tickets()
+	if err != nil {
+		return err
+	}
+
+	if *clearTicket {
+		if err := store.Clear(uuid); err != nil {
+			return err
+		}
+		c.globalOpts.infof("Removed ticket from session '%s'\n", uuid[:8])
+		return nil
+	}
+	if err := store.Set(uuid, fs.Arg(1)); err != nil {
+		return err
+	}
+	c.globalOpts.infof("Labeled session '%s' with ticket %s\n", uuid[:8], fs.Arg(1))
+	return nil
+}
+
+// resolveSessionID returns the UUID of the session identified by a name,
+// a UUID, or a UUID prefix of a discovered session.
+func (c *sessionCommand) resolveSessionID(mgr session.Manager, identifier string) (string, error) {
+	if metadata := c.findSessionMetadata(mgr, identifier); metadata != nil {
+		return metadata.UUID, nil
+	}
+
+	sessions, err := c.discoverSessions()
+	if err != nil {
+		return "", err
+	}
+	// A short prefix could match an unrelated session.
+	if len(identifier) >= 8 {
+		if f := c.findSessionFile(sessions, identifier, nil); f != nil {
+			return f.SessionID, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s", session.ErrSessionNotFound, identifier)
+}
+
+// ticketsCommand reports spend per ticket.
+type ticketsCommand struct {
+	days       int
+	format     string
+	globalOpts globalOptions
+}
+
+// runTicketsCommand runs the tickets command.
+func runTicketsCommand(globalOpts globalOptions, args []string) error {
+	fs := flag.NewFlagSet("tickets", flag.ExitOnError)
+	days := fs.Int("days", defaultTicketDays, "report the last N days")
+	format := fs.String("format", "table", "output format (table, csv, json)")
+
+	if err := globalOpts.applyFlagDefaults(fs); err != nil {
+		return err
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if globalOpts.jsonOutput {
+		*format = "json"
+	}
+	if *days <= 0 {
+		return fmt.Errorf("invalid -days: %d", *days)
+	}
+	switch *format {
+	case "table", "csv", "json":
+	default:
+		return fmt.Errorf("invalid -format %q (want table, csv, or json)", *format)
+	}
+
+	cmd := &ticketsCommand{days: *days, format: *format, globalOpts: globalOpts}
+	return cmd.Execute()
+}
+
+// Execute attributes the period's usage to tickets and prints it.
+func (c *ticketsCommand) Execute() error {
+	rt := c.globalOpts.newRuntime("")
+	defer func() {
+		_ = rt.Close() //nolint:errcheck // best effort cleanup
+	}()
+
+	tickets, err := rt.Tickets()
+	if err != nil {
+		return err
+	}
+	sessions, err := tickets.Sessions()
+	if err != nil {
+		return err
+	}
+	windowStore, err := rt.Focus()
+	if err != nil {
+		return err
+	}
+	since := time.Now().AddDate(0, 0, -c.days)
+	windows, err := windowStore.List(since, time.Time{})
+	if err != nil {
+		return err
+	}
+	entries, err := focusEntries(rt, since)
+	if err != nil {
+		return err
+	}
+
+	usage := ticket.Attribute(entries, sessions, windows)
+	switch c.format {
+	case "json":
+		return printJSON(usage)
+	case "csv":
+		return writeTicketsCSV(os.Stdout, usage)
+	}
+
+	if len(usage) == 0 {
+		c.globalOpts.infof("No usage in the last %d days\n", c.days)
+		return nil
+	}
+	header := []string{"TICKET", "SESSIONS", "REQUESTS", "TOKENS", "COST"}
+	rows := make([][]string, 0, len(usage))
+	for _, u := range usage {
+		label := u.Ticket
+		if label == "" {
+			label = noTicketLabel
+		}
+		rows = append(rows, []string{
+			label,
+			display.FormatNumber(u.Sessions),
+			display.FormatNumber(u.Totals.Entries),
+			display.FormatNumber(u.Totals.TotalTokens()),
+			display.FormatCost(u.Totals.CostUSD),
+		})
+	}
+	return display.WriteTable(os.Stdout, header, rows, false)
+}
+
+// writeTicketsCSV writes the usage per ticket as CSV with raw numbers,
+// for import into engineering-metrics tools.
+func writeTicketsCSV(w *os.File, usage []ticket.Usage) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{
+		"ticket", "sessions", "requests", "input_tokens", "output_tokens",
+		"cache_creation_tokens", "cache_read_tokens", "total_tokens", "cost_usd",
+	}); err != nil {
+		return err
+	}
+	for _, u := range usage {
+		t := u.Totals
+		if err := writer.Write([]string{
+			u.Ticket,
+			strconv.Itoa(u.Sessions),
+			strconv.Itoa(t.Entries),
+			strconv.Itoa(t.InputTokens),
+			strconv.Itoa(t.OutputTokens),
+			strconv.Itoa(t.CacheCreationTokens),
+			strconv.Itoa(t.CacheReadTokens),
+			strconv.Itoa(t.TotalTokens()),
+			strconv.FormatFloat(t.CostUSD, 'f', 4, 64),
+		}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,420 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/0xmhha/token-monitor/cmd/internal/runtime"
+	"github.com/0xmhha/token-monitor/pkg/aggregator"
+	"github.com/0xmhha/token-monitor/pkg/analysis"
+	"github.com/0xmhha/token-monitor/pkg/discovery"
+	"github.com/0xmhha/token-monitor/pkg/display"
+	"github.com/0xmhha/token-monitor/pkg/i18n"
+	"github.com/0xmhha/token-monitor/pkg/parser"
+	"github.com/0xmhha/token-monitor/pkg/rollup"
+	"github.com/0xmhha/token-monitor/pkg/sessionloader"
+)
+
+// todayAverageDays is how many days before today the daily average covers.
+const todayAverageDays = 30
+
+// blockLength is the length of a billing block; sessions last written
+// earlier cannot have entries in the current one.
+const blockLength = 5 * time.Hour
+
+// todayCommand prints a summary card of today's usage.
+type todayCommand struct {
+	format     string
+	compact    bool // one line instead of the card
+	globalOpts globalOptions
+}
+
+// todaySummary is today's usage as printed by the today command.
+type todaySummary struct {
+	Date   string        `json:"date"`
+	Totals rollup.Totals `json:"totals"`
+
+	// Sessions is the number of sessions with entries today.
+	Sessions int `json:"sessions"`
+
+	Block todayBlock `json:"block"`
+
+	// Average is the daily average over the active days among the
+	// todayAverageDays before today, or nil without rollup history.
+	Average *todayAverage `json:"average,omitempty"`
+
+	// Context estimates the context window use of the session active
+	// within session.idle_after, or is nil if none is.
+	Context *analysis.ContextUsage `json:"context,omitempty"`
+}
+
+// todayBlock is the current billing block.
+type todayBlock struct {
+	StartTime       time.Time `json:"start_time"`
+	EndTime         time.Time `json:"end_time"`
+	TotalTokens     int       `json:"total_tokens"`
+	Entries         int       `json:"entries"`
+	TokensPerMinute float64   `json:"tokens_per_minute"`
+
+	// RateLimits counts requests turned away by a rate limit or an
+	// overloaded API in the block.
+	RateLimits int `json:"rate_limits,omitempty"`
+}
+
+// todayAverage is the daily average today is compared with.
+type todayAverage struct {
+	ActiveDays  int     `json:"active_days"`
+	TotalTokens float64 `json:"total_tokens"`
+	CostUSD     float64 `json:"cost_usd"`
+}
+
+// runTodayCommand parses flags and runs the today command.
+func runTodayCommand(globalOpts globalOptions, args []string) error {
+	fs := flag.NewFlagSet("today", flag.ExitOnError)
+	format := fs.String("format", "card", "output format (card, json)")
+	compact := fs.Bool("compact", false, "print the summary as one line instead of a card")
+
+	if err := globalOpts.applyFlagDefaults(fs); err != nil {
+		return err
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	outputFormat := *format
+	if globalOpts.jsonOutput {
+		outputFormat = "json"
+	}
+	if outputFormat != "card" && outputFormat != "json" {
+		return fmt.Errorf("invalid -format %q (want card or json)", outputFormat)
+	}
+
+	cmd := &todayCommand{
+		format:     outputFormat,
+		compact:    *compact,
+		globalOpts: globalOpts,
+	}
+	return cmd.Execute()
+}
+
+// Execute reads today's entries and prints the summary.
+//
+// Only session files written since midnight or the start of the current
+// block are read, so the command stays fast with a long history. The
+// daily average comes from the rollups; when they are unavailable, e.g.
+// while watch holds the database, the card is printed without it.
+func (c *todayCommand) Execute() error {
+	rt := c.globalOpts.newRuntime("")
+	defer func() {
+		_ = rt.Close() //nolint:errcheck // best effort cleanup
+	}()
+
+	cfg, err := rt.Config()
+	if err != nil {
+		return err
+	}
+	log, err := rt.Logger()
+	if err != nil {
+		return err
+	}
+	disc, err := rt.Discoverer()
+	if err != nil {
+		return err
+	}
+	sessions, err := disc.Discover()
+	if err != nil {
+		return fmt.Errorf("failed to discover sessions: %w", err)
+	}
+
+	// Today is the rollup day (UTC), so that it covers the same hours as
+	// the days of the average.
+	now := time.Now()
+	midnight := rollup.DayStart(now)
+	since := midnight
+	if blockStart := now.Add(-blockLength); blockStart.Before(since) {
+		since = blockStart
+	}
+
+	ctx := context.Background()
+	entries, err := sessionloader.LoadEntries(ctx, recentSessions(sessions, since), rt.NewReader, log)
+	if err != nil {
+		return err
+	}
+	summary := summarizeToday(entries, midnight)
+	summary.Context = recentContext(latestMainEntry(entries), now, cfg.Session.IdleAfter, cfg.Monitoring.ContextWindow)
+
+	if average, err := c.average(ctx, rt, midnight); err != nil {
+		log.Debug("rollups unavailable, skipping daily average", "error", err)
+	} else {
+		summary.Average = average
+	}
+
+	if c.format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(summary)
+	}
+
+	out := c.globalOpts.output()
+	if c.compact {
+		line := todayCompactLine(summary, now)
+		if c.globalOpts.ascii {
+			line = display.ASCII(line)
+		}
+		out.Println(line)
+		return nil
+	}
+	if c.globalOpts.accessible {
+		for _, line := range todayLines(summary, now, newTodayStyles(false)) {
+			out.Printf("%s: %s\n", line[0], line[1])
+		}
+		return nil
+	}
+	color := cfg.Display.ColorEnabled && !c.globalOpts.noColor
+	out.Println(renderToday(summary, now, newTodayStyles(color), c.globalOpts.ascii))
+	return nil
+}
+
+// average catches the rollups up and returns the daily average over the
+// todayAverageDays before midnight, the start of today's rollup day. A
+// temporary database has no history, so it is not used.
+func (c *todayCommand) average(ctx context.Context, rt *runtime.Runtime, midnight time.Time) (*todayAverage, error) {
+	if err := rt.Persistent(); err != nil {
+		return nil, err
+	}
+	store, err := rt.Rollups()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := ingestRollups(ctx, rt, store); err != nil {
+		return nil, err
+	}
+
+	rows, err := store.Rows(averageRange(midnight))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rollups: %w", err)
+	}
+	return dailyAverage(rows), nil
+}
+
+// averageRange returns the rollup dates of the todayAverageDays before the
+// rollup day starting at midnight.
+func averageRange(midnight time.Time) (from, to string) {
+	return rollup.Date(midnight.AddDate(0, 0, -todayAverageDays)), rollup.Date(midnight.AddDate(0, 0, -1))
+}
+
+// recentSessions returns the session files modified at or after since.
+// Files are only appended to, so older files have no entries since then.
+func recentSessions(sessions []discovery.SessionFile, since time.Time) []discovery.SessionFile {
+	recent := make([]discovery.SessionFile, 0, len(sessions))
+	for _, s := range sessions {
+		if s.ModTime >= since.Unix() {
+			recent = append(recent, s)
+		}
+	}
+	return recent
+}
+
+// summarizeToday totals the entries at or after midnight and computes the
+// current billing block from all entries.
+func summarizeToday(entries []parser.UsageEntry, midnight time.Time) todaySummary {
+	summary := todaySummary{Date: rollup.Date(midnight)}
+
+	sessions := make(map[string]bool)
+	for _, entry := range aggregator.FilterSince(entries, midnight) {
+		summary.Totals.AddEntry(entry)
+		if entry.SessionID != "" {
+			sessions[entry.SessionID] = true
+		}
+	}
+	summary.Sessions = len(sessions)
+
+	agg := aggregator.New(aggregator.Config{})
+	for _, entry := range entries {
+		agg.Add(entry)
+	}
+	block := agg.CurrentBillingBlock("")
+	summary.Block = todayBlock{
+		StartTime:       block.StartTime,
+		EndTime:         block.EndTime,
+		TotalTokens:     block.TotalTokens,
+		Entries:         block.EntryCount,
+		TokensPerMinute: agg.BurnRate("", 5*time.Minute).TokensPerMinute,
+		RateLimits:      block.RateLimits,
+	}
+	return summary
+}
+
+// latestMainEntry returns the newest entry not written by a sub-agent,
+// whose context is the conversation's own.
+func latestMainEntry(entries []parser.UsageEntry) parser.UsageEntry {
+	var latest parser.UsageEntry
+	for _, entry := range entries {
+		if !entry.IsSubAgent() && !entry.Timestamp.Before(latest.Timestamp) {
+			latest = entry
+		}
+	}
+	return latest
+}
+
+// dailyAverage averages rows over the days with usage, or returns nil if
+// there are none. Idle days are left out so that a week off does not make
+// every working day look busy.
+func dailyAverage(rows []rollup.Row) *todayAverage {
+	var avg todayAverage
+	for _, row := range rollup.Summarize(rows, []rollup.Dimension{rollup.DimDate}) {
+		if row.Entries == 0 {
+			continue
+		}
+		avg.ActiveDays++
+		avg.TotalTokens += float64(row.TotalTokens())
+		avg.CostUSD += row.CostUSD
+	}
+	if avg.ActiveDays == 0 {
+		return nil
+	}
+	avg.TotalTokens /= float64(avg.ActiveDays)
+	avg.CostUSD /= float64(avg.ActiveDays)
+	return &avg
+}
+
+// todayStyles colors the parts of the today card.
+type todayStyles struct {
+	title, label, value, muted, above, below, border lipgloss.Style
+}
+
+// newTodayStyles returns the card styles, or plain ones without color.
+func newTodayStyles(color bool) todayStyles {
+	plain := lipgloss.NewStyle()
+	if !color {
+		return todayStyles{plain, plain, plain, plain, plain, plain, plain}
+	}
+	return todayStyles{
+		title:  plain.Bold(true).Foreground(lipgloss.Color("12")),
+		label:  plain.Foreground(lipgloss.Color("8")),
+		value:  plain.Bold(true),
+		muted:  plain.Foreground(lipgloss.Color("8")),
+		above:  plain.Foreground(lipgloss.Color("11")),
+		below:  plain.Foreground(lipgloss.Color("10")),
+		border: plain.BorderForeground(lipgloss.Color("8")),
+	}
+}
+
+// todayLines returns the card rows as label and value pairs, the first
+// being the title and date.
+func todayLines(s todaySummary, now time.Time, st todayStyles) [][2]string {
+	t := s.Totals
+	day := rollup.DayStart(now)
+	lines := [][2]string{
+		{i18n.T("today.title"), rollup.Date(day) + " " +
+			i18n.T("weekday."+strings.ToLower(day.Weekday().String()))},
+		{i18n.T("today.tokens"), st.value.Render(display.FormatCompact(t.TotalTokens())) + "  " +
+			st.muted.Render(i18n.Tf("today.breakdown", display.FormatCompact(t.InputTokens),
+				display.FormatCompact(t.OutputTokens), display.FormatCompact(t.CacheCreationTokens+t.CacheReadTokens)))},
+		{i18n.T("today.cost"), st.value.Render(display.FormatCost(t.CostUSD))},
+		{i18n.T("today.sessions"), st.value.Render(fmt.Sprint(s.Sessions))},
+	}
+
+	lines = append(lines, [2]string{i18n.T("today.block"), todayBlockText(s, now, st)})
+
+	average := st.muted.Render(i18n.T("today.no_average"))
+	if a := s.Average; a != nil {
+		delta := st.below
+		if float64(t.TotalTokens()) > a.TotalTokens {
+			delta = st.above
+		}
+		average = delta.Render(percentChange(float64(t.TotalTokens()), a.TotalTokens)) + " " +
+			st.muted.Render(i18n.Tf("today.average_detail", display.FormatCompact(int(a.TotalTokens)),
+				display.FormatCost(a.CostUSD), a.ActiveDays))
+	}
+	lines = append(lines, [2]string{i18n.T("today.average"), average})
+
+	if u := s.Context; u != nil {
+		context := st.value.Render(todayContextText(*u))
+		if u.Warn() {
+			context += " " + st.above.Render(i18n.T("today.context_warn"))
+		}
+		lines = append(lines, [2]string{i18n.T("today.context"), context})
+	}
+	return lines
+}
+
+// todayContextText formats the context estimate, e.g. "~62% full (124.0K
+// of 200.0K tokens)".
+func todayContextText(u analysis.ContextUsage) string {
+	return i18n.Tf("today.context_value", u.Percent(),
+		display.FormatCompact(u.Tokens), display.FormatCompact(u.Window))
+}
+
+// todayBlockText describes the current billing block.
+func todayBlockText(s todaySummary, now time.Time, st todayStyles) string {
+	remaining := max(0, s.Block.EndTime.Sub(now))
+	if s.Block.Entries == 0 {
+		return st.muted.Render(i18n.Tf("today.block_idle", display.FormatDuration(remaining)))
+	}
+	text := i18n.Tf("today.block_active", st.value.Render(display.FormatCompact(s.Block.TotalTokens)),
+		display.FormatDuration(remaining), display.FormatCompact(int(s.Block.TokensPerMinute)))
+	if s.Block.RateLimits > 0 {
+		text += st.above.Render(i18n.Tf("today.rate_limits", s.Block.RateLimits))
+	}
+	return text
+}
+
+// todayCompactLine renders the summary as one line for today -compact,
+// leaving out the token breakdown and the average's detail.
+func todayCompactLine(s todaySummary, now time.Time) string {
+	t := s.Totals
+	parts := []string{
+		i18n.T("today.title") + " " + rollup.Date(now),
+		i18n.T("today.tokens") + " " + display.FormatCompact(t.TotalTokens()),
+		i18n.T("today.cost") + " " + display.FormatCost(t.CostUSD),
+		i18n.T("today.sessions") + " " + fmt.Sprint(s.Sessions),
+		i18n.T("today.block") + " " + todayBlockText(s, now, newTodayStyles(false)),
+	}
+	if a := s.Average; a != nil {
+		parts = append(parts, i18n.T("today.average")+" "+percentChange(float64(t.TotalTokens()), a.TotalTokens))
+	}
+	if u := s.Context; u != nil {
+		parts = append(parts, i18n.T("today.context")+" "+todayContextText(*u))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// percentChange formats the change from base to value, e.g. "+35%".
+func percentChange(value, base float64) string {
+	if base == 0 {
+		return "+0%"
+	}
+	return fmt.Sprintf("%+.0f%%", (value-base)/base*100)
+}
+
+// renderToday draws the summary as a bordered card.
+func renderToday(s todaySummary, now time.Time, st todayStyles, ascii bool) string {
+	lines := todayLines(s, now, st)
+
+	labelWidth := 0
+	for _, line := range lines[1:] {
+		labelWidth = max(labelWidth, display.TextWidth(line[0]))
+	}
+
+	rows := make([]string, 0, len(lines))
+	rows = append(rows, st.title.Render(lines[0][0])+" "+st.muted.Render("· "+lines[0][1]))
+	for _, line := range lines[1:] {
+		rows = append(rows, st.label.Render(display.PadRight(line[0], labelWidth))+"  "+line[1])
+	}
+	body := strings.Join(rows, "\n")
+
+	border := lipgloss.RoundedBorder()
+	if ascii {
+		body = display.ASCII(body)
+		border = lipgloss.ASCIIBorder()
+	}
+	return st.border.Border(border).Padding(0, 1).Render(body)
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0xmhha/token-monitor/pkg/analysis"
+	"github.com/0xmhha/token-monitor/pkg/discovery"
+	"github.com/0xmhha/token-monitor/pkg/parser"
+	"github.com/0xmhha/token-monitor/pkg/rollup"
+)
+
+func TestSummarizeToday(t *testing.T) {
+	now := time.Now()
+	midnight := rollup.DayStart(now)
+	entries := []parser.UsageEntry{
+		{
+			SessionID: "a",
+			Timestamp: midnight.Add(-time.Hour), // yesterday
+			Message:   parser.Message{Model: "claude-sonnet-4", Usage: parser.Usage{InputTokens: 9000}},
+		},
+		{
+			SessionID: "a",
+			Timestamp: now,
+			Message: parser.Message{Model: "claude-sonnet-4", Usage: parser.Usage{
+				InputTokens: 1000, OutputTokens: 200, CacheReadInputTokens: 300,
+			}},
+		},
+		{
+			SessionID: "b",
+			Timestamp: now,
+			Message:   parser.Message{Model: "claude-opus-4", Usage: parser.Usage{InputTokens: 500}},
+		},
+	}
+
+	summary := summarizeToday(entries, midnight)
+	if got := summary.Totals.TotalTokens(); got != 2000 {
+		t.Errorf("TotalTokens = %d, want 2000", got)
+	}
+	if summary.Sessions != 2 {
+		t.Errorf("Sessions = %d, want 2", summary.Sessions)
+	}
+	if summary.Block.Entries < 2 || !summary.Block.EndTime.After(now) {
+		t.Errorf("Block = %+v, want the current block with today's entries", summary.Block)
+	}
+}
+
+func TestTodayLocalZone(t *testing.T) {
+	kst := time.FixedZone("KST", 9*3600)
+	// 08:00 on November 3 in Seoul is 23:00 on November 2 in UTC: today is
+	// the rollup day 2025-11-02, like the days of the average.
+	now := time.Date(2025, 11, 3, 8, 0, 0, 0, kst)
+	midnight := rollup.DayStart(now)
+
+	entry := func(at time.Time) parser.UsageEntry {
+		return parser.UsageEntry{Timestamp: at, Message: parser.Message{Usage: parser.Usage{InputTokens: 100}}}
+	}
+	summary := summarizeToday([]parser.UsageEntry{
+		entry(time.Date(2025, 11, 2, 8, 30, 0, 0, kst)), // 2025-11-01 23:30 UTC
+		entry(time.Date(2025, 11, 2, 9, 30, 0, 0, kst)), // 2025-11-02 00:30 UTC
+		entry(time.Date(2025, 11, 3, 7, 0, 0, 0, kst)),
+	}, midnight)
+	if summary.Date != "2025-11-02" || summary.Totals.TotalTokens() != 200 {
+		t.Errorf("summarizeToday() = %s with %d tokens, want 2025-11-02 with 200", summary.Date, summary.Totals.TotalTokens())
+	}
+
+	from, to := averageRange(midnight)
+	if from != "2025-10-03" || to != "2025-11-01" {
+		t.Errorf("averageRange() = %s..%s, want 2025-10-03..2025-11-01", from, to)
+	}
+
+	if got := todayCompactLine(summary, now); !strings.HasPrefix(got, "Today 2025-11-02 ") {
+		t.Errorf("todayCompactLine() = %q, want the rollup day", got)
+	}
+}
+
+func TestRecentSessions(t *testing.T) {
+	since := time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC)
+	sessions := []discovery.SessionFile{
+		{SessionID: "old", ModTime: since.Add(-time.Second).Unix()},
+		{SessionID: "new", ModTime: since.Unix()},
+	}
+	recent := recentSessions(sessions, since)
+	if len(recent) != 1 || recent[0].SessionID != "new" {
+		t.Errorf("recentSessions() = %+v, want only new", recent)
+	}
+}
+
+func TestDailyAverage(t *testing.T) {
+	if avg := dailyAverage(nil); avg != nil {
+		t.Errorf("dailyAverage(nil) = %+v, want nil", avg)
+	}
+
+	rows := []rollup.Row{
+		{Key: rollup.Key{Date: "2025-11-01", Model: "a"}, Totals: rollup.Totals{Entries: 1, InputTokens: 100, CostUSD: 1}},
+		{Key: rollup.Key{Date: "2025-11-01", Model: "b"}, Totals: rollup.Totals{Entries: 1, InputTokens: 100, CostUSD: 1}},
+		{Key: rollup.Key{Date: "2025-11-03"}, Totals: rollup.Totals{Entries: 1, InputTokens: 400, CostUSD: 2}},
+	}
+	avg := dailyAverage(rows)
+	// Idle days (2025-11-02) are not averaged in.
+	if avg == nil || avg.ActiveDays != 2 || avg.TotalTokens != 300 || avg.CostUSD != 2 {
+		t.Errorf("dailyAverage() = %+v, want 2 active days averaging 300 tokens, $2", avg)
+	}
+}
+
+func TestPercentChange(t *testing.T) {
+	tests := []struct {
+		value, base float64
+		want        string
+	}{
+		{135, 100, "+35%"},
+		{50, 100, "-50%"},
+		{100, 100, "+0%"},
+		{100, 0, "+0%"},
+	}
+	for _, tt := range tests {
+		if got := percentChange(tt.value, tt.base); got != tt.want {
+			t.Errorf("percentChange(%v, %v) = %q, want %q", tt.value, tt.base, got, tt.want)
+		}
+	}
+}
+
+func TestRenderToday(t *testing.T) {
+	now := time.Date(2025, 11, 3, 12, 0, 0, 0, time.UTC)
+	summary := todaySummary{
+		Date:     "2025-11-03",
+		Totals:   rollup.Totals{Entries: 2, InputTokens: 1000, OutputTokens: 2000, CacheReadTokens: 132000, CostUSD: 4.21},
+		Sessions: 3,
+		Block:    todayBlock{EndTime: now.Add(2*time.Hour + 14*time.Minute)},
+		Average:  &todayAverage{ActiveDays: 12, TotalTokens: 100000, CostUSD: 3},
+	}
+
+	card := renderToday(summary, now, newTodayStyles(false), true)
+	for _, want := range []string{
+		"+--",
+		"| Today . 2025-11-03 Monday",
+		"| Tokens       135.0K  in 1.0K . out 2.0K . cache 132.0K",
+		"| Cost         $4.21",
+		"| Sessions     3",
+		"| Block        no usage yet . 2h14m left",
+		"| vs. average  +35% (100.0K tokens, $3.00 per day over 12 active day(s))",
+	} {
+		if !strings.Contains(card, want) {
+			t.Errorf("renderToday() missing %q:\n%s", want, card)
+		}
+	}
+
+	summary.Average = nil
+	summary.Block.Entries, summary.Block.TotalTokens, summary.Block.TokensPerMinute = 4, 54600, 1200
+	summary.Context = &analysis.ContextUsage{Tokens: 170000, Window: 200000}
+	card = renderToday(summary, now, newTodayStyles(false), true)
+	for _, want := range []string{
+		"| Block        54.6K tokens . 2h14m left . 1.2K/min",
+		"| vs. average  no daily average yet",
+		"| Context      ~85% full (170.0K of 200.0K tokens) compaction near",
+	} {
+		if !strings.Contains(card, want) {
+			t.Errorf("renderToday() missing %q:\n%s", want, card)
+		}
+	}
+}
+
+func TestTodayCompactLine(t *testing.T) {
+	now := time.Date(2025, 11, 3, 12, 0, 0, 0, time.UTC)
+	summary := todaySummary{
+		Totals:   rollup.Totals{InputTokens: 1000, OutputTokens: 2000, CacheReadTokens: 132000, CostUSD: 4.21},
+		Sessions: 3,
+		Block:    todayBlock{EndTime: now.Add(2 * time.Hour), Entries: 4, TotalTokens: 54600, TokensPerMinute: 1200},
+		Average:  &todayAverage{ActiveDays: 12, TotalTokens: 100000, CostUSD: 3},
+	}
+
+	want := "Today 2025-11-03 · Tokens 135.0K · Cost $4.21 · Sessions 3 · Block 54.6K tokens · 2h0m left · 1.2K/min · vs. average +35%"
+	if got := todayCompactLine(summary, now); got != want {
+		t.Errorf("todayCompactLine() = %q, want %q", got, want)
+	}
+
+	summary.Average = nil
+	if got := todayCompactLine(summary, now); strings.Contains(got, "average") {
+		t.Errorf("todayCompactLine() = %q, want no average without history", got)
+	}
+
+	summary.Context = &analysis.ContextUsage{Tokens: 124000, Window: 200000}
+	if got := todayCompactLine(summary, now); !strings.HasSuffix(got, " · Context ~62% full (124.0K of 200.0K tokens)") {
+		t.Errorf("todayCompactLine() = %q, want the context estimate last", got)
+	}
+
+	summary.Block.RateLimits = 3
+	if got := todayCompactLine(summary, now); !strings.Contains(got, "1.2K/min · 3 rate-limit event(s) · ") {
+		t.Errorf("todayCompactLine() = %q, want the block's rate limits", got)
+	}
+}
+
+func TestLatestMainEntry(t *testing.T) {
+	now := time.Now()
+	entries := []parser.UsageEntry{
+		{SessionID: "old", Timestamp: now.Add(-time.Minute)},
+		{SessionID: "main", Timestamp: now},
+		{SessionID: "agent", Timestamp: now.Add(time.Second), IsSidechain: true},
+	}
+	if got := latestMainEntry(entries); got.SessionID != "main" {
+		t.Errorf("latestMainEntry() = %q, want main", got.SessionID)
+	}
+	if got := latestMainEntry(nil); !got.Timestamp.IsZero() {
+		t.Errorf("latestMainEntry(nil) = %+v, want zero entry", got)
+	}
+}
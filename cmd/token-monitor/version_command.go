@@ -0,0 +1,124 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	goruntime "runtime"
+	"runtime/debug"
+	"strings"
+)
+
+// buildFeatures lists the optional components compiled into this binary.
+// All of them are currently always built in; a component moved behind a
+// build tag should add itself from an init function in its tagged file.
+var buildFeatures = []string{"tui", "serve", "mqtt"}
+
+// buildInfo describes the running binary.
+type buildInfo struct {
+	Version   string   `json:"version"`
+	Commit    string   `json:"commit,omitempty"`
+	Date      string   `json:"date,omitempty"`
+	Modified  bool     `json:"modified,omitempty"` // built from a dirty tree
+	GoVersion string   `json:"go_version"`
+	Platform  string   `json:"platform"`
+	Features  []string `json:"features"`
+}
+
+// currentBuild returns the build metadata of the running binary.
+func currentBuild() buildInfo {
+	info := buildInfo{
+		Version:   version,
+		Commit:    commit,
+		Date:      date,
+		GoVersion: goruntime.Version(),
+		Platform:  goruntime.GOOS + "/" + goruntime.GOARCH,
+		Features:  buildFeatures,
+	}
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		applyVCSInfo(&info, bi)
+	}
+	return info
+}
+
+// applyVCSInfo fills in what ldflags left unset from the module version
+// (go install ...@v1.2.3) and the VCS stamp (go build in a checkout).
+func applyVCSInfo(info *buildInfo, bi *debug.BuildInfo) {
+	if info.Version == "dev" && bi.Main.Version != "" && bi.Main.Version != "(devel)" {
+		info.Version = bi.Main.Version
+	}
+	for _, s := range bi.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			if info.Commit == "" {
+				info.Commit = s.Value
+			}
+		case "vcs.time":
+			if info.Date == "" {
+				info.Date = s.Value
+			}
+		case "vcs.modified":
+			info.Modified = s.Value == "true"
+		}
+	}
+}
+
+// shortCommit abbreviates a commit hash for display.
+func shortCommit(c string) string {
+	if len(c) > 12 {
+		return c[:12]
+	}
+	return c
+}
+
+// String returns the one-line form printed by -version.
+func (b buildInfo) String() string {
+	var details []string
+	if b.Commit != "" {
+		c := shortCommit(b.Commit)
+		if b.Modified {
+			c += "-dirty"
+		}
+		details = append(details, c)
+	}
+	if b.Date != "" {
+		details = append(details, b.Date)
+	}
+	if len(details) == 0 {
+		return "token-monitor " + b.Version
+	}
+	return fmt.Sprintf("token-monitor %s (%s)", b.Version, strings.Join(details, ", "))
+}
+
+// writeText writes the build metadata as labeled lines.
+func (b buildInfo) writeText(w io.Writer) error {
+	commit := b.Commit
+	if commit == "" {
+		commit = "unknown"
+	} else if b.Modified {
+		commit += " (modified)"
+	}
+	built := b.Date
+	if built == "" {
+		built = "unknown"
+	}
+	_, err := fmt.Fprintf(w, "token-monitor %s\ncommit:   %s\nbuilt:    %s\ngo:       %s\nplatform: %s\nfeatures: %s\n",
+		b.Version, commit, built, b.GoVersion, b.Platform, strings.Join(b.Features, ", "))
+	return err
+}
+
+// runVersionCommand prints the build metadata.
+func runVersionCommand(globalOpts globalOptions, args []string) error {
+	fs := flag.NewFlagSet("version", flag.ExitOnError)
+	jsonOut := fs.Bool("json", false, "print the build metadata as JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	info := currentBuild()
+	if *jsonOut || globalOpts.jsonOutput {
+		return printJSON(info)
+	}
+	return info.writeText(os.Stdout)
+}
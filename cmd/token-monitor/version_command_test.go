@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"runtime/debug"
+	"strings"
+	"testing"
+)
+
+func TestApplyVCSInfo(t *testing.T) {
+	bi := &debug.BuildInfo{
+		Main: debug.Module{Version: "v1.2.3"},
+		Settings: []debug.BuildSetting{
+			{Key: "vcs.revision", Value: "0123456789abcdef0123"},
+			{Key: "vcs.time", Value: "2025-11-03T10:00:00Z"},
+			{Key: "vcs.modified", Value: "true"},
+		},
+	}
+
+	info := buildInfo{Version: "dev"}
+	applyVCSInfo(&info, bi)
+	if info.Version != "v1.2.3" || info.Commit != "0123456789abcdef0123" || info.Date != "2025-11-03T10:00:00Z" || !info.Modified {
+		t.Errorf("applyVCSInfo() = %+v, want the module version and VCS stamp", info)
+	}
+
+	// Values injected with ldflags win over the stamp.
+	info = buildInfo{Version: "v2.0.0", Commit: "feedface", Date: "2025-12-01T00:00:00Z"}
+	applyVCSInfo(&info, bi)
+	if info.Version != "v2.0.0" || info.Commit != "feedface" || info.Date != "2025-12-01T00:00:00Z" {
+		t.Errorf("applyVCSInfo() = %+v, want ldflags values kept", info)
+	}
+
+	info = buildInfo{Version: "dev"}
+	applyVCSInfo(&info, &debug.BuildInfo{Main: debug.Module{Version: "(devel)"}})
+	if info.Version != "dev" {
+		t.Errorf("applyVCSInfo((devel)) version = %q, want dev", info.Version)
+	}
+}
+
+func TestBuildInfoString(t *testing.T) {
+	tests := []struct {
+		info buildInfo
+		want string
+	}{
+		{buildInfo{Version: "dev"}, "token-monitor dev"},
+		{buildInfo{Version: "v1.0.0", Commit: "0123456789abcdef", Date: "2025-11-03"}, "token-monitor v1.0.0 (0123456789ab, 2025-11-03)"},
+		{buildInfo{Version: "dev", Commit: "abc", Modified: true}, "token-monitor dev (abc-dirty)"},
+	}
+	for _, tt := range tests {
+		if got := tt.info.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestBuildInfoWriteText(t *testing.T) {
+	info := buildInfo{Version: "v1.0.0", GoVersion: "go1.24.0", Platform: "linux/amd64", Features: []string{"tui", "serve"}}
+	var b bytes.Buffer
+	if err := info.writeText(&b); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"token-monitor v1.0.0\n", "commit:   unknown\n", "built:    unknown\n", "platform: linux/amd64\n", "features: tui, serve\n"} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("writeText() missing %q in:\n%s", want, b.String())
+		}
+	}
+}
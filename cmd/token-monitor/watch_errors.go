@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/0xmhha/token-monitor/pkg/display"
+	"github.com/0xmhha/token-monitor/pkg/monitor"
+	"github.com/0xmhha/token-monitor/pkg/notify"
+)
+
+// errorSpikeWindow is how far back watch -error-spike counts failed
+// requests.
+const errorSpikeWindow = 5 * time.Minute
+
+// errorSpike detects bursts of failed requests (API errors, rate limits,
+// aborts) for watch -error-spike, an early warning of API trouble.
+type errorSpike struct {
+	threshold int
+
+	// total is the cumulative error count of the previous update; times
+	// holds when each error within the window arrived.
+	total int
+	times []time.Time
+
+	// active is set while the count is at or above the threshold.
+	active bool
+}
+
+// newErrorSpike returns a detector for threshold errors within
+// errorSpikeWindow.
+func newErrorSpike(threshold int) *errorSpike {
+	return &errorSpike{threshold: threshold}
+}
+
+// observe records the errors of update and reports whether a spike has
+// just started. Each spike is reported once; it ends when the count
+// within the window drops below the threshold again.
+func (s *errorSpike) observe(update monitor.Update) bool {
+	total := update.Cumulative.Errors
+	if total < s.total {
+		s.total = 0 // statistics were reset
+	}
+	for i := s.total; i < total; i++ {
+		s.times = append(s.times, update.Timestamp)
+	}
+	s.total = total
+
+	cutoff := update.Timestamp.Add(-errorSpikeWindow)
+	for len(s.times) > 0 && !s.times[0].After(cutoff) {
+		s.times = s.times[1:]
+	}
+
+	started := !s.active && len(s.times) >= s.threshold
+	s.active = len(s.times) >= s.threshold
+	return started
+}
+
+// count returns the number of errors within the window.
+func (s *errorSpike) count() int {
+	return len(s.times)
+}
+
+// errorSpikeText describes a spike, e.g. "7 API errors in the last 5m".
+func errorSpikeText(count int) string {
+	return fmt.Sprintf("%d API errors in the last %s", count, display.FormatDuration(errorSpikeWindow))
+}
+
+// errorSpikeMessage is the alert posted when a spike starts.
+func errorSpikeMessage(count int, at time.Time) notify.Message {
+	return notify.Message{
+		Title: errorSpikeText(count),
+		Text:  "Claude Code requests are failing (API errors, rate limits, or aborts); the API may be overloaded or limiting usage.",
+		Level: notify.LevelWarning,
+		Time:  at,
+	}
+}
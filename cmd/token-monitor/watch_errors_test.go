@@ -0,0 +1,45 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/0xmhha/token-monitor/pkg/monitor"
+)
+
+func TestErrorSpike(t *testing.T) {
+	start := time.Date(2025, 11, 3, 12, 0, 0, 0, time.UTC)
+	s := newErrorSpike(3)
+	update := func(minutes, errors int) monitor.Update {
+		return monitor.Update{
+			Timestamp:  start.Add(time.Duration(minutes) * time.Minute),
+			Cumulative: monitor.DeltaStats{Errors: errors},
+		}
+	}
+
+	if s.observe(update(0, 1)) || s.observe(update(1, 2)) {
+		t.Fatal("observe() reported a spike below the threshold")
+	}
+	if !s.observe(update(2, 3)) {
+		t.Fatal("observe() did not report 3 errors within 5m")
+	}
+	if s.observe(update(3, 4)) || s.count() != 4 || !s.active {
+		t.Errorf("observe() during the spike: count %d, active %v; want 4, true, reported once", s.count(), s.active)
+	}
+
+	// The first errors age out of the window and the spike ends.
+	if s.observe(update(7, 4)) || s.active || s.count() != 1 {
+		t.Errorf("after 7m: count %d, active %v; want 1, false", s.count(), s.active)
+	}
+
+	// A reset drops the cumulative count; new errors count from zero.
+	if !s.observe(update(8, 3)) || s.count() != 3 {
+		t.Errorf("after reset: count %d, want a new spike of 3", s.count())
+	}
+}
+
+func TestErrorSpikeText(t *testing.T) {
+	if got := errorSpikeText(7); got != "7 API errors in the last 5m" {
+		t.Errorf("errorSpikeText() = %q", got)
+	}
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/0xmhha/token-monitor/pkg/config"
+	"github.com/0xmhha/token-monitor/pkg/display"
+	"github.com/0xmhha/token-monitor/pkg/logger"
+	"github.com/0xmhha/token-monitor/pkg/monitor"
+	"github.com/0xmhha/token-monitor/pkg/notify"
+	"github.com/0xmhha/token-monitor/pkg/session"
+)
+
+// silenceWatch tracks when the sessions with usage during watch were last
+// active, for watch -silent-after. In a long autonomous agent run, silence
+// usually means the agent crashed or is waiting for input.
+type silenceWatch struct {
+	after time.Duration
+
+	// lastSeen is when usage for each session last arrived; silent holds
+	// the sessions already reported, until their usage resumes.
+	lastSeen map[string]time.Time
+	silent   map[string]bool
+}
+
+// silentSession is a session without usage for at least the threshold.
+type silentSession struct {
+	SessionID string
+	LastSeen  time.Time
+}
+
+// newSilenceWatch returns a tracker alerting after the given silence.
+func newSilenceWatch(after time.Duration) *silenceWatch {
+	return &silenceWatch{
+		after:    after,
+		lastSeen: make(map[string]time.Time),
+		silent:   make(map[string]bool),
+	}
+}
+
+// observe records the sessions changed in update and returns those that
+// have just gone silent, oldest first. Each silence is returned once; new
+// usage for the session re-arms it. Sessions idle since before watch
+// started are never reported.
+func (s *silenceWatch) observe(update monitor.Update) []silentSession {
+	for _, d := range update.ChangedSessions {
+		if d.NewEntries == 0 {
+			continue
+		}
+		s.lastSeen[d.SessionID] = update.Timestamp
+		delete(s.silent, d.SessionID)
+	}
+
+	var fresh []silentSession
+	for id, seen := range s.lastSeen {
+		if s.silent[id] || update.Timestamp.Sub(seen) < s.after {
+			continue
+		}
+		s.silent[id] = true
+		fresh = append(fresh, silentSession{SessionID: id, LastSeen: seen})
+	}
+	sortSilent(fresh)
+	return fresh
+}
+
+// current returns the sessions that are silent now, oldest first.
+func (s *silenceWatch) current() []silentSession {
+	sessions := make([]silentSession, 0, len(s.silent))
+	for id := range s.silent {
+		sessions = append(sessions, silentSession{SessionID: id, LastSeen: s.lastSeen[id]})
+	}
+	sortSilent(sessions)
+	return sessions
+}
+
+// sortSilent orders sessions by last activity, then by ID.
+func sortSilent(sessions []silentSession) {
+	sort.Slice(sessions, func(i, j int) bool {
+		if !sessions[i].LastSeen.Equal(sessions[j].LastSeen) {
+			return sessions[i].LastSeen.Before(sessions[j].LastSeen)
+		}
+		return sessions[i].SessionID < sessions[j].SessionID
+	})
+}
+
+// sessionLabel returns the session's name, or its short ID without one
+// or without the session database.
+func sessionLabel(mgr session.Manager, id string) string {
+	if mgr != nil {
+		if metadata, err := mgr.GetByUUID(id); err == nil && metadata.Name != "" {
+			return metadata.Name
+		}
+	}
+	return shortSessionID(id)
+}
+
+// silenceText describes a silent session, e.g. "no usage in 45m on
+// session my-project".
+func silenceText(label string, silence time.Duration) string {
+	return fmt.Sprintf("no usage in %s on session %s", display.FormatDuration(silence), label)
+}
+
+// silenceMessage is the alert posted when a session goes silent.
+func silenceMessage(s silentSession, label string, after time.Duration) notify.Message {
+	return notify.Message{
+		Title: "No usage in " + display.FormatDuration(after) + " on session " + label,
+		Text:  "The agent may have stopped or be waiting for input.",
+		Level: notify.LevelWarning,
+		Fields: []notify.Field{
+			{Name: "Session", Value: s.SessionID},
+			{Name: "Last usage", Value: s.LastSeen.Local().Format("2006-01-02 15:04:05"), Inline: true},
+		},
+		Time: s.LastSeen.Add(after),
+	}
+}
+
+// postWatchAlert posts msg to the channels receiving alerts, if any, in
+// the background; delivery failures are logged, not shown in watch.
+func postWatchAlert(cfg *config.Config, log logger.Logger, msg notify.Message) {
+	if len(cfg.Notify.Channels) == 0 {
+		return
+	}
+	channels, err := notifyChannels(cfg, notifyEventAlert, "")
+	if err != nil {
+		log.Debug("watch alert not posted", "error", err)
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+		defer cancel()
+		for _, ch := range channels {
+			if err := ch.notifier.Notify(ctx, msg); err != nil {
+				log.Warn("failed to post watch alert", "channel", ch.name, "error", err)
+			}
+		}
+	}()
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/0xmhha/token-monitor/pkg/monitor"
+)
+
+func TestSilenceWatch(t *testing.T) {
+	start := time.Date(2025, 11, 3, 12, 0, 0, 0, time.UTC)
+	s := newSilenceWatch(45 * time.Minute)
+	update := func(minutes int, sessions ...string) monitor.Update {
+		u := monitor.Update{Timestamp: start.Add(time.Duration(minutes) * time.Minute)}
+		for _, id := range sessions {
+			u.ChangedSessions = append(u.ChangedSessions, monitor.SessionDelta{SessionID: id, NewEntries: 1})
+		}
+		return u
+	}
+
+	if got := s.observe(update(0, "a", "b")); len(got) != 0 {
+		t.Fatalf("observe(0m) = %v, want none", got)
+	}
+	if got := s.observe(update(30, "b")); len(got) != 0 {
+		t.Fatalf("observe(30m) = %v, want none", got)
+	}
+
+	got := s.observe(update(45))
+	if len(got) != 1 || got[0].SessionID != "a" || !got[0].LastSeen.Equal(start) {
+		t.Fatalf("observe(45m) = %v, want a silent since 12:00", got)
+	}
+	if got := s.observe(update(50)); len(got) != 0 {
+		t.Errorf("observe(50m) = %v, want a reported only once", got)
+	}
+	if current := s.current(); len(current) != 1 || current[0].SessionID != "a" {
+		t.Errorf("current() = %v, want a", current)
+	}
+
+	// b goes silent too; then a resumes and is re-armed.
+	if got := s.observe(update(75)); len(got) != 1 || got[0].SessionID != "b" {
+		t.Errorf("observe(75m) = %v, want b", got)
+	}
+	s.observe(update(80, "a"))
+	if current := s.current(); len(current) != 1 || current[0].SessionID != "b" {
+		t.Errorf("current() after a resumed = %v, want b", current)
+	}
+	if got := s.observe(update(125)); len(got) != 1 || got[0].SessionID != "a" {
+		t.Errorf("observe(125m) = %v, want a again", got)
+	}
+}
+
+func TestSilenceText(t *testing.T) {
+	if got := silenceText("my-project", 52*time.Minute); got != "no usage in 52m on session my-project" {
+		t.Errorf("silenceText() = %q", got)
+	}
+	if got := sessionLabel(nil, "a1b2c3d4-e5f6-7890-abcd-ef1234567890"); got != "a1b2c3d4" {
+		t.Errorf("sessionLabel(nil) = %q, want the short ID", got)
+	}
+}
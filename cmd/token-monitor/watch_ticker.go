@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/0xmhha/token-monitor/pkg/display"
+	"github.com/0xmhha/token-monitor/pkg/parser"
+	"github.com/0xmhha/token-monitor/pkg/rollup"
+)
+
+// dayTicker builds the watch footer: today's running totals from the
+// rollups, compared with yesterday's totals up to the same time of day.
+// Rollups only hold daily totals, so yesterday's entries are read from the
+// session files once per day, in the background so rendering never waits on
+// them, and kept as running totals.
+type dayTicker struct {
+	store rollup.Store
+
+	// load returns the entries with from <= timestamp < to.
+	load func(from, to time.Time) ([]parser.UsageEntry, error)
+
+	// mu guards the fields below, which the background load sets.
+	mu sync.Mutex
+
+	// yesterday is the date of points, set once they are loaded; points
+	// are yesterday's running totals in time order.
+	yesterday string
+	points    []tickerPoint
+
+	// loading is the date being loaded, or "" when idle; a failed load is
+	// retried from retry on.
+	loading string
+	retry   time.Time
+}
+
+// tickerRetryInterval is how long after a failed load of yesterday's
+// entries the ticker tries again.
+const tickerRetryInterval = time.Minute
+
+// tickerPoint is the running total after the entry at time at.
+type tickerPoint struct {
+	at     time.Time
+	totals rollup.Totals
+}
+
+// line returns the footer for now, or "" without today's rollups. Days
+// are rollup days (UTC), so that today's rollups and yesterday's entries
+// cover the same hours; the time of day is shown in now's zone.
+func (t *dayTicker) line(now time.Time) string {
+	if t.store == nil {
+		return ""
+	}
+	date := rollup.Date(now)
+	rows, err := t.store.Rows(date, date)
+	if err != nil {
+		return ""
+	}
+	var today rollup.Totals
+	for _, row := range rows {
+		today.Merge(row.Totals)
+	}
+
+	dayStart := rollup.DayStart(now)
+	yesterdayStart := dayStart.AddDate(0, 0, -1)
+
+	// The same time into the day, yesterday.
+	sameTime := yesterdayStart.Add(now.Sub(dayStart))
+	until := t.yesterdayUntil(yesterdayStart, dayStart, sameTime, now)
+	return formatTicker(today, until, sameTime.In(now.Location()))
+}
+
+// yesterdayUntil returns yesterday's totals until sameTime, or nil while
+// they are not loaded; it starts loading them if no load is under way.
+func (t *dayTicker) yesterdayUntil(from, to, sameTime, now time.Time) *rollup.Totals {
+	day := rollup.Date(from)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.yesterday == day {
+		totals := totalsAt(t.points, sameTime)
+		return &totals
+	}
+	if t.loading == "" && !now.Before(t.retry) {
+		t.loading = day
+		go t.loadDay(day, from, to, now)
+	}
+	return nil
+}
+
+// loadDay loads the running totals of day, the entries with
+// from <= timestamp < to. After a failure the load is retried from
+// tickerRetryInterval after now on.
+func (t *dayTicker) loadDay(day string, from, to, now time.Time) {
+	entries, err := t.load(from, to)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.loading = ""
+	if err != nil {
+		t.retry = now.Add(tickerRetryInterval)
+		return
+	}
+	t.yesterday = day
+	t.points = runningTotals(entries)
+}
+
+// runningTotals returns the running totals of entries in time order.
+func runningTotals(entries []parser.UsageEntry) []tickerPoint {
+	sorted := make([]parser.UsageEntry, len(entries))
+	copy(sorted, entries)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp.Before(sorted[j].Timestamp)
+	})
+
+	points := make([]tickerPoint, 0, len(sorted))
+	var totals rollup.Totals
+	for _, entry := range sorted {
+		totals.AddEntry(entry)
+		points = append(points, tickerPoint{at: entry.Timestamp, totals: totals})
+	}
+	return points
+}
+
+// totalsAt returns the running totals of the entries at or before at.
+func totalsAt(points []tickerPoint, at time.Time) rollup.Totals {
+	i := sort.Search(len(points), func(i int) bool {
+		return points[i].at.After(at)
+	})
+	if i == 0 {
+		return rollup.Totals{}
+	}
+	return points[i-1].totals
+}
+
+// formatTicker formats today's totals with the change in cost from
+// yesterday's totals until the same time, when known.
+func formatTicker(today rollup.Totals, yesterday *rollup.Totals, sameTime time.Time) string {
+	line := fmt.Sprintf("Today: %s tokens, %s",
+		display.FormatNumber(today.TotalTokens()), display.FormatCost(today.CostUSD))
+	switch {
+	case yesterday == nil:
+	case yesterday.CostUSD > 0:
+		change := (today.CostUSD - yesterday.CostUSD) / yesterday.CostUSD * 100
+		line += fmt.Sprintf(", %+.0f%% vs yesterday at %s", change, sameTime.Format("15:04"))
+	default:
+		line += fmt.Sprintf(" (yesterday at %s: %s)", sameTime.Format("15:04"), display.FormatCost(0))
+	}
+	return line
+}
+
+// loadEntries reads the entries with from <= timestamp < to from session
+// files modified since from, without moving the reader's positions.
+// Files that cannot be read are skipped.
+func (rt *watchRuntime) loadEntries(from, to time.Time) ([]parser.UsageEntry, error) {
+	disc, err := rt.shared.Discoverer()
+	if err != nil {
+		return nil, err
+	}
+	sessions, err := disc.Discover()
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []parser.UsageEntry
+	for _, sess := range sessions {
+		if sess.ModTime < from.Unix() {
+			continue
+		}
+		read, _, err := rt.reader.ReadFrom(context.Background(), sess.FilePath, 0)
+		if err != nil {
+			rt.log.Warn("failed to read session",
+				"session", sess.SessionID,
+				"path", sess.FilePath,
+				"error", err)
+			continue
+		}
+		for _, entry := range read {
+			if !entry.Timestamp.Before(from) && entry.Timestamp.Before(to) {
+				entries = append(entries, entry)
+			}
+		}
+	}
+	return entries, nil
+}
@@ -0,0 +1,253 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0xmhha/token-monitor/cmd/internal/runtime"
+	"github.com/0xmhha/token-monitor/pkg/logger"
+	"github.com/0xmhha/token-monitor/pkg/parser"
+	"github.com/0xmhha/token-monitor/pkg/reader"
+	"github.com/0xmhha/token-monitor/pkg/rollup"
+)
+
+// todayStore holds the rollup rows of one day; only Rows is called by the
+// ticker.
+type todayStore struct {
+	rollup.Store
+	rows []rollup.Row
+}
+
+func (s todayStore) Rows(from, to string) ([]rollup.Row, error) {
+	var rows []rollup.Row
+	for _, row := range s.rows {
+		if row.Date >= from && row.Date <= to {
+			rows = append(rows, row)
+		}
+	}
+	return rows, nil
+}
+
+// waitForLine polls t.line(now) until it contains want.
+func waitForLine(t *testing.T, ticker *dayTicker, now time.Time, want string) string {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		line := ticker.line(now)
+		if strings.Contains(line, want) {
+			return line
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("line() = %q, want it to contain %q", line, want)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestTotalsAt(t *testing.T) {
+	day := time.Date(2025, 11, 2, 0, 0, 0, 0, time.UTC)
+	entry := func(hour, tokens int) parser.UsageEntry {
+		return parser.UsageEntry{
+			Timestamp: day.Add(time.Duration(hour) * time.Hour),
+			Message:   parser.Message{Usage: parser.Usage{InputTokens: tokens}},
+		}
+	}
+	// Out of order, as entries from several files are.
+	points := runningTotals([]parser.UsageEntry{entry(15, 300), entry(9, 100), entry(12, 200)})
+
+	tests := []struct {
+		hour int
+		want int
+	}{
+		{8, 0},
+		{9, 100},
+		{13, 300},
+		{23, 600},
+	}
+	for _, tt := range tests {
+		got := totalsAt(points, day.Add(time.Duration(tt.hour)*time.Hour))
+		if got.TotalTokens() != tt.want {
+			t.Errorf("totalsAt(%02d:00) = %d tokens, want %d", tt.hour, got.TotalTokens(), tt.want)
+		}
+	}
+}
+
+func TestFormatTicker(t *testing.T) {
+	sameTime := time.Date(2025, 11, 2, 14, 5, 0, 0, time.UTC)
+	today := rollup.Totals{InputTokens: 1_500_000, CostUSD: 14.2}
+
+	tests := []struct {
+		name      string
+		yesterday *rollup.Totals
+		want      string
+	}{
+		{"unknown", nil, "Today: 1,500,000 tokens, $14.20"},
+		{"more", &rollup.Totals{CostUSD: 11.64}, "Today: 1,500,000 tokens, $14.20, +22% vs yesterday at 14:05"},
+		{"less", &rollup.Totals{CostUSD: 28.4}, "Today: 1,500,000 tokens, $14.20, -50% vs yesterday at 14:05"},
+		{"none", &rollup.Totals{}, "Today: 1,500,000 tokens, $14.20 (yesterday at 14:05: $0.00)"},
+	}
+	for _, tt := range tests {
+		if got := formatTicker(today, tt.yesterday, sameTime); got != tt.want {
+			t.Errorf("%s: formatTicker() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDayTicker_LoadsInBackground(t *testing.T) {
+	now := time.Date(2025, 11, 3, 14, 0, 0, 0, time.UTC)
+	release := make(chan struct{})
+	ticker := &dayTicker{
+		store: todayStore{},
+		load: func(from, to time.Time) ([]parser.UsageEntry, error) {
+			<-release
+			return []parser.UsageEntry{{Timestamp: from.Add(time.Hour)}}, nil
+		},
+	}
+
+	// Rendering does not wait for yesterday's entries.
+	done := make(chan string)
+	go func() { done <- ticker.line(now) }()
+	select {
+	case line := <-done:
+		if strings.Contains(line, "yesterday") {
+			t.Errorf("line() = %q before the load finished", line)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("line() blocked on the load of yesterday's entries")
+	}
+
+	close(release)
+	waitForLine(t, ticker, now, "yesterday at 14:00")
+}
+
+func TestDayTicker_RetriesFailedLoad(t *testing.T) {
+	now := time.Date(2025, 11, 3, 14, 0, 0, 0, time.UTC)
+	calls := make(chan struct{}, 10)
+	fail := true
+	ticker := &dayTicker{
+		store: todayStore{},
+		load: func(from, to time.Time) ([]parser.UsageEntry, error) {
+			defer func() { calls <- struct{}{} }()
+			if fail {
+				return nil, errors.New("disk on fire")
+			}
+			return nil, nil
+		},
+	}
+
+	ticker.line(now)
+	<-calls
+	// Wait until the failure is recorded before checking the retry.
+	for {
+		ticker.mu.Lock()
+		loading := ticker.loading
+		ticker.mu.Unlock()
+		if loading == "" {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if line := ticker.line(now.Add(time.Second)); strings.Contains(line, "yesterday") {
+		t.Errorf("line() = %q after a failed load", line)
+	}
+	select {
+	case <-calls:
+		t.Fatal("failed load retried before tickerRetryInterval")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	fail = false
+	waitForLine(t, ticker, now.Add(tickerRetryInterval), "yesterday at 14:01")
+}
+
+func TestLoadEntriesSkipsUnreadableFiles(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("CLAUDE_CONFIG_DIR", dir)
+	t.Setenv("TOKEN_MONITOR_DB", filepath.Join(dir, "sessions.db"))
+	t.Setenv("TOKEN_MONITOR_CACHE_DIR", filepath.Join(dir, "cache"))
+	t.Setenv("TOKEN_MONITOR_LOG_LEVEL", "error")
+
+	from := time.Date(2025, 11, 2, 0, 0, 0, 0, time.UTC)
+	project := filepath.Join(dir, "project-a")
+	if err := os.MkdirAll(project, 0700); err != nil {
+		t.Fatal(err)
+	}
+	writeSession := func(sessionID string, count int) {
+		var lines strings.Builder
+		for i := 0; i < count; i++ {
+			fmt.Fprintf(&lines, `{"timestamp":"2025-11-02T10:%02d:00Z","sessionId":%q,"message":{"id":"m%d","model":"claude-sonnet-4","usage":{"input_tokens":10,"output_tokens":5}}}`+"\n",
+				i, sessionID, i)
+		}
+		if err := os.WriteFile(filepath.Join(project, sessionID+".jsonl"), []byte(lines.String()), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeSession("a1b2c3d4-e5f6-7890-abcd-ef1234567890", 1)
+	writeSession("b1b2c3d4-e5f6-7890-abcd-ef1234567890", 20) // over MaxFileSize
+
+	log := logger.New(logger.Config{Level: "error", Writer: io.Discard})
+	r, err := reader.New(reader.Config{
+		PositionStore: reader.NewMemoryPositionStore(),
+		Parser:        parser.New(),
+		MaxFileSize:   1024,
+	}, log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shared := runtime.New(runtime.Options{NoCache: true})
+	defer func() { _ = shared.Close() }() //nolint:errcheck
+	rt := &watchRuntime{shared: shared, log: log, reader: r}
+
+	entries, err := rt.loadEntries(from, from.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("loadEntries() error = %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("loadEntries() = %d entries, want the 1 of the readable file", len(entries))
+	}
+}
+
+func TestDayTicker_LocalZone(t *testing.T) {
+	kst := time.FixedZone("KST", 9*3600)
+	// 08:00 on November 3 in Seoul is 23:00 on November 2 in UTC, the
+	// rollup day the store keys today's usage by.
+	now := time.Date(2025, 11, 3, 8, 0, 0, 0, kst)
+	store := todayStore{rows: []rollup.Row{
+		{Key: rollup.Key{Date: "2025-11-02"}, Totals: rollup.Totals{InputTokens: 100, CostUSD: 6}},
+		{Key: rollup.Key{Date: "2025-11-03"}, Totals: rollup.Totals{InputTokens: 900, CostUSD: 99}},
+	}}
+
+	entry := func(at time.Time) parser.UsageEntry {
+		return parser.UsageEntry{Timestamp: at, Message: parser.Message{
+			Model: "claude-sonnet-4-20250514",
+			Usage: parser.Usage{InputTokens: 1_000_000},
+		}}
+	}
+	var from, to time.Time
+	ticker := &dayTicker{
+		store: store,
+		load: func(f, t time.Time) ([]parser.UsageEntry, error) {
+			from, to = f, t
+			return []parser.UsageEntry{
+				entry(time.Date(2025, 11, 1, 22, 0, 0, 0, time.UTC)),
+				// After the same time of day, yesterday.
+				entry(time.Date(2025, 11, 1, 23, 30, 0, 0, time.UTC)),
+			}, nil
+		},
+	}
+
+	line := waitForLine(t, ticker, now, "yesterday")
+	if want := "Today: 100 tokens, $6.00, +100% vs yesterday at 08:00"; line != want {
+		t.Errorf("line() = %q, want %q", line, want)
+	}
+	wantFrom := time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC)
+	if !from.Equal(wantFrom) || !to.Equal(wantFrom.AddDate(0, 0, 1)) {
+		t.Errorf("loaded %s..%s, want the UTC day of %s", from, to, wantFrom)
+	}
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/0xmhha/token-monitor/pkg/aggregator"
+	"github.com/0xmhha/token-monitor/pkg/analysis"
+	"github.com/0xmhha/token-monitor/pkg/display"
+)
+
+// whatifCommand recomputes historical cost as if requests had used a
+// different model, or prompt caching had been used differently.
+type whatifCommand struct {
+	switches   []analysis.ModelSwitch
+	cache      bool
+	window     string
+	format     string
+	globalOpts globalOptions
+}
+
+// whatifReport is the JSON output of whatif.
+type whatifReport struct {
+	Window   string                 `json:"window"`
+	Switches []analysis.ModelSwitch `json:"switches,omitempty"`
+	Result   *analysis.WhatIfResult `json:"result,omitempty"`
+	Savings  float64                `json:"savings_usd,omitempty"`
+	Percent  float64                `json:"savings_percent,omitempty"`
+	Cache    *cacheReport           `json:"cache,omitempty"`
+}
+
+// cacheReport is the prompt caching what-if of whatif -cache.
+type cacheReport struct {
+	analysis.CacheWhatIf
+	Saved     float64 `json:"saved_usd"`
+	Potential float64 `json:"potential_usd"`
+}
+
+// parseModelSwitch parses a -map value such as "opus=sonnet".
+func parseModelSwitch(value string) (analysis.ModelSwitch, error) {
+	from, to, ok := strings.Cut(value, "=")
+	from, to = strings.TrimSpace(from), strings.TrimSpace(to)
+	if !ok || from == "" || to == "" {
+		return analysis.ModelSwitch{}, fmt.Errorf("invalid -map %q (want FROM=TO, e.g. opus=sonnet)", value)
+	}
+	if _, err := filepath.Match(from, ""); err != nil {
+		return analysis.ModelSwitch{}, fmt.Errorf("invalid -map %q: %w", value, err)
+	}
+	// Unknown models are priced as sonnet; require a name that says which
+	// class the requests would move to.
+	lower := strings.ToLower(to)
+	if !strings.Contains(lower, analysis.ClassOpus) && !strings.Contains(lower, analysis.ClassSonnet) &&
+		!strings.Contains(lower, analysis.ClassHaiku) {
+		return analysis.ModelSwitch{}, fmt.Errorf("invalid -map %q: target %q is not an opus, sonnet, or haiku model", value, to)
+	}
+	return analysis.ModelSwitch{From: from, To: to}, nil
+}
+
+// runWhatifCommand runs the whatif command.
+func runWhatifCommand(globalOpts globalOptions, args []string) error {
+	fs := flag.NewFlagSet("whatif", flag.ExitOnError)
+	var maps listFlag
+	fs.Var(&maps, "map", "FROM=TO model switch, e.g. opus=sonnet (repeatable, comma-separated)")
+	cache := fs.Bool("cache", false, "compare the cost without prompt caching and with every input cached")
+	window := fs.String("window", "30d", "period to reprice (today, all, Nd, Nh)")
+	format := fs.String("format", "table", "output format (table, json)")
+
+	if err := globalOpts.applyFlagDefaults(fs); err != nil {
+		return err
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if globalOpts.jsonOutput {
+		*format = "json"
+	}
+	switch *format {
+	case "table", "json":
+	default:
+		return fmt.Errorf("invalid -format %q (want table or json)", *format)
+	}
+	if len(maps) == 0 && !*cache {
+		return fmt.Errorf("whatif needs -cache or at least one -map FROM=TO, e.g. -map opus=sonnet")
+	}
+
+	cmd := &whatifCommand{cache: *cache, window: *window, format: *format, globalOpts: globalOpts}
+	for _, m := range maps {
+		s, err := parseModelSwitch(m)
+		if err != nil {
+			return err
+		}
+		cmd.switches = append(cmd.switches, s)
+	}
+	return cmd.Execute()
+}
+
+// Execute reprices the window's requests and prints the comparison.
+func (c *whatifCommand) Execute() error {
+	since, err := display.ParseWindow(c.window, time.Now())
+	if err != nil {
+		return err
+	}
+
+	rt := c.globalOpts.newRuntime("")
+	defer func() {
+		_ = rt.Close() //nolint:errcheck // best effort cleanup
+	}()
+
+	if _, err := rt.Config(); err != nil {
+		return err
+	}
+	entries, err := focusEntries(rt, since)
+	if err != nil {
+		return err
+	}
+	entries = aggregator.FilterSince(entries, since)
+
+	report := whatifReport{Window: c.window, Switches: c.switches}
+	if len(c.switches) > 0 {
+		result := analysis.WhatIf(entries, c.switches)
+		report.Result = &result
+		report.Savings, report.Percent = result.Savings(), result.SavingsPercent()
+	}
+	if c.cache {
+		var caching analysis.CacheWhatIf
+		for _, entry := range entries {
+			caching.AddEntry(entry)
+		}
+		report.Cache = &cacheReport{CacheWhatIf: caching, Saved: caching.Saved(), Potential: caching.Potential()}
+	}
+
+	if c.format == "json" {
+		return printJSON(report)
+	}
+	if report.Result != nil {
+		if err := c.displaySwitches(*report.Result); err != nil {
+			return err
+		}
+	}
+	if report.Cache != nil {
+		if report.Result != nil {
+			c.globalOpts.output().Printf("\n")
+		}
+		c.displayCache(report.Cache.CacheWhatIf)
+	}
+	return nil
+}
+
+// displaySwitches prints one row per switched model followed by the
+// totals.
+func (c *whatifCommand) displaySwitches(result analysis.WhatIfResult) error {
+	out := c.globalOpts.output()
+	maps := make([]string, 0, len(c.switches))
+	for _, s := range c.switches {
+		maps = append(maps, s.From+" → "+s.To)
+	}
+	out.Printf("What-if cost for %s: %s\n\n", c.window, strings.Join(maps, ", "))
+
+	if len(result.Rows) == 0 {
+		out.Printf("No requests matched in this period.\n")
+	} else {
+		header := []string{"MODEL", "AS", "REQUESTS", "COST", "WHAT-IF", "SAVINGS"}
+		rows := make([][]string, 0, len(result.Rows))
+		for _, r := range result.Rows {
+			rows = append(rows, []string{
+				r.Model,
+				r.To,
+				display.FormatNumber(r.Requests),
+				display.FormatCost(r.Cost),
+				display.FormatCost(r.WhatIfCost),
+				formatSavings(r.Savings()),
+			})
+		}
+		if err := display.WriteTable(os.Stdout, header, rows, false); err != nil {
+			return err
+		}
+	}
+
+	out.Printf("All requests: %s as recorded, %s with the switches; savings %s (%.1f%%)\n",
+		display.FormatCost(result.Cost), display.FormatCost(result.WhatIfCost),
+		formatSavings(result.Savings()), result.SavingsPercent())
+	return nil
+}
+
+// displayCache prints the cost of the window with caching as recorded,
+// without caching, and with every input token cached.
+func (c *whatifCommand) displayCache(caching analysis.CacheWhatIf) {
+	out := c.globalOpts.output()
+	out.Printf("Prompt caching for %s\n\n", c.window)
+	out.Printf("  As recorded:          %s\n", display.FormatCost(caching.Cost))
+	out.Printf("  Without caching:      %s\n", display.FormatCost(caching.NoCacheCost))
+	out.Printf("  Every input cached:   %s\n\n", display.FormatCost(caching.FullCacheCost))
+	out.Printf("Caching saved you %s; at most %s more could be saved.\n",
+		formatSavings(caching.Saved()), formatSavings(caching.Potential()))
+}
+
+// formatSavings formats a cost difference, with a minus sign when the
+// switch costs more.
+func formatSavings(amount float64) string {
+	if amount < 0 {
+		return "-" + display.FormatCost(-amount)
+	}
+	return display.FormatCost(amount)
+}
@@ -0,0 +1,16 @@
+package main
+
+import "testing"
+
+func TestParseModelSwitch(t *testing.T) {
+	s, err := parseModelSwitch(" opus = claude-sonnet-4-5 ")
+	if err != nil || s.From != "opus" || s.To != "claude-sonnet-4-5" {
+		t.Errorf("parseModelSwitch() = %+v, %v", s, err)
+	}
+
+	for _, value := range []string{"opus", "=sonnet", "opus=", "[=sonnet", "opus=gpt-4o"} {
+		if _, err := parseModelSwitch(value); err == nil {
+			t.Errorf("parseModelSwitch(%q) succeeded, want an error", value)
+		}
+	}
+}
@@ -0,0 +1,20 @@
+// Package examples shows how to embed token-monitor's packages in other Go
+// programs.
+//
+// The examples in this package are compiled and run by go test, so their
+// output is checked on every build. They double as the API contract for
+// library users: a change that breaks one breaks downstream code in the
+// same way.
+//
+//   - Example_parseLine, Example_parseFile: reading Claude Code session
+//     lines and files with pkg/parser
+//   - Example_aggregatorStats, Example_aggregatorGroupBy,
+//     Example_aggregatorBurnRate: totals, per-model groups, and burn rates
+//     with pkg/aggregator
+//   - Example_monitor: following session files live with pkg/discovery,
+//     pkg/watcher, pkg/reader, and pkg/monitor
+//
+// Run them with:
+//
+//	go test ./examples -v
+package examples
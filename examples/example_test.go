@@ -0,0 +1,216 @@
+package examples_test
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/0xmhha/token-monitor/pkg/aggregator"
+	"github.com/0xmhha/token-monitor/pkg/discovery"
+	"github.com/0xmhha/token-monitor/pkg/logger"
+	"github.com/0xmhha/token-monitor/pkg/monitor"
+	"github.com/0xmhha/token-monitor/pkg/parser"
+	"github.com/0xmhha/token-monitor/pkg/reader"
+	"github.com/0xmhha/token-monitor/pkg/watcher"
+)
+
+// sessionID is the session of the example lines.
+const sessionID = "a1b2c3d4-e5f6-7890-abcd-ef1234567890"
+
+// usageLine returns a Claude Code session line for one response.
+func usageLine(ts time.Time, model string, input, output int) string {
+	return fmt.Sprintf(`{"timestamp":%q,"sessionId":%q,"message":{"id":"msg_%d","model":%q,`+
+		`"usage":{"input_tokens":%d,"output_tokens":%d}}}`,
+		ts.UTC().Format(time.RFC3339), sessionID, ts.Unix(), model, input, output)
+}
+
+// writeSession writes lines as the session file of project under a new
+// Claude projects directory and returns that directory.
+func writeSession(project string, lines ...string) string {
+	base, err := os.MkdirTemp("", "token-monitor-example")
+	if err != nil {
+		log.Fatal(err)
+	}
+	dir := filepath.Join(base, project)
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		log.Fatal(err)
+	}
+	path := filepath.Join(dir, sessionID+".jsonl")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0600); err != nil {
+		log.Fatal(err)
+	}
+	return base
+}
+
+// Example_parseLine parses one line of a session file.
+func Example_parseLine() {
+	line := usageLine(time.Date(2025, 11, 3, 10, 0, 0, 0, time.UTC), "claude-sonnet-4", 1200, 300)
+
+	entry, err := parser.New().ParseLine(line)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(entry.SessionID)
+	fmt.Println(entry.Message.Model, entry.Message.Usage.TotalTokens())
+	// Output:
+	// a1b2c3d4-e5f6-7890-abcd-ef1234567890
+	// claude-sonnet-4 1500
+}
+
+// Example_parseFile reads a session file incrementally: the returned
+// offset is where the next read continues.
+func Example_parseFile() {
+	start := time.Date(2025, 11, 3, 10, 0, 0, 0, time.UTC)
+	base := writeSession("app",
+		usageLine(start, "claude-sonnet-4", 100, 20),
+		`{"type":"summary","summary":"not a usage line"}`,
+		usageLine(start.Add(time.Minute), "claude-sonnet-4", 200, 40),
+	)
+	defer os.RemoveAll(base) //nolint:errcheck // temporary directory
+
+	path := filepath.Join(base, "app", sessionID+".jsonl")
+	entries, offset, err := parser.New().ParseFile(path, 0)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(len(entries), "entries")
+
+	more, _, err := parser.New().ParseFile(path, offset)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(len(more), "new entries")
+	// Output:
+	// 2 entries
+	// 0 new entries
+}
+
+// Example_aggregatorStats totals entries.
+func Example_aggregatorStats() {
+	start := time.Date(2025, 11, 3, 10, 0, 0, 0, time.UTC)
+	p := parser.New()
+
+	agg := aggregator.New(aggregator.Config{TrackPercentiles: true})
+	for i, tokens := range []int{100, 300, 200} {
+		entry, err := p.ParseLine(usageLine(start.Add(time.Duration(i)*time.Minute), "claude-sonnet-4", tokens, 0))
+		if err != nil {
+			log.Fatal(err)
+		}
+		agg.Add(*entry)
+	}
+
+	stats := agg.Stats()
+	fmt.Println("entries:", stats.Count)
+	fmt.Println("total:", stats.TotalTokens)
+	fmt.Println("max:", stats.MaxTokens)
+	// Output:
+	// entries: 3
+	// total: 600
+	// max: 300
+}
+
+// Example_aggregatorGroupBy totals entries per model.
+func Example_aggregatorGroupBy() {
+	start := time.Date(2025, 11, 3, 10, 0, 0, 0, time.UTC)
+	p := parser.New()
+
+	agg := aggregator.New(aggregator.Config{GroupBy: []aggregator.Dimension{aggregator.DimModel}})
+	for _, line := range []string{
+		usageLine(start, "claude-sonnet-4", 100, 10),
+		usageLine(start.Add(time.Minute), "claude-opus-4", 50, 5),
+		usageLine(start.Add(2*time.Minute), "claude-sonnet-4", 200, 20),
+	} {
+		entry, err := p.ParseLine(line)
+		if err != nil {
+			log.Fatal(err)
+		}
+		agg.Add(*entry)
+	}
+
+	groups := agg.GroupedStats()
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Printf("%s: %d entries, %d tokens\n", key, groups[key].Count, groups[key].TotalTokens)
+	}
+	// Output:
+	// claude-opus-4: 1 entries, 55 tokens
+	// claude-sonnet-4: 2 entries, 330 tokens
+}
+
+// Example_aggregatorBurnRate measures the token rate over the last ten
+// minutes.
+func Example_aggregatorBurnRate() {
+	now := time.Now()
+	p := parser.New()
+
+	agg := aggregator.New(aggregator.Config{})
+	for _, ago := range []time.Duration{30 * time.Minute, 4 * time.Minute, 2 * time.Minute} {
+		entry, err := p.ParseLine(usageLine(now.Add(-ago), "claude-sonnet-4", 1000, 0))
+		if err != nil {
+			log.Fatal(err)
+		}
+		agg.Add(*entry)
+	}
+
+	rate := agg.BurnRate("", 10*time.Minute)
+	fmt.Println("entries in window:", rate.EntryCount)
+	fmt.Printf("tokens/min: %.0f\n", rate.TokensPerMinute)
+	fmt.Printf("tokens/hour: %.0f\n", rate.TokensPerHour)
+	// Output:
+	// entries in window: 2
+	// tokens/min: 200
+	// tokens/hour: 12000
+}
+
+// Example_monitor follows the sessions under a Claude projects directory.
+// Start reads the existing entries and sends a first update; later updates
+// follow writes to the session files until Stop.
+func Example_monitor() {
+	start := time.Date(2025, 11, 3, 10, 0, 0, 0, time.UTC)
+	base := writeSession("app",
+		usageLine(start, "claude-sonnet-4", 100, 20),
+		usageLine(start.Add(time.Minute), "claude-sonnet-4", 200, 40),
+	)
+	defer os.RemoveAll(base) //nolint:errcheck // temporary directory
+
+	logs := logger.Noop()
+	disc := discovery.New([]string{base}, logs)
+	w, err := watcher.New(watcher.Config{}, logs)
+	if err != nil {
+		log.Fatal(err)
+	}
+	r, err := reader.New(reader.Config{
+		PositionStore: reader.NewMemoryPositionStore(),
+		Parser:        parser.New(),
+	}, logs)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer r.Close() //nolint:errcheck // example cleanup
+	defer w.Close() //nolint:errcheck // example cleanup
+
+	mon, err := monitor.New(monitor.Config{RefreshInterval: time.Second}, w, r, disc, logs)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := mon.Start(); err != nil {
+		log.Fatal(err)
+	}
+	defer mon.Stop() //nolint:errcheck // example cleanup
+
+	updates := mon.(interface{ Updates() <-chan monitor.Update }).Updates()
+	update := <-updates
+	fmt.Println("entries:", update.Stats.Count)
+	fmt.Println("tokens:", update.Stats.TotalTokens)
+	// Output:
+	// entries: 2
+	// tokens: 360
+}
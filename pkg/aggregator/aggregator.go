@@ -1,10 +1,12 @@
 package aggregator
 
 import (
+	"fmt"
 	"sort"
 	"sync"
 	"time"
 
+	"github.com/0xmhha/token-monitor/pkg/analysis"
 	"github.com/0xmhha/token-monitor/pkg/parser"
 )
 
@@ -69,6 +71,8 @@ func (a *aggregator) Add(entry parser.UsageEntry) {
 		InputTokens:  input,
 		OutputTokens: output,
 		SessionID:    entry.SessionID,
+		Model:        entry.Message.Model,
+		RateLimited:  entry.APIError().Throttled(),
 	})
 
 	// Update grouped stats.
@@ -192,6 +196,12 @@ func (a *aggregator) TopSessions(n int) []SessionStats {
 		return result[i].Statistics.TotalTokens > result[j].Statistics.TotalTokens
 	})
 
+	// Calculate share of overall totals.
+	for i := range result {
+		result[i].Share = PercentOf(float64(result[i].Statistics.TotalTokens), float64(a.stats.TotalTokens))
+		result[i].CostShare = PercentOf(result[i].Statistics.CostUSD, a.stats.CostUSD)
+	}
+
 	// Return top N.
 	if n > 0 && n < len(result) {
 		result = result[:n]
@@ -200,6 +210,14 @@ func (a *aggregator) TopSessions(n int) []SessionStats {
 	return result
 }
 
+// PercentOf returns part as a percentage of total (0 if total is zero).
+func PercentOf(part, total float64) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return part / total * 100
+}
+
 // Reset implements Aggregator.Reset.
 func (a *aggregator) Reset() {
 	a.mu.Lock()
@@ -289,7 +307,7 @@ func (a *aggregator) BillingBlocks(sessionID string) []BillingBlock {
 	// Group entries by billing block.
 	blocks := make(map[time.Time]*BillingBlock)
 	now := time.Now().UTC()
-	currentBlockStart := getBillingBlockStart(now)
+	currentBlockStart := BillingBlockStart(now)
 
 	for _, entry := range a.entries {
 		// Filter by session if specified.
@@ -297,7 +315,7 @@ func (a *aggregator) BillingBlocks(sessionID string) []BillingBlock {
 			continue
 		}
 
-		blockStart := getBillingBlockStart(entry.Timestamp.UTC())
+		blockStart := BillingBlockStart(entry.Timestamp.UTC())
 		block, exists := blocks[blockStart]
 		if !exists {
 			block = &BillingBlock{
@@ -308,10 +326,7 @@ func (a *aggregator) BillingBlocks(sessionID string) []BillingBlock {
 			blocks[blockStart] = block
 		}
 
-		block.TotalTokens += entry.TotalTokens
-		block.InputTokens += entry.InputTokens
-		block.OutputTokens += entry.OutputTokens
-		block.EntryCount++
+		block.add(entry)
 	}
 
 	// Convert to slice and sort by start time (most recent first).
@@ -333,7 +348,7 @@ func (a *aggregator) CurrentBillingBlock(sessionID string) BillingBlock {
 	defer a.mu.RUnlock()
 
 	now := time.Now().UTC()
-	blockStart := getBillingBlockStart(now)
+	blockStart := BillingBlockStart(now)
 	blockEnd := blockStart.Add(5 * time.Hour)
 
 	block := BillingBlock{
@@ -341,6 +356,7 @@ func (a *aggregator) CurrentBillingBlock(sessionID string) BillingBlock {
 		EndTime:   blockEnd,
 		IsActive:  true,
 	}
+	models := make(map[string]*BlockModel)
 
 	for _, entry := range a.entries {
 		// Filter by session if specified.
@@ -354,18 +370,53 @@ func (a *aggregator) CurrentBillingBlock(sessionID string) BillingBlock {
 			continue
 		}
 
-		block.TotalTokens += entry.TotalTokens
-		block.InputTokens += entry.InputTokens
-		block.OutputTokens += entry.OutputTokens
-		block.EntryCount++
+		block.add(entry)
+
+		m, ok := models[entry.Model]
+		if !ok {
+			m = &BlockModel{Model: entry.Model}
+			models[entry.Model] = m
+		}
+		m.TotalTokens += entry.TotalTokens
+		m.EntryCount++
+		if entry.Timestamp.After(m.LastSeen) {
+			m.LastSeen = entry.Timestamp
+		}
 	}
 
+	for _, m := range models {
+		block.Models = append(block.Models, *m)
+	}
+	sort.Slice(block.Models, func(i, j int) bool {
+		if block.Models[i].TotalTokens != block.Models[j].TotalTokens {
+			return block.Models[i].TotalTokens > block.Models[j].TotalTokens
+		}
+		return block.Models[i].Model < block.Models[j].Model
+	})
+
 	return block
 }
 
-// getBillingBlockStart returns the start time of the 5-hour billing block
+// add adds entry to the block's totals.
+func (b *BillingBlock) add(entry TimestampedEntry) {
+	b.TotalTokens += entry.TotalTokens
+	b.InputTokens += entry.InputTokens
+	b.OutputTokens += entry.OutputTokens
+	b.EntryCount++
+	if entry.Timestamp.After(b.LastSeen) {
+		b.LastSeen = entry.Timestamp
+	}
+	if entry.RateLimited {
+		b.RateLimits++
+		if entry.Timestamp.After(b.LastRateLimit) {
+			b.LastRateLimit = entry.Timestamp
+		}
+	}
+}
+
+// BillingBlockStart returns the start time of the 5-hour billing block
 // that contains the given time. Blocks are aligned to UTC midnight.
-func getBillingBlockStart(t time.Time) time.Time {
+func BillingBlockStart(t time.Time) time.Time {
 	utc := t.UTC()
 	hour := utc.Hour()
 	blockIndex := hour / 5 // 0, 1, 2, 3, 4 for hours 0-4, 5-9, 10-14, 15-19, 20-24
@@ -377,6 +428,32 @@ func getBillingBlockStart(t time.Time) time.Time {
 	)
 }
 
+// BlockIDLayout is the time layout of billing block IDs: the UTC start of
+// the block, e.g. "2025-11-03T1000Z".
+const BlockIDLayout = "2006-01-02T1504Z"
+
+// BlockID returns the ID of the billing block that contains t. IDs are
+// derived from the block start, so they are stable across runs and sort
+// in time order.
+func BlockID(t time.Time) string {
+	return BillingBlockStart(t).Format(BlockIDLayout)
+}
+
+// ID returns the block's ID (see BlockID).
+func (b BillingBlock) ID() string {
+	return BlockID(b.StartTime)
+}
+
+// ParseBlockID returns the start time of the billing block with the given
+// ID.
+func ParseBlockID(id string) (time.Time, error) {
+	start, err := time.Parse(BlockIDLayout, id)
+	if err != nil || !BillingBlockStart(start).Equal(start) {
+		return time.Time{}, fmt.Errorf("invalid billing block ID %q", id)
+	}
+	return start, nil
+}
+
 // updateStats updates statistics with a new entry.
 func (a *aggregator) updateStats(stats *Statistics, entry parser.UsageEntry, total, input, output int) {
 	cacheCreate := entry.Message.Usage.CacheCreationInputTokens
@@ -389,6 +466,15 @@ func (a *aggregator) updateStats(stats *Statistics, entry parser.UsageEntry, tot
 	stats.OutputTokens += output
 	stats.CacheCreationTokens += cacheCreate
 	stats.CacheReadTokens += cacheRead
+	stats.CostUSD += analysis.EntryCost(entry)
+	stats.CO2eGrams += analysis.EntryFootprint(entry, a.config.Footprint)
+	if entry.IsSubAgent() {
+		stats.SubAgentCount++
+		stats.SubAgentTokens += total
+	}
+	if entry.APIError() != "" {
+		stats.ErrorCount++
+	}
 
 	// Update average.
 	stats.AvgTokens = float64(stats.TotalTokens) / float64(stats.Count)
@@ -436,12 +522,29 @@ func (a *aggregator) dimensionKey(entry parser.UsageEntry) string {
 			key += entry.Timestamp.Format("2006-01-02")
 		case DimHour:
 			key += entry.Timestamp.Format("2006-01-02 15:00")
+		case DimSource:
+			key += entry.Source
+		case DimSubAgent:
+			key += SubAgentKey(entry)
 		}
 	}
 
 	return key
 }
 
+// SubAgentKey returns the DimSubAgent key of entry: SubAgentMain,
+// its SubAgentID, or SubAgentSidechain.
+func SubAgentKey(entry parser.UsageEntry) string {
+	switch {
+	case !entry.IsSubAgent():
+		return SubAgentMain
+	case entry.SubAgentID != "":
+		return entry.SubAgentID
+	default:
+		return SubAgentSidechain
+	}
+}
+
 // hasSessionDimension checks if session is one of the dimensions.
 func (a *aggregator) hasSessionDimension() bool {
 	for _, dim := range a.config.GroupBy {
@@ -530,6 +633,8 @@ func (a *aggregator) mergeStats(s1, s2 Statistics) Statistics {
 		OutputTokens:        s1.OutputTokens + s2.OutputTokens,
 		CacheCreationTokens: s1.CacheCreationTokens + s2.CacheCreationTokens,
 		CacheReadTokens:     s1.CacheReadTokens + s2.CacheReadTokens,
+		CostUSD:             s1.CostUSD + s2.CostUSD,
+		CO2eGrams:           s1.CO2eGrams + s2.CO2eGrams,
 	}
 
 	result.AvgTokens = float64(result.TotalTokens) / float64(result.Count)
@@ -1,6 +1,7 @@
 package aggregator
 
 import (
+	"math"
 	"testing"
 	"time"
 
@@ -355,6 +356,14 @@ func TestTopSessions(t *testing.T) {
 	if topSessions[1].Statistics.TotalTokens != 1500 {
 		t.Errorf("TopSessions[1].TotalTokens = %d, want 1500", topSessions[1].Statistics.TotalTokens)
 	}
+
+	// Share is relative to all sessions, not just the top N.
+	if got, want := topSessions[0].Share, 3000.0/5250.0*100; math.Abs(got-want) > 1e-9 {
+		t.Errorf("TopSessions[0].Share = %f, want %f", got, want)
+	}
+	if got, want := topSessions[0].CostShare, 3000.0/5250.0*100; math.Abs(got-want) > 1e-9 {
+		t.Errorf("TopSessions[0].CostShare = %f, want %f", got, want)
+	}
 }
 
 func TestPercentiles(t *testing.T) {
@@ -630,6 +639,141 @@ func TestGroupedStats_ByHour(t *testing.T) {
 	}
 }
 
+func TestGroupedStats_BySource(t *testing.T) {
+	t.Parallel()
+
+	agg := New(Config{GroupBy: []Dimension{DimSource, DimModel}})
+
+	for _, source := range []string{"work", "work", "personal", ""} {
+		agg.Add(parser.UsageEntry{
+			SessionID: "session-1",
+			Timestamp: time.Now(),
+			Source:    source,
+			Message: parser.Message{
+				Model: "claude-3-5-sonnet-20241022",
+				Usage: parser.Usage{InputTokens: 100, OutputTokens: 50},
+			},
+		})
+	}
+
+	grouped := agg.GroupedStats()
+	if len(grouped) != 3 {
+		t.Fatalf("GroupedStats() returned %d groups, want 3", len(grouped))
+	}
+	if got := grouped["work|claude-3-5-sonnet-20241022"].Count; got != 2 {
+		t.Errorf("work Count = %d, want 2", got)
+	}
+	if got := grouped["|claude-3-5-sonnet-20241022"].Count; got != 1 {
+		t.Errorf("unlabeled Count = %d, want 1", got)
+	}
+}
+
+func TestGroupedStats_BySubAgent(t *testing.T) {
+	t.Parallel()
+
+	agg := New(Config{GroupBy: []Dimension{DimSession, DimSubAgent}})
+
+	entries := []parser.UsageEntry{
+		{SessionID: "session-1"},
+		{SessionID: "session-1", SubAgentID: "agent-1"},
+		{SessionID: "session-1", SubAgentID: "agent-1", IsSidechain: true},
+		{SessionID: "session-1", SubAgentID: "agent-2"},
+		// Sidechain entries sharing the session's ID, as Claude Code
+		// writes for Task tool runs.
+		{SessionID: "session-1", IsSidechain: true},
+		{SessionID: "session-1", IsSidechain: true},
+	}
+	for _, entry := range entries {
+		agg.Add(parser.UsageEntry{
+			SessionID:   entry.SessionID,
+			SubAgentID:  entry.SubAgentID,
+			IsSidechain: entry.IsSidechain,
+			Timestamp:   time.Now(),
+			Message: parser.Message{
+				Model: "claude-3-5-sonnet-20241022",
+				Usage: parser.Usage{InputTokens: 100, OutputTokens: 50},
+			},
+		})
+	}
+
+	grouped := agg.GroupedStats()
+	if len(grouped) != 4 {
+		t.Fatalf("GroupedStats() returned %d groups, want 4", len(grouped))
+	}
+	if got := grouped["session-1|agent-1"].Count; got != 2 {
+		t.Errorf("agent-1 Count = %d, want 2", got)
+	}
+	if got := grouped["session-1|"+SubAgentMain].Count; got != 1 {
+		t.Errorf("main Count = %d, want 1", got)
+	}
+	sidechain := grouped["session-1|"+SubAgentSidechain]
+	if sidechain.Count != 2 {
+		t.Errorf("sidechain Count = %d, want 2", sidechain.Count)
+	}
+	// The groups agree with the main/sub-agent split of Stats.
+	if stats := agg.Stats(); stats.SubAgentCount != 5 || grouped["session-1|"+SubAgentMain].SubAgentCount != 0 {
+		t.Errorf("SubAgentCount = %d (main group %d), want 5 (0)", stats.SubAgentCount, grouped["session-1|"+SubAgentMain].SubAgentCount)
+	}
+}
+
+func TestStats_SubAgentUsage(t *testing.T) {
+	t.Parallel()
+
+	agg := New(Config{})
+
+	entries := []parser.UsageEntry{
+		{SessionID: "session-1"},
+		{SessionID: "session-1", IsSidechain: true},
+		{SessionID: "session-1", SubAgentID: "agent-1"},
+	}
+	for _, entry := range entries {
+		entry.Timestamp = time.Now()
+		entry.Message = parser.Message{
+			Model: "claude-3-5-sonnet-20241022",
+			Usage: parser.Usage{InputTokens: 100, OutputTokens: 50},
+		}
+		agg.Add(entry)
+	}
+
+	stats := agg.Stats()
+	if stats.SubAgentCount != 2 || stats.SubAgentTokens != 300 {
+		t.Errorf("sub-agent usage = %d entries, %d tokens; want 2, 300", stats.SubAgentCount, stats.SubAgentTokens)
+	}
+	if stats.TotalTokens != 450 {
+		t.Errorf("TotalTokens = %d, want 450", stats.TotalTokens)
+	}
+}
+
+func TestStats_ErrorCount(t *testing.T) {
+	t.Parallel()
+
+	agg := New(Config{})
+	text := "API Error: 529 Overloaded"
+	for i := 0; i < 4; i++ {
+		entry := parser.UsageEntry{
+			SessionID: "session-1",
+			Timestamp: time.Now(),
+			Message:   parser.Message{Model: "claude-3-5-sonnet-20241022", Usage: parser.Usage{InputTokens: 100}},
+		}
+		if i == 0 {
+			entry.IsAPIErrorMessage = true
+			entry.Message = parser.Message{Model: "<synthetic>", Content: []parser.Content{{Type: "text", Text: &text}}}
+		}
+		agg.Add(entry)
+	}
+
+	stats := agg.Stats()
+	if stats.ErrorCount != 1 {
+		t.Errorf("ErrorCount = %d, want 1", stats.ErrorCount)
+	}
+	if got := stats.ErrorRate(); got != 25 {
+		t.Errorf("ErrorRate() = %f, want 25", got)
+	}
+	if got := (Statistics{}).ErrorRate(); got != 0 {
+		t.Errorf("empty ErrorRate() = %f, want 0", got)
+	}
+}
+
 func TestAdd_CacheTokens(t *testing.T) {
 	t.Parallel()
 
@@ -685,6 +829,50 @@ func TestAdd_CacheTokens(t *testing.T) {
 	}
 }
 
+func TestAdd_CostAndRequestRate(t *testing.T) {
+	t.Parallel()
+
+	agg := New(Config{GroupBy: []Dimension{DimModel}})
+
+	base := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	agg.Add(parser.UsageEntry{
+		SessionID: "session-1",
+		Timestamp: base,
+		Message: parser.Message{
+			Model: "claude-sonnet-4-6",
+			Usage: parser.Usage{InputTokens: 1_000_000},
+		},
+	})
+	agg.Add(parser.UsageEntry{
+		SessionID: "session-1",
+		Timestamp: base.Add(48 * time.Hour),
+		Message: parser.Message{
+			Model: "claude-opus-4-7",
+			Usage: parser.Usage{OutputTokens: 100_000},
+		},
+	})
+
+	stats := agg.Stats()
+	// 1M sonnet input at $3/MTok + 100K opus output at $75/MTok.
+	if diff := stats.CostUSD - 10.5; diff > 1e-9 || diff < -1e-9 {
+		t.Errorf("CostUSD = %f, want 10.5", stats.CostUSD)
+	}
+	if got := stats.RequestsPerDay(); got != 1 {
+		t.Errorf("RequestsPerDay() = %f, want 1", got)
+	}
+
+	grouped := agg.GroupedStats()
+	if got := grouped["claude-sonnet-4-6"].CostUSD; got != 3 {
+		t.Errorf("sonnet CostUSD = %f, want 3", got)
+	}
+	if got := grouped["claude-opus-4-7"].RequestsPerDay(); got != 1 {
+		t.Errorf("opus RequestsPerDay() = %f, want 1 (span under a day)", got)
+	}
+	if got := (Statistics{}).RequestsPerDay(); got != 0 {
+		t.Errorf("empty RequestsPerDay() = %f, want 0", got)
+	}
+}
+
 func TestBurnRate_EmptyAggregator(t *testing.T) {
 	t.Parallel()
 
@@ -1088,7 +1276,68 @@ func TestCurrentBillingBlock(t *testing.T) {
 	}
 }
 
-func TestGetBillingBlockStart(t *testing.T) {
+func TestCurrentBillingBlock_Models(t *testing.T) {
+	t.Parallel()
+
+	agg := New(Config{})
+
+	// Stay inside the current block, whose start is at most 5 hours ago.
+	now := time.Now()
+	start := BillingBlockStart(now)
+	add := func(model string, at time.Time, tokens int) {
+		agg.Add(parser.UsageEntry{
+			SessionID: "session-1",
+			Timestamp: at,
+			Message:   parser.Message{Model: model, Usage: parser.Usage{InputTokens: tokens}},
+		})
+	}
+	add("claude-sonnet-4", start, 100)
+	add("claude-opus-4", start, 300)
+	add("claude-sonnet-4", now, 100)
+	add("claude-opus-4", start.Add(-time.Hour), 1000) // previous block
+
+	models := agg.CurrentBillingBlock("").Models
+	if len(models) != 2 {
+		t.Fatalf("CurrentBillingBlock.Models = %+v, want 2 models", models)
+	}
+	if models[0].Model != "claude-opus-4" || models[0].TotalTokens != 300 || models[0].EntryCount != 1 {
+		t.Errorf("Models[0] = %+v, want opus with 300 tokens", models[0])
+	}
+	if models[1].Model != "claude-sonnet-4" || models[1].TotalTokens != 200 || !models[1].LastSeen.Equal(now) {
+		t.Errorf("Models[1] = %+v, want sonnet with 200 tokens last seen now", models[1])
+	}
+}
+
+func TestCurrentBillingBlock_RateLimits(t *testing.T) {
+	t.Parallel()
+
+	agg := New(Config{})
+
+	now := time.Now()
+	start := BillingBlockStart(now)
+	add := func(at time.Time, text string) {
+		agg.Add(parser.UsageEntry{
+			SessionID:         "session-1",
+			Timestamp:         at,
+			IsAPIErrorMessage: true,
+			Message:           parser.Message{Model: "<synthetic>", Content: []parser.Content{{Type: "text", Text: &text}}},
+		})
+	}
+	add(start, "API Error: 529 Overloaded")
+	add(now, "API Error: 429 rate_limit_error")
+	add(now, "API Error: Request was aborted.")             // not throttled
+	add(start.Add(-time.Hour), "API Error: 529 Overloaded") // previous block
+
+	block := agg.CurrentBillingBlock("")
+	if block.RateLimits != 2 || !block.LastRateLimit.Equal(now) {
+		t.Errorf("RateLimits = %d, last %v; want 2, last now", block.RateLimits, block.LastRateLimit)
+	}
+	if blocks := agg.BillingBlocks(""); len(blocks) != 2 || blocks[1].RateLimits != 1 {
+		t.Errorf("BillingBlocks() = %+v, want 1 rate limit in the previous block", blocks)
+	}
+}
+
+func TestBillingBlockStart(t *testing.T) {
 	t.Parallel()
 
 	tests := []struct {
@@ -1110,10 +1359,34 @@ func TestGetBillingBlockStart(t *testing.T) {
 
 	for _, tc := range tests {
 		input := time.Date(2024, 1, 15, tc.hour, 30, 0, 0, time.UTC)
-		result := getBillingBlockStart(input)
+		result := BillingBlockStart(input)
 		if result.Hour() != tc.expected {
-			t.Errorf("getBillingBlockStart(%d:30) = %d:00, want %d:00",
+			t.Errorf("BillingBlockStart(%d:30) = %d:00, want %d:00",
 				tc.hour, result.Hour(), tc.expected)
 		}
 	}
 }
+
+func TestBlockID(t *testing.T) {
+	t.Parallel()
+
+	at := time.Date(2024, 1, 15, 12, 45, 0, 0, time.FixedZone("KST", 9*60*60))
+	id := BlockID(at)
+	if id != "2024-01-15T0000Z" {
+		t.Errorf("BlockID(%v) = %q, want 2024-01-15T0000Z", at, id)
+	}
+
+	start, err := ParseBlockID(id)
+	if err != nil {
+		t.Fatalf("ParseBlockID(%q) error: %v", id, err)
+	}
+	if !start.Equal(BillingBlockStart(at)) {
+		t.Errorf("ParseBlockID(%q) = %v, want %v", id, start, BillingBlockStart(at))
+	}
+
+	for _, bad := range []string{"", "2024-01-15", "2024-01-15T0100Z"} {
+		if _, err := ParseBlockID(bad); err == nil {
+			t.Errorf("ParseBlockID(%q) succeeded, want error", bad)
+		}
+	}
+}
@@ -1,7 +1,9 @@
 package aggregator
 
 import (
+	"fmt"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"time"
 
@@ -70,6 +72,82 @@ func FilterByModelGlob(entries []parser.UsageEntry, glob string) []parser.UsageE
 	return out
 }
 
+// ModelFilter matches models against a set of patterns.
+// A model is accepted if it matches any pattern. A nil or empty filter
+// accepts every model.
+type ModelFilter struct {
+	globs   []string
+	regexps []*regexp.Regexp
+}
+
+// ParseModelFilter parses a comma-separated list of model patterns.
+// Each pattern is either a glob (see MatchModel) or a regular expression
+// enclosed in slashes, e.g. "/opus-4-[67]/". Regular expressions are
+// case-insensitive and unanchored. Returns nil for an empty spec.
+func ParseModelFilter(spec string) (*ModelFilter, error) {
+	f := &ModelFilter{}
+	for _, raw := range strings.Split(spec, ",") {
+		pattern := strings.Trim(strings.TrimSpace(raw), `"'`)
+		if pattern == "" {
+			continue
+		}
+
+		if len(pattern) > 2 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/") {
+			re, err := regexp.Compile("(?i)" + pattern[1:len(pattern)-1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid model regex %q: %w", pattern, err)
+			}
+			f.regexps = append(f.regexps, re)
+			continue
+		}
+
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid model pattern %q: %w", pattern, err)
+		}
+		f.globs = append(f.globs, pattern)
+	}
+
+	if f.Empty() {
+		return nil, nil
+	}
+	return f, nil
+}
+
+// Empty reports whether the filter has no patterns.
+func (f *ModelFilter) Empty() bool {
+	return f == nil || (len(f.globs) == 0 && len(f.regexps) == 0)
+}
+
+// Match reports whether model matches any pattern in the filter.
+func (f *ModelFilter) Match(model string) bool {
+	if f.Empty() {
+		return true
+	}
+	for _, glob := range f.globs {
+		if MatchModel(model, glob) {
+			return true
+		}
+	}
+	for _, re := range f.regexps {
+		if re.MatchString(model) {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns the filter patterns joined by commas.
+func (f *ModelFilter) String() string {
+	if f.Empty() {
+		return ""
+	}
+	parts := append([]string{}, f.globs...)
+	for _, re := range f.regexps {
+		parts = append(parts, "/"+strings.TrimPrefix(re.String(), "(?i)")+"/")
+	}
+	return strings.Join(parts, ",")
+}
+
 // FilterSince returns entries with Timestamp >= since.
 // A zero time.Time{} cutoff includes all entries (used for "all" window).
 func FilterSince(entries []parser.UsageEntry, since time.Time) []parser.UsageEntry {
@@ -81,3 +159,28 @@ func FilterSince(entries []parser.UsageEntry, since time.Time) []parser.UsageEnt
 	}
 	return out
 }
+
+// DailyTokens returns total tokens per calendar day for the `days` days
+// ending on the day containing `now` (in now's location), oldest first.
+// Entries outside the range are ignored.
+func DailyTokens(entries []parser.UsageEntry, now time.Time, days int) []int {
+	if days <= 0 {
+		return nil
+	}
+	out := make([]int, days)
+	loc := now.Location()
+	end := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	start := end.AddDate(0, 0, -(days - 1))
+	for _, e := range entries {
+		ts := e.Timestamp.In(loc)
+		day := time.Date(ts.Year(), ts.Month(), ts.Day(), 0, 0, 0, 0, loc)
+		if day.Before(start) || day.After(end) {
+			continue
+		}
+		idx := int(day.Sub(start).Hours()/24 + 0.5)
+		if idx >= 0 && idx < days {
+			out[idx] += e.Message.Usage.TotalTokens()
+		}
+	}
+	return out
+}
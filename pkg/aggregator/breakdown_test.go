@@ -188,6 +188,67 @@ func TestFilterByModelGlob_FiltersByPattern(t *testing.T) {
 	}
 }
 
+func TestParseModelFilter(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name  string
+		spec  string
+		model string
+		want  bool
+	}{
+		{"empty-spec-matches-anything", "", "claude-opus-4-7", true},
+		{"exact-value", "claude-haiku-3-5", "claude-haiku-3-5", true},
+		{"exact-value-no-partial-match", "claude-haiku", "claude-haiku-3-5", false},
+		{"multiple-globs-first", "claude-3-5*,*opus*", "claude-3-5-sonnet-20241022", true},
+		{"multiple-globs-second", "claude-3-5*,*opus*", "claude-opus-4-7", true},
+		{"multiple-globs-no-match", "claude-3-5*,*opus*", "claude-sonnet-4-6", false},
+		{"quoted-values", `"claude-3-5*","*opus*"`, "claude-opus-4-7", true},
+		{"whitespace-trimmed", " *haiku* , *opus* ", "claude-haiku-3-5", true},
+		{"regex", "/opus-4-[67]/", "claude-opus-4-7", true},
+		{"regex-case-insensitive", "/OPUS/", "claude-opus-4-7", true},
+		{"regex-no-match", "/^opus/", "claude-opus-4-7", false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			f, err := ParseModelFilter(tc.spec)
+			if err != nil {
+				t.Fatalf("ParseModelFilter(%q) error = %v", tc.spec, err)
+			}
+			if got := f.Match(tc.model); got != tc.want {
+				t.Errorf("ParseModelFilter(%q).Match(%q) = %v, want %v", tc.spec, tc.model, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseModelFilter_InvalidPatterns(t *testing.T) {
+	t.Parallel()
+
+	for _, spec := range []string{"[abc", "/(unclosed/", "*sonnet*,[bad"} {
+		if _, err := ParseModelFilter(spec); err == nil {
+			t.Errorf("ParseModelFilter(%q): want error, got nil", spec)
+		}
+	}
+}
+
+func TestParseModelFilter_EmptyReturnsNil(t *testing.T) {
+	t.Parallel()
+
+	f, err := ParseModelFilter(" , ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if f != nil {
+		t.Errorf("want nil filter for empty spec, got %v", f)
+	}
+	if f.String() != "" {
+		t.Errorf("want empty String() for nil filter, got %q", f.String())
+	}
+}
+
 func TestFilterSince_CutoffExact(t *testing.T) {
 	t.Parallel()
 
@@ -223,3 +284,31 @@ func TestFilterSince_ZeroCutoffIncludesAll(t *testing.T) {
 		t.Errorf("zero cutoff: want all %d entries, got %d", len(entries), len(got))
 	}
 }
+
+func TestDailyTokens(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 5, 7, 15, 0, 0, 0, time.UTC)
+	entries := []parser.UsageEntry{
+		makeEntry("m", now.Add(-1*time.Hour), 10, 0, 0, 0),  // today
+		makeEntry("m", now.AddDate(0, 0, -1), 5, 5, 0, 0),   // yesterday
+		makeEntry("m", now.AddDate(0, 0, -6), 7, 0, 0, 0),   // oldest day in range
+		makeEntry("m", now.AddDate(0, 0, -7), 100, 0, 0, 0), // out of range
+		makeEntry("m", now.Add(24*time.Hour), 100, 0, 0, 0), // future: ignored
+	}
+
+	got := DailyTokens(entries, now, 7)
+	want := []int{7, 0, 0, 0, 0, 10, 10}
+	if len(got) != len(want) {
+		t.Fatalf("want %d days, got %d", len(want), len(got))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("day %d: want %d, got %d", i, want[i], got[i])
+		}
+	}
+
+	if DailyTokens(entries, now, 0) != nil {
+		t.Error("want nil for zero days")
+	}
+}
@@ -0,0 +1,53 @@
+package aggregator
+
+import "time"
+
+// BlockPace compares a billing block's token use with the time elapsed,
+// for burn-down displays.
+type BlockPace struct {
+	// Limit is the token quota the block is measured against.
+	Limit int
+
+	// Elapsed is the fraction of the block's duration gone, from 0 to 1.
+	Elapsed float64
+
+	// Used is the fraction of Limit used; above 1 once the quota is
+	// exceeded.
+	Used float64
+}
+
+// OverPace reports whether the quota is being used faster than the block
+// passes, so at this pace it runs out before the block ends.
+func (p BlockPace) OverPace() bool {
+	return p.Used > p.Elapsed
+}
+
+// Pace returns the pace of the block against limit at now. It returns
+// false when limit is not positive, as there is nothing to burn down.
+func (b BillingBlock) Pace(limit int, now time.Time) (BlockPace, bool) {
+	if limit <= 0 {
+		return BlockPace{}, false
+	}
+
+	pace := BlockPace{
+		Limit: limit,
+		Used:  float64(b.TotalTokens) / float64(limit),
+	}
+	if length := b.EndTime.Sub(b.StartTime); length > 0 {
+		pace.Elapsed = min(1, max(0, float64(now.Sub(b.StartTime))/float64(length)))
+	}
+	return pace, true
+}
+
+// PeakBlockTokens returns the highest token total among the blocks that
+// are not active, or 0 if there are none. It stands in for a block quota
+// when none is configured.
+func PeakBlockTokens(blocks []BillingBlock) int {
+	peak := 0
+	for _, b := range blocks {
+		if !b.IsActive && b.TotalTokens > peak {
+			peak = b.TotalTokens
+		}
+	}
+	return peak
+}
@@ -0,0 +1,61 @@
+package aggregator
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBillingBlockPace(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2025, 11, 3, 10, 0, 0, 0, time.UTC)
+	block := BillingBlock{StartTime: start, EndTime: start.Add(5 * time.Hour), TotalTokens: 300}
+
+	if _, ok := block.Pace(0, start); ok {
+		t.Error("Pace(0) ok = true, want false without a limit")
+	}
+
+	tests := []struct {
+		name    string
+		now     time.Time
+		limit   int
+		elapsed float64
+		used    float64
+		over    bool
+	}{
+		{"ahead of pace", start.Add(4 * time.Hour), 1000, 0.8, 0.3, false},
+		{"over pace", start.Add(time.Hour), 1000, 0.2, 0.3, true},
+		{"quota exceeded", start.Add(4 * time.Hour), 200, 0.8, 1.5, true},
+		{"before start", start.Add(-time.Hour), 1000, 0, 0.3, true},
+		{"after end", start.Add(6 * time.Hour), 1000, 1, 0.3, false},
+	}
+	for _, tt := range tests {
+		pace, ok := block.Pace(tt.limit, tt.now)
+		if !ok {
+			t.Fatalf("%s: Pace() ok = false", tt.name)
+		}
+		if pace.Limit != tt.limit || pace.Elapsed != tt.elapsed || pace.Used != tt.used {
+			t.Errorf("%s: Pace() = %+v, want elapsed %v, used %v", tt.name, pace, tt.elapsed, tt.used)
+		}
+		if pace.OverPace() != tt.over {
+			t.Errorf("%s: OverPace() = %v, want %v", tt.name, pace.OverPace(), tt.over)
+		}
+	}
+}
+
+func TestPeakBlockTokens(t *testing.T) {
+	t.Parallel()
+
+	if got := PeakBlockTokens(nil); got != 0 {
+		t.Errorf("PeakBlockTokens(nil) = %d, want 0", got)
+	}
+
+	blocks := []BillingBlock{
+		{TotalTokens: 9000, IsActive: true}, // the current block is not a reference
+		{TotalTokens: 500},
+		{TotalTokens: 1200},
+	}
+	if got := PeakBlockTokens(blocks); got != 1200 {
+		t.Errorf("PeakBlockTokens() = %d, want 1200", got)
+	}
+}
@@ -23,6 +23,7 @@ package aggregator
 import (
 	"time"
 
+	"github.com/0xmhha/token-monitor/pkg/analysis"
 	"github.com/0xmhha/token-monitor/pkg/parser"
 )
 
@@ -41,6 +42,21 @@ const (
 
 	// DimHour aggregates by hour (YYYY-MM-DD HH:00).
 	DimHour Dimension = "hour"
+
+	// DimSource aggregates by Claude directory label (empty if unlabeled).
+	DimSource Dimension = "source"
+
+	// DimSubAgent aggregates by sub-agent, as parser.UsageEntry.IsSubAgent
+	// classifies entries: SubAgentMain for the main conversation, the
+	// embedded SubAgentID, or SubAgentSidechain for sidechain entries
+	// without one.
+	DimSubAgent Dimension = "subagent"
+)
+
+// Keys of DimSubAgent for entries without a sub-agent ID.
+const (
+	SubAgentMain      = "main"
+	SubAgentSidechain = "sidechain"
 )
 
 // Aggregator computes token usage statistics.
@@ -129,9 +145,27 @@ type Statistics struct {
 	// CacheReadTokens is the sum of all cache-read tokens.
 	CacheReadTokens int
 
+	// SubAgentCount is the number of sub-agent entries
+	// (parser.UsageEntry.IsSubAgent).
+	SubAgentCount int `json:",omitempty"`
+
+	// SubAgentTokens is the sum of all tokens of sub-agent entries.
+	SubAgentTokens int `json:",omitempty"`
+
+	// ErrorCount is the number of entries recording a failed request
+	// (parser.UsageEntry.APIError): API errors, rate limits, and aborts.
+	ErrorCount int `json:",omitempty"`
+
 	// AvgTokens is the average tokens per entry.
 	AvgTokens float64
 
+	// CostUSD is the estimated API cost of all entries.
+	CostUSD float64
+
+	// CO2eGrams is the estimated carbon footprint of all entries. It is
+	// zero unless Config.Footprint is set.
+	CO2eGrams float64 `json:",omitempty"`
+
 	// MinTokens is the minimum tokens in any entry.
 	MinTokens int
 
@@ -154,6 +188,29 @@ type Statistics struct {
 	LastSeen time.Time
 }
 
+// RequestsPerDay returns the average number of entries per day between
+// FirstSeen and LastSeen. Spans shorter than a day count as one day.
+func (s Statistics) RequestsPerDay() float64 {
+	if s.Count == 0 {
+		return 0
+	}
+
+	days := s.LastSeen.Sub(s.FirstSeen).Hours() / 24
+	if days < 1 {
+		days = 1
+	}
+
+	return float64(s.Count) / days
+}
+
+// ErrorRate returns the percentage of entries recording a failed request.
+func (s Statistics) ErrorRate() float64 {
+	if s.Count == 0 {
+		return 0
+	}
+	return float64(s.ErrorCount) * 100 / float64(s.Count)
+}
+
 // SessionStats contains statistics for a single session.
 type SessionStats struct {
 	// SessionID is the session identifier.
@@ -164,6 +221,12 @@ type SessionStats struct {
 
 	// Statistics contains aggregated stats for this session.
 	Statistics Statistics
+
+	// Share is the session's percentage of all tokens (0-100).
+	Share float64
+
+	// CostShare is the session's percentage of total estimated cost (0-100).
+	CostShare float64
 }
 
 // BurnRate contains token consumption rate metrics.
@@ -200,6 +263,11 @@ type TimestampedEntry struct {
 	InputTokens  int
 	OutputTokens int
 	SessionID    string
+	Model        string
+
+	// RateLimited marks a request turned away by a rate limit or an
+	// overloaded API (parser.APIErrorKind.Throttled).
+	RateLimited bool
 }
 
 // BillingBlock represents a 5-hour billing window for Claude API.
@@ -223,8 +291,35 @@ type BillingBlock struct {
 	// EntryCount is the number of entries in this block.
 	EntryCount int
 
+	// RateLimits is the number of requests in this block turned away by a
+	// rate limit or an overloaded API; LastRateLimit is the latest one.
+	RateLimits    int
+	LastRateLimit time.Time
+
+	// LastSeen is the time of the block's latest entry.
+	LastSeen time.Time
+
 	// IsActive indicates if this is the current billing block.
 	IsActive bool
+
+	// Models breaks the block down by model, most tokens first. Only
+	// CurrentBillingBlock fills it in.
+	Models []BlockModel
+}
+
+// BlockModel is one model's usage within a billing block.
+type BlockModel struct {
+	// Model is the model name.
+	Model string
+
+	// TotalTokens used by the model in the block.
+	TotalTokens int
+
+	// EntryCount is the number of the model's entries in the block.
+	EntryCount int
+
+	// LastSeen is the time of the model's latest entry in the block.
+	LastSeen time.Time
 }
 
 // Config contains aggregator configuration.
@@ -246,4 +341,9 @@ type Config struct {
 	//
 	// Default: true.
 	TrackPercentiles bool
+
+	// Footprint holds the rates for CO2eGrams.
+	//
+	// Default: nil (no footprint estimate).
+	Footprint analysis.FootprintRates
 }
@@ -0,0 +1,50 @@
+package analysis
+
+import "github.com/0xmhha/token-monitor/pkg/parser"
+
+// DefaultContextWindow is the context window of the Claude models in
+// tokens. Sessions on a larger window (the 1M-token beta) set
+// monitoring.context_window instead.
+const DefaultContextWindow = 200_000
+
+// ContextWarnPercent is the fill level from which the context estimate is
+// shown as a warning: Claude Code compacts or truncates the conversation
+// not far above it.
+const ContextWarnPercent = 80
+
+// ContextUsage estimates how full a session's context window is.
+type ContextUsage struct {
+	Model  string `json:"model"`
+	Tokens int    `json:"tokens"`
+	Window int    `json:"window"`
+}
+
+// EstimateContext estimates the context window use after entry, the
+// latest request of a conversation: everything the request sent (input,
+// cache creation, and cache read tokens) plus its response, which the next
+// request sends back. window <= 0 means DefaultContextWindow.
+func EstimateContext(entry parser.UsageEntry, window int) ContextUsage {
+	if window <= 0 {
+		window = DefaultContextWindow
+	}
+	u := entry.Message.Usage
+	return ContextUsage{
+		Model:  entry.Message.Model,
+		Tokens: u.InputTokens + u.CacheCreationInputTokens + u.CacheReadInputTokens + u.OutputTokens,
+		Window: window,
+	}
+}
+
+// Percent returns the estimated fill level, 0–100 (more when the window
+// is set too small).
+func (c ContextUsage) Percent() float64 {
+	if c.Window <= 0 {
+		return 0
+	}
+	return float64(c.Tokens) * 100 / float64(c.Window)
+}
+
+// Warn reports whether the fill level has reached ContextWarnPercent.
+func (c ContextUsage) Warn() bool {
+	return c.Percent() >= ContextWarnPercent
+}
@@ -0,0 +1,26 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/0xmhha/token-monitor/pkg/parser"
+)
+
+func TestEstimateContext(t *testing.T) {
+	entry := parser.UsageEntry{Message: parser.Message{Model: "claude-sonnet-4", Usage: parser.Usage{
+		InputTokens: 4_000, CacheCreationInputTokens: 20_000, CacheReadInputTokens: 100_000, OutputTokens: 1_000,
+	}}}
+
+	c := EstimateContext(entry, 0)
+	assert.Equal(t, ContextUsage{Model: "claude-sonnet-4", Tokens: 125_000, Window: DefaultContextWindow}, c)
+	assert.InDelta(t, 62.5, c.Percent(), 0.001)
+	assert.False(t, c.Warn())
+
+	c = EstimateContext(entry, 150_000)
+	assert.InDelta(t, 83.3, c.Percent(), 0.1)
+	assert.True(t, c.Warn())
+
+	assert.Zero(t, ContextUsage{}.Percent())
+}
@@ -1,22 +1,24 @@
 package analysis
 
-import "strings"
+import (
+	"github.com/0xmhha/token-monitor/pkg/parser"
+)
 
 // Known model pricing (per million tokens, as of 2025).
 var knownPricing = map[string]ModelPricing{
-	"sonnet": {
+	ClassSonnet: {
 		InputPerMTok:      3.0,
 		OutputPerMTok:     15.0,
 		CacheWritePerMTok: 3.75,
 		CacheReadPerMTok:  0.30,
 	},
-	"opus": {
+	ClassOpus: {
 		InputPerMTok:      15.0,
 		OutputPerMTok:     75.0,
 		CacheWritePerMTok: 18.75,
 		CacheReadPerMTok:  1.50,
 	},
-	"haiku": {
+	ClassHaiku: {
 		InputPerMTok:      0.80,
 		OutputPerMTok:     4.0,
 		CacheWritePerMTok: 1.0,
@@ -26,22 +28,14 @@ var knownPricing = map[string]ModelPricing{
 
 // LookupPricing finds pricing for a model name by matching known model families.
 func LookupPricing(modelName string) ModelPricing {
-	lower := strings.ToLower(modelName)
-
-	switch {
-	case strings.Contains(lower, "opus"):
-		return knownPricing["opus"]
-	case strings.Contains(lower, "haiku"):
-		return knownPricing["haiku"]
-	default:
-		return knownPricing["sonnet"]
-	}
+	return knownPricing[ModelClass(modelName)]
 }
 
 // EstimateCost calculates the estimated API cost for a session.
-// Uses per-turn calculation when multiple models are used.
+// Uses per-turn calculation when multiple models are used or pricing
+// overrides are set.
 func EstimateCost(a SessionAnalysis) float64 {
-	if len(a.Models) <= 1 {
+	if !perTurn(a) {
 		var modelName string
 		for m := range a.Models {
 			modelName = m
@@ -52,7 +46,7 @@ func EstimateCost(a SessionAnalysis) float64 {
 
 	var total float64
 	for _, turn := range a.Turns {
-		pricing := LookupPricing(turn.Model)
+		pricing := PricingAt(turn.Model, turn.Timestamp)
 		total += tokenCost(turn.InputTokens, turn.OutputTokens, turn.CacheCreation, turn.CacheRead, pricing)
 	}
 	return total
@@ -60,7 +54,7 @@ func EstimateCost(a SessionAnalysis) float64 {
 
 // CostBreakdown returns per-component cost for display.
 func CostBreakdown(a SessionAnalysis) (input, output, cacheWrite, cacheRead float64) {
-	if len(a.Models) <= 1 {
+	if !perTurn(a) {
 		var modelName string
 		for m := range a.Models {
 			modelName = m
@@ -73,7 +67,7 @@ func CostBreakdown(a SessionAnalysis) (input, output, cacheWrite, cacheRead floa
 	}
 
 	for _, turn := range a.Turns {
-		p := LookupPricing(turn.Model)
+		p := PricingAt(turn.Model, turn.Timestamp)
 		input += float64(turn.InputTokens) * p.InputPerMTok / 1_000_000
 		output += float64(turn.OutputTokens) * p.OutputPerMTok / 1_000_000
 		cacheWrite += float64(turn.CacheCreation) * p.CacheWritePerMTok / 1_000_000
@@ -82,6 +76,20 @@ func CostBreakdown(a SessionAnalysis) (input, output, cacheWrite, cacheRead floa
 	return
 }
 
+// perTurn reports whether the cost of a must be computed per turn: the
+// session used several models, or overrides may price turns differently.
+func perTurn(a SessionAnalysis) bool {
+	return len(a.Models) > 1 || len(CurrentPriceRules()) > 0
+}
+
+// EntryCost calculates the estimated API cost of a single usage entry,
+// applying the pricing overrides set with SetPriceRules.
+func EntryCost(entry parser.UsageEntry) float64 {
+	u := entry.Message.Usage
+	pricing := PricingAt(entry.Message.Model, entry.Timestamp)
+	return tokenCost(u.InputTokens, u.OutputTokens, u.CacheCreationInputTokens, u.CacheReadInputTokens, pricing)
+}
+
 func tokenCost(input, output, cacheCreate, cacheRead int, p ModelPricing) float64 {
 	return (float64(input)*p.InputPerMTok +
 		float64(output)*p.OutputPerMTok +
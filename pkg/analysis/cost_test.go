@@ -2,8 +2,11 @@ package analysis
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/0xmhha/token-monitor/pkg/parser"
 )
 
 func TestLookupPricing_ModelFamilies(t *testing.T) {
@@ -95,3 +98,53 @@ func TestCostBreakdown(t *testing.T) {
 	assert.InDelta(t, 0.75, cacheWrite, 0.01) // 200K * 3.75 / 1M
 	assert.InDelta(t, 0.15, cacheRead, 0.01)  // 500K * 0.30 / 1M
 }
+
+func TestFootprintRates_Grams(t *testing.T) {
+	rates := FootprintRates{ClassOpus: 400, ClassDefault: 100}
+
+	assert.InDelta(t, 200.0, rates.Grams("claude-opus-4-20250514", 500_000), 1e-9)
+	assert.InDelta(t, 50.0, rates.Grams("claude-haiku-3-5", 500_000), 1e-9, "default rate")
+	assert.Zero(t, FootprintRates(nil).Grams("claude-opus-4", 1_000_000), "disabled")
+	assert.Zero(t, FootprintRates{ClassOpus: 400}.Grams("claude-sonnet-4", 1_000_000), "no rate")
+}
+
+func TestPriceRules_Pricing(t *testing.T) {
+	legacy := 20.0
+	rules := PriceRules{
+		{Model: "opus", Until: time.Date(2025, 11, 24, 0, 0, 0, 0, time.UTC), InputPerMTok: &legacy, Multiplier: 1},
+		{Multiplier: 0.5},
+	}
+	before := time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC)
+	after := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+
+	p := rules.Pricing("claude-opus-4", before)
+	assert.Equal(t, 10.0, p.InputPerMTok)  // legacy price, discounted
+	assert.Equal(t, 37.5, p.OutputPerMTok) // built-in price, discounted
+
+	p = rules.Pricing("claude-opus-4", after)
+	assert.Equal(t, 7.5, p.InputPerMTok)
+
+	p = rules.Pricing("claude-sonnet-4", before)
+	assert.Equal(t, 1.5, p.InputPerMTok)
+
+	assert.Equal(t, LookupPricing("claude-opus-4"), PriceRules(nil).Pricing("claude-opus-4", before))
+}
+
+func TestEntryCost_PriceRules(t *testing.T) {
+	entry := parser.UsageEntry{
+		Timestamp: time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC),
+		Message: parser.Message{
+			Model: "claude-sonnet-4",
+			Usage: parser.Usage{InputTokens: 1_000_000},
+		},
+	}
+	assert.Equal(t, 3.0, EntryCost(entry))
+
+	discount := PriceRules{{Model: "sonnet", From: time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC), Multiplier: 0.5}}
+	SetPriceRules(discount)
+	defer SetPriceRules(nil)
+	assert.Equal(t, 1.5, EntryCost(entry))
+
+	entry.Timestamp = time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, 3.0, EntryCost(entry))
+}
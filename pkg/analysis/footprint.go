@@ -0,0 +1,53 @@
+package analysis
+
+import (
+	"strings"
+
+	"github.com/0xmhha/token-monitor/pkg/parser"
+)
+
+// Model classes used to look up pricing and footprint rates.
+const (
+	ClassOpus   = "opus"
+	ClassSonnet = "sonnet"
+	ClassHaiku  = "haiku"
+
+	// ClassDefault keys the footprint rate for classes without their own.
+	ClassDefault = "default"
+)
+
+// ModelClass returns the class of a model name: opus, haiku, or sonnet
+// for anything else.
+func ModelClass(modelName string) string {
+	lower := strings.ToLower(modelName)
+
+	switch {
+	case strings.Contains(lower, ClassOpus):
+		return ClassOpus
+	case strings.Contains(lower, ClassHaiku):
+		return ClassHaiku
+	default:
+		return ClassSonnet
+	}
+}
+
+// FootprintRates maps model classes to estimated grams of CO2e per
+// million tokens. ClassDefault applies to classes without a rate. A nil
+// or empty map disables footprint estimates.
+type FootprintRates map[string]float64
+
+// Grams estimates the CO2e of tokens processed by model. It returns zero
+// when no rate applies.
+func (r FootprintRates) Grams(model string, tokens int) float64 {
+	rate, ok := r[ModelClass(model)]
+	if !ok {
+		rate = r[ClassDefault]
+	}
+	return float64(tokens) * rate / 1_000_000
+}
+
+// EntryFootprint estimates the CO2e of a single usage entry in grams,
+// counting all of its tokens.
+func EntryFootprint(entry parser.UsageEntry, rates FootprintRates) float64 {
+	return rates.Grams(entry.Message.Model, entry.Message.Usage.TotalTokens())
+}
@@ -0,0 +1,119 @@
+package analysis
+
+import (
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// PriceRule overrides the built-in pricing of the entries it matches, for
+// example legacy prices before a date or a negotiated discount.
+type PriceRule struct {
+	// Model matches model names containing it, ignoring case. Empty
+	// matches every model.
+	Model string
+
+	// From is the first instant the rule applies to. Zero is unbounded.
+	From time.Time
+
+	// Until is the instant the rule stops applying. Zero is unbounded.
+	Until time.Time
+
+	// Per-million-token prices that replace the built-in ones. Nil keeps
+	// the built-in price.
+	InputPerMTok      *float64
+	OutputPerMTok     *float64
+	CacheWritePerMTok *float64
+	CacheReadPerMTok  *float64
+
+	// Multiplier scales all prices, e.g. 0.8 for a 20% discount.
+	// 1 keeps them.
+	Multiplier float64
+}
+
+// Matches reports whether the rule applies to model at time at.
+func (r PriceRule) Matches(model string, at time.Time) bool {
+	if r.Model != "" && !strings.Contains(strings.ToLower(model), strings.ToLower(r.Model)) {
+		return false
+	}
+	if !r.From.IsZero() && at.Before(r.From) {
+		return false
+	}
+	if !r.Until.IsZero() && !at.Before(r.Until) {
+		return false
+	}
+	return true
+}
+
+// setsPrices reports whether the rule replaces any price.
+func (r PriceRule) setsPrices() bool {
+	return r.InputPerMTok != nil || r.OutputPerMTok != nil ||
+		r.CacheWritePerMTok != nil || r.CacheReadPerMTok != nil
+}
+
+// PriceRules is an ordered list of pricing overrides.
+type PriceRules []PriceRule
+
+// Pricing returns the pricing of model at time at. The first matching
+// rule that sets prices replaces the built-in ones, then the multipliers
+// of all matching rules apply.
+func (r PriceRules) Pricing(model string, at time.Time) ModelPricing {
+	p := LookupPricing(model)
+	priced := false
+	multiplier := 1.0
+	for _, rule := range r {
+		if !rule.Matches(model, at) {
+			continue
+		}
+		if !priced && rule.setsPrices() {
+			p = rule.apply(p)
+			priced = true
+		}
+		multiplier *= rule.Multiplier
+	}
+	return ModelPricing{
+		InputPerMTok:      p.InputPerMTok * multiplier,
+		OutputPerMTok:     p.OutputPerMTok * multiplier,
+		CacheWritePerMTok: p.CacheWritePerMTok * multiplier,
+		CacheReadPerMTok:  p.CacheReadPerMTok * multiplier,
+	}
+}
+
+// apply replaces the prices of p the rule sets.
+func (r PriceRule) apply(p ModelPricing) ModelPricing {
+	for _, price := range []struct {
+		rule *float64
+		dst  *float64
+	}{
+		{r.InputPerMTok, &p.InputPerMTok},
+		{r.OutputPerMTok, &p.OutputPerMTok},
+		{r.CacheWritePerMTok, &p.CacheWritePerMTok},
+		{r.CacheReadPerMTok, &p.CacheReadPerMTok},
+	} {
+		if price.rule != nil {
+			*price.dst = *price.rule
+		}
+	}
+	return p
+}
+
+// priceRules holds the active pricing overrides.
+var priceRules atomic.Value
+
+// SetPriceRules sets the pricing overrides used by cost estimates.
+// Nil restores the built-in pricing.
+func SetPriceRules(rules PriceRules) {
+	priceRules.Store(rules)
+}
+
+// CurrentPriceRules returns the active pricing overrides.
+func CurrentPriceRules() PriceRules {
+	rules, _ := priceRules.Load().(PriceRules)
+	return rules
+}
+
+// PricingAt returns the pricing of model at time at under the active
+// overrides.
+func PricingAt(model string, at time.Time) ModelPricing {
+	return CurrentPriceRules().Pricing(model, at)
+}
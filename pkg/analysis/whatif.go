@@ -0,0 +1,183 @@
+package analysis
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/0xmhha/token-monitor/pkg/parser"
+)
+
+// ModelSwitch reprices the requests of one model as another, to estimate
+// what a model-routing change would have cost.
+type ModelSwitch struct {
+	// From selects the requests: a model class (opus, sonnet, haiku) or
+	// a case-insensitive model name glob such as "claude-opus-4-1*".
+	From string `json:"from"`
+
+	// To is the model, or model class, the requests are priced as.
+	To string `json:"to"`
+}
+
+// Matches reports whether the switch applies to requests of model.
+func (s ModelSwitch) Matches(model string) bool {
+	from := strings.ToLower(s.From)
+	switch from {
+	case ClassOpus, ClassSonnet, ClassHaiku:
+		return ModelClass(model) == from
+	}
+	ok, err := filepath.Match(from, strings.ToLower(model))
+	return err == nil && ok
+}
+
+// WhatIfRow is the cost of the requests of one model under a switch.
+type WhatIfRow struct {
+	// Model is the model the requests used.
+	Model string `json:"model"`
+
+	// To is the model the requests are repriced as.
+	To string `json:"to"`
+
+	// Requests is the number of switched requests.
+	Requests int `json:"requests"`
+
+	// Cost is their API-equivalent cost as recorded; WhatIfCost is their
+	// cost priced as To.
+	Cost       float64 `json:"cost_usd"`
+	WhatIfCost float64 `json:"whatif_cost_usd"`
+}
+
+// Savings returns how much less the requests would have cost, negative
+// when the switch is more expensive.
+func (r WhatIfRow) Savings() float64 {
+	return r.Cost - r.WhatIfCost
+}
+
+// WhatIfResult compares the cost of entries with and without switches.
+type WhatIfResult struct {
+	// Rows holds one row per switched model, largest savings first.
+	Rows []WhatIfRow `json:"rows"`
+
+	// Requests is the number of requests priced, switched or not.
+	Requests int `json:"requests"`
+
+	// Cost is the cost of all requests as recorded; WhatIfCost is their
+	// cost with the switches applied.
+	Cost       float64 `json:"cost_usd"`
+	WhatIfCost float64 `json:"whatif_cost_usd"`
+}
+
+// Savings returns the difference between the recorded and what-if cost.
+func (r WhatIfResult) Savings() float64 {
+	return r.Cost - r.WhatIfCost
+}
+
+// SavingsPercent returns the savings as a percentage of the recorded
+// cost, zero without cost.
+func (r WhatIfResult) SavingsPercent() float64 {
+	if r.Cost == 0 {
+		return 0
+	}
+	return r.Savings() * 100 / r.Cost
+}
+
+// WhatIf prices entries as recorded and with the first matching switch
+// applied to each, using the pricing in effect at each request (see
+// PricingAt). Synthetic entries, which have no usage, are skipped.
+func WhatIf(entries []parser.UsageEntry, switches []ModelSwitch) WhatIfResult {
+	var result WhatIfResult
+	rows := make(map[[2]string]*WhatIfRow)
+	for _, entry := range entries {
+		model := entry.Message.Model
+		if model == "" || model == "<synthetic>" {
+			continue
+		}
+		cost := EntryCost(entry)
+		result.Requests++
+		result.Cost += cost
+
+		s, ok := firstSwitch(switches, model)
+		if !ok {
+			result.WhatIfCost += cost
+			continue
+		}
+		u := entry.Message.Usage
+		whatIf := tokenCost(u.InputTokens, u.OutputTokens, u.CacheCreationInputTokens, u.CacheReadInputTokens,
+			PricingAt(s.To, entry.Timestamp))
+		result.WhatIfCost += whatIf
+
+		key := [2]string{model, s.To}
+		row := rows[key]
+		if row == nil {
+			row = &WhatIfRow{Model: model, To: s.To}
+			rows[key] = row
+		}
+		row.Requests++
+		row.Cost += cost
+		row.WhatIfCost += whatIf
+	}
+
+	result.Rows = make([]WhatIfRow, 0, len(rows))
+	for _, row := range rows {
+		result.Rows = append(result.Rows, *row)
+	}
+	sort.Slice(result.Rows, func(i, j int) bool {
+		a, b := result.Rows[i], result.Rows[j]
+		if a.Savings() != b.Savings() {
+			return a.Savings() > b.Savings()
+		}
+		return a.Model < b.Model
+	})
+	return result
+}
+
+// firstSwitch returns the first switch matching model.
+func firstSwitch(switches []ModelSwitch, model string) (ModelSwitch, bool) {
+	for _, s := range switches {
+		if s.Matches(model) {
+			return s, true
+		}
+	}
+	return ModelSwitch{}, false
+}
+
+// CacheWhatIf compares the cost of usage with prompt caching as recorded
+// against the cost without caching and with every input token cached.
+type CacheWhatIf struct {
+	// Cost is the API-equivalent cost as recorded.
+	Cost float64 `json:"cost_usd"`
+
+	// NoCacheCost prices cache writes and cache reads as regular input,
+	// as if prompt caching had not been used.
+	NoCacheCost float64 `json:"no_cache_cost_usd"`
+
+	// FullCacheCost prices regular input as cache reads, as if every
+	// prompt had been served from the cache. It is a lower bound: the
+	// first use of a prompt would still be a cache write.
+	FullCacheCost float64 `json:"full_cache_cost_usd"`
+}
+
+// Saved returns how much caching saved compared with no caching.
+func (c CacheWhatIf) Saved() float64 {
+	return c.NoCacheCost - c.Cost
+}
+
+// Potential returns how much more caching could have saved at most.
+func (c CacheWhatIf) Potential() float64 {
+	return c.Cost - c.FullCacheCost
+}
+
+// Add prices tokens of model used at at in all three scenarios.
+func (c *CacheWhatIf) Add(model string, at time.Time, input, output, cacheCreate, cacheRead int) {
+	p := PricingAt(model, at)
+	c.Cost += tokenCost(input, output, cacheCreate, cacheRead, p)
+	c.NoCacheCost += tokenCost(input+cacheCreate+cacheRead, output, 0, 0, p)
+	c.FullCacheCost += tokenCost(0, output, cacheCreate, input+cacheRead, p)
+}
+
+// AddEntry prices a usage entry in all three scenarios.
+func (c *CacheWhatIf) AddEntry(entry parser.UsageEntry) {
+	u := entry.Message.Usage
+	c.Add(entry.Message.Model, entry.Timestamp, u.InputTokens, u.OutputTokens, u.CacheCreationInputTokens, u.CacheReadInputTokens)
+}
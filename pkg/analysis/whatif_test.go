@@ -0,0 +1,75 @@
+package analysis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xmhha/token-monitor/pkg/parser"
+)
+
+func TestModelSwitch_Matches(t *testing.T) {
+	byClass := ModelSwitch{From: "Opus", To: "sonnet"}
+	assert.True(t, byClass.Matches("claude-opus-4-1-20250805"))
+	assert.False(t, byClass.Matches("claude-sonnet-4"))
+
+	byGlob := ModelSwitch{From: "claude-sonnet-4-5*", To: "haiku"}
+	assert.True(t, byGlob.Matches("claude-sonnet-4-5-20250929"))
+	assert.False(t, byGlob.Matches("claude-sonnet-4-20250514"))
+}
+
+func TestWhatIf(t *testing.T) {
+	at := time.Date(2025, 11, 3, 12, 0, 0, 0, time.UTC)
+	entry := func(model string, input, output int) parser.UsageEntry {
+		e := parser.UsageEntry{Timestamp: at}
+		e.Message.Model = model
+		e.Message.Usage.InputTokens = input
+		e.Message.Usage.OutputTokens = output
+		return e
+	}
+	entries := []parser.UsageEntry{
+		entry("claude-opus-4-1", 1_000_000, 100_000), // $15 + $7.5
+		entry("claude-opus-4-1", 1_000_000, 0),       // $15
+		entry("claude-haiku-3-5", 1_000_000, 0),      // $0.8
+		entry("claude-sonnet-4", 1_000_000, 100_000), // $3 + $1.5, not switched
+		entry("<synthetic>", 0, 0),
+	}
+
+	result := WhatIf(entries, []ModelSwitch{{From: "opus", To: "sonnet"}, {From: "haiku", To: "sonnet"}})
+
+	assert.Equal(t, 4, result.Requests)
+	assert.InDelta(t, 42.8, result.Cost, 1e-9)
+	assert.InDelta(t, 3+1.5+3+3+4.5, result.WhatIfCost, 1e-9)
+	assert.InDelta(t, 27.8, result.Savings(), 1e-9)
+	assert.InDelta(t, 27.8*100/42.8, result.SavingsPercent(), 1e-9)
+
+	require.Len(t, result.Rows, 2)
+	assert.Equal(t, "claude-opus-4-1", result.Rows[0].Model)
+	assert.Equal(t, 2, result.Rows[0].Requests)
+	assert.InDelta(t, 30, result.Rows[0].Savings(), 1e-9)
+	// Switching haiku to sonnet costs more.
+	assert.Equal(t, "claude-haiku-3-5", result.Rows[1].Model)
+	assert.InDelta(t, -2.2, result.Rows[1].Savings(), 1e-9)
+}
+
+func TestCacheWhatIf(t *testing.T) {
+	e := parser.UsageEntry{Timestamp: time.Date(2025, 11, 3, 12, 0, 0, 0, time.UTC)}
+	e.Message.Model = "claude-sonnet-4"
+	e.Message.Usage.InputTokens = 1_000_000              // $3
+	e.Message.Usage.OutputTokens = 100_000               // $1.5
+	e.Message.Usage.CacheCreationInputTokens = 1_000_000 // $3.75
+	e.Message.Usage.CacheReadInputTokens = 10_000_000    // $3
+
+	var c CacheWhatIf
+	c.AddEntry(e)
+
+	assert.InDelta(t, 11.25, c.Cost, 1e-9)
+	// 12M input tokens at $3 plus output.
+	assert.InDelta(t, 37.5, c.NoCacheCost, 1e-9)
+	assert.InDelta(t, 26.25, c.Saved(), 1e-9)
+	// Regular input read from the cache: 11M at $0.30.
+	assert.InDelta(t, 1.5+3.75+3.3, c.FullCacheCost, 1e-9)
+	assert.InDelta(t, 2.7, c.Potential(), 1e-9)
+}
@@ -0,0 +1,307 @@
+// Package apiclient is a typed Go client for the HTTP API of
+// token-monitor serve -http, described by the OpenAPI specification
+// embedded in Spec and served at /openapi.json. The server encodes its
+// responses from the types of this package, so client and server share
+// one wire format; the tests check it against the specification.
+//
+// Example usage:
+//
+//	client := apiclient.New("http://localhost:8080", os.Getenv("DASHBOARD_TOKEN"))
+//	page, err := client.Blocks(ctx, apiclient.BlocksParams{Limit: 10})
+//	for page.NextCursor != "" {
+//		page, err = client.Blocks(ctx, apiclient.BlocksParams{Limit: 10, Cursor: page.NextCursor})
+//	}
+//
+//	// Poll cheaply: ErrNotModified until the data changes.
+//	rate, err := client.BurnRate(ctx, apiclient.BurnRateParams{IfModifiedSince: last.LastModified})
+package apiclient
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/0xmhha/token-monitor/pkg/teamshare"
+)
+
+// specJSON is the OpenAPI specification of the API.
+//
+//go:embed openapi.json
+var specJSON []byte
+
+// SpecPath is the path the specification is served at.
+const SpecPath = "/openapi.json"
+
+// maxErrorDetail bounds the error body included in a StatusError.
+const maxErrorDetail = 512
+
+// ErrNotModified is returned when a request with IfModifiedSince finds
+// that nothing changed.
+var ErrNotModified = errors.New("not modified")
+
+// Spec returns the OpenAPI specification of the API as JSON.
+func Spec() []byte {
+	return bytes.Clone(specJSON)
+}
+
+// StatusError is returned when the server answers with an error status.
+type StatusError struct {
+	// StatusCode is the HTTP status code.
+	StatusCode int
+
+	// Message is the body of the response, trimmed.
+	Message string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("server returned %d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), e.Message)
+}
+
+// Client calls the API of one serve -http instance.
+type Client struct {
+	// BaseURL is the server URL, including serve.base_path if any.
+	BaseURL string
+
+	// Token is the bearer token sent with each request, if not empty.
+	Token string
+
+	// HTTPClient sends the requests; nil means http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+// New returns a client for the server at baseURL.
+func New(baseURL, token string) *Client {
+	return &Client{BaseURL: strings.TrimSuffix(baseURL, "/"), Token: token}
+}
+
+// Block is a 5-hour billing block.
+type Block struct {
+	// ID is the block's UTC start, e.g. "2025-11-03T1000Z".
+	ID string `json:"id"`
+
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+
+	// LastSeen is the time of the block's latest request.
+	LastSeen time.Time `json:"last_seen"`
+
+	TotalTokens  int `json:"total_tokens"`
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+	EntryCount   int `json:"entry_count"`
+
+	// RateLimits counts requests turned away by a rate limit or an
+	// overloaded API.
+	RateLimits int  `json:"rate_limits"`
+	IsActive   bool `json:"is_active"`
+}
+
+// BlocksParams are the parameters of Blocks.
+type BlocksParams struct {
+	// SessionID selects the session; empty means the current session.
+	SessionID string
+
+	// Limit is the page size; zero means the server default.
+	Limit int
+
+	// Cursor is the NextCursor of the previous page.
+	Cursor string
+
+	// IfModifiedSince makes Blocks return ErrNotModified when nothing
+	// changed since then.
+	IfModifiedSince time.Time
+}
+
+// BlockPage is a page of billing blocks, most recent first.
+type BlockPage struct {
+	SessionID string  `json:"session_id"`
+	Blocks    []Block `json:"blocks"`
+
+	// NextCursor fetches the next, older page; empty on the last page.
+	NextCursor string `json:"next_cursor,omitempty"`
+
+	// LastModified is when the blocks last changed, for IfModifiedSince.
+	LastModified time.Time `json:"-"`
+}
+
+// BurnRateParams are the parameters of BurnRate.
+type BurnRateParams struct {
+	// SessionID selects the session; empty means the current session.
+	SessionID string
+
+	// Window is the period the rate is measured over; zero means 5m.
+	Window time.Duration
+
+	// IfModifiedSince makes BurnRate return ErrNotModified when the rate
+	// has not changed since then.
+	IfModifiedSince time.Time
+}
+
+// BurnRate is a session's token consumption speed.
+type BurnRate struct {
+	SessionID string `json:"session_id"`
+
+	// BlockID is the ID of the current billing block.
+	BlockID string `json:"block_id"`
+
+	// Window is the period the rate is measured over, e.g. "5m0s".
+	Window string `json:"window"`
+
+	TokensPerMinute       float64 `json:"tokens_per_min"`
+	TokensPerHour         float64 `json:"tokens_per_hour"`
+	InputTokensPerMinute  float64 `json:"input_per_min"`
+	OutputTokensPerMinute float64 `json:"output_per_min"`
+	EntryCount            int     `json:"entry_count"`
+
+	// LastModified is when the rate last changed, for IfModifiedSince.
+	LastModified time.Time `json:"-"`
+}
+
+// HookPayload is the hook input of Claude Code; SessionID or
+// TranscriptPath is required.
+type HookPayload struct {
+	SessionID      string `json:"session_id,omitempty"`
+	TranscriptPath string `json:"transcript_path,omitempty"`
+	HookEventName  string `json:"hook_event_name,omitempty"`
+}
+
+// HookResult is the outcome of Hook.
+type HookResult struct {
+	SessionID string `json:"session_id"`
+
+	// Entries is the number of entries ingested.
+	Entries int `json:"entries"`
+}
+
+// Blocks returns a page of a session's billing blocks (GET /api/blocks).
+func (c *Client) Blocks(ctx context.Context, p BlocksParams) (*BlockPage, error) {
+	query := url.Values{}
+	if p.SessionID != "" {
+		query.Set("session_id", p.SessionID)
+	}
+	if p.Limit > 0 {
+		query.Set("limit", strconv.Itoa(p.Limit))
+	}
+	if p.Cursor != "" {
+		query.Set("cursor", p.Cursor)
+	}
+
+	var page BlockPage
+	modified, err := c.do(ctx, http.MethodGet, "/api/blocks", query, nil, p.IfModifiedSince, &page)
+	if err != nil {
+		return nil, err
+	}
+	page.LastModified = modified
+	return &page, nil
+}
+
+// BurnRate returns a session's burn rate (GET /api/burn-rate).
+func (c *Client) BurnRate(ctx context.Context, p BurnRateParams) (*BurnRate, error) {
+	query := url.Values{}
+	if p.SessionID != "" {
+		query.Set("session_id", p.SessionID)
+	}
+	if p.Window > 0 {
+		query.Set("window", p.Window.String())
+	}
+
+	var rate BurnRate
+	modified, err := c.do(ctx, http.MethodGet, "/api/burn-rate", query, nil, p.IfModifiedSince, &rate)
+	if err != nil {
+		return nil, err
+	}
+	rate.LastModified = modified
+	return &rate, nil
+}
+
+// Hook has the server ingest a session's new usage (POST /hooks). The
+// token needs the ingest scope.
+func (c *Client) Hook(ctx context.Context, p HookPayload) (*HookResult, error) {
+	var result HookResult
+	if _, err := c.do(ctx, http.MethodPost, "/hooks", nil, p, time.Time{}, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// PushTeamUsage merges a member's daily usage into the team server
+// (POST /team/usage). The token needs the ingest scope.
+func (c *Client) PushTeamUsage(ctx context.Context, r teamshare.Report) error {
+	_, err := c.do(ctx, http.MethodPost, teamshare.Path, nil, r, time.Time{}, nil)
+	return err
+}
+
+// Ready reports whether the server is ready (GET /readyz); the error
+// carries the reason when it is not.
+func (c *Client) Ready(ctx context.Context) error {
+	_, err := c.do(ctx, http.MethodGet, "/readyz", nil, nil, time.Time{}, nil)
+	return err
+}
+
+// do sends a request with an optional JSON body and decodes the JSON
+// response into out, if not nil. It returns the response's Last-Modified.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body any, since time.Time, out any) (time.Time, error) {
+	endpoint := strings.TrimSuffix(c.BaseURL, "/") + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("failed to encode request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	if !since.IsZero() {
+		req.Header.Set("If-Modified-Since", since.UTC().Format(http.TimeFormat))
+	}
+
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("request failed: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close() //nolint:errcheck // body fully read or discarded
+	}()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return time.Time{}, ErrNotModified
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorDetail)) //nolint:errcheck // best effort detail
+		return time.Time{}, &StatusError{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(detail))}
+	}
+
+	modified, _ := http.ParseTime(resp.Header.Get("Last-Modified")) //nolint:errcheck // zero when absent
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return time.Time{}, fmt.Errorf("failed to decode response: %w", err)
+		}
+	}
+	return modified, nil
+}
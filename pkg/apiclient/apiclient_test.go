@@ -0,0 +1,324 @@
+package apiclient_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0xmhha/token-monitor/pkg/apiclient"
+	"github.com/0xmhha/token-monitor/pkg/discovery"
+	"github.com/0xmhha/token-monitor/pkg/logger"
+	"github.com/0xmhha/token-monitor/pkg/mcp"
+	"github.com/0xmhha/token-monitor/pkg/parser"
+	"github.com/0xmhha/token-monitor/pkg/reader"
+	"github.com/0xmhha/token-monitor/pkg/rollup"
+	"github.com/0xmhha/token-monitor/pkg/teamshare"
+)
+
+// staticDiscoverer discovers a fixed set of sessions.
+type staticDiscoverer []discovery.SessionFile
+
+func (d staticDiscoverer) Discover() ([]discovery.SessionFile, error) { return d, nil }
+
+func (d staticDiscoverer) DiscoverProject(string) ([]discovery.SessionFile, error) { return d, nil }
+
+func (d staticDiscoverer) Duplicates() []discovery.Duplicate { return nil }
+
+func (d staticDiscoverer) FindCurrentSession() (*discovery.SessionFile, error) {
+	if len(d) == 0 {
+		return nil, discovery.ErrNoCurrentSession
+	}
+	return &d[0], nil
+}
+
+// newAPIServer serves the /api/ endpoints of serve -http for a session
+// with one request per given hour of 2025-11-03 UTC.
+func newAPIServer(t *testing.T, hours ...int) *httptest.Server {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "s1.jsonl")
+	var lines []string
+	for i, hour := range hours {
+		data, err := json.Marshal(map[string]any{
+			"timestamp": time.Date(2025, 11, 3, hour, 0, 0, 0, time.UTC).Format(time.RFC3339),
+			"sessionId": "s1",
+			"message": map[string]any{
+				"id":    "msg_" + string(rune('a'+i)),
+				"model": "claude-sonnet-4-20250514",
+				"usage": map[string]any{"input_tokens": 100, "output_tokens": 10},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		lines = append(lines, string(data))
+	}
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	sessions := staticDiscoverer{{SessionID: "s1", FilePath: path, ModTime: time.Now().Add(-time.Hour).Unix()}}
+
+	readers := func() (reader.Reader, error) {
+		return reader.New(reader.Config{PositionStore: reader.NewMemoryPositionStore(), Parser: parser.New()}, logger.Noop())
+	}
+	srv := httptest.NewServer(mcp.NewAPIHandler(sessions, readers, logger.Noop()))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestSpec(t *testing.T) {
+	var spec struct {
+		OpenAPI string                    `json:"openapi"`
+		Paths   map[string]map[string]any `json:"paths"`
+	}
+	if err := json.Unmarshal(apiclient.Spec(), &spec); err != nil {
+		t.Fatalf("spec is not valid JSON: %v", err)
+	}
+	if !strings.HasPrefix(spec.OpenAPI, "3.") {
+		t.Errorf("openapi = %q, want 3.x", spec.OpenAPI)
+	}
+
+	// Every endpoint of the client is in the spec.
+	for path, method := range map[string]string{
+		"/api/blocks":      "get",
+		"/api/burn-rate":   "get",
+		"/hooks":           "post",
+		teamshare.Path:     "post",
+		"/readyz":          "get",
+		apiclient.SpecPath: "get",
+	} {
+		if _, ok := spec.Paths[path][method]; !ok {
+			t.Errorf("spec has no %s %s", method, path)
+		}
+	}
+}
+
+func TestClient_Blocks(t *testing.T) {
+	srv := newAPIServer(t, 1, 6, 11)
+	client := apiclient.New(srv.URL, "")
+	ctx := context.Background()
+
+	var ids []string
+	params := apiclient.BlocksParams{Limit: 2}
+	for {
+		page, err := client.Blocks(ctx, params)
+		if err != nil {
+			t.Fatalf("Blocks(%+v) error: %v", params, err)
+		}
+		for _, b := range page.Blocks {
+			ids = append(ids, b.ID)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		params.Cursor = page.NextCursor
+	}
+	if got := strings.Join(ids, ","); got != "2025-11-03T1000Z,2025-11-03T0500Z,2025-11-03T0000Z" {
+		t.Errorf("blocks = %s, want the three blocks most recent first", got)
+	}
+
+	page, err := client.Blocks(ctx, apiclient.BlocksParams{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.LastModified.IsZero() {
+		t.Fatal("LastModified not set")
+	}
+	if _, err := client.Blocks(ctx, apiclient.BlocksParams{IfModifiedSince: page.LastModified}); !errors.Is(err, apiclient.ErrNotModified) {
+		t.Errorf("Blocks(IfModifiedSince) error = %v, want apiclient.ErrNotModified", err)
+	}
+}
+
+func TestClient_BurnRate(t *testing.T) {
+	srv := newAPIServer(t, 1)
+	rate, err := apiclient.New(srv.URL, "").BurnRate(context.Background(), apiclient.BurnRateParams{Window: 10 * time.Minute})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rate.SessionID != "s1" || rate.Window != "10m0s" || rate.BlockID == "" {
+		t.Errorf("BurnRate() = %+v, want session s1 over 10m0s with a block ID", rate)
+	}
+}
+
+func TestClient_StatusError(t *testing.T) {
+	srv := newAPIServer(t, 1)
+	_, err := apiclient.New(srv.URL, "").Blocks(context.Background(), apiclient.BlocksParams{SessionID: "missing"})
+
+	var statusErr *apiclient.StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusNotFound {
+		t.Fatalf("Blocks(missing) error = %v, want a 404 apiclient.StatusError", err)
+	}
+	if !strings.Contains(statusErr.Message, "missing") {
+		t.Errorf("Message = %q, want the server's message", statusErr.Message)
+	}
+}
+
+func TestClient_Token(t *testing.T) {
+	var auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	if err := apiclient.New(srv.URL+"/", "secret").PushTeamUsage(context.Background(), teamshare.Report{Member: "alice"}); err != nil {
+		t.Fatal(err)
+	}
+	if auth != "Bearer secret" {
+		t.Errorf("Authorization = %q, want Bearer secret", auth)
+	}
+}
+
+// schemaChecker checks JSON values against the component schemas of the
+// spec: types, formats, patterns, required properties, and properties the
+// spec does not know.
+type schemaChecker struct {
+	t       *testing.T
+	schemas map[string]map[string]any
+}
+
+func newSchemaChecker(t *testing.T) schemaChecker {
+	t.Helper()
+	var spec struct {
+		Components struct {
+			Schemas map[string]map[string]any `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(apiclient.Spec(), &spec); err != nil {
+		t.Fatal(err)
+	}
+	return schemaChecker{t: t, schemas: spec.Components.Schemas}
+}
+
+// checkJSON checks the JSON document data against the named schema.
+func (c schemaChecker) checkJSON(name string, data []byte) {
+	c.t.Helper()
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		c.t.Fatalf("%s: %v", name, err)
+	}
+	c.check(name, map[string]any{"$ref": "#/components/schemas/" + name}, value)
+}
+
+func (c schemaChecker) check(path string, schema map[string]any, value any) {
+	c.t.Helper()
+	if ref, ok := schema["$ref"].(string); ok {
+		name := strings.TrimPrefix(ref, "#/components/schemas/")
+		resolved, ok := c.schemas[name]
+		if !ok {
+			c.t.Fatalf("%s: unknown schema %s", path, ref)
+		}
+		schema = resolved
+	}
+
+	switch schema["type"] {
+	case "object":
+		obj, ok := value.(map[string]any)
+		if !ok {
+			c.t.Errorf("%s = %v, want an object", path, value)
+			return
+		}
+		properties, _ := schema["properties"].(map[string]any)
+		required, _ := schema["required"].([]any)
+		for _, name := range required {
+			if _, ok := obj[name.(string)]; !ok {
+				c.t.Errorf("%s: required property %s missing", path, name)
+			}
+		}
+		for name, v := range obj {
+			property, ok := properties[name].(map[string]any)
+			if !ok {
+				c.t.Errorf("%s.%s is not in the spec", path, name)
+				continue
+			}
+			c.check(path+"."+name, property, v)
+		}
+	case "array":
+		items, ok := value.([]any)
+		if !ok {
+			c.t.Errorf("%s = %v, want an array", path, value)
+			return
+		}
+		for i, item := range items {
+			c.check(fmt.Sprintf("%s[%d]", path, i), schema["items"].(map[string]any), item)
+		}
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			c.t.Errorf("%s = %v, want a string", path, value)
+			return
+		}
+		switch schema["format"] {
+		case "date-time":
+			if _, err := time.Parse(time.RFC3339, s); err != nil {
+				c.t.Errorf("%s = %q, want a date-time", path, s)
+			}
+		case "date":
+			if _, err := time.Parse("2006-01-02", s); err != nil {
+				c.t.Errorf("%s = %q, want a date", path, s)
+			}
+		}
+		if pattern, ok := schema["pattern"].(string); ok && !regexp.MustCompile(pattern).MatchString(s) {
+			c.t.Errorf("%s = %q, want a match of %s", path, s, pattern)
+		}
+	case "integer":
+		if n, ok := value.(float64); !ok || n != math.Trunc(n) {
+			c.t.Errorf("%s = %v, want an integer", path, value)
+		}
+	case "number":
+		if _, ok := value.(float64); !ok {
+			c.t.Errorf("%s = %v, want a number", path, value)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			c.t.Errorf("%s = %v, want a boolean", path, value)
+		}
+	default:
+		c.t.Fatalf("%s: unsupported schema %v", path, schema)
+	}
+}
+
+func TestResponsesMatchSpec(t *testing.T) {
+	srv := newAPIServer(t, 1, 6, 11)
+	checker := newSchemaChecker(t)
+
+	get := func(path string) []byte {
+		t.Helper()
+		resp, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer func() { _ = resp.Body.Close() }() //nolint:errcheck
+		data, err := io.ReadAll(resp.Body)
+		if err != nil || resp.StatusCode != http.StatusOK {
+			t.Fatalf("GET %s = %d, %v: %s", path, resp.StatusCode, err, data)
+		}
+		return data
+	}
+
+	// A first page has next_cursor, the last one does not.
+	checker.checkJSON("BlockPage", get("/api/blocks?limit=1"))
+	checker.checkJSON("BlockPage", get("/api/blocks"))
+	checker.checkJSON("BurnRate", get("/api/burn-rate?window=10m"))
+
+	report := teamshare.Build("alice", []rollup.Row{{
+		Key:    rollup.Key{Date: "2025-11-03", SessionID: "s1"},
+		Totals: rollup.Totals{Entries: 2, InputTokens: 100, CostUSD: 0.5},
+	}}, "dev", time.Date(2025, 11, 4, 0, 0, 0, 0, time.UTC))
+	data, err := json.Marshal(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checker.checkJSON("TeamReport", data)
+}
@@ -0,0 +1,181 @@
+package baseline
+
+import (
+	"errors"
+	"math"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/0xmhha/token-monitor/pkg/aggregator"
+)
+
+func newTestStore(t *testing.T) Store {
+	t.Helper()
+	db, err := bolt.Open(filepath.Join(t.TempDir(), "test.db"), 0600, nil)
+	if err != nil {
+		t.Fatalf("bolt.Open() error = %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	store, err := New(db)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return store
+}
+
+func TestStore(t *testing.T) {
+	store := newTestStore(t)
+
+	b := Baseline{Name: "before-caching", Days: 14, Model: "*sonnet*", Snapshot: Snapshot{Entries: 10, Days: 14}}
+	if err := store.Save(b, false); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if err := store.Save(b, false); !errors.Is(err, ErrExists) {
+		t.Errorf("Save() duplicate error = %v, want ErrExists", err)
+	}
+	b.Snapshot.Entries = 20
+	if err := store.Save(b, true); err != nil {
+		t.Fatalf("Save(overwrite) error = %v", err)
+	}
+	if err := store.Save(Baseline{Name: "a"}, false); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	got, err := store.Get("before-caching")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got.Snapshot.Entries != 20 || got.Model != "*sonnet*" || got.Days != 14 {
+		t.Errorf("Get() = %+v, want overwritten baseline", got)
+	}
+
+	list, err := store.List()
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(list) != 2 || list[0].Name != "a" || list[1].Name != "before-caching" {
+		t.Errorf("List() = %+v, want a, before-caching", list)
+	}
+
+	if err := store.Delete("a"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := store.Get("a"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get() deleted error = %v, want ErrNotFound", err)
+	}
+	if err := store.Delete("a"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Delete() missing error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestValidateName(t *testing.T) {
+	tests := []struct {
+		name  string
+		valid bool
+	}{
+		{"before-caching", true},
+		{"v1.2_opus", true},
+		{"", false},
+		{"-leading", false},
+		{"has space", false},
+		{"a/b", false},
+		{strings.Repeat("a", MaxNameLength+1), false},
+	}
+	for _, tt := range tests {
+		if err := ValidateName(tt.name); (err == nil) != tt.valid {
+			t.Errorf("ValidateName(%q) error = %v, want valid %v", tt.name, err, tt.valid)
+		}
+	}
+
+	store := newTestStore(t)
+	if err := store.Save(Baseline{Name: "bad name"}, false); !errors.Is(err, ErrInvalidName) {
+		t.Errorf("Save() error = %v, want ErrInvalidName", err)
+	}
+}
+
+func TestFromStatistics(t *testing.T) {
+	first := time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC)
+	stats := aggregator.Statistics{
+		Count:     4,
+		FirstSeen: first,
+		LastSeen:  first.Add(72 * time.Hour),
+	}
+
+	if got := FromStatistics(stats, 14).Days; got != 14 {
+		t.Errorf("Days with window = %v, want 14", got)
+	}
+	if got := FromStatistics(stats, 0).Days; got != 3 {
+		t.Errorf("Days from span = %v, want 3", got)
+	}
+	stats.LastSeen = first.Add(time.Hour)
+	if got := FromStatistics(stats, 0).Days; got != 1 {
+		t.Errorf("Days for short span = %v, want 1", got)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	base := Snapshot{
+		Entries:         100,
+		InputTokens:     80_000,
+		CacheReadTokens: 20_000,
+		TotalTokens:     100_000,
+		CostUSD:         10,
+		Days:            10,
+	}
+	current := Snapshot{
+		Entries:         50,
+		InputTokens:     10_000,
+		CacheReadTokens: 30_000,
+		TotalTokens:     40_000,
+		CostUSD:         2,
+		Days:            5,
+	}
+
+	changes := make(map[string]Change)
+	for _, c := range Compare(base, current) {
+		changes[c.Metric] = c
+	}
+
+	tests := []struct {
+		metric   string
+		baseline float64
+		current  float64
+		percent  float64
+	}{
+		{"entries", 100, 50, -50},
+		{"requests_per_day", 10, 10, 0},
+		{"tokens_per_request", 1000, 800, -20},
+		{"cost_per_request", 0.1, 0.04, -60},
+		{"cost_per_day", 1, 0.4, -60},
+	}
+	for _, tt := range tests {
+		c, ok := changes[tt.metric]
+		if !ok {
+			t.Fatalf("Compare() missing %s", tt.metric)
+		}
+		if !near(c.Baseline, tt.baseline) || !near(c.Current, tt.current) {
+			t.Errorf("%s = %v -> %v, want %v -> %v", tt.metric, c.Baseline, c.Current, tt.baseline, tt.current)
+		}
+		if c.Percent == nil || !near(*c.Percent, tt.percent) {
+			t.Errorf("%s percent = %v, want %v", tt.metric, c.Percent, tt.percent)
+		}
+	}
+
+	share := changes["cache_read_share"]
+	if !near(share.Baseline, 20) || !near(share.Current, 75) || !near(share.Delta, 55) || share.Percent != nil {
+		t.Errorf("cache_read_share = %+v, want 20 -> 75 (+55 points, no percent)", share)
+	}
+
+	if c := changes["cache_creation_tokens"]; c.Percent != nil {
+		t.Errorf("cache_creation_tokens percent = %v, want nil for zero baseline", *c.Percent)
+	}
+}
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
@@ -0,0 +1,113 @@
+package baseline
+
+// Kind describes how a compared value is measured.
+type Kind string
+
+const (
+	// KindCount is a count of entries or tokens.
+	KindCount Kind = "count"
+
+	// KindCost is a USD amount.
+	KindCost Kind = "cost"
+
+	// KindRate is a ratio such as tokens per request.
+	KindRate Kind = "rate"
+
+	// KindPercent is a percentage (0-100).
+	KindPercent Kind = "percent"
+)
+
+// Change compares one metric between a baseline and the current period.
+type Change struct {
+	// Metric is a stable metric name, e.g. "tokens_per_request".
+	Metric string `json:"metric"`
+
+	// Kind is how the metric is measured.
+	Kind Kind `json:"kind"`
+
+	Baseline float64 `json:"baseline"`
+	Current  float64 `json:"current"`
+
+	// Delta is Current - Baseline.
+	Delta float64 `json:"delta"`
+
+	// Percent is Delta relative to Baseline, or nil when Baseline is zero.
+	// Percentages report Delta in points instead and leave this nil.
+	Percent *float64 `json:"percent,omitempty"`
+}
+
+// Compare returns the changes from base to current. Totals come first,
+// followed by rates that do not depend on the period length.
+func Compare(base, current Snapshot) []Change {
+	metrics := []struct {
+		name     string
+		kind     Kind
+		baseline float64
+		current  float64
+	}{
+		{"entries", KindCount, float64(base.Entries), float64(current.Entries)},
+		{"total_tokens", KindCount, float64(base.TotalTokens), float64(current.TotalTokens)},
+		{"input_tokens", KindCount, float64(base.InputTokens), float64(current.InputTokens)},
+		{"output_tokens", KindCount, float64(base.OutputTokens), float64(current.OutputTokens)},
+		{"cache_creation_tokens", KindCount, float64(base.CacheCreationTokens), float64(current.CacheCreationTokens)},
+		{"cache_read_tokens", KindCount, float64(base.CacheReadTokens), float64(current.CacheReadTokens)},
+		{"cost_usd", KindCost, base.CostUSD, current.CostUSD},
+		{"requests_per_day", KindRate, base.RequestsPerDay(), current.RequestsPerDay()},
+		{"tokens_per_request", KindRate, base.TokensPerRequest(), current.TokensPerRequest()},
+		{"cost_per_request", KindCost, base.CostPerRequest(), current.CostPerRequest()},
+		{"cost_per_day", KindCost, base.CostPerDay(), current.CostPerDay()},
+		{"cache_read_share", KindPercent, base.CacheReadShare(), current.CacheReadShare()},
+	}
+
+	changes := make([]Change, 0, len(metrics))
+	for _, m := range metrics {
+		c := Change{
+			Metric:   m.name,
+			Kind:     m.kind,
+			Baseline: m.baseline,
+			Current:  m.current,
+			Delta:    m.current - m.baseline,
+		}
+		if m.kind != KindPercent && m.baseline != 0 {
+			pct := c.Delta / m.baseline * 100
+			c.Percent = &pct
+		}
+		changes = append(changes, c)
+	}
+	return changes
+}
+
+// RequestsPerDay returns entries per day of the period.
+func (s Snapshot) RequestsPerDay() float64 {
+	return ratio(float64(s.Entries), s.Days)
+}
+
+// TokensPerRequest returns the average total tokens per entry.
+func (s Snapshot) TokensPerRequest() float64 {
+	return ratio(float64(s.TotalTokens), float64(s.Entries))
+}
+
+// CostPerRequest returns the average estimated cost per entry.
+func (s Snapshot) CostPerRequest() float64 {
+	return ratio(s.CostUSD, float64(s.Entries))
+}
+
+// CostPerDay returns the estimated cost per day of the period.
+func (s Snapshot) CostPerDay() float64 {
+	return ratio(s.CostUSD, s.Days)
+}
+
+// CacheReadShare returns cache reads as a percentage of all input-side
+// tokens (input, cache creation, and cache read).
+func (s Snapshot) CacheReadShare() float64 {
+	input := s.InputTokens + s.CacheCreationTokens + s.CacheReadTokens
+	return ratio(float64(s.CacheReadTokens)*100, float64(input))
+}
+
+// ratio returns a/b, or 0 if b is zero.
+func ratio(a, b float64) float64 {
+	if b == 0 {
+		return 0
+	}
+	return a / b
+}
@@ -0,0 +1,129 @@
+package baseline
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// bucketBaselines maps baseline names to JSON-encoded Baselines.
+var bucketBaselines = []byte("baselines")
+
+// boltStore implements Store using BoltDB.
+type boltStore struct {
+	db *bolt.DB
+}
+
+// New creates a BoltDB-backed baseline store.
+//
+// Parameters:
+//   - db: BoltDB database instance (typically session.Manager.DB())
+//
+// Returns:
+//   - Configured Store
+//   - Error if bucket initialization fails
+func New(db *bolt.DB) (Store, error) {
+	if err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(bucketBaselines)
+		return err
+	}); err != nil {
+		return nil, fmt.Errorf("failed to create baseline bucket: %w", err)
+	}
+
+	return &boltStore{db: db}, nil
+}
+
+// Save implements Store.Save.
+func (s *boltStore) Save(b Baseline, overwrite bool) error {
+	if err := ValidateName(b.Name); err != nil {
+		return fmt.Errorf("%w: %q", err, b.Name)
+	}
+
+	data, err := json.Marshal(b)
+	if err != nil {
+		return fmt.Errorf("failed to encode baseline: %w", err)
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(bucketBaselines)
+		if !overwrite && bucket.Get([]byte(b.Name)) != nil {
+			return fmt.Errorf("%w: %s", ErrExists, b.Name)
+		}
+		return bucket.Put([]byte(b.Name), data)
+	})
+}
+
+// Get implements Store.Get.
+func (s *boltStore) Get(name string) (Baseline, error) {
+	var b Baseline
+	err := s.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(bucketBaselines).Get([]byte(name))
+		if data == nil {
+			return fmt.Errorf("%w: %s", ErrNotFound, name)
+		}
+		if err := json.Unmarshal(data, &b); err != nil {
+			return fmt.Errorf("invalid baseline %s: %w", name, err)
+		}
+		return nil
+	})
+	return b, err
+}
+
+// List implements Store.List.
+func (s *boltStore) List() ([]Baseline, error) {
+	var list []Baseline
+	err := s.db.View(func(tx *bolt.Tx) error {
+		// BoltDB iterates keys in byte order, which is name order.
+		return tx.Bucket(bucketBaselines).ForEach(func(k, v []byte) error {
+			var b Baseline
+			if err := json.Unmarshal(v, &b); err != nil {
+				return fmt.Errorf("invalid baseline %s: %w", k, err)
+			}
+			list = append(list, b)
+			return nil
+		})
+	})
+	return list, err
+}
+
+// Delete implements Store.Delete.
+func (s *boltStore) Delete(name string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(bucketBaselines)
+		if bucket.Get([]byte(name)) == nil {
+			return fmt.Errorf("%w: %s", ErrNotFound, name)
+		}
+		return bucket.Delete([]byte(name))
+	})
+}
+
+// Prune implements Store.Prune.
+func (s *boltStore) Prune(before time.Time) (int, error) {
+	removed := 0
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(bucketBaselines)
+		var stale [][]byte
+		if err := bucket.ForEach(func(k, v []byte) error {
+			var b Baseline
+			if err := json.Unmarshal(v, &b); err != nil {
+				return fmt.Errorf("invalid baseline %s: %w", k, err)
+			}
+			if b.CreatedAt.Before(before) {
+				stale = append(stale, append([]byte(nil), k...))
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+		for _, k := range stale {
+			if err := bucket.Delete(k); err != nil {
+				return fmt.Errorf("failed to delete baseline %s: %w", k, err)
+			}
+		}
+		removed = len(stale)
+		return nil
+	})
+	return removed, err
+}
@@ -0,0 +1,143 @@
+// Package baseline stores named snapshots of usage statistics and compares
+// later periods against them.
+//
+// A baseline captures totals for a period (for example the last 14 days
+// before enabling prompt caching). Comparing against it reports absolute
+// totals alongside per-request and per-day rates, so periods of different
+// length can still be compared.
+//
+// Example usage:
+//
+//	store, err := baseline.New(db)
+//	if err != nil {
+//	    return err
+//	}
+//	err = store.Save(baseline.Baseline{
+//	    Name:     "before-caching",
+//	    Days:     14,
+//	    Snapshot: baseline.FromStatistics(stats, 14),
+//	}, false)
+//
+//	saved, err := store.Get("before-caching")
+//	changes := baseline.Compare(saved.Snapshot, baseline.FromStatistics(current, saved.Days))
+package baseline
+
+import (
+	"errors"
+	"regexp"
+	"time"
+
+	"github.com/0xmhha/token-monitor/pkg/aggregator"
+)
+
+// MaxNameLength is the maximum length of a baseline name.
+const MaxNameLength = 64
+
+// namePattern matches valid baseline names.
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// Common errors returned by the baseline store.
+var (
+	// ErrNotFound is returned when no baseline has the requested name.
+	ErrNotFound = errors.New("baseline not found")
+
+	// ErrExists is returned when saving over an existing baseline without
+	// overwrite.
+	ErrExists = errors.New("baseline already exists")
+
+	// ErrInvalidName is returned when a baseline name is empty, too long,
+	// or contains characters other than letters, digits, '.', '_', and '-'.
+	ErrInvalidName = errors.New("invalid baseline name")
+)
+
+// ValidateName checks that name is usable as a baseline name.
+func ValidateName(name string) error {
+	if len(name) > MaxNameLength || !namePattern.MatchString(name) {
+		return ErrInvalidName
+	}
+	return nil
+}
+
+// Baseline is a named snapshot with the period and filter it was taken with.
+type Baseline struct {
+	// Name identifies the baseline.
+	Name string `json:"name"`
+
+	// CreatedAt is when the baseline was saved.
+	CreatedAt time.Time `json:"created_at"`
+
+	// Days is the length of the captured period ending at CreatedAt.
+	// Zero means all available data.
+	Days int `json:"days,omitempty"`
+
+	// Model is the model filter, if any.
+	Model string `json:"model,omitempty"`
+
+	// Snapshot holds the captured totals.
+	Snapshot Snapshot `json:"snapshot"`
+}
+
+// Snapshot holds the totals of one period.
+type Snapshot struct {
+	Entries             int       `json:"entries"`
+	Sessions            int       `json:"sessions"`
+	InputTokens         int       `json:"input_tokens"`
+	OutputTokens        int       `json:"output_tokens"`
+	CacheCreationTokens int       `json:"cache_creation_tokens"`
+	CacheReadTokens     int       `json:"cache_read_tokens"`
+	TotalTokens         int       `json:"total_tokens"`
+	CostUSD             float64   `json:"cost_usd"`
+	FirstSeen           time.Time `json:"first_seen"`
+	LastSeen            time.Time `json:"last_seen"`
+
+	// Days is the period length used for per-day rates.
+	Days float64 `json:"days"`
+}
+
+// FromStatistics captures stats as a snapshot. Days is the period length
+// in days; zero uses the span between the first and last entry, with
+// spans shorter than a day counting as one day.
+func FromStatistics(stats aggregator.Statistics, days int) Snapshot {
+	s := Snapshot{
+		Entries:             stats.Count,
+		Sessions:            stats.SessionCount,
+		InputTokens:         stats.InputTokens,
+		OutputTokens:        stats.OutputTokens,
+		CacheCreationTokens: stats.CacheCreationTokens,
+		CacheReadTokens:     stats.CacheReadTokens,
+		TotalTokens:         stats.TotalTokens,
+		CostUSD:             stats.CostUSD,
+		FirstSeen:           stats.FirstSeen,
+		LastSeen:            stats.LastSeen,
+		Days:                float64(days),
+	}
+	if days <= 0 {
+		s.Days = stats.LastSeen.Sub(stats.FirstSeen).Hours() / 24
+		if s.Days < 1 {
+			s.Days = 1
+		}
+	}
+	return s
+}
+
+// Store persists baselines.
+//
+// Thread-safety: Implementations must be safe for concurrent use.
+type Store interface {
+	// Save stores b under b.Name. It returns ErrExists if the name is
+	// taken and overwrite is false.
+	Save(b Baseline, overwrite bool) error
+
+	// Get returns the baseline named name, or ErrNotFound.
+	Get(name string) (Baseline, error)
+
+	// List returns all baselines ordered by name.
+	List() ([]Baseline, error)
+
+	// Delete removes the baseline named name, or returns ErrNotFound.
+	Delete(name string) error
+
+	// Prune removes the baselines saved before before and returns how
+	// many were removed.
+	Prune(before time.Time) (int, error)
+}
@@ -0,0 +1,149 @@
+// Package calendar lays out daily token usage as a month grid for
+// heat-map rendering, in the style of a contributions calendar: one row
+// per weekday (Monday first), one column per week, each day shaded by its
+// usage relative to the busiest day of the month.
+//
+// Example usage:
+//
+//	month, err := calendar.ParseMonth("2025-11", time.UTC) // rollup dates are UTC
+//	if err != nil {
+//	    return err
+//	}
+//	cal := calendar.Build(month, daily) // daily: YYYY-MM-DD -> rollup.Totals
+//	for _, row := range cal.Grid() {
+//	    for _, day := range row {
+//	        if day != nil {
+//	            fmt.Print(calendar.Glyphs[day.Level])
+//	        }
+//	    }
+//	}
+package calendar
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/0xmhha/token-monitor/pkg/rollup"
+)
+
+// MonthLayout is the format of month arguments.
+const MonthLayout = "2006-01"
+
+// Levels is the number of shading levels, including 0 for no usage.
+const Levels = 5
+
+// Glyphs shades each level from no usage to the busiest days.
+var Glyphs = [Levels]string{"·", "░", "▒", "▓", "█"}
+
+// Weekdays lists grid rows in order.
+var Weekdays = []time.Weekday{
+	time.Monday, time.Tuesday, time.Wednesday, time.Thursday,
+	time.Friday, time.Saturday, time.Sunday,
+}
+
+// Day is one day of the month with its usage.
+type Day struct {
+	Date   time.Time     `json:"-"`
+	Name   string        `json:"date"`
+	Totals rollup.Totals `json:"totals"`
+
+	// Level is the shading level, 0 (no usage) to Levels-1.
+	Level int `json:"level"`
+}
+
+// Month is a month of daily usage.
+type Month struct {
+	// Start is midnight on the first day of the month.
+	Start time.Time `json:"-"`
+	Name  string    `json:"month"`
+
+	// Days holds every day of the month in order.
+	Days []Day `json:"days"`
+
+	// Total sums the month.
+	Total rollup.Totals `json:"total"`
+
+	// ActiveDays is the number of days with any usage.
+	ActiveDays int `json:"active_days"`
+}
+
+// ParseMonth parses a YYYY-MM month in loc.
+func ParseMonth(s string, loc *time.Location) (time.Time, error) {
+	t, err := time.ParseInLocation(MonthLayout, s, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid month %q (want YYYY-MM)", s)
+	}
+	return t, nil
+}
+
+// Build lays out the month containing month, taking usage from daily,
+// which is keyed by date (YYYY-MM-DD).
+func Build(month time.Time, daily map[string]rollup.Totals) Month {
+	start := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, month.Location())
+	m := Month{Start: start, Name: start.Format(MonthLayout)}
+
+	maxTokens := 0
+	for day := start; day.Month() == start.Month(); day = day.AddDate(0, 0, 1) {
+		name := day.Format(rollup.DateLayout)
+		totals := daily[name]
+		m.Days = append(m.Days, Day{Date: day, Name: name, Totals: totals})
+		m.Total.Merge(totals)
+		if totals.Entries > 0 {
+			m.ActiveDays++
+		}
+		maxTokens = max(maxTokens, totals.TotalTokens())
+	}
+
+	for i := range m.Days {
+		m.Days[i].Level = Level(m.Days[i].Totals.TotalTokens(), maxTokens)
+	}
+	return m
+}
+
+// Level returns the shading level of tokens relative to the busiest day:
+// 0 for none, otherwise the quarter of maxTokens it falls in (1-4).
+func Level(tokens, maxTokens int) int {
+	if tokens <= 0 || maxTokens <= 0 {
+		return 0
+	}
+	level := (tokens*(Levels-1) + maxTokens - 1) / maxTokens
+	return min(max(level, 1), Levels-1)
+}
+
+// Grid returns the days arranged by weekday (rows, Monday first) and week
+// (columns). Cells before the first or after the last day are nil.
+func (m Month) Grid() [][]*Day {
+	offset := weekdayIndex(m.Start.Weekday())
+	weeks := (offset + len(m.Days) + 6) / 7
+
+	grid := make([][]*Day, len(Weekdays))
+	for row := range grid {
+		grid[row] = make([]*Day, weeks)
+	}
+	for i := range m.Days {
+		cell := offset + i
+		grid[cell%7][cell/7] = &m.Days[i]
+	}
+	return grid
+}
+
+// Busiest returns the day with the most tokens, or nil if the month has
+// no usage.
+func (m Month) Busiest() *Day {
+	var busiest *Day
+	for i := range m.Days {
+		day := &m.Days[i]
+		if day.Totals.TotalTokens() == 0 {
+			continue
+		}
+		if busiest == nil || day.Totals.TotalTokens() > busiest.Totals.TotalTokens() {
+			busiest = day
+		}
+	}
+	return busiest
+}
+
+// weekdayIndex returns the grid row of w.
+func weekdayIndex(w time.Weekday) int {
+	return (int(w) + 6) % 7
+}
@@ -0,0 +1,99 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/0xmhha/token-monitor/pkg/rollup"
+)
+
+func TestParseMonth(t *testing.T) {
+	month, err := ParseMonth("2025-11", time.UTC)
+	if err != nil {
+		t.Fatalf("ParseMonth() error = %v", err)
+	}
+	if !month.Equal(time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("ParseMonth() = %v", month)
+	}
+
+	for _, bad := range []string{"", "2025-13", "2025/11", "Nov 2025"} {
+		if _, err := ParseMonth(bad, time.UTC); err == nil {
+			t.Errorf("ParseMonth(%q) expected error", bad)
+		}
+	}
+}
+
+func TestLevel(t *testing.T) {
+	tests := []struct {
+		tokens, max, want int
+	}{
+		{0, 100, 0},
+		{1, 100, 1},
+		{25, 100, 1},
+		{26, 100, 2},
+		{50, 100, 2},
+		{75, 100, 3},
+		{99, 100, 4},
+		{100, 100, 4},
+		{5, 0, 0},
+	}
+	for _, tt := range tests {
+		if got := Level(tt.tokens, tt.max); got != tt.want {
+			t.Errorf("Level(%d, %d) = %d, want %d", tt.tokens, tt.max, got, tt.want)
+		}
+	}
+}
+
+func TestBuild(t *testing.T) {
+	// November 2025 starts on a Saturday and has 30 days.
+	daily := map[string]rollup.Totals{
+		"2025-11-01": {Entries: 1, InputTokens: 100},
+		"2025-11-10": {Entries: 4, InputTokens: 400, CostUSD: 2},
+		"2025-11-30": {Entries: 2, OutputTokens: 200},
+		"2025-12-01": {Entries: 9, InputTokens: 900}, // next month
+	}
+	month := Build(time.Date(2025, 11, 15, 12, 0, 0, 0, time.UTC), daily)
+
+	if month.Name != "2025-11" || len(month.Days) != 30 {
+		t.Fatalf("Build() = %s with %d days, want 2025-11 with 30", month.Name, len(month.Days))
+	}
+	if month.Total.Entries != 7 || month.Total.TotalTokens() != 700 || month.ActiveDays != 3 {
+		t.Errorf("Total = %+v, active %d; want 7 entries, 700 tokens, 3 active", month.Total, month.ActiveDays)
+	}
+	if got := month.Days[9].Level; got != Levels-1 {
+		t.Errorf("busiest day level = %d, want %d", got, Levels-1)
+	}
+	if got := month.Days[0].Level; got != 1 {
+		t.Errorf("2025-11-01 level = %d, want 1", got)
+	}
+	if got := month.Days[1].Level; got != 0 {
+		t.Errorf("idle day level = %d, want 0", got)
+	}
+	if busiest := month.Busiest(); busiest == nil || busiest.Name != "2025-11-10" {
+		t.Errorf("Busiest() = %v, want 2025-11-10", busiest)
+	}
+
+	grid := month.Grid()
+	if len(grid) != 7 || len(grid[0]) != 5 {
+		t.Fatalf("Grid() is %dx%d, want 7x5", len(grid), len(grid[0]))
+	}
+	// Saturday is row 5; the first week has nothing before it.
+	if grid[0][0] != nil || grid[5][0] == nil || grid[5][0].Name != "2025-11-01" {
+		t.Errorf("first week = Mon %v, Sat %v", grid[0][0], grid[5][0])
+	}
+	// Sunday the 30th closes the fifth and last week.
+	if grid[6][4] == nil || grid[6][4].Name != "2025-11-30" {
+		t.Errorf("last week Sunday = %v, want 2025-11-30", grid[6][4])
+	}
+}
+
+func TestBuildEmpty(t *testing.T) {
+	month := Build(time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), nil)
+	if len(month.Days) != 28 || month.ActiveDays != 0 || month.Busiest() != nil {
+		t.Errorf("Build(empty February) = %d days, %d active", len(month.Days), month.ActiveDays)
+	}
+	// February 2025 starts on a Saturday, so it touches five weeks.
+	if got := len(month.Grid()[0]); got != 5 {
+		t.Errorf("February 2025 spans %d weeks, want 5", got)
+	}
+}
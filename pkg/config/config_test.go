@@ -1,10 +1,15 @@
 package config
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
+
+	"gopkg.in/yaml.v3"
 )
 
 func TestDefault(t *testing.T) {
@@ -47,14 +52,344 @@ func TestConfigValidate(t *testing.T) {
 			config:  Default(),
 			wantErr: false,
 		},
+		{
+			name: "invalid session pattern",
+			config: func() *Config {
+				cfg := Default()
+				cfg.Discovery.SessionPatterns = []string{"sess_[0-9"}
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "invalid discovery file pattern",
+			config: func() *Config {
+				cfg := Default()
+				cfg.Discovery.Files = []string{"~/wrapper/[usage.jsonl"}
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "invalid name validation mode",
+			config: func() *Config {
+				cfg := Default()
+				cfg.Session.NameValidation = "loose"
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "valid serve tokens",
+			config: func() *Config {
+				cfg := Default()
+				cfg.Serve.Tokens = []ServeToken{
+					{Name: "dashboard", TokenEnv: "DASH_TOKEN", Scopes: []string{"read"}},
+					{Name: "ops", Token: "s3cret", Scopes: []string{"read", "admin"}},
+					{Name: "hooks", Token: "h00ks", Scopes: []string{"ingest"}},
+				}
+				return cfg
+			}(),
+			wantErr: false,
+		},
+		{
+			name: "valid serve proxy settings",
+			config: func() *Config {
+				cfg := Default()
+				cfg.Serve.BasePath = "/token-monitor"
+				cfg.Serve.CORSOrigins = []string{"https://dash.example.com", "http://localhost:3000", "*"}
+				cfg.Serve.TrustProxy = true
+				return cfg
+			}(),
+			wantErr: false,
+		},
+		{
+			name: "serve base path with trailing slash",
+			config: func() *Config {
+				cfg := Default()
+				cfg.Serve.BasePath = "/token-monitor/"
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "serve CORS origin with path",
+			config: func() *Config {
+				cfg := Default()
+				cfg.Serve.CORSOrigins = []string{"https://dash.example.com/app"}
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "serve token with both token and token_env",
+			config: func() *Config {
+				cfg := Default()
+				cfg.Serve.Tokens = []ServeToken{{Name: "x", Token: "a", TokenEnv: "B", Scopes: []string{"read"}}}
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "serve token with unknown scope",
+			config: func() *Config {
+				cfg := Default()
+				cfg.Serve.Tokens = []ServeToken{{Name: "x", Token: "a", Scopes: []string{"write"}}}
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "valid notify channels",
+			config: func() *Config {
+				cfg := Default()
+				cfg.Notify.Channels = []NotifyChannel{
+					{Name: "dev", Type: "discord", WebhookURLEnv: "DISCORD_WEBHOOK", Events: []string{"alert", "daily"}},
+				}
+				return cfg
+			}(),
+			wantErr: false,
+		},
+		{
+			name: "notify channel with unknown event",
+			config: func() *Config {
+				cfg := Default()
+				cfg.Notify.Channels = []NotifyChannel{
+					{Name: "dev", Type: "discord", WebhookURL: "https://discord.com/api/webhooks/1/x", Events: []string{"weekly"}},
+				}
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "notify channel without webhook",
+			config: func() *Config {
+				cfg := Default()
+				cfg.Notify.Channels = []NotifyChannel{{Name: "dev", Type: "discord", Events: []string{"daily"}}}
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "valid MQTT settings",
+			config: func() *Config {
+				cfg := Default()
+				cfg.MQTT.Broker = "tcp://homeassistant.local:1883"
+				return cfg
+			}(),
+			wantErr: false,
+		},
+		{
+			name: "MQTT broker without scheme",
+			config: func() *Config {
+				cfg := Default()
+				cfg.MQTT.Broker = "homeassistant.local:1883"
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "MQTT topic with wildcard",
+			config: func() *Config {
+				cfg := Default()
+				cfg.MQTT.Broker = "tcp://localhost"
+				cfg.MQTT.Topic = "token-monitor/#"
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "valid footprint rates",
+			config: func() *Config {
+				cfg := Default()
+				cfg.Footprint.GramsPerMTok = map[string]float64{"opus": 300, "default": 100}
+				return cfg
+			}(),
+			wantErr: false,
+		},
+		{
+			name: "footprint rate for unknown class",
+			config: func() *Config {
+				cfg := Default()
+				cfg.Footprint.GramsPerMTok = map[string]float64{"gpt": 100}
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "negative footprint rate",
+			config: func() *Config {
+				cfg := Default()
+				cfg.Footprint.GramsPerMTok = map[string]float64{"haiku": -1}
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "telemetry endpoint without scheme",
+			config: func() *Config {
+				cfg := Default()
+				cfg.Telemetry.Endpoint = "telemetry.example.com/report"
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "unknown missing session ID policy",
+			config: func() *Config {
+				cfg := Default()
+				cfg.Discovery.MissingSessionID = "guess"
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "pricing rule with malformed date",
+			config: func() *Config {
+				cfg := Default()
+				price := 10.0
+				cfg.Pricing.Rules = []PriceRuleConfig{{Model: "opus", Until: "2025/11/24", InputPerMTok: &price}}
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "pricing rule without overrides",
+			config: func() *Config {
+				cfg := Default()
+				cfg.Pricing.Rules = []PriceRuleConfig{{Model: "opus"}}
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "valid pricing discount",
+			config: func() *Config {
+				cfg := Default()
+				discount := 0.8
+				cfg.Pricing.Rules = []PriceRuleConfig{{From: "2025-01-01", Multiplier: &discount}}
+				return cfg
+			}(),
+			wantErr: false,
+		},
+		{
+			name: "team plan currency without exchange rate",
+			config: func() *Config {
+				cfg := Default()
+				cfg.Team.Currency = "EUR"
+				cfg.Team.ExchangeRates = map[string]float64{"USD": 0.9}
+				cfg.Team.Plans = []PlanConfig{{Name: "pro", Price: 20, Currency: "GBP"}}
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "team member on two plans",
+			config: func() *Config {
+				cfg := Default()
+				cfg.Team.Plans = []PlanConfig{
+					{Name: "pro", Price: 20, Members: []string{"alice"}},
+					{Name: "max", Price: 200, Members: []string{"alice"}},
+				}
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "valid team plans",
+			config: func() *Config {
+				cfg := Default()
+				cfg.Team.Currency = "EUR"
+				cfg.Team.ExchangeRates = map[string]float64{"USD": 0.9}
+				cfg.Team.Plans = []PlanConfig{{Name: "pro", Price: 20, Currency: "USD", Members: []string{"alice"}}}
+				return cfg
+			}(),
+			wantErr: false,
+		},
+		{
+			name: "team share without member",
+			config: func() *Config {
+				cfg := Default()
+				cfg.Team.Share.Endpoint = "https://team.example.com"
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "team share endpoint not a URL",
+			config: func() *Config {
+				cfg := Default()
+				cfg.Team.Share = TeamShareConfig{Endpoint: "team.example.com", Member: "alice"}
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "team share token reference malformed",
+			config: func() *Config {
+				cfg := Default()
+				cfg.Team.Share = TeamShareConfig{Endpoint: "https://team.example.com", Member: "alice", Token: "${ENV:}"}
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "valid team share",
+			config: func() *Config {
+				cfg := Default()
+				cfg.Team.Share = TeamShareConfig{Endpoint: "https://team.example.com/tm", Member: "alice", Token: "${ENV:TEAM_MONITOR_TOKEN}", Interval: time.Hour}
+				return cfg
+			}(),
+			wantErr: false,
+		},
+		{
+			name: "unknown session attribution",
+			config: func() *Config {
+				cfg := Default()
+				cfg.Discovery.SessionAttribution = "parent"
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "negative max entries per read",
+			config: func() *Config {
+				cfg := Default()
+				cfg.Performance.MaxEntriesPerRead = -1
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "duplicate serve token names",
+			config: func() *Config {
+				cfg := Default()
+				cfg.Serve.Tokens = []ServeToken{
+					{Name: "x", Token: "a", Scopes: []string{"read"}},
+					{Name: "x", Token: "b", Scopes: []string{"read"}},
+				}
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "invalid max poll interval",
+			config: func() *Config {
+				cfg := Default()
+				cfg.Monitoring.MaxPollInterval = 0
+				return cfg
+			}(),
+			wantErr: true,
+		},
 		{
 			name: "no claude directories",
 			config: &Config{
-				ClaudeConfigDirs: []string{},
+				ClaudeConfigDirs: []ClaudeDir{},
 				Monitoring: MonitoringConfig{
 					WatchInterval:    1 * time.Second,
 					UpdateFrequency:  1 * time.Second,
 					SessionRetention: 1 * time.Hour,
+					MaxPollInterval:  30 * time.Second,
 				},
 				Performance: PerformanceConfig{
 					WorkerPoolSize: 5,
@@ -75,11 +410,12 @@ func TestConfigValidate(t *testing.T) {
 		{
 			name: "invalid watch interval",
 			config: &Config{
-				ClaudeConfigDirs: []string{"/path"},
+				ClaudeConfigDirs: ClaudeDirs("/path"),
 				Monitoring: MonitoringConfig{
 					WatchInterval:    0,
 					UpdateFrequency:  1 * time.Second,
 					SessionRetention: 1 * time.Hour,
+					MaxPollInterval:  30 * time.Second,
 				},
 				Performance: PerformanceConfig{
 					WorkerPoolSize: 5,
@@ -100,11 +436,12 @@ func TestConfigValidate(t *testing.T) {
 		{
 			name: "invalid worker pool size",
 			config: &Config{
-				ClaudeConfigDirs: []string{"/path"},
+				ClaudeConfigDirs: ClaudeDirs("/path"),
 				Monitoring: MonitoringConfig{
 					WatchInterval:    1 * time.Second,
 					UpdateFrequency:  1 * time.Second,
 					SessionRetention: 1 * time.Hour,
+					MaxPollInterval:  30 * time.Second,
 				},
 				Performance: PerformanceConfig{
 					WorkerPoolSize: 0,
@@ -125,11 +462,12 @@ func TestConfigValidate(t *testing.T) {
 		{
 			name: "invalid display mode",
 			config: &Config{
-				ClaudeConfigDirs: []string{"/path"},
+				ClaudeConfigDirs: ClaudeDirs("/path"),
 				Monitoring: MonitoringConfig{
 					WatchInterval:    1 * time.Second,
 					UpdateFrequency:  1 * time.Second,
 					SessionRetention: 1 * time.Hour,
+					MaxPollInterval:  30 * time.Second,
 				},
 				Performance: PerformanceConfig{
 					WorkerPoolSize: 5,
@@ -147,14 +485,78 @@ func TestConfigValidate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "invalid locale",
+			config: &Config{
+				ClaudeConfigDirs: ClaudeDirs("/path"),
+				Monitoring: MonitoringConfig{
+					WatchInterval:    1 * time.Second,
+					UpdateFrequency:  1 * time.Second,
+					SessionRetention: 1 * time.Hour,
+					MaxPollInterval:  30 * time.Second,
+				},
+				Performance: PerformanceConfig{
+					WorkerPoolSize: 5,
+					CacheSize:      100,
+					BatchWindow:    100 * time.Millisecond,
+				},
+				Display: DisplayConfig{
+					DefaultMode: "live",
+					RefreshRate: 1 * time.Second,
+					Locale:      "klingon",
+				},
+				Logging: LoggingConfig{
+					Level:  "info",
+					Format: "text",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid units",
+			config: &Config{
+				ClaudeConfigDirs: ClaudeDirs("/path"),
+				Monitoring: MonitoringConfig{
+					WatchInterval:    1 * time.Second,
+					UpdateFrequency:  1 * time.Second,
+					SessionRetention: 1 * time.Hour,
+					MaxPollInterval:  30 * time.Second,
+				},
+				Performance: PerformanceConfig{
+					WorkerPoolSize: 5,
+					CacheSize:      100,
+					BatchWindow:    100 * time.Millisecond,
+				},
+				Display: DisplayConfig{
+					DefaultMode: "live",
+					RefreshRate: 1 * time.Second,
+					Units:       "usd",
+				},
+				Logging: LoggingConfig{
+					Level:  "info",
+					Format: "text",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "archive threshold not after idle",
+			config: func() *Config {
+				cfg := Default()
+				cfg.Session.ArchiveAfter = cfg.Session.IdleAfter
+				return cfg
+			}(),
+			wantErr: true,
+		},
 		{
 			name: "invalid log level",
 			config: &Config{
-				ClaudeConfigDirs: []string{"/path"},
+				ClaudeConfigDirs: ClaudeDirs("/path"),
 				Monitoring: MonitoringConfig{
 					WatchInterval:    1 * time.Second,
 					UpdateFrequency:  1 * time.Second,
 					SessionRetention: 1 * time.Hour,
+					MaxPollInterval:  30 * time.Second,
 				},
 				Performance: PerformanceConfig{
 					WorkerPoolSize: 5,
@@ -184,6 +586,38 @@ func TestConfigValidate(t *testing.T) {
 	}
 }
 
+func TestConfigProblems(t *testing.T) {
+	if problems := Default().Problems(); problems != nil {
+		t.Errorf("Default().Problems() = %+v, want none", problems)
+	}
+
+	cfg := Default()
+	cfg.Monitoring.WatchInterval = 0
+	cfg.Logging.Level = "verbose"
+	cfg.Serve.Tokens = []ServeToken{{Name: "ci"}}
+	cfg.Performance.MaxEntriesPerRead = -1
+
+	want := []Problem{
+		{Key: "monitoring.watch_interval", Suggestion: "1s", Err: ErrInvalidWatchInterval},
+		{Key: "performance.max_entries_per_read", Suggestion: "0", Err: ErrInvalidReadLimits},
+		{Key: "logging.level", Suggestion: "info", Err: ErrInvalidLogLevel},
+		{Key: "serve.tokens", Err: ErrInvalidServeToken},
+	}
+	problems := cfg.Problems()
+	if len(problems) != len(want) {
+		t.Fatalf("Problems() = %+v, want %d problems", problems, len(want))
+	}
+	for i, w := range want {
+		p := problems[i]
+		if p.Key != w.Key || p.Suggestion != w.Suggestion || !errors.Is(p.Err, w.Err) || p.Message != p.Err.Error() {
+			t.Errorf("Problems()[%d] = %+v, want key %s, suggestion %q, %v", i, p, w.Key, w.Suggestion, w.Err)
+		}
+	}
+	if cfg.Logging.Level != "verbose" {
+		t.Error("Problems() modified the configuration")
+	}
+}
+
 func TestLoadFromFile(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -241,6 +675,43 @@ logging:
 				}
 			},
 		},
+		{
+			name: "labeled claude dirs",
+			content: `
+claude_config_dirs:
+  - /path/to/claude1
+  - path: ~/work/.claude/projects
+    label: work
+`,
+			check: func(t *testing.T, cfg *Config) {
+				want := []ClaudeDir{
+					{Path: "/path/to/claude1"},
+					{Path: "~/work/.claude/projects", Label: "work"},
+				}
+				if !reflect.DeepEqual(cfg.ClaudeConfigDirs, want) {
+					t.Errorf("ClaudeConfigDirs = %+v, want %+v", cfg.ClaudeConfigDirs, want)
+				}
+				labels := cfg.ClaudeDirLabels()
+				if len(labels) != 1 || labels["~/work/.claude/projects"] != "work" {
+					t.Errorf("ClaudeDirLabels() = %v", labels)
+				}
+			},
+		},
+		{
+			name: "watch without keyboard input",
+			content: `
+monitoring:
+  no_input: true
+`,
+			check: func(t *testing.T, cfg *Config) {
+				if !cfg.Monitoring.NoInput {
+					t.Error("NoInput = false, want true")
+				}
+				if cfg.Monitoring.WatchInterval != Default().Monitoring.WatchInterval {
+					t.Errorf("WatchInterval = %v, want the default", cfg.Monitoring.WatchInterval)
+				}
+			},
+		},
 		{
 			name:    "invalid yaml",
 			content: `invalid: yaml: content: [`,
@@ -383,8 +854,8 @@ func TestEnvVarOverrides(t *testing.T) {
 	if len(cfg.ClaudeConfigDirs) != 2 {
 		t.Errorf("got %d claude dirs, want 2", len(cfg.ClaudeConfigDirs))
 	}
-	if cfg.ClaudeConfigDirs[0] != "/env/dir1" {
-		t.Errorf("ClaudeConfigDirs[0] = %s, want /env/dir1", cfg.ClaudeConfigDirs[0])
+	if cfg.ClaudeConfigDirs[0].Path != "/env/dir1" {
+		t.Errorf("ClaudeConfigDirs[0] = %s, want /env/dir1", cfg.ClaudeConfigDirs[0].Path)
 	}
 
 	if cfg.Storage.DBPath != "/env/db.db" {
@@ -396,6 +867,72 @@ func TestEnvVarOverrides(t *testing.T) {
 	}
 }
 
+func TestLocaleEnvVar(t *testing.T) {
+	t.Setenv("TOKEN_MONITOR_LANG", "ko_KR.UTF-8")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Display.Locale != "ko_KR.UTF-8" {
+		t.Errorf("Locale = %s, want ko_KR.UTF-8", cfg.Display.Locale)
+	}
+
+	t.Setenv("TOKEN_MONITOR_LANG", "xx")
+	if _, err := Load(); !errors.Is(err, ErrInvalidLocale) {
+		t.Errorf("Load() with unsupported locale error = %v, want ErrInvalidLocale", err)
+	}
+}
+
+func TestAccessibleEnvVar(t *testing.T) {
+	t.Setenv("TOKEN_MONITOR_ACCESSIBLE", "1")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !cfg.Display.Accessible {
+		t.Error("Accessible = false, want true with TOKEN_MONITOR_ACCESSIBLE=1")
+	}
+}
+
+func TestCommandDefaults(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "config.yaml")
+	content := `
+defaults:
+  stats:
+    group_by: [model, date]
+    cost: true
+  watch:
+    refresh: 2s
+`
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	cfg, err := NewLoader(path).Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	stats := cfg.Defaults.For("stats")
+	if stats["group-by"] != "model,date" {
+		t.Errorf("stats group-by = %q, want model,date", stats["group-by"])
+	}
+	if stats["cost"] != "true" {
+		t.Errorf("stats cost = %q, want true", stats["cost"])
+	}
+
+	if got := cfg.Defaults.For("watch")["refresh"]; got != "2s" {
+		t.Errorf("watch refresh = %q, want 2s", got)
+	}
+
+	if got := cfg.Defaults.For("list"); got != nil {
+		t.Errorf("list defaults = %v, want nil", got)
+	}
+}
+
 // Benchmark config loading.
 func BenchmarkLoad(b *testing.B) {
 	b.ResetTimer()
@@ -417,3 +954,93 @@ func BenchmarkValidate(b *testing.B) {
 		}
 	}
 }
+
+func TestClaudeDirYAMLRoundTrip(t *testing.T) {
+	dirs := []ClaudeDir{
+		{Path: "/plain"},
+		{Path: "/labeled", Label: "work"},
+	}
+
+	data, err := yaml.Marshal(dirs)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if !strings.Contains(string(data), "- /plain\n") {
+		t.Errorf("unlabeled dir not written as a plain path:\n%s", data)
+	}
+
+	var got []ClaudeDir
+	if err := yaml.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, dirs) {
+		t.Errorf("round trip = %+v, want %+v", got, dirs)
+	}
+}
+
+func TestRulesRoundTrip(t *testing.T) {
+	cfg := Default()
+	cfg.Defaults = CommandDefaults{"watch": {"format": "simple", "eco": true}}
+	cfg.Aliases = map[string]string{"daily": "report -days 1"}
+
+	data, err := yaml.Marshal(cfg.ExportRules())
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	rules, err := ParseRules(data)
+	if err != nil {
+		t.Fatalf("ParseRules() error = %v", err)
+	}
+	if !reflect.DeepEqual(rules, cfg.ExportRules()) {
+		t.Errorf("round trip = %+v, want %+v", rules, cfg.ExportRules())
+	}
+
+	if _, err := ParseRules(nil); err != nil {
+		t.Errorf("ParseRules(empty) error = %v", err)
+	}
+	for _, bad := range []string{
+		"budgets:\n  daily: 10\n",
+		"aliases: [1, 2]\n",
+		"aliases:\n  daily: \"\"\n",
+	} {
+		if _, err := ParseRules([]byte(bad)); !errors.Is(err, ErrInvalidRules) {
+			t.Errorf("ParseRules(%q) error = %v, want ErrInvalidRules", bad, err)
+		}
+	}
+}
+
+func TestImportRules(t *testing.T) {
+	cfg := Default()
+	cfg.Defaults = CommandDefaults{"watch": {"format": "simple"}, "stats": {"compact": true}}
+	cfg.Aliases = map[string]string{"daily": "report -days 1", "mine": "stats -top 5"}
+
+	rules := Rules{
+		Defaults: CommandDefaults{"watch": {"format": "table", "eco": true}},
+		Aliases:  map[string]string{"daily": "report -days 1", "weekly": "report -days 7"},
+	}
+
+	merged := *cfg
+	changes := merged.ImportRules(rules, false)
+	want := []RuleChange{
+		{Key: "aliases.weekly", New: "report -days 7"},
+		{Key: "defaults.watch.eco", New: "true"},
+		{Key: "defaults.watch.format", Old: "simple", New: "table"},
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("ImportRules() changes = %+v, want %+v", changes, want)
+	}
+	if merged.Aliases["mine"] == "" || merged.Defaults["stats"]["compact"] != true {
+		t.Errorf("ImportRules() dropped existing rules: %+v", merged.ExportRules())
+	}
+
+	replaced := Default()
+	replaced.Defaults = CommandDefaults{"stats": {"compact": true}}
+	replaced.Aliases = map[string]string{"mine": "stats -top 5"}
+	changes = replaced.ImportRules(rules, true)
+	if len(changes) != 6 {
+		t.Errorf("ImportRules(replace) changes = %+v, want 4 added and 2 removed", changes)
+	}
+	if !reflect.DeepEqual(replaced.ExportRules(), rules) {
+		t.Errorf("ImportRules(replace) = %+v, want %+v", replaced.ExportRules(), rules)
+	}
+}
@@ -12,11 +12,11 @@ import (
 // 2. ~/.claude/projects/ (legacy)
 //
 // Returns all directories that exist on the filesystem.
-func defaultClaudeDirs() []string {
+func defaultClaudeDirs() []ClaudeDir {
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
 		// Fallback to current directory if home dir not available
-		return []string{"."}
+		return ClaudeDirs(".")
 	}
 
 	candidates := []string{
@@ -34,10 +34,10 @@ func defaultClaudeDirs() []string {
 	// If no directories found, return the new default path
 	// (will be created by the application if needed)
 	if len(dirs) == 0 {
-		return []string{filepath.Join(homeDir, ".config", "claude", "projects")}
+		return ClaudeDirs(filepath.Join(homeDir, ".config", "claude", "projects"))
 	}
 
-	return dirs
+	return ClaudeDirs(dirs...)
 }
 
 // defaultDBPath returns the default database file path.
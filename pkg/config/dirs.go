@@ -0,0 +1,87 @@
+package config
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ClaudeDir is a Claude data directory with an optional label.
+//
+// In YAML it is either a plain path or a mapping with path and label:
+//
+//	claude_config_dirs:
+//	  - ~/.claude/projects
+//	  - path: /mnt/work-home/.claude/projects
+//	    label: work
+//
+// The label becomes the source dimension of entries read from the
+// directory, so usage from several Claude installations can be told apart.
+type ClaudeDir struct {
+	// Path is the directory containing Claude project directories.
+	Path string `yaml:"path"`
+
+	// Label names the installation (empty means unlabeled).
+	Label string `yaml:"label,omitempty"`
+}
+
+// UnmarshalYAML accepts a plain path or a path/label mapping.
+func (d *ClaudeDir) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*d = ClaudeDir{Path: node.Value}
+		return nil
+	}
+
+	// Decode through a separate type so this method is not called again.
+	type plain ClaudeDir
+	var p plain
+	if err := node.Decode(&p); err != nil {
+		return fmt.Errorf("claude_config_dirs entry: %w", err)
+	}
+	*d = ClaudeDir(p)
+	return nil
+}
+
+// MarshalYAML writes unlabeled directories as plain paths.
+func (d ClaudeDir) MarshalYAML() (interface{}, error) {
+	if d.Label == "" {
+		return d.Path, nil
+	}
+	type plain ClaudeDir
+	return plain(d), nil
+}
+
+// ClaudeDirs returns plain, unlabeled directory entries for paths.
+func ClaudeDirs(paths ...string) []ClaudeDir {
+	dirs := make([]ClaudeDir, len(paths))
+	for i, path := range paths {
+		dirs[i] = ClaudeDir{Path: path}
+	}
+	return dirs
+}
+
+// ClaudeDirPaths returns the configured Claude directory paths.
+func (c *Config) ClaudeDirPaths() []string {
+	paths := make([]string, len(c.ClaudeConfigDirs))
+	for i, dir := range c.ClaudeConfigDirs {
+		paths[i] = dir.Path
+	}
+	return paths
+}
+
+// ClaudeDirLabels maps each labeled Claude directory path to its label.
+//
+// Returns nil if no directory is labeled.
+func (c *Config) ClaudeDirLabels() map[string]string {
+	var labels map[string]string
+	for _, dir := range c.ClaudeConfigDirs {
+		if dir.Label == "" {
+			continue
+		}
+		if labels == nil {
+			labels = make(map[string]string)
+		}
+		labels[dir.Path] = dir.Label
+	}
+	return labels
+}
@@ -0,0 +1,330 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EditOp is the change Edit makes to a setting.
+type EditOp int
+
+const (
+	// EditSet replaces the value of the setting.
+	EditSet EditOp = iota
+
+	// EditAppend adds an item to a list setting.
+	EditAppend
+
+	// EditRemove removes matching items from a list setting, or an entry
+	// from a map setting.
+	EditRemove
+)
+
+// Edit changes one setting in the YAML configuration data and returns the
+// updated document. Comments, key order, and the other settings are kept,
+// so a hand-written file can be updated in place.
+//
+// The key is a dotted path of YAML keys, such as "monitoring.watch_interval"
+// or "defaults.watch.format"; list items are addressed by index, as in
+// "notify.channels.0.events". The value is parsed as YAML and must decode
+// into the setting's type, except for strings, which are taken literally.
+//
+// A list missing from the file starts from its default, so appending to
+// claude_config_dirs adds a directory instead of replacing the default one.
+// EditRemove matches list items by value, or by their path or name key for
+// items that are mappings.
+//
+// Edit does not validate the resulting configuration; see Parse.
+func Edit(data []byte, key string, op EditOp, value string) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidYAML, err)
+	}
+	if len(doc.Content) == 0 {
+		doc.Kind = yaml.DocumentNode
+		doc.Content = []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}
+	}
+	if !asMapping(doc.Content[0]) {
+		return nil, fmt.Errorf("%w: top level is not a mapping", ErrInvalidYAML)
+	}
+
+	node, typ, err := locate(doc.Content[0], key, op)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op {
+	case EditSet:
+		err = setNode(node, typ, key, value)
+	case EditAppend:
+		err = appendNode(node, typ, key, value)
+	case EditRemove:
+		err = removeNode(node, typ, key, value)
+	default:
+		err = fmt.Errorf("unknown edit operation %d", op)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(indentOf(data))
+	if err := enc.Encode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to marshal config: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("failed to marshal config: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// locate returns the node of the setting at key and the setting's type,
+// adding missing keys to the document. A missing list is added with its
+// default items.
+func locate(root *yaml.Node, key string, op EditOp) (*yaml.Node, reflect.Type, error) {
+	node := root
+	typ := reflect.TypeOf(Config{})
+	def := reflect.ValueOf(Default()).Elem()
+
+	parts := strings.Split(key, ".")
+	for i, part := range parts {
+		for typ.Kind() == reflect.Ptr {
+			typ = typ.Elem()
+			if def.IsValid() {
+				def = def.Elem()
+			}
+		}
+		parent := strings.Join(parts[:i], ".")
+
+		if typ.Kind() == reflect.Slice {
+			index, err := strconv.Atoi(part)
+			if err != nil || node.Kind != yaml.SequenceNode || index < 0 || index >= len(node.Content) {
+				return nil, nil, fmt.Errorf("%w: %s has no item %s", ErrUnknownKey, parent, part)
+			}
+			node, typ, def = node.Content[index], typ.Elem(), reflect.Value{}
+			continue
+		}
+
+		var next reflect.Type
+		var nextDef reflect.Value
+		switch typ.Kind() {
+		case reflect.Struct:
+			field, ok := fieldByTag(typ, part)
+			if !ok {
+				return nil, nil, fmt.Errorf("%w: %s", ErrUnknownKey, key)
+			}
+			next = field.Type
+			if def.IsValid() {
+				nextDef = def.FieldByIndex(field.Index)
+			}
+		case reflect.Map:
+			if typ.Key().Kind() != reflect.String {
+				return nil, nil, fmt.Errorf("%w: %s", ErrUnknownKey, key)
+			}
+			next = typ.Elem()
+			if def.IsValid() && !def.IsNil() {
+				nextDef = def.MapIndex(reflect.ValueOf(part).Convert(typ.Key()))
+			}
+		case reflect.Interface:
+			next = typ
+		default:
+			return nil, nil, fmt.Errorf("%w: %s is not a section", ErrUnknownKey, parent)
+		}
+
+		if !asMapping(node) {
+			return nil, nil, fmt.Errorf("%w: %s is not a mapping", ErrInvalidValue, parent)
+		}
+		child := mappingValue(node, part)
+		if child == nil {
+			child = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			if i == len(parts)-1 && op != EditSet {
+				var err error
+				if child, err = defaultNode(next, nextDef); err != nil {
+					return nil, nil, err
+				}
+			}
+			node.Content = append(node.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: part}, child)
+		}
+		node, typ, def = child, next, nextDef
+	}
+	return node, typ, nil
+}
+
+// fieldByTag returns the field of the struct type with the YAML key name.
+func fieldByTag(typ reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		tag, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if tag == name && field.IsExported() {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// asMapping reports whether node is a mapping, turning an empty value
+// (e.g. "mqtt:" with nothing under it) into an empty mapping.
+func asMapping(node *yaml.Node) bool {
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!null" {
+		node.Kind, node.Tag, node.Value = yaml.MappingNode, "!!map", ""
+	}
+	return node.Kind == yaml.MappingNode
+}
+
+// mappingValue returns the value node of key in the mapping, or nil.
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// defaultNode returns a node holding def, or an empty list or mapping
+// when the setting has no default.
+func defaultNode(typ reflect.Type, def reflect.Value) (*yaml.Node, error) {
+	if def.IsValid() && !def.IsZero() {
+		var node yaml.Node
+		if err := node.Encode(def.Interface()); err != nil {
+			return nil, fmt.Errorf("failed to encode default: %w", err)
+		}
+		return &node, nil
+	}
+	if typ.Kind() == reflect.Slice {
+		return &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}, nil
+	}
+	return &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}, nil
+}
+
+// valueNode parses value as a setting of type typ.
+func valueNode(typ reflect.Type, key, value string) (*yaml.Node, error) {
+	node := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+	if typ.Kind() != reflect.String {
+		var doc yaml.Node
+		if err := yaml.Unmarshal([]byte(value), &doc); err != nil {
+			return nil, fmt.Errorf("%w for %s: %v", ErrInvalidValue, key, err)
+		}
+		node = &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}
+		if len(doc.Content) > 0 {
+			node = doc.Content[0]
+		}
+	}
+
+	decoded := reflect.New(typ)
+	if err := node.Decode(decoded.Interface()); err != nil {
+		return nil, fmt.Errorf("%w for %s: %q: %v", ErrInvalidValue, key, value, err)
+	}
+
+	// Write numbers, booleans, and durations the way they are read back,
+	// e.g. "yes" as true.
+	switch typ.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		var normalized yaml.Node
+		if err := normalized.Encode(decoded.Elem().Interface()); err != nil {
+			return nil, fmt.Errorf("%w for %s: %q: %v", ErrInvalidValue, key, value, err)
+		}
+		node = &normalized
+	}
+	return node, nil
+}
+
+// setNode replaces the setting in node with value, keeping its comments.
+func setNode(node *yaml.Node, typ reflect.Type, key, value string) error {
+	replacement, err := valueNode(typ, key, value)
+	if err != nil {
+		return err
+	}
+	replacement.HeadComment = node.HeadComment
+	replacement.LineComment = node.LineComment
+	replacement.FootComment = node.FootComment
+	*node = *replacement
+	return nil
+}
+
+// appendNode adds value to the list in node.
+func appendNode(node *yaml.Node, typ reflect.Type, key, value string) error {
+	if typ.Kind() != reflect.Slice {
+		return fmt.Errorf("%w: %s is not a list", ErrInvalidValue, key)
+	}
+	item, err := valueNode(typ.Elem(), key, value)
+	if err != nil {
+		return err
+	}
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!null" {
+		node.Kind, node.Tag, node.Value = yaml.SequenceNode, "!!seq", ""
+	}
+	if node.Kind != yaml.SequenceNode {
+		return fmt.Errorf("%w: %s is not a list in the file", ErrInvalidValue, key)
+	}
+	node.Content = append(node.Content, item)
+	return nil
+}
+
+// removeNode removes the items matching value from the list in node, or
+// the entry named value from the map in node.
+func removeNode(node *yaml.Node, typ reflect.Type, key, value string) error {
+	var kept []*yaml.Node
+	switch {
+	case typ.Kind() == reflect.Slice && node.Kind == yaml.SequenceNode:
+		for _, item := range node.Content {
+			if !itemMatches(item, value) {
+				kept = append(kept, item)
+			}
+		}
+	case typ.Kind() == reflect.Map && node.Kind == yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value != value {
+				kept = append(kept, node.Content[i], node.Content[i+1])
+			}
+		}
+	default:
+		return fmt.Errorf("%w: %s is not a list or map", ErrInvalidValue, key)
+	}
+
+	if len(kept) == len(node.Content) {
+		return fmt.Errorf("%w: %q is not in %s", ErrInvalidValue, value, key)
+	}
+	node.Content = kept
+	return nil
+}
+
+// itemMatches reports whether a list item is value, or is a mapping whose
+// path or name is value.
+func itemMatches(item *yaml.Node, value string) bool {
+	switch item.Kind {
+	case yaml.ScalarNode:
+		return item.Value == value
+	case yaml.MappingNode:
+		for _, key := range []string{"path", "name"} {
+			if v := mappingValue(item, key); v != nil && v.Kind == yaml.ScalarNode && v.Value == value {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// indentOf returns the indentation used in data, or 4, the encoder's
+// default, for a flat or empty file.
+func indentOf(data []byte) int {
+	for _, line := range strings.Split(string(data), "\n") {
+		trimmed := strings.TrimLeft(line, " ")
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if n := len(line) - len(trimmed); n >= 2 {
+			return n
+		}
+	}
+	return 4
+}
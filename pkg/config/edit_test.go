@@ -0,0 +1,162 @@
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+const editFile = `# Token monitor settings
+claude_config_dirs:
+  - /data/claude # main install
+monitoring:
+  # How often to poll
+  watch_interval: 1s # fast
+logging:
+  level: info
+`
+
+func TestEditKeepsComments(t *testing.T) {
+	data, err := Edit([]byte(editFile), "monitoring.watch_interval", EditSet, "2s")
+	if err != nil {
+		t.Fatalf("Edit() error = %v", err)
+	}
+
+	got := string(data)
+	for _, want := range []string{
+		"# Token monitor settings",
+		"# How often to poll",
+		"watch_interval: 2s # fast",
+		"- /data/claude # main install",
+		"\n  level: info\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Edit() =\n%s\nwant %q", got, want)
+		}
+	}
+
+	cfg, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if cfg.Monitoring.WatchInterval != 2*time.Second {
+		t.Errorf("WatchInterval = %v, want 2s", cfg.Monitoring.WatchInterval)
+	}
+}
+
+func TestEditLists(t *testing.T) {
+	data, err := Edit([]byte(editFile), "claude_config_dirs", EditAppend, "~/.claude/projects")
+	if err != nil {
+		t.Fatalf("Edit(+=) error = %v", err)
+	}
+	cfg, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if got := cfg.ClaudeDirPaths(); len(got) != 2 || got[1] != "~/.claude/projects" {
+		t.Errorf("ClaudeDirPaths() after += = %v, want the new directory last", got)
+	}
+
+	data, err = Edit(data, "claude_config_dirs", EditRemove, "/data/claude")
+	if err != nil {
+		t.Fatalf("Edit(-=) error = %v", err)
+	}
+	if strings.Contains(string(data), "/data/claude") {
+		t.Errorf("Edit(-=) =\n%s\nwant /data/claude removed", data)
+	}
+
+	if _, err := Edit(data, "claude_config_dirs", EditRemove, "/missing"); !errors.Is(err, ErrInvalidValue) {
+		t.Errorf("Edit(-= missing item) error = %v, want ErrInvalidValue", err)
+	}
+	if _, err := Edit(data, "logging.level", EditAppend, "debug"); !errors.Is(err, ErrInvalidValue) {
+		t.Errorf("Edit(+= scalar) error = %v, want ErrInvalidValue", err)
+	}
+}
+
+func TestEditSeedsDefaultList(t *testing.T) {
+	data, err := Edit(nil, "claude_config_dirs", EditAppend, "/extra")
+	if err != nil {
+		t.Fatalf("Edit() error = %v", err)
+	}
+	cfg, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	want := append(Default().ClaudeDirPaths(), "/extra")
+	got := cfg.ClaudeDirPaths()
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("ClaudeDirPaths() = %v, want the defaults and /extra %v", got, want)
+	}
+}
+
+func TestEditNestedKeys(t *testing.T) {
+	data, err := Edit([]byte(editFile), "defaults.watch.eco", EditSet, "true")
+	if err != nil {
+		t.Fatalf("Edit(defaults.watch.eco) error = %v", err)
+	}
+	data, err = Edit(data, "notify.channels", EditAppend, "{name: ops, type: discord, webhook_url_env: OPS_HOOK, events: [alert]}")
+	if err != nil {
+		t.Fatalf("Edit(notify.channels+=) error = %v", err)
+	}
+	data, err = Edit(data, "notify.channels.0.events", EditAppend, "daily")
+	if err != nil {
+		t.Fatalf("Edit(notify.channels.0.events+=) error = %v", err)
+	}
+	data, err = Edit(data, "display.accessible", EditSet, "on")
+	if err != nil {
+		t.Fatalf("Edit(display.accessible) error = %v", err)
+	}
+
+	cfg, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse() error = %v\n%s", err, data)
+	}
+	if got := cfg.Defaults.For("watch")["eco"]; got != "true" {
+		t.Errorf("defaults.watch.eco = %q, want true", got)
+	}
+	if len(cfg.Notify.Channels) != 1 || !cfg.Notify.Channels[0].Wants("daily") {
+		t.Errorf("Notify.Channels = %+v, want ops with alert and daily", cfg.Notify.Channels)
+	}
+	if !cfg.Display.Accessible || !strings.Contains(string(data), "accessible: true") {
+		t.Errorf("display.accessible = %v in\n%s\nwant true", cfg.Display.Accessible, data)
+	}
+
+	data, err = Edit(data, "notify.channels", EditRemove, "ops")
+	if err != nil {
+		t.Fatalf("Edit(notify.channels-=) error = %v", err)
+	}
+	if cfg, err := Parse(data); err != nil || len(cfg.Notify.Channels) != 0 {
+		t.Errorf("Parse() after removing ops = %v, want no channels", err)
+	}
+}
+
+func TestEditErrors(t *testing.T) {
+	tests := []struct {
+		key   string
+		value string
+		want  error
+	}{
+		{"logging.colour", "true", ErrUnknownKey},
+		{"nosuch", "1", ErrUnknownKey},
+		{"logging.level.name", "x", ErrUnknownKey},
+		{"notify.channels.3.name", "x", ErrUnknownKey},
+		{"performance.worker_pool_size", "many", ErrInvalidValue},
+		{"monitoring.watch_interval", "soon", ErrInvalidValue},
+	}
+	for _, tt := range tests {
+		if _, err := Edit([]byte(editFile), tt.key, EditSet, tt.value); !errors.Is(err, tt.want) {
+			t.Errorf("Edit(%s=%s) error = %v, want %v", tt.key, tt.value, err, tt.want)
+		}
+	}
+
+	// Strings are taken literally, even when they look like YAML.
+	data, err := Edit([]byte(editFile), "logging.output", EditSet, "true")
+	if err != nil {
+		t.Fatalf("Edit(logging.output) error = %v", err)
+	}
+	if cfg, err := Parse(data); err != nil || cfg.Logging.Output != "true" {
+		t.Errorf("Parse() = %v, want logging.output \"true\"", err)
+	}
+}
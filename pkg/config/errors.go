@@ -7,6 +7,21 @@ var (
 	// ErrNoClaudeDirs is returned when no Claude config directories are specified.
 	ErrNoClaudeDirs = errors.New("no Claude config directories specified")
 
+	// ErrInvalidClaudeDir is returned when a Claude directory entry has no path.
+	ErrInvalidClaudeDir = errors.New("invalid claude_config_dirs entry")
+
+	// ErrInvalidSessionPattern is returned when a session pattern is not a valid regex.
+	ErrInvalidSessionPattern = errors.New("invalid session pattern")
+
+	// ErrInvalidDiscoveryFile is returned when a discovery file is not a valid glob pattern.
+	ErrInvalidDiscoveryFile = errors.New("invalid discovery file pattern")
+
+	// ErrInvalidMissingSessionID is returned when the missing session ID policy is not recognized.
+	ErrInvalidMissingSessionID = errors.New("invalid missing_session_id: must be drop or filename")
+
+	// ErrInvalidSessionAttribution is returned when the session attribution is not recognized.
+	ErrInvalidSessionAttribution = errors.New("invalid session_attribution: must be embedded or file")
+
 	// ErrInvalidWatchInterval is returned when watch interval is <= 0.
 	ErrInvalidWatchInterval = errors.New("invalid watch interval: must be > 0")
 
@@ -16,30 +31,104 @@ var (
 	// ErrInvalidSessionRetention is returned when session retention is <= 0.
 	ErrInvalidSessionRetention = errors.New("invalid session retention: must be > 0")
 
+	// ErrInvalidMaxPollInterval is returned when max poll interval is <= 0.
+	ErrInvalidMaxPollInterval = errors.New("invalid max poll interval: must be > 0")
+
+	// ErrInvalidBlockTokenLimit is returned when the block token limit is < 0.
+	ErrInvalidBlockTokenLimit = errors.New("invalid block token limit: must be >= 0")
+
+	// ErrInvalidContextWindow is returned when the context window is < 0.
+	ErrInvalidContextWindow = errors.New("invalid context window: must be >= 0")
+
 	// ErrInvalidWorkerPoolSize is returned when worker pool size is <= 0.
 	ErrInvalidWorkerPoolSize = errors.New("invalid worker pool size: must be > 0")
 
+	// ErrInvalidReadLimits is returned when the large-file limits are out of range.
+	ErrInvalidReadLimits = errors.New("invalid read limits: max_file_size_mb must be > 0 and max_entries_per_read >= 0")
+
 	// ErrInvalidCacheSize is returned when cache size is <= 0.
 	ErrInvalidCacheSize = errors.New("invalid cache size: must be > 0")
 
 	// ErrInvalidBatchWindow is returned when batch window is <= 0.
 	ErrInvalidBatchWindow = errors.New("invalid batch window: must be > 0")
 
+	// ErrInvalidReadRetry is returned when the re-read delay or count is negative.
+	ErrInvalidReadRetry = errors.New("invalid read retry: read_retry_delay and read_retries must be >= 0")
+
 	// ErrInvalidDisplayMode is returned when display mode is not recognized.
 	ErrInvalidDisplayMode = errors.New("invalid display mode: must be live, compact, table, or json")
 
 	// ErrInvalidRefreshRate is returned when refresh rate is <= 0.
 	ErrInvalidRefreshRate = errors.New("invalid refresh rate: must be > 0")
 
+	// ErrInvalidLocale is returned when the display locale is not supported.
+	ErrInvalidLocale = errors.New("invalid locale: must be auto, en, or ko")
+
+	// ErrInvalidUnits is returned when the display units are not recognized.
+	ErrInvalidUnits = errors.New("invalid units: must be tokens, k, or cost")
+
+	// ErrInvalidNameValidation is returned when the name validation mode is not recognized.
+	ErrInvalidNameValidation = errors.New("invalid name validation mode: must be strict or relaxed")
+
+	// ErrInvalidNameNormalization is returned when the name normalization mode is not recognized.
+	ErrInvalidNameNormalization = errors.New("invalid name normalization mode: must be trim, lower, or none")
+
+	// ErrInvalidSessionStates is returned when the lifecycle thresholds are out of order.
+	ErrInvalidSessionStates = errors.New("invalid session states: idle_after must be > 0 and archive_after > idle_after")
+
 	// ErrInvalidLogLevel is returned when log level is not recognized.
 	ErrInvalidLogLevel = errors.New("invalid log level: must be debug, info, warn, or error")
 
 	// ErrInvalidLogFormat is returned when log format is not recognized.
 	ErrInvalidLogFormat = errors.New("invalid log format: must be text or json")
 
+	// ErrInvalidBasePath is returned when the serve base path is malformed.
+	ErrInvalidBasePath = errors.New("invalid serve base path")
+
+	// ErrInvalidCORSOrigin is returned when a CORS origin is malformed.
+	ErrInvalidCORSOrigin = errors.New("invalid CORS origin")
+
+	// ErrInvalidServeToken is returned when a serve API token is incomplete.
+	ErrInvalidServeToken = errors.New("invalid serve token")
+
+	// ErrInvalidMQTT is returned when the MQTT settings are malformed.
+	ErrInvalidMQTT = errors.New("invalid MQTT settings")
+
+	// ErrInvalidFootprint is returned when a footprint rate is negative or
+	// names an unknown model class.
+	ErrInvalidFootprint = errors.New("invalid footprint settings")
+
+	// ErrInvalidRetention is returned when a retention period is negative.
+	ErrInvalidRetention = errors.New("invalid retention: rollup_days, baseline_days, and focus_days must be >= 0")
+
+	// ErrInvalidPricing is returned when a pricing rule is malformed.
+	ErrInvalidPricing = errors.New("invalid pricing rule")
+
+	// ErrInvalidTeam is returned when the team plans or currencies are
+	// malformed.
+	ErrInvalidTeam = errors.New("invalid team settings")
+
+	// ErrInvalidTelemetry is returned when the telemetry endpoint is malformed.
+	ErrInvalidTelemetry = errors.New("invalid telemetry settings")
+
+	// ErrInvalidNotifyChannel is returned when a notification channel is incomplete.
+	ErrInvalidNotifyChannel = errors.New("invalid notify channel")
+
 	// ErrConfigNotFound is returned when config file is not found.
 	ErrConfigNotFound = errors.New("config file not found")
 
 	// ErrInvalidYAML is returned when config file has invalid YAML syntax.
 	ErrInvalidYAML = errors.New("invalid YAML syntax in config file")
+
+	// ErrInvalidRules is returned when a rules snippet cannot be imported.
+	ErrInvalidRules = errors.New("invalid rules")
+
+	// ErrSecretNotFound is returned when a secret reference cannot be resolved.
+	ErrSecretNotFound = errors.New("secret not found")
+
+	// ErrUnknownKey is returned when an edited key is not a configuration setting.
+	ErrUnknownKey = errors.New("unknown configuration key")
+
+	// ErrInvalidValue is returned when an edited value does not fit the setting.
+	ErrInvalidValue = errors.New("invalid configuration value")
 )
@@ -0,0 +1,17 @@
+//go:build darwin
+
+package config
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// keychainLookup reads a generic password from the macOS login keychain.
+func keychainLookup(service, account string) (string, error) {
+	out, err := exec.Command("security", "find-generic-password", "-s", service, "-a", account, "-w").Output() //nolint:gosec // fixed binary, configured entry
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(out), "\n"), nil
+}
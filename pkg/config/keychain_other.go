@@ -0,0 +1,19 @@
+//go:build !darwin && !windows
+
+package config
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// keychainLookup reads a secret from the Secret Service (GNOME Keyring,
+// KWallet) with secret-tool, matching the service and account attributes
+// that "secret-tool store service <service> account <account>" sets.
+func keychainLookup(service, account string) (string, error) {
+	out, err := exec.Command("secret-tool", "lookup", "service", service, "account", account).Output() //nolint:gosec // fixed binary, configured entry
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(out), "\n"), nil
+}
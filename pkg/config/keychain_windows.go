@@ -0,0 +1,10 @@
+//go:build windows
+
+package config
+
+import "errors"
+
+// keychainLookup is not supported on Windows; use ${ENV:NAME} instead.
+func keychainLookup(_, _ string) (string, error) {
+	return "", errors.New("keychain references are not supported on Windows")
+}
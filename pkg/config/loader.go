@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"gopkg.in/yaml.v3"
@@ -19,6 +20,10 @@ type Loader interface {
 	// Returns the merged configuration or an error if validation fails.
 	Load() (*Config, error)
 
+	// LoadUnvalidated is Load without validation, so every problem of an
+	// invalid configuration can be reported (see Config.Problems).
+	LoadUnvalidated() (*Config, error)
+
 	// LoadFromFile loads configuration from a specific file.
 	LoadFromFile(path string) (*Config, error)
 }
@@ -41,6 +46,21 @@ func NewLoader(configPath string) Loader {
 
 // Load implements Loader.Load.
 func (l *loader) Load() (*Config, error) {
+	cfg, err := l.LoadUnvalidated()
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate final configuration
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	return cfg, nil
+}
+
+// LoadUnvalidated implements Loader.LoadUnvalidated.
+func (l *loader) LoadUnvalidated() (*Config, error) {
 	// Start with default configuration
 	cfg := Default()
 
@@ -61,18 +81,12 @@ func (l *loader) Load() (*Config, error) {
 			// Otherwise, just use defaults
 		} else {
 			cfg = l.mergeConfigs(cfg, fileCfg)
+			cfg.unknownKeys = fileCfg.unknownKeys
 		}
 	}
 
 	// Apply environment variable overrides
-	cfg = l.applyEnvVars(cfg)
-
-	// Validate final configuration
-	if err := cfg.Validate(); err != nil {
-		return nil, fmt.Errorf("invalid configuration: %w", err)
-	}
-
-	return cfg, nil
+	return l.applyEnvVars(cfg), nil
 }
 
 // LoadFromFile implements Loader.LoadFromFile.
@@ -89,6 +103,7 @@ func (l *loader) LoadFromFile(path string) (*Config, error) {
 	if err := yaml.Unmarshal(data, &cfg); err != nil {
 		return nil, fmt.Errorf("%w: %v", ErrInvalidYAML, err)
 	}
+	cfg.unknownKeys = FindUnknownKeys(data)
 
 	return &cfg, nil
 }
@@ -126,6 +141,23 @@ func (l *loader) mergeConfigs(base, override *Config) *Config {
 		result.ClaudeConfigDirs = override.ClaudeConfigDirs
 	}
 
+	// Merge discovery config
+	if len(override.Discovery.SessionPatterns) > 0 {
+		result.Discovery.SessionPatterns = override.Discovery.SessionPatterns
+	}
+	if override.Discovery.AnyJSONL {
+		result.Discovery.AnyJSONL = true
+	}
+	if len(override.Discovery.Files) > 0 {
+		result.Discovery.Files = override.Discovery.Files
+	}
+	if override.Discovery.MissingSessionID != "" {
+		result.Discovery.MissingSessionID = override.Discovery.MissingSessionID
+	}
+	if override.Discovery.SessionAttribution != "" {
+		result.Discovery.SessionAttribution = override.Discovery.SessionAttribution
+	}
+
 	// Merge monitoring config
 	if override.Monitoring.WatchInterval > 0 {
 		result.Monitoring.WatchInterval = override.Monitoring.WatchInterval
@@ -136,6 +168,18 @@ func (l *loader) mergeConfigs(base, override *Config) *Config {
 	if override.Monitoring.SessionRetention > 0 {
 		result.Monitoring.SessionRetention = override.Monitoring.SessionRetention
 	}
+	if override.Monitoring.MaxPollInterval > 0 {
+		result.Monitoring.MaxPollInterval = override.Monitoring.MaxPollInterval
+	}
+	if override.Monitoring.BlockTokenLimit > 0 {
+		result.Monitoring.BlockTokenLimit = override.Monitoring.BlockTokenLimit
+	}
+	if override.Monitoring.ContextWindow > 0 {
+		result.Monitoring.ContextWindow = override.Monitoring.ContextWindow
+	}
+	if override.Monitoring.NoInput {
+		result.Monitoring.NoInput = true
+	}
 
 	// Merge performance config
 	if override.Performance.WorkerPoolSize > 0 {
@@ -147,6 +191,21 @@ func (l *loader) mergeConfigs(base, override *Config) *Config {
 	if override.Performance.BatchWindow > 0 {
 		result.Performance.BatchWindow = override.Performance.BatchWindow
 	}
+	if override.Performance.ReadRetryDelay > 0 {
+		result.Performance.ReadRetryDelay = override.Performance.ReadRetryDelay
+	}
+	if override.Performance.ReadRetries > 0 {
+		result.Performance.ReadRetries = override.Performance.ReadRetries
+	}
+	if override.Performance.LowPower {
+		result.Performance.LowPower = true
+	}
+	if override.Performance.MaxFileSizeMB > 0 {
+		result.Performance.MaxFileSizeMB = override.Performance.MaxFileSizeMB
+	}
+	if override.Performance.MaxEntriesPerRead > 0 {
+		result.Performance.MaxEntriesPerRead = override.Performance.MaxEntriesPerRead
+	}
 
 	// Merge display config
 	if override.Display.DefaultMode != "" {
@@ -157,6 +216,18 @@ func (l *loader) mergeConfigs(base, override *Config) *Config {
 	if override.Display.RefreshRate > 0 {
 		result.Display.RefreshRate = override.Display.RefreshRate
 	}
+	if override.Display.Locale != "" {
+		result.Display.Locale = override.Display.Locale
+	}
+	if override.Display.Accessible {
+		result.Display.Accessible = true
+	}
+	if override.Display.ASCII {
+		result.Display.ASCII = true
+	}
+	if override.Display.Units != "" {
+		result.Display.Units = override.Display.Units
+	}
 
 	// Merge storage config
 	if override.Storage.DBPath != "" {
@@ -166,6 +237,20 @@ func (l *loader) mergeConfigs(base, override *Config) *Config {
 		result.Storage.CacheDir = override.Storage.CacheDir
 	}
 
+	// Merge session config
+	if override.Session.NameValidation != "" {
+		result.Session.NameValidation = override.Session.NameValidation
+	}
+	if override.Session.NameNormalization != "" {
+		result.Session.NameNormalization = override.Session.NameNormalization
+	}
+	if override.Session.IdleAfter > 0 {
+		result.Session.IdleAfter = override.Session.IdleAfter
+	}
+	if override.Session.ArchiveAfter > 0 {
+		result.Session.ArchiveAfter = override.Session.ArchiveAfter
+	}
+
 	// Merge logging config
 	if override.Logging.Level != "" {
 		result.Logging.Level = override.Logging.Level
@@ -177,6 +262,113 @@ func (l *loader) mergeConfigs(base, override *Config) *Config {
 		result.Logging.Format = override.Logging.Format
 	}
 
+	// Merge command flag defaults
+	if len(override.Defaults) > 0 {
+		result.Defaults = override.Defaults
+	}
+
+	// Merge command aliases
+	if len(override.Aliases) > 0 {
+		result.Aliases = override.Aliases
+	}
+
+	// Merge serve config
+	if override.Serve.Addr != "" {
+		result.Serve.Addr = override.Serve.Addr
+	}
+	if override.Serve.ShutdownTimeout > 0 {
+		result.Serve.ShutdownTimeout = override.Serve.ShutdownTimeout
+	}
+	if len(override.Serve.Tokens) > 0 {
+		result.Serve.Tokens = override.Serve.Tokens
+	}
+	if override.Serve.BasePath != "" {
+		result.Serve.BasePath = override.Serve.BasePath
+	}
+	if len(override.Serve.CORSOrigins) > 0 {
+		result.Serve.CORSOrigins = override.Serve.CORSOrigins
+	}
+	if override.Serve.TrustProxy {
+		result.Serve.TrustProxy = true
+	}
+
+	// Merge MQTT config
+	if override.MQTT.Broker != "" {
+		result.MQTT.Broker = override.MQTT.Broker
+	}
+	if override.MQTT.Topic != "" {
+		result.MQTT.Topic = override.MQTT.Topic
+	}
+	if override.MQTT.ClientID != "" {
+		result.MQTT.ClientID = override.MQTT.ClientID
+	}
+	if override.MQTT.Username != "" {
+		result.MQTT.Username = override.MQTT.Username
+	}
+	if override.MQTT.PasswordEnv != "" {
+		result.MQTT.PasswordEnv = override.MQTT.PasswordEnv
+	}
+	if override.MQTT.Retain {
+		result.MQTT.Retain = true
+	}
+
+	// Merge notify config
+	if len(override.Notify.Channels) > 0 {
+		result.Notify.Channels = override.Notify.Channels
+	}
+
+	// Merge footprint config
+	if len(override.Footprint.GramsPerMTok) > 0 {
+		result.Footprint.GramsPerMTok = override.Footprint.GramsPerMTok
+	}
+
+	// Merge retention config
+	if override.Retention.RollupDays > 0 {
+		result.Retention.RollupDays = override.Retention.RollupDays
+	}
+	if override.Retention.BaselineDays > 0 {
+		result.Retention.BaselineDays = override.Retention.BaselineDays
+	}
+	if override.Retention.FocusDays > 0 {
+		result.Retention.FocusDays = override.Retention.FocusDays
+	}
+
+	// Merge pricing config
+	if len(override.Pricing.Rules) > 0 {
+		result.Pricing.Rules = override.Pricing.Rules
+	}
+
+	// Merge team config
+	if override.Team.Currency != "" {
+		result.Team.Currency = override.Team.Currency
+	}
+	if len(override.Team.ExchangeRates) > 0 {
+		result.Team.ExchangeRates = override.Team.ExchangeRates
+	}
+	if len(override.Team.Plans) > 0 {
+		result.Team.Plans = override.Team.Plans
+	}
+	if override.Team.Share.Endpoint != "" {
+		result.Team.Share.Endpoint = override.Team.Share.Endpoint
+	}
+	if override.Team.Share.Member != "" {
+		result.Team.Share.Member = override.Team.Share.Member
+	}
+	if override.Team.Share.Token != "" {
+		result.Team.Share.Token = override.Team.Share.Token
+	}
+	if override.Team.Share.Interval > 0 {
+		result.Team.Share.Interval = override.Team.Share.Interval
+	}
+
+	// Merge telemetry config
+	if override.Telemetry.Enabled {
+		result.Telemetry.Enabled = true
+	}
+	if override.Telemetry.Endpoint != "" {
+		result.Telemetry.Endpoint = override.Telemetry.Endpoint
+	}
+
 	return &result
 }
 
@@ -186,7 +378,11 @@ func (l *loader) mergeConfigs(base, override *Config) *Config {
 //   - CLAUDE_CONFIG_DIR: Comma-separated list of Claude directories
 //   - TOKEN_MONITOR_CONFIG: Path to config file
 //   - TOKEN_MONITOR_DB: Path to database file
+//   - TOKEN_MONITOR_CACHE_DIR: Cache directory
 //   - TOKEN_MONITOR_LOG_LEVEL: Log level
+//   - TOKEN_MONITOR_LOG_FORMAT: Log format
+//   - TOKEN_MONITOR_SERVE_ADDR: Serve HTTP listen address
+//   - TOKEN_MONITOR_LANG: Display locale
 func (l *loader) applyEnvVars(cfg *Config) *Config {
 	result := *cfg
 
@@ -196,7 +392,7 @@ func (l *loader) applyEnvVars(cfg *Config) *Config {
 		for i := range dirs {
 			dirs[i] = strings.TrimSpace(dirs[i])
 		}
-		result.ClaudeConfigDirs = dirs
+		result.ClaudeConfigDirs = ClaudeDirs(dirs...)
 	}
 
 	// TOKEN_MONITOR_DB: database path
@@ -204,11 +400,36 @@ func (l *loader) applyEnvVars(cfg *Config) *Config {
 		result.Storage.DBPath = dbPath
 	}
 
+	// TOKEN_MONITOR_CACHE_DIR: cache directory
+	if cacheDir := os.Getenv("TOKEN_MONITOR_CACHE_DIR"); cacheDir != "" {
+		result.Storage.CacheDir = cacheDir
+	}
+
 	// TOKEN_MONITOR_LOG_LEVEL: log level
 	if logLevel := os.Getenv("TOKEN_MONITOR_LOG_LEVEL"); logLevel != "" {
 		result.Logging.Level = strings.ToLower(logLevel)
 	}
 
+	// TOKEN_MONITOR_LOG_FORMAT: log format
+	if logFormat := os.Getenv("TOKEN_MONITOR_LOG_FORMAT"); logFormat != "" {
+		result.Logging.Format = strings.ToLower(logFormat)
+	}
+
+	// TOKEN_MONITOR_SERVE_ADDR: serve HTTP listen address
+	if addr := os.Getenv("TOKEN_MONITOR_SERVE_ADDR"); addr != "" {
+		result.Serve.Addr = addr
+	}
+
+	// TOKEN_MONITOR_LANG: display locale
+	if lang := os.Getenv("TOKEN_MONITOR_LANG"); lang != "" {
+		result.Display.Locale = lang
+	}
+
+	// TOKEN_MONITOR_ACCESSIBLE: screen-reader friendly output
+	if accessible, err := strconv.ParseBool(os.Getenv("TOKEN_MONITOR_ACCESSIBLE")); err == nil && accessible {
+		result.Display.Accessible = true
+	}
+
 	return &result
 }
 
@@ -232,6 +453,22 @@ func LoadFromFile(path string) (*Config, error) {
 	return NewLoader(path).Load()
 }
 
+// Parse decodes the contents of a config file, applies them over the
+// defaults, and validates the result. Environment variables are not
+// applied, so it checks the file itself.
+func Parse(data []byte) (*Config, error) {
+	var fileCfg Config
+	if err := yaml.Unmarshal(data, &fileCfg); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidYAML, err)
+	}
+
+	cfg := (&loader{}).mergeConfigs(Default(), &fileCfg)
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	return cfg, nil
+}
+
 // Save writes the configuration to a YAML file.
 //
 // Creates parent directories if they don't exist.
@@ -0,0 +1,252 @@
+package config
+
+import (
+	"errors"
+	"strconv"
+)
+
+// Problem is a validation error at a configuration key.
+type Problem struct {
+	// Key is the dotted YAML path of the offending setting, e.g.
+	// "logging.level".
+	Key string `json:"key"`
+
+	// Message is the validation error.
+	Message string `json:"message"`
+
+	// Suggestion is a valid value for the key, usually its default. It is
+	// empty for lists, which are valid when the bad entry is fixed or
+	// removed.
+	Suggestion string `json:"suggestion,omitempty"`
+
+	// Err is the error returned by Validate.
+	Err error `json:"-"`
+}
+
+// keyFix locates the settings reported by a validation error and resets
+// them to their defaults.
+type keyFix struct {
+	err error
+
+	// fix resets the setting in c to its value in d and returns the key
+	// and suggested value.
+	fix func(c, d *Config) (key, suggestion string)
+}
+
+// keyFixes covers every error returned by Validate.
+var keyFixes = []keyFix{
+	{ErrNoClaudeDirs, func(c, d *Config) (string, string) {
+		c.ClaudeConfigDirs = d.ClaudeConfigDirs
+		if len(d.ClaudeConfigDirs) == 0 {
+			return "claude_config_dirs", ""
+		}
+		return "claude_config_dirs", d.ClaudeConfigDirs[0].Path
+	}},
+	{ErrInvalidClaudeDir, func(c, d *Config) (string, string) {
+		dirs := make([]ClaudeDir, 0, len(c.ClaudeConfigDirs))
+		for _, dir := range c.ClaudeConfigDirs {
+			if dir.Path != "" {
+				dirs = append(dirs, dir)
+			}
+		}
+		if len(dirs) == 0 {
+			dirs = d.ClaudeConfigDirs
+		}
+		c.ClaudeConfigDirs = dirs
+		return "claude_config_dirs", ""
+	}},
+	{ErrInvalidSessionPattern, func(c, d *Config) (string, string) {
+		c.Discovery.SessionPatterns = d.Discovery.SessionPatterns
+		return "discovery.session_patterns", ""
+	}},
+	{ErrInvalidDiscoveryFile, func(c, d *Config) (string, string) {
+		c.Discovery.Files = d.Discovery.Files
+		return "discovery.files", ""
+	}},
+	{ErrInvalidMissingSessionID, func(c, d *Config) (string, string) {
+		c.Discovery.MissingSessionID = d.Discovery.MissingSessionID
+		return "discovery.missing_session_id", "drop"
+	}},
+	{ErrInvalidSessionAttribution, func(c, d *Config) (string, string) {
+		c.Discovery.SessionAttribution = d.Discovery.SessionAttribution
+		return "discovery.session_attribution", "embedded"
+	}},
+	{ErrInvalidWatchInterval, func(c, d *Config) (string, string) {
+		c.Monitoring.WatchInterval = d.Monitoring.WatchInterval
+		return "monitoring.watch_interval", d.Monitoring.WatchInterval.String()
+	}},
+	{ErrInvalidUpdateFrequency, func(c, d *Config) (string, string) {
+		c.Monitoring.UpdateFrequency = d.Monitoring.UpdateFrequency
+		return "monitoring.update_frequency", d.Monitoring.UpdateFrequency.String()
+	}},
+	{ErrInvalidSessionRetention, func(c, d *Config) (string, string) {
+		c.Monitoring.SessionRetention = d.Monitoring.SessionRetention
+		return "monitoring.session_retention", d.Monitoring.SessionRetention.String()
+	}},
+	{ErrInvalidMaxPollInterval, func(c, d *Config) (string, string) {
+		c.Monitoring.MaxPollInterval = d.Monitoring.MaxPollInterval
+		return "monitoring.max_poll_interval", d.Monitoring.MaxPollInterval.String()
+	}},
+	{ErrInvalidBlockTokenLimit, func(c, d *Config) (string, string) {
+		c.Monitoring.BlockTokenLimit = d.Monitoring.BlockTokenLimit
+		return "monitoring.block_token_limit", "0"
+	}},
+	{ErrInvalidContextWindow, func(c, d *Config) (string, string) {
+		c.Monitoring.ContextWindow = d.Monitoring.ContextWindow
+		return "monitoring.context_window", "0"
+	}},
+	{ErrInvalidWorkerPoolSize, func(c, d *Config) (string, string) {
+		c.Performance.WorkerPoolSize = d.Performance.WorkerPoolSize
+		return "performance.worker_pool_size", strconv.Itoa(d.Performance.WorkerPoolSize)
+	}},
+	{ErrInvalidReadLimits, func(c, d *Config) (string, string) {
+		if c.Performance.MaxFileSizeMB <= 0 {
+			c.Performance.MaxFileSizeMB = d.Performance.MaxFileSizeMB
+			return "performance.max_file_size_mb", strconv.Itoa(d.Performance.MaxFileSizeMB)
+		}
+		c.Performance.MaxEntriesPerRead = d.Performance.MaxEntriesPerRead
+		return "performance.max_entries_per_read", strconv.Itoa(d.Performance.MaxEntriesPerRead)
+	}},
+	{ErrInvalidCacheSize, func(c, d *Config) (string, string) {
+		c.Performance.CacheSize = d.Performance.CacheSize
+		return "performance.cache_size", strconv.Itoa(d.Performance.CacheSize)
+	}},
+	{ErrInvalidBatchWindow, func(c, d *Config) (string, string) {
+		c.Performance.BatchWindow = d.Performance.BatchWindow
+		return "performance.batch_window", d.Performance.BatchWindow.String()
+	}},
+	{ErrInvalidReadRetry, func(c, d *Config) (string, string) {
+		if c.Performance.ReadRetryDelay < 0 {
+			c.Performance.ReadRetryDelay = d.Performance.ReadRetryDelay
+			return "performance.read_retry_delay", d.Performance.ReadRetryDelay.String()
+		}
+		c.Performance.ReadRetries = d.Performance.ReadRetries
+		return "performance.read_retries", strconv.Itoa(d.Performance.ReadRetries)
+	}},
+	{ErrInvalidDisplayMode, func(c, d *Config) (string, string) {
+		c.Display.DefaultMode = d.Display.DefaultMode
+		return "display.default_mode", d.Display.DefaultMode
+	}},
+	{ErrInvalidRefreshRate, func(c, d *Config) (string, string) {
+		c.Display.RefreshRate = d.Display.RefreshRate
+		return "display.refresh_rate", d.Display.RefreshRate.String()
+	}},
+	{ErrInvalidLocale, func(c, d *Config) (string, string) {
+		c.Display.Locale = d.Display.Locale
+		return "display.locale", d.Display.Locale
+	}},
+	{ErrInvalidUnits, func(c, d *Config) (string, string) {
+		c.Display.Units = d.Display.Units
+		return "display.units", d.Display.Units
+	}},
+	{ErrInvalidNameValidation, func(c, d *Config) (string, string) {
+		c.Session.NameValidation = d.Session.NameValidation
+		return "session.name_validation", d.Session.NameValidation
+	}},
+	{ErrInvalidNameNormalization, func(c, d *Config) (string, string) {
+		c.Session.NameNormalization = d.Session.NameNormalization
+		return "session.name_normalization", d.Session.NameNormalization
+	}},
+	{ErrInvalidSessionStates, func(c, d *Config) (string, string) {
+		if c.Session.IdleAfter <= 0 {
+			c.Session.IdleAfter = d.Session.IdleAfter
+			return "session.idle_after", d.Session.IdleAfter.String()
+		}
+		c.Session.ArchiveAfter = d.Session.ArchiveAfter
+		return "session.archive_after", d.Session.ArchiveAfter.String()
+	}},
+	{ErrInvalidLogLevel, func(c, d *Config) (string, string) {
+		c.Logging.Level = d.Logging.Level
+		return "logging.level", d.Logging.Level
+	}},
+	{ErrInvalidLogFormat, func(c, d *Config) (string, string) {
+		c.Logging.Format = d.Logging.Format
+		return "logging.format", d.Logging.Format
+	}},
+	{ErrInvalidBasePath, func(c, d *Config) (string, string) {
+		c.Serve.BasePath = d.Serve.BasePath
+		return "serve.base_path", "/token-monitor"
+	}},
+	{ErrInvalidCORSOrigin, func(c, d *Config) (string, string) {
+		c.Serve.CORSOrigins = d.Serve.CORSOrigins
+		return "serve.cors_origins", ""
+	}},
+	{ErrInvalidServeToken, func(c, d *Config) (string, string) {
+		c.Serve.Tokens = d.Serve.Tokens
+		return "serve.tokens", ""
+	}},
+	{ErrInvalidMQTT, func(c, d *Config) (string, string) {
+		c.MQTT = d.MQTT
+		return "mqtt", ""
+	}},
+	{ErrInvalidNotifyChannel, func(c, d *Config) (string, string) {
+		c.Notify.Channels = d.Notify.Channels
+		return "notify.channels", ""
+	}},
+	{ErrInvalidFootprint, func(c, d *Config) (string, string) {
+		c.Footprint.GramsPerMTok = d.Footprint.GramsPerMTok
+		return "footprint.grams_per_mtok", ""
+	}},
+	{ErrInvalidRetention, func(c, d *Config) (string, string) {
+		switch {
+		case c.Retention.RollupDays < 0:
+			c.Retention.RollupDays = d.Retention.RollupDays
+			return "retention.rollup_days", "0"
+		case c.Retention.BaselineDays < 0:
+			c.Retention.BaselineDays = d.Retention.BaselineDays
+			return "retention.baseline_days", "0"
+		default:
+			c.Retention.FocusDays = d.Retention.FocusDays
+			return "retention.focus_days", "0"
+		}
+	}},
+	{ErrInvalidPricing, func(c, d *Config) (string, string) {
+		c.Pricing.Rules = d.Pricing.Rules
+		return "pricing.rules", ""
+	}},
+	{ErrInvalidTeam, func(c, d *Config) (string, string) {
+		c.Team = d.Team
+		return "team", ""
+	}},
+	{ErrInvalidTelemetry, func(c, d *Config) (string, string) {
+		c.Telemetry.Endpoint = d.Telemetry.Endpoint
+		return "telemetry.endpoint", ""
+	}},
+}
+
+// Problems returns every validation error in the configuration, in the
+// order Validate checks them, or nil if it is valid.
+//
+// Validate stops at the first error, so Problems validates a copy, resets
+// the reported setting to its default, and validates again until the copy
+// is valid. Settings in a list are reset as a whole, so only the first bad
+// entry of each list is reported.
+//
+// Thread-safety: This method is read-only and thread-safe.
+func (c *Config) Problems() []Problem {
+	defaults := Default()
+	check := *c
+
+	var problems []Problem
+	// Each fix applies at most once, as defaults are valid.
+	for range len(keyFixes) + 1 {
+		err := check.Validate()
+		if err == nil {
+			break
+		}
+		problem := Problem{Message: err.Error(), Err: err}
+		fixed := false
+		for _, kf := range keyFixes {
+			if errors.Is(err, kf.err) {
+				problem.Key, problem.Suggestion = kf.fix(&check, defaults)
+				fixed = true
+				break
+			}
+		}
+		problems = append(problems, problem)
+		if !fixed {
+			break
+		}
+	}
+	return problems
+}
@@ -0,0 +1,119 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Rules is the shareable part of a configuration: per-command flag
+// defaults (such as defaults.watch) and aliases, which act as named
+// watch and report profiles. Machine-specific settings like directories
+// and database paths are left out, so a team can exchange rules as a
+// YAML snippet.
+type Rules struct {
+	// Per-command flag defaults, as in Config.Defaults
+	Defaults CommandDefaults `yaml:"defaults,omitempty"`
+
+	// Command aliases, as in Config.Aliases
+	Aliases map[string]string `yaml:"aliases,omitempty"`
+}
+
+// RuleChange describes one rule changed by ImportRules.
+type RuleChange struct {
+	// Key is "aliases.<name>" or "defaults.<command>.<flag>".
+	Key string
+
+	// Old is the previous value; empty when the rule was added.
+	Old string
+
+	// New is the imported value; empty when the rule was removed.
+	New string
+}
+
+// ExportRules returns the rules of the configuration.
+func (c *Config) ExportRules() Rules {
+	return Rules{Defaults: c.Defaults, Aliases: c.Aliases}
+}
+
+// ParseRules decodes a rules snippet. Sections other than defaults and
+// aliases are rejected, so a snippet meant for another version or a full
+// config file is not silently half-imported.
+func ParseRules(data []byte) (Rules, error) {
+	var rules Rules
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&rules); err != nil && !errors.Is(err, io.EOF) {
+		return Rules{}, fmt.Errorf("%w: %v", ErrInvalidRules, err)
+	}
+	for name, value := range rules.Aliases {
+		if name == "" || value == "" {
+			return Rules{}, fmt.Errorf("%w: alias %q is empty", ErrInvalidRules, name)
+		}
+	}
+	return rules, nil
+}
+
+// ImportRules merges rules into the configuration and returns the changes
+// in key order. Imported values win over existing ones; with replace, rules
+// missing from the import are removed as well.
+func (c *Config) ImportRules(rules Rules, replace bool) []RuleChange {
+	old := flattenRules(c.ExportRules())
+	imported := flattenRules(rules)
+
+	if replace {
+		c.Defaults = nil
+		c.Aliases = nil
+	}
+	for command, flags := range rules.Defaults {
+		for name, value := range flags {
+			if c.Defaults == nil {
+				c.Defaults = make(CommandDefaults)
+			}
+			if c.Defaults[command] == nil {
+				c.Defaults[command] = make(map[string]interface{})
+			}
+			c.Defaults[command][name] = value
+		}
+	}
+	for name, value := range rules.Aliases {
+		if c.Aliases == nil {
+			c.Aliases = make(map[string]string)
+		}
+		c.Aliases[name] = value
+	}
+
+	var changes []RuleChange
+	for key, value := range imported {
+		if old[key] != value {
+			changes = append(changes, RuleChange{Key: key, Old: old[key], New: value})
+		}
+	}
+	if replace {
+		for key, value := range old {
+			if _, ok := imported[key]; !ok {
+				changes = append(changes, RuleChange{Key: key, Old: value})
+			}
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Key < changes[j].Key })
+	return changes
+}
+
+// flattenRules maps each rule to its key and flag string value.
+func flattenRules(rules Rules) map[string]string {
+	flat := make(map[string]string)
+	for command, flags := range rules.Defaults {
+		for name, value := range flags {
+			flat["defaults."+command+"."+name] = formatDefaultValue(value)
+		}
+	}
+	for name, value := range rules.Aliases {
+		flat["aliases."+name] = value
+	}
+	return flat
+}
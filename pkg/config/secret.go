@@ -0,0 +1,120 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Secret settings (notify.channels webhook_url and serve.tokens token)
+// may hold a reference instead of the secret itself, so the secret never
+// sits in the YAML file:
+//
+//	${ENV:SLACK_WEBHOOK}           the environment variable SLACK_WEBHOOK
+//	keychain:token-monitor/slack   the OS keychain entry with service
+//	                               token-monitor and account slack
+//
+// Other values are used as they are.
+const (
+	envRefPrefix      = "${ENV:"
+	keychainRefPrefix = "keychain:"
+)
+
+// envRef matches a complete environment variable reference.
+var envRef = regexp.MustCompile(`^\$\{ENV:([A-Za-z_][A-Za-z0-9_]*)\}$`)
+
+// RedactedSecret replaces plaintext secrets in config show.
+const RedactedSecret = "REDACTED"
+
+// lookupKeychain reads a keychain entry; tests replace it.
+var lookupKeychain = keychainLookup
+
+// IsSecretRef reports whether value references a secret rather than
+// holding it.
+func IsSecretRef(value string) bool {
+	return strings.HasPrefix(value, envRefPrefix) || strings.HasPrefix(value, keychainRefPrefix)
+}
+
+// checkSecretRef returns an error if value looks like a secret reference
+// but is malformed.
+func checkSecretRef(value string) error {
+	switch {
+	case strings.HasPrefix(value, envRefPrefix):
+		if !envRef.MatchString(value) {
+			return fmt.Errorf("malformed reference %q (want ${ENV:NAME})", value)
+		}
+	case strings.HasPrefix(value, keychainRefPrefix):
+		service, account, ok := strings.Cut(strings.TrimPrefix(value, keychainRefPrefix), "/")
+		if !ok || service == "" || account == "" {
+			return fmt.Errorf("malformed reference %q (want keychain:service/account)", value)
+		}
+	}
+	return nil
+}
+
+// ResolveSecret returns the secret value refers to, reading environment
+// variables and keychain entries, or value itself if it is not a
+// reference. A reference to a missing or empty secret is an error, so a
+// misconfigured channel fails instead of posting to an empty URL.
+func ResolveSecret(value string) (string, error) {
+	if err := checkSecretRef(value); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrSecretNotFound, err)
+	}
+
+	switch {
+	case strings.HasPrefix(value, envRefPrefix):
+		name := envRef.FindStringSubmatch(value)[1]
+		secret := os.Getenv(name)
+		if secret == "" {
+			return "", fmt.Errorf("%w: environment variable %s is not set", ErrSecretNotFound, name)
+		}
+		return secret, nil
+	case strings.HasPrefix(value, keychainRefPrefix):
+		service, account, _ := strings.Cut(strings.TrimPrefix(value, keychainRefPrefix), "/")
+		secret, err := lookupKeychain(service, account)
+		if err != nil {
+			return "", fmt.Errorf("%w: keychain entry %s/%s: %v", ErrSecretNotFound, service, account, err)
+		}
+		if secret == "" {
+			return "", fmt.Errorf("%w: keychain entry %s/%s is empty", ErrSecretNotFound, service, account)
+		}
+		return secret, nil
+	default:
+		return value, nil
+	}
+}
+
+// redactSecret returns value for config show: references are kept, as
+// they name where the secret is rather than the secret, and plaintext
+// secrets are replaced.
+func redactSecret(value string) string {
+	if value == "" || IsSecretRef(value) {
+		return value
+	}
+	return RedactedSecret
+}
+
+// Redacted returns a copy of the configuration with plaintext secrets
+// replaced by RedactedSecret, for display. Secret references are kept.
+//
+// Thread-safety: This method is read-only and thread-safe.
+func (c *Config) Redacted() *Config {
+	r := *c
+
+	if len(c.Notify.Channels) > 0 {
+		r.Notify.Channels = make([]NotifyChannel, len(c.Notify.Channels))
+		for i, ch := range c.Notify.Channels {
+			ch.WebhookURL = redactSecret(ch.WebhookURL)
+			r.Notify.Channels[i] = ch
+		}
+	}
+	if len(c.Serve.Tokens) > 0 {
+		r.Serve.Tokens = make([]ServeToken, len(c.Serve.Tokens))
+		for i, token := range c.Serve.Tokens {
+			token.Token = redactSecret(token.Token)
+			r.Serve.Tokens[i] = token
+		}
+	}
+	return &r
+}
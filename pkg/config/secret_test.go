@@ -0,0 +1,86 @@
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestResolveSecret(t *testing.T) {
+	t.Setenv("TEST_SLACK_WEBHOOK", "https://hooks.test/s3cret")
+	lookupKeychain = func(service, account string) (string, error) {
+		if service == "token-monitor" && account == "slack" {
+			return "from-keychain", nil
+		}
+		return "", errors.New("item not found")
+	}
+	t.Cleanup(func() { lookupKeychain = keychainLookup })
+
+	tests := []struct {
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{"https://plain.test/hook", "https://plain.test/hook", false},
+		{"${ENV:TEST_SLACK_WEBHOOK}", "https://hooks.test/s3cret", false},
+		{"${ENV:TEST_UNSET_WEBHOOK}", "", true},
+		{"${ENV:TEST_SLACK_WEBHOOK", "", true},
+		{"keychain:token-monitor/slack", "from-keychain", false},
+		{"keychain:token-monitor/discord", "", true},
+		{"keychain:token-monitor", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ResolveSecret(tt.value)
+		if tt.wantErr {
+			if !errors.Is(err, ErrSecretNotFound) {
+				t.Errorf("ResolveSecret(%q) error = %v, want ErrSecretNotFound", tt.value, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ResolveSecret(%q) = %q, %v; want %q", tt.value, got, err, tt.want)
+		}
+	}
+}
+
+func TestRedacted(t *testing.T) {
+	cfg := Default()
+	cfg.Notify.Channels = []NotifyChannel{
+		{Name: "plain", Type: "discord", WebhookURL: "https://discord.test/s3cret", Events: []string{"alert"}},
+		{Name: "ref", Type: "discord", WebhookURL: "${ENV:DISCORD_WEBHOOK}", Events: []string{"alert"}},
+	}
+	cfg.Serve.Tokens = []ServeToken{
+		{Name: "dash", Token: "s3cret", Scopes: []string{"read"}},
+		{Name: "ci", Token: "keychain:token-monitor/ci", Scopes: []string{"read"}},
+	}
+
+	r := cfg.Redacted()
+	if got := r.Notify.Channels[0].WebhookURL; got != RedactedSecret {
+		t.Errorf("plaintext webhook = %q, want %s", got, RedactedSecret)
+	}
+	if got := r.Notify.Channels[1].WebhookURL; got != "${ENV:DISCORD_WEBHOOK}" {
+		t.Errorf("webhook reference = %q, want it kept", got)
+	}
+	if r.Serve.Tokens[0].Token != RedactedSecret || r.Serve.Tokens[1].Token != "keychain:token-monitor/ci" {
+		t.Errorf("tokens = %+v, want the plaintext one redacted", r.Serve.Tokens)
+	}
+	if cfg.Serve.Tokens[0].Token != "s3cret" || cfg.Notify.Channels[0].WebhookURL != "https://discord.test/s3cret" {
+		t.Error("Redacted() modified the original configuration")
+	}
+}
+
+func TestValidateSecretRefs(t *testing.T) {
+	cfg := Default()
+	cfg.Notify.Channels = []NotifyChannel{
+		{Name: "ops", Type: "discord", WebhookURL: "${ENV:DISCORD WEBHOOK}", Events: []string{"alert"}},
+	}
+	err := cfg.Validate()
+	if !errors.Is(err, ErrInvalidNotifyChannel) || !strings.Contains(err.Error(), "${ENV:NAME}") {
+		t.Errorf("Validate() = %v, want a malformed reference error", err)
+	}
+
+	cfg.Notify.Channels[0].WebhookURL = "${ENV:DISCORD_WEBHOOK}"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() with a reference = %v, want nil (resolved when used)", err)
+	}
+}
@@ -12,11 +12,24 @@
 //	if err != nil {
 //	    log.Fatal(err)
 //	}
-//	fmt.Printf("Claude dirs: %v\n", cfg.ClaudeConfigDirs)
+//	fmt.Printf("Claude dirs: %v\n", cfg.ClaudeDirPaths())
 package config
 
 import (
+	"errors"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strings"
 	"time"
+
+	"github.com/0xmhha/token-monitor/pkg/analysis"
+	"github.com/0xmhha/token-monitor/pkg/i18n"
+	"github.com/0xmhha/token-monitor/pkg/mqtt"
+	"github.com/0xmhha/token-monitor/pkg/plan"
+	"github.com/0xmhha/token-monitor/pkg/retention"
+	"github.com/0xmhha/token-monitor/pkg/session"
 )
 
 // Config represents the complete application configuration.
@@ -26,12 +39,21 @@ import (
 // - WatchInterval must be > 0
 // - UpdateFrequency must be > 0
 // - SessionRetention must be > 0
+// - MaxPollInterval must be > 0
+// - BlockTokenLimit must be >= 0
+// - ContextWindow must be >= 0
 // - WorkerPoolSize must be > 0
+// - MaxFileSizeMB must be > 0 and MaxEntriesPerRead >= 0
 // - CacheSize must be > 0
-// - BatchWindow must be > 0.
+// - BatchWindow must be > 0
+// - ReadRetryDelay and ReadRetries must be >= 0
+// - Session IdleAfter must be > 0 and ArchiveAfter > IdleAfter.
 type Config struct {
-	// Claude data directories to monitor
-	ClaudeConfigDirs []string `yaml:"claude_config_dirs"`
+	// Claude data directories to monitor, optionally labeled
+	ClaudeConfigDirs []ClaudeDir `yaml:"claude_config_dirs"`
+
+	// Session file discovery settings
+	Discovery DiscoveryConfig `yaml:"discovery,omitempty"`
 
 	// Monitoring settings
 	Monitoring MonitoringConfig `yaml:"monitoring"`
@@ -45,11 +67,110 @@ type Config struct {
 	// Storage settings
 	Storage StorageConfig `yaml:"storage"`
 
+	// How long each class of stored data is kept
+	Retention RetentionConfig `yaml:"retention,omitempty"`
+
+	// Session naming settings
+	Session SessionConfig `yaml:"session"`
+
 	// Logging settings
 	Logging LoggingConfig `yaml:"logging"`
 
 	// Integration settings for Claude Code extension ecosystem
 	Integration IntegrationConfig `yaml:"integration"`
+
+	// Serve command settings
+	Serve ServeConfig `yaml:"serve,omitempty"`
+
+	// MQTT status publishing from watch
+	MQTT MQTTConfig `yaml:"mqtt,omitempty"`
+
+	// Notification channels (Discord)
+	Notify NotifyConfig `yaml:"notify,omitempty"`
+
+	// Estimated carbon footprint in stats and reports
+	Footprint FootprintConfig `yaml:"footprint,omitempty"`
+
+	// Cost overrides for specific models or date ranges
+	Pricing PricingConfig `yaml:"pricing,omitempty"`
+
+	// Team plans, limits, and currency for the team report
+	Team TeamConfig `yaml:"team,omitempty"`
+
+	// Opt-in anonymous telemetry about the tool itself
+	Telemetry TelemetryConfig `yaml:"telemetry,omitempty"`
+
+	// Per-command flag defaults (e.g. defaults.stats.group_by)
+	Defaults CommandDefaults `yaml:"defaults,omitempty"`
+
+	// User-defined command aliases (e.g. work: "stats -group-by date")
+	Aliases map[string]string `yaml:"aliases,omitempty"`
+
+	// Keys of the loaded file that are not settings
+	unknownKeys []UnknownKey
+}
+
+// CommandDefaults maps a command name to default values for its flags.
+//
+// Flag names may use underscores in place of dashes (group_by for -group-by).
+// List values are joined with commas, matching the CLI's list flag syntax.
+type CommandDefaults map[string]map[string]interface{}
+
+// For returns the flag defaults configured for command, keyed by flag name.
+//
+// Returns nil if no defaults are configured for the command.
+func (d CommandDefaults) For(command string) map[string]string {
+	values := d[command]
+	if len(values) == 0 {
+		return nil
+	}
+
+	result := make(map[string]string, len(values))
+	for key, value := range values {
+		name := strings.ReplaceAll(key, "_", "-")
+		result[name] = formatDefaultValue(value)
+	}
+	return result
+}
+
+// formatDefaultValue converts a YAML value into its flag string form.
+func formatDefaultValue(value interface{}) string {
+	switch v := value.(type) {
+	case []interface{}:
+		parts := make([]string, len(v))
+		for i, item := range v {
+			parts[i] = formatDefaultValue(item)
+		}
+		return strings.Join(parts, ",")
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// DiscoveryConfig contains session file discovery settings.
+type DiscoveryConfig struct {
+	// Extra session ID regexes for non-UUID file names (matched against
+	// the whole name without .jsonl)
+	SessionPatterns []string `yaml:"session_patterns,omitempty"`
+
+	// What to do with entries without a sessionId: drop (default) or
+	// filename (take it from <session-id>.jsonl)
+	MissingSessionID string `yaml:"missing_session_id,omitempty"`
+
+	// Session entries are attributed to: embedded (default, their own
+	// sessionId) or file (the containing file's session, so sub-agent
+	// usage counts toward the parent session)
+	SessionAttribution string `yaml:"session_attribution,omitempty"`
+
+	// Include every .jsonl file in project directories, not only those
+	// named by a session ID; the session ID is read from the file
+	AnyJSONL bool `yaml:"any_jsonl,omitempty"`
+
+	// Extra JSONL files to monitor, outside the Claude directories
+	// (paths or glob patterns; ~ is expanded)
+	Files []string `yaml:"files,omitempty"`
 }
 
 // MonitoringConfig contains monitoring-related settings.
@@ -62,18 +183,53 @@ type MonitoringConfig struct {
 
 	// How long to keep session data
 	SessionRetention time.Duration `yaml:"session_retention"`
+
+	// Longest interval between periodic re-reads while sessions are idle
+	// (values at or below the refresh interval disable the backoff)
+	MaxPollInterval time.Duration `yaml:"max_poll_interval"`
+
+	// Token quota per billing block for the burn-down bar (0: the busiest
+	// earlier block)
+	BlockTokenLimit int `yaml:"block_token_limit,omitempty"`
+
+	// Context window, in tokens, the active session's last request is
+	// measured against (0: 200K)
+	ContextWindow int `yaml:"context_window,omitempty"`
+
+	// Never put the terminal in raw mode for watch's key bindings; signals
+	// (Ctrl+C, SIGTERM) are then the only way to control it
+	NoInput bool `yaml:"no_input,omitempty"`
 }
 
 // PerformanceConfig contains performance tuning settings.
 type PerformanceConfig struct {
-	// Number of concurrent file processors
+	// Number of session files read concurrently by the watch pipeline
 	WorkerPoolSize int `yaml:"worker_pool_size"`
 
 	// Maximum sessions to keep in memory cache
 	CacheSize int `yaml:"cache_size"`
 
-	// Database write batching window
+	// Window for batching file change events before reading them
 	BatchWindow time.Duration `yaml:"batch_window"`
+
+	// Delay before re-reading a file whose write event produced no new
+	// entries, for usage lines flushed late (0 disables re-reads)
+	ReadRetryDelay time.Duration `yaml:"read_retry_delay,omitempty"`
+
+	// Re-reads of such a file before giving up
+	ReadRetries int `yaml:"read_retries,omitempty"`
+
+	// Low-power mode for watch and tui: slower refresh, no percentiles,
+	// and less frequent re-discovery (same as watch -eco)
+	LowPower bool `yaml:"low_power"`
+
+	// Largest session file read, in MB; larger files are skipped with a
+	// warning unless -force is given
+	MaxFileSizeMB int `yaml:"max_file_size_mb"`
+
+	// Most entries parsed from one file per read (0 for no limit); watch
+	// continues where a limited read stopped
+	MaxEntriesPerRead int `yaml:"max_entries_per_read,omitempty"`
 }
 
 // DisplayConfig contains display-related settings.
@@ -86,6 +242,21 @@ type DisplayConfig struct {
 
 	// Display refresh rate
 	RefreshRate time.Duration `yaml:"refresh_rate"`
+
+	// Language of user-facing output (auto, en, ko); auto follows
+	// LC_ALL, LC_MESSAGES, and LANG
+	Locale string `yaml:"locale"`
+
+	// Screen-reader friendly output: labeled lines instead of tables and
+	// box drawing, no emoji, and status spelled out in words
+	Accessible bool `yaml:"accessible"`
+
+	// Plain ASCII output: box drawing becomes +-| and emoji are dropped
+	ASCII bool `yaml:"ascii"`
+
+	// Primary measure in stats and report tables (tokens, k, cost); the
+	// other measures are shown with -detailed
+	Units string `yaml:"units"`
 }
 
 // StorageConfig contains storage-related settings.
@@ -97,6 +268,29 @@ type StorageConfig struct {
 	CacheDir string `yaml:"cache_dir"`
 }
 
+// SessionConfig contains session naming settings.
+type SessionConfig struct {
+	// Name validation rules (strict, relaxed)
+	NameValidation string `yaml:"name_validation"`
+
+	// Name rewriting on write (trim, lower, none); lookups ignore case
+	NameNormalization string `yaml:"name_normalization"`
+
+	// Quiet period after which a session is idle
+	IdleAfter time.Duration `yaml:"idle_after"`
+
+	// Quiet period after which a session is archived
+	ArchiveAfter time.Duration `yaml:"archive_after"`
+}
+
+// Thresholds returns the lifecycle state thresholds.
+func (c SessionConfig) Thresholds() session.Thresholds {
+	return session.Thresholds{
+		IdleAfter:    c.IdleAfter,
+		ArchiveAfter: c.ArchiveAfter,
+	}
+}
+
 // LoggingConfig contains logging settings.
 type LoggingConfig struct {
 	// Log level (debug, info, warn, error)
@@ -142,6 +336,405 @@ type MCPConfig struct {
 	Enabled bool `yaml:"enabled"`
 }
 
+// ServeConfig contains serve command settings.
+type ServeConfig struct {
+	// Addr is the HTTP listen address (e.g. :8080). Empty serves MCP over
+	// stdin/stdout.
+	Addr string `yaml:"addr,omitempty"`
+
+	// ShutdownTimeout is how long in-flight HTTP requests may take to
+	// finish after SIGTERM.
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout,omitempty"`
+
+	// Tokens are the API tokens accepted over HTTP. When any are set,
+	// /mcp requires "Authorization: Bearer <token>"; the probes stay open.
+	Tokens []ServeToken `yaml:"tokens,omitempty"`
+
+	// BasePath is a path prefix for all endpoints (e.g. /token-monitor)
+	// when a reverse proxy shares the host with other services.
+	BasePath string `yaml:"base_path,omitempty"`
+
+	// CORSOrigins are the browser origins allowed to call the API
+	// (e.g. https://dash.example.com, or "*" for any).
+	CORSOrigins []string `yaml:"cors_origins,omitempty"`
+
+	// TrustProxy takes the client address from X-Forwarded-For and the
+	// scheme and host from X-Forwarded-Proto and X-Forwarded-Host. Enable
+	// it only behind a proxy that sets these headers.
+	TrustProxy bool `yaml:"trust_proxy,omitempty"`
+}
+
+// ServeToken is an API token for the serve HTTP endpoint.
+type ServeToken struct {
+	// Name identifies the token in logs, e.g. "dashboard".
+	Name string `yaml:"name"`
+
+	// Token is the secret, or a reference to it such as ${ENV:NAME} or
+	// keychain:service/account that keeps it out of the file.
+	Token string `yaml:"token,omitempty"`
+
+	// TokenEnv names an environment variable holding the secret.
+	TokenEnv string `yaml:"token_env,omitempty"`
+
+	// Scopes granted to the token: read (query tools), ingest (usage
+	// pushed by hooks), and admin (state-changing operations; implies
+	// the others).
+	Scopes []string `yaml:"scopes"`
+}
+
+// MQTTConfig contains settings for publishing watch status to an MQTT
+// broker, e.g. for Home Assistant.
+type MQTTConfig struct {
+	// Broker is the broker URL (tcp://host:1883, or mqtts://host:8883
+	// for TLS). Empty disables publishing.
+	Broker string `yaml:"broker,omitempty"`
+
+	// Topic receives the status JSON.
+	Topic string `yaml:"topic,omitempty"`
+
+	// ClientID identifies watch to the broker; empty lets it assign one.
+	ClientID string `yaml:"client_id,omitempty"`
+
+	// Username is sent with the password read from PasswordEnv.
+	Username string `yaml:"username,omitempty"`
+
+	// PasswordEnv names an environment variable holding the password.
+	PasswordEnv string `yaml:"password_env,omitempty"`
+
+	// Retain keeps the last status on the broker for new subscribers.
+	Retain bool `yaml:"retain,omitempty"`
+}
+
+// NotifyConfig contains notification settings.
+type NotifyConfig struct {
+	// Channels are the destinations for notifications.
+	Channels []NotifyChannel `yaml:"channels,omitempty"`
+}
+
+// NotifyChannel is one notification destination.
+type NotifyChannel struct {
+	// Name identifies the channel, e.g. for notify -channel.
+	Name string `yaml:"name"`
+
+	// Type is the service: discord.
+	Type string `yaml:"type"`
+
+	// WebhookURL is the channel webhook, or a reference to it such as
+	// ${ENV:DISCORD_WEBHOOK} or keychain:token-monitor/discord that keeps
+	// the embedded token out of the file.
+	WebhookURL string `yaml:"webhook_url,omitempty"`
+
+	// WebhookURLEnv names an environment variable holding the webhook URL.
+	WebhookURLEnv string `yaml:"webhook_url_env,omitempty"`
+
+	// Username overrides the webhook's display name.
+	Username string `yaml:"username,omitempty"`
+
+	// Events the channel receives: alert, daily.
+	Events []string `yaml:"events"`
+}
+
+// Wants reports whether the channel receives event.
+func (c NotifyChannel) Wants(event string) bool {
+	for _, e := range c.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// FootprintConfig contains settings for estimating the carbon footprint
+// of usage.
+type FootprintConfig struct {
+	// GramsPerMTok maps model classes (opus, sonnet, haiku, or default
+	// for the others) to grams of CO2e per million tokens. Empty disables
+	// footprint estimates.
+	GramsPerMTok map[string]float64 `yaml:"grams_per_mtok,omitempty"`
+}
+
+// Rates returns the configured rates for footprint estimates.
+func (c FootprintConfig) Rates() analysis.FootprintRates {
+	return analysis.FootprintRates(c.GramsPerMTok)
+}
+
+// RetentionConfig sets how many days each class of data in the database
+// is kept. Zero keeps the class forever. Raw entries stay in Claude's
+// session files and are not affected.
+type RetentionConfig struct {
+	// Days of per-day rollups kept, today included
+	RollupDays int `yaml:"rollup_days,omitempty"`
+
+	// Days a saved baseline is kept
+	BaselineDays int `yaml:"baseline_days,omitempty"`
+
+	// Days a closed focus window is kept after it ended
+	FocusDays int `yaml:"focus_days,omitempty"`
+}
+
+// Policy returns the retention policy to apply.
+func (c RetentionConfig) Policy() retention.Policy {
+	return retention.Policy{
+		RollupDays:   c.RollupDays,
+		BaselineDays: c.BaselineDays,
+		FocusDays:    c.FocusDays,
+	}
+}
+
+// PricingConfig contains overrides of the built-in model pricing.
+type PricingConfig struct {
+	// Rules are applied in order: the first matching rule with prices
+	// replaces the built-in ones, and the multipliers of all matching
+	// rules apply on top.
+	Rules []PriceRuleConfig `yaml:"rules,omitempty"`
+}
+
+// PriceRuleConfig is one pricing override.
+type PriceRuleConfig struct {
+	// Model matches model names containing it, ignoring case. Empty
+	// matches every model.
+	Model string `yaml:"model,omitempty"`
+
+	// From is the first day (YYYY-MM-DD, UTC) the rule applies to.
+	From string `yaml:"from,omitempty"`
+
+	// Until is the first day (YYYY-MM-DD, UTC) the rule no longer
+	// applies to.
+	Until string `yaml:"until,omitempty"`
+
+	// Per-million-token prices in USD. Unset prices keep the built-in ones.
+	InputPerMTok      *float64 `yaml:"input_per_mtok,omitempty"`
+	OutputPerMTok     *float64 `yaml:"output_per_mtok,omitempty"`
+	CacheWritePerMTok *float64 `yaml:"cache_write_per_mtok,omitempty"`
+	CacheReadPerMTok  *float64 `yaml:"cache_read_per_mtok,omitempty"`
+
+	// Multiplier scales the cost, e.g. 0.8 for a 20% discount. Unset is 1.
+	Multiplier *float64 `yaml:"multiplier,omitempty"`
+}
+
+// PriceRules returns the configured rules for cost estimates. Rules with
+// malformed dates are skipped; Validate reports them.
+func (c PricingConfig) PriceRules() analysis.PriceRules {
+	rules := make(analysis.PriceRules, 0, len(c.Rules))
+	for _, rc := range c.Rules {
+		rule, err := rc.rule()
+		if err != nil {
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// rule converts the configuration into an analysis.PriceRule.
+func (rc PriceRuleConfig) rule() (analysis.PriceRule, error) {
+	rule := analysis.PriceRule{
+		Model:             rc.Model,
+		InputPerMTok:      rc.InputPerMTok,
+		OutputPerMTok:     rc.OutputPerMTok,
+		CacheWritePerMTok: rc.CacheWritePerMTok,
+		CacheReadPerMTok:  rc.CacheReadPerMTok,
+		Multiplier:        1,
+	}
+	if rc.Multiplier != nil {
+		rule.Multiplier = *rc.Multiplier
+	}
+	for _, date := range []struct {
+		value string
+		dst   *time.Time
+	}{{rc.From, &rule.From}, {rc.Until, &rule.Until}} {
+		if date.value == "" {
+			continue
+		}
+		t, err := time.Parse(time.DateOnly, date.value)
+		if err != nil {
+			return rule, fmt.Errorf("date %q must be YYYY-MM-DD", date.value)
+		}
+		*date.dst = t
+	}
+	return rule, nil
+}
+
+// validate checks that the rule parses, changes something, and has no
+// negative prices or empty date range.
+func (rc PriceRuleConfig) validate() error {
+	rule, err := rc.rule()
+	if err != nil {
+		return err
+	}
+	if rc.Multiplier == nil && rc.InputPerMTok == nil && rc.OutputPerMTok == nil &&
+		rc.CacheWritePerMTok == nil && rc.CacheReadPerMTok == nil {
+		return errors.New("rule sets no prices or multiplier")
+	}
+	for _, price := range []*float64{rc.InputPerMTok, rc.OutputPerMTok, rc.CacheWritePerMTok, rc.CacheReadPerMTok, rc.Multiplier} {
+		if price != nil && *price < 0 {
+			return errors.New("prices and multiplier must not be negative")
+		}
+	}
+	if !rule.From.IsZero() && !rule.Until.IsZero() && !rule.From.Before(rule.Until) {
+		return errors.New("from must be before until")
+	}
+	return nil
+}
+
+// TeamConfig contains the plans of a team for the team report.
+type TeamConfig struct {
+	// Currency is the currency code the report is in. Default: USD.
+	Currency string `yaml:"currency,omitempty"`
+
+	// ExchangeRates maps currency codes to the value of one unit in
+	// Currency. USD is required for another Currency, since estimated
+	// API costs are in USD.
+	ExchangeRates map[string]float64 `yaml:"exchange_rates,omitempty"`
+
+	// Plans are the team's plans.
+	Plans []PlanConfig `yaml:"plans,omitempty"`
+
+	// Share pushes this member's aggregate usage to a team server.
+	Share TeamShareConfig `yaml:"share,omitempty"`
+}
+
+// TeamShareConfig contains the opt-in sharing of aggregate usage with a
+// team server (see package teamshare).
+type TeamShareConfig struct {
+	// Endpoint is the base URL of the team server, a token-monitor
+	// running serve -http. Empty disables sharing.
+	Endpoint string `yaml:"endpoint,omitempty"`
+
+	// Member names this member on the team server. It must be the name
+	// of Token in the server's serve.tokens.
+	Member string `yaml:"member,omitempty"`
+
+	// Token is the bearer token with the ingest scope, or a reference to
+	// it such as ${ENV:TEAM_MONITOR_TOKEN} or keychain:service/account
+	// that keeps it out of the file.
+	Token string `yaml:"token,omitempty"`
+
+	// Interval is the time between two pushes by watch. Default: 1h.
+	Interval time.Duration `yaml:"interval,omitempty"`
+}
+
+// PlanConfig is one plan of a team.
+type PlanConfig struct {
+	// Name identifies the plan in the report.
+	Name string `yaml:"name"`
+
+	// Price is the monthly price per member.
+	Price float64 `yaml:"price"`
+
+	// Currency is the currency code of Price. Default: USD.
+	Currency string `yaml:"currency,omitempty"`
+
+	// TokenLimit is the monthly token quota per member (0 for none).
+	TokenLimit int `yaml:"token_limit,omitempty"`
+
+	// CostLimit is the monthly quota per member as estimated API cost in
+	// USD (0 for none).
+	CostLimit float64 `yaml:"cost_limit,omitempty"`
+
+	// Members are claude_config_dirs labels, one per user or profile.
+	Members []string `yaml:"members"`
+}
+
+// Team returns the team for plan reports.
+func (c TeamConfig) Team() plan.Team {
+	team := plan.Team{Currency: c.Currency, Rates: plan.Rates(c.ExchangeRates)}
+	for _, p := range c.Plans {
+		team.Plans = append(team.Plans, plan.Plan(p))
+	}
+	return team
+}
+
+// currencyPattern matches ISO 4217 style currency codes.
+var currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// validate checks plan names, members, limits, and that every amount can
+// be converted to the team's currency.
+func (c TeamConfig) validate() error {
+	currency := c.Currency
+	if currency == "" {
+		currency = plan.USD
+	}
+	convertible := func(code string) error {
+		if code == "" || code == currency {
+			return nil
+		}
+		if !currencyPattern.MatchString(code) {
+			return fmt.Errorf("currency %q must be a three-letter code such as USD", code)
+		}
+		if _, ok := c.ExchangeRates[code]; !ok {
+			return fmt.Errorf("no exchange rate for %s to %s", code, currency)
+		}
+		return nil
+	}
+
+	if !currencyPattern.MatchString(currency) {
+		return fmt.Errorf("currency %q must be a three-letter code such as USD", currency)
+	}
+	for code, rate := range c.ExchangeRates {
+		if !currencyPattern.MatchString(code) || rate <= 0 {
+			return fmt.Errorf("exchange rate %s: %v must be a positive rate for a three-letter code", code, rate)
+		}
+	}
+	if len(c.Plans) > 0 {
+		if err := convertible(plan.USD); err != nil {
+			return err
+		}
+	}
+
+	names := make(map[string]bool)
+	members := make(map[string]string)
+	for i, p := range c.Plans {
+		if p.Name == "" {
+			return fmt.Errorf("plan %d has no name", i+1)
+		}
+		if names[p.Name] {
+			return fmt.Errorf("duplicate plan %s", p.Name)
+		}
+		names[p.Name] = true
+		if p.Price < 0 || p.TokenLimit < 0 || p.CostLimit < 0 {
+			return fmt.Errorf("plan %s: price and limits must not be negative", p.Name)
+		}
+		if err := convertible(p.Currency); err != nil {
+			return fmt.Errorf("plan %s: %v", p.Name, err)
+		}
+		for _, m := range p.Members {
+			if other, ok := members[m]; ok {
+				return fmt.Errorf("member %s is on plans %s and %s", m, other, p.Name)
+			}
+			members[m] = p.Name
+		}
+	}
+
+	if share := c.Share; share.Endpoint != "" {
+		u, err := url.Parse(share.Endpoint)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("share endpoint %q must be an http:// or https:// URL", share.Endpoint)
+		}
+		if strings.TrimSpace(share.Member) == "" {
+			return fmt.Errorf("share endpoint %s needs a member name", share.Endpoint)
+		}
+		if err := checkSecretRef(share.Token); err != nil {
+			return fmt.Errorf("share token: %w", err)
+		}
+	}
+	if c.Share.Interval < 0 {
+		return fmt.Errorf("share interval %s must not be negative", c.Share.Interval)
+	}
+	return nil
+}
+
+// TelemetryConfig contains settings for the opt-in performance report
+// about token-monitor itself (see package telemetry).
+type TelemetryConfig struct {
+	// Enabled records command timings. Off by default.
+	Enabled bool `yaml:"enabled,omitempty"`
+
+	// Endpoint receives the report as an HTTP POST at most once a day.
+	// Empty keeps timings local, viewable with telemetry status.
+	Endpoint string `yaml:"endpoint,omitempty"`
+}
+
 // StatusConfig contains status line display settings.
 type StatusConfig struct {
 	// Format is the default status output format: "compact", "default", "full".
@@ -154,18 +747,65 @@ type StatusConfig struct {
 // Validate checks if the configuration satisfies all invariants.
 //
 // Returns an error if any invariant is violated:
-//   - No Claude config directories specified
+//   - No Claude config directories specified, or one without a path
+//   - Session pattern is not a valid regular expression
+//   - Missing session ID policy other than drop or filename
+//   - Session attribution other than embedded or file
 //   - Invalid time durations (must be > 0)
 //   - Invalid worker pool size (must be > 0)
+//   - Invalid read limits (max file size must be > 0, max entries >= 0)
 //   - Invalid cache size (must be > 0)
 //   - Invalid display mode
+//   - Invalid name validation or normalization mode
 //   - Invalid log level
+//   - Serve base path without a leading slash or with a trailing one
+//   - Serve CORS origin that is not scheme://host[:port] or *
+//   - Serve token without a name, secret, or valid scopes
+//   - MQTT broker that is not a tcp:// or mqtts:// URL, or a topic with
+//     wildcards
+//   - Notify channel without a name, webhook, or known type and events
+//   - Footprint rate for an unknown model class, or a negative rate
+//   - Negative retention days
+//   - Pricing rule with a malformed date or empty date range, a negative
+//     price or multiplier, or nothing to override
+//   - Team currency or exchange rate that is malformed, a plan without a
+//     name, with negative price or limits, or an unconvertible currency,
+//     and a member on several plans, or a share endpoint that is not an
+//     http:// or https:// URL, has no member name, or a malformed token
+//     reference
+//   - Telemetry endpoint that is not an http:// or https:// URL
 //
 // Thread-safety: This method is read-only and thread-safe.
 func (c *Config) Validate() error {
 	if len(c.ClaudeConfigDirs) == 0 {
 		return ErrNoClaudeDirs
 	}
+	for i, dir := range c.ClaudeConfigDirs {
+		if dir.Path == "" {
+			return fmt.Errorf("%w: entry %d has no path", ErrInvalidClaudeDir, i+1)
+		}
+	}
+
+	for _, pattern := range c.Discovery.SessionPatterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrInvalidSessionPattern, pattern, err)
+		}
+	}
+	for _, file := range c.Discovery.Files {
+		if _, err := filepath.Match(file, ""); err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrInvalidDiscoveryFile, file, err)
+		}
+	}
+	switch c.Discovery.MissingSessionID {
+	case "", "drop", "filename":
+	default:
+		return ErrInvalidMissingSessionID
+	}
+	switch c.Discovery.SessionAttribution {
+	case "", "embedded", "file":
+	default:
+		return ErrInvalidSessionAttribution
+	}
 
 	// Validate monitoring config
 	if c.Monitoring.WatchInterval <= 0 {
@@ -177,17 +817,32 @@ func (c *Config) Validate() error {
 	if c.Monitoring.SessionRetention <= 0 {
 		return ErrInvalidSessionRetention
 	}
+	if c.Monitoring.MaxPollInterval <= 0 {
+		return ErrInvalidMaxPollInterval
+	}
+	if c.Monitoring.BlockTokenLimit < 0 {
+		return ErrInvalidBlockTokenLimit
+	}
+	if c.Monitoring.ContextWindow < 0 {
+		return ErrInvalidContextWindow
+	}
 
 	// Validate performance config
 	if c.Performance.WorkerPoolSize <= 0 {
 		return ErrInvalidWorkerPoolSize
 	}
+	if c.Performance.MaxFileSizeMB <= 0 || c.Performance.MaxEntriesPerRead < 0 {
+		return ErrInvalidReadLimits
+	}
 	if c.Performance.CacheSize <= 0 {
 		return ErrInvalidCacheSize
 	}
 	if c.Performance.BatchWindow <= 0 {
 		return ErrInvalidBatchWindow
 	}
+	if c.Performance.ReadRetryDelay < 0 || c.Performance.ReadRetries < 0 {
+		return ErrInvalidReadRetry
+	}
 
 	// Validate display config
 	validModes := map[string]bool{
@@ -203,6 +858,35 @@ func (c *Config) Validate() error {
 	if c.Display.RefreshRate <= 0 {
 		return ErrInvalidRefreshRate
 	}
+	if c.Display.Locale != "" && c.Display.Locale != "auto" {
+		if _, ok := i18n.Parse(c.Display.Locale); !ok {
+			return ErrInvalidLocale
+		}
+	}
+	validUnits := map[string]bool{
+		"tokens": true,
+		"k":      true,
+		"cost":   true,
+	}
+	if c.Display.Units != "" && !validUnits[c.Display.Units] {
+		return ErrInvalidUnits
+	}
+
+	// Validate session config
+	if c.Session.NameValidation != "strict" && c.Session.NameValidation != "relaxed" {
+		return ErrInvalidNameValidation
+	}
+	validNormalizations := map[string]bool{
+		"trim":  true,
+		"lower": true,
+		"none":  true,
+	}
+	if !validNormalizations[c.Session.NameNormalization] {
+		return ErrInvalidNameNormalization
+	}
+	if c.Session.IdleAfter <= 0 || c.Session.ArchiveAfter <= c.Session.IdleAfter {
+		return ErrInvalidSessionStates
+	}
 
 	// Validate logging config
 	validLevels := map[string]bool{
@@ -223,6 +907,115 @@ func (c *Config) Validate() error {
 		return ErrInvalidLogFormat
 	}
 
+	// Validate serve settings
+	if base := c.Serve.BasePath; base != "" && (!strings.HasPrefix(base, "/") || strings.HasSuffix(base, "/")) {
+		return fmt.Errorf("%w: %q (want e.g. /token-monitor)", ErrInvalidBasePath, base)
+	}
+	for _, origin := range c.Serve.CORSOrigins {
+		if origin == "*" {
+			continue
+		}
+		u, err := url.Parse(origin)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || (u.Path != "" && u.Path != "/") {
+			return fmt.Errorf("%w: %q (want scheme://host[:port] or *)", ErrInvalidCORSOrigin, origin)
+		}
+	}
+	names := make(map[string]bool, len(c.Serve.Tokens))
+	for i, token := range c.Serve.Tokens {
+		switch {
+		case token.Name == "":
+			return fmt.Errorf("%w: entry %d has no name", ErrInvalidServeToken, i+1)
+		case names[token.Name]:
+			return fmt.Errorf("%w: duplicate name %s", ErrInvalidServeToken, token.Name)
+		case (token.Token == "") == (token.TokenEnv == ""):
+			return fmt.Errorf("%w: %s needs exactly one of token or token_env", ErrInvalidServeToken, token.Name)
+		case len(token.Scopes) == 0:
+			return fmt.Errorf("%w: %s has no scopes", ErrInvalidServeToken, token.Name)
+		}
+		if err := checkSecretRef(token.Token); err != nil {
+			return fmt.Errorf("%w: %s token: %v", ErrInvalidServeToken, token.Name, err)
+		}
+		names[token.Name] = true
+		for _, scope := range token.Scopes {
+			if scope != "read" && scope != "ingest" && scope != "admin" {
+				return fmt.Errorf("%w: %s has unknown scope %q (want read, ingest, or admin)", ErrInvalidServeToken, token.Name, scope)
+			}
+		}
+	}
+
+	// Validate MQTT settings
+	if c.MQTT.Broker != "" {
+		if _, _, err := mqtt.ParseBroker(c.MQTT.Broker); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidMQTT, err)
+		}
+		if c.MQTT.Topic == "" || strings.ContainsAny(c.MQTT.Topic, "+#") {
+			return fmt.Errorf("%w: topic %q must be set and have no wildcards", ErrInvalidMQTT, c.MQTT.Topic)
+		}
+	}
+
+	// Validate notification channels
+	channels := make(map[string]bool, len(c.Notify.Channels))
+	for i, ch := range c.Notify.Channels {
+		switch {
+		case ch.Name == "":
+			return fmt.Errorf("%w: entry %d has no name", ErrInvalidNotifyChannel, i+1)
+		case channels[ch.Name]:
+			return fmt.Errorf("%w: duplicate name %s", ErrInvalidNotifyChannel, ch.Name)
+		case ch.Type != "discord":
+			return fmt.Errorf("%w: %s has unknown type %q (want discord)", ErrInvalidNotifyChannel, ch.Name, ch.Type)
+		case (ch.WebhookURL == "") == (ch.WebhookURLEnv == ""):
+			return fmt.Errorf("%w: %s needs exactly one of webhook_url or webhook_url_env", ErrInvalidNotifyChannel, ch.Name)
+		case len(ch.Events) == 0:
+			return fmt.Errorf("%w: %s has no events", ErrInvalidNotifyChannel, ch.Name)
+		}
+		if err := checkSecretRef(ch.WebhookURL); err != nil {
+			return fmt.Errorf("%w: %s webhook_url: %v", ErrInvalidNotifyChannel, ch.Name, err)
+		}
+		channels[ch.Name] = true
+		for _, event := range ch.Events {
+			if event != "alert" && event != "daily" {
+				return fmt.Errorf("%w: %s has unknown event %q (want alert or daily)", ErrInvalidNotifyChannel, ch.Name, event)
+			}
+		}
+	}
+
+	// Validate footprint rates
+	for class, rate := range c.Footprint.GramsPerMTok {
+		switch class {
+		case analysis.ClassOpus, analysis.ClassSonnet, analysis.ClassHaiku, analysis.ClassDefault:
+		default:
+			return fmt.Errorf("%w: unknown model class %q (want opus, sonnet, haiku, or default)", ErrInvalidFootprint, class)
+		}
+		if rate < 0 {
+			return fmt.Errorf("%w: %s rate must not be negative", ErrInvalidFootprint, class)
+		}
+	}
+
+	// Validate retention
+	if c.Retention.RollupDays < 0 || c.Retention.BaselineDays < 0 || c.Retention.FocusDays < 0 {
+		return ErrInvalidRetention
+	}
+
+	// Validate pricing rules
+	for i, rule := range c.Pricing.Rules {
+		if err := rule.validate(); err != nil {
+			return fmt.Errorf("%w: rule %d: %v", ErrInvalidPricing, i+1, err)
+		}
+	}
+
+	// Validate team plans
+	if err := c.Team.validate(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidTeam, err)
+	}
+
+	// Validate telemetry endpoint
+	if c.Telemetry.Endpoint != "" {
+		u, err := url.Parse(c.Telemetry.Endpoint)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("%w: endpoint %q must be an http:// or https:// URL", ErrInvalidTelemetry, c.Telemetry.Endpoint)
+		}
+	}
+
 	return nil
 }
 
@@ -237,21 +1030,32 @@ func Default() *Config {
 			WatchInterval:    1 * time.Second,
 			UpdateFrequency:  1 * time.Second,
 			SessionRetention: 720 * time.Hour, // 30 days
+			MaxPollInterval:  30 * time.Second,
 		},
 		Performance: PerformanceConfig{
 			WorkerPoolSize: 5,
 			CacheSize:      100,
 			BatchWindow:    100 * time.Millisecond,
+			ReadRetries:    2,
+			MaxFileSizeMB:  100,
 		},
 		Display: DisplayConfig{
 			DefaultMode:  "live",
 			ColorEnabled: true,
 			RefreshRate:  1 * time.Second,
+			Locale:       "auto",
+			Units:        "tokens",
 		},
 		Storage: StorageConfig{
 			DBPath:   defaultDBPath(),
 			CacheDir: defaultCacheDir(),
 		},
+		Session: SessionConfig{
+			NameValidation:    "strict",
+			NameNormalization: "trim",
+			IdleAfter:         30 * time.Minute,
+			ArchiveAfter:      7 * 24 * time.Hour,
+		},
 		Logging: LoggingConfig{
 			Level:  "info",
 			Output: "stderr",
@@ -272,5 +1076,11 @@ func Default() *Config {
 				Emoji:  true,
 			},
 		},
+		Serve: ServeConfig{
+			ShutdownTimeout: 10 * time.Second,
+		},
+		MQTT: MQTTConfig{
+			Topic: "token-monitor/status",
+		},
 	}
 }
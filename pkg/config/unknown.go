@@ -0,0 +1,141 @@
+package config
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// UnknownKey is a key in a config file that is not a setting, usually a
+// typo. Unknown keys are ignored when loading, so they are reported
+// rather than rejected.
+type UnknownKey struct {
+	// Key is the dotted YAML path of the key, e.g. "logging.levle".
+	Key string `json:"key"`
+
+	// Line is the line of the key in the file.
+	Line int `json:"line"`
+
+	// Suggestion is the closest setting at the same level, e.g.
+	// "logging.level"; empty when none is close.
+	Suggestion string `json:"suggestion,omitempty"`
+}
+
+// UnknownKeys returns the keys of the config file that are not settings,
+// in file order, or nil if there are none.
+func (c *Config) UnknownKeys() []UnknownKey {
+	return c.unknownKeys
+}
+
+// FindUnknownKeys returns the keys in the YAML config data that are not
+// settings, in file order. Like yaml's KnownFields, it checks keys against
+// the Config type, but it reports every unknown key instead of failing at
+// the first. Invalid YAML has no unknown keys; decoding reports it.
+func FindUnknownKeys(data []byte) []UnknownKey {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil
+	}
+
+	var keys []UnknownKey
+	walkUnknownKeys(&doc, reflect.TypeOf(Config{}), "", &keys)
+	return keys
+}
+
+// walkUnknownKeys appends the unknown keys under node, a value of type
+// typ at the path prefix, to keys.
+func walkUnknownKeys(node *yaml.Node, typ reflect.Type, prefix string, keys *[]UnknownKey) {
+	for node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if node.Kind == yaml.DocumentNode {
+		for _, child := range node.Content {
+			walkUnknownKeys(child, typ, prefix, keys)
+		}
+		return
+	}
+
+	switch {
+	case typ.Kind() == reflect.Struct && node.Kind == yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			name := node.Content[i].Value
+			if name == "<<" {
+				continue // merge key
+			}
+			field, ok := fieldByTag(typ, name)
+			if !ok {
+				unknown := UnknownKey{Key: joinKey(prefix, name), Line: node.Content[i].Line}
+				if match := closestTag(typ, name); match != "" {
+					unknown.Suggestion = joinKey(prefix, match)
+				}
+				*keys = append(*keys, unknown)
+				continue
+			}
+			walkUnknownKeys(node.Content[i+1], field.Type, joinKey(prefix, name), keys)
+		}
+	case typ.Kind() == reflect.Map && node.Kind == yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			walkUnknownKeys(node.Content[i+1], typ.Elem(), joinKey(prefix, node.Content[i].Value), keys)
+		}
+	case typ.Kind() == reflect.Slice && node.Kind == yaml.SequenceNode:
+		for i, item := range node.Content {
+			walkUnknownKeys(item, typ.Elem(), joinKey(prefix, strconv.Itoa(i)), keys)
+		}
+	}
+}
+
+// joinKey appends name to the dotted path prefix.
+func joinKey(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+// closestTag returns the YAML key of the struct type closest to name, or
+// "" if none is within a few edits. A key that name abbreviates, such as
+// monitoring for monitor, counts as one edit away.
+func closestTag(typ reflect.Type, name string) string {
+	name = strings.ToLower(name)
+	best, bestDist := "", max(2, len(name)/3)+1
+	for i := 0; i < typ.NumField(); i++ {
+		tag, _, _ := strings.Cut(typ.Field(i).Tag.Get("yaml"), ",")
+		if tag == "" || tag == "-" || !typ.Field(i).IsExported() {
+			continue
+		}
+		d := editDistance(name, tag)
+		if len(name) >= 3 && strings.HasPrefix(tag, name) {
+			d = 1
+		}
+		if d < bestDist {
+			best, bestDist = tag, d
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
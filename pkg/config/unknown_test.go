@@ -0,0 +1,61 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFindUnknownKeys(t *testing.T) {
+	data := []byte(`claude_config_dirs:
+  - /data/claude
+  - path: /work/claude
+    lable: work
+logging:
+  levle: debug
+  format: json
+monitor:
+  watch_interval: 1s
+notify:
+  channels:
+    - name: ops
+      type: discord
+      evnets: [alert]
+defaults:
+  watch:
+    any_flag: true
+aliases:
+  work: "stats"
+`)
+
+	want := []UnknownKey{
+		{Key: "claude_config_dirs.1.lable", Line: 4, Suggestion: "claude_config_dirs.1.label"},
+		{Key: "logging.levle", Line: 6, Suggestion: "logging.level"},
+		{Key: "monitor", Line: 8, Suggestion: "monitoring"},
+		{Key: "notify.channels.0.evnets", Line: 14, Suggestion: "notify.channels.0.events"},
+	}
+	if got := FindUnknownKeys(data); !reflect.DeepEqual(got, want) {
+		t.Errorf("FindUnknownKeys() =\n%+v\nwant\n%+v", got, want)
+	}
+
+	if got := FindUnknownKeys([]byte("logging:\n  level: info\n")); got != nil {
+		t.Errorf("FindUnknownKeys(valid) = %+v, want nil", got)
+	}
+}
+
+func TestLoadRecordsUnknownKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("display:\n  colour_enabled: true\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := NewLoader(path).Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	got := cfg.UnknownKeys()
+	if len(got) != 1 || got[0].Key != "display.colour_enabled" || got[0].Suggestion != "display.color_enabled" {
+		t.Errorf("UnknownKeys() = %+v, want display.colour_enabled with a suggestion", got)
+	}
+}
@@ -0,0 +1,180 @@
+package discovery
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// cacheFileName is the discovery cache file inside Config.CacheDir.
+const cacheFileName = "discovery.json"
+
+// cacheVersion is bumped when the cache file layout changes.
+const cacheVersion = 2
+
+// racyWindow is how recent a directory mtime may be before its listing is
+// no longer cached. Filesystem timestamps are coarse, so an entry added in
+// the same tick as the listing would not change the mtime.
+const racyWindow = 2 * time.Second
+
+// cachedDir is the filtered listing of one directory at a given mtime.
+type cachedDir struct {
+	ModTime int64    `json:"mtime"`
+	Names   []string `json:"names"`
+}
+
+// cacheContents is the on-disk cache document.
+type cacheContents struct {
+	Version   int                  `json:"version"`
+	Signature string               `json:"signature"`
+	Dirs      map[string]cachedDir `json:"dirs"`
+}
+
+// dirCache caches directory listings keyed by directory mtime.
+//
+// A directory's mtime changes whenever an entry is added, removed, or
+// renamed, so an unchanged mtime means the cached listing is still valid.
+// File sizes and mtimes are never cached; they are re-read on every scan.
+//
+// A nil *dirCache disables caching. Thread-safety: safe for concurrent use.
+type dirCache struct {
+	path      string
+	signature string
+
+	mu     sync.Mutex
+	loaded bool
+	dirty  bool
+	dirs   map[string]cachedDir
+}
+
+// newDirCache returns a cache stored in dir, or nil if dir is empty.
+// signature identifies the filtering rules; a cache written with a
+// different signature is discarded.
+func newDirCache(dir, signature string) *dirCache {
+	if dir == "" {
+		return nil
+	}
+	return &dirCache{
+		path:      filepath.Join(expandHome(dir), cacheFileName),
+		signature: signature,
+	}
+}
+
+// lookup returns the cached listing of dir if it was taken at modTime.
+func (c *dirCache) lookup(dir string, modTime time.Time) ([]string, bool) {
+	if c == nil {
+		return nil, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.load()
+	entry, ok := c.dirs[dir]
+	if !ok || entry.ModTime != modTime.UnixNano() {
+		return nil, false
+	}
+	return entry.Names, true
+}
+
+// store records the listing of dir taken at modTime.
+func (c *dirCache) store(dir string, modTime time.Time, names []string) {
+	if c == nil || time.Since(modTime) < racyWindow {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.load()
+	c.dirs[dir] = cachedDir{ModTime: modTime.UnixNano(), Names: names}
+	c.dirty = true
+}
+
+// load reads the cache file once. A missing or unreadable file, or one
+// written by another version or with other filtering rules, starts empty.
+// Must be called with c.mu held.
+func (c *dirCache) load() {
+	if c.loaded {
+		return
+	}
+	c.loaded = true
+	c.dirs = make(map[string]cachedDir)
+
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		return
+	}
+
+	var contents cacheContents
+	if err := json.Unmarshal(data, &contents); err != nil {
+		return
+	}
+	if contents.Version != cacheVersion || contents.Signature != c.signature || contents.Dirs == nil {
+		return
+	}
+	c.dirs = contents.Dirs
+}
+
+// save writes the cache file if it changed since it was loaded.
+// The file is replaced atomically so concurrent readers never see a
+// partial write.
+func (c *dirCache) save() error {
+	if c == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.dirty {
+		return nil
+	}
+
+	data, err := json.Marshal(cacheContents{
+		Version:   cacheVersion,
+		Signature: c.signature,
+		Dirs:      c.dirs,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.path), 0700); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), cacheFileName+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()           //nolint:errcheck // already failing
+		_ = os.Remove(tmp.Name()) //nolint:errcheck // best effort cleanup
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name()) //nolint:errcheck // best effort cleanup
+		return err
+	}
+	if err := os.Rename(tmp.Name(), c.path); err != nil {
+		_ = os.Remove(tmp.Name()) //nolint:errcheck // best effort cleanup
+		return err
+	}
+
+	c.dirty = false
+	return nil
+}
+
+// cacheSignature identifies the session filtering rules for patterns and
+// the any-JSONL switch.
+func cacheSignature(patterns []string, anyJSONL bool) string {
+	signature := strings.Join(patterns, "\x00")
+	if anyJSONL {
+		signature = "*.jsonl\x00" + signature
+	}
+	return signature
+}
@@ -17,9 +17,14 @@
 package discovery
 
 import (
+	"bufio"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"regexp"
+	"runtime"
 	"strings"
 	"sync"
 	"time"
@@ -35,7 +40,8 @@ type Logger interface {
 
 // SessionFile represents a discovered session JSONL file.
 type SessionFile struct {
-	// SessionID is the UUID extracted from the filename.
+	// SessionID is the UUID extracted from the filename, or for a file
+	// not named by a session ID, the first sessionId in its content.
 	SessionID string
 
 	// FilePath is the absolute path to the JSONL file.
@@ -50,6 +56,10 @@ type SessionFile struct {
 
 	// ModTime is the last modification time.
 	ModTime int64 // Unix timestamp
+
+	// Source is the label of the base directory the file was found in
+	// (empty if the directory is unlabeled).
+	Source string
 }
 
 // Discoverer provides methods for discovering Claude Code session files.
@@ -60,7 +70,9 @@ type Discoverer interface {
 	//   - Slice of discovered session files
 	//   - Error if directories cannot be accessed
 	//
-	// Skips files that don't match the expected pattern (UUID.jsonl).
+	// Skips files that don't match the expected pattern (UUID.jsonl or a
+	// configured session pattern) unless Config.AnyJSONL is set, and adds
+	// Config.Files.
 	Discover() ([]SessionFile, error)
 
 	// DiscoverProject returns session files for a specific project directory.
@@ -80,15 +92,56 @@ type Discoverer interface {
 	//  2. CLAUDE_PROJECT_DIR env var → most recent .jsonl in that dir; fall through if none found
 	//  3. Default dirs → most recently modified .jsonl
 	FindCurrentSession() (*SessionFile, error)
+
+	// Duplicates returns the files the last Discover skipped as copies of
+	// another discovered file of the same session, e.g. in a symlinked
+	// or backed-up directory. Each session is counted once.
+	Duplicates() []Duplicate
+}
+
+// Config contains discoverer configuration.
+type Config struct {
+	// BaseDirs are the Claude config directories to scan.
+	BaseDirs []string
+
+	// Labels maps base directories, as given in BaseDirs, to the Source
+	// label of the sessions found in them.
+	Labels map[string]string
+
+	// SessionPatterns are extra regular expressions for session IDs that
+	// are not UUIDs. Each must match the whole file name without the
+	// .jsonl extension.
+	SessionPatterns []string
+
+	// AnyJSONL includes every .jsonl file in project directories, not
+	// only those named by a session ID. The session ID of such a file is
+	// read from its content.
+	AnyJSONL bool
+
+	// Files are extra JSONL files to discover outside the base
+	// directories, as paths or glob patterns. Each file is its own
+	// project, named by its directory.
+	Files []string
+
+	// CacheDir is where directory listings are cached between runs,
+	// keyed by directory mtime. Empty disables the cache.
+	CacheDir string
 }
 
 // discoverer implements the Discoverer interface.
 type discoverer struct {
-	baseDirs     []string // Claude config directories to scan
+	baseDirs     []string          // Claude config directories to scan
+	labels       map[string]string // expanded base directory -> label
+	patterns     []*regexp.Regexp
+	anyJSONL     bool
+	files        []string
+	cache        *dirCache
 	logger       Logger
 	cacheMu      sync.Mutex
 	currentCache *SessionFile
 	cacheTime    time.Time
+	dupMu        sync.Mutex
+	duplicates   []Duplicate
 }
 
 // New creates a new Discoverer instance.
@@ -99,42 +152,130 @@ type discoverer struct {
 //
 // Returns a configured Discoverer.
 func New(baseDirs []string, logger Logger) Discoverer {
-	return &discoverer{
-		baseDirs: baseDirs,
+	return NewWithConfig(Config{BaseDirs: baseDirs}, logger)
+}
+
+// NewWithConfig creates a Discoverer from cfg.
+//
+// Invalid session patterns are logged and ignored; config validation
+// rejects them before they reach this point.
+func NewWithConfig(cfg Config, logger Logger) Discoverer {
+	d := &discoverer{
+		baseDirs: cfg.BaseDirs,
+		anyJSONL: cfg.AnyJSONL,
+		files:    cfg.Files,
+		cache:    newDirCache(cfg.CacheDir, cacheSignature(cfg.SessionPatterns, cfg.AnyJSONL)),
 		logger:   logger,
 	}
+
+	if len(cfg.Labels) > 0 {
+		d.labels = make(map[string]string, len(cfg.Labels))
+		for dir, label := range cfg.Labels {
+			d.labels[filepath.Clean(expandHome(dir))] = label
+		}
+	}
+
+	for _, pattern := range cfg.SessionPatterns {
+		re, err := CompileSessionPattern(pattern)
+		if err != nil {
+			logger.Warn("ignoring invalid session pattern", "pattern", pattern, "error", err)
+			continue
+		}
+		d.patterns = append(d.patterns, re)
+	}
+
+	return d
+}
+
+// CompileSessionPattern compiles a session ID pattern anchored to match
+// the whole ID.
+func CompileSessionPattern(pattern string) (*regexp.Regexp, error) {
+	// Compile unanchored first so a pattern like "a)|(b" cannot escape
+	// the anchoring group.
+	if _, err := regexp.Compile(pattern); err != nil {
+		return nil, err
+	}
+	return regexp.Compile("^(?:" + pattern + ")$")
 }
 
 // Discover implements Discoverer.Discover.
+//
+// Project directories are scanned in parallel; results keep the order of
+// baseDirs and directory entries. Symlinked base and project directories
+// are followed; a directory reached by several paths (symlinks, bind
+// mounts) is scanned once, under the first path.
 func (d *discoverer) Discover() ([]SessionFile, error) {
-	var allSessions []SessionFile
+	var projectDirs []string
+	var bases, projects dirSet
 
 	for _, baseDir := range d.baseDirs {
 		// Expand home directory if present
 		expandedDir := expandHome(baseDir)
 
 		// Check if directory exists
-		if _, err := os.Stat(expandedDir); err != nil {
+		info, err := os.Stat(expandedDir)
+		if err != nil {
 			if os.IsNotExist(err) {
 				d.logger.Warn("directory not found, skipping", "path", expandedDir)
 				continue
 			}
+			if isSymlink(expandedDir) {
+				d.logger.Warn("unresolvable symlink, skipping", "path", expandedDir, "error", err)
+				continue
+			}
 			return nil, fmt.Errorf("failed to stat directory %s: %w", expandedDir, err)
 		}
+		if !bases.add(info) {
+			d.logger.Debug("directory already scanned through another path, skipping", "path", expandedDir)
+			continue
+		}
 
-		// Scan directory for projects
-		sessions, err := d.scanBaseDirectory(expandedDir)
+		// List project directories
+		names, err := d.listProjects(expandedDir, info.ModTime())
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan directory %s: %w", expandedDir, err)
 		}
 
-		allSessions = append(allSessions, sessions...)
+		for _, projectDir := range names {
+			projectInfo, err := os.Stat(projectDir)
+			switch {
+			case err != nil:
+				d.logger.Warn("unresolvable project directory, skipping", "path", projectDir, "error", err)
+			case !projectInfo.IsDir():
+				// A symlink to a file.
+			case !projects.add(projectInfo):
+				d.logger.Debug("project directory already scanned through another path, skipping", "path", projectDir)
+			default:
+				projectDirs = append(projectDirs, projectDir)
+			}
+		}
 	}
 
+	sessions := d.scanProjects(projectDirs)
+	sessions = append(sessions, d.scanFiles(sessions)...)
+
+	allSessions, duplicates := removeDuplicates(sessions)
+	d.saveCache()
+	for _, dup := range duplicates {
+		d.logger.Warn("skipping duplicate session file",
+			"path", dup.File.FilePath,
+			"duplicate_of", dup.Of)
+	}
+	d.dupMu.Lock()
+	d.duplicates = duplicates
+	d.dupMu.Unlock()
+
 	d.logger.Info("discovery complete", "total_sessions", len(allSessions))
 	return allSessions, nil
 }
 
+// Duplicates implements Discoverer.Duplicates.
+func (d *discoverer) Duplicates() []Duplicate {
+	d.dupMu.Lock()
+	defer d.dupMu.Unlock()
+	return d.duplicates
+}
+
 // DiscoverProject implements Discoverer.DiscoverProject.
 func (d *discoverer) DiscoverProject(projectPath string) ([]SessionFile, error) {
 	expandedPath := expandHome(projectPath)
@@ -147,44 +288,193 @@ func (d *discoverer) DiscoverProject(projectPath string) ([]SessionFile, error)
 		return nil, fmt.Errorf("failed to stat directory %s: %w", expandedPath, err)
 	}
 
-	return d.scanProjectDirectory(expandedPath)
+	sessions, err := d.scanProjectDirectory(expandedPath)
+	d.saveCache()
+	return sessions, err
 }
 
-// scanBaseDirectory scans a base directory for project subdirectories.
+// listProjects returns the project subdirectories of a base directory,
+// and its symlinks, which may point at directories.
 //
 // Claude Code structure: basedir/project-hash/session-uuid.jsonl.
-func (d *discoverer) scanBaseDirectory(baseDir string) ([]SessionFile, error) {
+func (d *discoverer) listProjects(baseDir string, modTime time.Time) ([]string, error) {
+	names, ok := d.cache.lookup(baseDir, modTime)
+	if !ok {
+		// Read all entries in base directory
+		entries, err := os.ReadDir(baseDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read directory: %w", err)
+		}
+
+		names = make([]string, 0, len(entries))
+		for _, entry := range entries {
+			// Symlinks are resolved by the caller; they may point at
+			// directories.
+			if entry.IsDir() || entry.Type()&os.ModeSymlink != 0 {
+				names = append(names, entry.Name())
+			}
+		}
+		d.cache.store(baseDir, modTime, names)
+	}
+
+	projects := make([]string, len(names))
+	for i, name := range names {
+		projects[i] = filepath.Join(baseDir, name)
+	}
+	return projects, nil
+}
+
+// scanProjects scans project directories with a bounded worker pool.
+// Directories that cannot be scanned are logged and skipped.
+func (d *discoverer) scanProjects(projectDirs []string) []SessionFile {
+	results := make([][]SessionFile, len(projectDirs))
+
+	workers := runtime.GOMAXPROCS(0)
+	if workers > len(projectDirs) {
+		workers = len(projectDirs)
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				sessions, err := d.scanProjectDirectory(projectDirs[i])
+				if err != nil {
+					d.logger.Warn("failed to scan project directory",
+						"path", projectDirs[i],
+						"error", err)
+					continue
+				}
+				results[i] = sessions
+			}
+		}()
+	}
+	for i := range projectDirs {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
 	var sessions []SessionFile
+	for _, r := range results {
+		sessions = append(sessions, r...)
+	}
+	return sessions
+}
 
-	// Read all entries in base directory
-	entries, err := os.ReadDir(baseDir)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read directory: %w", err)
+// scanFiles returns the configured extra files, skipping those already
+// found in a project directory. Patterns that match nothing are logged,
+// as the wrapper writing them may not have run yet.
+func (d *discoverer) scanFiles(found []SessionFile) []SessionFile {
+	if len(d.files) == 0 {
+		return nil
 	}
 
-	for _, entry := range entries {
-		if !entry.IsDir() {
+	seen := make(map[string]bool, len(found))
+	for _, s := range found {
+		seen[s.FilePath] = true
+	}
+
+	var sessions []SessionFile
+	for _, pattern := range d.files {
+		matches, err := filepath.Glob(expandHome(pattern))
+		if err != nil {
+			d.logger.Warn("ignoring invalid discovery file pattern", "pattern", pattern, "error", err)
 			continue
 		}
+		if len(matches) == 0 {
+			d.logger.Debug("discovery file pattern matched nothing", "pattern", pattern)
+		}
+
+		for _, match := range matches {
+			filePath, err := filepath.Abs(match)
+			if err != nil || seen[filePath] {
+				continue
+			}
+			seen[filePath] = true
 
-		projectPath := filepath.Join(baseDir, entry.Name())
-		projectSessions, err := d.scanProjectDirectory(projectPath)
+			// The watcher only reports changes to .jsonl files.
+			if !strings.HasSuffix(filePath, ".jsonl") {
+				d.logger.Warn("skipping discovery file without .jsonl extension", "path", filePath)
+				continue
+			}
+			info, err := os.Stat(filePath)
+			if err != nil {
+				d.logger.Warn("failed to get file info", "path", filePath, "error", err)
+				continue
+			}
+			if info.IsDir() {
+				continue
+			}
+
+			sessions = append(sessions, SessionFile{
+				SessionID:   d.sessionID(filePath),
+				FilePath:    filePath,
+				ProjectPath: filepath.Dir(filePath),
+				Size:        info.Size(),
+				ModTime:     info.ModTime().Unix(),
+			})
+		}
+	}
+	return sessions
+}
+
+// scanProjectDirectory scans a project directory for session JSONL files.
+func (d *discoverer) scanProjectDirectory(projectDir string) ([]SessionFile, error) {
+	dirInfo, err := os.Stat(projectDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat directory: %w", err)
+	}
+
+	names, err := d.listSessionFiles(projectDir, dirInfo.ModTime())
+	if err != nil {
+		return nil, err
+	}
+
+	source := d.labels[filepath.Dir(filepath.Clean(projectDir))]
+
+	sessions := make([]SessionFile, 0, len(names))
+	for _, name := range names {
+		// Sizes and mtimes change on every append, so they are never cached.
+		// Symlinked session files report their target's size and mtime.
+		filePath := filepath.Join(projectDir, name)
+		info, err := os.Stat(filePath)
 		if err != nil {
-			d.logger.Warn("failed to scan project directory",
-				"path", projectPath,
+			d.logger.Warn("failed to get file info",
+				"path", filePath,
 				"error", err)
 			continue
 		}
+		if info.IsDir() {
+			continue
+		}
 
-		sessions = append(sessions, projectSessions...)
+		sessions = append(sessions, SessionFile{
+			SessionID:   d.sessionID(filePath),
+			FilePath:    filePath,
+			ProjectPath: projectDir,
+			Size:        info.Size(),
+			ModTime:     info.ModTime().Unix(),
+			Source:      source,
+		})
 	}
 
+	d.logger.Debug("scanned project directory",
+		"path", projectDir,
+		"sessions_found", len(sessions))
+
 	return sessions, nil
 }
 
-// scanProjectDirectory scans a project directory for session JSONL files.
-func (d *discoverer) scanProjectDirectory(projectDir string) ([]SessionFile, error) {
-	sessions := make([]SessionFile, 0, 10) // Pre-allocate with reasonable capacity
+// listSessionFiles returns the session file names in a project directory,
+// from the cache when the directory is unchanged.
+func (d *discoverer) listSessionFiles(projectDir string, modTime time.Time) ([]string, error) {
+	if names, ok := d.cache.lookup(projectDir, modTime); ok {
+		return names, nil
+	}
 
 	// Read all files in project directory
 	entries, err := os.ReadDir(projectDir)
@@ -192,6 +482,7 @@ func (d *discoverer) scanProjectDirectory(projectDir string) ([]SessionFile, err
 		return nil, fmt.Errorf("failed to read directory: %w", err)
 	}
 
+	names := make([]string, 0, len(entries))
 	for _, entry := range entries {
 		if entry.IsDir() {
 			continue
@@ -202,41 +493,40 @@ func (d *discoverer) scanProjectDirectory(projectDir string) ([]SessionFile, err
 			continue
 		}
 
-		// Extract session ID from filename (remove .jsonl extension)
-		sessionID := strings.TrimSuffix(entry.Name(), ".jsonl")
-
-		// Validate session ID format (basic UUID check)
-		if !isValidSessionID(sessionID) {
+		// Validate session ID format
+		if !d.anyJSONL && !d.isSessionID(strings.TrimSuffix(entry.Name(), ".jsonl")) {
 			d.logger.Debug("skipping non-session file",
 				"file", entry.Name(),
 				"reason", "invalid session ID format")
 			continue
 		}
 
-		// Get file info
-		filePath := filepath.Join(projectDir, entry.Name())
-		info, err := entry.Info()
-		if err != nil {
-			d.logger.Warn("failed to get file info",
-				"path", filePath,
-				"error", err)
-			continue
-		}
-
-		sessions = append(sessions, SessionFile{
-			SessionID:   sessionID,
-			FilePath:    filePath,
-			ProjectPath: projectDir,
-			Size:        info.Size(),
-			ModTime:     info.ModTime().Unix(),
-		})
+		names = append(names, entry.Name())
 	}
 
-	d.logger.Debug("scanned project directory",
-		"path", projectDir,
-		"sessions_found", len(sessions))
+	d.cache.store(projectDir, modTime, names)
+	return names, nil
+}
 
-	return sessions, nil
+// saveCache persists the directory cache; failures only cost speed.
+func (d *discoverer) saveCache() {
+	if err := d.cache.save(); err != nil {
+		d.logger.Debug("failed to save discovery cache", "error", err)
+	}
+}
+
+// ProjectDirName returns the name of the directory Claude Code keeps the
+// sessions of the project at path in, e.g. "-Users-foo-work-app" for
+// /Users/foo/work/app. Every character other than an ASCII letter or digit
+// becomes a dash.
+func ProjectDirName(path string) string {
+	b := []byte(path)
+	for i, c := range b {
+		if !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9') {
+			b[i] = '-'
+		}
+	}
+	return string(b)
 }
 
 // expandHome expands ~ in file paths to the user's home directory.
@@ -257,12 +547,69 @@ func expandHome(path string) string {
 	return filepath.Join(homeDir, path[2:])
 }
 
+// isSessionID reports whether id is a UUID or matches a configured pattern.
+func (d *discoverer) isSessionID(id string) bool {
+	if isValidSessionID(id) {
+		return true
+	}
+	for _, re := range d.patterns {
+		if re.MatchString(id) {
+			return true
+		}
+	}
+	return false
+}
+
+// sessionIDScanBytes bounds how much of a file sessionID reads looking
+// for an embedded session ID.
+const sessionIDScanBytes = 64 * 1024
+
+// sessionID returns the session ID of the file at path: its name without
+// .jsonl when that is a session ID, otherwise the first sessionId in its
+// content, and failing that the name anyway.
+func (d *discoverer) sessionID(path string) string {
+	name := strings.TrimSuffix(filepath.Base(path), ".jsonl")
+	if d.isSessionID(name) {
+		return name
+	}
+	if id := readSessionID(path); id != "" {
+		return id
+	}
+	return name
+}
+
+// readSessionID returns the first sessionId in the first
+// sessionIDScanBytes of the JSONL file at path, or "" if there is none.
+func readSessionID(path string) string {
+	f, err := os.Open(path) //nolint:gosec // path from discovery
+	if err != nil {
+		return ""
+	}
+	defer func() {
+		_ = f.Close() //nolint:errcheck // read-only file
+	}()
+
+	scanner := bufio.NewScanner(io.LimitReader(f, sessionIDScanBytes))
+	scanner.Buffer(make([]byte, 0, 4096), sessionIDScanBytes)
+	for scanner.Scan() {
+		var entry struct {
+			SessionID string `json:"sessionId"`
+		}
+		if json.Unmarshal(scanner.Bytes(), &entry) == nil && entry.SessionID != "" {
+			return entry.SessionID
+		}
+	}
+	return ""
+}
+
 // isValidSessionID performs basic validation on session ID format.
 //
-// Expected format: UUID v4 (8-4-4-4-12 hex digits with dashes)
+// Expected format: canonical UUID text (8-4-4-4-12 hex digits with dashes).
+// Version and variant bits are not checked, so every UUID version
+// (including time-ordered v7) is accepted.
 // Example: a1b2c3d4-e5f6-7890-abcd-ef1234567890.
 func isValidSessionID(id string) bool {
-	// Basic length check (UUID v4 is 36 characters)
+	// Basic length check (canonical UUIDs are 36 characters)
 	if len(id) != 36 {
 		return false
 	}
@@ -3,11 +3,14 @@ package discovery
 import (
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
+	"time"
 )
 
 // mockLogger implements Logger interface for testing.
 type mockLogger struct {
+	mu         sync.Mutex
 	debugCalls []string
 	infoCalls  []string
 	warnCalls  []string
@@ -15,18 +18,26 @@ type mockLogger struct {
 }
 
 func (m *mockLogger) Debug(msg string, keysAndValues ...interface{}) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.debugCalls = append(m.debugCalls, msg)
 }
 
 func (m *mockLogger) Info(msg string, keysAndValues ...interface{}) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.infoCalls = append(m.infoCalls, msg)
 }
 
 func (m *mockLogger) Warn(msg string, keysAndValues ...interface{}) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.warnCalls = append(m.warnCalls, msg)
 }
 
 func (m *mockLogger) Error(msg string, keysAndValues ...interface{}) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.errorCalls = append(m.errorCalls, msg)
 }
 
@@ -209,6 +220,250 @@ func TestDiscoverInvalidSessionIDs(t *testing.T) {
 	}
 }
 
+func TestDiscoverSessionPatterns(t *testing.T) {
+	tmpDir := t.TempDir()
+	project := filepath.Join(tmpDir, "project")
+	if err := os.MkdirAll(project, 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	createFile(t, filepath.Join(project, "a1b2c3d4-e5f6-7890-abcd-ef1234567890.jsonl"), "content")
+	createFile(t, filepath.Join(project, "sess_01HV8Z3K.jsonl"), "content")
+	createFile(t, filepath.Join(project, "prefix-sess_01HV8Z3K.jsonl"), "content")
+
+	logger := &mockLogger{}
+	d := NewWithConfig(Config{
+		BaseDirs:        []string{tmpDir},
+		SessionPatterns: []string{`sess_[0-9A-Z]+`, `(`},
+	}, logger)
+
+	if len(logger.warnCalls) != 1 {
+		t.Errorf("expected a warning for the invalid pattern, got %v", logger.warnCalls)
+	}
+
+	sessions, err := d.Discover()
+	if err != nil {
+		t.Fatalf("Discover() error = %v", err)
+	}
+
+	// Patterns must match the whole ID, so the prefixed file is skipped.
+	if len(sessions) != 2 {
+		t.Fatalf("Discover() found %d sessions, want 2", len(sessions))
+	}
+	found := false
+	for _, s := range sessions {
+		if s.SessionID == "sess_01HV8Z3K" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Discover() = %+v, want the pattern-matched session", sessions)
+	}
+}
+
+func TestDiscoverAnyJSONL(t *testing.T) {
+	tmpDir := t.TempDir()
+	project := filepath.Join(tmpDir, "project")
+	if err := os.MkdirAll(project, 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	createFile(t, filepath.Join(project, "a1b2c3d4-e5f6-7890-abcd-ef1234567890.jsonl"), "content")
+	createFile(t, filepath.Join(project, "usage-2026-10-15.jsonl"),
+		"{\"type\":\"summary\"}\n{\"sessionId\":\"wrapped-1\",\"type\":\"assistant\"}\n")
+	createFile(t, filepath.Join(project, "notes.jsonl"), "not json\n")
+
+	// Without the switch, only the UUID-named file is a session.
+	sessions, err := NewWithConfig(Config{BaseDirs: []string{tmpDir}}, &mockLogger{}).Discover()
+	if err != nil {
+		t.Fatalf("Discover() error = %v", err)
+	}
+	if len(sessions) != 1 {
+		t.Fatalf("Discover() found %d sessions, want 1", len(sessions))
+	}
+
+	sessions, err = NewWithConfig(Config{BaseDirs: []string{tmpDir}, AnyJSONL: true}, &mockLogger{}).Discover()
+	if err != nil {
+		t.Fatalf("Discover() error = %v", err)
+	}
+	ids := make(map[string]bool)
+	for _, s := range sessions {
+		ids[s.SessionID] = true
+	}
+	// The session ID comes from the content, or the name if there is none.
+	for _, want := range []string{"a1b2c3d4-e5f6-7890-abcd-ef1234567890", "wrapped-1", "notes"} {
+		if !ids[want] {
+			t.Errorf("Discover() = %+v, want session %s", sessions, want)
+		}
+	}
+}
+
+func TestDiscoverFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	project := filepath.Join(tmpDir, "project")
+	wrapper := t.TempDir()
+	if err := os.MkdirAll(project, 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	inProject := filepath.Join(project, "a1b2c3d4-e5f6-7890-abcd-ef1234567890.jsonl")
+	createFile(t, inProject, "content")
+	createFile(t, filepath.Join(wrapper, "usage-1.jsonl"), "{\"sessionId\":\"wrapped-1\"}\n")
+	createFile(t, filepath.Join(wrapper, "usage-2.jsonl"), "{\"sessionId\":\"wrapped-2\"}\n")
+	createFile(t, filepath.Join(wrapper, "usage.log"), "{\"sessionId\":\"wrapped-3\"}\n")
+
+	d := NewWithConfig(Config{
+		BaseDirs: []string{tmpDir},
+		// The project file is already discovered and must not be added twice.
+		Files: []string{filepath.Join(wrapper, "usage-*"), inProject, filepath.Join(wrapper, "missing.jsonl")},
+	}, &mockLogger{})
+
+	sessions, err := d.Discover()
+	if err != nil {
+		t.Fatalf("Discover() error = %v", err)
+	}
+	if len(sessions) != 3 {
+		t.Fatalf("Discover() found %d sessions, want 3: %+v", len(sessions), sessions)
+	}
+	for _, s := range sessions[1:] {
+		if s.ProjectPath != wrapper {
+			t.Errorf("ProjectPath = %s, want %s", s.ProjectPath, wrapper)
+		}
+	}
+	if sessions[1].SessionID != "wrapped-1" || sessions[2].SessionID != "wrapped-2" {
+		t.Errorf("Discover() = %+v, want wrapped-1 and wrapped-2", sessions[1:])
+	}
+	if len(d.Duplicates()) != 0 {
+		t.Errorf("Duplicates() = %+v, want none", d.Duplicates())
+	}
+}
+
+func TestDiscoverLabels(t *testing.T) {
+	personal := t.TempDir()
+	work := t.TempDir()
+	for _, base := range []string{personal, work} {
+		project := filepath.Join(base, "project")
+		if err := os.MkdirAll(project, 0700); err != nil {
+			t.Fatal(err)
+		}
+	}
+	createFile(t, filepath.Join(personal, "project", "a1b2c3d4-e5f6-7890-abcd-ef1234567890.jsonl"), "content")
+	createFile(t, filepath.Join(work, "project", "b1b2c3d4-e5f6-7890-abcd-ef1234567890.jsonl"), "content")
+
+	d := NewWithConfig(Config{
+		BaseDirs: []string{personal, work + string(filepath.Separator)},
+		Labels:   map[string]string{work + string(filepath.Separator): "work"},
+	}, &mockLogger{})
+
+	sessions, err := d.Discover()
+	if err != nil {
+		t.Fatalf("Discover() error = %v", err)
+	}
+	if len(sessions) != 2 {
+		t.Fatalf("Discover() found %d sessions, want 2", len(sessions))
+	}
+	for _, s := range sessions {
+		want := ""
+		if filepath.Dir(s.ProjectPath) == work {
+			want = "work"
+		}
+		if s.Source != want {
+			t.Errorf("session %s Source = %q, want %q", s.SessionID, s.Source, want)
+		}
+	}
+
+	projectSessions, err := d.DiscoverProject(filepath.Join(work, "project"))
+	if err != nil {
+		t.Fatalf("DiscoverProject() error = %v", err)
+	}
+	if len(projectSessions) != 1 || projectSessions[0].Source != "work" {
+		t.Errorf("DiscoverProject() = %+v, want one session labeled work", projectSessions)
+	}
+}
+
+func TestDiscoverCache(t *testing.T) {
+	tmpDir := t.TempDir()
+	cacheDir := t.TempDir()
+	project := filepath.Join(tmpDir, "project")
+	if err := os.MkdirAll(project, 0700); err != nil {
+		t.Fatal(err)
+	}
+	createFile(t, filepath.Join(project, "a1b2c3d4-e5f6-7890-abcd-ef1234567890.jsonl"), "content")
+
+	// Listings of just-modified directories are not cached.
+	past := time.Now().Add(-time.Hour)
+	touch(t, project, past)
+	touch(t, tmpDir, past)
+
+	cfg := Config{BaseDirs: []string{tmpDir}, CacheDir: cacheDir}
+	sessions, err := NewWithConfig(cfg, &mockLogger{}).Discover()
+	if err != nil {
+		t.Fatalf("Discover() error = %v", err)
+	}
+	if len(sessions) != 1 {
+		t.Fatalf("Discover() found %d sessions, want 1", len(sessions))
+	}
+	if _, err := os.Stat(filepath.Join(cacheDir, cacheFileName)); err != nil {
+		t.Fatalf("cache file not written: %v", err)
+	}
+
+	// Sizes are read fresh even when the listing comes from the cache.
+	createFile(t, filepath.Join(project, "a1b2c3d4-e5f6-7890-abcd-ef1234567890.jsonl"), "longer content")
+	touch(t, project, past)
+
+	sessions, err = NewWithConfig(cfg, &mockLogger{}).Discover()
+	if err != nil {
+		t.Fatalf("Discover() error = %v", err)
+	}
+	if len(sessions) != 1 || sessions[0].Size != int64(len("longer content")) {
+		t.Fatalf("cached Discover() = %+v, want 1 session with fresh size", sessions)
+	}
+
+	// A new file changes the directory mtime and invalidates the listing.
+	createFile(t, filepath.Join(project, "b1b2c3d4-e5f6-7890-abcd-ef1234567890.jsonl"), "content")
+	touch(t, project, past.Add(time.Minute))
+
+	sessions, err = NewWithConfig(cfg, &mockLogger{}).Discover()
+	if err != nil {
+		t.Fatalf("Discover() error = %v", err)
+	}
+	if len(sessions) != 2 {
+		t.Errorf("Discover() after adding a file found %d sessions, want 2", len(sessions))
+	}
+
+	// A cache written with other session patterns is ignored.
+	cfg.SessionPatterns = []string{`sess_[0-9A-Z]+`}
+	createFile(t, filepath.Join(project, "sess_01HV8Z3K.jsonl"), "content")
+	touch(t, project, past.Add(time.Minute))
+
+	sessions, err = NewWithConfig(cfg, &mockLogger{}).Discover()
+	if err != nil {
+		t.Fatalf("Discover() error = %v", err)
+	}
+	if len(sessions) != 3 {
+		t.Errorf("Discover() with new patterns found %d sessions, want 3", len(sessions))
+	}
+
+	// As is one written without any_jsonl.
+	cfg.AnyJSONL = true
+	createFile(t, filepath.Join(project, "usage.jsonl"), "content")
+	touch(t, project, past.Add(time.Minute))
+
+	sessions, err = NewWithConfig(cfg, &mockLogger{}).Discover()
+	if err != nil {
+		t.Fatalf("Discover() error = %v", err)
+	}
+	if len(sessions) != 4 {
+		t.Errorf("Discover() with any_jsonl found %d sessions, want 4", len(sessions))
+	}
+}
+
+func TestCompileSessionPatternAnchoring(t *testing.T) {
+	if _, err := CompileSessionPattern(`a)|(b`); err == nil {
+		t.Error("CompileSessionPattern() accepted a pattern that escapes the anchor group")
+	}
+}
+
 func TestIsValidSessionID(t *testing.T) {
 	tests := []struct {
 		name string
@@ -230,6 +485,11 @@ func TestIsValidSessionID(t *testing.T) {
 			id:   "a1B2c3D4-e5F6-7890-aBcD-eF1234567890",
 			want: true,
 		},
+		{
+			name: "valid UUID v7",
+			id:   "01890a5d-ac96-774b-bcce-b302099a8057",
+			want: true,
+		},
 		{
 			name: "too short",
 			id:   "a1b2c3d4-e5f6-7890-abcd-ef123456789",
@@ -393,6 +653,55 @@ func BenchmarkDiscover(b *testing.B) {
 	}
 }
 
+// touch sets the modification time of path.
+func touch(t *testing.T, path string, modTime time.Time) {
+	t.Helper()
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// BenchmarkDiscoverCached measures repeat discovery with a warm cache.
+func BenchmarkDiscoverCached(b *testing.B) {
+	tmpDir := b.TempDir()
+	past := time.Now().Add(-time.Hour)
+
+	// Create 100 projects with 50 sessions each
+	for i := 0; i < 100; i++ {
+		projectDir := filepath.Join(tmpDir, "project-"+itoa(i))
+		if err := os.MkdirAll(projectDir, 0700); err != nil {
+			b.Fatal(err)
+		}
+
+		for j := 0; j < 50; j++ {
+			sessionFile := filepath.Join(projectDir,
+				"a1b2c3d4-e5f6-7890-abcd-"+padHex(j, 12)+".jsonl")
+			if err := os.WriteFile(sessionFile, []byte("test"), 0600); err != nil {
+				b.Fatal(err)
+			}
+		}
+		if err := os.Chtimes(projectDir, past, past); err != nil {
+			b.Fatal(err)
+		}
+	}
+	if err := os.Chtimes(tmpDir, past, past); err != nil {
+		b.Fatal(err)
+	}
+
+	cfg := Config{BaseDirs: []string{tmpDir}, CacheDir: b.TempDir()}
+	if _, err := NewWithConfig(cfg, &mockLogger{}).Discover(); err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		// A fresh discoverer per run, as each command invocation has.
+		if _, err := NewWithConfig(cfg, &mockLogger{}).Discover(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
 // Helper to convert int to string.
 func itoa(i int) string {
 	if i == 0 {
@@ -433,3 +742,130 @@ func padHex(n, width int) string {
 
 	return string(result)
 }
+
+func TestProjectDirName(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/Users/foo/work/app", "-Users-foo-work-app"},
+		{"/home/me/my.repo", "-home-me-my-repo"},
+		{"/srv/go_proj v2", "-srv-go-proj-v2"},
+		{`C:\Users\foo\app`, "C--Users-foo-app"},
+	}
+	for _, tt := range tests {
+		if got := ProjectDirName(tt.path); got != tt.want {
+			t.Errorf("ProjectDirName(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestDiscoverDuplicates(t *testing.T) {
+	live := t.TempDir()
+	backup := t.TempDir()
+	other := t.TempDir()
+	const id = "a1b2c3d4-e5f6-7890-abcd-ef1234567890"
+	for _, base := range []string{live, backup, other} {
+		if err := os.MkdirAll(filepath.Join(base, "project"), 0700); err != nil {
+			t.Fatal(err)
+		}
+	}
+	livePath := filepath.Join(live, "project", id+".jsonl")
+	createFile(t, livePath, "line1\nline2\n")
+	createFile(t, filepath.Join(backup, "project", id+".jsonl"), "line1\n")
+	createFile(t, filepath.Join(other, "project", id+".jsonl"), "different\n")
+	linked := filepath.Join(t.TempDir(), "linked")
+	if err := os.Symlink(live, linked); err != nil {
+		t.Skipf("symlinks unsupported: %v", err)
+	}
+
+	d := NewWithConfig(Config{BaseDirs: []string{backup, linked, live, other}}, &mockLogger{})
+	sessions, err := d.Discover()
+	if err != nil {
+		t.Fatalf("Discover() error = %v", err)
+	}
+
+	// The live file (first seen through the symlink, which makes live a
+	// repeat) and the unrelated file with the same ID remain; the backup
+	// is a copy.
+	if len(sessions) != 2 {
+		t.Fatalf("Discover() found %d sessions, want 2: %+v", len(sessions), sessions)
+	}
+	if filepath.Dir(filepath.Dir(sessions[0].FilePath)) != linked || filepath.Dir(filepath.Dir(sessions[1].FilePath)) != other {
+		t.Errorf("Discover() kept %s and %s", sessions[0].FilePath, sessions[1].FilePath)
+	}
+	dups := d.Duplicates()
+	if len(dups) != 1 || dups[0].File.FilePath != filepath.Join(backup, "project", id+".jsonl") || dups[0].Of != sessions[0].FilePath {
+		t.Errorf("Duplicates() = %+v", dups)
+	}
+}
+
+func TestDiscoverSymlinks(t *testing.T) {
+	// A dotfiles setup: the configured directory is a symlink into a
+	// repository, which is also configured under its real path.
+	root := t.TempDir()
+	realDir := filepath.Join(root, "dotfiles", "claude", "projects")
+	if err := os.MkdirAll(filepath.Join(realDir, "project"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	createFile(t, filepath.Join(realDir, "project", "a1b2c3d4-e5f6-7890-abcd-ef1234567890.jsonl"), "a")
+
+	// A second base whose only project is a symlink to another project,
+	// next to a dangling link and a link cycle.
+	other := filepath.Join(root, "other")
+	external := filepath.Join(root, "external")
+	if err := os.MkdirAll(other, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(external, 0700); err != nil {
+		t.Fatal(err)
+	}
+	createFile(t, filepath.Join(external, "b1b2c3d4-e5f6-7890-abcd-ef1234567890.jsonl"), "b")
+
+	linked := filepath.Join(root, "home", ".claude", "projects")
+	if err := os.MkdirAll(filepath.Dir(linked), 0700); err != nil {
+		t.Fatal(err)
+	}
+	cycle := filepath.Join(root, "cycle")
+	links := map[string]string{
+		linked:                            realDir,
+		filepath.Join(other, "linked"):    external,
+		filepath.Join(other, "dangling"):  filepath.Join(root, "missing"),
+		filepath.Join(other, "loop"):      filepath.Join(other, "loop"),
+		filepath.Join(other, "duplicate"): filepath.Join(realDir, "project"),
+		cycle:                             cycle,
+	}
+	for link, target := range links {
+		if err := os.Symlink(target, link); err != nil {
+			t.Skipf("symlinks unsupported: %v", err)
+		}
+	}
+
+	d := NewWithConfig(Config{BaseDirs: []string{linked, realDir, other, cycle}}, &mockLogger{})
+	sessions, err := d.Discover()
+	if err != nil {
+		t.Fatalf("Discover() error = %v", err)
+	}
+
+	got := make(map[string]string, len(sessions))
+	for _, s := range sessions {
+		got[s.SessionID[:1]] = s.FilePath
+	}
+	want := map[string]string{
+		"a": filepath.Join(linked, "project", "a1b2c3d4-e5f6-7890-abcd-ef1234567890.jsonl"),
+		"b": filepath.Join(other, "linked", "b1b2c3d4-e5f6-7890-abcd-ef1234567890.jsonl"),
+	}
+	if len(sessions) != len(want) {
+		t.Fatalf("Discover() = %+v, want %d sessions", sessions, len(want))
+	}
+	for id, path := range want {
+		if got[id] != path {
+			t.Errorf("session %s found at %s, want %s", id, got[id], path)
+		}
+	}
+	for _, s := range sessions {
+		if s.Size != 1 {
+			t.Errorf("session %s Size = %d, want the target's size 1", s.SessionID, s.Size)
+		}
+	}
+}
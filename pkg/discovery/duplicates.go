@@ -0,0 +1,118 @@
+package discovery
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"io"
+	"os"
+)
+
+// fingerprintBytes bounds how much of each file is hashed to compare
+// copies of a session.
+const fingerprintBytes = 64 * 1024
+
+// Duplicate is a session file skipped because it is a copy of another.
+type Duplicate struct {
+	// File is the skipped copy.
+	File SessionFile
+
+	// Of is the path of the file counted instead.
+	Of string
+}
+
+// removeDuplicates drops copies of the same session found in more than
+// one place, such as a symlinked or backed-up Claude directory, so usage
+// is counted once. Files with the same session ID are copies when their
+// common prefix is identical: session files are append-only, so an older
+// backup is a prefix of the live file. The largest (most complete) copy
+// is kept, the most recently modified one on a tie.
+//
+// Returns the remaining files in their original order and the skipped
+// copies.
+func removeDuplicates(sessions []SessionFile) ([]SessionFile, []Duplicate) {
+	byID := make(map[string][]int)
+	for i, s := range sessions {
+		byID[s.SessionID] = append(byID[s.SessionID], i)
+	}
+
+	skipped := make(map[int]string)
+	for _, idx := range byID {
+		if len(idx) < 2 {
+			continue
+		}
+
+		// Hash the prefix every copy has, so a shorter backup matches.
+		sizes := make(map[int]int64, len(idx))
+		n := int64(fingerprintBytes)
+		for _, i := range idx {
+			sizes[i] = fileSize(sessions[i])
+			if sizes[i] < n {
+				n = sizes[i]
+			}
+		}
+		groups := make(map[[sha256.Size]byte][]int)
+		for _, i := range idx {
+			fp, ok := fingerprint(sessions[i].FilePath, n)
+			if !ok {
+				continue
+			}
+			groups[fp] = append(groups[fp], i)
+		}
+
+		for _, group := range groups {
+			keep := group[0]
+			for _, i := range group[1:] {
+				if sizes[i] > sizes[keep] || (sizes[i] == sizes[keep] && sessions[i].ModTime > sessions[keep].ModTime) {
+					keep = i
+				}
+			}
+			for _, i := range group {
+				if i != keep {
+					skipped[i] = sessions[keep].FilePath
+				}
+			}
+		}
+	}
+	if len(skipped) == 0 {
+		return sessions, nil
+	}
+
+	unique := make([]SessionFile, 0, len(sessions)-len(skipped))
+	var duplicates []Duplicate
+	for i, s := range sessions {
+		if of, ok := skipped[i]; ok {
+			duplicates = append(duplicates, Duplicate{File: s, Of: of})
+			continue
+		}
+		unique = append(unique, s)
+	}
+	return unique, duplicates
+}
+
+// fileSize returns the size of the file a session path refers to,
+// following symlinks, which SessionFile.Size does not.
+func fileSize(s SessionFile) int64 {
+	info, err := os.Stat(s.FilePath)
+	if err != nil {
+		return s.Size
+	}
+	return info.Size()
+}
+
+// fingerprint hashes the first n bytes of the file at path. ok is false
+// when the file cannot be read.
+func fingerprint(path string, n int64) (sum [sha256.Size]byte, ok bool) {
+	f, err := os.Open(path) //nolint:gosec // path comes from directory scanning
+	if err != nil {
+		return sum, false
+	}
+	defer func() {
+		_ = f.Close() //nolint:errcheck // read-only
+	}()
+
+	var buf bytes.Buffer
+	if _, err := io.CopyN(&buf, f, n); err != nil && err != io.EOF {
+		return sum, false
+	}
+	return sha256.Sum256(buf.Bytes()), true
+}
@@ -0,0 +1,34 @@
+package discovery
+
+import "os"
+
+// Claude directories are often symlinks into a dotfiles repository or
+// bind mounts into a container, so the same directory can be reached by
+// more than one path. Discovery follows links but only two levels deep
+// (base directory, project directory), so link cycles cannot recurse;
+// they surface as stat errors and the link is skipped.
+
+// isSymlink reports whether path itself is a symbolic link. A symlink
+// that cannot be followed is dangling or part of a cycle.
+func isSymlink(path string) bool {
+	info, err := os.Lstat(path)
+	return err == nil && info.Mode()&os.ModeSymlink != 0
+}
+
+// dirSet records directories already scanned, by identity rather than
+// path, so a directory reached through a symlink or bind mount is
+// scanned once.
+type dirSet struct {
+	seen []os.FileInfo
+}
+
+// add records info and reports whether the directory was new.
+func (s *dirSet) add(info os.FileInfo) bool {
+	for _, seen := range s.seen {
+		if os.SameFile(seen, info) {
+			return false
+		}
+	}
+	s.seen = append(s.seen, info)
+	return true
+}
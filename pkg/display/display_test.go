@@ -105,6 +105,18 @@ func TestTableFormatter_FormatStats(t *testing.T) {
 	if !strings.Contains(output, "2024-01-01") {
 		t.Error("Output missing timestamps")
 	}
+	if strings.Contains(output, "CO2e") {
+		t.Error("Output shows a footprint without rates")
+	}
+
+	stats.CO2eGrams = 1250
+	buf.Reset()
+	if err := formatter.FormatStats(&buf, stats); err != nil {
+		t.Fatalf("FormatStats() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "1.25 kg CO2e") {
+		t.Errorf("Output missing footprint:\n%s", buf.String())
+	}
 }
 
 func TestTableFormatter_FormatGroupedStats(t *testing.T) {
@@ -154,6 +166,175 @@ func TestTableFormatter_FormatGroupedStats(t *testing.T) {
 	}
 }
 
+func TestFormatGroupedStats_CostAndRate(t *testing.T) {
+	t.Parallel()
+
+	first := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	grouped := map[string]aggregator.Statistics{
+		"model-1": {
+			Count:       40,
+			TotalTokens: 4000,
+			CostUSD:     1.234,
+			FirstSeen:   first,
+			LastSeen:    first.Add(4 * 24 * time.Hour),
+		},
+	}
+
+	tests := []struct {
+		name   string
+		config Config
+		want   []string
+		absent []string
+	}{
+		{
+			name:   "table without toggles",
+			config: Config{Format: FormatTable},
+			absent: []string{"Cost", "Req/Day"},
+		},
+		{
+			name:   "table with cost and rate",
+			config: Config{Format: FormatTable, ShowCost: true, ShowRate: true},
+			want:   []string{"Cost", "Req/Day", "$1.23", "10.0"},
+		},
+		{
+			name:   "simple with cost and rate",
+			config: Config{Format: FormatSimple, ShowCost: true, ShowRate: true},
+			want:   []string{"$1.23", "10.0 req/day"},
+		},
+		{
+			name:   "json with rate",
+			config: Config{Format: FormatJSON, ShowRate: true},
+			want:   []string{`"CostUSD": 1.234`, `"RequestsPerDay": 10`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			if err := New(tt.config).FormatGroupedStats(&buf, grouped, []string{"Model"}); err != nil {
+				t.Fatalf("FormatGroupedStats() error = %v", err)
+			}
+
+			output := buf.String()
+			for _, s := range tt.want {
+				if !strings.Contains(output, s) {
+					t.Errorf("output missing %q:\n%s", s, output)
+				}
+			}
+			for _, s := range tt.absent {
+				if strings.Contains(output, s) {
+					t.Errorf("output unexpectedly contains %q:\n%s", s, output)
+				}
+			}
+		})
+	}
+}
+
+func TestFormatGroupedStats_Share(t *testing.T) {
+	t.Parallel()
+
+	grouped := map[string]aggregator.Statistics{
+		"model-1": {Count: 3, TotalTokens: 7500, CostUSD: 1},
+		"model-2": {Count: 1, TotalTokens: 2500, CostUSD: 3},
+	}
+
+	tests := []struct {
+		name   string
+		config Config
+		want   []string
+	}{
+		{"table", Config{Format: FormatTable}, []string{"Share", "75.0%", "25.0%"}},
+		{"table with cost", Config{Format: FormatTable, ShowCost: true}, []string{"Cost %", "75.0%", "25.0%"}},
+		{"simple", Config{Format: FormatSimple}, []string{"7,500 tokens, 75.0%"}},
+		{"json", Config{Format: FormatJSON}, []string{`"Share": 75`, `"Share": 25`}},
+		{"json with cost", Config{Format: FormatJSON, ShowCost: true}, []string{`"CostShare": 75`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			if err := New(tt.config).FormatGroupedStats(&buf, grouped, []string{"Model"}); err != nil {
+				t.Fatalf("FormatGroupedStats() error = %v", err)
+			}
+
+			output := buf.String()
+			for _, s := range tt.want {
+				if !strings.Contains(output, s) {
+					t.Errorf("output missing %q:\n%s", s, output)
+				}
+			}
+		})
+	}
+}
+
+func TestFormatStats_Errors(t *testing.T) {
+	t.Parallel()
+
+	stats := aggregator.Statistics{Count: 40, SessionCount: 1, TotalTokens: 4000, ErrorCount: 2}
+	grouped := map[string]aggregator.Statistics{
+		"session-1": stats,
+		"session-2": {Count: 10, TotalTokens: 1000},
+	}
+
+	tests := []struct {
+		name   string
+		config Config
+		want   []string
+	}{
+		{"table", Config{Format: FormatTable}, []string{"API Errors", "2 (5.0%)"}},
+		{"simple", Config{Format: FormatSimple}, []string{", 2 API errors (5.0%)"}},
+		{"json", Config{Format: FormatJSON}, []string{`"ErrorCount": 2`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			if err := New(tt.config).FormatStats(&buf, stats); err != nil {
+				t.Fatalf("FormatStats() error = %v", err)
+			}
+			for _, s := range tt.want {
+				if !strings.Contains(buf.String(), s) {
+					t.Errorf("output missing %q:\n%s", s, buf.String())
+				}
+			}
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := New(Config{Format: FormatTable}).FormatGroupedStats(&buf, grouped, []string{"Session"}); err != nil {
+		t.Fatalf("FormatGroupedStats() error = %v", err)
+	}
+	for _, s := range []string{"Errors", "2 (5.0%)", "0 (0.0%)"} {
+		if !strings.Contains(buf.String(), s) {
+			t.Errorf("grouped output missing %q:\n%s", s, buf.String())
+		}
+	}
+}
+
+func TestFormatTopSessions_Share(t *testing.T) {
+	t.Parallel()
+
+	sessions := []aggregator.SessionStats{
+		{SessionID: "session-1", Model: "model-1", Statistics: aggregator.Statistics{Count: 1, TotalTokens: 600}, Share: 60},
+	}
+
+	for _, format := range []Format{FormatTable, FormatSimple, FormatJSON} {
+		var buf bytes.Buffer
+		if err := New(Config{Format: format}).FormatTopSessions(&buf, sessions); err != nil {
+			t.Fatalf("FormatTopSessions(%s) error = %v", format, err)
+		}
+		if !strings.Contains(buf.String(), "60") {
+			t.Errorf("%s output missing share:\n%s", format, buf.String())
+		}
+	}
+}
+
 func TestTableFormatter_FormatTopSessions(t *testing.T) {
 	t.Parallel()
 
@@ -382,3 +563,110 @@ func TestEmptyData(t *testing.T) {
 		t.Error("Empty top sessions should show 'No data'")
 	}
 }
+
+func TestWriteTable_WideCharacters(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	header := []string{"모델", "합계"}
+	rows := [][]string{{"claude-sonnet-4", "1,200"}, {"opus", "35"}}
+	if err := WriteTable(&buf, header, rows, false); err != nil {
+		t.Fatalf("WriteTable() error = %v", err)
+	}
+
+	// Hangul is two columns wide, so the second column starts at the same
+	// terminal column on every line.
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	want := textWidth("claude-sonnet-4  ")
+	for _, line := range lines {
+		fields := strings.SplitN(line, "  ", 2)
+		if len(fields) != 2 {
+			t.Fatalf("unexpected line %q", line)
+		}
+		if got := textWidth(line) - textWidth(strings.TrimLeft(fields[1], " ")); got != want {
+			t.Errorf("line %q: second column at %d, want %d", line, got, want)
+		}
+	}
+}
+
+func TestAccessibleMode(t *testing.T) {
+	t.Parallel()
+
+	formatter := New(Config{Format: FormatTable, Accessible: true})
+
+	var buf bytes.Buffer
+	stats := aggregator.Statistics{Count: 3, SessionCount: 1, TotalTokens: 1500, InputTokens: 1000, OutputTokens: 500}
+	if err := formatter.FormatStats(&buf, stats); err != nil {
+		t.Fatalf("FormatStats() error = %v", err)
+	}
+	output := buf.String()
+	if !strings.Contains(output, "Total Tokens: 1,500\n") {
+		t.Errorf("FormatStats() missing labeled line:\n%s", output)
+	}
+	if strings.Contains(output, "===") || strings.Contains(output, "---") {
+		t.Errorf("FormatStats() contains separator lines:\n%s", output)
+	}
+
+	buf.Reset()
+	grouped := map[string]aggregator.Statistics{
+		"claude-sonnet-4": {Count: 2, TotalTokens: 300, InputTokens: 200, OutputTokens: 100},
+	}
+	if err := formatter.FormatGroupedStats(&buf, grouped, []string{"model"}); err != nil {
+		t.Fatalf("FormatGroupedStats() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "model: claude-sonnet-4, Entries: 2, Total: 300, Share: 100.0%") {
+		t.Errorf("FormatGroupedStats() did not label cells:\n%s", buf.String())
+	}
+}
+
+func TestUnits(t *testing.T) {
+	t.Parallel()
+
+	grouped := map[string]aggregator.Statistics{
+		"claude-sonnet-4": {Count: 2, TotalTokens: 1234567, InputTokens: 1000000, OutputTokens: 234567, CostUSD: 4.5},
+	}
+	render := func(cfg Config) string {
+		var buf bytes.Buffer
+		cfg.Format = FormatTable
+		if err := New(cfg).FormatGroupedStats(&buf, grouped, []string{"model"}); err != nil {
+			t.Fatalf("FormatGroupedStats() error = %v", err)
+		}
+		return buf.String()
+	}
+
+	output := render(Config{Units: UnitsThousands})
+	if !strings.Contains(output, "1,234.6K") || strings.Contains(output, "$4.50") {
+		t.Errorf("thousands output:\n%s", output)
+	}
+
+	output = render(Config{Units: UnitsCost})
+	if !strings.Contains(output, "$4.50") || strings.Contains(output, "1,234,567") {
+		t.Errorf("cost output:\n%s", output)
+	}
+
+	output = render(Config{Units: UnitsCost, Detailed: true})
+	if !strings.Contains(output, "$4.50") || !strings.Contains(output, "1,234,567") {
+		t.Errorf("detailed cost output:\n%s", output)
+	}
+	if strings.Index(output, "$4.50") > strings.Index(output, "1,234,567") {
+		t.Errorf("cost is not the leading column:\n%s", output)
+	}
+
+	output = render(Config{Units: UnitsTokens, Detailed: true})
+	if !strings.Contains(output, "1,234,567") || !strings.Contains(output, "$4.50") {
+		t.Errorf("detailed token output:\n%s", output)
+	}
+}
+
+func TestParseUnits(t *testing.T) {
+	t.Parallel()
+
+	for _, s := range []string{"", "tokens", "k", "cost"} {
+		if _, err := ParseUnits(s); err != nil {
+			t.Errorf("ParseUnits(%q) error = %v", s, err)
+		}
+	}
+	if _, err := ParseUnits("usd"); err == nil {
+		t.Error("ParseUnits(usd) error = nil, want error")
+	}
+}
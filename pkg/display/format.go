@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"strings"
 	"time"
+
+	"github.com/0xmhha/token-monitor/pkg/aggregator"
 )
 
 // FormatCompact formats a number with K/M suffix for compact display.
@@ -23,8 +25,9 @@ func FormatCompact(n int) string {
 	}
 }
 
-// FormatDuration formats a duration as a human-readable string
-// (e.g. "3h42m", "45m", "12s", "0s").
+// FormatDuration formats a duration as a short human-readable string
+// (e.g. "3h42m", "45m", "12s", "0s") for status lines and cards; see
+// FormatDurationLong for the long form with days.
 func FormatDuration(d time.Duration) string {
 	if d == 0 {
 		return "0s"
@@ -45,6 +48,87 @@ func FormatDuration(d time.Duration) string {
 	return fmt.Sprintf("%ds", seconds)
 }
 
+// durationUnits are the units of FormatDurationLong, largest first.
+var durationUnits = []struct {
+	size   time.Duration
+	suffix string
+}{
+	{24 * time.Hour, "d"},
+	{time.Hour, "h"},
+	{time.Minute, "m"},
+	{time.Second, "s"},
+}
+
+// FormatDurationLong formats a duration in long form with days, e.g.
+// "1d 3h 20m", for reports and detail views. precision is the number of
+// units shown, starting at the largest nonzero one; smaller units are
+// truncated and zero units are left out, so with precision 2, 1d 3h 20m
+// is "1d 3h" and 2h 0m 30s is "2h". Durations under a second are "0s".
+func FormatDurationLong(d time.Duration, precision int) string {
+	if d < 0 {
+		return "-" + FormatDurationLong(-d, precision)
+	}
+
+	var parts []string
+	shown := 0
+	for _, unit := range durationUnits {
+		n := d / unit.size
+		d -= n * unit.size
+		if n == 0 && shown == 0 {
+			continue
+		}
+		if shown++; shown > precision {
+			break
+		}
+		if n > 0 {
+			parts = append(parts, fmt.Sprintf("%d%s", n, unit.suffix))
+		}
+	}
+	if len(parts) == 0 {
+		return "0s"
+	}
+	return strings.Join(parts, " ")
+}
+
+// absoluteTimeLayout is the layout used when times are shown as absolute.
+const absoluteTimeLayout = "2006-01-02 15:04"
+
+// FormatRelativeTime formats t relative to now as a humanized string
+// (e.g. "just now", "5m ago", "2h ago", "3d ago"). Times older than
+// 30 days, or in the future by more than a minute, fall back to an
+// absolute date.
+func FormatRelativeTime(t, now time.Time) string {
+	d := now.Sub(t)
+
+	switch {
+	case d < -time.Minute:
+		return t.Format(absoluteTimeLayout)
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	case d < 30*24*time.Hour:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	default:
+		return t.Format("2006-01-02")
+	}
+}
+
+// FormatTime formats t for list and detail views: relative to the current
+// time by default, or as "2006-01-02 15:04" when absolute is set.
+// Zero times are shown as "-".
+func FormatTime(t time.Time, absolute bool) string {
+	if t.IsZero() {
+		return "-"
+	}
+	if absolute {
+		return t.Format(absoluteTimeLayout)
+	}
+	return FormatRelativeTime(t, time.Now())
+}
+
 // FormatRate formats a float rate value with 1 decimal place
 // (e.g. "2145.3", "0.0").
 func FormatRate(f float64) string {
@@ -83,3 +167,117 @@ func formatPositiveWithCommas(n int) string {
 
 	return sb.String()
 }
+
+// sparkTicks are the bar glyphs used by Sparkline, lowest first.
+var sparkTicks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline renders values as a compact bar chart, one glyph per value
+// (e.g. "▁▁▃█▂▁▅"). Zero values use the lowest bar; non-zero values are
+// scaled against the maximum so any activity stands out.
+func Sparkline(values []int) string {
+	maxValue := 0
+	for _, v := range values {
+		if v > maxValue {
+			maxValue = v
+		}
+	}
+
+	var sb strings.Builder
+	for _, v := range values {
+		if v <= 0 || maxValue == 0 {
+			sb.WriteRune(sparkTicks[0])
+			continue
+		}
+		// Map (0, max] onto ticks[1:].
+		idx := 1 + (v*(len(sparkTicks)-1)-1)/maxValue
+		sb.WriteRune(sparkTicks[idx])
+	}
+
+	return sb.String()
+}
+
+// BurnDownBar renders a billing block's pace as a bar width glyphs wide:
+// the quota used fills from the left ("█", "░" for the rest) and "│"
+// marks the time elapsed, e.g. "███░░│░░░░" is a block half gone with 30%
+// of its quota used. Use over the marker means the quota runs out before
+// the block ends at this pace.
+func BurnDownBar(pace aggregator.BlockPace, width int) string {
+	if width < 1 {
+		return ""
+	}
+	used := int(min(1, pace.Used)*float64(width) + 0.5)
+	marker := min(width-1, int(pace.Elapsed*float64(width)))
+
+	var sb strings.Builder
+	for i := 0; i < width; i++ {
+		switch {
+		case i == marker:
+			sb.WriteRune('│')
+		case i < used:
+			sb.WriteRune('█')
+		default:
+			sb.WriteRune('░')
+		}
+	}
+	return sb.String()
+}
+
+// asciiGlyphs maps box drawing, sparkline bars, calendar shades, and symbols
+// used in output to ASCII. Box drawing maps one-to-one so tables stay
+// aligned.
+var asciiGlyphs = map[rune]string{
+	'─': "-", '━': "-", '═': "=",
+	'│': "|", '┃': "|", '║': "|",
+	'┌': "+", '┐': "+", '└': "+", '┘': "+",
+	'├': "+", '┤': "+", '┬': "+", '┴': "+", '┼': "+",
+	'╔': "+", '╗': "+", '╚': "+", '╝': "+",
+	'╭': "+", '╮': "+", '╰': "+", '╯': "+",
+	'▁': "_", '▂': ".", '▃': ":", '▄': "-", '▅': "=", '▆': "+", '▇': "*", '█': "#",
+	'·': ".", '░': ":", '▒': "+", '▓': "*",
+	'✓': "OK", '✗': "FAIL",
+	'↑': "^", '↓': "v", '→': "->",
+	'—': "--", '…': "...",
+}
+
+// ASCII returns s with box drawing, sparkline bars, and symbols replaced by
+// ASCII equivalents, and emoji removed along with the spaces after them.
+// Other text, such as localized messages, is left unchanged.
+func ASCII(s string) string {
+	var sb strings.Builder
+	sb.Grow(len(s))
+
+	afterEmoji := false
+	for _, r := range s {
+		if glyph, ok := asciiGlyphs[r]; ok {
+			sb.WriteString(glyph)
+			afterEmoji = false
+			continue
+		}
+		if isEmoji(r) {
+			afterEmoji = true
+			continue
+		}
+		if afterEmoji && r == ' ' {
+			continue
+		}
+		afterEmoji = false
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// isEmoji reports whether r is a pictograph or an emoji variation selector.
+func isEmoji(r rune) bool {
+	switch {
+	case r >= 0x1F000 && r <= 0x1FAFF: // pictographs, emoticons, symbols
+		return true
+	case r >= 0x23E9 && r <= 0x23FA: // clocks and media controls (⏰, ⏱)
+		return true
+	case r >= 0x2600 && r <= 0x27BF: // miscellaneous symbols and dingbats
+		return true
+	case r == 0xFE0F: // emoji presentation selector
+		return true
+	default:
+		return false
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/0xmhha/token-monitor/pkg/aggregator"
 )
 
 func TestFormatCompact(t *testing.T) {
@@ -66,6 +68,38 @@ func TestFormatDuration(t *testing.T) {
 	}
 }
 
+func TestFormatDurationLong(t *testing.T) {
+	t.Parallel()
+
+	day := 24 * time.Hour
+	tests := []struct {
+		name      string
+		input     time.Duration
+		precision int
+		want      string
+	}{
+		{"zero", 0, 2, "0s"},
+		{"under a second", 400 * time.Millisecond, 2, "0s"},
+		{"seconds only", 45 * time.Second, 2, "45s"},
+		{"minutes and seconds", 5*time.Minute + 30*time.Second, 2, "5m 30s"},
+		{"hours truncate seconds", 2*time.Hour + 10*time.Minute + 59*time.Second, 2, "2h 10m"},
+		{"days", day + 3*time.Hour + 20*time.Minute, 3, "1d 3h 20m"},
+		{"days at precision 2", day + 3*time.Hour + 20*time.Minute, 2, "1d 3h"},
+		{"zero unit left out", day + 20*time.Minute, 3, "1d 20m"},
+		{"zero units past precision", 2*time.Hour + 30*time.Second, 2, "2h"},
+		{"precision 1", 3*day + 23*time.Hour, 1, "3d"},
+		{"negative", -(90 * time.Minute), 2, "-1h 30m"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, FormatDurationLong(tt.input, tt.precision))
+		})
+	}
+}
+
 func TestFormatRate(t *testing.T) {
 	t.Parallel()
 
@@ -114,3 +148,119 @@ func TestFormatTokenCount(t *testing.T) {
 		})
 	}
 }
+
+func TestSparkline(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		input []int
+		want  string
+	}{
+		{"empty", nil, ""},
+		{"all zero", []int{0, 0, 0}, "▁▁▁"},
+		{"single peak", []int{0, 0, 10}, "▁▁█"},
+		{"small non-zero stands out", []int{1, 0, 1000}, "▂▁█"},
+		{"scaled", []int{0, 50, 100}, "▁▅█"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := Sparkline(tt.input)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestBurnDownBar(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		pace aggregator.BlockPace
+		want string
+	}{
+		{"ahead of pace", aggregator.BlockPace{Elapsed: 0.5, Used: 0.3}, "███░░│░░░░"},
+		{"over pace", aggregator.BlockPace{Elapsed: 0.2, Used: 0.6}, "██│███░░░░"},
+		{"quota exceeded", aggregator.BlockPace{Elapsed: 0.5, Used: 1.4}, "█████│████"},
+		{"block over", aggregator.BlockPace{Elapsed: 1, Used: 0}, "░░░░░░░░░│"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, BurnDownBar(tt.pace, 10))
+		})
+	}
+	assert.Equal(t, "", BurnDownBar(aggregator.BlockPace{}, 0))
+}
+
+func TestFormatRelativeTime(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 5, 7, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		input time.Time
+		want  string
+	}{
+		{"seconds ago", now.Add(-30 * time.Second), "just now"},
+		{"slightly in future", now.Add(30 * time.Second), "just now"},
+		{"minutes ago", now.Add(-5 * time.Minute), "5m ago"},
+		{"hours ago", now.Add(-2*time.Hour - 30*time.Minute), "2h ago"},
+		{"days ago", now.Add(-3 * 24 * time.Hour), "3d ago"},
+		{"older than 30 days", now.AddDate(0, -2, 0), "2026-03-07"},
+		{"far future", now.Add(2 * time.Hour), "2026-05-07 14:00"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := FormatRelativeTime(tt.input, now)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFormatTime(t *testing.T) {
+	t.Parallel()
+
+	ts := time.Date(2026, 5, 7, 9, 30, 0, 0, time.UTC)
+
+	assert.Equal(t, "-", FormatTime(time.Time{}, false))
+	assert.Equal(t, "-", FormatTime(time.Time{}, true))
+	assert.Equal(t, "2026-05-07 09:30", FormatTime(ts, true))
+	assert.Equal(t, "just now", FormatTime(time.Now(), false))
+}
+
+func TestASCII(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"box row", "│ Requests        │ %12d │", "| Requests        | %12d |"},
+		{"box border", "┌────┬──┐", "+----+--+"},
+		{"emoji heading", "📊 Token Breakdown", "Token Breakdown"},
+		{"emoji with selector", "⏱️  First: 10:00", "First: 10:00"},
+		{"check mark", "✓ Configuration is valid", "OK Configuration is valid"},
+		{"sparkline", "▁▃█", "_:#"},
+		{"calendar shades", "·· ░░ ▒▒ ▓▓ ██", ".. :: ++ ** ##"},
+		{"localized text", "토큰 사용량 통계", "토큰 사용량 통계"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, ASCII(tt.input))
+		})
+	}
+}
@@ -3,6 +3,12 @@ package display
 import (
 	"fmt"
 	"io"
+	"strconv"
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+
+	"github.com/0xmhha/token-monitor/pkg/aggregator"
 )
 
 // New creates a new formatter based on configuration.
@@ -47,12 +53,87 @@ func formatNumber(n int) string {
 	return result
 }
 
+// FormatNumber formats a token count with thousand separators.
+func FormatNumber(n int) string {
+	return formatNumber(n)
+}
+
+// FormatCost formats a USD cost as "$x.xx".
+func FormatCost(usd float64) string {
+	return formatCost(usd)
+}
+
+// FormatCO2e formats an estimated carbon footprint in grams as g, kg,
+// or t of CO2e.
+func FormatCO2e(grams float64) string {
+	switch {
+	case grams >= 1_000_000:
+		return fmt.Sprintf("%.2f t CO2e", grams/1_000_000)
+	case grams >= 1000:
+		return fmt.Sprintf("%.2f kg CO2e", grams/1000)
+	default:
+		return fmt.Sprintf("%.1f g CO2e", grams)
+	}
+}
+
+// formatThousands formats a token count in thousands with one decimal
+// place and a K suffix, e.g. "1,234.5K".
+func formatThousands(n int) string {
+	s := strconv.FormatFloat(float64(n)/1000, 'f', 1, 64)
+	whole, frac, _ := strings.Cut(s, ".")
+	w, _ := strconv.Atoi(whole) //nolint:errcheck // formatted above
+	return formatNumber(w) + "." + frac + "K"
+}
+
+// FormatTokens formats a token count in the given units. Cost units
+// format tokens as raw counts.
+func FormatTokens(n int, units Units) string {
+	if units == UnitsThousands {
+		return formatThousands(n)
+	}
+	return formatNumber(n)
+}
+
 // formatFloat formats a float with specified precision.
 func formatFloat(f float64, precision int) string {
 	format := fmt.Sprintf("%%.%df", precision)
 	return fmt.Sprintf(format, f)
 }
 
+// formatCost formats a USD cost with two decimal places.
+func formatCost(usd float64) string {
+	return fmt.Sprintf("$%.2f", usd)
+}
+
+// formatShare formats a percentage with one decimal place.
+func formatShare(pct float64) string {
+	return fmt.Sprintf("%.1f%%", pct)
+}
+
+// groupTotals returns the token and cost totals across all groups.
+func groupTotals(grouped map[string]aggregator.Statistics) (tokens int, cost float64) {
+	for _, stats := range grouped {
+		tokens += stats.TotalTokens
+		cost += stats.CostUSD
+	}
+	return tokens, cost
+}
+
+// groupErrors returns the number of failed requests across all groups.
+func groupErrors(grouped map[string]aggregator.Statistics) int {
+	errors := 0
+	for _, stats := range grouped {
+		errors += stats.ErrorCount
+	}
+	return errors
+}
+
+// errorsValue formats the failed requests with their share of entries,
+// e.g. "3 (2.5%)".
+func errorsValue(stats aggregator.Statistics) string {
+	return fmt.Sprintf("%s (%s)", formatNumber(stats.ErrorCount), formatShare(stats.ErrorRate()))
+}
+
 // validateDimensions validates dimension names.
 func validateDimensions(dimensions []string) error {
 	if len(dimensions) == 0 {
@@ -68,11 +149,32 @@ func writeHeader(w io.Writer, title string, compact bool) error {
 		return err
 	}
 
-	separator := ""
-	for i := 0; i < len(title); i++ {
-		separator += "="
-	}
+	separator := strings.Repeat("=", textWidth(title))
 
 	_, err := fmt.Fprintf(w, "\n%s\n%s\n\n", title, separator)
 	return err
 }
+
+// textWidth returns the terminal column width of s, counting wide
+// characters such as Hangul as two columns.
+func textWidth(s string) int {
+	return runewidth.StringWidth(s)
+}
+
+// padRight pads s with spaces to width terminal columns.
+func padRight(s string, width int) string {
+	if pad := width - textWidth(s); pad > 0 {
+		return s + strings.Repeat(" ", pad)
+	}
+	return s
+}
+
+// TextWidth returns the terminal column width of s.
+func TextWidth(s string) int {
+	return textWidth(s)
+}
+
+// PadRight pads s with spaces to width terminal columns.
+func PadRight(s string, width int) string {
+	return padRight(s, width)
+}
@@ -12,6 +12,15 @@ type jsonFormatter struct {
 	config Config
 }
 
+// groupedJSON is the JSON form of grouped statistics with derived fields.
+type groupedJSON struct {
+	aggregator.Statistics
+	Share          float64
+	CostShare      float64 `json:",omitempty"`
+	RequestsPerDay float64 `json:",omitempty"`
+	ErrorRate      float64 `json:",omitempty"`
+}
+
 // FormatStats implements Formatter.FormatStats.
 func (f *jsonFormatter) FormatStats(w io.Writer, stats aggregator.Statistics) error {
 	encoder := json.NewEncoder(w)
@@ -33,7 +42,26 @@ func (f *jsonFormatter) FormatGroupedStats(w io.Writer, grouped map[string]aggre
 		encoder.SetIndent("", "  ")
 	}
 
-	return encoder.Encode(grouped)
+	totalTokens, totalCost := groupTotals(grouped)
+
+	// Extend each group with derived share and rate fields.
+	extended := make(map[string]groupedJSON, len(grouped))
+	for key, stats := range grouped {
+		g := groupedJSON{
+			Statistics: stats,
+			Share:      aggregator.PercentOf(float64(stats.TotalTokens), float64(totalTokens)),
+		}
+		if f.config.ShowCost {
+			g.CostShare = aggregator.PercentOf(stats.CostUSD, totalCost)
+		}
+		if f.config.ShowRate {
+			g.RequestsPerDay = stats.RequestsPerDay()
+		}
+		g.ErrorRate = stats.ErrorRate()
+		extended[key] = g
+	}
+
+	return encoder.Encode(extended)
 }
 
 // FormatTopSessions implements Formatter.FormatTopSessions.
@@ -0,0 +1,70 @@
+package display
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// Output separates command results from diagnostics.
+//
+// Data receives results (tables, JSON, exported records) and is normally
+// stdout so it can be piped into other tools. Diag receives everything
+// else (progress, confirmations, prompts, warnings) and is normally
+// stderr. Commands should never write diagnostics to Data.
+type Output struct {
+	// Data is the destination for command results.
+	Data io.Writer
+
+	// Diag is the destination for diagnostics and informational messages.
+	Diag io.Writer
+
+	// Quiet suppresses informational messages written with Infof.
+	// Warnings and data are always written.
+	Quiet bool
+
+	// ASCII replaces box drawing, symbols, and emoji with ASCII (see ASCII).
+	ASCII bool
+}
+
+// StdOutput returns an Output writing data to stdout and diagnostics to stderr.
+func StdOutput(quiet bool) Output {
+	return Output{
+		Data:  os.Stdout,
+		Diag:  os.Stderr,
+		Quiet: quiet,
+	}
+}
+
+// Printf writes formatted command results to Data.
+func (o Output) Printf(format string, args ...interface{}) {
+	o.write(o.Data, fmt.Sprintf(format, args...))
+}
+
+// Println writes command results to Data followed by a newline.
+func (o Output) Println(args ...interface{}) {
+	o.write(o.Data, fmt.Sprintln(args...))
+}
+
+// Infof writes a non-essential informational message to Diag.
+// Nothing is written in quiet mode.
+func (o Output) Infof(format string, args ...interface{}) {
+	if o.Quiet {
+		return
+	}
+	o.write(o.Diag, fmt.Sprintf(format, args...))
+}
+
+// Warnf writes a diagnostic message to Diag, even in quiet mode.
+// Use it for prompts and failures the user must see.
+func (o Output) Warnf(format string, args ...interface{}) {
+	o.write(o.Diag, fmt.Sprintf(format, args...))
+}
+
+// write writes s to w, converted to ASCII if requested.
+func (o Output) write(w io.Writer, s string) {
+	if o.ASCII {
+		s = ASCII(s)
+	}
+	_, _ = io.WriteString(w, s) //nolint:errcheck // best effort terminal output
+}
@@ -0,0 +1,50 @@
+package display
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutput_SeparatesDataAndDiagnostics(t *testing.T) {
+	t.Parallel()
+
+	var data, diag bytes.Buffer
+	out := Output{Data: &data, Diag: &diag}
+
+	out.Printf("rows: %d\n", 3)
+	out.Println("done")
+	out.Infof("found %d sessions\n", 2)
+	out.Warnf("warning: %s\n", "stale")
+
+	assert.Equal(t, "rows: 3\ndone\n", data.String())
+	assert.Equal(t, "found 2 sessions\nwarning: stale\n", diag.String())
+}
+
+func TestOutput_QuietSuppressesInfoOnly(t *testing.T) {
+	t.Parallel()
+
+	var data, diag bytes.Buffer
+	out := Output{Data: &data, Diag: &diag, Quiet: true}
+
+	out.Printf("result\n")
+	out.Infof("informational\n")
+	out.Warnf("prompt? ")
+
+	assert.Equal(t, "result\n", data.String())
+	assert.Equal(t, "prompt? ", diag.String())
+}
+
+func TestOutput_ASCII(t *testing.T) {
+	t.Parallel()
+
+	var data, diag bytes.Buffer
+	out := Output{Data: &data, Diag: &diag, ASCII: true}
+
+	out.Println("│ Total │")
+	out.Warnf("✗ failed\n")
+
+	assert.Equal(t, "| Total |\n", data.String())
+	assert.Equal(t, "FAIL failed\n", diag.String())
+}
@@ -5,6 +5,7 @@ import (
 	"io"
 
 	"github.com/0xmhha/token-monitor/pkg/aggregator"
+	"github.com/0xmhha/token-monitor/pkg/i18n"
 )
 
 // simpleFormatter formats output as simple text.
@@ -14,13 +15,17 @@ type simpleFormatter struct {
 
 // FormatStats implements Formatter.FormatStats.
 func (f *simpleFormatter) FormatStats(w io.Writer, stats aggregator.Statistics) error {
-	_, err := fmt.Fprintf(w, "Entries: %d | Sessions: %d | Total: %s | Avg: %s | Min: %s | Max: %s\n",
+	line := i18n.Tf("simple.stats",
 		stats.Count,
 		stats.SessionCount,
 		formatNumber(stats.TotalTokens),
 		formatFloat(stats.AvgTokens, 1),
 		formatNumber(stats.MinTokens),
 		formatNumber(stats.MaxTokens))
+	if stats.ErrorCount > 0 {
+		line += i18n.Tf("simple.errors", stats.ErrorCount, formatShare(stats.ErrorRate()))
+	}
+	_, err := fmt.Fprintln(w, line)
 	return err
 }
 
@@ -30,12 +35,27 @@ func (f *simpleFormatter) FormatGroupedStats(w io.Writer, grouped map[string]agg
 		return err
 	}
 
+	totalTokens, totalCost := groupTotals(grouped)
+
 	for key, stats := range grouped {
-		if _, err := fmt.Fprintf(w, "%s: %d entries, %s tokens (avg: %s)\n",
+		line := i18n.Tf("simple.group",
 			key,
 			stats.Count,
 			formatNumber(stats.TotalTokens),
-			formatFloat(stats.AvgTokens, 1)); err != nil {
+			formatShare(aggregator.PercentOf(float64(stats.TotalTokens), float64(totalTokens))),
+			formatFloat(stats.AvgTokens, 1))
+		if f.config.ShowCost {
+			line += fmt.Sprintf(", %s (%s)",
+				formatCost(stats.CostUSD),
+				formatShare(aggregator.PercentOf(stats.CostUSD, totalCost)))
+		}
+		if f.config.ShowRate {
+			line += i18n.Tf("simple.req_day", formatFloat(stats.RequestsPerDay(), 1))
+		}
+		if stats.ErrorCount > 0 {
+			line += i18n.Tf("simple.errors", stats.ErrorCount, formatShare(stats.ErrorRate()))
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
 			return err
 		}
 	}
@@ -46,12 +66,13 @@ func (f *simpleFormatter) FormatGroupedStats(w io.Writer, grouped map[string]agg
 // FormatTopSessions implements Formatter.FormatTopSessions.
 func (f *simpleFormatter) FormatTopSessions(w io.Writer, sessions []aggregator.SessionStats) error {
 	for i, session := range sessions {
-		if _, err := fmt.Fprintf(w, "#%d: %s (%s) - %s tokens in %d entries\n",
+		if _, err := fmt.Fprintln(w, i18n.Tf("simple.top",
 			i+1,
 			session.SessionID,
 			session.Model,
 			formatNumber(session.Statistics.TotalTokens),
-			session.Statistics.Count); err != nil {
+			formatShare(session.Share),
+			session.Statistics.Count)); err != nil {
 			return err
 		}
 	}
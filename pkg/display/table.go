@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/0xmhha/token-monitor/pkg/aggregator"
+	"github.com/0xmhha/token-monitor/pkg/i18n"
 )
 
 // tableFormatter formats output as tables.
@@ -15,37 +16,69 @@ type tableFormatter struct {
 
 // FormatStats implements Formatter.FormatStats.
 func (f *tableFormatter) FormatStats(w io.Writer, stats aggregator.Statistics) error {
-	if err := writeHeader(w, "Token Usage Statistics", f.config.Compact); err != nil {
+	if err := f.writeHeader(w, i18n.T("stats.title")); err != nil {
 		return err
 	}
 
 	rows := [][]string{
-		{"Entries", formatNumber(stats.Count)},
-		{"Sessions", formatNumber(stats.SessionCount)},
-		{"Total Tokens", formatNumber(stats.TotalTokens)},
-		{"Input Tokens", formatNumber(stats.InputTokens)},
-		{"Output Tokens", formatNumber(stats.OutputTokens)},
-		{"Average Tokens", formatFloat(stats.AvgTokens, 2)},
-		{"Min Tokens", formatNumber(stats.MinTokens)},
-		{"Max Tokens", formatNumber(stats.MaxTokens)},
+		{i18n.T("stats.entries"), formatNumber(stats.Count)},
+		{i18n.T("stats.sessions"), formatNumber(stats.SessionCount)},
 	}
 
-	if f.config.ShowPercentiles {
+	costRow := []string{i18n.T("stats.cost"), formatCost(stats.CostUSD)}
+	if f.config.Units == UnitsCost {
+		rows = append(rows, costRow)
+	}
+
+	if f.showTokens() {
+		rows = append(rows,
+			[]string{i18n.T("stats.total_tokens"), f.tokens(stats.TotalTokens)},
+			[]string{i18n.T("stats.input_tokens"), f.tokens(stats.InputTokens)},
+			[]string{i18n.T("stats.output_tokens"), f.tokens(stats.OutputTokens)},
+		)
+		if stats.SubAgentTokens > 0 {
+			rows = append(rows, []string{i18n.T("stats.subagent_tokens"), fmt.Sprintf("%s (%s)",
+				f.tokens(stats.SubAgentTokens),
+				formatShare(aggregator.PercentOf(float64(stats.SubAgentTokens), float64(stats.TotalTokens))))})
+		}
 		rows = append(rows,
-			[]string{"P50 Tokens", formatNumber(stats.P50Tokens)},
-			[]string{"P95 Tokens", formatNumber(stats.P95Tokens)},
-			[]string{"P99 Tokens", formatNumber(stats.P99Tokens)},
+			[]string{i18n.T("stats.avg_tokens"), f.avgTokens(stats.AvgTokens, 2)},
+			[]string{i18n.T("stats.min_tokens"), f.tokens(stats.MinTokens)},
+			[]string{i18n.T("stats.max_tokens"), f.tokens(stats.MaxTokens)},
 		)
+
+		if f.config.ShowPercentiles {
+			rows = append(rows,
+				[]string{i18n.T("stats.p50_tokens"), f.tokens(stats.P50Tokens)},
+				[]string{i18n.T("stats.p95_tokens"), f.tokens(stats.P95Tokens)},
+				[]string{i18n.T("stats.p99_tokens"), f.tokens(stats.P99Tokens)},
+			)
+		}
+	}
+
+	if f.config.Units != UnitsCost && f.config.Detailed {
+		rows = append(rows, costRow)
+	}
+
+	if stats.CO2eGrams > 0 {
+		rows = append(rows, []string{i18n.T("stats.co2e"), FormatCO2e(stats.CO2eGrams)})
+	}
+
+	if stats.ErrorCount > 0 {
+		rows = append(rows, []string{i18n.T("stats.errors"), errorsValue(stats)})
 	}
 
 	if f.config.ShowTimestamps && !stats.FirstSeen.IsZero() {
 		rows = append(rows,
-			[]string{"First Seen", stats.FirstSeen.Format("2006-01-02 15:04:05")},
-			[]string{"Last Seen", stats.LastSeen.Format("2006-01-02 15:04:05")},
+			[]string{i18n.T("stats.first_seen"), stats.FirstSeen.Format("2006-01-02 15:04:05")},
+			[]string{i18n.T("stats.last_seen"), stats.LastSeen.Format("2006-01-02 15:04:05")},
 		)
 	}
 
-	return f.writeTable(w, []string{"Metric", "Value"}, rows)
+	if f.config.Accessible {
+		return f.writeLabels(w, rows)
+	}
+	return f.writeTable(w, []string{i18n.T("stats.metric"), i18n.T("stats.value")}, rows)
 }
 
 // FormatGroupedStats implements Formatter.FormatGroupedStats.
@@ -54,24 +87,63 @@ func (f *tableFormatter) FormatGroupedStats(w io.Writer, grouped map[string]aggr
 		return err
 	}
 
-	if err := writeHeader(w, "Grouped Statistics", f.config.Compact); err != nil {
+	if err := f.writeHeader(w, i18n.T("grouped.title")); err != nil {
 		return err
 	}
 
+	totalTokens, totalCost := groupTotals(grouped)
+
+	tokenColumns := func(stats aggregator.Statistics) []string {
+		return []string{
+			f.tokens(stats.TotalTokens),
+			formatShare(aggregator.PercentOf(float64(stats.TotalTokens), float64(totalTokens))),
+			f.tokens(stats.InputTokens),
+			f.tokens(stats.OutputTokens),
+			f.avgTokens(stats.AvgTokens, 1),
+			fmt.Sprintf("%s/%s", f.tokens(stats.MinTokens), f.tokens(stats.MaxTokens)),
+		}
+	}
+	costColumns := func(stats aggregator.Statistics) []string {
+		return []string{
+			formatCost(stats.CostUSD),
+			formatShare(aggregator.PercentOf(stats.CostUSD, totalCost)),
+		}
+	}
+	tokenHeader := []string{
+		i18n.T("col.total"),
+		i18n.T("col.share"),
+		i18n.T("col.input"),
+		i18n.T("col.output"),
+		i18n.T("col.avg"),
+		i18n.T("col.min_max"),
+	}
+	costHeader := []string{i18n.T("col.cost"), i18n.T("col.cost_share")}
+
 	// Build header.
-	header := make([]string, len(dimensions)+6)
-	copy(header, dimensions)
-	header[len(dimensions)] = "Entries"
-	header[len(dimensions)+1] = "Total"
-	header[len(dimensions)+2] = "Input"
-	header[len(dimensions)+3] = "Output"
-	header[len(dimensions)+4] = "Avg"
-	header[len(dimensions)+5] = "Min/Max"
+	header := make([]string, 0, len(dimensions)+10)
+	header = append(header, dimensions...)
+	header = append(header, i18n.T("col.entries"))
+	if f.config.Units == UnitsCost {
+		header = append(header, costHeader...)
+	}
+	if f.showTokens() {
+		header = append(header, tokenHeader...)
+	}
+	if f.showTrailingCost() {
+		header = append(header, costHeader...)
+	}
+	if f.config.ShowRate {
+		header = append(header, i18n.T("col.req_day"))
+	}
+	showErrors := groupErrors(grouped) > 0
+	if showErrors {
+		header = append(header, i18n.T("col.errors"))
+	}
 
 	// Build rows.
 	rows := make([][]string, 0, len(grouped))
 	for key, stats := range grouped {
-		row := make([]string, len(header))
+		row := make([]string, len(dimensions), len(header))
 
 		// Parse key into dimension values.
 		parts := strings.Split(key, "|")
@@ -82,14 +154,22 @@ func (f *tableFormatter) FormatGroupedStats(w io.Writer, grouped map[string]aggr
 		}
 
 		// Add statistics.
-		row[len(dimensions)] = formatNumber(stats.Count)
-		row[len(dimensions)+1] = formatNumber(stats.TotalTokens)
-		row[len(dimensions)+2] = formatNumber(stats.InputTokens)
-		row[len(dimensions)+3] = formatNumber(stats.OutputTokens)
-		row[len(dimensions)+4] = formatFloat(stats.AvgTokens, 1)
-		row[len(dimensions)+5] = fmt.Sprintf("%s/%s",
-			formatNumber(stats.MinTokens),
-			formatNumber(stats.MaxTokens))
+		row = append(row, formatNumber(stats.Count))
+		if f.config.Units == UnitsCost {
+			row = append(row, costColumns(stats)...)
+		}
+		if f.showTokens() {
+			row = append(row, tokenColumns(stats)...)
+		}
+		if f.showTrailingCost() {
+			row = append(row, costColumns(stats)...)
+		}
+		if f.config.ShowRate {
+			row = append(row, formatFloat(stats.RequestsPerDay(), 1))
+		}
+		if showErrors {
+			row = append(row, errorsValue(stats))
+		}
 
 		rows = append(rows, row)
 	}
@@ -99,46 +179,117 @@ func (f *tableFormatter) FormatGroupedStats(w io.Writer, grouped map[string]aggr
 
 // FormatTopSessions implements Formatter.FormatTopSessions.
 func (f *tableFormatter) FormatTopSessions(w io.Writer, sessions []aggregator.SessionStats) error {
-	if err := writeHeader(w, "Top Sessions by Token Usage", f.config.Compact); err != nil {
+	if err := f.writeHeader(w, i18n.T("top.title")); err != nil {
 		return err
 	}
 
-	header := []string{"Rank", "Session ID", "Model", "Entries", "Total Tokens", "Input", "Output", "Avg"}
+	header := []string{
+		i18n.T("col.rank"),
+		i18n.T("col.session_id"),
+		i18n.T("col.model"),
+		i18n.T("col.entries"),
+	}
+	costHeader := []string{i18n.T("col.cost"), i18n.T("col.cost_share")}
+	if f.config.Units == UnitsCost {
+		header = append(header, costHeader...)
+	}
+	if f.showTokens() {
+		header = append(header,
+			i18n.T("col.total_tokens"),
+			i18n.T("col.share"),
+			i18n.T("col.input"),
+			i18n.T("col.output"),
+			i18n.T("col.avg"),
+		)
+	}
+	if f.config.Units != UnitsCost && f.config.Detailed {
+		header = append(header, costHeader...)
+	}
 
 	rows := make([][]string, len(sessions))
 	for i, session := range sessions {
-		rows[i] = []string{
+		stats := session.Statistics
+		costColumns := []string{formatCost(stats.CostUSD), formatShare(session.CostShare)}
+
+		row := []string{
 			fmt.Sprintf("#%d", i+1),
 			session.SessionID,
 			session.Model,
-			formatNumber(session.Statistics.Count),
-			formatNumber(session.Statistics.TotalTokens),
-			formatNumber(session.Statistics.InputTokens),
-			formatNumber(session.Statistics.OutputTokens),
-			formatFloat(session.Statistics.AvgTokens, 1),
+			formatNumber(stats.Count),
 		}
+		if f.config.Units == UnitsCost {
+			row = append(row, costColumns...)
+		}
+		if f.showTokens() {
+			row = append(row,
+				f.tokens(stats.TotalTokens),
+				formatShare(session.Share),
+				f.tokens(stats.InputTokens),
+				f.tokens(stats.OutputTokens),
+				f.avgTokens(stats.AvgTokens, 1),
+			)
+		}
+		if f.config.Units != UnitsCost && f.config.Detailed {
+			row = append(row, costColumns...)
+		}
+		rows[i] = row
 	}
 
 	return f.writeTable(w, header, rows)
 }
 
+// showTokens reports whether token columns are shown: always, unless cost
+// is the primary measure and detailed output is off.
+func (f *tableFormatter) showTokens() bool {
+	return f.config.Units != UnitsCost || f.config.Detailed
+}
+
+// showTrailingCost reports whether cost columns follow the token columns,
+// which happens for token units with -cost or detailed output.
+func (f *tableFormatter) showTrailingCost() bool {
+	return f.config.Units != UnitsCost && (f.config.ShowCost || f.config.Detailed)
+}
+
+// tokens formats a token count in the configured units.
+func (f *tableFormatter) tokens(n int) string {
+	return FormatTokens(n, f.config.Units)
+}
+
+// avgTokens formats an average token count in the configured units.
+func (f *tableFormatter) avgTokens(avg float64, precision int) string {
+	if f.config.Units == UnitsThousands {
+		return formatFloat(avg/1000, precision) + "K"
+	}
+	return formatFloat(avg, precision)
+}
+
+// writeHeader writes a section header. Accessible output omits the
+// underline, which screen readers would read out character by character.
+func (f *tableFormatter) writeHeader(w io.Writer, title string) error {
+	return writeHeader(w, title, f.config.Compact || f.config.Accessible)
+}
+
 // writeTable writes a formatted table.
 func (f *tableFormatter) writeTable(w io.Writer, header []string, rows [][]string) error {
 	if len(rows) == 0 {
-		_, err := fmt.Fprintln(w, "No data")
+		_, err := fmt.Fprintln(w, i18n.T("table.no_data"))
 		return err
 	}
 
+	if f.config.Accessible {
+		return f.writeRecords(w, header, rows)
+	}
+
 	// Calculate column widths.
 	widths := make([]int, len(header))
 	for i, h := range header {
-		widths[i] = len(h)
+		widths[i] = textWidth(h)
 	}
 
 	for _, row := range rows {
 		for i, cell := range row {
-			if i < len(widths) && len(cell) > widths[i] {
-				widths[i] = len(cell)
+			if i < len(widths) && textWidth(cell) > widths[i] {
+				widths[i] = textWidth(cell)
 			}
 		}
 	}
@@ -190,8 +341,7 @@ func (f *tableFormatter) writeRow(w io.Writer, cells []string, widths []int) err
 			}
 		}
 
-		format := fmt.Sprintf("%%-%ds", widths[i])
-		if _, err := fmt.Fprintf(w, format, cell); err != nil {
+		if _, err := fmt.Fprint(w, padRight(cell, widths[i])); err != nil {
 			return err
 		}
 	}
@@ -199,3 +349,37 @@ func (f *tableFormatter) writeRow(w io.Writer, cells []string, widths []int) err
 	_, err := fmt.Fprintln(w)
 	return err
 }
+
+// writeLabels writes label/value pairs one per line, as "label: value".
+func (f *tableFormatter) writeLabels(w io.Writer, rows [][]string) error {
+	for _, row := range rows {
+		if _, err := fmt.Fprintf(w, "%s: %s\n", row[0], row[1]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeRecords writes each row on its own line with every cell labeled by
+// its column, as "column: value, column: value". Empty cells are skipped.
+func (f *tableFormatter) writeRecords(w io.Writer, header []string, rows [][]string) error {
+	for _, row := range rows {
+		fields := make([]string, 0, len(row))
+		for i, cell := range row {
+			if i < len(header) && cell != "" {
+				fields = append(fields, header[i]+": "+cell)
+			}
+		}
+		if _, err := fmt.Fprintln(w, strings.Join(fields, ", ")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteTable writes rows under header using the standard table layout.
+// It is used for ad-hoc result sets such as query output.
+func WriteTable(w io.Writer, header []string, rows [][]string, compact bool) error {
+	f := &tableFormatter{config: Config{Format: FormatTable, Compact: compact}}
+	return f.writeTable(w, header, rows)
+}
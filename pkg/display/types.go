@@ -5,6 +5,7 @@
 package display
 
 import (
+	"fmt"
 	"io"
 
 	"github.com/0xmhha/token-monitor/pkg/aggregator"
@@ -24,6 +25,33 @@ const (
 	FormatSimple Format = "simple"
 )
 
+// Units selects the primary measure shown in tables.
+type Units string
+
+const (
+	// UnitsTokens shows raw token counts.
+	UnitsTokens Units = "tokens"
+
+	// UnitsThousands shows token counts in thousands (e.g. "1,234.5K").
+	UnitsThousands Units = "k"
+
+	// UnitsCost shows estimated USD cost, with token columns only in
+	// detailed output.
+	UnitsCost Units = "cost"
+)
+
+// ParseUnits returns the units named by s. An empty string is UnitsTokens.
+func ParseUnits(s string) (Units, error) {
+	switch u := Units(s); u {
+	case "":
+		return UnitsTokens, nil
+	case UnitsTokens, UnitsThousands, UnitsCost:
+		return u, nil
+	default:
+		return "", fmt.Errorf("invalid units: %s (must be tokens, k, or cost)", s)
+	}
+}
+
 // Formatter formats and displays token statistics.
 type Formatter interface {
 	// FormatStats formats overall statistics.
@@ -72,4 +100,28 @@ type Config struct {
 	// Compact enables compact output (less whitespace).
 	// Default: false.
 	Compact bool
+
+	// ShowCost adds estimated cost to grouped statistics.
+	// Default: false.
+	ShowCost bool
+
+	// ShowRate adds requests per day to grouped statistics.
+	// Default: false.
+	ShowRate bool
+
+	// Units selects the primary measure in table output. Token counts
+	// are scaled for UnitsThousands; UnitsCost leads with cost columns.
+	// JSON and simple output always use raw tokens.
+	// Default: UnitsTokens.
+	Units Units
+
+	// Detailed adds the measures that Units does not show: cost columns
+	// for token units, token columns for cost units.
+	// Default: false.
+	Detailed bool
+
+	// Accessible replaces table layout with labeled lines that read well
+	// in a screen reader ("Total Tokens: 1,234"), and drops underlines.
+	// Default: false.
+	Accessible bool
 }
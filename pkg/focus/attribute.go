@@ -0,0 +1,77 @@
+package focus
+
+import (
+	"sort"
+	"time"
+
+	"github.com/0xmhha/token-monitor/pkg/parser"
+	"github.com/0xmhha/token-monitor/pkg/rollup"
+)
+
+// Usage is the usage attributed to one label.
+type Usage struct {
+	// Label is the task label.
+	Label string `json:"label"`
+
+	// Windows is the number of windows with this label.
+	Windows int `json:"windows"`
+
+	// Duration is the total length of those windows.
+	Duration time.Duration `json:"duration_ns"`
+
+	// Sessions is the number of distinct Claude sessions with usage in
+	// the windows.
+	Sessions int `json:"sessions"`
+
+	// Totals sums the entries in the windows.
+	Totals rollup.Totals `json:"totals"`
+}
+
+// Attribute sums entries per window label. windows must not overlap,
+// which the store guarantees; entries outside every window are left out.
+// now is the end of open windows for Duration. The result is ordered by
+// cost, highest first.
+func Attribute(windows []Window, entries []parser.UsageEntry, now time.Time) []Usage {
+	sorted := append([]Window(nil), windows...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Start.Before(sorted[j].Start) })
+
+	byLabel := make(map[string]*Usage)
+	sessions := make(map[string]map[string]bool)
+	for _, w := range sorted {
+		u := byLabel[w.Label]
+		if u == nil {
+			u = &Usage{Label: w.Label}
+			byLabel[w.Label] = u
+			sessions[w.Label] = make(map[string]bool)
+		}
+		u.Windows++
+		u.Duration += w.Duration(now)
+	}
+
+	for _, entry := range entries {
+		// The window that started last at or before the entry is the only
+		// candidate.
+		i := sort.Search(len(sorted), func(i int) bool {
+			return sorted[i].Start.After(entry.Timestamp)
+		}) - 1
+		if i < 0 || !sorted[i].Contains(entry.Timestamp) {
+			continue
+		}
+		label := sorted[i].Label
+		byLabel[label].Totals.AddEntry(entry)
+		sessions[label][entry.SessionID] = true
+	}
+
+	result := make([]Usage, 0, len(byLabel))
+	for label, u := range byLabel {
+		u.Sessions = len(sessions[label])
+		result = append(result, *u)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Totals.CostUSD != result[j].Totals.CostUSD {
+			return result[i].Totals.CostUSD > result[j].Totals.CostUSD
+		}
+		return result[i].Label < result[j].Label
+	})
+	return result
+}
@@ -0,0 +1,113 @@
+package focus
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/0xmhha/token-monitor/pkg/parser"
+)
+
+func newTestStore(t *testing.T) Store {
+	t.Helper()
+	db, err := bolt.Open(filepath.Join(t.TempDir(), "test.db"), 0600, nil)
+	if err != nil {
+		t.Fatalf("bolt.Open() error = %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	store, err := New(db)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return store
+}
+
+func TestStore(t *testing.T) {
+	store := newTestStore(t)
+	base := time.Date(2025, 11, 30, 9, 0, 0, 0, time.UTC)
+
+	if _, err := store.Stop(base); !errors.Is(err, ErrNotActive) {
+		t.Errorf("Stop() with nothing open error = %v, want ErrNotActive", err)
+	}
+	if _, err := store.Start(Window{Label: "bad label", Start: base}); !errors.Is(err, ErrInvalidLabel) {
+		t.Errorf("Start() bad label error = %v, want ErrInvalidLabel", err)
+	}
+
+	if _, err := store.Start(Window{Label: "feature-x", Start: base}); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if _, err := store.Start(Window{Label: "feature-y", Start: base.Add(time.Minute)}); !errors.Is(err, ErrActive) {
+		t.Errorf("Start() while active error = %v, want ErrActive", err)
+	}
+	active, ok, err := store.Active()
+	if err != nil || !ok || active.Label != "feature-x" {
+		t.Errorf("Active() = %+v, %v, %v; want feature-x", active, ok, err)
+	}
+
+	w, err := store.Stop(base.Add(time.Hour))
+	if err != nil || w.Label != "feature-x" || w.Duration(time.Time{}) != time.Hour {
+		t.Fatalf("Stop() = %+v, %v; want one hour of feature-x", w, err)
+	}
+	if _, ok, _ := store.Active(); ok {
+		t.Error("Active() after Stop() reports an open window")
+	}
+	if _, err := store.Start(Window{Label: "feature-y", Start: base.Add(30 * time.Minute)}); err == nil {
+		t.Error("Start() overlapping the previous window succeeded")
+	}
+	if _, err := store.Start(Window{Label: "feature-y", Start: base.Add(2 * time.Hour)}); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	all, err := store.List(time.Time{}, time.Time{})
+	if err != nil || len(all) != 2 || all[0].Label != "feature-x" || !all[1].Active() {
+		t.Errorf("List() = %+v, %v; want feature-x then open feature-y", all, err)
+	}
+	later, err := store.List(base.Add(90*time.Minute), time.Time{})
+	if err != nil || len(later) != 1 || later[0].Label != "feature-y" {
+		t.Errorf("List(from) = %+v, %v; want feature-y", later, err)
+	}
+	earlier, err := store.List(time.Time{}, base.Add(90*time.Minute))
+	if err != nil || len(earlier) != 1 || earlier[0].Label != "feature-x" {
+		t.Errorf("List(to) = %+v, %v; want feature-x", earlier, err)
+	}
+}
+
+func TestAttribute(t *testing.T) {
+	base := time.Date(2025, 11, 30, 9, 0, 0, 0, time.UTC)
+	windows := []Window{
+		{Label: "review", Start: base.Add(3 * time.Hour)},
+		{Label: "feature-x", Start: base, End: base.Add(time.Hour)},
+		{Label: "feature-x", Start: base.Add(2 * time.Hour), End: base.Add(150 * time.Minute)},
+	}
+	entry := func(session string, offset time.Duration, tokens int) parser.UsageEntry {
+		return parser.UsageEntry{
+			Timestamp: base.Add(offset),
+			SessionID: session,
+			Message:   parser.Message{Model: "claude-sonnet-4", Usage: parser.Usage{InputTokens: tokens}},
+		}
+	}
+	entries := []parser.UsageEntry{
+		entry("a", -time.Minute, 1),      // before any window
+		entry("a", 30*time.Minute, 10),   // feature-x
+		entry("b", 90*time.Minute, 100),  // between windows
+		entry("b", 2*time.Hour, 20),      // feature-x, at the start
+		entry("c", 5*time.Hour, 1000000), // review, still open
+	}
+
+	got := Attribute(windows, entries, base.Add(4*time.Hour))
+	if len(got) != 2 {
+		t.Fatalf("Attribute() = %+v, want 2 labels", got)
+	}
+	review, feature := got[0], got[1]
+	if review.Label != "review" || review.Totals.InputTokens != 1000000 || review.Duration != time.Hour {
+		t.Errorf("review = %+v", review)
+	}
+	if feature.Label != "feature-x" || feature.Windows != 2 || feature.Sessions != 2 ||
+		feature.Totals.InputTokens != 30 || feature.Duration != 90*time.Minute {
+		t.Errorf("feature-x = %+v", feature)
+	}
+}
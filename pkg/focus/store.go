@@ -0,0 +1,184 @@
+package focus
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// bucketFocus maps window start times (big-endian Unix nanoseconds) to
+// JSON-encoded Windows, so keys iterate in start order.
+var bucketFocus = []byte("focus")
+
+// boltStore implements Store using BoltDB.
+type boltStore struct {
+	db *bolt.DB
+}
+
+// New creates a BoltDB-backed focus store.
+//
+// Parameters:
+//   - db: BoltDB database instance (typically session.Manager.DB())
+//
+// Returns:
+//   - Configured Store
+//   - Error if bucket initialization fails
+func New(db *bolt.DB) (Store, error) {
+	if err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(bucketFocus)
+		return err
+	}); err != nil {
+		return nil, fmt.Errorf("failed to create focus bucket: %w", err)
+	}
+
+	return &boltStore{db: db}, nil
+}
+
+// Start implements Store.Start.
+func (s *boltStore) Start(w Window) (Window, error) {
+	if err := ValidateLabel(w.Label); err != nil {
+		return Window{}, fmt.Errorf("%w: %q", err, w.Label)
+	}
+
+	w.End = time.Time{}
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(bucketFocus)
+		if active, ok, err := lastWindow(bucket); err != nil {
+			return err
+		} else if ok && active.Active() {
+			return fmt.Errorf("%w: %s since %s", ErrActive, active.Label, active.Start.Local().Format("15:04"))
+		} else if ok && w.Start.Before(active.End) {
+			return fmt.Errorf("focus window would start before the previous one ended at %s", active.End.Local().Format(time.DateTime))
+		}
+		return putWindow(bucket, w)
+	})
+	return w, err
+}
+
+// Stop implements Store.Stop.
+func (s *boltStore) Stop(at time.Time) (Window, error) {
+	var w Window
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(bucketFocus)
+		active, ok, err := lastWindow(bucket)
+		if err != nil {
+			return err
+		}
+		if !ok || !active.Active() {
+			return ErrNotActive
+		}
+		if at.Before(active.Start) {
+			at = active.Start
+		}
+		active.End = at
+		w = active
+		return putWindow(bucket, active)
+	})
+	return w, err
+}
+
+// Active implements Store.Active.
+func (s *boltStore) Active() (Window, bool, error) {
+	var w Window
+	var ok bool
+	err := s.db.View(func(tx *bolt.Tx) error {
+		var err error
+		w, ok, err = lastWindow(tx.Bucket(bucketFocus))
+		ok = ok && w.Active()
+		return err
+	})
+	if !ok {
+		w = Window{}
+	}
+	return w, ok, err
+}
+
+// List implements Store.List.
+func (s *boltStore) List(from, to time.Time) ([]Window, error) {
+	var list []Window
+	err := s.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(bucketFocus).Cursor()
+		for k, v := c.First(); k != nil; k, v = c.Next() {
+			w, err := decodeWindow(k, v)
+			if err != nil {
+				return err
+			}
+			if !to.IsZero() && w.Start.After(to) {
+				break
+			}
+			if !from.IsZero() && !w.Active() && w.End.Before(from) {
+				continue
+			}
+			list = append(list, w)
+		}
+		return nil
+	})
+	return list, err
+}
+
+// Prune implements Store.Prune.
+func (s *boltStore) Prune(before time.Time) (int, error) {
+	removed := 0
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(bucketFocus)
+		var stale [][]byte
+		c := bucket.Cursor()
+		// Windows end after they start, so none past before qualifies.
+		for k, v := c.First(); k != nil && bytes.Compare(k, windowKey(before)) < 0; k, v = c.Next() {
+			w, err := decodeWindow(k, v)
+			if err != nil {
+				return err
+			}
+			if !w.Active() && w.End.Before(before) {
+				stale = append(stale, bytes.Clone(k))
+			}
+		}
+		for _, k := range stale {
+			if err := bucket.Delete(k); err != nil {
+				return fmt.Errorf("failed to delete focus window: %w", err)
+			}
+		}
+		removed = len(stale)
+		return nil
+	})
+	return removed, err
+}
+
+// lastWindow returns the most recently started window.
+func lastWindow(bucket *bolt.Bucket) (Window, bool, error) {
+	k, v := bucket.Cursor().Last()
+	if k == nil {
+		return Window{}, false, nil
+	}
+	w, err := decodeWindow(k, v)
+	return w, err == nil, err
+}
+
+// putWindow stores w under its start time.
+func putWindow(bucket *bolt.Bucket, w Window) error {
+	data, err := json.Marshal(w)
+	if err != nil {
+		return fmt.Errorf("failed to encode focus window: %w", err)
+	}
+	return bucket.Put(windowKey(w.Start), data)
+}
+
+// decodeWindow decodes the window stored under k.
+func decodeWindow(k, v []byte) (Window, error) {
+	var w Window
+	if err := json.Unmarshal(v, &w); err != nil {
+		return Window{}, fmt.Errorf("invalid focus window %x: %w", k, err)
+	}
+	return w, nil
+}
+
+// windowKey returns the key for a window starting at start.
+func windowKey(start time.Time) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, uint64(start.UnixNano())) //nolint:gosec // times before 1970 are not used
+	return key
+}
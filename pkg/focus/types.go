@@ -0,0 +1,120 @@
+// Package focus records labeled focus windows and attributes token usage
+// to them.
+//
+// A focus window is a user-declared period of work on one task, such as
+// "feature-x". Unlike Claude sessions, which end when the CLI exits,
+// windows follow the task: usage from every session that ran while a
+// window was open counts toward its label, so spend can be reported per
+// task.
+//
+// Example usage:
+//
+//	store, err := focus.New(db)
+//	if err != nil {
+//	    return err
+//	}
+//	if _, err := store.Start(focus.Window{Label: "feature-x", Start: time.Now()}); err != nil {
+//	    return err
+//	}
+//	// ... work ...
+//	w, err := store.Stop(time.Now())
+//
+//	windows, err := store.List(from, to)
+//	usage := focus.Attribute(windows, entries, time.Now())
+package focus
+
+import (
+	"errors"
+	"regexp"
+	"time"
+)
+
+// MaxLabelLength is the maximum length of a focus label.
+const MaxLabelLength = 64
+
+// labelPattern matches valid focus labels.
+var labelPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._/-]*$`)
+
+// Common errors returned by the focus store.
+var (
+	// ErrActive is returned when starting a window while another is open.
+	ErrActive = errors.New("focus window already active")
+
+	// ErrNotActive is returned when stopping with no open window.
+	ErrNotActive = errors.New("no active focus window")
+
+	// ErrInvalidLabel is returned when a label is empty, too long, or
+	// contains characters other than letters, digits, '.', '_', '/', and
+	// '-'.
+	ErrInvalidLabel = errors.New("invalid focus label")
+)
+
+// ValidateLabel checks that label is usable as a focus label.
+func ValidateLabel(label string) error {
+	if len(label) > MaxLabelLength || !labelPattern.MatchString(label) {
+		return ErrInvalidLabel
+	}
+	return nil
+}
+
+// Window is a labeled period of focused work.
+type Window struct {
+	// Label names the task.
+	Label string `json:"label"`
+
+	// Ticket is an optional issue tracker ticket for the task (see
+	// package ticket).
+	Ticket string `json:"ticket,omitempty"`
+
+	// Start is when the window was opened.
+	Start time.Time `json:"start"`
+
+	// End is when the window was closed. Zero while the window is open.
+	End time.Time `json:"end,omitempty"`
+}
+
+// Active reports whether the window is still open.
+func (w Window) Active() bool {
+	return w.End.IsZero()
+}
+
+// Duration returns the length of the window, up to now while it is open.
+func (w Window) Duration(now time.Time) time.Duration {
+	if w.Active() {
+		return now.Sub(w.Start)
+	}
+	return w.End.Sub(w.Start)
+}
+
+// Contains reports whether t falls in the window. An open window
+// contains every time after its start.
+func (w Window) Contains(t time.Time) bool {
+	if t.Before(w.Start) {
+		return false
+	}
+	return w.Active() || !t.After(w.End)
+}
+
+// Store persists focus windows. At most one window is open at a time.
+//
+// Thread-safety: Implementations must be safe for concurrent use.
+type Store interface {
+	// Start opens w at w.Start; w.End is ignored. It returns ErrActive
+	// if a window is already open.
+	Start(w Window) (Window, error)
+
+	// Stop closes the open window at at and returns it, or returns
+	// ErrNotActive.
+	Stop(at time.Time) (Window, error)
+
+	// Active returns the open window. ok is false when none is open.
+	Active() (w Window, ok bool, err error)
+
+	// List returns the windows overlapping [from, to], oldest first. A
+	// zero from or to leaves that side unbounded.
+	List(from, to time.Time) ([]Window, error)
+
+	// Prune removes the closed windows that ended before before and
+	// returns how many were removed. The open window is kept.
+	Prune(before time.Time) (int, error)
+}
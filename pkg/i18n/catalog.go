@@ -0,0 +1,384 @@
+package i18n
+
+// catalog maps each locale to its messages. English must define every
+// key; other locales may omit keys and fall back to English.
+//
+// Keys are grouped by where the message appears:
+//   - usage.*: the command list in help output
+//   - stats.*, grouped.*, top.*, col.*, table.*, simple.*: stats output
+//   - report.*, weekday.*: report table headers and weekday names
+//   - calendar.*: calendar heat map
+//   - today.*: today summary card
+//   - baseline.*: stats -against-baseline output
+//   - msg.*: one-line command messages
+var catalog = map[Locale]map[string]string{
+	English: {
+		"usage.title":         "Token Monitor - Claude Code CLI token usage monitoring tool",
+		"usage.usage":         "Usage:",
+		"usage.commands":      "Commands:",
+		"usage.tui":           "Interactive TUI dashboard (default when no command given)",
+		"usage.stats":         "Display token usage statistics",
+		"usage.list":          `List all discovered sessions (same as "session list -all -sort date")`,
+		"usage.watch":         "Live monitoring of token usage",
+		"usage.session":       "Session management (name, list, show, delete)",
+		"usage.project":       "Compare all sessions of a project side by side (compare)",
+		"usage.config":        "Configuration management (show, path, set, validate, reset, export-rules, import-rules)",
+		"usage.query":         "Fast single-metric token lookup (for hooks), or a query expression",
+		"usage.status":        "Compact status line output (for Claude Code status)",
+		"usage.serve":         "MCP server mode (for Claude Code MCP integration)",
+		"usage.hook-receiver": "Ingest usage pushed by a Claude Code hook (payload on stdin)",
+		"usage.notify":        "Post usage summaries and alerts to Discord (daily, test)",
+		"usage.annotate":      "Summarize tokens and cost per git commit for a PR",
+		"usage.install":       "Install token-monitor into Claude Code (statusline, mcp, hook)",
+		"usage.repl":          "Interactive prompt over a dataset loaded once (stats, list, reload)",
+		"usage.report":        "Daily/model/session totals from pre-aggregated rollups",
+		"usage.calendar":      "Monthly calendar heat map of daily token usage",
+		"usage.today":         "Today's tokens, cost, sessions, and billing block at a glance",
+		"usage.dump":          "Write every usage entry to one JSONL file for analysis (-output, -from, -to)",
+		"usage.history":       "Which dates are backed by session files vs. only by rollups (coverage)",
+		"usage.baseline":      "Saved stats snapshots for stats -against-baseline (save, list, delete)",
+		"usage.focus":         "Track spend per task with labeled focus windows (start, stop, report)",
+		"usage.tickets":       "Spend per ticket from session labels and focus windows (CSV export)",
+		"usage.team":          "Quota utilization and spend per team member under configured plans",
+		"usage.api":           "OpenAPI specification of the serve -http endpoints (spec)",
+		"usage.whatif":        "Historical cost as if requests had used another model (-map opus=sonnet)",
+		"usage.fsck":          "Check database integrity (names, file positions, rollups)",
+		"usage.health":        "Check that watch is running and ingesting, and probe serve -http",
+		"usage.debug":         "Diagnostics for bug reports (bundle)",
+		"usage.logs":          "Show or follow the log file (-n, -f, -level, -path)",
+		"usage.telemetry":     "Opt-in anonymous performance report (status, enable, disable)",
+		"usage.version":       "Show version and build metadata (-json)",
+		"usage.help":          "Show this help message",
+
+		"stats.title":           "Token Usage Statistics",
+		"stats.metric":          "Metric",
+		"stats.value":           "Value",
+		"stats.entries":         "Entries",
+		"stats.sessions":        "Sessions",
+		"stats.total_tokens":    "Total Tokens",
+		"stats.input_tokens":    "Input Tokens",
+		"stats.output_tokens":   "Output Tokens",
+		"stats.subagent_tokens": "Sub-agent Tokens",
+		"stats.errors":          "API Errors",
+		"stats.avg_tokens":      "Average Tokens",
+		"stats.min_tokens":      "Min Tokens",
+		"stats.max_tokens":      "Max Tokens",
+		"stats.p50_tokens":      "P50 Tokens",
+		"stats.p95_tokens":      "P95 Tokens",
+		"stats.p99_tokens":      "P99 Tokens",
+		"stats.first_seen":      "First Seen",
+		"stats.last_seen":       "Last Seen",
+		"stats.cost":            "Estimated Cost",
+		"stats.co2e":            "Estimated Footprint",
+
+		"grouped.title": "Grouped Statistics",
+		"top.title":     "Top Sessions by Token Usage",
+
+		"col.rank":         "Rank",
+		"col.session_id":   "Session ID",
+		"col.model":        "Model",
+		"col.entries":      "Entries",
+		"col.total":        "Total",
+		"col.total_tokens": "Total Tokens",
+		"col.share":        "Share",
+		"col.input":        "Input",
+		"col.output":       "Output",
+		"col.avg":          "Avg",
+		"col.min_max":      "Min/Max",
+		"col.cost":         "Cost",
+		"col.cost_share":   "Cost %",
+		"col.req_day":      "Req/Day",
+		"col.errors":       "Errors",
+		"col.baseline":     "Baseline",
+		"col.current":      "Current",
+		"col.change":       "Change",
+		"col.change_pct":   "Change %",
+
+		"table.no_data": "No data",
+
+		"simple.stats":   "Entries: %d | Sessions: %d | Total: %s | Avg: %s | Min: %s | Max: %s",
+		"simple.group":   "%s: %d entries, %s tokens, %s (avg: %s)",
+		"simple.req_day": ", %s req/day",
+		"simple.errors":  ", %d API errors (%s)",
+		"simple.top":     "#%d: %s (%s) - %s tokens (%s) in %d entries",
+
+		"report.date":        "DATE",
+		"report.model":       "MODEL",
+		"report.session":     "SESSION",
+		"report.entries":     "ENTRIES",
+		"report.input":       "INPUT",
+		"report.output":      "OUTPUT",
+		"report.cache":       "CACHE",
+		"report.total":       "TOTAL",
+		"report.cost":        "COST",
+		"report.co2e":        "CO2E",
+		"report.weekday":     "WEEKDAY",
+		"report.active_days": "ACTIVE",
+		"report.avg_entries": "AVG ENTRIES",
+		"report.avg_total":   "AVG TOKENS",
+		"report.avg_cost":    "AVG COST",
+		"report.cache_saved": "Prompt caching saved you %s (%s without caching)",
+
+		"weekday.monday":    "Monday",
+		"weekday.tuesday":   "Tuesday",
+		"weekday.wednesday": "Wednesday",
+		"weekday.thursday":  "Thursday",
+		"weekday.friday":    "Friday",
+		"weekday.saturday":  "Saturday",
+		"weekday.sunday":    "Sunday",
+
+		"calendar.summary":  "%s: %s tokens, %s, %d active day(s)",
+		"calendar.busiest":  "Busiest day: %s (%s tokens, %s)",
+		"calendar.weekdays": "Mon Tue Wed Thu Fri Sat Sun",
+		"calendar.less":     "Less",
+		"calendar.more":     "More",
+
+		"today.title":          "Today",
+		"today.tokens":         "Tokens",
+		"today.cost":           "Cost",
+		"today.sessions":       "Sessions",
+		"today.block":          "Block",
+		"today.average":        "vs. average",
+		"today.breakdown":      "in %s · out %s · cache %s",
+		"today.block_active":   "%s tokens · %s left · %s/min",
+		"today.block_idle":     "no usage yet · %s left",
+		"today.rate_limits":    " · %d rate-limit event(s)",
+		"today.average_detail": "(%s tokens, %s per day over %d active day(s))",
+		"today.no_average":     "no daily average yet",
+		"today.context":        "Context",
+		"today.context_value":  "~%.0f%% full (%s of %s tokens)",
+		"today.context_warn":   "compaction near",
+
+		"history.from":       "FROM",
+		"history.to":         "TO",
+		"history.days":       "DAYS",
+		"history.coverage":   "COVERAGE",
+		"history.both":       "files + rollups",
+		"history.partial":    "partial files + rollups",
+		"history.store_only": "rollups only",
+		"history.raw_only":   "files only (not rolled up)",
+		"history.summary":    "%d day(s) with usage: %d backed by session files, %d only by rollups",
+		"history.empty":      "No usage history found",
+
+		"history.data":            "DATA",
+		"history.kept":            "KEPT",
+		"history.removed":         "REMOVED",
+		"history.would_remove":    "WOULD REMOVE",
+		"history.forever":         "forever",
+		"history.kept_days":       "%d day(s)",
+		"history.class_rollups":   "Rollup rows",
+		"history.class_baselines": "Baselines",
+		"history.class_focus":     "Focus windows",
+		"history.no_retention":    "No retention configured (retention.* in config); everything is kept",
+
+		"baseline.title":                 "Compared with baseline %s (saved %s, %s)",
+		"baseline.points":                "%+.1f pts",
+		"baseline.entries":               "Entries",
+		"baseline.total_tokens":          "Total Tokens",
+		"baseline.input_tokens":          "Input Tokens",
+		"baseline.output_tokens":         "Output Tokens",
+		"baseline.cache_creation_tokens": "Cache Write Tokens",
+		"baseline.cache_read_tokens":     "Cache Read Tokens",
+		"baseline.cost_usd":              "Estimated Cost",
+		"baseline.requests_per_day":      "Requests/Day",
+		"baseline.tokens_per_request":    "Tokens/Request",
+		"baseline.cost_per_request":      "Cost/Request",
+		"baseline.cost_per_day":          "Cost/Day",
+		"baseline.cache_read_share":      "Cache Read Share",
+
+		"msg.no_sessions":    "No sessions found",
+		"msg.session_total":  "Total: %d session(s)",
+		"msg.filters":        "Filters: %s",
+		"msg.no_problems":    "No problems found",
+		"msg.review_bundle":  "Review the bundle before attaching it to an issue.",
+		"msg.config_set":     "✓ Set %s = %s",
+		"msg.config_added":   "✓ Added to %s: %s",
+		"msg.config_removed": "✓ Removed from %s: %s",
+		"msg.config_saved":   "✓ Configuration saved to: %s",
+	},
+
+	Korean: {
+		"usage.title":         "Token Monitor - Claude Code CLI 토큰 사용량 모니터링 도구",
+		"usage.usage":         "사용법:",
+		"usage.commands":      "명령:",
+		"usage.tui":           "대화형 TUI 대시보드 (명령을 생략하면 기본 실행)",
+		"usage.stats":         "토큰 사용량 통계 표시",
+		"usage.list":          `발견된 모든 세션 목록 ("session list -all -sort date"와 동일)`,
+		"usage.watch":         "토큰 사용량 실시간 모니터링",
+		"usage.session":       "세션 관리 (name, list, show, delete)",
+		"usage.project":       "프로젝트의 모든 세션을 나란히 비교 (compare)",
+		"usage.config":        "설정 관리 (show, path, set, validate, reset, export-rules, import-rules)",
+		"usage.query":         "단일 지표 빠른 조회 (훅용) 또는 쿼리 표현식",
+		"usage.status":        "간결한 상태 줄 출력 (Claude Code 상태 표시용)",
+		"usage.serve":         "MCP 서버 모드 (Claude Code MCP 연동용)",
+		"usage.hook-receiver": "Claude Code 훅이 보낸 사용량 수집 (stdin으로 페이로드 전달)",
+		"usage.notify":        "사용량 요약과 알림을 Discord로 전송 (daily, test)",
+		"usage.annotate":      "git 커밋별 토큰과 비용을 PR용으로 요약",
+		"usage.install":       "Claude Code에 token-monitor 설치 (statusline, mcp, hook)",
+		"usage.repl":          "한 번 불러온 데이터에 대한 대화형 프롬프트 (stats, list, reload)",
+		"usage.report":        "미리 집계된 롤업 기반 일별/모델별/세션별 합계",
+		"usage.calendar":      "일별 토큰 사용량 월간 달력 히트맵",
+		"usage.today":         "오늘의 토큰, 비용, 세션, 과금 블록 요약",
+		"usage.dump":          "분석용으로 모든 사용량 항목을 하나의 JSONL 파일로 출력 (-output, -from, -to)",
+		"usage.history":       "세션 파일과 롤업 중 어디에 기록이 남아 있는지 날짜별로 표시 (coverage)",
+		"usage.baseline":      "stats -against-baseline용 통계 스냅샷 저장 (save, list, delete)",
+		"usage.focus":         "라벨을 붙인 집중 구간으로 작업별 사용량 추적 (start, stop, report)",
+		"usage.tickets":       "세션 라벨과 집중 구간 기준 티켓별 사용량 (CSV 내보내기)",
+		"usage.team":          "설정한 요금제 기준 팀원별 한도 사용률과 지출",
+		"usage.api":           "serve -http 엔드포인트의 OpenAPI 명세 (spec)",
+		"usage.whatif":        "다른 모델을 썼다면 들었을 과거 비용 (-map opus=sonnet)",
+		"usage.fsck":          "데이터베이스 무결성 검사 (이름, 파일 위치, 롤업)",
+		"usage.health":        "watch 실행 및 수집 상태 확인, serve -http 점검",
+		"usage.debug":         "버그 리포트용 진단 정보 (bundle)",
+		"usage.logs":          "로그 파일 보기 및 따라가기 (-n, -f, -level, -path)",
+		"usage.telemetry":     "선택형 익명 성능 보고 (status, enable, disable)",
+		"usage.version":       "버전 및 빌드 정보 표시 (-json)",
+		"usage.help":          "이 도움말 표시",
+
+		"stats.title":           "토큰 사용량 통계",
+		"stats.metric":          "지표",
+		"stats.value":           "값",
+		"stats.entries":         "항목 수",
+		"stats.sessions":        "세션 수",
+		"stats.total_tokens":    "총 토큰",
+		"stats.input_tokens":    "입력 토큰",
+		"stats.output_tokens":   "출력 토큰",
+		"stats.subagent_tokens": "서브 에이전트 토큰",
+		"stats.errors":          "API 오류",
+		"stats.avg_tokens":      "평균 토큰",
+		"stats.min_tokens":      "최소 토큰",
+		"stats.max_tokens":      "최대 토큰",
+		"stats.p50_tokens":      "P50 토큰",
+		"stats.p95_tokens":      "P95 토큰",
+		"stats.p99_tokens":      "P99 토큰",
+		"stats.first_seen":      "처음 기록",
+		"stats.last_seen":       "마지막 기록",
+		"stats.cost":            "예상 비용",
+		"stats.co2e":            "예상 탄소 배출",
+
+		"grouped.title": "그룹별 통계",
+		"top.title":     "토큰 사용량 상위 세션",
+
+		"col.rank":         "순위",
+		"col.session_id":   "세션 ID",
+		"col.model":        "모델",
+		"col.entries":      "항목 수",
+		"col.total":        "합계",
+		"col.total_tokens": "총 토큰",
+		"col.share":        "비율",
+		"col.input":        "입력",
+		"col.output":       "출력",
+		"col.avg":          "평균",
+		"col.min_max":      "최소/최대",
+		"col.cost":         "비용",
+		"col.cost_share":   "비용 %",
+		"col.req_day":      "일일 요청",
+		"col.errors":       "오류",
+		"col.baseline":     "기준",
+		"col.current":      "현재",
+		"col.change":       "변화",
+		"col.change_pct":   "변화 %",
+
+		"table.no_data": "데이터 없음",
+
+		"simple.stats":   "항목 수: %d | 세션 수: %d | 합계: %s | 평균: %s | 최소: %s | 최대: %s",
+		"simple.group":   "%s: 항목 %d개, 토큰 %s, %s (평균: %s)",
+		"simple.req_day": ", 하루 %s회 요청",
+		"simple.errors":  ", API 오류 %d개 (%s)",
+		"simple.top":     "#%d: %s (%s) - 토큰 %s (%s), 항목 %d개",
+
+		"report.date":        "날짜",
+		"report.model":       "모델",
+		"report.session":     "세션",
+		"report.entries":     "항목 수",
+		"report.input":       "입력",
+		"report.output":      "출력",
+		"report.cache":       "캐시",
+		"report.total":       "합계",
+		"report.cost":        "비용",
+		"report.co2e":        "탄소 배출",
+		"report.weekday":     "요일",
+		"report.active_days": "사용일",
+		"report.avg_entries": "평균 항목 수",
+		"report.avg_total":   "평균 토큰",
+		"report.avg_cost":    "평균 비용",
+		"report.cache_saved": "프롬프트 캐싱으로 %s 절약 (캐싱 없이 %s)",
+
+		"weekday.monday":    "월요일",
+		"weekday.tuesday":   "화요일",
+		"weekday.wednesday": "수요일",
+		"weekday.thursday":  "목요일",
+		"weekday.friday":    "금요일",
+		"weekday.saturday":  "토요일",
+		"weekday.sunday":    "일요일",
+
+		"calendar.summary":  "%s: 토큰 %s, %s, 사용일 %d일",
+		"calendar.busiest":  "가장 많이 사용한 날: %s (토큰 %s, %s)",
+		"calendar.weekdays": "월 화 수 목 금 토 일",
+		"calendar.less":     "적음",
+		"calendar.more":     "많음",
+
+		"today.title":          "오늘",
+		"today.tokens":         "토큰",
+		"today.cost":           "비용",
+		"today.sessions":       "세션",
+		"today.block":          "블록",
+		"today.average":        "평균 대비",
+		"today.breakdown":      "입력 %s · 출력 %s · 캐시 %s",
+		"today.block_active":   "토큰 %s · %s 남음 · 분당 %s",
+		"today.block_idle":     "아직 사용 없음 · %s 남음",
+		"today.rate_limits":    " · 속도 제한 %d회",
+		"today.average_detail": "(하루 평균 토큰 %s, %s · 사용일 %d일)",
+		"today.no_average":     "아직 일일 평균 없음",
+		"today.context":        "컨텍스트",
+		"today.context_value":  "약 %.0f%% 사용 (%s / %s 토큰)",
+		"today.context_warn":   "곧 압축됨",
+
+		"history.from":       "시작",
+		"history.to":         "종료",
+		"history.days":       "일수",
+		"history.coverage":   "보관 위치",
+		"history.both":       "파일 + 롤업",
+		"history.partial":    "일부 파일 + 롤업",
+		"history.store_only": "롤업만",
+		"history.raw_only":   "파일만 (롤업 전)",
+		"history.summary":    "사용 기록이 있는 날 %d일: 세션 파일 보관 %d일, 롤업에만 보관 %d일",
+		"history.empty":      "사용 기록이 없습니다",
+
+		"history.data":            "데이터",
+		"history.kept":            "보존 기간",
+		"history.removed":         "삭제",
+		"history.would_remove":    "삭제 예정",
+		"history.forever":         "영구",
+		"history.kept_days":       "%d일",
+		"history.class_rollups":   "롤업 행",
+		"history.class_baselines": "베이스라인",
+		"history.class_focus":     "포커스 구간",
+		"history.no_retention":    "보존 기간이 설정되지 않아 (설정의 retention.*) 모든 데이터를 보존합니다",
+
+		"baseline.title":                 "기준 %s와 비교 (저장 %s, %s)",
+		"baseline.points":                "%+.1f포인트",
+		"baseline.entries":               "항목 수",
+		"baseline.total_tokens":          "총 토큰",
+		"baseline.input_tokens":          "입력 토큰",
+		"baseline.output_tokens":         "출력 토큰",
+		"baseline.cache_creation_tokens": "캐시 쓰기 토큰",
+		"baseline.cache_read_tokens":     "캐시 읽기 토큰",
+		"baseline.cost_usd":              "예상 비용",
+		"baseline.requests_per_day":      "일일 요청",
+		"baseline.tokens_per_request":    "요청당 토큰",
+		"baseline.cost_per_request":      "요청당 비용",
+		"baseline.cost_per_day":          "일일 비용",
+		"baseline.cache_read_share":      "캐시 읽기 비율",
+
+		"msg.no_sessions":    "세션을 찾을 수 없습니다",
+		"msg.session_total":  "합계: 세션 %d개",
+		"msg.filters":        "필터: %s",
+		"msg.no_problems":    "문제가 없습니다",
+		"msg.review_bundle":  "이슈에 첨부하기 전에 번들 내용을 확인하세요.",
+		"msg.config_set":     "✓ %s = %s 설정됨",
+		"msg.config_added":   "✓ %s에 추가됨: %s",
+		"msg.config_removed": "✓ %s에서 제거됨: %s",
+		"msg.config_saved":   "✓ 설정 저장 위치: %s",
+	},
+}
@@ -0,0 +1,113 @@
+// Package i18n provides the message catalog for user-facing strings.
+//
+// Messages are looked up by key in the current locale and fall back to
+// English, then to the key itself, so a missing translation never hides
+// output. The locale is chosen once at startup from configuration or the
+// environment:
+//
+//	i18n.SetLocale(i18n.Detect(cfg.Display.Locale))
+//	fmt.Println(i18n.T("table.no_data"))
+//
+// Machine-readable output (JSON, CSV, error codes) is never translated.
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
+)
+
+// Locale identifies a message language.
+type Locale string
+
+// Supported locales.
+const (
+	// English is the default locale.
+	English Locale = "en"
+
+	// Korean is the Korean locale.
+	Korean Locale = "ko"
+)
+
+// Locales lists the supported locales.
+var Locales = []Locale{English, Korean}
+
+// current holds the active locale.
+var current atomic.Value
+
+// Parse returns the supported locale named by s, accepting POSIX and
+// BCP 47 forms such as "ko", "ko_KR.UTF-8", and "ko-KR".
+//
+// Returns false for empty, "C", "POSIX", and unsupported languages.
+func Parse(s string) (Locale, bool) {
+	lang := strings.ToLower(s)
+	if i := strings.IndexAny(lang, "_-.@"); i >= 0 {
+		lang = lang[:i]
+	}
+	for _, l := range Locales {
+		if lang == string(l) {
+			return l, true
+		}
+	}
+	return "", false
+}
+
+// Detect returns the locale to use. A configured value wins; otherwise
+// LC_ALL, LC_MESSAGES, and LANG are consulted in the usual POSIX order.
+// Unsupported or empty values fall back to English.
+func Detect(configured string) Locale {
+	if configured != "" && configured != "auto" {
+		if l, ok := Parse(configured); ok {
+			return l
+		}
+		return English
+	}
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		value := os.Getenv(name)
+		if value == "" {
+			continue
+		}
+		// The first variable that is set decides, even if unsupported.
+		if l, ok := Parse(value); ok {
+			return l
+		}
+		return English
+	}
+	return English
+}
+
+// SetLocale sets the locale used by T and Tf.
+func SetLocale(l Locale) {
+	current.Store(l)
+}
+
+// Current returns the active locale.
+func Current() Locale {
+	if l, ok := current.Load().(Locale); ok {
+		return l
+	}
+	return English
+}
+
+// T returns the message for key in the current locale.
+func T(key string) string {
+	return lookup(Current(), key)
+}
+
+// Tf formats the message for key in the current locale with args.
+func Tf(key string, args ...interface{}) string {
+	return fmt.Sprintf(T(key), args...)
+}
+
+// lookup returns the message for key in l, falling back to English and
+// then to the key.
+func lookup(l Locale, key string) string {
+	if msg, ok := catalog[l][key]; ok {
+		return msg
+	}
+	if msg, ok := catalog[English][key]; ok {
+		return msg
+	}
+	return key
+}
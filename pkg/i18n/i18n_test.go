@@ -0,0 +1,98 @@
+package i18n
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Locale
+		ok   bool
+	}{
+		{"en", English, true},
+		{"ko", Korean, true},
+		{"ko_KR.UTF-8", Korean, true},
+		{"ko-KR", Korean, true},
+		{"KO", Korean, true},
+		{"en_US.UTF-8", English, true},
+		{"ja_JP.UTF-8", "", false},
+		{"C", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := Parse(tt.in)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("Parse(%q) = %q, %v; want %q, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestDetect(t *testing.T) {
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_MESSAGES", "")
+	t.Setenv("LANG", "ko_KR.UTF-8")
+
+	if got := Detect(""); got != Korean {
+		t.Errorf("Detect(\"\") with LANG=ko_KR = %q, want ko", got)
+	}
+	if got := Detect("auto"); got != Korean {
+		t.Errorf("Detect(auto) with LANG=ko_KR = %q, want ko", got)
+	}
+	if got := Detect("en"); got != English {
+		t.Errorf("Detect(en) = %q, want en", got)
+	}
+
+	// LC_ALL takes precedence, even when unsupported.
+	t.Setenv("LC_ALL", "C")
+	if got := Detect(""); got != English {
+		t.Errorf("Detect(\"\") with LC_ALL=C = %q, want en", got)
+	}
+}
+
+func TestLookupFallback(t *testing.T) {
+	if got := lookup(Korean, "table.no_data"); got != "데이터 없음" {
+		t.Errorf("lookup(ko, table.no_data) = %q", got)
+	}
+	if got := lookup(Locale("xx"), "table.no_data"); got != "No data" {
+		t.Errorf("lookup(xx, table.no_data) = %q, want English fallback", got)
+	}
+	if got := lookup(Korean, "missing.key"); got != "missing.key" {
+		t.Errorf("lookup(ko, missing.key) = %q, want the key", got)
+	}
+}
+
+// TestCatalogComplete checks that every locale translates every English
+// message with the same format verbs, so Tf never misformats.
+func TestCatalogComplete(t *testing.T) {
+	for _, l := range Locales {
+		for key, en := range catalog[English] {
+			msg, ok := catalog[l][key]
+			if !ok {
+				t.Errorf("%s: missing %s", l, key)
+				continue
+			}
+			if got, want := verbs(msg), verbs(en); got != want {
+				t.Errorf("%s: %s has verbs %q, want %q", l, key, got, want)
+			}
+		}
+		for key := range catalog[l] {
+			if _, ok := catalog[English][key]; !ok {
+				t.Errorf("%s: %s is not defined in English", l, key)
+			}
+		}
+	}
+}
+
+// verbs returns the format verbs of msg in order.
+func verbs(msg string) string {
+	var b strings.Builder
+	for i := 0; i < len(msg)-1; i++ {
+		if msg[i] == '%' {
+			b.WriteByte(msg[i+1])
+			i++
+		}
+	}
+	return b.String()
+}
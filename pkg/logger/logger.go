@@ -50,6 +50,9 @@ type Config struct {
 
 	// Format is the output format (text, json).
 	Format string
+
+	// Writer, if set, overrides Output as the log destination.
+	Writer io.Writer
 }
 
 // logger implements the Logger interface using slog.
@@ -71,10 +74,14 @@ func New(cfg Config) Logger {
 	level := parseLevel(cfg.Level)
 
 	// Get output writer
-	writer, err := getWriter(cfg.Output)
-	if err != nil {
-		// Fallback to stderr
-		writer = os.Stderr
+	writer := cfg.Writer
+	if writer == nil {
+		var err error
+		writer, err = getWriter(cfg.Output)
+		if err != nil {
+			// Fallback to stderr
+			writer = os.Stderr
+		}
 	}
 
 	// Create handler based on format
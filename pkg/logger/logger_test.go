@@ -432,3 +432,19 @@ func BenchmarkLogWith(b *testing.B) {
 		log.Info("benchmark message")
 	}
 }
+
+func TestWriterOverridesOutput(t *testing.T) {
+	var buf strings.Builder
+	log := New(Config{
+		Level:  "info",
+		Output: "stdout",
+		Format: "text",
+		Writer: &buf,
+	})
+
+	log.Info("routed", "key", "value")
+
+	if !strings.Contains(buf.String(), "routed") {
+		t.Errorf("expected log written to Writer, got %q", buf.String())
+	}
+}
@@ -0,0 +1,231 @@
+package mcp
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/0xmhha/token-monitor/pkg/aggregator"
+	"github.com/0xmhha/token-monitor/pkg/apiclient"
+	"github.com/0xmhha/token-monitor/pkg/discovery"
+	"github.com/0xmhha/token-monitor/pkg/reader"
+)
+
+// APIPath is the path prefix of the REST endpoints served by NewAPIHandler.
+const APIPath = "/api/"
+
+// Page sizes of GET /api/blocks.
+const (
+	defaultBlockPage = 20
+	maxBlockPage     = 100
+)
+
+// NewAPIHandler returns read-only REST endpoints for clients that poll
+// rather than speak MCP:
+//
+//   - GET /api/blocks lists a session's billing blocks, most recent first.
+//     Blocks are identified by their start (see aggregator.BlockID); limit
+//     sets the page size and cursor, the next_cursor of the previous page,
+//     continues after that block.
+//   - GET /api/burn-rate returns a session's burn rate over window
+//     (default 5m).
+//
+// Both take session_id, defaulting to the current session, and send
+// Last-Modified so that clients can poll with If-Modified-Since and get
+// 304 Not Modified until the data changes. Responses are encoded from the
+// types of package apiclient, which follow its OpenAPI specification.
+func NewAPIHandler(disc discovery.Discoverer, readerFactory func() (reader.Reader, error), log Logger) http.Handler {
+	ctx := &sessionContext{disc: disc, readerFactory: readerFactory, log: log}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(APIPath+"blocks", ctx.serveBlocks)
+	mux.HandleFunc(APIPath+"burn-rate", ctx.serveBurnRate)
+	return mux
+}
+
+// serveBlocks serves GET /api/blocks.
+func (c *sessionContext) serveBlocks(w http.ResponseWriter, r *http.Request) {
+	if !allowGet(w, r) {
+		return
+	}
+	query := r.URL.Query()
+
+	limit := defaultBlockPage
+	if v := query.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxBlockPage {
+			http.Error(w, "limit must be between 1 and "+strconv.Itoa(maxBlockPage), http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+	var before time.Time
+	if cursor := query.Get("cursor"); cursor != "" {
+		start, err := aggregator.ParseBlockID(cursor)
+		if err != nil {
+			http.Error(w, "invalid cursor: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		before = start
+	}
+
+	sf, ok := c.resolveAPISession(w, query.Get("session_id"))
+	if !ok {
+		return
+	}
+	// Blocks change with the session file, and when a new block starts
+	// and the previous one is no longer active.
+	modified := time.Unix(sf.ModTime, 0)
+	if start := aggregator.BillingBlockStart(time.Now()); start.After(modified) {
+		modified = start
+	}
+	if notModified(w, r, modified) {
+		return
+	}
+
+	agg, err := c.aggregateSession(sf)
+	if err != nil {
+		c.apiError(w, err)
+		return
+	}
+
+	page := apiclient.BlockPage{SessionID: sf.SessionID, Blocks: make([]apiclient.Block, 0, limit)}
+	for _, block := range agg.BillingBlocks(sf.SessionID) {
+		if !before.IsZero() && !block.StartTime.Before(before) {
+			continue
+		}
+		if len(page.Blocks) == limit {
+			page.NextCursor = page.Blocks[limit-1].ID
+			break
+		}
+		page.Blocks = append(page.Blocks, apiclient.Block{
+			ID:           block.ID(),
+			StartTime:    apiTime(block.StartTime),
+			EndTime:      apiTime(block.EndTime),
+			LastSeen:     apiTime(block.LastSeen),
+			TotalTokens:  block.TotalTokens,
+			InputTokens:  block.InputTokens,
+			OutputTokens: block.OutputTokens,
+			EntryCount:   block.EntryCount,
+			RateLimits:   block.RateLimits,
+			IsActive:     block.IsActive,
+		})
+	}
+	c.writeAPIJSON(w, page)
+}
+
+// serveBurnRate serves GET /api/burn-rate.
+func (c *sessionContext) serveBurnRate(w http.ResponseWriter, r *http.Request) {
+	if !allowGet(w, r) {
+		return
+	}
+	query := r.URL.Query()
+
+	window := 5 * time.Minute
+	if v := query.Get("window"); v != "" {
+		parsed, err := time.ParseDuration(v)
+		if err != nil || parsed <= 0 {
+			http.Error(w, "invalid window "+strconv.Quote(v), http.StatusBadRequest)
+			return
+		}
+		window = parsed
+	}
+
+	sf, ok := c.resolveAPISession(w, query.Get("session_id"))
+	if !ok {
+		return
+	}
+	// The rate keeps falling while the latest entries slide out of the
+	// window, and is stable once the window has passed them.
+	now := time.Now()
+	modified := time.Unix(sf.ModTime, 0).Add(window)
+	if modified.After(now) {
+		modified = now
+	}
+	if notModified(w, r, modified) {
+		return
+	}
+
+	agg, err := c.aggregateSession(sf)
+	if err != nil {
+		c.apiError(w, err)
+		return
+	}
+
+	rate := agg.BurnRate(sf.SessionID, window)
+	c.writeAPIJSON(w, apiclient.BurnRate{
+		SessionID:             sf.SessionID,
+		BlockID:               aggregator.BlockID(now),
+		Window:                window.String(),
+		TokensPerMinute:       rate.TokensPerMinute,
+		TokensPerHour:         rate.TokensPerHour,
+		InputTokensPerMinute:  rate.InputTokensPerMinute,
+		OutputTokensPerMinute: rate.OutputTokensPerMinute,
+		EntryCount:            rate.EntryCount,
+	})
+}
+
+// apiTime returns t in UTC with second precision, as API times are sent.
+func apiTime(t time.Time) time.Time {
+	return t.UTC().Truncate(time.Second)
+}
+
+// resolveAPISession resolves the session of an API request, writing the
+// error response when it fails.
+func (c *sessionContext) resolveAPISession(w http.ResponseWriter, sessionID string) (discovery.SessionFile, bool) {
+	sf, err := c.resolveSession(sessionID)
+	if err != nil {
+		c.apiError(w, err)
+		return discovery.SessionFile{}, false
+	}
+	return sf, true
+}
+
+// apiError writes err as a 404 for unknown or undetected sessions and as a
+// 500 otherwise.
+func (c *sessionContext) apiError(w http.ResponseWriter, err error) {
+	var paramErr *ParamError
+	if errors.As(err, &paramErr) || errors.Is(err, discovery.ErrNoCurrentSession) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	c.log.Error("API request failed", "error", err)
+	http.Error(w, "internal error", http.StatusInternalServerError)
+}
+
+// writeAPIJSON writes v as the JSON body of a 200 response.
+func (c *sessionContext) writeAPIJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		c.log.Error("failed to write response", "error", err)
+	}
+}
+
+// allowGet answers requests other than GET and HEAD with 405 Method Not
+// Allowed and reports whether r may proceed.
+func allowGet(w http.ResponseWriter, r *http.Request) bool {
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		return true
+	}
+	w.Header().Set("Allow", "GET, HEAD")
+	http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	return false
+}
+
+// notModified sets Last-Modified to modified and, when the request's
+// If-Modified-Since is not older, answers 304 Not Modified and returns
+// true.
+func notModified(w http.ResponseWriter, r *http.Request, modified time.Time) bool {
+	// HTTP dates have second precision.
+	modified = modified.UTC().Truncate(time.Second)
+	w.Header().Set("Last-Modified", modified.Format(http.TimeFormat))
+
+	since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil || modified.After(since) {
+		return false
+	}
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
@@ -0,0 +1,149 @@
+package mcp
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xmhha/token-monitor/pkg/aggregator"
+	"github.com/0xmhha/token-monitor/pkg/apiclient"
+	"github.com/0xmhha/token-monitor/pkg/discovery"
+)
+
+// makeBlockSession writes a session with one entry per timestamp.
+func makeBlockSession(t *testing.T, sessionID string, times []time.Time) discovery.SessionFile {
+	t.Helper()
+
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, sessionID+".jsonl")
+
+	var lines []string
+	for i, ts := range times {
+		data, err := json.Marshal(map[string]any{
+			"timestamp": ts.UTC().Format(time.RFC3339),
+			"sessionId": sessionID,
+			"message": map[string]any{
+				"id":    "msg_" + ts.Format("150405") + string(rune('a'+i)),
+				"model": "claude-sonnet-4-20250514",
+				"usage": map[string]any{"input_tokens": 100, "output_tokens": 10},
+			},
+		})
+		require.NoError(t, err)
+		lines = append(lines, string(data))
+	}
+	require.NoError(t, os.WriteFile(filePath, []byte(strings.Join(lines, "\n")+"\n"), 0600))
+
+	info, err := os.Stat(filePath)
+	require.NoError(t, err)
+	return discovery.SessionFile{SessionID: sessionID, FilePath: filePath, ModTime: info.ModTime().Unix()}
+}
+
+func TestAPIHandler_BlocksPagination(t *testing.T) {
+	t.Parallel()
+
+	day := time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC)
+	sf := makeBlockSession(t, "s1", []time.Time{
+		day.Add(1 * time.Hour),
+		day.Add(2 * time.Hour),
+		day.Add(6 * time.Hour),
+		day.Add(11 * time.Hour),
+	})
+	h := NewAPIHandler(&mockDiscoverer{sessions: []discovery.SessionFile{sf}}, newTestReaderFactory(), &testLogger{})
+
+	get := func(query string) apiclient.BlockPage {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, APIPath+"blocks?"+query, nil))
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var page apiclient.BlockPage
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &page))
+		return page
+	}
+
+	first := get("limit=2")
+	require.Len(t, first.Blocks, 2)
+	assert.Equal(t, "2025-11-03T1000Z", first.Blocks[0].ID)
+	assert.Equal(t, "2025-11-03T0500Z", first.Blocks[1].ID)
+	assert.Equal(t, "2025-11-03T0500Z", first.NextCursor)
+
+	last := get("limit=2&cursor=" + first.NextCursor)
+	require.Len(t, last.Blocks, 1)
+	assert.Equal(t, "2025-11-03T0000Z", last.Blocks[0].ID)
+	assert.Equal(t, 2, last.Blocks[0].EntryCount)
+	assert.True(t, last.Blocks[0].LastSeen.Equal(day.Add(2*time.Hour)), "last_seen = %s", last.Blocks[0].LastSeen)
+	assert.Empty(t, last.NextCursor)
+
+	for _, query := range []string{"limit=0", "limit=x", "cursor=2025-11-03T0100Z"} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, APIPath+"blocks?"+query, nil))
+		assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+	}
+}
+
+func TestAPIHandler_IfModifiedSince(t *testing.T) {
+	t.Parallel()
+
+	sf := makeBlockSession(t, "s1", []time.Time{time.Now().Add(-time.Hour)})
+	sf.ModTime = time.Now().Add(-time.Hour).Unix()
+	h := NewAPIHandler(&mockDiscoverer{sessions: []discovery.SessionFile{sf}}, newTestReaderFactory(), &testLogger{})
+
+	for _, path := range []string{"blocks", "burn-rate"} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, APIPath+path, nil))
+		require.Equal(t, http.StatusOK, rec.Code, path)
+		modified := rec.Header().Get("Last-Modified")
+		require.NotEmpty(t, modified, path)
+
+		req := httptest.NewRequest(http.MethodGet, APIPath+path, nil)
+		req.Header.Set("If-Modified-Since", modified)
+		rec = httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusNotModified, rec.Code, path)
+		assert.Empty(t, rec.Body.String(), path)
+	}
+}
+
+func TestAPIHandler_BurnRate(t *testing.T) {
+	t.Parallel()
+
+	sf := makeSessionFile(t, "s1", 500, 200)
+	h := NewAPIHandler(&mockDiscoverer{sessions: []discovery.SessionFile{sf}}, newTestReaderFactory(), &testLogger{})
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, APIPath+"burn-rate?window=10m", nil))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var data map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &data))
+	assert.Equal(t, "s1", data["session_id"])
+	assert.Equal(t, aggregator.BlockID(time.Now()), data["block_id"])
+	assert.EqualValues(t, 1, data["entry_count"])
+}
+
+func TestAPIHandler_Errors(t *testing.T) {
+	t.Parallel()
+
+	h := NewAPIHandler(&mockDiscoverer{}, newTestReaderFactory(), &testLogger{})
+
+	tests := []struct {
+		method, target string
+		want           int
+	}{
+		{http.MethodGet, APIPath + "blocks", http.StatusNotFound},
+		{http.MethodGet, APIPath + "blocks?session_id=missing", http.StatusNotFound},
+		{http.MethodGet, APIPath + "burn-rate?window=soon", http.StatusBadRequest},
+		{http.MethodPost, APIPath + "blocks", http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.target, nil))
+		assert.Equal(t, tt.want, rec.Code, "%s %s", tt.method, tt.target)
+	}
+}
@@ -0,0 +1,119 @@
+package mcp
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// Scope is a permission granted to an API token.
+type Scope string
+
+// Token scopes. Admin implies read.
+const (
+	// ScopeRead allows querying usage data.
+	ScopeRead Scope = "read"
+
+	// ScopeAdmin allows operations that change state.
+	ScopeAdmin Scope = "admin"
+
+	// ScopeIngest allows pushing usage, e.g. from Claude Code hooks.
+	ScopeIngest Scope = "ingest"
+)
+
+// Scopes is the set of scopes granted to a caller. A nil set grants
+// everything; it is used for stdio, where the caller owns the process.
+type Scopes map[Scope]bool
+
+// Allows reports whether the set grants required.
+func (s Scopes) Allows(required Scope) bool {
+	if s == nil {
+		return true
+	}
+	return s[required] || s[ScopeAdmin]
+}
+
+// Token is an API token accepted by Authenticate.
+type Token struct {
+	// Name identifies the token in logs.
+	Name string
+
+	// Secret is the bearer token value.
+	Secret string
+
+	// Scopes are the scopes granted to requests with this token.
+	Scopes Scopes
+}
+
+// scopesKey is the context key for the caller's scopes.
+type scopesKey struct{}
+
+// scopesFromContext returns the scopes set by Authenticate, or nil when the
+// request was not authenticated.
+func scopesFromContext(ctx context.Context) Scopes {
+	scopes, _ := ctx.Value(scopesKey{}).(Scopes)
+	return scopes
+}
+
+// tokenNameKey is the context key for the name of the caller's token.
+type tokenNameKey struct{}
+
+// TokenName returns the name of the token Authenticate matched, or "" when
+// the request was not authenticated.
+func TokenName(ctx context.Context) string {
+	name, _ := ctx.Value(tokenNameKey{}).(string)
+	return name
+}
+
+// Authenticate wraps next so that each request must carry one of tokens as
+// "Authorization: Bearer <secret>". Other requests get 401 Unauthorized.
+// The matched token's scopes limit the tools the request may list and call.
+func Authenticate(tokens []Token, next http.Handler) http.Handler {
+	// Comparing fixed-size digests keeps the comparison constant-time
+	// regardless of secret length.
+	digests := make([][sha256.Size]byte, len(tokens))
+	for i, token := range tokens {
+		digests[i] = sha256.Sum256([]byte(token.Secret))
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		secret, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if ok {
+			digest := sha256.Sum256([]byte(strings.TrimSpace(secret)))
+			match := -1
+			for i := range digests {
+				if subtle.ConstantTimeCompare(digest[:], digests[i][:]) == 1 {
+					match = i
+				}
+			}
+			if match >= 0 {
+				scopes := tokens[match].Scopes
+				if scopes == nil {
+					scopes = Scopes{}
+				}
+				ctx := context.WithValue(r.Context(), scopesKey{}, scopes)
+				ctx = context.WithValue(ctx, tokenNameKey{}, tokens[match].Name)
+				next.ServeHTTP(w, r.WithContext(ctx))
+				return
+			}
+		}
+
+		w.Header().Set("WWW-Authenticate", `Bearer realm="token-monitor"`)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+	})
+}
+
+// RequireScope wraps next so that authenticated requests without required
+// get 403 Forbidden. Use it behind Authenticate for endpoints other than
+// MCP, whose tools are checked individually.
+func RequireScope(required Scope, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !scopesFromContext(r.Context()).Allows(required) {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/0xmhha/token-monitor/pkg/discovery"
 	"github.com/0xmhha/token-monitor/pkg/display"
 	"github.com/0xmhha/token-monitor/pkg/parser"
+	"github.com/0xmhha/token-monitor/pkg/plan"
 	"github.com/0xmhha/token-monitor/pkg/sessionloader"
 )
 
@@ -79,6 +80,20 @@ func toolGetUsageByWindow() ToolDefinition {
 	}
 }
 
+func toolGetTeamLeaderboard() ToolDefinition {
+	return ToolDefinition{
+		Name:        "get_team_leaderboard",
+		Description: "Rank team members (Claude config dir labels) by token usage or API-equivalent cost over a time window.",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"window": map[string]any{"type": "string", "description": "Window: today, all, Nd (e.g. 7d), or Nh (e.g. 24h). Default: 7d."},
+				"by":     map[string]any{"type": "string", "description": "Rank by 'tokens' (default) or 'cost'."},
+			},
+		},
+	}
+}
+
 // --- Helpers ---
 
 // breakdownToList converts a model→breakdown map into a deterministic
@@ -241,3 +256,58 @@ func (c *sessionContext) usageByWindow(window, modelGlob string) (*ToolCallResul
 		"by_model":      breakdownToList(breakdown),
 	})
 }
+
+func (c *sessionContext) handleGetTeamLeaderboard(args json.RawMessage) (*ToolCallResult, error) {
+	var params struct {
+		Window string `json:"window"`
+		By     string `json:"by"`
+	}
+	if len(args) > 0 {
+		if err := json.Unmarshal(args, &params); err != nil {
+			return nil, fmt.Errorf("invalid arguments: %w", err)
+		}
+	}
+	if params.Window == "" {
+		params.Window = "7d"
+	}
+	switch params.By {
+	case "":
+		params.By = "tokens"
+	case "tokens", "cost":
+	default:
+		return nil, NewParamError(fmt.Sprintf("invalid by: %q (expected tokens or cost)", params.By))
+	}
+
+	since, err := display.ParseWindow(params.Window, time.Now())
+	if err != nil {
+		return nil, NewParamError(err.Error())
+	}
+
+	entries, _, err := loadAllEntries(c.disc, c.readerFactory, c.log)
+	if err != nil {
+		return nil, err
+	}
+
+	// Members are the labels of the Claude config dirs; plans live in the
+	// CLI's team configuration, which the server does not read.
+	board := plan.Team{}.Leaderboard(aggregator.FilterSince(entries, since), params.By == "cost")
+	members := make([]map[string]any, 0, len(board))
+	for _, s := range board {
+		members = append(members, map[string]any{
+			"rank":         s.Rank,
+			"member":       s.Member,
+			"sessions":     s.Sessions,
+			"requests":     s.Totals.Entries,
+			"total_tokens": s.Totals.TotalTokens(),
+			"cost_usd":     s.Totals.CostUSD,
+			"share":        s.Share,
+		})
+	}
+
+	return textResult(map[string]any{
+		"window":  params.Window,
+		"since":   formatSince(since),
+		"by":      params.By,
+		"members": members,
+	})
+}
@@ -323,3 +323,53 @@ func TestGetUsageByWindow_InvalidWindowReturnsError(t *testing.T) {
 	require.Error(t, err, "invalid window format must produce an error")
 	assert.Contains(t, err.Error(), "invalid window")
 }
+
+// --- get_team_leaderboard ---
+
+func TestGetTeamLeaderboard_RanksMembersBySource(t *testing.T) {
+	t.Parallel()
+
+	sfA := makeMultiModelSession(t, "ffffffff-1111-2222-3333-444444444444", []modelEntry{
+		{model: "claude-sonnet-4-6", input: 100, output: 50, timestampOffset: -1 * time.Hour},
+	})
+	sfA.Source = "alice"
+	sfB := makeMultiModelSession(t, "ffffffff-5555-6666-7777-888888888888", []modelEntry{
+		{model: "claude-sonnet-4-6", input: 300, output: 150, timestampOffset: -2 * time.Hour},
+		{model: "claude-sonnet-4-6", input: 900, output: 0, timestampOffset: -30 * 24 * time.Hour},
+	})
+	sfB.Source = "bob"
+
+	disc := &mockDiscoverer{sessions: []discovery.SessionFile{sfA, sfB}}
+	registry := NewToolRegistry()
+	RegisterTokenTools(registry, disc, newTestReaderFactory(), &testLogger{})
+
+	result, err := registry.Call("get_team_leaderboard", json.RawMessage(`{}`))
+	require.NoError(t, err)
+
+	var data map[string]any
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].Text), &data))
+	assert.Equal(t, "7d", data["window"])
+	assert.Equal(t, "tokens", data["by"])
+
+	members, ok := data["members"].([]any)
+	require.True(t, ok)
+	require.Len(t, members, 2)
+	first, _ := members[0].(map[string]any)
+	second, _ := members[1].(map[string]any)
+	assert.Equal(t, "bob", first["member"])
+	assert.EqualValues(t, 450, first["total_tokens"], "the 30-day-old entry is outside the 7d default")
+	assert.EqualValues(t, 75, first["share"])
+	assert.Equal(t, "alice", second["member"])
+	assert.EqualValues(t, 2, second["rank"])
+}
+
+func TestGetTeamLeaderboard_InvalidBy(t *testing.T) {
+	t.Parallel()
+
+	registry := NewToolRegistry()
+	RegisterTokenTools(registry, &mockDiscoverer{}, newTestReaderFactory(), &testLogger{})
+
+	_, err := registry.Call("get_team_leaderboard", json.RawMessage(`{"by":"requests"}`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid by")
+}
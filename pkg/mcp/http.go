@@ -0,0 +1,44 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+)
+
+// maxHTTPRequestSize limits the body of a JSON-RPC request over HTTP.
+const maxHTTPRequestSize = 1 << 20
+
+// ServeHTTP implements http.Handler. Each POST body is one JSON-RPC
+// message; the response body is its JSON-RPC response. Notifications are
+// acknowledged with 202 Accepted and an empty body. Behind Authenticate,
+// the request's token scopes limit the available tools.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxHTTPRequestSize+1))
+	if err != nil {
+		http.Error(w, "failed to read request", http.StatusBadRequest)
+		return
+	}
+	if len(body) > maxHTTPRequestSize {
+		http.Error(w, "request too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	resp := s.HandleScoped(bytes.TrimSpace(body), scopesFromContext(r.Context()))
+	if resp == nil {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		s.logger.Error("failed to write response", "error", err)
+	}
+}
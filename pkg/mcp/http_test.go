@@ -0,0 +1,169 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_HTTP(t *testing.T) {
+	srv := NewServer(nil, nil, NewToolRegistry(), "test", &testLogger{})
+
+	post := func(body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(body)))
+		return rec
+	}
+
+	t.Run("request", func(t *testing.T) {
+		rec := post(`{"jsonrpc":"2.0","id":1,"method":"ping"}`)
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+		var resp Response
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Nil(t, resp.Error)
+		assert.EqualValues(t, 1, resp.ID)
+	})
+
+	t.Run("notification", func(t *testing.T) {
+		rec := post(`{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+		assert.Equal(t, http.StatusAccepted, rec.Code)
+		assert.Empty(t, rec.Body.String())
+	})
+
+	t.Run("parse error", func(t *testing.T) {
+		rec := post(`{not json`)
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var resp Response
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		require.NotNil(t, resp.Error)
+		assert.Equal(t, ErrCodeParseError, resp.Error.Code)
+	})
+
+	t.Run("wrong method", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/mcp", nil))
+		assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+		assert.Equal(t, http.MethodPost, rec.Header().Get("Allow"))
+	})
+}
+
+func TestAuthenticate(t *testing.T) {
+	registry := NewToolRegistry()
+	handler := func(json.RawMessage) (*ToolCallResult, error) {
+		return &ToolCallResult{Content: []ToolContent{{Type: "text", Text: "done"}}}, nil
+	}
+	registry.Register(ToolDefinition{Name: "get_stats"}, handler)
+	registry.RegisterScoped(ToolDefinition{Name: "prune"}, ScopeAdmin, handler)
+
+	srv := NewServer(nil, nil, registry, "test", &testLogger{})
+	h := Authenticate([]Token{
+		{Name: "dashboard", Secret: "read-token", Scopes: Scopes{ScopeRead: true}},
+		{Name: "ops", Secret: "admin-token", Scopes: Scopes{ScopeAdmin: true}},
+	}, srv)
+
+	post := func(token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(body))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+	call := func(token, tool string) Response {
+		rec := post(token, `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"`+tool+`"}}`)
+		require.Equal(t, http.StatusOK, rec.Code)
+		var resp Response
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		return resp
+	}
+	listed := func(token string) []string {
+		rec := post(token, `{"jsonrpc":"2.0","id":1,"method":"tools/list"}`)
+		require.Equal(t, http.StatusOK, rec.Code)
+		var resp struct {
+			Result ToolsListResult `json:"result"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		var names []string
+		for _, tool := range resp.Result.Tools {
+			names = append(names, tool.Name)
+		}
+		return names
+	}
+
+	t.Run("missing or wrong token", func(t *testing.T) {
+		for _, token := range []string{"", "nope"} {
+			rec := post(token, `{"jsonrpc":"2.0","id":1,"method":"ping"}`)
+			assert.Equal(t, http.StatusUnauthorized, rec.Code)
+			assert.Contains(t, rec.Header().Get("WWW-Authenticate"), "Bearer")
+		}
+	})
+
+	t.Run("read token", func(t *testing.T) {
+		assert.Equal(t, []string{"get_stats"}, listed("read-token"))
+		assert.Nil(t, call("read-token", "get_stats").Error)
+
+		resp := call("read-token", "prune")
+		require.NotNil(t, resp.Error)
+		assert.Equal(t, ErrCodeForbidden, resp.Error.Code)
+	})
+
+	t.Run("admin token", func(t *testing.T) {
+		assert.Equal(t, []string{"get_stats", "prune"}, listed("admin-token"))
+		assert.Nil(t, call("admin-token", "prune").Error)
+	})
+
+	t.Run("unauthenticated handler grants everything", func(t *testing.T) {
+		resp := srv.Handle([]byte(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"prune"}}`))
+		assert.Nil(t, resp.Error)
+	})
+}
+
+func TestRequireScope(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	})
+	h := Authenticate([]Token{
+		{Name: "dashboard", Secret: "read-token", Scopes: Scopes{ScopeRead: true}},
+		{Name: "hooks", Secret: "ingest-token", Scopes: Scopes{ScopeIngest: true}},
+		{Name: "ops", Secret: "admin-token", Scopes: Scopes{ScopeAdmin: true}},
+	}, RequireScope(ScopeIngest, ok))
+
+	for token, want := range map[string]int{
+		"read-token":   http.StatusForbidden,
+		"ingest-token": http.StatusAccepted,
+		"admin-token":  http.StatusAccepted,
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/hooks", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		assert.Equal(t, want, rec.Code, token)
+	}
+}
+
+func TestTokenName(t *testing.T) {
+	var name string
+	h := Authenticate([]Token{
+		{Name: "alice", Secret: "alice-token", Scopes: Scopes{ScopeIngest: true}},
+		{Name: "bob", Secret: "bob-token", Scopes: Scopes{ScopeIngest: true}},
+	}, http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		name = TokenName(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/team/usage", nil)
+	req.Header.Set("Authorization", "Bearer bob-token")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	assert.Equal(t, "bob", name)
+
+	assert.Empty(t, TokenName(context.Background()))
+}
@@ -25,7 +25,8 @@ type Server struct {
 	logger  Logger
 }
 
-// NewServer creates a new MCP server.
+// NewServer creates a new MCP server. reader and writer may be nil when
+// the server is only used as an http.Handler.
 func NewServer(reader io.Reader, writer io.Writer, registry *ToolRegistry, version string, log Logger) *Server {
 	return &Server{
 		tools:   registry,
@@ -46,20 +47,7 @@ func (s *Server) Run() error {
 			continue
 		}
 
-		req, parseErr := s.parseRequest(line)
-		if parseErr != nil {
-			s.writeError(nil, ErrCodeParseError, "parse error", nil)
-			continue
-		}
-
-		s.logger.Debug("received request", "method", req.Method, "id", req.ID)
-
-		// Notifications have no id and must not receive a response.
-		if req.ID == nil && req.Method == "notifications/initialized" {
-			continue
-		}
-
-		resp := s.dispatch(req)
+		resp := s.Handle(line)
 		if resp == nil {
 			continue
 		}
@@ -76,6 +64,31 @@ func (s *Server) Run() error {
 	return nil
 }
 
+// Handle processes one raw JSON-RPC message and returns the response to
+// send, or nil for notifications, which must not receive one.
+func (s *Server) Handle(data []byte) *Response {
+	return s.HandleScoped(data, nil)
+}
+
+// HandleScoped is Handle for a caller granted scopes: tools requiring
+// other scopes are hidden from tools/list and refused by tools/call.
+// Nil scopes grant everything.
+func (s *Server) HandleScoped(data []byte, scopes Scopes) *Response {
+	req, err := s.parseRequest(data)
+	if err != nil {
+		return s.errorResponse(nil, ErrCodeParseError, "parse error", nil)
+	}
+
+	s.logger.Debug("received request", "method", req.Method, "id", req.ID)
+
+	// Notifications have no id and must not receive a response.
+	if req.ID == nil && req.Method == "notifications/initialized" {
+		return nil
+	}
+
+	return s.dispatch(req, scopes)
+}
+
 // parseRequest decodes a JSON-RPC request from raw bytes.
 func (s *Server) parseRequest(data []byte) (*Request, error) {
 	var req Request
@@ -86,7 +99,7 @@ func (s *Server) parseRequest(data []byte) (*Request, error) {
 }
 
 // dispatch routes a request to the appropriate handler and returns a response.
-func (s *Server) dispatch(req *Request) *Response {
+func (s *Server) dispatch(req *Request, scopes Scopes) *Response {
 	switch req.Method {
 	case "initialize":
 		return s.handleInitialize(req)
@@ -94,9 +107,9 @@ func (s *Server) dispatch(req *Request) *Response {
 		// Notification — no response.
 		return nil
 	case "tools/list":
-		return s.handleToolsList(req)
+		return s.handleToolsList(req, scopes)
 	case "tools/call":
-		return s.handleToolsCall(req)
+		return s.handleToolsCall(req, scopes)
 	case "ping":
 		return s.successResponse(req.ID, struct{}{})
 	default:
@@ -120,20 +133,31 @@ func (s *Server) handleInitialize(req *Request) *Response {
 }
 
 // handleToolsList handles the tools/list method.
-func (s *Server) handleToolsList(req *Request) *Response {
+func (s *Server) handleToolsList(req *Request, scopes Scopes) *Response {
+	tools := make([]ToolDefinition, 0, len(s.tools.tools))
+	for _, tool := range s.tools.List() {
+		if scopes.Allows(s.tools.Scope(tool.Name)) {
+			tools = append(tools, tool)
+		}
+	}
 	result := ToolsListResult{
-		Tools: s.tools.List(),
+		Tools: tools,
 	}
 	return s.successResponse(req.ID, result)
 }
 
 // handleToolsCall handles the tools/call method.
-func (s *Server) handleToolsCall(req *Request) *Response {
+func (s *Server) handleToolsCall(req *Request, scopes Scopes) *Response {
 	var params ToolCallParams
 	if err := json.Unmarshal(req.Params, &params); err != nil {
 		return s.errorResponse(req.ID, ErrCodeInvalidParams, "invalid params", err.Error())
 	}
 
+	if scope := s.tools.Scope(params.Name); !scopes.Allows(scope) {
+		s.logger.Warn("tool call refused", "tool", params.Name, "required_scope", scope)
+		return s.errorResponse(req.ID, ErrCodeForbidden, fmt.Sprintf("tool %s requires the %s scope", params.Name, scope), nil)
+	}
+
 	result, err := s.tools.Call(params.Name, params.Arguments)
 	if err != nil {
 		var paramErr *ParamError
@@ -168,14 +192,6 @@ func (s *Server) errorResponse(id any, code int, message string, data any) *Resp
 	}
 }
 
-// writeError encodes and sends an error response directly.
-func (s *Server) writeError(id any, code int, message string, data any) {
-	resp := s.errorResponse(id, code, message, data)
-	if err := s.writeResponse(resp); err != nil {
-		s.logger.Error("failed to write error response", "error", err)
-	}
-}
-
 // writeResponse encodes a response as a single JSON line to the writer.
 func (s *Server) writeResponse(resp *Response) error {
 	data, err := json.Marshal(resp)
@@ -19,6 +19,7 @@ type ToolHandler func(args json.RawMessage) (*ToolCallResult, error)
 type ToolRegistry struct {
 	tools    []ToolDefinition
 	handlers map[string]ToolHandler
+	scopes   map[string]Scope
 }
 
 // NewToolRegistry creates an empty tool registry.
@@ -26,13 +27,30 @@ func NewToolRegistry() *ToolRegistry {
 	return &ToolRegistry{
 		tools:    make([]ToolDefinition, 0),
 		handlers: make(map[string]ToolHandler),
+		scopes:   make(map[string]Scope),
 	}
 }
 
-// Register adds a tool definition and its handler to the registry.
+// Register adds a read-only tool definition and its handler to the
+// registry.
 func (r *ToolRegistry) Register(def ToolDefinition, handler ToolHandler) {
+	r.RegisterScoped(def, ScopeRead, handler)
+}
+
+// RegisterScoped adds a tool that only callers granted scope may list
+// and call.
+func (r *ToolRegistry) RegisterScoped(def ToolDefinition, scope Scope, handler ToolHandler) {
 	r.tools = append(r.tools, def)
 	r.handlers[def.Name] = handler
+	r.scopes[def.Name] = scope
+}
+
+// Scope returns the scope required to call the named tool.
+func (r *ToolRegistry) Scope(name string) Scope {
+	if scope, ok := r.scopes[name]; ok {
+		return scope
+	}
+	return ScopeRead
 }
 
 // List returns all registered tool definitions.
@@ -60,11 +78,11 @@ type sessionContext struct {
 
 // RegisterTokenTools registers all token monitoring tools into the registry.
 //
-// Currently registers 9 tools, split into two groups:
+// Currently registers 10 tools, split into two groups:
 //   - Single-session: get_token_usage, get_burn_rate, get_billing_block,
 //     list_sessions, get_session_detail, compare_sessions
 //   - Cross-session breakdown (v0.2): get_session_breakdown,
-//     get_today_usage, get_usage_by_window
+//     get_today_usage, get_usage_by_window, get_team_leaderboard
 func RegisterTokenTools(registry *ToolRegistry, disc discovery.Discoverer, readerFactory func() (reader.Reader, error), log Logger) {
 	ctx := &sessionContext{disc: disc, readerFactory: readerFactory, log: log}
 
@@ -79,6 +97,7 @@ func RegisterTokenTools(registry *ToolRegistry, disc discovery.Discoverer, reade
 	registry.Register(toolGetSessionBreakdown(), ctx.handleGetSessionBreakdown)
 	registry.Register(toolGetTodayUsage(), ctx.handleGetTodayUsage)
 	registry.Register(toolGetUsageByWindow(), ctx.handleGetUsageByWindow)
+	registry.Register(toolGetTeamLeaderboard(), ctx.handleGetTeamLeaderboard)
 }
 
 // --- Tool definitions ---
@@ -326,6 +345,7 @@ func (c *sessionContext) handleGetBillingBlock(args json.RawMessage) (*ToolCallR
 
 	return textResult(map[string]any{
 		"session_id":     sf.SessionID,
+		"id":             block.ID(),
 		"start_time":     block.StartTime.UTC().Format(time.RFC3339),
 		"end_time":       block.EndTime.UTC().Format(time.RFC3339),
 		"total_tokens":   block.TotalTokens,
@@ -428,6 +448,7 @@ func (c *sessionContext) handleGetSessionDetail(args json.RawMessage) (*ToolCall
 			"entry_count":     rate.EntryCount,
 		},
 		"billing_block": map[string]any{
+			"id":             block.ID(),
 			"start_time":     block.StartTime.UTC().Format(time.RFC3339),
 			"end_time":       block.EndTime.UTC().Format(time.RFC3339),
 			"total_tokens":   block.TotalTokens,
@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/0xmhha/token-monitor/pkg/aggregator"
 	"github.com/0xmhha/token-monitor/pkg/discovery"
 	"github.com/0xmhha/token-monitor/pkg/logger"
 	"github.com/0xmhha/token-monitor/pkg/parser"
@@ -31,6 +32,10 @@ func (m *mockDiscoverer) DiscoverProject(path string) ([]discovery.SessionFile,
 	return m.sessions, nil
 }
 
+func (m *mockDiscoverer) Duplicates() []discovery.Duplicate {
+	return nil
+}
+
 func (m *mockDiscoverer) FindCurrentSession() (*discovery.SessionFile, error) {
 	if len(m.sessions) == 0 {
 		return nil, discovery.ErrNoCurrentSession
@@ -331,6 +336,7 @@ func TestGetBillingBlock_ReturnsResult(t *testing.T) {
 
 	assert.Contains(t, data, "start_time")
 	assert.Contains(t, data, "end_time")
+	assert.Equal(t, aggregator.BlockID(time.Now()), data["id"])
 	assert.Contains(t, data, "time_remaining")
 	assert.Contains(t, data, "is_active")
 }
@@ -491,7 +497,7 @@ func TestRegisterTokenTools_RegistersAllTools(t *testing.T) {
 	RegisterTokenTools(registry, disc, newTestReaderFactory(), &testLogger{})
 
 	tools := registry.List()
-	assert.Len(t, tools, 9, "RegisterTokenTools must register exactly 9 tools (6 v0.1 + 3 v0.2)")
+	assert.Len(t, tools, 10, "RegisterTokenTools must register exactly 10 tools (6 v0.1 + 4 cross-session)")
 
 	names := make([]string, len(tools))
 	for i, tool := range tools {
@@ -508,4 +514,5 @@ func TestRegisterTokenTools_RegistersAllTools(t *testing.T) {
 	assert.Contains(t, names, "get_session_breakdown")
 	assert.Contains(t, names, "get_today_usage")
 	assert.Contains(t, names, "get_usage_by_window")
+	assert.Contains(t, names, "get_team_leaderboard")
 }
@@ -36,6 +36,10 @@ const (
 	ErrCodeInternal       = -32603
 )
 
+// ErrCodeForbidden is returned when the caller's token lacks the scope a
+// tool requires (from the implementation-defined server error range).
+const ErrCodeForbidden = -32001
+
 // InitializeResult is returned by the initialize method.
 type InitializeResult struct {
 	ProtocolVersion string       `json:"protocolVersion"`
@@ -3,16 +3,22 @@ package monitor
 import (
 	"context"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/0xmhha/token-monitor/pkg/aggregator"
 	"github.com/0xmhha/token-monitor/pkg/discovery"
 	"github.com/0xmhha/token-monitor/pkg/logger"
+	"github.com/0xmhha/token-monitor/pkg/parser"
 	"github.com/0xmhha/token-monitor/pkg/reader"
 	"github.com/0xmhha/token-monitor/pkg/watcher"
 )
 
+// debugBufferSize is the number of debug events buffered for a slow
+// consumer before further events are dropped.
+const debugBufferSize = 100
+
 // liveMonitor implements the LiveMonitor interface.
 type liveMonitor struct {
 	config    Config
@@ -32,9 +38,25 @@ type liveMonitor struct {
 	initialStats aggregator.Statistics // Stats at monitor start
 	lastDelta    DeltaStats            // Last non-zero delta for "now" display
 
+	// Busiest earlier billing block, recomputed when a new block starts
+	peakBlockStart time.Time
+	peakBlock      int
+
+	// Per-session changes accumulated since the last update
+	pendingChanges map[string]*SessionDelta
+
+	// Newest main-conversation entry, for context window estimates
+	latest parser.UsageEntry
+
+	// Time new entries last arrived through watcher events
+	lastActivity time.Time
+
 	// Update channel for consumers
 	updates chan Update
 
+	// Watcher events and reads for debugging consumers
+	debugEvents chan DebugEvent
+
 	// Session file paths being monitored
 	sessionPaths map[string]string // sessionID -> filePath
 }
@@ -56,24 +78,34 @@ func New(cfg Config, w watcher.Watcher, r reader.Reader, disc discovery.Discover
 	if cfg.RefreshInterval == 0 {
 		cfg.RefreshInterval = time.Second
 	}
+	if cfg.Workers < 1 {
+		cfg.Workers = 1
+	}
 
 	m := &liveMonitor{
-		config:       cfg,
-		logger:       log,
-		watcher:      w,
-		reader:       r,
-		discovery:    disc,
-		stopChan:     make(chan struct{}),
-		updates:      make(chan Update, 10),
-		sessionPaths: make(map[string]string),
+		config:         cfg,
+		logger:         log,
+		watcher:        w,
+		reader:         r,
+		discovery:      disc,
+		stopChan:       make(chan struct{}),
+		updates:        make(chan Update, 10),
+		debugEvents:    make(chan DebugEvent, debugBufferSize),
+		sessionPaths:   make(map[string]string),
+		pendingChanges: make(map[string]*SessionDelta),
 		agg: aggregator.New(aggregator.Config{
-			TrackPercentiles: true,
+			TrackPercentiles: !cfg.SkipPercentiles,
 		}),
 	}
 
 	log.Info("live monitor created",
 		"refresh_interval", cfg.RefreshInterval,
-		"session_filter", cfg.SessionIDs)
+		"max_poll_interval", cfg.MaxPollInterval,
+		"batch_window", cfg.BatchWindow,
+		"read_retry_delay", cfg.ReadRetryDelay,
+		"workers", cfg.Workers,
+		"session_filter", cfg.SessionIDs,
+		"model_filter", cfg.ModelFilter.String())
 
 	return m, nil
 }
@@ -202,32 +234,31 @@ func (m *liveMonitor) filterSessions(sessions []discovery.SessionFile) []discove
 
 // initialRead reads all session files from the beginning.
 func (m *liveMonitor) initialRead(ctx context.Context) error { //nolint:unparam // error return kept for future error handling
-	for sessionID, path := range m.sessionPaths {
+	paths := m.monitoredPaths()
+	for _, path := range paths {
 		// Reset position to read from beginning
 		if err := m.reader.Reset(path); err != nil {
 			m.logger.Warn("failed to reset position",
-				"session", sessionID,
+				"session", m.sessionIDForPath(path),
 				"path", path,
 				"error", err)
 		}
+	}
 
-		entries, err := m.reader.Read(ctx, path)
-		if err != nil {
+	for _, result := range m.readFiles(ctx, paths) {
+		if result.err != nil {
 			m.logger.Warn("failed to read session file",
-				"session", sessionID,
-				"path", path,
-				"error", err)
+				"session", result.sessionID,
+				"path", result.path,
+				"error", result.err)
 			continue
 		}
 
-		// Add entries to aggregator
-		for _, entry := range entries {
-			m.agg.Add(entry)
-		}
+		m.addEntries(result.entries)
 
 		m.logger.Debug("initial read complete",
-			"session", sessionID,
-			"entries", len(entries))
+			"session", result.sessionID,
+			"entries", len(result.entries))
 	}
 
 	// Store initial stats
@@ -238,7 +269,24 @@ func (m *liveMonitor) initialRead(ctx context.Context) error { //nolint:unparam
 }
 
 // processEvents handles file change events from the watcher.
+//
+// With a batch window, events are collected until the window closes and
+// each changed file is then read once, so a burst of writes to the same
+// session costs one read and one update. Files whose read finds nothing
+// are read again after the retry delay, in case the usage lines were
+// still being flushed.
 func (m *liveMonitor) processEvents(ctx context.Context) {
+	pending := make(map[string]bool)
+	var flush <-chan time.Time // nil while no batch is open
+
+	retry := newReadRetry(m.config.ReadRetryDelay, m.config.ReadRetries)
+	var retryDue <-chan time.Time // nil while no re-read is scheduled
+	scheduleRetry := func(paths, empty []string) {
+		if retry.schedule(paths, empty) && retryDue == nil {
+			retryDue = time.After(retry.delay)
+		}
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -253,7 +301,39 @@ func (m *liveMonitor) processEvents(ctx context.Context) {
 				return
 			}
 
-			m.handleFileChange(ctx, event)
+			m.sendDebug(DebugEvent{Kind: DebugWatch, Path: event.Path, Op: event.Op})
+
+			if m.config.BatchWindow <= 0 {
+				scheduleRetry([]string{event.Path}, m.handleFileChange(ctx, event))
+				continue
+			}
+
+			m.logger.Debug("file change detected",
+				"path", event.Path,
+				"op", event.Op)
+
+			pending[event.Path] = true
+			if flush == nil {
+				flush = time.After(m.config.BatchWindow)
+			}
+
+		case <-flush:
+			paths := make([]string, 0, len(pending))
+			for path := range pending {
+				paths = append(paths, path)
+			}
+			sort.Strings(paths)
+
+			pending = make(map[string]bool)
+			flush = nil
+
+			scheduleRetry(paths, m.handleFileChanges(ctx, paths))
+
+		case <-retryDue:
+			retryDue = nil
+			paths := retry.take()
+			m.logger.Debug("re-reading files with no new entries", "paths", len(paths))
+			scheduleRetry(paths, m.handleFileChanges(ctx, paths))
 
 		case err, ok := <-m.watcher.Errors():
 			if !ok {
@@ -262,93 +342,375 @@ func (m *liveMonitor) processEvents(ctx context.Context) {
 			}
 
 			m.logger.Error("watcher error", "error", err)
+			m.sendDebug(DebugEvent{Kind: DebugWatchError, Err: err})
 		}
 	}
 }
 
-// handleFileChange processes a file change event.
-func (m *liveMonitor) handleFileChange(ctx context.Context, event watcher.Event) {
+// handleFileChange processes a file change event, returning the path if
+// it had no new entries.
+func (m *liveMonitor) handleFileChange(ctx context.Context, event watcher.Event) []string {
 	m.logger.Debug("file change detected",
 		"path", event.Path,
 		"op", event.Op)
 
-	// Read new entries from the file
-	entries, err := m.reader.Read(ctx, event.Path)
-	if err != nil {
-		m.logger.Warn("failed to read file after change",
-			"path", event.Path,
-			"error", err)
-		return
-	}
+	return m.handleFileChanges(ctx, []string{event.Path})
+}
 
-	if len(entries) == 0 {
-		return
-	}
+// handleFileChanges reads new entries from the changed files and sends a
+// single update if any were found. It returns the paths read without
+// error that had no new entries.
+func (m *liveMonitor) handleFileChanges(ctx context.Context, paths []string) []string {
+	results := m.readFiles(ctx, paths)
 
-	// Add entries to aggregator
+	var empty []string
+	changed := false
 	m.mu.Lock()
-	for _, entry := range entries {
-		m.agg.Add(entry)
+	for _, result := range results {
+		if result.err != nil {
+			m.logger.Warn("failed to read file after change",
+				"path", result.path,
+				"error", result.err)
+			continue
+		}
+		if len(result.entries) == 0 {
+			empty = append(empty, result.path)
+			continue
+		}
+
+		m.addEntries(result.entries)
+		m.recordChange(result.sessionID, result.entries)
+		m.lastActivity = time.Now()
+		changed = true
+
+		m.logger.Debug("processed file change",
+			"session", result.sessionID,
+			"path", result.path,
+			"new_entries", len(result.entries))
 	}
 	m.mu.Unlock()
 
-	m.logger.Debug("processed file change",
-		"path", event.Path,
-		"new_entries", len(entries))
+	if changed {
+		// Trigger immediate update
+		m.sendUpdate()
+	}
+	return empty
+}
 
-	// Trigger immediate update
-	m.sendUpdate()
+// readRetry schedules re-reads of files whose write event produced no new
+// entries, up to limit times per file.
+type readRetry struct {
+	delay    time.Duration
+	limit    int
+	attempts map[string]int // re-reads made since the file last had entries
+	due      map[string]bool
+}
+
+// newReadRetry returns a schedule that never re-reads when delay or limit
+// is not positive.
+func newReadRetry(delay time.Duration, limit int) *readRetry {
+	return &readRetry{
+		delay:    delay,
+		limit:    limit,
+		attempts: make(map[string]int),
+		due:      make(map[string]bool),
+	}
+}
+
+// schedule records the outcome of reading paths, of which empty had no
+// new entries, and reports whether any re-reads are due.
+func (r *readRetry) schedule(paths, empty []string) bool {
+	if r.delay <= 0 || r.limit <= 0 {
+		return false
+	}
+
+	found := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		found[path] = true
+	}
+	for _, path := range empty {
+		found[path] = false
+		if r.attempts[path] >= r.limit {
+			delete(r.attempts, path)
+			continue
+		}
+		r.attempts[path]++
+		r.due[path] = true
+	}
+	for path, ok := range found {
+		if ok {
+			delete(r.attempts, path)
+		}
+	}
+	return len(r.due) > 0
+}
+
+// take returns the paths due for a re-read, sorted, and clears them.
+func (r *readRetry) take() []string {
+	paths := make([]string, 0, len(r.due))
+	for path := range r.due {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	r.due = make(map[string]bool)
+	return paths
 }
 
 // periodicUpdates sends periodic updates even if no file changes.
+//
+// Updates are sent on every refresh tick, but the files are only re-read
+// when the poll interval has elapsed. The interval doubles after each read
+// that finds nothing, up to MaxPollInterval, and resets to the refresh
+// interval as soon as new entries arrive, either from a re-read or from
+// watcher events.
 func (m *liveMonitor) periodicUpdates() {
 	ticker := time.NewTicker(m.config.RefreshInterval)
 	defer ticker.Stop()
 
 	ctx := context.Background()
+	poll := newPollBackoff(m.config.RefreshInterval, m.config.MaxPollInterval, time.Now())
 
 	for {
 		select {
 		case <-m.stopChan:
 			return
 
-		case <-ticker.C:
-			// Read all session files to catch any missed updates
-			m.readAllSessions(ctx)
+		case now := <-ticker.C:
+			m.mu.RLock()
+			lastActivity := m.lastActivity
+			m.mu.RUnlock()
+
+			if poll.due(now, lastActivity) {
+				// Read all session files to catch any missed updates
+				found := m.readAllSessions(ctx)
+				poll.record(now, found)
+				if !found {
+					m.logger.Debug("no new entries, backing off",
+						"next_poll", poll.interval)
+				}
+			}
 			m.sendUpdate()
 		}
 	}
 }
 
-// readAllSessions reads all monitored session files for new data.
-func (m *liveMonitor) readAllSessions(ctx context.Context) {
-	for sessionID, path := range m.sessionPaths {
-		entries, err := m.reader.Read(ctx, path)
-		if err != nil {
+// pollBackoff schedules periodic re-reads with exponential backoff.
+type pollBackoff struct {
+	min      time.Duration
+	max      time.Duration
+	interval time.Duration
+	last     time.Time
+}
+
+// newPollBackoff returns a schedule whose first re-read is due one min
+// interval after start.
+func newPollBackoff(minInterval, maxInterval time.Duration, start time.Time) *pollBackoff {
+	if maxInterval < minInterval {
+		maxInterval = minInterval
+	}
+	return &pollBackoff{
+		min:      minInterval,
+		max:      maxInterval,
+		interval: minInterval,
+		last:     start,
+	}
+}
+
+// due reports whether a re-read is due at now. Activity after the last
+// re-read resets the interval to the minimum.
+func (p *pollBackoff) due(now, lastActivity time.Time) bool {
+	if lastActivity.After(p.last) {
+		p.interval = p.min
+	}
+	// Allow half a tick of slack so ticker jitter doesn't skip a re-read.
+	return now.Sub(p.last) >= p.interval-p.min/2
+}
+
+// record notes a re-read at now and whether it found new entries.
+func (p *pollBackoff) record(now time.Time, found bool) {
+	p.last = now
+	if found {
+		p.interval = p.min
+		return
+	}
+	p.interval *= 2
+	if p.interval > p.max {
+		p.interval = p.max
+	}
+}
+
+// readAllSessions reads all monitored session files for new data and
+// reports whether any new entries were found.
+func (m *liveMonitor) readAllSessions(ctx context.Context) bool {
+	found := false
+	for _, result := range m.readFiles(ctx, m.monitoredPaths()) {
+		if result.err != nil {
 			m.logger.Debug("failed to read session file",
-				"session", sessionID,
-				"path", path,
-				"error", err)
+				"session", result.sessionID,
+				"path", result.path,
+				"error", result.err)
 			continue
 		}
 
-		if len(entries) == 0 {
+		if len(result.entries) == 0 {
 			continue
 		}
 
-		// Add entries to aggregator
 		m.mu.Lock()
-		for _, entry := range entries {
-			m.agg.Add(entry)
-		}
+		m.addEntries(result.entries)
+		m.recordChange(result.sessionID, result.entries)
 		m.mu.Unlock()
+		found = true
 
 		m.logger.Debug("periodic read complete",
-			"session", sessionID,
-			"new_entries", len(entries))
+			"session", result.sessionID,
+			"new_entries", len(result.entries))
+	}
+	return found
+}
+
+// readResult is the outcome of reading one session file.
+type readResult struct {
+	sessionID string
+	path      string
+	entries   []parser.UsageEntry // already passed through the model filter
+	err       error
+}
+
+// readFiles reads new entries from each path using up to config.Workers
+// concurrent reads. Results are returned in the order of paths.
+func (m *liveMonitor) readFiles(ctx context.Context, paths []string) []readResult {
+	results := make([]readResult, len(paths))
+
+	workers := m.config.Workers
+	if workers > len(paths) {
+		workers = len(paths)
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				entries, err := m.reader.Read(ctx, paths[i])
+				results[i] = readResult{
+					sessionID: m.sessionIDForPath(paths[i]),
+					path:      paths[i],
+					entries:   m.filterEntries(entries),
+					err:       err,
+				}
+				m.sendDebug(DebugEvent{
+					Kind:    DebugRead,
+					Path:    paths[i],
+					Entries: len(results[i].entries),
+					Err:     err,
+				})
+			}
+		}()
+	}
+	for i := range paths {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
+
+// monitoredPaths returns the monitored session file paths sorted by path.
+func (m *liveMonitor) monitoredPaths() []string {
+	paths := make([]string, 0, len(m.sessionPaths))
+	for _, path := range m.sessionPaths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// filterEntries drops entries whose model does not match the model filter.
+func (m *liveMonitor) filterEntries(entries []parser.UsageEntry) []parser.UsageEntry {
+	if m.config.ModelFilter.Empty() {
+		return entries
+	}
+
+	filtered := make([]parser.UsageEntry, 0, len(entries))
+	for _, entry := range entries {
+		if m.config.ModelFilter.Match(entry.Message.Model) {
+			filtered = append(filtered, entry)
+		}
+	}
+	return filtered
+}
+
+// sessionIDForPath returns the session ID monitored at the given file path.
+// Returns an empty string if the path is not a monitored session file.
+func (m *liveMonitor) sessionIDForPath(path string) string {
+	for sessionID, p := range m.sessionPaths {
+		if p == path {
+			return sessionID
+		}
+	}
+	return ""
+}
+
+// addEntries adds entries to the aggregator and keeps the newest
+// main-conversation entry; sub-agents have contexts of their own.
+// Must be called with m.mu held, except during the initial read.
+func (m *liveMonitor) addEntries(entries []parser.UsageEntry) {
+	for _, entry := range entries {
+		m.agg.Add(entry)
+		if !entry.IsSubAgent() && !entry.Timestamp.Before(m.latest.Timestamp) {
+			m.latest = entry
+		}
+	}
+}
+
+// recordChange accumulates per-session deltas for the next update.
+// Entries are attributed to the session owning the file; if the file is
+// unknown, each entry's own session ID is used instead.
+// Must be called with m.mu held.
+func (m *liveMonitor) recordChange(sessionID string, entries []parser.UsageEntry) {
+	for _, entry := range entries {
+		id := sessionID
+		if id == "" {
+			id = entry.SessionID
+		}
+
+		change, ok := m.pendingChanges[id]
+		if !ok {
+			change = &SessionDelta{SessionID: id}
+			m.pendingChanges[id] = change
+		}
+
+		change.NewEntries++
+		change.InputTokens += entry.Message.Usage.InputTokens
+		change.OutputTokens += entry.Message.Usage.OutputTokens
+		change.TotalTokens += entry.Message.Usage.TotalTokens()
 	}
 }
 
+// drainChanges returns the accumulated per-session deltas sorted by
+// session ID and clears them.
+// Must be called with m.mu held.
+func (m *liveMonitor) drainChanges() []SessionDelta {
+	if len(m.pendingChanges) == 0 {
+		return nil
+	}
+
+	changes := make([]SessionDelta, 0, len(m.pendingChanges))
+	for _, change := range m.pendingChanges {
+		changes = append(changes, *change)
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].SessionID < changes[j].SessionID
+	})
+
+	m.pendingChanges = make(map[string]*SessionDelta)
+	return changes
+}
+
 // sendUpdate sends a statistics update to the updates channel.
 func (m *liveMonitor) sendUpdate() {
 	m.mu.Lock()
@@ -362,6 +724,7 @@ func (m *liveMonitor) sendUpdate() {
 		InputTokens:  currentStats.InputTokens - m.lastStats.InputTokens,
 		OutputTokens: currentStats.OutputTokens - m.lastStats.OutputTokens,
 		TotalTokens:  currentStats.TotalTokens - m.lastStats.TotalTokens,
+		Errors:       currentStats.ErrorCount - m.lastStats.ErrorCount,
 	}
 
 	// Calculate cumulative (since monitor started)
@@ -370,6 +733,7 @@ func (m *liveMonitor) sendUpdate() {
 		InputTokens:  currentStats.InputTokens - m.initialStats.InputTokens,
 		OutputTokens: currentStats.OutputTokens - m.initialStats.OutputTokens,
 		TotalTokens:  currentStats.TotalTokens - m.initialStats.TotalTokens,
+		Errors:       currentStats.ErrorCount - m.initialStats.ErrorCount,
 	}
 
 	// Update lastDelta only if there was a change (for "now" display)
@@ -389,14 +753,22 @@ func (m *liveMonitor) sendUpdate() {
 
 	// Get current billing block
 	currentBlock := m.agg.CurrentBillingBlock(sessionID)
+	if !currentBlock.StartTime.Equal(m.peakBlockStart) {
+		m.peakBlock = aggregator.PeakBlockTokens(m.agg.BillingBlocks(sessionID))
+		m.peakBlockStart = currentBlock.StartTime
+	}
 
 	update := Update{
-		Timestamp:    time.Now(),
-		Stats:        currentStats,
-		Delta:        m.lastDelta, // Use last non-zero delta
-		Cumulative:   cumulative,
-		BurnRate:     burnRate,
-		CurrentBlock: currentBlock,
+		Timestamp:       time.Now(),
+		Stats:           currentStats,
+		Delta:           m.lastDelta, // Use last non-zero delta
+		Cumulative:      cumulative,
+		SessionID:       sessionID,
+		BurnRate:        burnRate,
+		CurrentBlock:    currentBlock,
+		PeakBlockTokens: m.peakBlock,
+		ChangedSessions: m.drainChanges(),
+		LatestEntry:     m.latest,
 	}
 
 	// Send update (non-blocking)
@@ -410,6 +782,31 @@ func (m *liveMonitor) sendUpdate() {
 	m.lastStats = currentStats
 }
 
+// DebugEvents returns the channel of watcher events and file reads.
+//
+// Events are dropped while the channel is full, so a consumer that only
+// needs recent events may fall behind without slowing the monitor. The
+// channel is closed when the monitor is closed.
+func (m *liveMonitor) DebugEvents() <-chan DebugEvent {
+	return m.debugEvents
+}
+
+// sendDebug sends a debug event to the debug channel, dropping it if the
+// channel is full.
+func (m *liveMonitor) sendDebug(event DebugEvent) {
+	event.Timestamp = time.Now()
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.closed {
+		return
+	}
+	select {
+	case m.debugEvents <- event:
+	default:
+	}
+}
+
 // Close closes the monitor and releases resources.
 func (m *liveMonitor) Close() error {
 	m.mu.Lock()
@@ -427,8 +824,9 @@ func (m *liveMonitor) Close() error {
 		m.running = false
 	}
 
-	// Close update channel
+	// Close update and debug channels
 	close(m.updates)
+	close(m.debugEvents)
 
 	m.logger.Info("live monitor closed")
 	return nil
@@ -447,6 +845,7 @@ func (m *liveMonitor) ResetStats() {
 	m.lastStats = aggregator.Statistics{}
 	m.initialStats = aggregator.Statistics{}
 	m.lastDelta = DeltaStats{}
+	m.pendingChanges = make(map[string]*SessionDelta)
 
 	m.logger.Info("statistics reset")
 }
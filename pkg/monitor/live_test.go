@@ -2,10 +2,12 @@ package monitor
 
 import (
 	"context"
+	"errors"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/0xmhha/token-monitor/pkg/aggregator"
 	"github.com/0xmhha/token-monitor/pkg/discovery"
 	"github.com/0xmhha/token-monitor/pkg/logger"
 	"github.com/0xmhha/token-monitor/pkg/parser"
@@ -104,6 +106,7 @@ func (m *mockWatcher) Paths() []string {
 type mockReader struct {
 	mu       sync.Mutex
 	entries  map[string][]parser.UsageEntry
+	reads    map[string]int
 	readErr  error
 	resetErr error
 	closed   bool
@@ -112,12 +115,14 @@ type mockReader struct {
 func newMockReader() *mockReader {
 	return &mockReader{
 		entries: make(map[string][]parser.UsageEntry),
+		reads:   make(map[string]int),
 	}
 }
 
 func (m *mockReader) Read(ctx context.Context, path string) ([]parser.UsageEntry, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	m.reads[path]++
 	if m.readErr != nil {
 		return nil, m.readErr
 	}
@@ -127,6 +132,14 @@ func (m *mockReader) Read(ctx context.Context, path string) ([]parser.UsageEntry
 	return entries, nil
 }
 
+func (m *mockReader) ReadTail(ctx context.Context, path string, n int) ([]parser.UsageEntry, error) {
+	entries, _, err := m.ReadFrom(ctx, path, 0)
+	if len(entries) > n {
+		entries = entries[len(entries)-n:]
+	}
+	return entries, err
+}
+
 func (m *mockReader) ReadFrom(ctx context.Context, path string, offset int64) ([]parser.UsageEntry, int64, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -151,6 +164,12 @@ func (m *mockReader) Close() error {
 	return nil
 }
 
+func (m *mockReader) Reads(path string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.reads[path]
+}
+
 func (m *mockReader) SetEntries(path string, entries []parser.UsageEntry) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -190,6 +209,10 @@ func (m *mockDiscovery) DiscoverProject(projectPath string) ([]discovery.Session
 	return filtered, nil
 }
 
+func (m *mockDiscovery) Duplicates() []discovery.Duplicate {
+	return nil
+}
+
 func (m *mockDiscovery) FindCurrentSession() (*discovery.SessionFile, error) {
 	if m.discoverErr != nil {
 		return nil, m.discoverErr
@@ -241,6 +264,18 @@ func TestNew(t *testing.T) {
 		assert.Equal(t, 5*time.Second, lm.config.RefreshInterval)
 	})
 
+	t.Run("skips percentiles when configured", func(t *testing.T) {
+		mon, err := New(Config{SkipPercentiles: true}, w, r, d, log)
+		require.NoError(t, err)
+		lm := mon.(*liveMonitor)
+		for i := 1; i <= 10; i++ {
+			lm.agg.Add(createTestEntry("session-1", i*100))
+		}
+		stats := mon.Stats()
+		assert.Equal(t, 10, stats.Count)
+		assert.Zero(t, stats.P50Tokens)
+	})
+
 	t.Run("accepts session filter", func(t *testing.T) {
 		sessionIDs := []string{"session-1", "session-2"}
 		mon, err := New(Config{SessionIDs: sessionIDs}, w, r, d, log)
@@ -604,6 +639,113 @@ func TestUpdates(t *testing.T) {
 	})
 }
 
+func TestChangedSessions(t *testing.T) {
+	log := logger.New(logger.Config{Level: "error"})
+
+	t.Run("attributes file changes to sessions", func(t *testing.T) {
+		w := newMockWatcher()
+		r := newMockReader()
+		sessions := []discovery.SessionFile{
+			{SessionID: "session-1", FilePath: "/path/to/session1.jsonl"},
+			{SessionID: "session-2", FilePath: "/path/to/session2.jsonl"},
+		}
+		d := newMockDiscovery(sessions)
+
+		mon, err := New(Config{RefreshInterval: time.Hour}, w, r, d, log)
+		require.NoError(t, err)
+		lm := mon.(*liveMonitor)
+		require.NoError(t, mon.Start())
+		defer func() { _ = mon.Stop() }() // Ignore error in test cleanup
+
+		// Drain initial update
+		initial := <-lm.Updates()
+		assert.Empty(t, initial.ChangedSessions)
+
+		r.SetEntries("/path/to/session2.jsonl", []parser.UsageEntry{
+			createTestEntry("session-2", 100),
+			createTestEntry("session-2", 50),
+		})
+		r.SetEntries("/path/to/session1.jsonl", []parser.UsageEntry{
+			createTestEntry("session-1", 20),
+		})
+		lm.handleFileChange(context.Background(), watcher.Event{Path: "/path/to/session2.jsonl"})
+		first := <-lm.Updates()
+		require.Len(t, first.ChangedSessions, 1)
+		assert.Equal(t, SessionDelta{
+			SessionID:    "session-2",
+			NewEntries:   2,
+			InputTokens:  75,
+			OutputTokens: 75,
+			TotalTokens:  150,
+		}, first.ChangedSessions[0])
+
+		lm.handleFileChange(context.Background(), watcher.Event{Path: "/path/to/session1.jsonl"})
+		second := <-lm.Updates()
+		require.Len(t, second.ChangedSessions, 1)
+		assert.Equal(t, "session-1", second.ChangedSessions[0].SessionID)
+		assert.Equal(t, 20, second.ChangedSessions[0].TotalTokens)
+	})
+
+	t.Run("sorts multiple changed sessions", func(t *testing.T) {
+		lm := &liveMonitor{pendingChanges: make(map[string]*SessionDelta)}
+		lm.recordChange("session-b", []parser.UsageEntry{createTestEntry("session-b", 10)})
+		lm.recordChange("", []parser.UsageEntry{createTestEntry("session-a", 4)})
+
+		changes := lm.drainChanges()
+		require.Len(t, changes, 2)
+		assert.Equal(t, "session-a", changes[0].SessionID)
+		assert.Equal(t, "session-b", changes[1].SessionID)
+		assert.Nil(t, lm.drainChanges())
+	})
+}
+
+func TestLatestEntry(t *testing.T) {
+	lm := &liveMonitor{agg: aggregator.New(aggregator.Config{})}
+	start := time.Date(2025, 11, 3, 10, 0, 0, 0, time.UTC)
+
+	main := createTestEntry("session-1", 100)
+	main.Timestamp = start.Add(time.Minute)
+	older := createTestEntry("session-2", 300)
+	older.Timestamp = start
+	subAgent := createTestEntry("session-1", 50)
+	subAgent.Timestamp = start.Add(2 * time.Minute)
+	subAgent.IsSidechain = true
+
+	lm.addEntries([]parser.UsageEntry{main, older, subAgent})
+	assert.Equal(t, main, lm.latest, "latest = newest entry outside sub-agents")
+	assert.Equal(t, 3, lm.agg.Stats().Count)
+}
+
+func TestModelFilter(t *testing.T) {
+	log := logger.New(logger.Config{Level: "error"})
+
+	w := newMockWatcher()
+	r := newMockReader()
+	sessions := []discovery.SessionFile{
+		{SessionID: "session-1", FilePath: "/path/to/session1.jsonl"},
+	}
+	d := newMockDiscovery(sessions)
+
+	opus := createTestEntry("session-1", 100)
+	opus.Message.Model = "claude-opus-4-7"
+	r.SetEntries("/path/to/session1.jsonl", []parser.UsageEntry{
+		createTestEntry("session-1", 40),
+		opus,
+	})
+
+	filter, err := aggregator.ParseModelFilter("*opus*")
+	require.NoError(t, err)
+
+	mon, err := New(Config{RefreshInterval: time.Hour, ModelFilter: filter}, w, r, d, log)
+	require.NoError(t, err)
+	require.NoError(t, mon.Start())
+	defer func() { _ = mon.Stop() }() // Ignore error in test cleanup
+
+	stats := mon.Stats()
+	assert.Equal(t, 1, stats.Count)
+	assert.Equal(t, 100, stats.TotalTokens)
+}
+
 func TestConcurrency(t *testing.T) {
 	log := logger.New(logger.Config{Level: "error"})
 
@@ -638,6 +780,248 @@ func TestConcurrency(t *testing.T) {
 	})
 }
 
+func TestBatchWindow(t *testing.T) {
+	log := logger.New(logger.Config{Level: "error"})
+
+	t.Run("reads each changed file once per window", func(t *testing.T) {
+		w := newMockWatcher()
+		r := newMockReader()
+		sessions := []discovery.SessionFile{
+			{SessionID: "session-1", FilePath: "/path/to/session1.jsonl"},
+			{SessionID: "session-2", FilePath: "/path/to/session2.jsonl"},
+		}
+		d := newMockDiscovery(sessions)
+
+		mon, err := New(Config{
+			RefreshInterval: time.Hour,
+			BatchWindow:     50 * time.Millisecond,
+			Workers:         2,
+		}, w, r, d, log)
+		require.NoError(t, err)
+
+		go func() {
+			_ = mon.Start() // Error handled by monitor
+		}()
+
+		updates := mon.(*liveMonitor).Updates()
+		select {
+		case <-updates:
+		case <-time.After(200 * time.Millisecond):
+			t.Fatal("did not receive initial update")
+		}
+
+		r.SetEntries("/path/to/session1.jsonl", []parser.UsageEntry{
+			createTestEntry("session-1", 100),
+		})
+		r.SetEntries("/path/to/session2.jsonl", []parser.UsageEntry{
+			createTestEntry("session-2", 200),
+		})
+		for i := 0; i < 3; i++ {
+			w.events <- watcher.Event{Path: "/path/to/session1.jsonl", Op: watcher.OpWrite}
+		}
+		w.events <- watcher.Event{Path: "/path/to/session2.jsonl", Op: watcher.OpWrite}
+
+		select {
+		case update := <-updates:
+			assert.Equal(t, 2, update.Delta.NewEntries)
+			require.Len(t, update.ChangedSessions, 2)
+			assert.Equal(t, "session-1", update.ChangedSessions[0].SessionID)
+			assert.Equal(t, "session-2", update.ChangedSessions[1].SessionID)
+		case <-time.After(300 * time.Millisecond):
+			t.Fatal("did not receive batched update")
+		}
+
+		select {
+		case <-updates:
+			t.Error("received more than one update for a single batch")
+		case <-time.After(100 * time.Millisecond):
+		}
+
+		// One initial read plus one read for the batch.
+		assert.Equal(t, 2, r.Reads("/path/to/session1.jsonl"))
+		assert.Equal(t, 2, r.Reads("/path/to/session2.jsonl"))
+
+		_ = mon.Stop() // Ignore error in test cleanup
+	})
+}
+
+func TestReadRetry(t *testing.T) {
+	log := logger.New(logger.Config{Level: "error"})
+
+	t.Run("re-reads a file whose write had no entries yet", func(t *testing.T) {
+		w := newMockWatcher()
+		r := newMockReader()
+		path := "/path/to/session1.jsonl"
+		d := newMockDiscovery([]discovery.SessionFile{{SessionID: "session-1", FilePath: path}})
+
+		mon, err := New(Config{
+			RefreshInterval: time.Hour,
+			ReadRetryDelay:  50 * time.Millisecond,
+			ReadRetries:     2,
+		}, w, r, d, log)
+		require.NoError(t, err)
+
+		go func() {
+			_ = mon.Start() // Error handled by monitor
+		}()
+
+		updates := mon.(*liveMonitor).Updates()
+		select {
+		case <-updates:
+		case <-time.After(200 * time.Millisecond):
+			t.Fatal("did not receive initial update")
+		}
+
+		// The write event arrives before the usage line is flushed.
+		w.events <- watcher.Event{Path: path, Op: watcher.OpWrite}
+		require.Eventually(t, func() bool { return r.Reads(path) == 2 },
+			200*time.Millisecond, 5*time.Millisecond)
+		r.SetEntries(path, []parser.UsageEntry{createTestEntry("session-1", 100)})
+
+		select {
+		case update := <-updates:
+			assert.Equal(t, 1, update.Delta.NewEntries)
+		case <-time.After(300 * time.Millisecond):
+			t.Fatal("did not receive update from the re-read")
+		}
+
+		// Entries were found, so there are no further re-reads.
+		time.Sleep(150 * time.Millisecond)
+		assert.Equal(t, 3, r.Reads(path))
+
+		_ = mon.Stop() // Ignore error in test cleanup
+	})
+
+	t.Run("gives up after the retry limit", func(t *testing.T) {
+		retry := newReadRetry(time.Millisecond, 2)
+		paths := []string{"a", "b"}
+
+		assert.True(t, retry.schedule(paths, paths))
+		assert.Equal(t, paths, retry.take())
+		assert.True(t, retry.schedule(paths, []string{"b"}))
+		assert.Equal(t, []string{"b"}, retry.take())
+		assert.False(t, retry.schedule([]string{"b"}, []string{"b"}), "b is past the limit")
+		assert.Empty(t, retry.take())
+
+		// A later write starts over.
+		assert.True(t, retry.schedule([]string{"b"}, []string{"b"}))
+	})
+
+	t.Run("disabled without a delay", func(t *testing.T) {
+		retry := newReadRetry(0, 2)
+		assert.False(t, retry.schedule([]string{"a"}, []string{"a"}))
+	})
+}
+
+func TestDebugEvents(t *testing.T) {
+	log := logger.New(logger.Config{Level: "error"})
+
+	w := newMockWatcher()
+	r := newMockReader()
+	sessions := []discovery.SessionFile{
+		{SessionID: "session-1", FilePath: "/path/to/session1.jsonl"},
+	}
+	d := newMockDiscovery(sessions)
+	r.SetEntries("/path/to/session1.jsonl", []parser.UsageEntry{
+		createTestEntry("session-1", 100),
+		createTestEntry("session-1", 200),
+	})
+
+	mon, err := New(Config{RefreshInterval: time.Hour}, w, r, d, log)
+	require.NoError(t, err)
+
+	go func() {
+		_ = mon.Start() // Error handled by monitor
+	}()
+
+	lm := mon.(*liveMonitor)
+	next := func() DebugEvent {
+		t.Helper()
+		select {
+		case event := <-lm.DebugEvents():
+			return event
+		case <-time.After(200 * time.Millisecond):
+			t.Fatal("did not receive debug event")
+			return DebugEvent{}
+		}
+	}
+
+	initial := next()
+	assert.Equal(t, DebugRead, initial.Kind)
+	assert.Equal(t, "/path/to/session1.jsonl", initial.Path)
+	assert.Equal(t, 2, initial.Entries)
+	assert.NoError(t, initial.Err)
+
+	r.SetEntries("/path/to/session1.jsonl", []parser.UsageEntry{
+		createTestEntry("session-1", 300),
+	})
+	w.events <- watcher.Event{Path: "/path/to/session1.jsonl", Op: watcher.OpWrite}
+
+	event := next()
+	assert.Equal(t, DebugWatch, event.Kind)
+	assert.Equal(t, watcher.OpWrite, event.Op)
+	assert.False(t, event.Timestamp.IsZero())
+
+	read := next()
+	assert.Equal(t, DebugRead, read.Kind)
+	assert.Equal(t, 1, read.Entries)
+
+	w.errors <- errors.New("too many open files")
+	failed := next()
+	assert.Equal(t, DebugWatchError, failed.Kind)
+	assert.EqualError(t, failed.Err, "too many open files")
+
+	require.NoError(t, lm.Close())
+	_, ok := <-lm.DebugEvents()
+	assert.False(t, ok, "debug channel should be closed")
+}
+
+func TestPollBackoff(t *testing.T) {
+	start := time.Now()
+	tick := func(n int) time.Time { return start.Add(time.Duration(n) * time.Second) }
+
+	t.Run("backs off while idle", func(t *testing.T) {
+		p := newPollBackoff(time.Second, 4*time.Second, start)
+
+		var polls []int
+		for n := 1; n <= 16; n++ {
+			if p.due(tick(n), time.Time{}) {
+				polls = append(polls, n)
+				p.record(tick(n), false)
+			}
+		}
+		// Intervals 1s, 2s, 4s, then capped at 4s.
+		assert.Equal(t, []int{1, 3, 7, 11, 15}, polls)
+	})
+
+	t.Run("resets when entries are found", func(t *testing.T) {
+		p := newPollBackoff(time.Second, 8*time.Second, start)
+		p.record(tick(1), false)
+		p.record(tick(3), false)
+		assert.Equal(t, 4*time.Second, p.interval)
+
+		p.record(tick(7), true)
+		assert.Equal(t, time.Second, p.interval)
+		assert.True(t, p.due(tick(8), time.Time{}))
+	})
+
+	t.Run("resets on watcher activity", func(t *testing.T) {
+		p := newPollBackoff(time.Second, 8*time.Second, start)
+		p.record(tick(1), false)
+		p.record(tick(3), false)
+		assert.False(t, p.due(tick(4), time.Time{}))
+		assert.True(t, p.due(tick(4), tick(3).Add(time.Millisecond)))
+	})
+
+	t.Run("max below refresh polls every tick", func(t *testing.T) {
+		p := newPollBackoff(time.Second, 0, start)
+		for n := 1; n <= 3; n++ {
+			assert.True(t, p.due(tick(n), time.Time{}))
+			p.record(tick(n), false)
+		}
+	})
+}
+
 func TestClose(t *testing.T) {
 	log := logger.New(logger.Config{Level: "error"})
 
@@ -4,6 +4,8 @@ import (
 	"time"
 
 	"github.com/0xmhha/token-monitor/pkg/aggregator"
+	"github.com/0xmhha/token-monitor/pkg/parser"
+	"github.com/0xmhha/token-monitor/pkg/watcher"
 )
 
 // Config holds the configuration for the live monitor.
@@ -14,8 +16,35 @@ type Config struct {
 	// RefreshInterval is the interval between display updates
 	RefreshInterval time.Duration
 
+	// MaxPollInterval caps the periodic re-read interval, which doubles
+	// while sessions are idle and resets to RefreshInterval on new entries
+	// (values at or below RefreshInterval re-read on every refresh)
+	MaxPollInterval time.Duration
+
 	// ClearScreen enables clearing the terminal between updates
 	ClearScreen bool
+
+	// ModelFilter restricts monitoring to matching models (nil means all models)
+	ModelFilter *aggregator.ModelFilter
+
+	// BatchWindow collects file change events for this long and reads each
+	// changed file once per window (0 processes every event immediately)
+	BatchWindow time.Duration
+
+	// ReadRetryDelay is how long to wait before re-reading a file whose
+	// write event produced no new entries, as usage lines can land a few
+	// moments after the write that announced them (0 disables re-reads)
+	ReadRetryDelay time.Duration
+
+	// ReadRetries is how many times such a file is re-read before giving up
+	ReadRetries int
+
+	// Workers is the number of files read concurrently (values below 1 mean 1)
+	Workers int
+
+	// SkipPercentiles disables percentile tracking, which keeps every
+	// entry's token count and sorts them on each update
+	SkipPercentiles bool
 }
 
 // LiveMonitor provides real-time token usage monitoring.
@@ -52,6 +81,20 @@ type Update struct {
 
 	// CurrentBlock contains the current billing block stats
 	CurrentBlock aggregator.BillingBlock
+
+	// PeakBlockTokens is the highest token total of an earlier billing
+	// block, the reference for the burn-down bar when no block quota is
+	// configured (0 without earlier blocks)
+	PeakBlockTokens int
+
+	// ChangedSessions lists the sessions that received new entries since
+	// the last update, sorted by session ID (empty if nothing changed)
+	ChangedSessions []SessionDelta
+
+	// LatestEntry is the newest entry of a main conversation (not a
+	// sub-agent), the basis of context window estimates (zero before the
+	// first entry)
+	LatestEntry parser.UsageEntry
 }
 
 // DeltaStats represents changes since the last update.
@@ -67,4 +110,58 @@ type DeltaStats struct {
 
 	// TotalTokens added since last update
 	TotalTokens int
+
+	// Errors is the number of new entries recording a failed request
+	Errors int
+}
+
+// SessionDelta represents the changes attributed to a single session since
+// the last update.
+type SessionDelta struct {
+	// SessionID of the session that changed
+	SessionID string
+
+	// NewEntries is the number of new entries read for the session
+	NewEntries int
+
+	// InputTokens added to the session since last update
+	InputTokens int
+
+	// OutputTokens added to the session since last update
+	OutputTokens int
+
+	// TotalTokens added to the session since last update
+	TotalTokens int
+}
+
+// DebugKind identifies what a DebugEvent records.
+type DebugKind int
+
+// Debug event kinds.
+const (
+	DebugWatch      DebugKind = iota // A file event from the watcher
+	DebugRead                        // A read of a session file
+	DebugWatchError                  // An error from the watcher
+)
+
+// DebugEvent records a watcher event or file read, for diagnosing a
+// monitor that is not picking up changes.
+type DebugEvent struct {
+	// Timestamp of the event
+	Timestamp time.Time
+
+	// Kind of the event
+	Kind DebugKind
+
+	// Path of the file (empty for watcher errors)
+	Path string
+
+	// Op is the watcher operation of a DebugWatch event
+	Op watcher.Op
+
+	// Entries is the number of entries a DebugRead parsed after the model filter
+	Entries int
+
+	// Err is the error of a failed read or a DebugWatchError
+	Err error
 }
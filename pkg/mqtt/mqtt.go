@@ -0,0 +1,254 @@
+// Package mqtt publishes messages to an MQTT broker.
+//
+// It implements the part of MQTT 3.1.1 needed to publish status updates:
+// CONNECT with optional credentials, PUBLISH at QoS 0, and DISCONNECT.
+// Subscriptions and higher QoS levels are not supported.
+//
+// Example usage:
+//
+//	client, err := mqtt.Dial(mqtt.Config{Broker: "tcp://localhost:1883"})
+//	if err != nil {
+//	    return err
+//	}
+//	defer client.Close()
+//	err = client.Publish("token-monitor/status", payload, true)
+package mqtt
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"time"
+)
+
+// Default ports by transport.
+const (
+	defaultPort    = "1883"
+	defaultTLSPort = "8883"
+)
+
+// defaultDialTimeout bounds connecting and the CONNACK exchange.
+const defaultDialTimeout = 5 * time.Second
+
+// writeTimeout bounds sending one packet, so a stalled broker cannot
+// block the publisher.
+const writeTimeout = 5 * time.Second
+
+// Packet types (fixed header, upper nibble).
+const (
+	packetConnect    = 0x10
+	packetConnAck    = 0x20
+	packetPublish    = 0x30
+	packetDisconnect = 0xE0
+)
+
+// maxRemainingLength is the largest packet body MQTT can encode.
+const maxRemainingLength = 268435455
+
+var (
+	// ErrInvalidBroker is returned when the broker URL is malformed.
+	ErrInvalidBroker = errors.New("invalid MQTT broker URL")
+
+	// ErrConnectionRefused is returned when the broker rejects CONNECT.
+	ErrConnectionRefused = errors.New("MQTT connection refused")
+)
+
+// Config contains connection settings.
+type Config struct {
+	// Broker is the broker URL: tcp:// or mqtt:// for plain TCP, ssl:// or
+	// mqtts:// for TLS. The port defaults to 1883 or 8883.
+	Broker string
+
+	// ClientID identifies the client to the broker. Empty lets the broker
+	// assign one.
+	ClientID string
+
+	// Username and Password are sent when Username is set.
+	Username string
+	Password string
+
+	// DialTimeout bounds connecting (default: 5s).
+	DialTimeout time.Duration
+}
+
+// Client is a connection to a broker.
+//
+// Thread-safety: A Client must not be used concurrently.
+type Client struct {
+	conn net.Conn
+}
+
+// ParseBroker returns the host:port to dial and whether to use TLS.
+func ParseBroker(broker string) (addr string, useTLS bool, err error) {
+	u, err := url.Parse(broker)
+	if err != nil || u.Host == "" {
+		return "", false, fmt.Errorf("%w: %q (want e.g. tcp://host:1883)", ErrInvalidBroker, broker)
+	}
+
+	port := defaultPort
+	switch u.Scheme {
+	case "tcp", "mqtt":
+	case "ssl", "mqtts":
+		useTLS = true
+		port = defaultTLSPort
+	default:
+		return "", false, fmt.Errorf("%w: unsupported scheme %q", ErrInvalidBroker, u.Scheme)
+	}
+	if u.Port() != "" {
+		port = u.Port()
+	}
+	return net.JoinHostPort(u.Hostname(), port), useTLS, nil
+}
+
+// Dial connects to the broker and completes the CONNECT handshake.
+func Dial(cfg Config) (*Client, error) {
+	addr, useTLS, err := ParseBroker(cfg.Broker)
+	if err != nil {
+		return nil, err
+	}
+	timeout := cfg.DialTimeout
+	if timeout <= 0 {
+		timeout = defaultDialTimeout
+	}
+
+	dialer := &net.Dialer{Timeout: timeout}
+	var conn net.Conn
+	if useTLS {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, &tls.Config{MinVersion: tls.VersionTLS12})
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", addr, err)
+	}
+
+	c := &Client{conn: conn}
+	if err := c.connect(cfg, timeout); err != nil {
+		_ = conn.Close() //nolint:errcheck // already failing
+		return nil, err
+	}
+	return c, nil
+}
+
+// connect sends CONNECT and waits for CONNACK.
+func (c *Client) connect(cfg Config, timeout time.Duration) error {
+	// Variable header: protocol name, level 4 (3.1.1), flags, keep alive.
+	// A keep alive of 0 disables the broker's idle timeout, so no pings
+	// are needed between infrequent publishes.
+	flags := byte(0x02) // clean session
+	body := appendString(nil, "MQTT")
+	body = append(body, 4)
+	flagsAt := len(body)
+	body = append(body, flags, 0, 0)
+
+	body = appendString(body, cfg.ClientID)
+	if cfg.Username != "" {
+		body[flagsAt] |= 0x80
+		body = appendString(body, cfg.Username)
+		if cfg.Password != "" {
+			body[flagsAt] |= 0x40
+			body = appendString(body, cfg.Password)
+		}
+	}
+
+	if err := c.conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		return err
+	}
+	defer func() {
+		_ = c.conn.SetDeadline(time.Time{}) //nolint:errcheck // best effort reset
+	}()
+
+	if err := c.write(packetConnect, body); err != nil {
+		return fmt.Errorf("failed to send CONNECT: %w", err)
+	}
+
+	var ack [4]byte
+	if _, err := io.ReadFull(c.conn, ack[:]); err != nil {
+		return fmt.Errorf("failed to read CONNACK: %w", err)
+	}
+	if ack[0] != packetConnAck || ack[1] != 2 {
+		return fmt.Errorf("unexpected reply to CONNECT: %#x", ack[0])
+	}
+	if code := ack[3]; code != 0 {
+		return fmt.Errorf("%w: %s", ErrConnectionRefused, connAckReason(code))
+	}
+	return nil
+}
+
+// Publish sends payload to topic at QoS 0. With retain, the broker keeps
+// the message for clients that subscribe later.
+func (c *Client) Publish(topic string, payload []byte, retain bool) error {
+	header := byte(packetPublish)
+	if retain {
+		header |= 0x01
+	}
+	body := appendString(nil, topic)
+	body = append(body, payload...)
+	if err := c.write(header, body); err != nil {
+		return fmt.Errorf("failed to publish to %s: %w", topic, err)
+	}
+	return nil
+}
+
+// Close sends DISCONNECT and closes the connection.
+func (c *Client) Close() error {
+	_ = c.write(packetDisconnect, nil) //nolint:errcheck // closing anyway
+	return c.conn.Close()
+}
+
+// write sends one packet.
+func (c *Client) write(header byte, body []byte) error {
+	if len(body) > maxRemainingLength {
+		return fmt.Errorf("packet too large: %d bytes", len(body))
+	}
+	packet := append([]byte{header}, encodeLength(len(body))...)
+	packet = append(packet, body...)
+	if err := c.conn.SetWriteDeadline(time.Now().Add(writeTimeout)); err != nil {
+		return err
+	}
+	_, err := c.conn.Write(packet)
+	return err
+}
+
+// encodeLength encodes a remaining length as MQTT's variable-length integer.
+func encodeLength(n int) []byte {
+	var out []byte
+	for {
+		b := byte(n % 128)
+		n /= 128
+		if n > 0 {
+			b |= 0x80
+		}
+		out = append(out, b)
+		if n == 0 {
+			return out
+		}
+	}
+}
+
+// appendString appends s with its 16-bit length prefix.
+func appendString(b []byte, s string) []byte {
+	b = append(b, byte(len(s)>>8), byte(len(s)))
+	return append(b, s...)
+}
+
+// connAckReason describes a CONNACK return code.
+func connAckReason(code byte) string {
+	switch code {
+	case 1:
+		return "unacceptable protocol version"
+	case 2:
+		return "client identifier rejected"
+	case 3:
+		return "server unavailable"
+	case 4:
+		return "bad user name or password"
+	case 5:
+		return "not authorized"
+	default:
+		return fmt.Sprintf("return code %d", code)
+	}
+}
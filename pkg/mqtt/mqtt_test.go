@@ -0,0 +1,154 @@
+package mqtt
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net"
+	"testing"
+)
+
+// fakeBroker accepts one connection, answers CONNECT with code, and
+// returns every packet it receives until the client disconnects.
+func fakeBroker(t *testing.T, code byte) (string, <-chan [][]byte) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+
+	packets := make(chan [][]byte, 1)
+	go func() {
+		var got [][]byte
+		defer func() { packets <- got }()
+
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+		for {
+			packet, err := readPacket(conn)
+			if err != nil {
+				return
+			}
+			got = append(got, packet)
+			if packet[0] == packetConnect {
+				_, _ = conn.Write([]byte{packetConnAck, 2, 0, code})
+			}
+		}
+	}()
+	return "tcp://" + ln.Addr().String(), packets
+}
+
+// readPacket reads one packet: the header byte followed by the body.
+func readPacket(r io.Reader) ([]byte, error) {
+	var header [1]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, err
+	}
+	length, multiplier := 0, 1
+	for {
+		var b [1]byte
+		if _, err := io.ReadFull(r, b[:]); err != nil {
+			return nil, err
+		}
+		length += int(b[0]&0x7f) * multiplier
+		multiplier *= 128
+		if b[0]&0x80 == 0 {
+			break
+		}
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return nil, err
+	}
+	return append(header[:], body...), nil
+}
+
+func TestPublish(t *testing.T) {
+	broker, packets := fakeBroker(t, 0)
+
+	client, err := Dial(Config{Broker: broker, ClientID: "tm", Username: "u", Password: "p"})
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	if err := client.Publish("token-monitor/status", []byte(`{"burn_rate":1}`), true); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if err := client.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	got := <-packets
+	if len(got) != 3 {
+		t.Fatalf("broker got %d packets, want CONNECT, PUBLISH, DISCONNECT", len(got))
+	}
+
+	connect := got[0]
+	wantConnect := []byte{packetConnect, 0, 4, 'M', 'Q', 'T', 'T', 4, 0xC2, 0, 0, 0, 2, 't', 'm', 0, 1, 'u', 0, 1, 'p'}
+	if !bytes.Equal(connect, wantConnect) {
+		t.Errorf("CONNECT = %v, want %v", connect, wantConnect)
+	}
+
+	publish := got[1]
+	wantPublish := append([]byte{packetPublish | 0x01, 0, 20}, "token-monitor/status"...)
+	wantPublish = append(wantPublish, `{"burn_rate":1}`...)
+	if !bytes.Equal(publish, wantPublish) {
+		t.Errorf("PUBLISH = %q, want %q", publish, wantPublish)
+	}
+
+	if got[2][0] != packetDisconnect {
+		t.Errorf("last packet = %#x, want DISCONNECT", got[2][0])
+	}
+}
+
+func TestDialRefused(t *testing.T) {
+	broker, _ := fakeBroker(t, 5)
+
+	_, err := Dial(Config{Broker: broker})
+	if !errors.Is(err, ErrConnectionRefused) {
+		t.Errorf("Dial() error = %v, want ErrConnectionRefused", err)
+	}
+}
+
+func TestParseBroker(t *testing.T) {
+	tests := []struct {
+		broker  string
+		addr    string
+		useTLS  bool
+		wantErr bool
+	}{
+		{"tcp://localhost", "localhost:1883", false, false},
+		{"mqtt://ha.local:1884", "ha.local:1884", false, false},
+		{"mqtts://broker.example.com", "broker.example.com:8883", true, false},
+		{"http://localhost", "", false, true},
+		{"localhost:1883", "", false, true},
+	}
+	for _, tt := range tests {
+		addr, useTLS, err := ParseBroker(tt.broker)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseBroker(%q) error = %v, wantErr %v", tt.broker, err, tt.wantErr)
+			continue
+		}
+		if addr != tt.addr || useTLS != tt.useTLS {
+			t.Errorf("ParseBroker(%q) = %s, %v; want %s, %v", tt.broker, addr, useTLS, tt.addr, tt.useTLS)
+		}
+	}
+}
+
+func TestEncodeLength(t *testing.T) {
+	tests := map[int][]byte{
+		0:       {0},
+		127:     {0x7f},
+		128:     {0x80, 0x01},
+		16383:   {0xff, 0x7f},
+		2097152: {0x80, 0x80, 0x80, 0x01},
+	}
+	for n, want := range tests {
+		if got := encodeLength(n); !bytes.Equal(got, want) {
+			t.Errorf("encodeLength(%d) = %v, want %v", n, got, want)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// defaultDiscordTimeout bounds one webhook request.
+const defaultDiscordTimeout = 10 * time.Second
+
+// Discord limits embed text; longer values are truncated.
+const (
+	discordTitleLimit       = 256
+	discordDescriptionLimit = 4096
+	discordFieldNameLimit   = 256
+	discordFieldValueLimit  = 1024
+	discordFieldCountLimit  = 25
+)
+
+// discordColors maps levels to embed sidebar colors.
+var discordColors = map[Level]int{
+	LevelInfo:     0x5865F2, // blurple
+	LevelWarning:  0xFEE75C, // yellow
+	LevelCritical: 0xED4245, // red
+}
+
+// DiscordConfig contains Discord webhook settings.
+type DiscordConfig struct {
+	// WebhookURL is the channel webhook URL
+	// (https://discord.com/api/webhooks/<id>/<token>).
+	WebhookURL string
+
+	// Username overrides the webhook's display name when set.
+	Username string
+
+	// Client sends the requests (default: a client with a 10s timeout).
+	Client *http.Client
+}
+
+// Discord posts messages as embeds to a Discord channel webhook.
+type Discord struct {
+	cfg DiscordConfig
+}
+
+// NewDiscord returns a notifier for a Discord channel webhook.
+func NewDiscord(cfg DiscordConfig) *Discord {
+	if cfg.Client == nil {
+		cfg.Client = &http.Client{Timeout: defaultDiscordTimeout}
+	}
+	return &Discord{cfg: cfg}
+}
+
+// discordPayload is the webhook request body.
+type discordPayload struct {
+	Username string         `json:"username,omitempty"`
+	Embeds   []discordEmbed `json:"embeds"`
+}
+
+// discordEmbed is a rich embed.
+type discordEmbed struct {
+	Title       string              `json:"title,omitempty"`
+	Description string              `json:"description,omitempty"`
+	Color       int                 `json:"color,omitempty"`
+	Fields      []discordEmbedField `json:"fields,omitempty"`
+	Timestamp   string              `json:"timestamp,omitempty"`
+	Footer      *discordEmbedFooter `json:"footer,omitempty"`
+}
+
+// discordEmbedField is an embed field.
+type discordEmbedField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline,omitempty"`
+}
+
+// discordEmbedFooter is an embed footer.
+type discordEmbedFooter struct {
+	Text string `json:"text"`
+}
+
+// Notify implements Notifier.
+func (d *Discord) Notify(ctx context.Context, msg Message) error {
+	body, err := json.Marshal(d.payload(msg))
+	if err != nil {
+		return fmt.Errorf("failed to encode Discord message: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.cfg.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("invalid Discord webhook URL: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := d.cfg.Client.Do(req)
+	if err != nil {
+		// The URL embeds the webhook token; keep it out of the error.
+		return fmt.Errorf("%w: Discord webhook request failed", ErrDeliveryFailed)
+	}
+	defer func() {
+		_ = resp.Body.Close() //nolint:errcheck // best effort cleanup
+	}()
+
+	if resp.StatusCode/100 != 2 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512)) //nolint:errcheck // best effort detail
+		return fmt.Errorf("%w: Discord returned %s: %s", ErrDeliveryFailed, resp.Status, strings.TrimSpace(string(detail)))
+	}
+	return nil
+}
+
+// payload renders msg as a single embed.
+func (d *Discord) payload(msg Message) discordPayload {
+	at := msg.Time
+	if at.IsZero() {
+		at = time.Now()
+	}
+
+	embed := discordEmbed{
+		Title:       truncate(msg.Title, discordTitleLimit),
+		Description: truncate(msg.Text, discordDescriptionLimit),
+		Color:       discordColors[msg.Level],
+		Timestamp:   at.UTC().Format(time.RFC3339),
+		Footer:      &discordEmbedFooter{Text: "token-monitor"},
+	}
+	for i, f := range msg.Fields {
+		if i == discordFieldCountLimit {
+			break
+		}
+		embed.Fields = append(embed.Fields, discordEmbedField{
+			Name:   truncate(f.Name, discordFieldNameLimit),
+			Value:  truncate(f.Value, discordFieldValueLimit),
+			Inline: f.Inline,
+		})
+	}
+	return discordPayload{Username: d.cfg.Username, Embeds: []discordEmbed{embed}}
+}
+
+// truncate shortens s to at most limit runes, marking the cut with an
+// ellipsis.
+func truncate(s string, limit int) string {
+	runes := []rune(s)
+	if len(runes) <= limit {
+		return s
+	}
+	return string(runes[:limit-1]) + "…"
+}
@@ -0,0 +1,71 @@
+// Package notify sends usage notifications, such as alerts and daily
+// summaries, to chat services.
+//
+// A Message is service-neutral; each backend renders it in its own
+// format. Discord renders messages as rich embeds through a channel
+// webhook.
+//
+// Example usage:
+//
+//	n := notify.NewDiscord(notify.DiscordConfig{WebhookURL: url})
+//	err := n.Notify(ctx, notify.Message{
+//	    Title: "Daily usage: 2025-11-30",
+//	    Level: notify.LevelInfo,
+//	    Fields: []notify.Field{{Name: "Cost", Value: "$4.12", Inline: true}},
+//	})
+package notify
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Level is the severity of a message.
+type Level string
+
+// Message levels.
+const (
+	// LevelInfo is for reports such as daily summaries.
+	LevelInfo Level = "info"
+
+	// LevelWarning is for alerts that need attention soon.
+	LevelWarning Level = "warning"
+
+	// LevelCritical is for alerts that need attention now.
+	LevelCritical Level = "critical"
+)
+
+// ErrDeliveryFailed is returned when a service rejects a message.
+var ErrDeliveryFailed = errors.New("notification delivery failed")
+
+// Field is a labeled value shown with a message.
+type Field struct {
+	Name  string
+	Value string
+
+	// Inline lets the backend lay out consecutive fields side by side.
+	Inline bool
+}
+
+// Message is one notification.
+type Message struct {
+	Title string
+
+	// Text is the message body; it may be empty when Fields say it all.
+	Text string
+
+	Level  Level
+	Fields []Field
+
+	// Time is when the reported event happened (default: now).
+	Time time.Time
+}
+
+// Notifier delivers messages to one destination.
+//
+// Thread-safety: Implementations must be safe for concurrent use.
+type Notifier interface {
+	// Notify delivers msg, honoring ctx for cancellation.
+	Notify(ctx context.Context, msg Message) error
+}
@@ -0,0 +1,92 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0xmhha/token-monitor/pkg/rollup"
+)
+
+func TestDiscordNotify(t *testing.T) {
+	var got discordPayload
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("Decode() error = %v", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	d := NewDiscord(DiscordConfig{WebhookURL: srv.URL, Username: "Token Monitor"})
+	at := time.Date(2025, 11, 30, 9, 0, 0, 0, time.UTC)
+	err := d.Notify(context.Background(), Message{
+		Title:  strings.Repeat("x", 300),
+		Text:   "Block usage is high",
+		Level:  LevelWarning,
+		Fields: []Field{{Name: "Tokens", Value: "1,234", Inline: true}},
+		Time:   at,
+	})
+	if err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+
+	if got.Username != "Token Monitor" || len(got.Embeds) != 1 {
+		t.Fatalf("payload = %+v", got)
+	}
+	embed := got.Embeds[0]
+	if n := len([]rune(embed.Title)); n != discordTitleLimit {
+		t.Errorf("title length = %d, want %d", n, discordTitleLimit)
+	}
+	if embed.Color != discordColors[LevelWarning] || embed.Timestamp != "2025-11-30T09:00:00Z" {
+		t.Errorf("embed color = %#x, timestamp = %s", embed.Color, embed.Timestamp)
+	}
+	if len(embed.Fields) != 1 || !embed.Fields[0].Inline || embed.Fields[0].Value != "1,234" {
+		t.Errorf("fields = %+v", embed.Fields)
+	}
+}
+
+func TestDiscordNotifyRejected(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, `{"message": "Unknown Webhook"}`, http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	err := NewDiscord(DiscordConfig{WebhookURL: srv.URL}).Notify(context.Background(), Message{Title: "x"})
+	if !errors.Is(err, ErrDeliveryFailed) || !strings.Contains(err.Error(), "Unknown Webhook") {
+		t.Errorf("Notify() error = %v, want ErrDeliveryFailed with detail", err)
+	}
+}
+
+func TestDailySummary(t *testing.T) {
+	rows := []rollup.Row{
+		{Key: rollup.Key{Date: "2025-11-30", Model: "claude-opus-4", SessionID: "a"}, Totals: rollup.Totals{Entries: 2, InputTokens: 1000, CostUSD: 3}},
+		{Key: rollup.Key{Date: "2025-11-30", Model: "claude-sonnet-4", SessionID: "a"}, Totals: rollup.Totals{Entries: 3, InputTokens: 500, CostUSD: 0.5}},
+		{Key: rollup.Key{Date: "2025-11-30", Model: "claude-sonnet-4", SessionID: "b"}, Totals: rollup.Totals{Entries: 1, OutputTokens: 100, CostUSD: 0.5}},
+	}
+
+	msg := DailySummary("2025-11-30", rows, rollup.Totals{CostUSD: 2})
+	fields := make(map[string]string)
+	for _, f := range msg.Fields {
+		fields[f.Name] = f.Value
+	}
+	if !strings.Contains(fields["Cost"], "+100% vs. previous day") {
+		t.Errorf("Cost = %q, want day-over-day change", fields["Cost"])
+	}
+	if fields["Tokens"] != "1,600" || fields["Requests"] != "6" || fields["Sessions"] != "2" {
+		t.Errorf("fields = %v", fields)
+	}
+	if !strings.HasPrefix(fields["Top models"], "claude-opus-4:") {
+		t.Errorf("Top models = %q, want most expensive first", fields["Top models"])
+	}
+
+	empty := DailySummary("2025-12-01", nil, rollup.Totals{})
+	if len(empty.Fields) != 0 || empty.Text == "" {
+		t.Errorf("empty summary = %+v", empty)
+	}
+}
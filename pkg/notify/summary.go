@@ -0,0 +1,69 @@
+package notify
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/0xmhha/token-monitor/pkg/display"
+	"github.com/0xmhha/token-monitor/pkg/rollup"
+)
+
+// summaryTopModels is the number of models listed in a daily summary.
+const summaryTopModels = 3
+
+// DailySummary builds the summary message for date from its rollup rows.
+// previous holds the totals of the day before, for the day-over-day
+// change; a zero value leaves the change out.
+func DailySummary(date string, rows []rollup.Row, previous rollup.Totals) Message {
+	var totals rollup.Totals
+	for _, row := range rows {
+		totals.Merge(row.Totals)
+	}
+	sessions := len(rollup.Summarize(rows, []rollup.Dimension{rollup.DimSession}))
+
+	msg := Message{
+		Title: "Daily usage: " + date,
+		Level: LevelInfo,
+	}
+	if totals.Entries == 0 {
+		msg.Text = "No Claude Code usage recorded."
+		return msg
+	}
+
+	cost := display.FormatCost(totals.CostUSD)
+	if change, ok := percentChange(totals.CostUSD, previous.CostUSD); ok {
+		cost += fmt.Sprintf(" (%+.0f%% vs. previous day)", change)
+	}
+	msg.Fields = []Field{
+		{Name: "Cost", Value: cost, Inline: true},
+		{Name: "Tokens", Value: display.FormatNumber(totals.TotalTokens()), Inline: true},
+		{Name: "Requests", Value: display.FormatNumber(totals.Entries), Inline: true},
+		{Name: "Sessions", Value: display.FormatNumber(sessions), Inline: true},
+	}
+
+	models := rollup.Summarize(rows, []rollup.Dimension{rollup.DimModel})
+	sort.SliceStable(models, func(i, j int) bool {
+		return models[i].Totals.CostUSD > models[j].Totals.CostUSD
+	})
+	var lines []string
+	for i, m := range models {
+		if i == summaryTopModels {
+			lines = append(lines, fmt.Sprintf("and %d more", len(models)-i))
+			break
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s, %s tokens", m.Model,
+			display.FormatCost(m.Totals.CostUSD), display.FormatNumber(m.Totals.TotalTokens())))
+	}
+	msg.Fields = append(msg.Fields, Field{Name: "Top models", Value: strings.Join(lines, "\n")})
+	return msg
+}
+
+// percentChange returns the change from previous to current in percent.
+// It reports false when there is nothing to compare against.
+func percentChange(current, previous float64) (float64, bool) {
+	if previous <= 0 {
+		return 0, false
+	}
+	return (current - previous) / previous * 100, true
+}
@@ -0,0 +1,27 @@
+//go:build windows
+
+package overhead
+
+import (
+	"syscall"
+	"time"
+)
+
+// cpuTime returns the user and system CPU time used by the process.
+func cpuTime() time.Duration {
+	handle, err := syscall.GetCurrentProcess()
+	if err != nil {
+		return 0
+	}
+	var creation, exit, kernel, user syscall.Filetime
+	if err := syscall.GetProcessTimes(handle, &creation, &exit, &kernel, &user); err != nil {
+		return 0
+	}
+	return filetimeDuration(kernel) + filetimeDuration(user)
+}
+
+// filetimeDuration converts a Filetime holding a duration, in 100-nanosecond
+// intervals, to a time.Duration. Filetime.Nanoseconds is for points in time.
+func filetimeDuration(ft syscall.Filetime) time.Duration {
+	return time.Duration((int64(ft.HighDateTime)<<32 | int64(ft.LowDateTime)) * 100)
+}
@@ -0,0 +1,135 @@
+// Package overhead measures the resources token-monitor itself uses: CPU
+// time, bytes read from session files, and heap allocations. A long-running
+// command takes a Sample at the start and one per interval, and reports the
+// difference, so users can check that the monitor stays lightweight and
+// maintainers can spot regressions.
+//
+// Example usage:
+//
+//	start := overhead.Take()
+//	prev := start
+//	for range ticker.C {
+//	    now := overhead.Take()
+//	    last, total := now.Since(prev), now.Since(start)
+//	    prev = now
+//	}
+package overhead
+
+import (
+	"fmt"
+	"runtime"
+	"sync/atomic"
+	"time"
+)
+
+// bytesRead counts the bytes read from session files by this process.
+var bytesRead atomic.Int64
+
+// AddBytesRead records n bytes read from session files.
+//
+// Thread-safety: Safe for concurrent use.
+func AddBytesRead(n int64) {
+	if n > 0 {
+		bytesRead.Add(n)
+	}
+}
+
+// Sample holds the process counters at one point in time. The counters
+// only grow, so two samples give the usage between them.
+type Sample struct {
+	// Time the sample was taken
+	Time time.Time
+
+	// CPU is the user and system CPU time used by the process
+	CPU time.Duration
+
+	// BytesRead is the number of bytes read from session files
+	BytesRead int64
+
+	// Allocs is the number of heap objects allocated
+	Allocs uint64
+
+	// AllocBytes is the number of heap bytes allocated
+	AllocBytes uint64
+}
+
+// Take samples the process counters.
+//
+// It reads the runtime memory statistics, which briefly stops the world,
+// so take samples per interval rather than per operation.
+func Take() Sample {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	return Sample{
+		Time:       time.Now(),
+		CPU:        cpuTime(),
+		BytesRead:  bytesRead.Load(),
+		Allocs:     mem.Mallocs,
+		AllocBytes: mem.TotalAlloc,
+	}
+}
+
+// Interval is the resource usage between two samples.
+type Interval struct {
+	// DurationMs is the wall-clock length of the interval in milliseconds.
+	DurationMs int64 `json:"duration_ms"`
+
+	// CPUMs is the CPU time used in milliseconds.
+	CPUMs int64 `json:"cpu_ms"`
+
+	// CPUPercent is the CPU time as a percentage of one core.
+	CPUPercent float64 `json:"cpu_percent"`
+
+	// BytesRead is the number of bytes read from session files.
+	BytesRead int64 `json:"bytes_read"`
+
+	// Allocs is the number of heap objects allocated.
+	Allocs uint64 `json:"allocs"`
+
+	// AllocBytes is the number of heap bytes allocated.
+	AllocBytes uint64 `json:"alloc_bytes"`
+}
+
+// Since returns the usage between prev and s.
+func (s Sample) Since(prev Sample) Interval {
+	wall := s.Time.Sub(prev.Time)
+	cpu := s.CPU - prev.CPU
+
+	interval := Interval{
+		DurationMs: wall.Milliseconds(),
+		CPUMs:      cpu.Milliseconds(),
+		BytesRead:  s.BytesRead - prev.BytesRead,
+		Allocs:     s.Allocs - prev.Allocs,
+		AllocBytes: s.AllocBytes - prev.AllocBytes,
+	}
+	if wall > 0 {
+		interval.CPUPercent = float64(cpu) / float64(wall) * 100
+	}
+	return interval
+}
+
+// String summarizes the interval, e.g.
+// "cpu 120ms (0.2%), read 34.0 KB, 1523 allocs (2.1 MB) in 1m0s".
+func (i Interval) String() string {
+	return fmt.Sprintf("cpu %s (%.1f%%), read %s, %d allocs (%s) in %s",
+		time.Duration(i.CPUMs)*time.Millisecond, i.CPUPercent,
+		formatBytes(i.BytesRead), i.Allocs, formatBytes(int64(i.AllocBytes)), //nolint:gosec // allocation totals fit in int64
+		(time.Duration(i.DurationMs) * time.Millisecond).Round(time.Second))
+}
+
+// formatBytes formats n in B, KB, MB, or GB (powers of 1024).
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	value, suffix := float64(n)/unit, "KB"
+	for _, next := range []string{"MB", "GB"} {
+		if value < unit {
+			break
+		}
+		value, suffix = value/unit, next
+	}
+	return fmt.Sprintf("%.1f %s", value, suffix)
+}
@@ -0,0 +1,69 @@
+package overhead
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTake(t *testing.T) {
+	before := Take()
+
+	AddBytesRead(1500)
+	AddBytesRead(-1) // ignored
+	sink := make([][]byte, 0, 100)
+	for i := 0; i < 100; i++ {
+		sink = append(sink, make([]byte, 1024))
+	}
+	_ = sink
+	// Spin so the process uses measurable CPU time.
+	n := 0
+	for deadline := time.Now().Add(20 * time.Millisecond); time.Now().Before(deadline); {
+		n++
+	}
+	_ = n
+
+	usage := Take().Since(before)
+	if usage.BytesRead != 1500 {
+		t.Errorf("BytesRead = %d, want 1500", usage.BytesRead)
+	}
+	if usage.Allocs < 100 || usage.AllocBytes < 100*1024 {
+		t.Errorf("Allocs = %d, AllocBytes = %d, want at least 100 and 100 KB", usage.Allocs, usage.AllocBytes)
+	}
+	if usage.CPUMs <= 0 || usage.DurationMs < 20 || usage.CPUPercent <= 0 {
+		t.Errorf("usage = %+v, want CPU time over at least 20ms", usage)
+	}
+}
+
+func TestSince(t *testing.T) {
+	start := time.Date(2025, 11, 1, 9, 0, 0, 0, time.UTC)
+	prev := Sample{Time: start, CPU: time.Second, BytesRead: 100, Allocs: 10, AllocBytes: 1000}
+	now := Sample{Time: start.Add(time.Minute), CPU: 4 * time.Second, BytesRead: 2148, Allocs: 25, AllocBytes: 3000}
+
+	got := now.Since(prev)
+	want := Interval{DurationMs: 60000, CPUMs: 3000, CPUPercent: 5, BytesRead: 2048, Allocs: 15, AllocBytes: 2000}
+	if got != want {
+		t.Errorf("Since() = %+v, want %+v", got, want)
+	}
+	if s, want := got.String(), "cpu 3s (5.0%), read 2.0 KB, 15 allocs (2.0 KB) in 1m0s"; s != want {
+		t.Errorf("String() = %q, want %q", s, want)
+	}
+
+	if got := prev.Since(prev); got.CPUPercent != 0 {
+		t.Errorf("Since() of an empty interval = %+v, want 0%% CPU", got)
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := map[int64]string{
+		512:     "512 B",
+		2048:    "2.0 KB",
+		5 << 20: "5.0 MB",
+		3 << 30: "3.0 GB",
+		0:       "0 B",
+	}
+	for n, want := range tests {
+		if got := formatBytes(n); got != want {
+			t.Errorf("formatBytes(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
@@ -1,6 +1,9 @@
 package parser
 
-import "errors"
+import (
+	"errors"
+	"strings"
+)
 
 // Common errors returned by the parser package.
 var (
@@ -16,6 +19,9 @@ var (
 	// ErrNegativeTokenCount is returned when any token count is negative.
 	ErrNegativeTokenCount = errors.New("invalid token count: must be non-negative")
 
+	// ErrTokenCountTooLarge is returned when any token count exceeds MaxTokenCount.
+	ErrTokenCountTooLarge = errors.New("invalid token count: exceeds maximum")
+
 	// ErrMalformedJSON is returned when a JSONL line cannot be parsed.
 	ErrMalformedJSON = errors.New("malformed JSON line")
 
@@ -34,7 +40,7 @@ func (e *ParseError) Error() string {
 	maxLen := 100
 	data := e.Data
 	if len(data) > maxLen {
-		data = data[:maxLen] + "..."
+		data = strings.ToValidUTF8(data[:maxLen], "") + "..."
 	}
 	return formatError("parse error", e.Line, data, e.Err)
 }
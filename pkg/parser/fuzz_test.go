@@ -0,0 +1,146 @@
+package parser
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// malformedCorpus holds real-world malformed lines: truncated writes,
+// broken UTF-8, nested escapes, out-of-range numbers, and deep nesting.
+const malformedCorpus = "testdata/malformed.jsonl"
+
+// corpusValid is the number of corpus lines that are valid entries
+// despite odd encodings (broken UTF-8, escapes, duplicate keys, BOM).
+const corpusValid = 6
+
+func corpusLines(t testing.TB) [][]byte {
+	t.Helper()
+	data, err := os.ReadFile(malformedCorpus)
+	if err != nil {
+		t.Fatalf("failed to read corpus: %v", err)
+	}
+	return bytes.Split(bytes.TrimSuffix(data, []byte{'\n'}), []byte{'\n'})
+}
+
+func TestParseLineMalformedCorpus(t *testing.T) {
+	p := New()
+	valid := 0
+	for i, line := range corpusLines(t) {
+		entry, err := p.ParseLine(string(line))
+		if err != nil {
+			continue
+		}
+		if verr := entry.Validate(); verr != nil {
+			t.Errorf("line %d: ParseLine() returned an invalid entry: %v", i+1, verr)
+		}
+		valid++
+	}
+	if valid != corpusValid {
+		t.Errorf("ParseLine() accepted %d corpus lines, want %d", valid, corpusValid)
+	}
+
+	entries, offset, err := p.ParseFile(malformedCorpus, 0)
+	info, _ := os.Stat(malformedCorpus)
+	if err != nil || len(entries) != corpusValid || offset != info.Size() {
+		t.Errorf("ParseFile() = %d entries, offset %d, %v; want %d entries to offset %d",
+			len(entries), offset, err, corpusValid, info.Size())
+	}
+}
+
+func TestParseFileSkipsLongLines(t *testing.T) {
+	valid := `{"timestamp":"2025-11-01T10:00:00Z","sessionId":"s1","message":{"model":"m","usage":{"input_tokens":1}}}`
+	long := `{"timestamp":"2025-11-01T10:00:00Z","sessionId":"s1","cwd":"` +
+		string(bytes.Repeat([]byte{'x'}, 5000)) + `","message":{"model":"m","usage":{"input_tokens":1}}}`
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	data := valid + "\n" + long + "\n" + valid + "\n" + long
+	if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	p := NewWithConfig(Config{MaxLineLength: 1024})
+	entries, offset, err := p.ParseFile(path, 0)
+	if err != nil || len(entries) != 2 || offset != int64(len(data)) {
+		t.Errorf("ParseFile() = %d entries, offset %d, %v; want 2 entries to offset %d", len(entries), offset, err, len(data))
+	}
+	tail, err := ParseTail(p, path, 10)
+	if err != nil || len(tail) != 2 {
+		t.Errorf("ParseTail() = %d entries, %v; want 2", len(tail), err)
+	}
+	if entries, _, _ := New().ParseFile(path, 0); len(entries) != 4 {
+		t.Errorf("ParseFile() with default limit = %d entries, want 4", len(entries))
+	}
+}
+
+func TestParseFileLeavesPartialLine(t *testing.T) {
+	valid := `{"timestamp":"2025-11-01T10:00:00Z","sessionId":"s1","message":{"model":"m","usage":{"input_tokens":1}}}`
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	if err := os.WriteFile(path, []byte(valid+"\n"+valid[:40]), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	p := New()
+	entries, offset, err := p.ParseFile(path, 0)
+	if err != nil || len(entries) != 1 || offset != int64(len(valid)+1) {
+		t.Fatalf("ParseFile() = %d entries, offset %d, %v; want 1 entry before the partial line", len(entries), offset, err)
+	}
+
+	// The writer finishes the line.
+	if err := os.WriteFile(path, []byte(valid+"\n"+valid+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if entries, _, err = p.ParseFile(path, offset); err != nil || len(entries) != 1 {
+		t.Errorf("ParseFile() after completion = %d entries, %v; want the completed line", len(entries), err)
+	}
+}
+
+func FuzzParseLine(f *testing.F) {
+	for _, line := range corpusLines(f) {
+		f.Add(string(line))
+	}
+	p := New()
+	f.Fuzz(func(t *testing.T, line string) {
+		entry, err := p.ParseLine(line)
+		if err != nil {
+			return
+		}
+		if verr := entry.Validate(); verr != nil {
+			t.Errorf("ParseLine(%q) returned an invalid entry: %v", line, verr)
+		}
+		if entry.Message.Usage.TotalTokens() < 0 {
+			t.Errorf("ParseLine(%q) total tokens overflowed", line)
+		}
+	})
+}
+
+func FuzzParseFile(f *testing.F) {
+	data, err := os.ReadFile(malformedCorpus)
+	if err != nil {
+		f.Fatalf("failed to read corpus: %v", err)
+	}
+	f.Add(data, 64)
+	f.Add([]byte("\n\r\n\n"), 1)
+	f.Fuzz(func(t *testing.T, data []byte, maxLine int) {
+		path := filepath.Join(t.TempDir(), "session.jsonl")
+		if err := os.WriteFile(path, data, 0600); err != nil {
+			t.Fatal(err)
+		}
+		p := NewWithConfig(Config{MaxLineLength: maxLine % 4096})
+
+		entries, offset, err := p.ParseFile(path, 0)
+		if err != nil {
+			t.Fatalf("ParseFile() error = %v", err)
+		}
+		if offset < 0 || offset > int64(len(data)) {
+			t.Fatalf("ParseFile() offset = %d, outside [0, %d]", offset, len(data))
+		}
+		more, _, err := p.ParseFile(path, offset)
+		if err != nil || len(more) != 0 {
+			t.Errorf("ParseFile() from %d = %d entries, %v; want none", offset, len(more), err)
+		}
+		if _, err := ParseTail(p, path, len(entries)+1); err != nil {
+			t.Errorf("ParseTail() error = %v", err)
+		}
+	})
+}
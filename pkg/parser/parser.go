@@ -2,10 +2,13 @@ package parser
 
 import (
 	"bufio"
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
+	"strings"
 )
 
 const (
@@ -13,9 +16,13 @@ const (
 	// Files larger than this will be rejected to prevent memory exhaustion.
 	MaxFileSize = 100 * 1024 * 1024
 
-	// MaxLineLength is the maximum allowed line length (1MB).
-	// Lines longer than this will be truncated in error messages.
+	// MaxLineLength is the default maximum line length (1MB). Longer
+	// lines are skipped without being buffered in full.
 	MaxLineLength = 1024 * 1024
+
+	// MaxTokenCount is the largest token count a usage field may hold.
+	// Larger values are corrupt and would overflow totals.
+	MaxTokenCount = 1<<31 - 1
 )
 
 // Parser provides methods for parsing Claude Code JSONL files.
@@ -51,14 +58,84 @@ type Parser interface {
 	ParseLine(line string) (*UsageEntry, error)
 }
 
+// Config contains parser limits for pathologically large session files.
+type Config struct {
+	// MaxFileSize is the largest file ParseFile accepts, in bytes.
+	// Default: MaxFileSize. Negative disables the limit.
+	MaxFileSize int64
+
+	// MaxEntries stops ParseFile after this many entries. The returned
+	// offset follows the last line read, so the next call continues
+	// there. Default: 0 (no limit).
+	MaxEntries int
+
+	// MaxLineLength is the longest line parsed, in bytes. Longer lines
+	// are skipped. Default: MaxLineLength.
+	MaxLineLength int
+
+	// MissingSessionID decides what happens to entries without a
+	// sessionId. Default: MissingSessionIDDrop.
+	MissingSessionID MissingSessionIDPolicy
+
+	// Attribution decides which session ParseFile attributes entries to.
+	// Default: AttributeEmbedded.
+	Attribution Attribution
+}
+
+// Attribution is how ParseFile assigns entries to sessions when an entry's
+// embedded sessionId differs from the session of the file containing it,
+// as with sub-agent usage.
+type Attribution string
+
+const (
+	// AttributeEmbedded attributes entries to their embedded sessionId.
+	AttributeEmbedded Attribution = "embedded"
+
+	// AttributeFile attributes entries to the containing file's session,
+	// keeping a differing embedded sessionId in SubAgentID.
+	AttributeFile Attribution = "file"
+)
+
+// MissingSessionIDPolicy is how the parser treats entries without a
+// sessionId.
+type MissingSessionIDPolicy string
+
+const (
+	// MissingSessionIDDrop skips entries without a sessionId.
+	MissingSessionIDDrop MissingSessionIDPolicy = "drop"
+
+	// MissingSessionIDFilename takes the session ID of entries without
+	// one from the file name (<session-id>.jsonl), for logs that record it
+	// only there.
+	MissingSessionIDFilename MissingSessionIDPolicy = "filename"
+)
+
+// SessionIDFromPath returns the session ID encoded in a session file
+// path: the file name without the .jsonl extension.
+func SessionIDFromPath(path string) string {
+	return strings.TrimSuffix(filepath.Base(path), ".jsonl")
+}
+
 // jsonlParser implements the Parser interface.
 type jsonlParser struct {
 	// TODO: Add logger for reporting skipped lines
+	cfg Config
 }
 
-// New creates a new Parser instance.
+// New creates a new Parser instance with the default limits.
 func New() Parser {
-	return &jsonlParser{}
+	return NewWithConfig(Config{})
+}
+
+// NewWithConfig creates a Parser with the limits in cfg.
+func NewWithConfig(cfg Config) Parser {
+	if cfg.MaxFileSize == 0 {
+		cfg.MaxFileSize = MaxFileSize
+	}
+	if cfg.MaxLineLength <= 0 {
+		cfg.MaxLineLength = MaxLineLength
+	}
+	return &jsonlParser{cfg: cfg}
 }
 
 // ParseFile implements Parser.ParseFile.
@@ -69,9 +146,9 @@ func (p *jsonlParser) ParseFile(path string, offset int64) ([]UsageEntry, int64,
 		return nil, 0, fmt.Errorf("failed to stat file: %w", err)
 	}
 
-	if info.Size() > MaxFileSize {
+	if p.cfg.MaxFileSize > 0 && info.Size() > p.cfg.MaxFileSize {
 		return nil, 0, fmt.Errorf("%w: size=%d, max=%d",
-			ErrFileTooLarge, info.Size(), MaxFileSize)
+			ErrFileTooLarge, info.Size(), p.cfg.MaxFileSize)
 	}
 
 	// Open file - #nosec G304: path is validated by caller
@@ -96,43 +173,99 @@ func (p *jsonlParser) ParseFile(path string, offset int64) ([]UsageEntry, int64,
 	// Pre-allocate slice with reasonable capacity
 	entries := make([]UsageEntry, 0, 100)
 	scanner := bufio.NewScanner(f)
+	lines := &lineSplitter{max: p.cfg.MaxLineLength}
+	scanner.Buffer(make([]byte, 0, min(64*1024, lines.max+1)), lines.max+1)
+	scanner.Split(lines.split)
 
-	// Set maximum line size
-	buf := make([]byte, 0, 64*1024)
-	scanner.Buffer(buf, MaxLineLength)
-
-	lineNum := 0
-
+	fileSessionID := SessionIDFromPath(path)
 	for scanner.Scan() {
-		lineNum++
-		line := scanner.Text()
-
-		entry, parseErr := p.ParseLine(line)
+		entry, parseErr := p.parseLine(scanner.Text(), fileSessionID)
 		if parseErr != nil {
+			if lines.unterminated {
+				// The last line may still be being written; leave it
+				// for the next read instead of skipping it for good.
+				return entries, offset + lines.consumed - lines.last, nil
+			}
 			// TODO: Log warning about skipped line
 			// For now, skip malformed lines silently
 			continue
 		}
 
 		entries = append(entries, *entry)
+		if p.cfg.MaxEntries > 0 && len(entries) >= p.cfg.MaxEntries {
+			return entries, offset + lines.consumed, nil
+		}
 	}
 
 	if scanErr := scanner.Err(); scanErr != nil {
-		return entries, 0, fmt.Errorf("scanner error at line %d: %w", lineNum, scanErr)
+		return entries, 0, fmt.Errorf("scanner error after %d bytes: %w", lines.consumed, scanErr)
 	}
 
-	// Get current position as new offset
-	newOffset, seekErr := f.Seek(0, io.SeekCurrent)
-	if seekErr != nil {
-		// If we can't get offset, return file size
-		newOffset = info.Size()
-	}
+	return entries, offset + lines.consumed, nil
+}
+
+// lineSplitter is a bufio.SplitFunc that splits lines like
+// bufio.ScanLines, skips lines longer than max without buffering them in
+// full, and counts the bytes consumed, since the scanner reads ahead of
+// the line it returns.
+type lineSplitter struct {
+	max      int
+	consumed int64 // bytes consumed so far
+	last     int64 // bytes consumed by the last token
+
+	// skipping is set while discarding the rest of an overlong line.
+	skipping bool
 
-	return entries, newOffset, nil
+	// unterminated is set when the last token had no trailing newline.
+	unterminated bool
 }
 
-// ParseLine implements Parser.ParseLine.
+func (l *lineSplitter) split(data []byte, atEOF bool) (int, []byte, error) {
+	for {
+		i := bytes.IndexByte(data, '\n')
+		switch {
+		case l.skipping && i < 0:
+			// Discard what is buffered and keep skipping.
+			l.skipping = !atEOF
+			l.consumed += int64(len(data))
+			if len(data) == 0 {
+				return 0, nil, nil
+			}
+			return len(data), nil, nil
+		case l.skipping:
+			l.skipping = false
+			l.consumed += int64(i + 1)
+			return i + 1, nil, nil
+		case i < 0 && len(data) > l.max:
+			l.skipping = true
+			continue
+		}
+
+		advance, token, err := bufio.ScanLines(data, atEOF)
+		if advance > 0 && len(token) > l.max {
+			l.consumed += int64(advance)
+			return advance, nil, nil
+		}
+		l.consumed += int64(advance)
+		l.last = int64(advance)
+		l.unterminated = advance > 0 && data[advance-1] != '\n'
+		return advance, token, err
+	}
+}
+
+// ParseLine implements Parser.ParseLine. There is no file name to take a
+// missing session ID from, so such lines are always rejected.
 func (p *jsonlParser) ParseLine(line string) (*UsageEntry, error) {
+	return p.parseLine(line, "")
+}
+
+// parseLine parses line from the file of session fileSessionID, if known.
+// It records an embedded session ID that differs from the file's in
+// SubAgentID, and attributes the entry to the file's session when the
+// Attribution or MissingSessionID policy says so.
+func (p *jsonlParser) parseLine(line, fileSessionID string) (*UsageEntry, error) {
+	// Editors on some platforms prepend a byte order mark.
+	line = strings.TrimPrefix(line, "\ufeff")
 	if line == "" {
 		return nil, fmt.Errorf("%w: empty line", ErrMalformedJSON)
 	}
@@ -142,6 +275,20 @@ func (p *jsonlParser) ParseLine(line string) (*UsageEntry, error) {
 		return nil, fmt.Errorf("%w: %v", ErrMalformedJSON, err)
 	}
 
+	if fileSessionID != "" {
+		switch {
+		case entry.SessionID == "":
+			if p.cfg.MissingSessionID == MissingSessionIDFilename || p.cfg.Attribution == AttributeFile {
+				entry.SessionID = fileSessionID
+			}
+		case entry.SessionID != fileSessionID:
+			entry.SubAgentID = entry.SessionID
+			if p.cfg.Attribution == AttributeFile {
+				entry.SessionID = fileSessionID
+			}
+		}
+	}
+
 	// Validate the parsed entry
 	if err := entry.Validate(); err != nil {
 		return nil, fmt.Errorf("validation failed: %w", err)
@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
@@ -203,6 +204,106 @@ func TestParseFile(t *testing.T) {
 	}
 }
 
+// writeEntries writes n valid entries with input_tokens 1..n, with CRLF
+// line endings when crlf is set.
+func writeEntries(t *testing.T, n int, crlf bool) string {
+	t.Helper()
+	eol := "\n"
+	if crlf {
+		eol = "\r\n"
+	}
+	var b []byte
+	for i := 1; i <= n; i++ {
+		b = append(b, `{"timestamp":"2024-01-15T10:30:00Z","sessionId":"test1","message":{"model":"claude-sonnet-4","usage":{"input_tokens":`+itoa(i)+`}}}`+eol...)
+		if i%3 == 0 {
+			b = append(b, "not json"+eol...)
+		}
+	}
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	if err := os.WriteFile(path, b, 0600); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	return path
+}
+
+func TestParseFileMaxEntries(t *testing.T) {
+	for _, crlf := range []bool{false, true} {
+		path := writeEntries(t, 10, crlf)
+		p := NewWithConfig(Config{MaxEntries: 4})
+
+		var got []int
+		offset := int64(0)
+		for i := 0; i < 5; i++ {
+			entries, next, err := p.ParseFile(path, offset)
+			if err != nil {
+				t.Fatalf("ParseFile() error = %v", err)
+			}
+			if len(entries) > 4 {
+				t.Fatalf("ParseFile() returned %d entries, want at most 4", len(entries))
+			}
+			for _, e := range entries {
+				got = append(got, e.Message.Usage.InputTokens)
+			}
+			offset = next
+		}
+		if len(got) != 10 {
+			t.Fatalf("crlf=%v: limited reads returned %v, want 1..10 once each", crlf, got)
+		}
+		for i, v := range got {
+			if v != i+1 {
+				t.Errorf("crlf=%v: entry %d = %d, want %d", crlf, i, v, i+1)
+			}
+		}
+	}
+}
+
+func TestParseFileSizeLimit(t *testing.T) {
+	path := writeEntries(t, 10, false)
+	if _, _, err := NewWithConfig(Config{MaxFileSize: 100}).ParseFile(path, 0); !errors.Is(err, ErrFileTooLarge) {
+		t.Errorf("ParseFile() over limit error = %v, want ErrFileTooLarge", err)
+	}
+	if entries, _, err := NewWithConfig(Config{MaxFileSize: -1}).ParseFile(path, 0); err != nil || len(entries) != 10 {
+		t.Errorf("ParseFile() without limit = %d entries, %v; want 10", len(entries), err)
+	}
+}
+
+func TestParseTail(t *testing.T) {
+	for _, crlf := range []bool{false, true} {
+		path := writeEntries(t, 10, crlf)
+		for _, n := range []int{3, 10, 50} {
+			entries, err := ParseTail(New(), path, n)
+			if err != nil {
+				t.Fatalf("ParseTail(%d) error = %v", n, err)
+			}
+			want := n
+			if want > 10 {
+				want = 10
+			}
+			if len(entries) != want {
+				t.Fatalf("crlf=%v: ParseTail(%d) returned %d entries, want %d", crlf, n, len(entries), want)
+			}
+			for i, e := range entries {
+				if e.Message.Usage.InputTokens != 10-want+1+i {
+					t.Errorf("crlf=%v: ParseTail(%d)[%d] = %d, want %d", crlf, n, i, e.Message.Usage.InputTokens, 10-want+1+i)
+				}
+			}
+		}
+	}
+}
+
+func TestParseTailAcrossChunks(t *testing.T) {
+	path := writeEntries(t, 4000, false) // well over tailChunkSize
+	entries, err := ParseTail(New(), path, 3500)
+	if err != nil || len(entries) != 3500 {
+		t.Fatalf("ParseTail() = %d entries, %v; want 3500", len(entries), err)
+	}
+	for i, e := range entries {
+		if e.Message.Usage.InputTokens != 501+i {
+			t.Fatalf("ParseTail()[%d] = %d, want %d", i, e.Message.Usage.InputTokens, 501+i)
+		}
+	}
+}
+
 func TestParseFile_FileTooLarge(t *testing.T) {
 	// Create a temporary file that exceeds MaxFileSize
 	tmpDir := t.TempDir()
@@ -351,6 +452,52 @@ func TestUsageTotalTokens(t *testing.T) {
 	}
 }
 
+func TestClampFuture(t *testing.T) {
+	limit := time.Date(2025, 12, 1, 12, 0, 0, 0, time.UTC)
+	entries := []UsageEntry{
+		{Timestamp: limit.Add(-time.Hour)},
+		{Timestamp: limit.Add(time.Minute)}, // within tolerance
+		{Timestamp: limit.Add(48 * time.Hour)},
+	}
+
+	if n := ClampFuture(entries, limit, 5*time.Minute); n != 1 {
+		t.Fatalf("ClampFuture() = %d, want 1", n)
+	}
+	if !entries[1].ClampedFrom.IsZero() || entries[1].Skew() != 0 {
+		t.Errorf("entry within tolerance was clamped: %+v", entries[1])
+	}
+	if !entries[2].Timestamp.Equal(limit) || entries[2].Skew() != 48*time.Hour {
+		t.Errorf("future entry = %v (skew %v), want %v (skew 48h)", entries[2].Timestamp, entries[2].Skew(), limit)
+	}
+}
+
+func TestAPIError(t *testing.T) {
+	tests := []struct {
+		name   string
+		line   string
+		expect APIErrorKind
+	}{
+		{"response", `{"timestamp":"2025-01-01T00:00:00Z","sessionId":"s","message":{"model":"claude-sonnet-4","content":[{"type":"text","text":"API Error is a good name"}]}}`, ""},
+		{"overloaded", `{"timestamp":"2025-01-01T00:00:00Z","sessionId":"s","isApiErrorMessage":true,"message":{"model":"<synthetic>","content":[{"type":"text","text":"API Error: 529 {\"type\":\"error\",\"error\":{\"type\":\"overloaded_error\",\"message\":\"Overloaded\"}}"}]}}`, APIErrorOverloaded},
+		{"usage limit", `{"timestamp":"2025-01-01T00:00:00Z","sessionId":"s","isApiErrorMessage":true,"message":{"model":"<synthetic>","content":[{"type":"text","text":"Claude AI usage limit reached|1735700000"}]}}`, APIErrorRateLimit},
+		{"aborted", `{"timestamp":"2025-01-01T00:00:00Z","sessionId":"s","message":{"model":"<synthetic>","content":[{"type":"text","text":"API Error: Request was aborted."}]}}`, APIErrorAborted},
+		{"other", `{"timestamp":"2025-01-01T00:00:00Z","sessionId":"s","isApiErrorMessage":true,"message":{"model":"<synthetic>","content":[{"type":"text","text":"API Error: 400 invalid request"}]}}`, APIErrorOther},
+	}
+
+	p := New()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry, err := p.ParseLine(tt.line)
+			if err != nil {
+				t.Fatalf("ParseLine() error = %v", err)
+			}
+			if got := entry.APIError(); got != tt.expect {
+				t.Errorf("APIError() = %q, want %q", got, tt.expect)
+			}
+		})
+	}
+}
+
 func TestUsageEntryValidate(t *testing.T) {
 	validTime := time.Now()
 	zeroTime := time.Time{}
@@ -611,3 +758,63 @@ func BenchmarkParseFile(b *testing.B) {
 		}
 	}
 }
+
+func TestParseFileMissingSessionID(t *testing.T) {
+	const sessionID = "0b8c2f9e-5a7d-4c1e-9f3b-2d6a8e4c1f70"
+	path := filepath.Join(t.TempDir(), sessionID+".jsonl")
+	data := `{"timestamp":"2025-11-01T10:00:00Z","message":{"model":"m","usage":{"input_tokens":1}}}` + "\n" +
+		`{"timestamp":"2025-11-01T10:01:00Z","sessionId":"other","message":{"model":"m","usage":{"input_tokens":2}}}` + "\n"
+	if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, _, err := New().ParseFile(path, 0)
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("ParseFile() with drop policy = %d entries, %v; want 1", len(entries), err)
+	}
+
+	p := NewWithConfig(Config{MissingSessionID: MissingSessionIDFilename})
+	entries, _, err = p.ParseFile(path, 0)
+	if err != nil || len(entries) != 2 {
+		t.Fatalf("ParseFile() with filename policy = %d entries, %v; want 2", len(entries), err)
+	}
+	if entries[0].SessionID != sessionID || entries[1].SessionID != "other" {
+		t.Errorf("session IDs = %q, %q; want %q from the file name and the embedded one", entries[0].SessionID, entries[1].SessionID, sessionID)
+	}
+	if tail, err := ParseTail(p, path, 5); err != nil || len(tail) != 2 || tail[0].SessionID != sessionID {
+		t.Errorf("ParseTail() with filename policy = %+v, %v", tail, err)
+	}
+	if _, err := p.ParseLine(`{"timestamp":"2025-11-01T10:00:00Z","message":{"model":"m"}}`); !errors.Is(err, ErrInvalidSessionID) {
+		t.Errorf("ParseLine() without file name error = %v, want ErrInvalidSessionID", err)
+	}
+}
+
+func TestParseFileAttribution(t *testing.T) {
+	const sessionID = "0b8c2f9e-5a7d-4c1e-9f3b-2d6a8e4c1f70"
+	path := filepath.Join(t.TempDir(), sessionID+".jsonl")
+	data := `{"timestamp":"2025-11-01T10:00:00Z","sessionId":"` + sessionID + `","message":{"model":"m","usage":{"input_tokens":1}}}` + "\n" +
+		`{"timestamp":"2025-11-01T10:01:00Z","sessionId":"agent-1","message":{"model":"m","usage":{"input_tokens":2}}}` + "\n"
+	if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		attribution Attribution
+		wantSession string
+	}{
+		{AttributeEmbedded, "agent-1"},
+		{AttributeFile, sessionID},
+	}
+	for _, tt := range tests {
+		entries, _, err := NewWithConfig(Config{Attribution: tt.attribution}).ParseFile(path, 0)
+		if err != nil || len(entries) != 2 {
+			t.Fatalf("%s: ParseFile() = %d entries, %v; want 2", tt.attribution, len(entries), err)
+		}
+		if entries[0].SessionID != sessionID || entries[0].SubAgentID != "" {
+			t.Errorf("%s: own entry = %q/%q, want %q and no sub-agent", tt.attribution, entries[0].SessionID, entries[0].SubAgentID, sessionID)
+		}
+		if entries[1].SessionID != tt.wantSession || entries[1].SubAgentID != "agent-1" {
+			t.Errorf("%s: sub-agent entry = %q/%q, want %q/agent-1", tt.attribution, entries[1].SessionID, entries[1].SubAgentID, tt.wantSession)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package parser
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+)
+
+// tailChunkSize is how much ParseTail reads at a time, from the end.
+const tailChunkSize = 256 * 1024
+
+// ParseTail returns the last n entries of the JSONL file at path, oldest
+// first, using p to parse lines. It reads backwards from the end of the
+// file, so the cost depends on n rather than the file size; there is no
+// file size limit. Malformed lines are skipped as in ParseFile.
+//
+// It is meant for quick approximate statistics over very large files.
+func ParseTail(p Parser, path string, n int) ([]UsageEntry, error) {
+	f, err := os.Open(path) //nolint:gosec // path is validated by caller
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer func() {
+		_ = f.Close() //nolint:errcheck // read-only
+	}()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat file: %w", err)
+	}
+
+	maxLine := MaxLineLength
+	parseLine := p.ParseLine
+	if jp, ok := p.(*jsonlParser); ok {
+		maxLine = jp.cfg.MaxLineLength
+		fileSessionID := SessionIDFromPath(path)
+		parseLine = func(line string) (*UsageEntry, error) {
+			return jp.parseLine(line, fileSessionID)
+		}
+	}
+
+	var found []UsageEntry
+	var rest []byte    // start of a line continuing into the next chunk
+	oversized := false // the line continuing into the next chunk is too long
+	pos := info.Size()
+	for pos > 0 && len(found) < n {
+		size := int64(tailChunkSize)
+		if size > pos {
+			size = pos
+		}
+		pos -= size
+
+		chunk := make([]byte, size, size+int64(len(rest)))
+		if _, err := f.ReadAt(chunk, pos); err != nil {
+			return nil, fmt.Errorf("failed to read file: %w", err)
+		}
+		lines := bytes.Split(append(chunk, rest...), []byte{'\n'})
+
+		// The last line continues an oversized line from the previous
+		// chunk when that chunk held no line break.
+		last := len(lines) - 1
+		if oversized {
+			last--
+		}
+
+		// Unless at the start of the file, the first line may be cut.
+		// Oversized lines are skipped, as ParseFile skips them, without
+		// carrying them along.
+		first := 0
+		rest = nil
+		if pos > 0 {
+			first = 1
+			if len(lines) == 1 && oversized {
+				continue
+			}
+			oversized = len(lines[0]) > maxLine
+			if !oversized {
+				rest = lines[0]
+			}
+		}
+		for i := last; i >= first && len(found) < n; i-- {
+			line := bytes.TrimSuffix(lines[i], []byte{'\r'})
+			if len(line) == 0 || len(line) > maxLine {
+				continue
+			}
+			if entry, err := parseLine(string(line)); err == nil {
+				found = append(found, *entry)
+			}
+		}
+	}
+
+	// found is newest first.
+	for i, j := 0, len(found)-1; i < j; i, j = i+1, j-1 {
+		found[i], found[j] = found[j], found[i]
+	}
+	return found, nil
+}
@@ -18,6 +18,7 @@
 package parser
 
 import (
+	"strings"
 	"time"
 )
 
@@ -26,7 +27,7 @@ import (
 //
 // Invariant: Timestamp must not be zero value.
 // Invariant: SessionID must be a valid UUID.
-// Invariant: Message.Usage token counts must be non-negative.
+// Invariant: Message.Usage token counts must be in [0, MaxTokenCount].
 type UsageEntry struct {
 	Timestamp  time.Time `json:"timestamp"`
 	SessionID  string    `json:"sessionId"`
@@ -35,6 +36,125 @@ type UsageEntry struct {
 	Message    Message   `json:"message"`
 	CostUSD    *float64  `json:"costUSD,omitempty"`
 	RequestID  *string   `json:"requestId,omitempty"`
+
+	// IsSidechain marks entries written by a sub-agent (Task tool) run
+	// rather than the main conversation.
+	IsSidechain bool `json:"isSidechain,omitempty"`
+
+	// IsAPIErrorMessage marks the entries Claude Code writes in place of
+	// a response when a request fails (API errors, rate limits, aborted
+	// requests), with the model "<synthetic>" and no usage.
+	IsAPIErrorMessage bool `json:"isApiErrorMessage,omitempty"`
+
+	// Source is the label of the Claude directory the entry was read
+	// from. It is not part of the JSONL data; callers set it from
+	// discovery.SessionFile.Source.
+	Source string `json:"-"`
+
+	// SubAgentID is the embedded sessionId of an entry found in another
+	// session's file, as sub-agents write, when it differs from the
+	// file's session. Set only when the file is known (ParseFile,
+	// ParseTail). With AttributeFile, SessionID is the file's session.
+	SubAgentID string `json:"-"`
+
+	// ClampedFrom is the original Timestamp of a future-dated entry
+	// whose Timestamp was clamped by ClampFuture. Zero otherwise.
+	ClampedFrom time.Time `json:"-"`
+}
+
+// IsSubAgent reports whether the entry is sub-agent usage: marked as a
+// sidechain, or found in another session's file.
+func (e UsageEntry) IsSubAgent() bool {
+	return e.IsSidechain || e.SubAgentID != ""
+}
+
+// APIErrorKind classifies a failed request.
+type APIErrorKind string
+
+// API error kinds, from the text of the error entry.
+const (
+	// APIErrorRateLimit is a rate limit or usage limit rejection.
+	APIErrorRateLimit APIErrorKind = "rate_limit"
+
+	// APIErrorOverloaded is an overloaded API (HTTP 529) or server error.
+	APIErrorOverloaded APIErrorKind = "overloaded"
+
+	// APIErrorAborted is a request aborted before it completed, e.g. by a
+	// crash, a lost connection, or a timeout.
+	APIErrorAborted APIErrorKind = "aborted"
+
+	// APIErrorOther is any other failed request.
+	APIErrorOther APIErrorKind = "api_error"
+)
+
+// Throttled reports whether the API turned the request away under load:
+// a rate limit or an overload. Requests slow down while these last.
+func (k APIErrorKind) Throttled() bool {
+	return k == APIErrorRateLimit || k == APIErrorOverloaded
+}
+
+// APIError returns the kind of failed request the entry records, or ""
+// for a regular response. Besides entries marked IsAPIErrorMessage, it
+// recognizes the "<synthetic>" entries of older Claude Code versions whose
+// text starts with "API Error".
+func (e UsageEntry) APIError() APIErrorKind {
+	text := e.text()
+	if !e.IsAPIErrorMessage && (e.Message.Model != "<synthetic>" || !strings.HasPrefix(text, "API Error")) {
+		return ""
+	}
+
+	lower := strings.ToLower(text)
+	switch {
+	case strings.Contains(lower, "rate limit"), strings.Contains(lower, "rate_limit"),
+		strings.Contains(lower, "usage limit"), strings.Contains(lower, " 429"):
+		return APIErrorRateLimit
+	case strings.Contains(lower, "overloaded"), strings.Contains(lower, " 529"),
+		strings.Contains(lower, " 500"), strings.Contains(lower, " 502"), strings.Contains(lower, " 503"):
+		return APIErrorOverloaded
+	case strings.Contains(lower, "abort"), strings.Contains(lower, "timed out"),
+		strings.Contains(lower, "timeout"), strings.Contains(lower, "connection"):
+		return APIErrorAborted
+	default:
+		return APIErrorOther
+	}
+}
+
+// text returns the text of the entry's first text content block.
+func (e UsageEntry) text() string {
+	for _, c := range e.Message.Content {
+		if c.Type == "text" && c.Text != nil {
+			return *c.Text
+		}
+	}
+	return ""
+}
+
+// Skew returns how far the original timestamp of a clamped entry was
+// ahead of its clamped Timestamp. Zero for entries that were not clamped.
+func (e UsageEntry) Skew() time.Duration {
+	if e.ClampedFrom.IsZero() {
+		return 0
+	}
+	return e.ClampedFrom.Sub(e.Timestamp)
+}
+
+// ClampFuture guards against clock skew: entries dated more than
+// tolerance after limit get limit as their Timestamp, with the original
+// kept in ClampedFrom. Future timestamps would otherwise inflate burn
+// rates and keep billing blocks active.
+//
+// Returns the number of clamped entries.
+func ClampFuture(entries []UsageEntry, limit time.Time, tolerance time.Duration) int {
+	clamped := 0
+	cutoff := limit.Add(tolerance)
+	for i := range entries {
+		if entries[i].Timestamp.After(cutoff) {
+			entries[i].ClampedFrom = entries[i].Timestamp
+			entries[i].Timestamp = limit
+			clamped++
+		}
+	}
+	return clamped
 }
 
 // Message contains the API response details including token usage.
@@ -53,7 +173,7 @@ type Message struct {
 // - CacheCreationInputTokens: Tokens written to cache (1/2 price of input)
 // - CacheReadInputTokens: Tokens read from cache (1/10 price of input)
 //
-// Invariant: All token counts must be >= 0.
+// Invariant: All token counts must be in [0, MaxTokenCount].
 type Usage struct {
 	InputTokens              int `json:"input_tokens"`
 	OutputTokens             int `json:"output_tokens"`
@@ -104,19 +224,16 @@ func (e *UsageEntry) Validate() error {
 	return nil
 }
 
-// Validate checks if all token counts are non-negative.
+// Validate checks if all token counts are non-negative and at most
+// MaxTokenCount.
 func (u Usage) Validate() error {
-	if u.InputTokens < 0 {
-		return ErrNegativeTokenCount
-	}
-	if u.OutputTokens < 0 {
-		return ErrNegativeTokenCount
-	}
-	if u.CacheCreationInputTokens < 0 {
-		return ErrNegativeTokenCount
-	}
-	if u.CacheReadInputTokens < 0 {
-		return ErrNegativeTokenCount
+	for _, n := range []int{u.InputTokens, u.OutputTokens, u.CacheCreationInputTokens, u.CacheReadInputTokens} {
+		if n < 0 {
+			return ErrNegativeTokenCount
+		}
+		if n > MaxTokenCount {
+			return ErrTokenCountTooLarge
+		}
 	}
 	return nil
 }
@@ -0,0 +1,287 @@
+// Package plan models the subscription plans of a team and reports each
+// member's quota utilization and the team's aggregate spend.
+//
+// Members are the labels of Claude directories (config claude_config_dirs),
+// one per user or profile. Each plan has a price in its own currency and
+// optional monthly token and cost limits; amounts are converted to the
+// team's currency with configured exchange rates.
+//
+// Example usage:
+//
+//	team := plan.Team{
+//	    Currency: "EUR",
+//	    Rates:    plan.Rates{"USD": 0.92},
+//	    Plans: []plan.Plan{
+//	        {Name: "max", Price: 200, Currency: "USD", Members: []string{"alice"}},
+//	    },
+//	}
+//	report, err := team.Report(entries)
+package plan
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/0xmhha/token-monitor/pkg/parser"
+	"github.com/0xmhha/token-monitor/pkg/rollup"
+)
+
+// USD is the currency of estimated API costs.
+const USD = "USD"
+
+// ErrNoRate is returned when an amount cannot be converted to the team's
+// currency because its exchange rate is not configured.
+var ErrNoRate = errors.New("no exchange rate")
+
+// Plan is a subscription plan held by one or more members.
+type Plan struct {
+	// Name identifies the plan in reports.
+	Name string `json:"name"`
+
+	// Price is the monthly price per member, in Currency.
+	Price float64 `json:"price"`
+
+	// Currency is the currency code of Price. Empty is USD.
+	Currency string `json:"currency,omitempty"`
+
+	// TokenLimit is the monthly token quota per member. Zero is unlimited.
+	TokenLimit int `json:"token_limit,omitempty"`
+
+	// CostLimit is the monthly quota per member as estimated API cost in
+	// USD. Zero is unlimited.
+	CostLimit float64 `json:"cost_limit,omitempty"`
+
+	// Members are the labels of the Claude directories on this plan.
+	Members []string `json:"members"`
+}
+
+// Rates holds exchange rates into a team's currency: Rates["USD"] is the
+// value of one US dollar in the team's currency.
+type Rates map[string]float64
+
+// Convert converts amount in currency from into the team's currency to.
+// Amounts already in to are returned unchanged.
+func (r Rates) Convert(amount float64, from, to string) (float64, error) {
+	if from == "" {
+		from = USD
+	}
+	if from == to {
+		return amount, nil
+	}
+	rate, ok := r[from]
+	if !ok {
+		return 0, fmt.Errorf("%w for %s to %s", ErrNoRate, from, to)
+	}
+	return amount * rate, nil
+}
+
+// Team is a set of plans with the currency they are reported in.
+type Team struct {
+	// Currency is the currency of the report. Empty is USD.
+	Currency string
+
+	// Rates converts plan prices and API costs into Currency.
+	Rates Rates
+
+	// Plans are the team's plans. A member is on at most one plan.
+	Plans []Plan
+}
+
+// Member is one member's usage in a team report.
+type Member struct {
+	// Member is the Claude directory label, empty for unlabeled usage.
+	Member string `json:"member"`
+
+	// Plan is the name of the member's plan, empty without one.
+	Plan string `json:"plan,omitempty"`
+
+	// Totals sums the member's entries; CostUSD is the API-equivalent cost.
+	Totals rollup.Totals `json:"totals"`
+
+	// TokenUtilization is the percentage of the plan's token limit used.
+	// Nil without a limit.
+	TokenUtilization *float64 `json:"token_utilization,omitempty"`
+
+	// CostUtilization is the percentage of the plan's cost limit used.
+	// Nil without a limit.
+	CostUtilization *float64 `json:"cost_utilization,omitempty"`
+
+	// Spend is what the member costs in the team's currency: the plan
+	// price, or the API-equivalent cost for members without a plan.
+	Spend float64 `json:"spend"`
+}
+
+// Report is the usage and spend of a team over one month.
+type Report struct {
+	// Currency is the currency of Spend and APICost.
+	Currency string `json:"currency"`
+
+	// Members are ordered by plan and then by member.
+	Members []Member `json:"members"`
+
+	// Spend is the team's aggregate spend.
+	Spend float64 `json:"spend"`
+
+	// APICost is the API-equivalent cost of all usage, for comparison
+	// with Spend.
+	APICost float64 `json:"api_cost"`
+}
+
+// Report sums one month of entries per member (parser.UsageEntry.Source)
+// and computes quota utilization and spend. Members of a plan appear even
+// without usage, since their seat is paid for.
+func (t Team) Report(entries []parser.UsageEntry) (Report, error) {
+	currency := t.Currency
+	if currency == "" {
+		currency = USD
+	}
+	report := Report{Currency: currency}
+
+	plans := make(map[string]*Plan)
+	members := make(map[string]*Member)
+	for i := range t.Plans {
+		p := &t.Plans[i]
+		for _, name := range p.Members {
+			plans[name] = p
+			members[name] = &Member{Member: name, Plan: p.Name}
+		}
+	}
+	for _, entry := range entries {
+		m := members[entry.Source]
+		if m == nil {
+			m = &Member{Member: entry.Source}
+			members[entry.Source] = m
+		}
+		m.Totals.AddEntry(entry)
+	}
+
+	for name, m := range members {
+		apiCost, err := t.Rates.Convert(m.Totals.CostUSD, USD, currency)
+		if err != nil {
+			return Report{}, err
+		}
+		report.APICost += apiCost
+
+		p := plans[name]
+		if p == nil {
+			m.Spend = apiCost
+		} else {
+			if m.Spend, err = t.Rates.Convert(p.Price, p.Currency, currency); err != nil {
+				return Report{}, err
+			}
+			if p.TokenLimit > 0 {
+				used := float64(m.Totals.TotalTokens()) * 100 / float64(p.TokenLimit)
+				m.TokenUtilization = &used
+			}
+			if p.CostLimit > 0 {
+				used := m.Totals.CostUSD * 100 / p.CostLimit
+				m.CostUtilization = &used
+			}
+		}
+		report.Spend += m.Spend
+		report.Members = append(report.Members, *m)
+	}
+
+	// Members without a plan sort last.
+	sort.Slice(report.Members, func(i, j int) bool {
+		a, b := report.Members[i], report.Members[j]
+		if (a.Plan == "") != (b.Plan == "") {
+			return b.Plan == ""
+		}
+		if a.Plan != b.Plan {
+			return a.Plan < b.Plan
+		}
+		return a.Member < b.Member
+	})
+	return report, nil
+}
+
+// Standing is one member's place on a team leaderboard.
+type Standing struct {
+	// Rank is the member's place, starting at 1; members with equal usage
+	// share a rank.
+	Rank int `json:"rank"`
+
+	// Member is the Claude directory label, empty for unlabeled usage.
+	Member string `json:"member"`
+
+	// Plan is the name of the member's plan, empty without one.
+	Plan string `json:"plan,omitempty"`
+
+	// Totals sums the member's entries; CostUSD is the API-equivalent cost.
+	Totals rollup.Totals `json:"totals"`
+
+	// Sessions is the number of sessions with usage by the member.
+	Sessions int `json:"sessions"`
+
+	// Share is the member's percentage of the team's tokens, or of its
+	// cost when ranked by cost.
+	Share float64 `json:"share"`
+}
+
+// Leaderboard ranks the members by their usage in entries, most tokens
+// first, or the highest API-equivalent cost first with byCost. Members
+// of a plan appear even without usage.
+func (t Team) Leaderboard(entries []parser.UsageEntry, byCost bool) []Standing {
+	members := make(map[string]*Standing)
+	for _, p := range t.Plans {
+		for _, name := range p.Members {
+			members[name] = &Standing{Member: name, Plan: p.Name}
+		}
+	}
+	sessions := make(map[string]map[string]bool)
+	for _, entry := range entries {
+		s := members[entry.Source]
+		if s == nil {
+			s = &Standing{Member: entry.Source}
+			members[entry.Source] = s
+		}
+		s.Totals.AddEntry(entry)
+		if sessions[entry.Source] == nil {
+			sessions[entry.Source] = make(map[string]bool)
+		}
+		sessions[entry.Source][entry.SessionID] = true
+	}
+
+	board := make([]Standing, 0, len(members))
+	for name, s := range members {
+		s.Sessions = len(sessions[name])
+		board = append(board, *s)
+	}
+	return Rank(board, byCost)
+}
+
+// Rank sorts board by tokens, or by API-equivalent cost with byCost, and
+// sets each standing's Rank and Share. Ties are ordered by member.
+func Rank(board []Standing, byCost bool) []Standing {
+	measure := func(s *Standing) float64 {
+		if byCost {
+			return s.Totals.CostUSD
+		}
+		return float64(s.Totals.TotalTokens())
+	}
+	total := 0.0
+	for i := range board {
+		total += measure(&board[i])
+	}
+
+	sort.Slice(board, func(i, j int) bool {
+		a, b := measure(&board[i]), measure(&board[j])
+		if a != b {
+			return a > b
+		}
+		return board[i].Member < board[j].Member
+	})
+	for i := range board {
+		board[i].Share = 0
+		if total > 0 {
+			board[i].Share = measure(&board[i]) * 100 / total
+		}
+		board[i].Rank = i + 1
+		if i > 0 && measure(&board[i]) == measure(&board[i-1]) {
+			board[i].Rank = board[i-1].Rank
+		}
+	}
+	return board
+}
@@ -0,0 +1,130 @@
+package plan
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/0xmhha/token-monitor/pkg/parser"
+)
+
+// entry returns a sonnet entry of source with 1M input tokens ($3.00).
+func entry(source string) parser.UsageEntry {
+	return parser.UsageEntry{
+		SessionID: "s1",
+		Source:    source,
+		Timestamp: time.Date(2025, 11, 3, 10, 0, 0, 0, time.UTC),
+		Message: parser.Message{
+			Model: "claude-sonnet-4",
+			Usage: parser.Usage{InputTokens: 1_000_000},
+		},
+	}
+}
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+// percent returns the utilization, -1 for nil.
+func percent(p *float64) float64 {
+	if p == nil {
+		return -1
+	}
+	return *p
+}
+
+func TestTeamReport(t *testing.T) {
+	team := Team{
+		Currency: "EUR",
+		Rates:    Rates{"USD": 0.5},
+		Plans: []Plan{
+			{Name: "max", Price: 200, Currency: "USD", TokenLimit: 4_000_000, Members: []string{"alice", "bob"}},
+			{Name: "pro", Price: 20, Currency: "EUR", CostLimit: 12, Members: []string{"carol"}},
+		},
+	}
+	entries := []parser.UsageEntry{entry("alice"), entry("alice"), entry("carol"), entry("dave")}
+
+	report, err := team.Report(entries)
+	if err != nil {
+		t.Fatalf("Report() error = %v", err)
+	}
+	if len(report.Members) != 4 {
+		t.Fatalf("Report() = %d members, want 4", len(report.Members))
+	}
+	want := []struct {
+		member, plan string
+		tokenUtil    float64
+		costUtil     float64
+		spend        float64
+	}{
+		{"alice", "max", 50, -1, 100},
+		{"bob", "max", 0, -1, 100},
+		{"carol", "pro", -1, 25, 20},
+		{"dave", "", -1, -1, 1.5},
+	}
+	for i, w := range want {
+		m := report.Members[i]
+		if m.Member != w.member || m.Plan != w.plan || !near(percent(m.TokenUtilization), w.tokenUtil) ||
+			!near(percent(m.CostUtilization), w.costUtil) || !near(m.Spend, w.spend) {
+			t.Errorf("member %d = %+v, want %+v", i, m, w)
+		}
+	}
+	if !near(report.Spend, 221.5) || !near(report.APICost, 6) {
+		t.Errorf("Spend = %v, APICost = %v; want 221.5, 6", report.Spend, report.APICost)
+	}
+}
+
+func TestTeamReportMissingRate(t *testing.T) {
+	team := Team{
+		Currency: "EUR",
+		Plans:    []Plan{{Name: "max", Price: 200, Currency: "USD", Members: []string{"alice"}}},
+	}
+	if _, err := team.Report(nil); !errors.Is(err, ErrNoRate) {
+		t.Errorf("Report() error = %v, want ErrNoRate", err)
+	}
+
+	report, err := Team{}.Report([]parser.UsageEntry{entry("")})
+	if err != nil || report.Currency != USD || !near(report.Spend, 3) {
+		t.Errorf("Report() = %+v, %v; want $3 spend in USD", report, err)
+	}
+}
+
+func TestLeaderboard(t *testing.T) {
+	team := Team{Plans: []Plan{{Name: "max", Members: []string{"alice", "erin"}}}}
+	opus := entry("bob")
+	opus.Message.Model = "claude-opus-4"
+	opus.SessionID = "s2"
+	entries := []parser.UsageEntry{entry("alice"), entry("alice"), opus, entry("carol")}
+
+	board := team.Leaderboard(entries, false)
+	want := []struct {
+		rank     int
+		member   string
+		sessions int
+	}{
+		{1, "alice", 1},
+		{2, "bob", 1},
+		{2, "carol", 1},
+		{4, "erin", 0},
+	}
+	if len(board) != len(want) {
+		t.Fatalf("Leaderboard() = %+v, want %d members", board, len(want))
+	}
+	for i, w := range want {
+		s := board[i]
+		if s.Rank != w.rank || s.Member != w.member || s.Sessions != w.sessions {
+			t.Errorf("board[%d] = rank %d %s (%d sessions), want rank %d %s (%d sessions)",
+				i, s.Rank, s.Member, s.Sessions, w.rank, w.member, w.sessions)
+		}
+	}
+	if !near(board[0].Share, 50) || board[3].Plan != "max" {
+		t.Errorf("alice share = %f, erin plan = %q; want 50, max", board[0].Share, board[3].Plan)
+	}
+
+	// By cost, opus input is five times the price of sonnet input.
+	board = team.Leaderboard(entries, true)
+	if board[0].Member != "bob" || !near(board[0].Share, 62.5) {
+		t.Errorf("by cost: first = %s with %f%%, want bob with 62.5%%", board[0].Member, board[0].Share)
+	}
+}
@@ -0,0 +1,15 @@
+package query
+
+import "errors"
+
+// Common errors returned when parsing or running a query.
+var (
+	// ErrSyntax is returned when the query text cannot be parsed.
+	ErrSyntax = errors.New("query syntax error")
+
+	// ErrUnknownColumn is returned when a query references an unknown column.
+	ErrUnknownColumn = errors.New("unknown column")
+
+	// ErrInvalidQuery is returned when a parsed query is semantically invalid.
+	ErrInvalidQuery = errors.New("invalid query")
+)
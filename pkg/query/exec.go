@@ -0,0 +1,305 @@
+package query
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/0xmhha/token-monitor/pkg/parser"
+)
+
+// predicate reports whether an entry matches a WHERE condition.
+type predicate func(e parser.UsageEntry) bool
+
+// accumulator folds entry values for one aggregate select item.
+type accumulator struct {
+	count int
+	sum   float64
+	min   float64
+	max   float64
+}
+
+// add folds v into the accumulator.
+func (a *accumulator) add(v float64) {
+	if a.count == 0 || v < a.min {
+		a.min = v
+	}
+	if a.count == 0 || v > a.max {
+		a.max = v
+	}
+	a.count++
+	a.sum += v
+}
+
+// result returns the aggregate value for fn.
+func (a *accumulator) result(fn string) interface{} {
+	switch fn {
+	case "count":
+		return float64(a.count)
+	case "sum":
+		return a.sum
+	case "avg":
+		if a.count == 0 {
+			return 0.0
+		}
+		return a.sum / float64(a.count)
+	case "min":
+		return a.min
+	default:
+		return a.max
+	}
+}
+
+// groupState holds the key values and accumulators for one group.
+type groupState struct {
+	keys map[string]interface{}
+	accs []accumulator
+}
+
+// Run executes the query against entries.
+func (q *Query) Run(entries []parser.UsageEntry) (*Result, error) {
+	predicates, err := q.predicates()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{Columns: make([]string, len(q.Select))}
+	for i, item := range q.Select {
+		result.Columns[i] = item.Name()
+	}
+
+	matched := make([]parser.UsageEntry, 0, len(entries))
+	for _, e := range entries {
+		if matchesAll(predicates, e) {
+			matched = append(matched, e)
+		}
+	}
+
+	if q.aggregated() {
+		result.Rows = q.groupRows(matched)
+	} else {
+		result.Rows = q.plainRows(matched)
+	}
+
+	q.sortRows(result.Rows)
+	if q.Limit > 0 && len(result.Rows) > q.Limit {
+		result.Rows = result.Rows[:q.Limit]
+	}
+	return result, nil
+}
+
+// aggregated reports whether the query produces grouped rows.
+func (q *Query) aggregated() bool {
+	if len(q.GroupBy) > 0 {
+		return true
+	}
+	for _, item := range q.Select {
+		if item.Func != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// plainRows produces one row per entry.
+func (q *Query) plainRows(entries []parser.UsageEntry) [][]interface{} {
+	rows := make([][]interface{}, len(entries))
+	for i, e := range entries {
+		row := make([]interface{}, len(q.Select))
+		for j, item := range q.Select {
+			row[j] = outputValue(columns[item.Column].value(e))
+		}
+		rows[i] = row
+	}
+	return rows
+}
+
+// groupRows aggregates entries by the GROUP BY columns.
+// Without GROUP BY, all entries form a single group.
+func (q *Query) groupRows(entries []parser.UsageEntry) [][]interface{} {
+	groups := make(map[string]*groupState)
+	var order []string
+
+	for _, e := range entries {
+		keyParts := make([]string, len(q.GroupBy))
+		keys := make(map[string]interface{}, len(q.GroupBy))
+		for i, name := range q.GroupBy {
+			v := outputValue(columns[name].value(e))
+			keys[name] = v
+			keyParts[i] = FormatValue(v)
+		}
+		key := strings.Join(keyParts, "\x00")
+
+		g, ok := groups[key]
+		if !ok {
+			g = &groupState{keys: keys, accs: make([]accumulator, len(q.Select))}
+			groups[key] = g
+			order = append(order, key)
+		}
+
+		for i, item := range q.Select {
+			switch {
+			case item.Func == "":
+				continue
+			case item.Column == "*":
+				g.accs[i].add(0)
+			default:
+				g.accs[i].add(columns[item.Column].value(e).(float64))
+			}
+		}
+	}
+
+	// An aggregate over no rows still yields one row, as in SQL.
+	if len(order) == 0 && len(q.GroupBy) == 0 {
+		order = append(order, "")
+		groups[""] = &groupState{accs: make([]accumulator, len(q.Select))}
+	}
+
+	// Natural order is by group key.
+	sort.Strings(order)
+
+	rows := make([][]interface{}, 0, len(order))
+	for _, key := range order {
+		g := groups[key]
+		row := make([]interface{}, len(q.Select))
+		for i, item := range q.Select {
+			if item.Func == "" {
+				row[i] = g.keys[item.Column]
+			} else {
+				row[i] = g.accs[i].result(item.Func)
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// sortRows applies ORDER BY, keeping ties in their natural order.
+func (q *Query) sortRows(rows [][]interface{}) {
+	if q.OrderBy == "" {
+		return
+	}
+	idx := q.orderIndex()
+	sort.SliceStable(rows, func(i, j int) bool {
+		if q.Desc {
+			return lessValue(rows[j][idx], rows[i][idx])
+		}
+		return lessValue(rows[i][idx], rows[j][idx])
+	})
+}
+
+// lessValue orders numbers numerically and everything else as text.
+func lessValue(a, b interface{}) bool {
+	af, aok := a.(float64)
+	bf, bok := b.(float64)
+	if aok && bok {
+		return af < bf
+	}
+	return FormatValue(a) < FormatValue(b)
+}
+
+// outputValue converts column values to their result representation.
+func outputValue(v interface{}) interface{} {
+	if t, ok := v.(time.Time); ok {
+		return t.Format(time.RFC3339)
+	}
+	return v
+}
+
+// matchesAll reports whether e satisfies every predicate.
+func matchesAll(predicates []predicate, e parser.UsageEntry) bool {
+	for _, p := range predicates {
+		if !p(e) {
+			return false
+		}
+	}
+	return true
+}
+
+// predicates compiles WHERE conditions. Literals were checked in validate.
+func (q *Query) predicates() ([]predicate, error) {
+	predicates := make([]predicate, 0, len(q.Where))
+	for _, cond := range q.Where {
+		col := columns[cond.Column]
+		cond := cond
+
+		if cond.Op == "like" {
+			re, err := likePattern(cond.Value)
+			if err != nil {
+				return nil, err
+			}
+			predicates = append(predicates, func(e parser.UsageEntry) bool {
+				return re.MatchString(col.value(e).(string))
+			})
+			continue
+		}
+
+		switch col.kind {
+		case kindNumber:
+			want, _ := strconv.ParseFloat(cond.Value, 64) //nolint:errcheck // validated
+			predicates = append(predicates, func(e parser.UsageEntry) bool {
+				return compare(cond.Op, cmpFloat(col.value(e).(float64), want))
+			})
+		case kindTime:
+			want, _ := parseTime(cond.Value) //nolint:errcheck // validated
+			predicates = append(predicates, func(e parser.UsageEntry) bool {
+				return compare(cond.Op, col.value(e).(time.Time).Compare(want))
+			})
+		default:
+			predicates = append(predicates, func(e parser.UsageEntry) bool {
+				return compare(cond.Op, strings.Compare(col.value(e).(string), cond.Value))
+			})
+		}
+	}
+	return predicates, nil
+}
+
+// cmpFloat returns -1, 0, or 1 comparing a and b.
+func cmpFloat(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// compare applies a comparison operator to a three-way comparison result.
+func compare(op string, c int) bool {
+	switch op {
+	case "=":
+		return c == 0
+	case "!=", "<>":
+		return c != 0
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	default:
+		return c >= 0
+	}
+}
+
+// likePattern compiles a SQL LIKE pattern into a case-insensitive regexp.
+func likePattern(pattern string) (*regexp.Regexp, error) {
+	var sb strings.Builder
+	sb.WriteString("(?is)^")
+	for _, r := range pattern {
+		switch r {
+		case '%':
+			sb.WriteString(".*")
+		case '_':
+			sb.WriteString(".")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	sb.WriteString("$")
+	return regexp.Compile(sb.String())
+}
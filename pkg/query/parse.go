@@ -0,0 +1,487 @@
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// tokenKind classifies lexer tokens.
+type tokenKind int
+
+const (
+	tokIdent tokenKind = iota
+	tokNumber
+	tokString
+	tokSymbol
+	tokEOF
+)
+
+// token is a single lexical token.
+type token struct {
+	kind tokenKind
+	text string
+}
+
+// aggregates is the set of supported aggregate functions.
+var aggregates = map[string]bool{
+	"count": true,
+	"sum":   true,
+	"avg":   true,
+	"min":   true,
+	"max":   true,
+}
+
+// comparisons is the set of supported WHERE operators.
+var comparisons = map[string]bool{
+	"=": true, "!=": true, "<>": true,
+	"<": true, "<=": true, ">": true, ">=": true,
+	"like": true,
+}
+
+// tokenize splits query text into tokens.
+func tokenize(text string) ([]token, error) {
+	var tokens []token
+	runes := []rune(text)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '\'':
+			j := i + 1
+			var sb strings.Builder
+			for ; j < len(runes); j++ {
+				if runes[j] == '\'' {
+					// '' escapes a single quote inside a string.
+					if j+1 < len(runes) && runes[j+1] == '\'' {
+						sb.WriteRune('\'')
+						j++
+						continue
+					}
+					break
+				}
+				sb.WriteRune(runes[j])
+			}
+			if j >= len(runes) {
+				return nil, fmt.Errorf("%w: unterminated string", ErrSyntax)
+			}
+			tokens = append(tokens, token{tokString, sb.String()})
+			i = j + 1
+		case unicode.IsDigit(r) || (r == '.' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			j := i
+			for j < len(runes) && (unicode.IsDigit(runes[j]) || runes[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, token{tokNumber, string(runes[i:j])})
+			i = j
+		case unicode.IsLetter(r) || r == '_':
+			j := i
+			for j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j]) || runes[j] == '_') {
+				j++
+			}
+			tokens = append(tokens, token{tokIdent, string(runes[i:j])})
+			i = j
+		case strings.ContainsRune("<>!=", r):
+			j := i + 1
+			if j < len(runes) && (runes[j] == '=' || (r == '<' && runes[j] == '>')) {
+				j++
+			}
+			op := string(runes[i:j])
+			if op == "!" {
+				return nil, fmt.Errorf("%w: unexpected %q", ErrSyntax, op)
+			}
+			tokens = append(tokens, token{tokSymbol, op})
+			i = j
+		case strings.ContainsRune(",()*", r):
+			tokens = append(tokens, token{tokSymbol, string(r)})
+			i++
+		default:
+			return nil, fmt.Errorf("%w: unexpected character %q", ErrSyntax, r)
+		}
+	}
+
+	return append(tokens, token{kind: tokEOF}), nil
+}
+
+// queryParser is a recursive-descent parser over tokens.
+type queryParser struct {
+	tokens []token
+	pos    int
+}
+
+// peek returns the current token.
+func (p *queryParser) peek() token {
+	return p.tokens[p.pos]
+}
+
+// next consumes and returns the current token.
+func (p *queryParser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+// isKeyword reports whether the current token is the given keyword.
+func (p *queryParser) isKeyword(keyword string) bool {
+	t := p.peek()
+	return t.kind == tokIdent && strings.EqualFold(t.text, keyword)
+}
+
+// expectKeyword consumes the given keyword or fails.
+func (p *queryParser) expectKeyword(keyword string) error {
+	if !p.isKeyword(keyword) {
+		return p.unexpected(strings.ToUpper(keyword))
+	}
+	p.next()
+	return nil
+}
+
+// expectSymbol consumes the given symbol or fails.
+func (p *queryParser) expectSymbol(symbol string) error {
+	t := p.peek()
+	if t.kind != tokSymbol || t.text != symbol {
+		return p.unexpected(symbol)
+	}
+	p.next()
+	return nil
+}
+
+// ident consumes an identifier and returns it lowercased.
+func (p *queryParser) ident(what string) (string, error) {
+	t := p.peek()
+	if t.kind != tokIdent {
+		return "", p.unexpected(what)
+	}
+	p.next()
+	return strings.ToLower(t.text), nil
+}
+
+// unexpected builds a syntax error describing the current token.
+func (p *queryParser) unexpected(want string) error {
+	t := p.peek()
+	if t.kind == tokEOF {
+		return fmt.Errorf("%w: expected %s, got end of query", ErrSyntax, want)
+	}
+	return fmt.Errorf("%w: expected %s, got %q", ErrSyntax, want, t.text)
+}
+
+// Parse parses query text into a validated Query.
+func Parse(text string) (*Query, error) {
+	tokens, err := tokenize(text)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &queryParser{tokens: tokens}
+	q, err := p.parseQuery()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := q.validate(); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// parseQuery parses the full SELECT statement.
+func (p *queryParser) parseQuery() (*Query, error) {
+	q := &Query{}
+
+	if err := p.expectKeyword("select"); err != nil {
+		return nil, err
+	}
+	items, err := p.parseSelectList()
+	if err != nil {
+		return nil, err
+	}
+	q.Select = items
+
+	if p.isKeyword("from") {
+		p.next()
+		table, err := p.ident("table name")
+		if err != nil {
+			return nil, err
+		}
+		if table != "entries" {
+			return nil, fmt.Errorf("%w: unknown table %q (only \"entries\" is supported)", ErrInvalidQuery, table)
+		}
+	}
+
+	if p.isKeyword("where") {
+		p.next()
+		if q.Where, err = p.parseConditions(); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.isKeyword("group") {
+		p.next()
+		if err := p.expectKeyword("by"); err != nil {
+			return nil, err
+		}
+		if q.GroupBy, err = p.parseIdentList("column"); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.isKeyword("order") {
+		p.next()
+		if err := p.expectKeyword("by"); err != nil {
+			return nil, err
+		}
+		if q.OrderBy, err = p.parseOrderTarget(); err != nil {
+			return nil, err
+		}
+		switch {
+		case p.isKeyword("desc"):
+			p.next()
+			q.Desc = true
+		case p.isKeyword("asc"):
+			p.next()
+		}
+	}
+
+	if p.isKeyword("limit") {
+		p.next()
+		t := p.next()
+		n, convErr := strconv.Atoi(t.text)
+		if t.kind != tokNumber || convErr != nil || n < 0 {
+			return nil, fmt.Errorf("%w: LIMIT expects a non-negative integer", ErrSyntax)
+		}
+		q.Limit = n
+	}
+
+	if p.peek().kind != tokEOF {
+		return nil, p.unexpected("end of query")
+	}
+
+	return q, nil
+}
+
+// parseSelectList parses comma-separated select items.
+func (p *queryParser) parseSelectList() ([]SelectItem, error) {
+	var items []SelectItem
+	for {
+		item, err := p.parseSelectItem()
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+
+		if t := p.peek(); t.kind != tokSymbol || t.text != "," {
+			return items, nil
+		}
+		p.next()
+	}
+}
+
+// parseSelectItem parses a column or aggregate with an optional alias.
+func (p *queryParser) parseSelectItem() (SelectItem, error) {
+	name, err := p.ident("column or aggregate")
+	if err != nil {
+		return SelectItem{}, err
+	}
+
+	item := SelectItem{Column: name}
+	if t := p.peek(); t.kind == tokSymbol && t.text == "(" {
+		if !aggregates[name] {
+			return SelectItem{}, fmt.Errorf("%w: unknown function %q", ErrInvalidQuery, name)
+		}
+		p.next()
+		item.Func = name
+		if t := p.peek(); t.kind == tokSymbol && t.text == "*" {
+			p.next()
+			item.Column = "*"
+		} else if item.Column, err = p.ident("column"); err != nil {
+			return SelectItem{}, err
+		}
+		if err := p.expectSymbol(")"); err != nil {
+			return SelectItem{}, err
+		}
+	}
+
+	if p.isKeyword("as") {
+		p.next()
+		if item.Alias, err = p.ident("alias"); err != nil {
+			return SelectItem{}, err
+		}
+	}
+
+	return item, nil
+}
+
+// parseConditions parses AND-joined comparisons.
+func (p *queryParser) parseConditions() ([]Condition, error) {
+	var conditions []Condition
+	for {
+		col, err := p.ident("column")
+		if err != nil {
+			return nil, err
+		}
+
+		t := p.next()
+		op := strings.ToLower(t.text)
+		if (t.kind != tokSymbol && t.kind != tokIdent) || !comparisons[op] {
+			return nil, fmt.Errorf("%w: expected comparison operator after %s", ErrSyntax, col)
+		}
+
+		value := p.next()
+		if value.kind != tokString && value.kind != tokNumber {
+			return nil, fmt.Errorf("%w: expected literal after %s %s", ErrSyntax, col, t.text)
+		}
+		conditions = append(conditions, Condition{Column: col, Op: op, Value: value.text})
+
+		if p.isKeyword("or") {
+			return nil, fmt.Errorf("%w: OR is not supported; conditions are joined with AND", ErrInvalidQuery)
+		}
+		if !p.isKeyword("and") {
+			return conditions, nil
+		}
+		p.next()
+	}
+}
+
+// parseIdentList parses comma-separated identifiers.
+func (p *queryParser) parseIdentList(what string) ([]string, error) {
+	var names []string
+	for {
+		name, err := p.ident(what)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+
+		if t := p.peek(); t.kind != tokSymbol || t.text != "," {
+			return names, nil
+		}
+		p.next()
+	}
+}
+
+// parseOrderTarget parses an ORDER BY target: a name or an aggregate
+// expression such as sum(total), returned in its SelectItem.Name form.
+func (p *queryParser) parseOrderTarget() (string, error) {
+	name, err := p.ident("column")
+	if err != nil {
+		return "", err
+	}
+	if t := p.peek(); t.kind != tokSymbol || t.text != "(" {
+		return name, nil
+	}
+
+	p.next()
+	inner := "*"
+	if t := p.peek(); t.kind == tokSymbol && t.text == "*" {
+		p.next()
+	} else if inner, err = p.ident("column"); err != nil {
+		return "", err
+	}
+	if err := p.expectSymbol(")"); err != nil {
+		return "", err
+	}
+	return name + "(" + inner + ")", nil
+}
+
+// validate checks column references and grouping rules.
+func (q *Query) validate() error {
+	aggregated := len(q.GroupBy) > 0
+
+	for _, item := range q.Select {
+		if item.Func != "" {
+			aggregated = true
+		}
+		if item.Column == "*" {
+			if item.Func != "count" {
+				return fmt.Errorf("%w: * is only valid in count(*)", ErrInvalidQuery)
+			}
+			continue
+		}
+		col, ok := columns[item.Column]
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrUnknownColumn, item.Column)
+		}
+		if item.Func != "" && item.Func != "count" && col.kind != kindNumber {
+			return fmt.Errorf("%w: %s() requires a numeric column, got %s", ErrInvalidQuery, item.Func, item.Column)
+		}
+	}
+
+	for _, name := range q.GroupBy {
+		if _, ok := columns[name]; !ok {
+			return fmt.Errorf("%w: %s", ErrUnknownColumn, name)
+		}
+	}
+
+	if aggregated {
+		for _, item := range q.Select {
+			if item.Func == "" && !contains(q.GroupBy, item.Column) {
+				return fmt.Errorf("%w: %s must appear in GROUP BY or be aggregated", ErrInvalidQuery, item.Column)
+			}
+		}
+	}
+
+	for _, cond := range q.Where {
+		col, ok := columns[cond.Column]
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrUnknownColumn, cond.Column)
+		}
+		if err := checkLiteral(col.kind, cond); err != nil {
+			return err
+		}
+	}
+
+	if q.OrderBy != "" && q.orderIndex() < 0 {
+		return fmt.Errorf("%w: ORDER BY %s must name a selected column", ErrInvalidQuery, q.OrderBy)
+	}
+
+	return nil
+}
+
+// checkLiteral validates a condition literal against the column kind.
+func checkLiteral(kind columnKind, cond Condition) error {
+	if cond.Op == "like" {
+		if kind != kindString {
+			return fmt.Errorf("%w: LIKE requires a text column, got %s", ErrInvalidQuery, cond.Column)
+		}
+		return nil
+	}
+
+	switch kind {
+	case kindNumber:
+		if _, err := strconv.ParseFloat(cond.Value, 64); err != nil {
+			return fmt.Errorf("%w: %s expects a number, got %q", ErrInvalidQuery, cond.Column, cond.Value)
+		}
+	case kindTime:
+		if _, err := parseTime(cond.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// orderIndex returns the select index matching OrderBy, or -1.
+// OrderBy may use either an item's alias or its unaliased expression.
+func (q *Query) orderIndex() int {
+	for i, item := range q.Select {
+		expr := SelectItem{Func: item.Func, Column: item.Column}.Name()
+		if q.OrderBy == item.Name() || q.OrderBy == expr {
+			return i
+		}
+	}
+	return -1
+}
+
+// contains reports whether names includes name.
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,170 @@
+package query
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/0xmhha/token-monitor/pkg/parser"
+)
+
+// makeEntry builds a UsageEntry for tests.
+func makeEntry(session, model string, ts time.Time, in, out int) parser.UsageEntry {
+	return parser.UsageEntry{
+		SessionID: session,
+		Timestamp: ts,
+		Message: parser.Message{
+			Model: model,
+			Usage: parser.Usage{InputTokens: in, OutputTokens: out},
+		},
+	}
+}
+
+func testEntries() []parser.UsageEntry {
+	day1 := time.Date(2025, 10, 31, 12, 0, 0, 0, time.UTC)
+	day2 := time.Date(2025, 11, 2, 9, 0, 0, 0, time.UTC)
+	return []parser.UsageEntry{
+		makeEntry("s1", "claude-sonnet-4", day1, 100, 50),
+		makeEntry("s1", "claude-opus-4", day2, 200, 100),
+		makeEntry("s2", "claude-sonnet-4", day2, 300, 150),
+		makeEntry("s2", "claude-sonnet-4", day2.Add(time.Hour), 10, 5),
+	}
+}
+
+func runQuery(t *testing.T, text string) *Result {
+	t.Helper()
+	q, err := Parse(text)
+	if err != nil {
+		t.Fatalf("Parse(%q) error = %v", text, err)
+	}
+	result, err := q.Run(testEntries())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	return result
+}
+
+func TestGroupByWithFilter(t *testing.T) {
+	result := runQuery(t, "SELECT model, sum(total) FROM entries WHERE ts > '2025-11-01' GROUP BY model")
+
+	if got := result.Columns; len(got) != 2 || got[0] != "model" || got[1] != "sum(total)" {
+		t.Fatalf("Columns = %v", got)
+	}
+	if len(result.Rows) != 2 {
+		t.Fatalf("got %d rows, want 2", len(result.Rows))
+	}
+	// Natural order is by group key.
+	if result.Rows[0][0] != "claude-opus-4" || result.Rows[0][1] != 300.0 {
+		t.Errorf("row 0 = %v, want [claude-opus-4 300]", result.Rows[0])
+	}
+	if result.Rows[1][0] != "claude-sonnet-4" || result.Rows[1][1] != 465.0 {
+		t.Errorf("row 1 = %v, want [claude-sonnet-4 465]", result.Rows[1])
+	}
+}
+
+func TestGroupBySubAgent(t *testing.T) {
+	day := time.Date(2025, 11, 2, 9, 0, 0, 0, time.UTC)
+	agent := makeEntry("s1", "claude-sonnet-4", day, 10, 0)
+	agent.SubAgentID = "agent-1"
+	sidechain := makeEntry("s1", "claude-sonnet-4", day, 20, 0)
+	sidechain.IsSidechain = true
+
+	q, err := Parse("SELECT subagent, sum(input) GROUP BY subagent")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := q.Run([]parser.UsageEntry{makeEntry("s1", "claude-sonnet-4", day, 40, 0), agent, sidechain})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The same keys as stats -group-by subagent.
+	want := map[string]float64{"agent-1": 10, "main": 40, "sidechain": 20}
+	if len(result.Rows) != len(want) {
+		t.Fatalf("rows = %v, want %v", result.Rows, want)
+	}
+	for _, row := range result.Rows {
+		if want[row[0].(string)] != row[1] {
+			t.Errorf("row %v, want %v", row, want)
+		}
+	}
+}
+
+func TestOrderByAliasAndLimit(t *testing.T) {
+	result := runQuery(t, "select session, count(*) as n, avg(input) as a from entries group by session order by a desc limit 1")
+
+	if len(result.Rows) != 1 {
+		t.Fatalf("got %d rows, want 1", len(result.Rows))
+	}
+	row := result.Rows[0]
+	if row[0] != "s2" || row[1] != 2.0 || row[2] != 155.0 {
+		t.Errorf("row = %v, want [s2 2 155]", row)
+	}
+}
+
+func TestPlainSelectAndLike(t *testing.T) {
+	result := runQuery(t, "SELECT ts, model, output WHERE model LIKE '%OPUS%' AND output >= 100")
+
+	if len(result.Rows) != 1 {
+		t.Fatalf("got %d rows, want 1", len(result.Rows))
+	}
+	if result.Rows[0][0] != "2025-11-02T09:00:00Z" {
+		t.Errorf("ts = %v, want RFC 3339 string", result.Rows[0][0])
+	}
+}
+
+func TestAggregateWithoutRows(t *testing.T) {
+	result := runQuery(t, "SELECT count(*), sum(total) WHERE model = 'none'")
+
+	if len(result.Rows) != 1 || result.Rows[0][0] != 0.0 {
+		t.Errorf("Rows = %v, want a single zero row", result.Rows)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  error
+	}{
+		{"missing select", "model FROM entries", ErrSyntax},
+		{"unknown column", "SELECT tokens FROM entries", ErrUnknownColumn},
+		{"unknown table", "SELECT model FROM sessions", ErrInvalidQuery},
+		{"ungrouped column", "SELECT model, sum(total) FROM entries", ErrInvalidQuery},
+		{"sum of text", "SELECT sum(model) FROM entries", ErrInvalidQuery},
+		{"bad number literal", "SELECT model WHERE total > 'lots'", ErrInvalidQuery},
+		{"bad time literal", "SELECT model WHERE ts > 'yesterday'", ErrInvalidQuery},
+		{"or unsupported", "SELECT model WHERE total > 1 OR total < 0", ErrInvalidQuery},
+		{"order by unselected", "SELECT model ORDER BY total", ErrInvalidQuery},
+		{"unterminated string", "SELECT model WHERE model = 'x", ErrSyntax},
+		{"trailing tokens", "SELECT model FROM entries extra", ErrSyntax},
+		{"bad limit", "SELECT model LIMIT -1", ErrSyntax},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.query)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Parse(%q) error = %v, want %v", tt.query, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatValue(t *testing.T) {
+	tests := []struct {
+		value interface{}
+		want  string
+	}{
+		{465.0, "465"},
+		{0.125, "0.1250"},
+		{"text", "text"},
+		{nil, ""},
+	}
+
+	for _, tt := range tests {
+		if got := FormatValue(tt.value); got != tt.want {
+			t.Errorf("FormatValue(%v) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+// Package query provides a small SQL-like language over usage entries.
+//
+// Queries select columns or aggregates from the "entries" table, with
+// optional filtering, grouping, ordering, and limiting:
+//
+//	SELECT model, sum(total) AS tokens FROM entries
+//	WHERE ts > '2025-11-01' AND model LIKE '%sonnet%'
+//	GROUP BY model ORDER BY tokens DESC LIMIT 5
+//
+// Example usage:
+//
+//	q, err := query.Parse(text)
+//	if err != nil {
+//	    return err
+//	}
+//	result, err := q.Run(entries)
+//
+// Supported columns are listed in Columns. Aggregates are count, sum, avg,
+// min, and max. WHERE conditions are joined with AND and compare a column
+// to a literal using =, !=, <>, <, <=, >, >=, or LIKE (% and _ wildcards,
+// case-insensitive). The ts column compares against date or RFC 3339 strings.
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/0xmhha/token-monitor/pkg/aggregator"
+	"github.com/0xmhha/token-monitor/pkg/analysis"
+	"github.com/0xmhha/token-monitor/pkg/parser"
+)
+
+// Query is a parsed query.
+type Query struct {
+	// Select lists the output columns in order.
+	Select []SelectItem
+
+	// Where conditions, all of which must match.
+	Where []Condition
+
+	// GroupBy lists the grouping columns.
+	GroupBy []string
+
+	// OrderBy names a selected column or alias; empty keeps natural order.
+	OrderBy string
+
+	// Desc sorts OrderBy in descending order.
+	Desc bool
+
+	// Limit caps the number of rows; 0 means no limit.
+	Limit int
+}
+
+// SelectItem is a single output column: a plain column or an aggregate.
+type SelectItem struct {
+	// Func is the aggregate function (count, sum, avg, min, max), or empty.
+	Func string
+
+	// Column is the source column; "*" for count(*).
+	Column string
+
+	// Alias is the optional AS name.
+	Alias string
+}
+
+// Name returns the output column name.
+func (s SelectItem) Name() string {
+	if s.Alias != "" {
+		return s.Alias
+	}
+	if s.Func != "" {
+		return s.Func + "(" + s.Column + ")"
+	}
+	return s.Column
+}
+
+// Condition compares a column to a literal value.
+type Condition struct {
+	Column string
+	Op     string
+	Value  string
+}
+
+// Result holds query output. Values are strings or float64s.
+type Result struct {
+	Columns []string
+	Rows    [][]interface{}
+}
+
+// Records returns rows as column-name keyed maps, for JSON output.
+func (r *Result) Records() []map[string]interface{} {
+	records := make([]map[string]interface{}, len(r.Rows))
+	for i, row := range r.Rows {
+		record := make(map[string]interface{}, len(r.Columns))
+		for j, col := range r.Columns {
+			record[col] = row[j]
+		}
+		records[i] = record
+	}
+	return records
+}
+
+// FormatValue renders a result value for display. Whole numbers are
+// printed without a fractional part.
+func FormatValue(v interface{}) string {
+	switch val := v.(type) {
+	case float64:
+		if val == float64(int64(val)) {
+			return strconv.FormatInt(int64(val), 10)
+		}
+		return strconv.FormatFloat(val, 'f', 4, 64)
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(val)
+	}
+}
+
+// columnKind describes how a column's values are compared and aggregated.
+type columnKind int
+
+const (
+	kindString columnKind = iota
+	kindNumber
+	kindTime
+)
+
+// column extracts a value from an entry.
+type column struct {
+	kind  columnKind
+	value func(e parser.UsageEntry) interface{}
+}
+
+// Columns lists the queryable column names.
+var Columns = []string{
+	"session", "subagent", "model", "project", "version", "ts", "date", "hour",
+	"input", "output", "cache_creation", "cache_read", "total", "cost",
+}
+
+// columns maps column names to their extractors.
+var columns = map[string]column{
+	"session":  {kindString, func(e parser.UsageEntry) interface{} { return e.SessionID }},
+	"subagent": {kindString, func(e parser.UsageEntry) interface{} { return aggregator.SubAgentKey(e) }},
+	"model":    {kindString, func(e parser.UsageEntry) interface{} { return e.Message.Model }},
+	"project":  {kindString, func(e parser.UsageEntry) interface{} { return e.CurrentDir }},
+	"version":  {kindString, func(e parser.UsageEntry) interface{} { return e.Version }},
+	"ts":       {kindTime, func(e parser.UsageEntry) interface{} { return e.Timestamp }},
+	"date": {kindString, func(e parser.UsageEntry) interface{} {
+		return e.Timestamp.Format("2006-01-02")
+	}},
+	"hour": {kindString, func(e parser.UsageEntry) interface{} {
+		return e.Timestamp.Format("2006-01-02 15:00")
+	}},
+	"input": {kindNumber, func(e parser.UsageEntry) interface{} {
+		return float64(e.Message.Usage.InputTokens)
+	}},
+	"output": {kindNumber, func(e parser.UsageEntry) interface{} {
+		return float64(e.Message.Usage.OutputTokens)
+	}},
+	"cache_creation": {kindNumber, func(e parser.UsageEntry) interface{} {
+		return float64(e.Message.Usage.CacheCreationInputTokens)
+	}},
+	"cache_read": {kindNumber, func(e parser.UsageEntry) interface{} {
+		return float64(e.Message.Usage.CacheReadInputTokens)
+	}},
+	"total": {kindNumber, func(e parser.UsageEntry) interface{} {
+		return float64(e.Message.Usage.TotalTokens())
+	}},
+	"cost": {kindNumber, func(e parser.UsageEntry) interface{} { return analysis.EntryCost(e) }},
+}
+
+// timeLayouts are the accepted formats for ts literals.
+var timeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// parseTime parses a ts literal in any of timeLayouts.
+func parseTime(s string) (time.Time, error) {
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%w: invalid time %q", ErrInvalidQuery, s)
+}
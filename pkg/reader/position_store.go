@@ -92,6 +92,42 @@ func (s *boltPositionStore) SetPosition(path string, offset int64) error {
 	})
 }
 
+// Positions implements PositionStore.Positions.
+func (s *boltPositionStore) Positions() (map[string]int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	positions := make(map[string]int64)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketPositions).ForEach(func(k, v []byte) error {
+			var offset int64
+			if err := json.Unmarshal(v, &offset); err != nil {
+				return fmt.Errorf("failed to unmarshal offset for %s: %w", k, err)
+			}
+			positions[string(k)] = offset
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return positions, nil
+}
+
+// DeletePosition implements PositionStore.DeletePosition.
+func (s *boltPositionStore) DeletePosition(path string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		if err := tx.Bucket(bucketPositions).Delete([]byte(path)); err != nil {
+			return fmt.Errorf("failed to delete position: %w", err)
+		}
+		return nil
+	})
+}
+
 // memoryPositionStore implements PositionStore using in-memory map.
 // Useful for testing.
 type memoryPositionStore struct {
@@ -130,3 +166,24 @@ func (s *memoryPositionStore) SetPosition(path string, offset int64) error {
 	s.positions[path] = offset
 	return nil
 }
+
+// Positions implements PositionStore.Positions.
+func (s *memoryPositionStore) Positions() (map[string]int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	positions := make(map[string]int64, len(s.positions))
+	for path, offset := range s.positions {
+		positions[path] = offset
+	}
+	return positions, nil
+}
+
+// DeletePosition implements PositionStore.DeletePosition.
+func (s *memoryPositionStore) DeletePosition(path string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.positions, path)
+	return nil
+}
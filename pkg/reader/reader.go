@@ -1,6 +1,7 @@
 package reader
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"os"
@@ -8,9 +9,13 @@ import (
 	"time"
 
 	"github.com/0xmhha/token-monitor/pkg/logger"
+	"github.com/0xmhha/token-monitor/pkg/overhead"
 	"github.com/0xmhha/token-monitor/pkg/parser"
 )
 
+// lineProbeSize bounds how far lineFollows looks for the end of a line.
+const lineProbeSize = 64 * 1024
+
 // reader implements the Reader interface.
 type reader struct {
 	store  PositionStore
@@ -53,6 +58,9 @@ func New(cfg Config, log logger.Logger) (Reader, error) {
 	if cfg.MaxFileSize == 0 {
 		cfg.MaxFileSize = 100 * 1024 * 1024 // 100MB
 	}
+	if cfg.MaxClockSkew == 0 {
+		cfg.MaxClockSkew = 5 * time.Minute
+	}
 
 	log.Info("incremental reader created",
 		"max_retries", cfg.MaxRetries,
@@ -224,7 +232,11 @@ func (r *reader) readFile(ctx context.Context, path string, offset int64) ([]par
 
 	// Check file size.
 	fileSize := info.Size()
-	if fileSize > r.config.MaxFileSize {
+	if r.config.MaxFileSize > 0 && fileSize > r.config.MaxFileSize {
+		r.logger.Warn("file exceeds maximum size, skipping (use -force or raise performance.max_file_size_mb)",
+			"path", path,
+			"size", fileSize,
+			"max_size", r.config.MaxFileSize)
 		return nil, 0, ErrFileTooLarge
 	}
 
@@ -247,10 +259,92 @@ func (r *reader) readFile(ctx context.Context, path string, offset int64) ([]par
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to parse file: %w", err)
 	}
+	overhead.AddBytesRead(newOffset - offset)
+
+	// The parser stops early when it reaches its entry limit, and before
+	// a last line that is still being written.
+	if newOffset < fileSize && lineFollows(path, newOffset) {
+		r.logger.Warn("entry limit reached, file read partially (use -force or raise performance.max_entries_per_read)",
+			"path", path,
+			"entries", len(entries),
+			"offset", newOffset,
+			"file_size", fileSize)
+	}
 
+	r.clampFuture(path, entries, info.ModTime())
 	return entries, newOffset, nil
 }
 
+// ReadTail implements Reader.ReadTail.
+func (r *reader) ReadTail(ctx context.Context, path string, n int) ([]parser.UsageEntry, error) {
+	r.mu.RLock()
+	if r.closed {
+		r.mu.RUnlock()
+		return nil, ErrReaderClosed
+	}
+	r.mu.RUnlock()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrFileNotFound
+		}
+		if os.IsPermission(err) {
+			return nil, ErrPermissionDenied
+		}
+		return nil, fmt.Errorf("failed to stat file: %w", err)
+	}
+
+	entries, err := parser.ParseTail(r.parser, path, n)
+	if err != nil {
+		return nil, err
+	}
+	r.clampFuture(path, entries, info.ModTime())
+	return entries, nil
+}
+
+// clampFuture clamps the timestamps of entries dated after the file was
+// last written. An entry cannot be newer than the file that holds it, so
+// the modification time bounds timestamps even on a later read.
+func (r *reader) clampFuture(path string, entries []parser.UsageEntry, modTime time.Time) {
+	if r.config.MaxClockSkew < 0 {
+		return
+	}
+	limit := modTime
+	if now := time.Now(); now.Before(limit) {
+		limit = now
+	}
+	if n := parser.ClampFuture(entries, limit, r.config.MaxClockSkew); n > 0 {
+		r.logger.Warn("future-dated entries clamped (clock skew?)",
+			"path", path,
+			"entries", n,
+			"limit", limit)
+	}
+}
+
+// lineFollows reports whether a complete line starts at offset, rather
+// than only an unterminated last line. Only the first lineProbeSize bytes
+// are checked.
+func lineFollows(path string, offset int64) bool {
+	f, err := os.Open(path) //nolint:gosec // path is validated by caller
+	if err != nil {
+		return false
+	}
+	defer func() {
+		_ = f.Close() //nolint:errcheck // read-only
+	}()
+
+	buf := make([]byte, lineProbeSize)
+	n, _ := f.ReadAt(buf, offset) //nolint:errcheck // a short read is expected at EOF
+	return bytes.IndexByte(buf[:n], '\n') >= 0 || n == lineProbeSize
+}
+
 // isRetryable checks if an error is retryable.
 func (r *reader) isRetryable(err error) bool {
 	switch err {
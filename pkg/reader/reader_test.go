@@ -136,6 +136,63 @@ func TestRead(t *testing.T) {
 	}
 }
 
+func TestReadClampsFutureEntries(t *testing.T) {
+	testFile := filepath.Join(t.TempDir(), "test.jsonl")
+	content := `{"timestamp":"2024-01-01T00:00:00Z","sessionId":"a1b2c3d4-e5f6-7890-abcd-ef1234567890","message":{"model":"claude-3-5-sonnet-20241022","usage":{"input_tokens":100,"output_tokens":50}}}
+{"timestamp":"2999-01-01T00:00:00Z","sessionId":"a1b2c3d4-e5f6-7890-abcd-ef1234567890","message":{"model":"claude-3-5-sonnet-20241022","usage":{"input_tokens":100,"output_tokens":50}}}
+`
+	if err := os.WriteFile(testFile, []byte(content), 0600); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	info, err := os.Stat(testFile)
+	if err != nil {
+		t.Fatalf("Stat() error = %v", err)
+	}
+
+	r, err := New(Config{PositionStore: NewMemoryPositionStore(), Parser: parser.New()}, logger.Noop())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer func() { _ = r.Close() }()
+
+	entries, _, err := r.ReadFrom(context.Background(), testFile, 0)
+	if err != nil || len(entries) != 2 {
+		t.Fatalf("ReadFrom() = %d entries, %v; want 2", len(entries), err)
+	}
+	if !entries[0].ClampedFrom.IsZero() {
+		t.Errorf("past entry was clamped: %+v", entries[0])
+	}
+	if !entries[1].Timestamp.Equal(info.ModTime()) || entries[1].ClampedFrom.Year() != 2999 {
+		t.Errorf("future entry = %v from %v, want the file mtime %v", entries[1].Timestamp, entries[1].ClampedFrom, info.ModTime())
+	}
+}
+
+func TestReadTail(t *testing.T) {
+	testFile := filepath.Join(t.TempDir(), "test.jsonl")
+	content := `{"timestamp":"2024-01-01T00:00:00Z","sessionId":"a1b2c3d4-e5f6-7890-abcd-ef1234567890","message":{"model":"claude-3-5-sonnet-20241022","usage":{"input_tokens":1}}}
+{"timestamp":"2024-01-01T00:01:00Z","sessionId":"a1b2c3d4-e5f6-7890-abcd-ef1234567890","message":{"model":"claude-3-5-sonnet-20241022","usage":{"input_tokens":2}}}
+{"timestamp":"2024-01-01T00:02:00Z","sessionId":"a1b2c3d4-e5f6-7890-abcd-ef1234567890","message":{"model":"claude-3-5-sonnet-20241022","usage":{"input_tokens":3}}}
+`
+	if err := os.WriteFile(testFile, []byte(content), 0600); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	// The size limit does not apply to tail reads.
+	r, err := New(Config{PositionStore: NewMemoryPositionStore(), Parser: parser.New(), MaxFileSize: 10}, logger.Noop())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer func() { _ = r.Close() }()
+
+	if _, err := r.Read(context.Background(), testFile); !errors.Is(err, ErrFileTooLarge) {
+		t.Errorf("Read() error = %v, want ErrFileTooLarge", err)
+	}
+	entries, err := r.ReadTail(context.Background(), testFile, 2)
+	if err != nil || len(entries) != 2 || entries[0].Message.Usage.InputTokens != 2 || entries[1].Message.Usage.InputTokens != 3 {
+		t.Errorf("ReadTail() = %+v, %v; want the last two entries", entries, err)
+	}
+}
+
 func TestReadFrom(t *testing.T) {
 	tmpDir := t.TempDir()
 	testFile := filepath.Join(tmpDir, "test.jsonl")
@@ -468,6 +525,36 @@ func TestMemoryPositionStore(t *testing.T) {
 	}
 }
 
+func TestPositionsAndDelete(t *testing.T) {
+	store := NewMemoryPositionStore()
+
+	for path, offset := range map[string]int64{"/a": 10, "/b": 20} {
+		if err := store.SetPosition(path, offset); err != nil {
+			t.Fatalf("SetPosition() error = %v", err)
+		}
+	}
+
+	positions, err := store.Positions()
+	if err != nil {
+		t.Fatalf("Positions() error = %v", err)
+	}
+	if len(positions) != 2 || positions["/b"] != 20 {
+		t.Errorf("Positions() = %v", positions)
+	}
+
+	if err := store.DeletePosition("/a"); err != nil {
+		t.Fatalf("DeletePosition() error = %v", err)
+	}
+	if err := store.DeletePosition("/missing"); err != nil {
+		t.Errorf("DeletePosition(missing) error = %v", err)
+	}
+
+	positions, _ = store.Positions()
+	if _, ok := positions["/a"]; ok || len(positions) != 1 {
+		t.Errorf("Positions() after delete = %v", positions)
+	}
+}
+
 func TestReadEmptyFile(t *testing.T) {
 	tmpDir := t.TempDir()
 	testFile := filepath.Join(tmpDir, "empty.jsonl")
@@ -53,6 +53,13 @@ type PositionStore interface {
 	//
 	// Returns error if storage fails.
 	SetPosition(path string, offset int64) error
+
+	// Positions returns all stored positions keyed by file path.
+	Positions() (map[string]int64, error)
+
+	// DeletePosition removes the stored position for a file.
+	// Deleting a missing position is not an error.
+	DeletePosition(path string) error
 }
 
 // Reader provides incremental file reading.
@@ -85,6 +92,18 @@ type Reader interface {
 	// Does not update the stored position.
 	ReadFrom(ctx context.Context, path string, offset int64) ([]parser.UsageEntry, int64, error)
 
+	// ReadTail reads the last n entries of a file, oldest first, for
+	// quick approximate statistics over very large files.
+	//
+	// Parameters:
+	//   - ctx: Context for cancellation
+	//   - path: Absolute path to JSONL file
+	//   - n: Maximum number of entries
+	//
+	// Does not update the stored position. MaxFileSize does not apply,
+	// since only the end of the file is read.
+	ReadTail(ctx context.Context, path string, n int) ([]parser.UsageEntry, error)
+
 	// Reset resets the read position for a file to the beginning.
 	//
 	// Parameters:
@@ -121,6 +140,12 @@ type Config struct {
 	FileOpenTimeout time.Duration
 
 	// MaxFileSize is the maximum file size to read (safety limit).
-	// Default: 100MB.
+	// Default: 100MB. Negative disables the limit.
 	MaxFileSize int64
+
+	// MaxClockSkew is how far an entry may be dated after the file's
+	// modification time (or the read time, if earlier) before its
+	// timestamp is clamped to it. See parser.ClampFuture.
+	// Default: 5m. Negative disables clamping.
+	MaxClockSkew time.Duration
 }
@@ -0,0 +1,150 @@
+// Package retention enforces how long token-monitor keeps each class of
+// data in its database.
+//
+// Raw usage entries are not stored: they stay in Claude's session files,
+// which Claude deletes on its own schedule (cleanupPeriodDays). What the
+// database keeps is derived from them or entered by the user, and each
+// class has its own retention in days, 0 keeping it forever:
+//
+//   - rollups: per-day totals read by report, calendar, and today
+//   - baselines: saved stats snapshots
+//   - focus: closed focus windows
+//
+// Example usage:
+//
+//	policy := retention.Policy{RollupDays: 730, FocusDays: 180}
+//	result, err := retention.Apply(policy, retention.Stores{
+//	    Rollups: rollups,
+//	    Focus:   windows,
+//	}, time.Now(), false)
+package retention
+
+import (
+	"time"
+
+	"github.com/0xmhha/token-monitor/pkg/baseline"
+	"github.com/0xmhha/token-monitor/pkg/focus"
+	"github.com/0xmhha/token-monitor/pkg/rollup"
+)
+
+// Policy holds the retention of each data class in days. Zero keeps the
+// class forever.
+type Policy struct {
+	// RollupDays keeps rollup rows of the last RollupDays days, today
+	// included.
+	RollupDays int `json:"rollup_days"`
+
+	// BaselineDays keeps baselines saved in the last BaselineDays days.
+	BaselineDays int `json:"baseline_days"`
+
+	// FocusDays keeps focus windows that ended in the last FocusDays
+	// days. The open window is always kept.
+	FocusDays int `json:"focus_days"`
+}
+
+// Enabled reports whether the policy removes anything at all.
+func (p Policy) Enabled() bool {
+	return p.RollupDays > 0 || p.BaselineDays > 0 || p.FocusDays > 0
+}
+
+// Stores are the stores a policy is applied to. Classes whose store is nil
+// are skipped.
+type Stores struct {
+	Rollups   rollup.Store
+	Baselines baseline.Store
+	Focus     focus.Store
+}
+
+// Result counts the records removed per class, or that would be removed
+// in a dry run.
+type Result struct {
+	Rollups   int `json:"rollups"`
+	Baselines int `json:"baselines"`
+	Focus     int `json:"focus_windows"`
+}
+
+// Total returns the number of records across all classes.
+func (r Result) Total() int {
+	return r.Rollups + r.Baselines + r.Focus
+}
+
+// Apply removes the records the policy no longer keeps as of now. With
+// dryRun, nothing is removed and the result counts what would be.
+func Apply(p Policy, s Stores, now time.Time, dryRun bool) (Result, error) {
+	var result Result
+	var err error
+
+	if p.RollupDays > 0 && s.Rollups != nil {
+		before := now.AddDate(0, 0, -(p.RollupDays - 1)).Format(rollup.DateLayout)
+		if result.Rollups, err = pruneRollups(s.Rollups, before, dryRun); err != nil {
+			return result, err
+		}
+	}
+	if p.BaselineDays > 0 && s.Baselines != nil {
+		before := now.AddDate(0, 0, -p.BaselineDays)
+		if result.Baselines, err = pruneBaselines(s.Baselines, before, dryRun); err != nil {
+			return result, err
+		}
+	}
+	if p.FocusDays > 0 && s.Focus != nil {
+		before := now.AddDate(0, 0, -p.FocusDays)
+		if result.Focus, err = pruneFocus(s.Focus, before, dryRun); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// pruneRollups removes or counts the rows dated before before.
+func pruneRollups(store rollup.Store, before string, dryRun bool) (int, error) {
+	if !dryRun {
+		return store.Prune(before)
+	}
+	rows, err := store.Rows("", before)
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, row := range rows {
+		if row.Date < before {
+			n++
+		}
+	}
+	return n, nil
+}
+
+// pruneBaselines removes or counts the baselines saved before before.
+func pruneBaselines(store baseline.Store, before time.Time, dryRun bool) (int, error) {
+	if !dryRun {
+		return store.Prune(before)
+	}
+	list, err := store.List()
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, b := range list {
+		if b.CreatedAt.Before(before) {
+			n++
+		}
+	}
+	return n, nil
+}
+
+// pruneFocus removes or counts the closed windows that ended before before.
+func pruneFocus(store focus.Store, before time.Time, dryRun bool) (int, error) {
+	if !dryRun {
+		return store.Prune(before)
+	}
+	windows, err := store.List(time.Time{}, before)
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, w := range windows {
+		if !w.Active() && w.End.Before(before) {
+			n++
+		}
+	}
+	return n, nil
+}
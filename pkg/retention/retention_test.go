@@ -0,0 +1,137 @@
+package retention
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/0xmhha/token-monitor/pkg/baseline"
+	"github.com/0xmhha/token-monitor/pkg/discovery"
+	"github.com/0xmhha/token-monitor/pkg/focus"
+	"github.com/0xmhha/token-monitor/pkg/logger"
+	"github.com/0xmhha/token-monitor/pkg/parser"
+	"github.com/0xmhha/token-monitor/pkg/reader"
+	"github.com/0xmhha/token-monitor/pkg/rollup"
+)
+
+func newTestStores(t *testing.T) Stores {
+	t.Helper()
+	db, err := bolt.Open(filepath.Join(t.TempDir(), "test.db"), 0600, nil)
+	if err != nil {
+		t.Fatalf("bolt.Open() error = %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	rollups, err := rollup.New(db)
+	if err != nil {
+		t.Fatalf("rollup.New() error = %v", err)
+	}
+	baselines, err := baseline.New(db)
+	if err != nil {
+		t.Fatalf("baseline.New() error = %v", err)
+	}
+	windows, err := focus.New(db)
+	if err != nil {
+		t.Fatalf("focus.New() error = %v", err)
+	}
+	return Stores{Rollups: rollups, Baselines: baselines, Focus: windows}
+}
+
+func TestApply(t *testing.T) {
+	stores := newTestStores(t)
+	now := time.Date(2025, 11, 30, 12, 0, 0, 0, time.UTC)
+
+	for _, b := range []baseline.Baseline{
+		{Name: "old", CreatedAt: now.AddDate(0, 0, -100)},
+		{Name: "recent", CreatedAt: now.AddDate(0, 0, -10)},
+	} {
+		if err := stores.Baselines.Save(b, false); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+	for _, start := range []time.Time{now.AddDate(0, 0, -60), now.AddDate(0, 0, -40)} {
+		if _, err := stores.Focus.Start(focus.Window{Label: "task", Start: start}); err != nil {
+			t.Fatalf("Start() error = %v", err)
+		}
+		if _, err := stores.Focus.Stop(start.Add(time.Hour)); err != nil {
+			t.Fatalf("Stop() error = %v", err)
+		}
+	}
+	// The open window is kept however long it has been open.
+	if _, err := stores.Focus.Start(focus.Window{Label: "open", Start: now.AddDate(0, 0, -20)}); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	policy := Policy{BaselineDays: 30, FocusDays: 50}
+	if !policy.Enabled() || (Policy{}).Enabled() {
+		t.Error("Enabled() should be true only with a retention set")
+	}
+
+	want := Result{Baselines: 1, Focus: 1}
+	dry, err := Apply(policy, stores, now, true)
+	if err != nil || dry != want {
+		t.Fatalf("Apply(dry run) = %+v, %v; want %+v", dry, err, want)
+	}
+	if list, _ := stores.Baselines.List(); len(list) != 2 {
+		t.Errorf("dry run removed baselines: %+v", list)
+	}
+
+	result, err := Apply(policy, stores, now, false)
+	if err != nil || result != want || result.Total() != 2 {
+		t.Fatalf("Apply() = %+v, %v; want %+v", result, err, want)
+	}
+	if list, _ := stores.Baselines.List(); len(list) != 1 || list[0].Name != "recent" {
+		t.Errorf("baselines after Apply() = %+v, want recent only", list)
+	}
+	windows, err := stores.Focus.List(time.Time{}, time.Time{})
+	if err != nil || len(windows) != 2 || !windows[1].Active() {
+		t.Errorf("focus windows after Apply() = %+v, %v; want the open and the recent window", windows, err)
+	}
+
+	if again, err := Apply(policy, stores, now, false); err != nil || again.Total() != 0 {
+		t.Errorf("second Apply() = %+v, %v; want nothing removed", again, err)
+	}
+}
+
+func TestApplyRollups(t *testing.T) {
+	stores := newTestStores(t)
+	now := time.Date(2025, 11, 30, 12, 0, 0, 0, time.Local)
+
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	var lines string
+	for _, ts := range []string{"2025-11-20T12:00:00Z", "2025-11-24T12:00:00Z", "2025-11-29T12:00:00Z"} {
+		lines += `{"type":"assistant","sessionId":"s1","timestamp":"` + ts +
+			`","message":{"model":"m","usage":{"input_tokens":1,"output_tokens":1}}}` + "\n"
+	}
+	if err := os.WriteFile(path, []byte(lines), 0600); err != nil {
+		t.Fatal(err)
+	}
+	r, err := reader.New(reader.Config{
+		PositionStore: reader.NewMemoryPositionStore(),
+		Parser:        parser.New(),
+	}, logger.Noop())
+	if err != nil {
+		t.Fatalf("reader.New() error = %v", err)
+	}
+	t.Cleanup(func() { _ = r.Close() })
+	files := []discovery.SessionFile{{SessionID: "s1", FilePath: path}}
+	if _, err := stores.Rollups.Ingest(context.Background(), files, r); err != nil {
+		t.Fatalf("Ingest() error = %v", err)
+	}
+
+	// Seven days up to 2025-11-30 start on 2025-11-24.
+	policy := Policy{RollupDays: 7}
+	for _, dryRun := range []bool{true, false} {
+		if result, err := Apply(policy, stores, now, dryRun); err != nil || result.Rollups != 1 {
+			t.Errorf("Apply(dryRun=%v) = %+v, %v; want 1 rollup row", dryRun, result, err)
+		}
+	}
+	rows, err := stores.Rollups.Rows("", "")
+	if err != nil || len(rows) != 2 || rows[0].Date != "2025-11-24" {
+		t.Errorf("rows after Apply() = %+v, %v; want 2025-11-24 onward", rows, err)
+	}
+}
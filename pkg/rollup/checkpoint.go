@@ -0,0 +1,115 @@
+package rollup
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+)
+
+// checksumBytes bounds how much of a file before a checkpoint is hashed
+// to recognize that the file was rewritten since it was ingested.
+const checksumBytes = 4096
+
+// Checkpoint is the persisted ingest progress of one session file.
+type Checkpoint struct {
+	// Offset is the byte offset ingest resumes from.
+	Offset int64 `json:"offset"`
+
+	// Entries is the number of entries ingested from the file.
+	Entries int `json:"entries"`
+
+	// Checksum is the hex SHA-256 of up to checksumBytes before Offset.
+	// Empty for checkpoints written before checksums were recorded.
+	Checksum string `json:"checksum,omitempty"`
+}
+
+// Progress reports how much of a set of session files has been ingested.
+type Progress struct {
+	// Files is the number of session files.
+	Files int `json:"files"`
+
+	// FilesDone is the number of files ingested up to their current size.
+	FilesDone int `json:"files_done"`
+
+	// Bytes is the total size of the files.
+	Bytes int64 `json:"bytes"`
+
+	// BytesDone is the number of bytes ingested.
+	BytesDone int64 `json:"bytes_done"`
+
+	// Entries is the number of entries ingested from the files.
+	Entries int `json:"entries"`
+
+	// Rebuilding is set while an interrupted rebuild waits to be resumed.
+	Rebuilding bool `json:"rebuilding"`
+}
+
+// Percent returns the ingested share of the bytes, 100 when there are none.
+func (p Progress) Percent() float64 {
+	if p.Bytes == 0 {
+		return 100
+	}
+	return float64(p.BytesDone) * 100 / float64(p.Bytes)
+}
+
+// String formats the progress as "45.0% (12/30 files, 1.2 MB of 2.7 MB)".
+func (p Progress) String() string {
+	return fmt.Sprintf("%.1f%% (%d/%d files, %.1f MB of %.1f MB)",
+		p.Percent(), p.FilesDone, p.Files, megabytes(p.BytesDone), megabytes(p.Bytes))
+}
+
+// megabytes converts n bytes to MB.
+func megabytes(n int64) float64 {
+	return float64(n) / (1024 * 1024)
+}
+
+// encodeCheckpoint serializes cp for the offsets bucket.
+func encodeCheckpoint(cp Checkpoint) ([]byte, error) {
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal checkpoint: %w", err)
+	}
+	return data, nil
+}
+
+// decodeCheckpoint parses a stored checkpoint. Older stores kept only the
+// offset as a decimal number; those decode without count or checksum.
+func decodeCheckpoint(path string, data []byte) (Checkpoint, error) {
+	var cp Checkpoint
+	if len(data) > 0 && data[0] == '{' {
+		if err := json.Unmarshal(data, &cp); err != nil {
+			return cp, fmt.Errorf("invalid rollup checkpoint for %s: %w", path, err)
+		}
+		return cp, nil
+	}
+	offset, err := strconv.ParseInt(string(data), 10, 64)
+	if err != nil {
+		return cp, fmt.Errorf("invalid rollup offset for %s: %w", path, err)
+	}
+	cp.Offset = offset
+	return cp, nil
+}
+
+// checksum hashes up to checksumBytes of the file at path that precede
+// offset. Session files are append-only, so the bytes before a checkpoint
+// only change when the file is rewritten or replaced.
+func checksum(path string, offset int64) (string, error) {
+	f, err := os.Open(path) //nolint:gosec // path comes from session discovery
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = f.Close() //nolint:errcheck // read-only
+	}()
+
+	start := max(offset-checksumBytes, 0)
+	h := sha256.New()
+	if _, err := io.Copy(h, io.NewSectionReader(f, start, offset-start)); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,86 @@
+package rollup
+
+import "sort"
+
+// CoverageStatus tells where the usage data of a day is kept.
+type CoverageStatus string
+
+// Coverage statuses.
+const (
+	// CoverageBoth means the raw files still hold every rolled-up entry.
+	CoverageBoth CoverageStatus = "both"
+
+	// CoveragePartial means some of the day's raw entries are gone and
+	// only the rollups have the full totals.
+	CoveragePartial CoverageStatus = "partial"
+
+	// CoverageStoreOnly means the raw files are gone and the rollups are
+	// the only record.
+	CoverageStoreOnly CoverageStatus = "store_only"
+
+	// CoverageRawOnly means the raw entries have not been rolled up yet.
+	CoverageRawOnly CoverageStatus = "raw_only"
+)
+
+// CoverageRange is a run of active days with the same coverage status.
+// Idle days between them do not split a range.
+type CoverageRange struct {
+	From   string         `json:"from"`
+	To     string         `json:"to"`
+	Status CoverageStatus `json:"status"`
+
+	// Days is the number of days with usage in the range.
+	Days int `json:"days"`
+
+	// Totals sums the most complete record of each day: the rollups,
+	// or the raw entries for days not rolled up yet.
+	Totals Totals `json:"totals"`
+}
+
+// Coverage compares daily totals from the rollups (stored) with daily
+// totals recomputed from the raw session files (raw), both keyed by date
+// (YYYY-MM-DD), and returns the ranges of days by coverage status in date
+// order.
+func Coverage(stored, raw map[string]Totals) []CoverageRange {
+	dates := make([]string, 0, len(stored)+len(raw))
+	for date := range stored {
+		dates = append(dates, date)
+	}
+	for date := range raw {
+		if _, ok := stored[date]; !ok {
+			dates = append(dates, date)
+		}
+	}
+	sort.Strings(dates)
+
+	var ranges []CoverageRange
+	for _, date := range dates {
+		s, r := stored[date], raw[date]
+		if s.Entries == 0 && r.Entries == 0 {
+			continue
+		}
+
+		var status CoverageStatus
+		totals := s
+		switch {
+		case s.Entries == 0:
+			status = CoverageRawOnly
+			totals = r
+		case r.Entries == 0:
+			status = CoverageStoreOnly
+		case r.Entries < s.Entries:
+			status = CoveragePartial
+		default:
+			status = CoverageBoth
+		}
+
+		if n := len(ranges); n > 0 && ranges[n-1].Status == status {
+			ranges[n-1].To = date
+			ranges[n-1].Days++
+			ranges[n-1].Totals.Merge(totals)
+			continue
+		}
+		ranges = append(ranges, CoverageRange{From: date, To: date, Status: status, Days: 1, Totals: totals})
+	}
+	return ranges
+}
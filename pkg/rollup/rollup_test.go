@@ -0,0 +1,639 @@
+package rollup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/0xmhha/token-monitor/pkg/discovery"
+	"github.com/0xmhha/token-monitor/pkg/logger"
+	"github.com/0xmhha/token-monitor/pkg/parser"
+	"github.com/0xmhha/token-monitor/pkg/reader"
+)
+
+const testSessionID = "11111111-2222-4333-8444-555555555555"
+
+func newTestStore(t *testing.T) Store {
+	t.Helper()
+	db, err := bolt.Open(filepath.Join(t.TempDir(), "test.db"), 0600, nil)
+	if err != nil {
+		t.Fatalf("bolt.Open() error = %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	store, err := New(db)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return store
+}
+
+func newTestReader(t *testing.T) reader.Reader {
+	t.Helper()
+	r, err := reader.New(reader.Config{
+		PositionStore: reader.NewMemoryPositionStore(),
+		Parser:        parser.New(),
+	}, logger.Noop())
+	if err != nil {
+		t.Fatalf("reader.New() error = %v", err)
+	}
+	t.Cleanup(func() { _ = r.Close() })
+	return r
+}
+
+func appendEntry(t *testing.T, path, ts, model string, in, out int) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer f.Close()
+	line := fmt.Sprintf(`{"type":"assistant","sessionId":%q,"timestamp":%q,"message":{"model":%q,"usage":{"input_tokens":%d,"output_tokens":%d}}}`+"\n",
+		testSessionID, ts, model, in, out)
+	if _, err := f.WriteString(line); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+}
+
+func TestIngestIsIncremental(t *testing.T) {
+	store := newTestStore(t)
+	r := newTestReader(t)
+	ctx := context.Background()
+
+	path := filepath.Join(t.TempDir(), testSessionID+".jsonl")
+	files := []discovery.SessionFile{{SessionID: testSessionID, FilePath: path}}
+
+	appendEntry(t, path, "2025-11-01T10:00:00Z", "claude-sonnet-4", 100, 50)
+	appendEntry(t, path, "2025-11-01T11:00:00Z", "claude-sonnet-4", 10, 5)
+
+	stats, err := store.Ingest(ctx, files, r)
+	if err != nil {
+		t.Fatalf("Ingest() error = %v", err)
+	}
+	if stats.Entries != 2 || stats.Files != 1 {
+		t.Errorf("Ingest() = %+v, want 2 entries from 1 file", stats)
+	}
+
+	// Re-ingesting without new data must not double count.
+	if stats, err = store.Ingest(ctx, files, r); err != nil || stats.Entries != 0 {
+		t.Fatalf("second Ingest() = %+v, %v; want no new entries", stats, err)
+	}
+
+	appendEntry(t, path, "2025-11-02T09:00:00Z", "claude-opus-4", 200, 100)
+	if stats, err = store.Ingest(ctx, files, r); err != nil || stats.Entries != 1 {
+		t.Fatalf("third Ingest() = %+v, %v; want 1 new entry", stats, err)
+	}
+
+	rows, err := store.Rows("", "")
+	if err != nil {
+		t.Fatalf("Rows() error = %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("got %d rows, want 2", len(rows))
+	}
+	first := rows[0]
+	if first.Date != "2025-11-01" || first.Model != "claude-sonnet-4" || first.SessionID != testSessionID {
+		t.Errorf("rows[0].Key = %+v", first.Key)
+	}
+	if first.Entries != 2 || first.TotalTokens() != 165 {
+		t.Errorf("rows[0] = %d entries / %d tokens, want 2 / 165", first.Entries, first.TotalTokens())
+	}
+}
+
+func TestIngestConcurrent(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	path := filepath.Join(t.TempDir(), testSessionID+".jsonl")
+	files := []discovery.SessionFile{{SessionID: testSessionID, FilePath: path}}
+	for i := 0; i < 20; i++ {
+		appendEntry(t, path, fmt.Sprintf("2025-11-01T10:%02d:00Z", i), "m", 10, 5)
+	}
+
+	// Ingests of the same file racing each other, as concurrent hooks do,
+	// must count each entry once. Every ingest reads the same bytes before
+	// any of them commits.
+	const ingests = 4
+	var wg, readAll sync.WaitGroup
+	readAll.Add(ingests)
+	for i := 0; i < ingests; i++ {
+		r := &barrierReader{Reader: newTestReader(t), barrier: &readAll}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := store.Ingest(ctx, files, r); err != nil {
+				t.Errorf("Ingest() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	rows, err := store.Rows("", "")
+	if err != nil {
+		t.Fatalf("Rows() error = %v", err)
+	}
+	if len(rows) != 1 || rows[0].Entries != 20 {
+		t.Errorf("rows = %+v, want one row with 20 entries", rows)
+	}
+}
+
+// barrierReader waits after its first read from the start of a file
+// until every reader sharing barrier has read.
+type barrierReader struct {
+	reader.Reader
+	barrier *sync.WaitGroup
+}
+
+func (r *barrierReader) ReadFrom(ctx context.Context, path string, offset int64) ([]parser.UsageEntry, int64, error) {
+	entries, next, err := r.Reader.ReadFrom(ctx, path, offset)
+	if offset == 0 {
+		r.barrier.Done()
+		r.barrier.Wait()
+	}
+	return entries, next, err
+}
+
+func TestCommitRejectsStaleCheckpoint(t *testing.T) {
+	bs := newTestStore(t).(*boltStore)
+	key := Key{Date: "2025-11-01", Model: "m", SessionID: testSessionID}
+
+	if err := bs.commit("a.jsonl", 0, Checkpoint{Offset: 100}, map[Key]*Totals{key: {Entries: 1}}); err != nil {
+		t.Fatalf("commit() error = %v", err)
+	}
+	err := bs.commit("a.jsonl", 0, Checkpoint{Offset: 100}, map[Key]*Totals{key: {Entries: 1}})
+	if !errors.Is(err, errCheckpointMoved) {
+		t.Errorf("commit() from a stale offset error = %v, want errCheckpointMoved", err)
+	}
+
+	rows, err := bs.Rows("", "")
+	if err != nil {
+		t.Fatalf("Rows() error = %v", err)
+	}
+	if len(rows) != 1 || rows[0].Entries != 1 {
+		t.Errorf("rows = %+v, want the first commit only", rows)
+	}
+}
+
+func TestRowsDateRange(t *testing.T) {
+	store := newTestStore(t)
+	r := newTestReader(t)
+
+	path := filepath.Join(t.TempDir(), testSessionID+".jsonl")
+	appendEntry(t, path, "2025-10-31T23:00:00Z", "m", 1, 1)
+	appendEntry(t, path, "2025-11-01T00:00:00Z", "m", 2, 2)
+	appendEntry(t, path, "2025-11-30T12:00:00Z", "m", 3, 3)
+	appendEntry(t, path, "2025-12-01T00:00:00Z", "m", 4, 4)
+
+	files := []discovery.SessionFile{{SessionID: testSessionID, FilePath: path}}
+	if _, err := store.Ingest(context.Background(), files, r); err != nil {
+		t.Fatalf("Ingest() error = %v", err)
+	}
+
+	rows, err := store.Rows("2025-11-01", "2025-11-30")
+	if err != nil {
+		t.Fatalf("Rows() error = %v", err)
+	}
+	if len(rows) != 2 || rows[0].Date != "2025-11-01" || rows[1].Date != "2025-11-30" {
+		t.Errorf("Rows() = %+v, want November only", rows)
+	}
+}
+
+func TestDateIsUTCDay(t *testing.T) {
+	kst := time.FixedZone("KST", 9*3600)
+	// 08:00 on November 3 in Seoul is still November 2 in UTC.
+	at := time.Date(2025, 11, 3, 8, 0, 0, 0, kst)
+	if got := Date(at); got != "2025-11-02" {
+		t.Errorf("Date(%s) = %s, want 2025-11-02", at, got)
+	}
+	if got, want := DayStart(at), time.Date(2025, 11, 2, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("DayStart(%s) = %s, want %s", at, got, want)
+	}
+}
+
+func TestReset(t *testing.T) {
+	store := newTestStore(t)
+	r := newTestReader(t)
+	ctx := context.Background()
+
+	path := filepath.Join(t.TempDir(), testSessionID+".jsonl")
+	appendEntry(t, path, "2025-11-01T10:00:00Z", "m", 1, 1)
+	files := []discovery.SessionFile{{SessionID: testSessionID, FilePath: path}}
+
+	if _, err := store.Ingest(ctx, files, r); err != nil {
+		t.Fatalf("Ingest() error = %v", err)
+	}
+	if err := store.Reset(); err != nil {
+		t.Fatalf("Reset() error = %v", err)
+	}
+	if rows, _ := store.Rows("", ""); len(rows) != 0 {
+		t.Errorf("Rows() after Reset = %d rows, want 0", len(rows))
+	}
+
+	// Offsets were cleared too, so the file is re-ingested in full.
+	stats, err := store.Ingest(ctx, files, r)
+	if err != nil || stats.Entries != 1 {
+		t.Errorf("Ingest() after Reset = %+v, %v; want 1 entry", stats, err)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	rows := []Row{
+		{Key{"2025-11-01", "sonnet", "a"}, Totals{Entries: 1, InputTokens: 10}},
+		{Key{"2025-11-01", "opus", "b"}, Totals{Entries: 2, InputTokens: 20}},
+		{Key{"2025-11-02", "sonnet", "a"}, Totals{Entries: 3, InputTokens: 30}},
+	}
+
+	byModel := Summarize(rows, []Dimension{DimModel})
+	if len(byModel) != 2 || byModel[0].Model != "opus" || byModel[1].InputTokens != 40 {
+		t.Errorf("Summarize(model) = %+v", byModel)
+	}
+	if byModel[1].Date != "" || byModel[1].SessionID != "" {
+		t.Errorf("ungrouped fields should be empty, got %+v", byModel[1].Key)
+	}
+
+	total := Summarize(rows, nil)
+	if len(total) != 1 || total[0].Entries != 6 {
+		t.Errorf("Summarize(nil) = %+v, want one row with 6 entries", total)
+	}
+}
+
+func TestWeekdayAverages(t *testing.T) {
+	// 2025-11-03 is a Monday; two weeks run through Sunday 2025-11-16.
+	from := time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 11, 16, 0, 0, 0, 0, time.UTC)
+	rows := []Row{
+		{Key{"2025-11-03", "sonnet", "a"}, Totals{Entries: 2, InputTokens: 1000, CostUSD: 1}},
+		{Key{"2025-11-03", "opus", "b"}, Totals{Entries: 1, OutputTokens: 500, CostUSD: 2}},
+		{Key{"2025-11-10", "sonnet", "a"}, Totals{Entries: 1, InputTokens: 500}},
+		{Key{"2025-11-15", "sonnet", "c"}, Totals{Entries: 4, CacheReadTokens: 4000}},
+		{Key{"2025-11-17", "sonnet", "a"}, Totals{Entries: 9, InputTokens: 9000}}, // out of range
+	}
+
+	avgs := WeekdayAverages(rows, from, to)
+	if len(avgs) != 7 || avgs[0].Weekday != time.Monday || avgs[6].Weekday != time.Sunday {
+		t.Fatalf("WeekdayAverages() = %+v, want Monday..Sunday", avgs)
+	}
+
+	monday := avgs[0]
+	if monday.Days != 2 || monday.ActiveDays != 2 || monday.Entries != 2 || monday.TotalTokens != 1000 || monday.CostUSD != 1.5 {
+		t.Errorf("Monday = %+v, want 2 days, 2 active, 2 entries, 1000 tokens, $1.50", monday)
+	}
+	saturday := avgs[5]
+	if saturday.Days != 2 || saturday.ActiveDays != 1 || saturday.TotalTokens != 2000 {
+		t.Errorf("Saturday = %+v, want 2 days, 1 active, 2000 tokens", saturday)
+	}
+	if tuesday := avgs[1]; tuesday.ActiveDays != 0 || tuesday.Entries != 0 || tuesday.Name != "Tuesday" {
+		t.Errorf("Tuesday = %+v, want idle", tuesday)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	store := newTestStore(t)
+	r := newTestReader(t)
+	ctx := context.Background()
+
+	path := filepath.Join(t.TempDir(), testSessionID+".jsonl")
+	appendEntry(t, path, "2025-11-01T10:00:00Z", "m", 10, 5)
+	files := []discovery.SessionFile{{SessionID: testSessionID, FilePath: path}}
+
+	if _, err := store.Ingest(ctx, files, r); err != nil {
+		t.Fatalf("Ingest() error = %v", err)
+	}
+
+	result, err := store.Verify(ctx, files, r)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if len(result.Mismatches) != 0 || result.Pending != 0 {
+		t.Errorf("Verify() after ingest = %+v, want clean", result)
+	}
+
+	// Appended but not yet ingested data is pending, not a mismatch.
+	appendEntry(t, path, "2025-11-01T11:00:00Z", "m", 1, 1)
+	if result, err = store.Verify(ctx, files, r); err != nil || result.Pending != 1 || len(result.Mismatches) != 0 {
+		t.Errorf("Verify() with pending data = %+v, %v; want 1 pending file", result, err)
+	}
+
+	// A stored row of a session with raw files but no matching entries is
+	// reported; rows of sessions whose files are gone are archived.
+	if _, err := store.Ingest(ctx, files, r); err != nil {
+		t.Fatalf("Ingest() error = %v", err)
+	}
+	bs := store.(*boltStore)
+	orphan := Key{Date: "2025-10-01", Model: "m", SessionID: testSessionID}
+	gone := Key{Date: "2025-10-01", Model: "m", SessionID: "gone"}
+	if err := bs.commit("elsewhere.jsonl", 0, Checkpoint{}, map[Key]*Totals{orphan: {Entries: 1}, gone: {Entries: 1}}); err != nil {
+		t.Fatalf("commit() error = %v", err)
+	}
+	result, err = store.Verify(ctx, files, r)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if len(result.Mismatches) != 1 || result.Mismatches[0].Key != orphan || result.Mismatches[0].Expected.Entries != 0 {
+		t.Errorf("Verify() mismatches = %+v, want the orphan row", result.Mismatches)
+	}
+	if result.Archived != 1 {
+		t.Errorf("Verify() archived = %d, want 1", result.Archived)
+	}
+}
+
+func TestRebuildKeepsArchivedSessions(t *testing.T) {
+	store := newTestStore(t)
+	r := newTestReader(t)
+	ctx := context.Background()
+
+	path := filepath.Join(t.TempDir(), testSessionID+".jsonl")
+	appendEntry(t, path, "2025-11-01T10:00:00Z", "m", 10, 5)
+	files := []discovery.SessionFile{{SessionID: testSessionID, FilePath: path}}
+	if _, err := store.Ingest(ctx, files, r); err != nil {
+		t.Fatalf("Ingest() error = %v", err)
+	}
+
+	// A corrupted row of a live session and a row whose files were deleted.
+	bs := store.(*boltStore)
+	live := Key{Date: "2025-11-01", Model: "m", SessionID: testSessionID}
+	gone := Key{Date: "2025-09-01", Model: "m", SessionID: "gone"}
+	if err := bs.commit("elsewhere.jsonl", 0, Checkpoint{}, map[Key]*Totals{live: {Entries: 5}, gone: {Entries: 3}}); err != nil {
+		t.Fatalf("commit() error = %v", err)
+	}
+
+	stats, err := store.Rebuild(ctx, files, r)
+	if err != nil {
+		t.Fatalf("Rebuild() error = %v", err)
+	}
+	if stats.Entries != 1 {
+		t.Errorf("Rebuild() = %+v, want 1 entry", stats)
+	}
+
+	rows, err := store.Rows("", "")
+	if err != nil {
+		t.Fatalf("Rows() error = %v", err)
+	}
+	if len(rows) != 2 || rows[0].Key != gone || rows[0].Entries != 3 || rows[1].Key != live || rows[1].Entries != 1 {
+		t.Errorf("Rows() after rebuild = %+v, want the archived row and the recomputed live row", rows)
+	}
+
+	offsets, err := store.Offsets()
+	if err != nil {
+		t.Fatalf("Offsets() error = %v", err)
+	}
+	if _, ok := offsets["elsewhere.jsonl"]; ok || len(offsets) != 1 {
+		t.Errorf("Offsets() after rebuild = %v, want only the live file", offsets)
+	}
+
+	// An unreadable file aborts before anything is removed.
+	missing := append(files, discovery.SessionFile{SessionID: "x", FilePath: filepath.Join(t.TempDir(), "missing.jsonl")})
+	if _, err := store.Rebuild(ctx, missing, r); err == nil {
+		t.Error("Rebuild() with unreadable file expected error")
+	}
+	if rows, _ := store.Rows("", ""); len(rows) != 2 {
+		t.Errorf("Rows() after failed rebuild = %+v, want unchanged", rows)
+	}
+}
+
+func TestCoverage(t *testing.T) {
+	stored := map[string]Totals{
+		"2025-09-01": {Entries: 3},
+		"2025-09-03": {Entries: 2},
+		"2025-09-10": {Entries: 4},
+		"2025-09-11": {Entries: 2},
+		"2025-09-12": {Entries: 1},
+	}
+	raw := map[string]Totals{
+		"2025-09-10": {Entries: 1},
+		"2025-09-11": {Entries: 2},
+		"2025-09-12": {Entries: 2},
+		"2025-09-13": {Entries: 6},
+	}
+
+	got := Coverage(stored, raw)
+	want := []CoverageRange{
+		{From: "2025-09-01", To: "2025-09-03", Status: CoverageStoreOnly, Days: 2, Totals: Totals{Entries: 5}},
+		{From: "2025-09-10", To: "2025-09-10", Status: CoveragePartial, Days: 1, Totals: Totals{Entries: 4}},
+		{From: "2025-09-11", To: "2025-09-12", Status: CoverageBoth, Days: 2, Totals: Totals{Entries: 3}},
+		{From: "2025-09-13", To: "2025-09-13", Status: CoverageRawOnly, Days: 1, Totals: Totals{Entries: 6}},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Coverage() = %+v, want %d ranges", got, len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("range %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	if got := Coverage(nil, nil); len(got) != 0 {
+		t.Errorf("Coverage(nil, nil) = %+v, want none", got)
+	}
+}
+
+func TestOffsetsAndDeleteOffset(t *testing.T) {
+	store := newTestStore(t)
+	r := newTestReader(t)
+
+	path := filepath.Join(t.TempDir(), testSessionID+".jsonl")
+	appendEntry(t, path, "2025-11-01T10:00:00Z", "m", 1, 1)
+	files := []discovery.SessionFile{{SessionID: testSessionID, FilePath: path}}
+	if _, err := store.Ingest(context.Background(), files, r); err != nil {
+		t.Fatalf("Ingest() error = %v", err)
+	}
+
+	offsets, err := store.Offsets()
+	if err != nil || len(offsets) != 1 || offsets[path] == 0 {
+		t.Fatalf("Offsets() = %v, %v; want one non-zero offset", offsets, err)
+	}
+	if err := store.DeleteOffset(path); err != nil {
+		t.Fatalf("DeleteOffset() error = %v", err)
+	}
+	if offsets, _ = store.Offsets(); len(offsets) != 0 {
+		t.Errorf("Offsets() after delete = %v, want empty", offsets)
+	}
+}
+
+// cancelingReader cancels the ingest context after a number of reads,
+// simulating an interrupted run.
+type cancelingReader struct {
+	reader.Reader
+	reads  int
+	cancel context.CancelFunc
+}
+
+func (c *cancelingReader) ReadFrom(ctx context.Context, path string, offset int64) ([]parser.UsageEntry, int64, error) {
+	entries, end, err := c.Reader.ReadFrom(ctx, path, offset)
+	c.reads--
+	if c.reads == 0 {
+		c.cancel()
+	}
+	return entries, end, err
+}
+
+func newChunkedReader(t *testing.T, maxEntries int) reader.Reader {
+	t.Helper()
+	r, err := reader.New(reader.Config{
+		PositionStore: reader.NewMemoryPositionStore(),
+		Parser:        parser.NewWithConfig(parser.Config{MaxEntries: maxEntries}),
+	}, logger.Noop())
+	if err != nil {
+		t.Fatalf("reader.New() error = %v", err)
+	}
+	t.Cleanup(func() { _ = r.Close() })
+	return r
+}
+
+func TestIngestResumesFromCheckpoint(t *testing.T) {
+	store := newTestStore(t)
+	r := newChunkedReader(t, 2)
+
+	path := filepath.Join(t.TempDir(), testSessionID+".jsonl")
+	for i := range 5 {
+		appendEntry(t, path, fmt.Sprintf("2025-11-01T1%d:00:00Z", i), "m", 10, 5)
+	}
+	files := []discovery.SessionFile{{SessionID: testSessionID, FilePath: path}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if _, err := store.Ingest(ctx, files, &cancelingReader{Reader: r, reads: 1, cancel: cancel}); err == nil {
+		t.Fatal("interrupted Ingest() error = nil, want context error")
+	}
+
+	p, err := store.Progress(files)
+	if err != nil {
+		t.Fatalf("Progress() error = %v", err)
+	}
+	if p.Files != 1 || p.FilesDone != 0 || p.Entries != 2 || p.BytesDone == 0 || p.BytesDone >= p.Bytes {
+		t.Errorf("Progress() after interruption = %+v, want 2 entries of 1 unfinished file", p)
+	}
+
+	stats, err := store.Ingest(context.Background(), files, r)
+	if err != nil || stats.Entries != 3 {
+		t.Fatalf("resumed Ingest() = %+v, %v; want the remaining 3 entries", stats, err)
+	}
+	if p, _ = store.Progress(files); p.FilesDone != 1 || p.Entries != 5 || p.Percent() != 100 {
+		t.Errorf("Progress() after resume = %+v, want done", p)
+	}
+	rows, _ := store.Rows("", "")
+	if len(rows) != 1 || rows[0].Entries != 5 || rows[0].TotalTokens() != 75 {
+		t.Errorf("Rows() = %+v, want 5 entries / 75 tokens", rows)
+	}
+}
+
+func TestIngestSkipsChangedFile(t *testing.T) {
+	store := newTestStore(t)
+	r := newTestReader(t)
+	ctx := context.Background()
+
+	path := filepath.Join(t.TempDir(), testSessionID+".jsonl")
+	appendEntry(t, path, "2025-11-01T10:00:00Z", "m", 10, 5)
+	files := []discovery.SessionFile{{SessionID: testSessionID, FilePath: path}}
+	if _, err := store.Ingest(ctx, files, r); err != nil {
+		t.Fatalf("Ingest() error = %v", err)
+	}
+
+	// Rewrite the file with different content; re-ingesting from the old
+	// offset would mix the two versions.
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	appendEntry(t, path, "2025-11-02T10:00:00Z", "m", 20, 5)
+	appendEntry(t, path, "2025-11-02T11:00:00Z", "m", 20, 5)
+
+	stats, err := store.Ingest(ctx, files, r)
+	if err != nil || stats.Changed != 1 || stats.Entries != 0 {
+		t.Fatalf("Ingest() of rewritten file = %+v, %v; want 1 changed file", stats, err)
+	}
+	if stats, err = store.Rebuild(ctx, files, r); err != nil || stats.Entries != 2 {
+		t.Fatalf("Rebuild() = %+v, %v; want 2 entries", stats, err)
+	}
+}
+
+func TestRebuildResumes(t *testing.T) {
+	store := newTestStore(t)
+	r := newTestReader(t)
+
+	dir := t.TempDir()
+	var files []discovery.SessionFile
+	for i := range 3 {
+		id := fmt.Sprintf("%08d-2222-4333-8444-555555555555", i)
+		path := filepath.Join(dir, id+".jsonl")
+		line := fmt.Sprintf(`{"type":"assistant","sessionId":%q,"timestamp":"2025-11-01T10:00:00Z","message":{"model":"m","usage":{"input_tokens":10,"output_tokens":5}}}`+"\n", id)
+		if err := os.WriteFile(path, []byte(line), 0600); err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, discovery.SessionFile{SessionID: id, FilePath: path})
+	}
+	if _, err := store.Ingest(context.Background(), files, r); err != nil {
+		t.Fatalf("Ingest() error = %v", err)
+	}
+
+	// Interrupt after the scan of all three files and the first re-ingest.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if _, err := store.Rebuild(ctx, files, &cancelingReader{Reader: r, reads: 4, cancel: cancel}); err == nil {
+		t.Fatal("interrupted Rebuild() error = nil, want context error")
+	}
+	p, err := store.Progress(files)
+	if err != nil || !p.Rebuilding || p.FilesDone != 1 {
+		t.Fatalf("Progress() after interruption = %+v, %v; want rebuilding with 1 file done", p, err)
+	}
+
+	stats, err := store.Rebuild(context.Background(), files, r)
+	if err != nil || stats.Entries != 2 {
+		t.Fatalf("resumed Rebuild() = %+v, %v; want the remaining 2 entries", stats, err)
+	}
+	if p, _ = store.Progress(files); p.Rebuilding || p.FilesDone != 3 {
+		t.Errorf("Progress() after resume = %+v, want done", p)
+	}
+	if rows, _ := store.Rows("", ""); len(rows) != 3 {
+		t.Errorf("Rows() = %+v, want one row per session", rows)
+	}
+}
+
+func TestPrune(t *testing.T) {
+	store := newTestStore(t)
+	r := newTestReader(t)
+	ctx := context.Background()
+
+	path := filepath.Join(t.TempDir(), testSessionID+".jsonl")
+	appendEntry(t, path, "2025-11-01T10:00:00Z", "m", 1, 1)
+	appendEntry(t, path, "2025-11-02T10:00:00Z", "m", 2, 2)
+	files := []discovery.SessionFile{{SessionID: testSessionID, FilePath: path}}
+	if _, err := store.Ingest(ctx, files, r); err != nil {
+		t.Fatalf("Ingest() error = %v", err)
+	}
+
+	if removed, err := store.Prune("2025-11-02"); err != nil || removed != 1 {
+		t.Fatalf("Prune() = %d, %v; want 1 row removed", removed, err)
+	}
+	// An earlier date does not bring pruned days back.
+	if removed, err := store.Prune("2025-10-01"); err != nil || removed != 0 {
+		t.Fatalf("Prune(earlier) = %d, %v; want nothing removed", removed, err)
+	}
+
+	// Pruned days are neither reported by fsck nor restored by a rebuild,
+	// while the session file still has their entries.
+	if result, err := store.Verify(ctx, files, r); err != nil || len(result.Mismatches) != 0 {
+		t.Errorf("Verify() after prune = %+v, %v; want clean", result, err)
+	}
+	if _, err := store.Rebuild(ctx, files, r); err != nil {
+		t.Fatalf("Rebuild() error = %v", err)
+	}
+	appendEntry(t, path, "2025-11-03T10:00:00Z", "m", 3, 3)
+	if _, err := store.Ingest(ctx, files, r); err != nil {
+		t.Fatalf("Ingest() error = %v", err)
+	}
+
+	rows, err := store.Rows("", "")
+	if err != nil {
+		t.Fatalf("Rows() error = %v", err)
+	}
+	if len(rows) != 2 || rows[0].Date != "2025-11-02" || rows[1].Date != "2025-11-03" {
+		t.Errorf("Rows() = %+v, want 2025-11-02 and 2025-11-03 only", rows)
+	}
+}
@@ -0,0 +1,618 @@
+package rollup
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/0xmhha/token-monitor/pkg/discovery"
+	"github.com/0xmhha/token-monitor/pkg/parser"
+	"github.com/0xmhha/token-monitor/pkg/reader"
+)
+
+// Bucket names.
+var (
+	bucketRollups = []byte("rollups")        // date\x00model\x00session -> Totals
+	bucketOffsets = []byte("rollup_offsets") // Path -> Checkpoint
+	bucketMeta    = []byte("rollup_meta")    // metaRebuild -> "1" while a rebuild is interrupted
+)
+
+// metaRebuild marks a rebuild that removed the old rollups but has not
+// finished re-ingesting them.
+var metaRebuild = []byte("rebuild")
+
+// metaPrunedBefore holds the date before which rollups were pruned; older
+// entries are no longer ingested or verified.
+var metaPrunedBefore = []byte("pruned_before")
+
+// errCheckpointMoved is returned by commit when another ingest advanced
+// the file's checkpoint since it was read.
+var errCheckpointMoved = errors.New("rollup checkpoint moved")
+
+// keySep separates key fields; it cannot appear in dates, models, or IDs.
+const keySep = "\x00"
+
+// boltStore implements Store using BoltDB.
+type boltStore struct {
+	db *bolt.DB
+}
+
+// New creates a BoltDB-backed rollup store.
+//
+// Parameters:
+//   - db: BoltDB database instance (typically session.Manager.DB())
+//
+// Returns:
+//   - Configured Store
+//   - Error if bucket initialization fails
+func New(db *bolt.DB) (Store, error) {
+	if err := db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{bucketRollups, bucketOffsets, bucketMeta} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("failed to create rollup buckets: %w", err)
+	}
+
+	return &boltStore{db: db}, nil
+}
+
+// Ingest implements Store.Ingest.
+func (s *boltStore) Ingest(ctx context.Context, files []discovery.SessionFile, r reader.Reader) (IngestStats, error) {
+	var stats IngestStats
+
+	pruned, err := s.PrunedBefore()
+	if err != nil {
+		return stats, err
+	}
+
+	for _, file := range files {
+		if err := ctx.Err(); err != nil {
+			return stats, err
+		}
+
+		cp, err := s.checkpoint(file.FilePath)
+		if err != nil {
+			return stats, err
+		}
+
+		info, err := os.Stat(file.FilePath)
+		if err != nil {
+			// One unreadable file must not block ingest of the rest.
+			stats.Skipped++
+			continue
+		}
+		if info.Size() == cp.Offset {
+			continue
+		}
+		if info.Size() < cp.Offset || !s.unchanged(file.FilePath, cp) {
+			// Ingesting a rewritten file would count its entries twice.
+			stats.Changed++
+			continue
+		}
+
+		read, err := s.ingestFile(ctx, file, cp, r, pruned)
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return stats, ctxErr
+			}
+			stats.Skipped++
+		}
+		if read > 0 {
+			stats.Files++
+			stats.Entries += read
+		}
+	}
+
+	return stats, nil
+}
+
+// ingestFile reads file from cp until no new entries are returned,
+// committing a checkpoint after every read, so an interrupted ingest of a
+// large file resumes from the last read rather than the start. Entries
+// dated before pruned are read past without being added. Returns the
+// number of entries read.
+func (s *boltStore) ingestFile(
+	ctx context.Context, file discovery.SessionFile, cp Checkpoint, r reader.Reader, pruned string,
+) (int, error) {
+	read := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return read, err
+		}
+
+		entries, newOffset, err := r.ReadFrom(ctx, file.FilePath, cp.Offset)
+		if err != nil {
+			return read, err
+		}
+		if newOffset == cp.Offset {
+			return read, nil
+		}
+
+		delta := make(map[Key]*Totals)
+		for _, entry := range entries {
+			key := entryKey(entry, file.SessionID)
+			if key.Date < pruned {
+				continue
+			}
+			totals, ok := delta[key]
+			if !ok {
+				totals = &Totals{}
+				delta[key] = totals
+			}
+			totals.AddEntry(entry)
+		}
+
+		sum, err := checksum(file.FilePath, newOffset)
+		if err != nil {
+			return read, err
+		}
+		next := Checkpoint{Offset: newOffset, Entries: cp.Entries + len(entries), Checksum: sum}
+		if err := s.commit(file.FilePath, cp.Offset, next, delta); err != nil {
+			if errors.Is(err, errCheckpointMoved) {
+				// A concurrent ingest of the same file committed these
+				// entries first.
+				return read, nil
+			}
+			return read, err
+		}
+		cp = next
+		read += len(entries)
+	}
+}
+
+// unchanged reports whether the bytes before cp still match its checksum.
+func (s *boltStore) unchanged(path string, cp Checkpoint) bool {
+	if cp.Offset == 0 || cp.Checksum == "" {
+		return true
+	}
+	sum, err := checksum(path, cp.Offset)
+	return err == nil && sum == cp.Checksum
+}
+
+// checkpoint returns the stored checkpoint for path, zero if none.
+func (s *boltStore) checkpoint(path string) (Checkpoint, error) {
+	var cp Checkpoint
+	err := s.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(bucketOffsets).Get([]byte(path))
+		if data == nil {
+			return nil
+		}
+		var decodeErr error
+		cp, decodeErr = decodeCheckpoint(path, data)
+		return decodeErr
+	})
+	return cp, err
+}
+
+// checkpoints returns the stored checkpoints keyed by path.
+func (s *boltStore) checkpoints() (map[string]Checkpoint, error) {
+	cps := make(map[string]Checkpoint)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketOffsets).ForEach(func(k, v []byte) error {
+			cp, err := decodeCheckpoint(string(k), v)
+			if err != nil {
+				return err
+			}
+			cps[string(k)] = cp
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cps, nil
+}
+
+// commit merges delta into the rollups and records the new checkpoint
+// in a single transaction. It is a compare-and-set on the checkpoint: if
+// the stored offset of path is no longer from, the entries were already
+// committed by a concurrent ingest and errCheckpointMoved is returned
+// without changing anything.
+func (s *boltStore) commit(path string, from int64, cp Checkpoint, delta map[Key]*Totals) error {
+	value, err := encodeCheckpoint(cp)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		var stored Checkpoint
+		if data := tx.Bucket(bucketOffsets).Get([]byte(path)); data != nil {
+			var decodeErr error
+			if stored, decodeErr = decodeCheckpoint(path, data); decodeErr != nil {
+				return decodeErr
+			}
+		}
+		if stored.Offset != from {
+			return errCheckpointMoved
+		}
+
+		rollups := tx.Bucket(bucketRollups)
+		for key, add := range delta {
+			k := encodeKey(key)
+
+			var totals Totals
+			if data := rollups.Get(k); data != nil {
+				if err := json.Unmarshal(data, &totals); err != nil {
+					return fmt.Errorf("failed to unmarshal rollup: %w", err)
+				}
+			}
+			totals.Merge(*add)
+
+			data, err := json.Marshal(totals)
+			if err != nil {
+				return fmt.Errorf("failed to marshal rollup: %w", err)
+			}
+			if err := rollups.Put(k, data); err != nil {
+				return fmt.Errorf("failed to store rollup: %w", err)
+			}
+		}
+
+		if err := tx.Bucket(bucketOffsets).Put([]byte(path), value); err != nil {
+			return fmt.Errorf("failed to store rollup offset: %w", err)
+		}
+		return nil
+	})
+}
+
+// Rows implements Store.Rows.
+func (s *boltStore) Rows(from, to string) ([]Row, error) {
+	var rows []Row
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(bucketRollups).Cursor()
+
+		var k, v []byte
+		if from != "" {
+			k, v = c.Seek([]byte(from))
+		} else {
+			k, v = c.First()
+		}
+
+		for ; k != nil; k, v = c.Next() {
+			key := decodeKey(k)
+			if to != "" && key.Date > to {
+				break
+			}
+
+			var totals Totals
+			if err := json.Unmarshal(v, &totals); err != nil {
+				return fmt.Errorf("failed to unmarshal rollup: %w", err)
+			}
+			rows = append(rows, Row{Key: key, Totals: totals})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// Reset implements Store.Reset.
+func (s *boltStore) Reset() error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{bucketRollups, bucketOffsets, bucketMeta} {
+			if err := tx.DeleteBucket(name); err != nil {
+				return fmt.Errorf("failed to delete %s bucket: %w", name, err)
+			}
+			if _, err := tx.CreateBucket(name); err != nil {
+				return fmt.Errorf("failed to create %s bucket: %w", name, err)
+			}
+		}
+		return nil
+	})
+}
+
+// Rebuild implements Store.Rebuild.
+func (s *boltStore) Rebuild(ctx context.Context, files []discovery.SessionFile, r reader.Reader) (IngestStats, error) {
+	resume, err := s.rebuilding()
+	if err != nil {
+		return IngestStats{}, err
+	}
+	if !resume {
+		if err := s.clearLive(ctx, files, r); err != nil {
+			return IngestStats{}, err
+		}
+	}
+
+	stats, err := s.Ingest(ctx, files, r)
+	if err != nil {
+		// The marker stays, so the next rebuild resumes from the checkpoints.
+		return stats, err
+	}
+	err = s.db.Update(func(tx *bolt.Tx) error {
+		if err := tx.Bucket(bucketMeta).Delete(metaRebuild); err != nil {
+			return fmt.Errorf("failed to clear rebuild marker: %w", err)
+		}
+		return nil
+	})
+	return stats, err
+}
+
+// clearLive removes the rollups of every session that still has raw
+// entries in files and all checkpoints, and marks a rebuild in progress,
+// in one transaction. Nothing is changed if a file cannot be read.
+func (s *boltStore) clearLive(ctx context.Context, files []discovery.SessionFile, r reader.Reader) error {
+	sessions := make(map[string]bool)
+	for _, file := range files {
+		entries, _, err := r.ReadFrom(ctx, file.FilePath, 0)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", file.FilePath, err)
+		}
+		sessions[file.SessionID] = true
+		for _, entry := range entries {
+			sessions[entryKey(entry, file.SessionID).SessionID] = true
+		}
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		rollups := tx.Bucket(bucketRollups)
+		var stale [][]byte
+		c := rollups.Cursor()
+		for k, _ := c.First(); k != nil; k, _ = c.Next() {
+			if sessions[decodeKey(k).SessionID] {
+				stale = append(stale, bytes.Clone(k))
+			}
+		}
+		for _, k := range stale {
+			if err := rollups.Delete(k); err != nil {
+				return fmt.Errorf("failed to delete rollup: %w", err)
+			}
+		}
+
+		if err := tx.DeleteBucket(bucketOffsets); err != nil {
+			return fmt.Errorf("failed to delete %s bucket: %w", bucketOffsets, err)
+		}
+		if _, err := tx.CreateBucket(bucketOffsets); err != nil {
+			return fmt.Errorf("failed to create %s bucket: %w", bucketOffsets, err)
+		}
+		if err := tx.Bucket(bucketMeta).Put(metaRebuild, []byte("1")); err != nil {
+			return fmt.Errorf("failed to store rebuild marker: %w", err)
+		}
+		return nil
+	})
+}
+
+// Prune implements Store.Prune.
+func (s *boltStore) Prune(before string) (int, error) {
+	removed := 0
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		meta := tx.Bucket(bucketMeta)
+		if prev := string(meta.Get(metaPrunedBefore)); prev > before {
+			before = prev
+		}
+
+		rollups := tx.Bucket(bucketRollups)
+		var stale [][]byte
+		c := rollups.Cursor()
+		// Keys start with the date, so the rows to remove come first.
+		for k, _ := c.First(); k != nil && decodeKey(k).Date < before; k, _ = c.Next() {
+			stale = append(stale, bytes.Clone(k))
+		}
+		for _, k := range stale {
+			if err := rollups.Delete(k); err != nil {
+				return fmt.Errorf("failed to delete rollup: %w", err)
+			}
+		}
+		removed = len(stale)
+
+		if err := meta.Put(metaPrunedBefore, []byte(before)); err != nil {
+			return fmt.Errorf("failed to store prune date: %w", err)
+		}
+		return nil
+	})
+	return removed, err
+}
+
+// PrunedBefore implements Store.PrunedBefore.
+func (s *boltStore) PrunedBefore() (string, error) {
+	var before string
+	err := s.db.View(func(tx *bolt.Tx) error {
+		before = string(tx.Bucket(bucketMeta).Get(metaPrunedBefore))
+		return nil
+	})
+	return before, err
+}
+
+// rebuilding reports whether an interrupted rebuild is pending.
+func (s *boltStore) rebuilding() (bool, error) {
+	var pending bool
+	err := s.db.View(func(tx *bolt.Tx) error {
+		pending = tx.Bucket(bucketMeta).Get(metaRebuild) != nil
+		return nil
+	})
+	return pending, err
+}
+
+// Offsets implements Store.Offsets.
+func (s *boltStore) Offsets() (map[string]int64, error) {
+	cps, err := s.checkpoints()
+	if err != nil {
+		return nil, err
+	}
+	offsets := make(map[string]int64, len(cps))
+	for path, cp := range cps {
+		offsets[path] = cp.Offset
+	}
+	return offsets, nil
+}
+
+// Progress implements Store.Progress.
+func (s *boltStore) Progress(files []discovery.SessionFile) (Progress, error) {
+	var p Progress
+
+	cps, err := s.checkpoints()
+	if err != nil {
+		return p, err
+	}
+	if p.Rebuilding, err = s.rebuilding(); err != nil {
+		return p, err
+	}
+
+	for _, file := range files {
+		size := file.Size
+		if info, err := os.Stat(file.FilePath); err == nil {
+			size = info.Size()
+		}
+		cp := cps[file.FilePath]
+
+		p.Files++
+		p.Bytes += size
+		p.BytesDone += min(cp.Offset, size)
+		p.Entries += cp.Entries
+		if cp.Offset >= size {
+			p.FilesDone++
+		}
+	}
+	return p, nil
+}
+
+// DeleteOffset implements Store.DeleteOffset.
+func (s *boltStore) DeleteOffset(path string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		if err := tx.Bucket(bucketOffsets).Delete([]byte(path)); err != nil {
+			return fmt.Errorf("failed to delete rollup offset: %w", err)
+		}
+		return nil
+	})
+}
+
+// Verify implements Store.Verify.
+func (s *boltStore) Verify(ctx context.Context, files []discovery.SessionFile, r reader.Reader) (VerifyResult, error) {
+	var result VerifyResult
+
+	offsets, err := s.Offsets()
+	if err != nil {
+		return result, err
+	}
+	pruned, err := s.PrunedBefore()
+	if err != nil {
+		return result, err
+	}
+
+	expected := make(map[Key]*Totals)
+	pending := make(map[string]bool)
+	raw := make(map[string]bool) // sessions with raw files
+	for _, file := range files {
+		raw[file.SessionID] = true
+		entries, end, readErr := r.ReadFrom(ctx, file.FilePath, 0)
+		if readErr != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return result, ctxErr
+			}
+			pending[file.SessionID] = true
+			result.Pending++
+			continue
+		}
+		for _, entry := range entries {
+			raw[entryKey(entry, file.SessionID).SessionID] = true
+		}
+
+		if stored, ok := offsets[file.FilePath]; !ok || stored != end {
+			pending[file.SessionID] = true
+			for _, entry := range entries {
+				pending[entry.SessionID] = true
+			}
+			result.Pending++
+			continue
+		}
+
+		for _, entry := range entries {
+			key := entryKey(entry, file.SessionID)
+			if key.Date < pruned {
+				continue
+			}
+			totals, ok := expected[key]
+			if !ok {
+				totals = &Totals{}
+				expected[key] = totals
+			}
+			totals.AddEntry(entry)
+		}
+	}
+
+	rows, err := s.Rows("", "")
+	if err != nil {
+		return result, err
+	}
+
+	seen := make(map[Key]bool, len(rows))
+	archived := make(map[string]bool)
+	for _, row := range rows {
+		seen[row.Key] = true
+		if !raw[row.SessionID] {
+			archived[row.SessionID] = true
+			continue
+		}
+		if pending[row.SessionID] {
+			continue
+		}
+		want := Totals{}
+		if totals, ok := expected[row.Key]; ok {
+			want = *totals
+		}
+		if !sameTotals(row.Totals, want) {
+			result.Mismatches = append(result.Mismatches, Mismatch{Key: row.Key, Stored: row.Totals, Expected: want})
+		}
+	}
+	for key, totals := range expected {
+		if !seen[key] && !pending[key.SessionID] {
+			result.Mismatches = append(result.Mismatches, Mismatch{Key: key, Expected: *totals})
+		}
+	}
+
+	result.Archived = len(archived)
+
+	sort.Slice(result.Mismatches, func(i, j int) bool {
+		return lessKey(result.Mismatches[i].Key, result.Mismatches[j].Key)
+	})
+	return result, nil
+}
+
+// sameTotals compares the integer counters of two totals. Cost is derived
+// from the counts and is not compared to avoid float noise.
+func sameTotals(a, b Totals) bool {
+	return a.Entries == b.Entries &&
+		a.InputTokens == b.InputTokens &&
+		a.OutputTokens == b.OutputTokens &&
+		a.CacheCreationTokens == b.CacheCreationTokens &&
+		a.CacheReadTokens == b.CacheReadTokens
+}
+
+// entryKey returns the rollup key for entry, attributing entries without
+// an embedded session ID to the file's session.
+func entryKey(entry parser.UsageEntry, fileSessionID string) Key {
+	sessionID := entry.SessionID
+	if sessionID == "" {
+		sessionID = fileSessionID
+	}
+	return Key{
+		Date:      Date(entry.Timestamp),
+		Model:     entry.Message.Model,
+		SessionID: sessionID,
+	}
+}
+
+// encodeKey builds the sortable bucket key for k.
+func encodeKey(k Key) []byte {
+	return []byte(k.Date + keySep + k.Model + keySep + k.SessionID)
+}
+
+// decodeKey parses a bucket key built by encodeKey.
+func decodeKey(data []byte) Key {
+	parts := bytes.SplitN(data, []byte(keySep), 3)
+	for len(parts) < 3 {
+		parts = append(parts, nil)
+	}
+	return Key{Date: string(parts[0]), Model: string(parts[1]), SessionID: string(parts[2])}
+}
@@ -0,0 +1,316 @@
+// Package rollup maintains pre-aggregated usage totals per day, model,
+// and session.
+//
+// Rollups are updated incrementally: each ingest reads only the bytes
+// appended to a session file since the previous ingest, so month-scale
+// reports read a small table instead of re-parsing every JSONL file.
+//
+// Example usage:
+//
+//	store, err := rollup.New(db)
+//	if err != nil {
+//	    return err
+//	}
+//	if _, err := store.Ingest(ctx, sessions, r); err != nil {
+//	    return err
+//	}
+//	rows, err := store.Rows("2025-11-01", "2025-11-30")
+//	byModel := rollup.Summarize(rows, []rollup.Dimension{rollup.DimModel})
+package rollup
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/0xmhha/token-monitor/pkg/analysis"
+	"github.com/0xmhha/token-monitor/pkg/discovery"
+	"github.com/0xmhha/token-monitor/pkg/parser"
+	"github.com/0xmhha/token-monitor/pkg/reader"
+)
+
+// DateLayout is the format of rollup dates.
+const DateLayout = "2006-01-02"
+
+// Date returns the rollup date of t. Rollup dates are UTC days whatever
+// the local time zone, so callers comparing rollups with raw entries must
+// use the same day boundaries (see DayStart).
+func Date(t time.Time) string {
+	return t.UTC().Format(DateLayout)
+}
+
+// DayStart returns the start of the rollup day of t, midnight UTC.
+func DayStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// Dimension is a rollup grouping dimension.
+type Dimension string
+
+const (
+	// DimDate groups by day (YYYY-MM-DD).
+	DimDate Dimension = "date"
+
+	// DimModel groups by model name.
+	DimModel Dimension = "model"
+
+	// DimSession groups by session ID.
+	DimSession Dimension = "session"
+)
+
+// Key identifies a rollup row. Summarized rows leave ungrouped fields empty.
+type Key struct {
+	Date      string `json:"date,omitempty"`
+	Model     string `json:"model,omitempty"`
+	SessionID string `json:"session_id,omitempty"`
+}
+
+// Totals holds aggregated usage for one rollup row.
+type Totals struct {
+	Entries             int     `json:"entries"`
+	InputTokens         int     `json:"input_tokens"`
+	OutputTokens        int     `json:"output_tokens"`
+	CacheCreationTokens int     `json:"cache_creation_tokens"`
+	CacheReadTokens     int     `json:"cache_read_tokens"`
+	CostUSD             float64 `json:"cost_usd"`
+}
+
+// TotalTokens returns the sum of all token types.
+func (t Totals) TotalTokens() int {
+	return t.InputTokens + t.OutputTokens + t.CacheCreationTokens + t.CacheReadTokens
+}
+
+// AddEntry folds a usage entry into the totals.
+func (t *Totals) AddEntry(entry parser.UsageEntry) {
+	usage := entry.Message.Usage
+	t.Entries++
+	t.InputTokens += usage.InputTokens
+	t.OutputTokens += usage.OutputTokens
+	t.CacheCreationTokens += usage.CacheCreationInputTokens
+	t.CacheReadTokens += usage.CacheReadInputTokens
+	t.CostUSD += analysis.EntryCost(entry)
+}
+
+// Merge adds other into the totals.
+func (t *Totals) Merge(other Totals) {
+	t.Entries += other.Entries
+	t.InputTokens += other.InputTokens
+	t.OutputTokens += other.OutputTokens
+	t.CacheCreationTokens += other.CacheCreationTokens
+	t.CacheReadTokens += other.CacheReadTokens
+	t.CostUSD += other.CostUSD
+}
+
+// Row is a rollup key with its totals.
+type Row struct {
+	Key
+	Totals
+}
+
+// IngestStats reports the work done by one Ingest call.
+type IngestStats struct {
+	// Files is the number of session files that had new entries.
+	Files int
+
+	// Entries is the number of entries added to the rollups.
+	Entries int
+
+	// Skipped is the number of files that could not be read.
+	// They are retried from the same offset on the next ingest.
+	Skipped int
+
+	// Changed is the number of files that were rewritten or truncated
+	// since they were ingested. They are not ingested again until a
+	// Rebuild, which would otherwise count their entries twice.
+	Changed int
+}
+
+// Store persists rollups and per-file ingest offsets.
+//
+// Thread-safety: Implementations must be safe for concurrent use.
+type Store interface {
+	// Ingest reads entries appended to each file since the last ingest
+	// and adds them to the rollups. Rollup updates and the file's new
+	// Checkpoint are committed atomically after every read, so an
+	// interrupted ingest never double counts and resumes where it stopped.
+	// Concurrent ingests of the same file count each entry once: a commit
+	// whose checkpoint was advanced meanwhile is dropped.
+	Ingest(ctx context.Context, files []discovery.SessionFile, r reader.Reader) (IngestStats, error)
+
+	// Rows returns rollup rows with from <= date <= to, ordered by key.
+	// Empty bounds are open.
+	Rows(from, to string) ([]Row, error)
+
+	// Reset removes all rollups and offsets so the next ingest rebuilds them.
+	Reset() error
+
+	// Rebuild recomputes the rollups of every session that still has raw
+	// entries in files and re-ingests them from the start. Rows of sessions
+	// whose files are gone, e.g. deleted by Claude's log cleanup, are kept.
+	// Nothing is changed if a file cannot be read. An interrupted rebuild
+	// is resumed from the checkpoints by the next Rebuild instead of
+	// starting over.
+	Rebuild(ctx context.Context, files []discovery.SessionFile, r reader.Reader) (IngestStats, error)
+
+	// Offsets returns the ingest offset of every tracked file.
+	Offsets() (map[string]int64, error)
+
+	// DeleteOffset stops tracking a file, e.g. after it was removed.
+	DeleteOffset(path string) error
+
+	// Progress reports how much of files has been ingested, from the
+	// stored checkpoints and the current file sizes.
+	Progress(files []discovery.SessionFile) (Progress, error)
+
+	// Verify recomputes rollups from the raw files and compares them with
+	// the stored rows. Sessions with entries not yet ingested are skipped
+	// and counted as pending; sessions without any raw entries left are
+	// skipped and counted as archived.
+	Verify(ctx context.Context, files []discovery.SessionFile, r reader.Reader) (VerifyResult, error)
+
+	// Prune removes the rows dated before before (YYYY-MM-DD) and returns
+	// how many were removed. Entries dated before the latest prune are no
+	// longer ingested or verified, so pruned days stay pruned while their
+	// session files remain.
+	Prune(before string) (int, error)
+
+	// PrunedBefore returns the date before which rows were pruned, or ""
+	// if they never were.
+	PrunedBefore() (string, error)
+}
+
+// Mismatch is a rollup row whose stored totals differ from the raw data.
+type Mismatch struct {
+	Key
+	Stored   Totals
+	Expected Totals
+}
+
+// VerifyResult reports the outcome of Store.Verify.
+type VerifyResult struct {
+	// Mismatches lists rows whose stored totals disagree with raw entries.
+	Mismatches []Mismatch
+
+	// Pending is the number of files with entries not yet ingested.
+	Pending int
+
+	// Archived is the number of stored sessions whose raw files are gone.
+	// Their rollups are the only record left and cannot be verified.
+	Archived int
+}
+
+// Project returns the key with only the fields of dims set, the key of
+// the group k belongs to in Summarize.
+func (k Key) Project(dims []Dimension) Key {
+	var key Key
+	for _, dim := range dims {
+		switch dim {
+		case DimDate:
+			key.Date = k.Date
+		case DimModel:
+			key.Model = k.Model
+		case DimSession:
+			key.SessionID = k.SessionID
+		}
+	}
+	return key
+}
+
+// Summarize merges rows into groups by the given dimensions, ordered by key.
+// With no dimensions, all rows merge into a single total.
+func Summarize(rows []Row, dims []Dimension) []Row {
+	groups := make(map[Key]*Totals)
+	for _, row := range rows {
+		key := row.Key.Project(dims)
+		totals, ok := groups[key]
+		if !ok {
+			totals = &Totals{}
+			groups[key] = totals
+		}
+		totals.Merge(row.Totals)
+	}
+
+	result := make([]Row, 0, len(groups))
+	for key, totals := range groups {
+		result = append(result, Row{Key: key, Totals: *totals})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return lessKey(result[i].Key, result[j].Key)
+	})
+	return result
+}
+
+// WeekdayAverage is the average daily usage on one day of the week.
+type WeekdayAverage struct {
+	Weekday time.Weekday `json:"-"`
+	Name    string       `json:"weekday"`
+
+	// Days is the number of such weekdays in the range; averages divide
+	// by it, so idle days count as zero.
+	Days int `json:"days"`
+
+	// ActiveDays is the number of those days with any usage.
+	ActiveDays int `json:"active_days"`
+
+	Entries     float64 `json:"entries"`
+	TotalTokens float64 `json:"total_tokens"`
+	CostUSD     float64 `json:"cost_usd"`
+}
+
+// WeekdayAverages returns the average daily usage per weekday for rows
+// dated from..to (inclusive dates), Monday first. Rows outside the range
+// are ignored.
+func WeekdayAverages(rows []Row, from, to time.Time) []WeekdayAverage {
+	fromDate, toDate := from.Format(DateLayout), to.Format(DateLayout)
+
+	daily := make(map[string]Totals)
+	for _, row := range rows {
+		if row.Date < fromDate || row.Date > toDate {
+			continue
+		}
+		totals := daily[row.Date]
+		totals.Merge(row.Totals)
+		daily[row.Date] = totals
+	}
+
+	var sums [7]WeekdayAverage
+	for day := from; day.Format(DateLayout) <= toDate; day = day.AddDate(0, 0, 1) {
+		sum := &sums[day.Weekday()]
+		sum.Days++
+		totals, ok := daily[day.Format(DateLayout)]
+		if !ok || totals.Entries == 0 {
+			continue
+		}
+		sum.ActiveDays++
+		sum.Entries += float64(totals.Entries)
+		sum.TotalTokens += float64(totals.TotalTokens())
+		sum.CostUSD += totals.CostUSD
+	}
+
+	result := make([]WeekdayAverage, 0, 7)
+	for i := 1; i <= 7; i++ {
+		weekday := time.Weekday(i % 7)
+		avg := sums[weekday]
+		avg.Weekday = weekday
+		avg.Name = weekday.String()
+		if avg.Days > 0 {
+			avg.Entries /= float64(avg.Days)
+			avg.TotalTokens /= float64(avg.Days)
+			avg.CostUSD /= float64(avg.Days)
+		}
+		result = append(result, avg)
+	}
+	return result
+}
+
+// lessKey orders keys by date, model, then session.
+func lessKey(a, b Key) bool {
+	if a.Date != b.Date {
+		return a.Date < b.Date
+	}
+	if a.Model != b.Model {
+		return a.Model < b.Model
+	}
+	return a.SessionID < b.SessionID
+}
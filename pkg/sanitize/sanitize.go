@@ -0,0 +1,177 @@
+// Package sanitize strips personal data from session lines, configuration,
+// and logs so they can be attached to public bug reports.
+//
+// Session lines keep their structure, numbers, and a few descriptive
+// fields (type, model, timestamps) but lose prompts, file contents, and
+// identifiers. Lines that are not valid JSON keep only their punctuation.
+//
+// Example usage:
+//
+//	safe := sanitize.Line(line)
+//	cfgYAML, err := sanitize.Config(data)
+package sanitize
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MaxLineLength bounds the length of a sanitized line.
+const MaxLineLength = 4096
+
+// Redacted replaces configuration values that look like credentials.
+const Redacted = "REDACTED"
+
+// keptFields are JSON keys whose string values are kept verbatim: they
+// describe the shape of an entry, not what the user wrote.
+var keptFields = map[string]bool{
+	"type":         true,
+	"role":         true,
+	"model":        true,
+	"version":      true,
+	"timestamp":    true,
+	"stop_reason":  true,
+	"userType":     true,
+	"service_tier": true,
+}
+
+// secretKey matches configuration keys whose values are credentials.
+var secretKey = regexp.MustCompile(`(?i)(token|secret|password|passwd|api_?key|webhook|credential)`)
+
+// Line returns a session line with personal data removed.
+//
+// JSON lines keep keys, numbers, booleans, and the values of descriptive
+// fields; other strings become "<redacted:N>" with N their length. Lines
+// that are not valid JSON keep punctuation and whitespace, with letters
+// masked as x and digits as 0, so truncation and quoting problems remain
+// visible. The result is at most MaxLineLength bytes.
+func Line(line string) string {
+	dec := json.NewDecoder(strings.NewReader(line))
+	dec.UseNumber()
+
+	var v interface{}
+	if err := dec.Decode(&v); err != nil || dec.More() {
+		return truncate(mask(line), len(line))
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(value("", v)); err != nil {
+		return truncate(mask(line), len(line))
+	}
+	return truncate(strings.TrimSuffix(buf.String(), "\n"), len(line))
+}
+
+// value sanitizes a decoded JSON value found under key.
+func value(key string, v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, item := range v {
+			v[k] = value(k, item)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = value(key, item)
+		}
+		return v
+	case string:
+		if keptFields[key] {
+			return v
+		}
+		return fmt.Sprintf("<redacted:%d>", len(v))
+	default:
+		return v
+	}
+}
+
+// mask hides letters and digits but keeps everything else.
+func mask(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range s {
+		switch {
+		case unicode.IsLetter(r):
+			b.WriteByte('x')
+		case unicode.IsDigit(r):
+			b.WriteByte('0')
+		case unicode.IsPunct(r), unicode.IsSpace(r), unicode.IsSymbol(r):
+			b.WriteRune(r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+// truncate shortens s to MaxLineLength bytes, noting the original size.
+func truncate(s string, original int) string {
+	if len(s) <= MaxLineLength {
+		return s
+	}
+	cut := MaxLineLength
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return fmt.Sprintf("%s...(truncated, %d bytes)", s[:cut], original)
+}
+
+// Config returns YAML configuration with credential-like values replaced
+// by Redacted and the home directory shortened to ~.
+func Config(data []byte) ([]byte, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+	redactNode(&root)
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&root); err != nil {
+		return nil, fmt.Errorf("failed to encode config: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode config: %w", err)
+	}
+	return []byte(Home(buf.String())), nil
+}
+
+// redactNode replaces scalar values under credential-like keys.
+func redactNode(node *yaml.Node) {
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, val := node.Content[i], node.Content[i+1]
+			if secretKey.MatchString(key.Value) && val.Kind == yaml.ScalarNode && val.Value != "" {
+				val.Value = Redacted
+				val.Tag = "!!str"
+				val.Style = 0
+				continue
+			}
+			redactNode(val)
+		}
+		return
+	}
+	for _, child := range node.Content {
+		redactNode(child)
+	}
+}
+
+// Home replaces the user's home directory in s with ~, so paths in
+// configuration and logs do not reveal the user name.
+func Home(s string) string {
+	home, err := os.UserHomeDir()
+	if err != nil || home == "" || home == "/" {
+		return s
+	}
+	return strings.ReplaceAll(s, home, "~")
+}
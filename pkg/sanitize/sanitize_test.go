@@ -0,0 +1,111 @@
+package sanitize
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLine_JSON(t *testing.T) {
+	line := `{"type":"assistant","sessionId":"6f1c2a4e-0000-4000-8000-000000000001","cwd":"/home/alice/secret-project",` +
+		`"timestamp":"2025-11-01T10:00:00Z","message":{"model":"claude-sonnet-4","content":[{"type":"text","text":"my password is hunter2"}],` +
+		`"usage":{"input_tokens":120,"output_tokens":-5}}}`
+
+	got := Line(line)
+
+	for _, leaked := range []string{"alice", "secret-project", "hunter2", "6f1c2a4e"} {
+		if strings.Contains(got, leaked) {
+			t.Errorf("Line() leaked %q: %s", leaked, got)
+		}
+	}
+
+	var decoded struct {
+		Type      string `json:"type"`
+		CWD       string `json:"cwd"`
+		Timestamp string `json:"timestamp"`
+		Message   struct {
+			Model string `json:"model"`
+			Usage struct {
+				InputTokens  int `json:"input_tokens"`
+				OutputTokens int `json:"output_tokens"`
+			} `json:"usage"`
+		} `json:"message"`
+	}
+	if err := json.Unmarshal([]byte(got), &decoded); err != nil {
+		t.Fatalf("Line() returned invalid JSON: %v\n%s", err, got)
+	}
+	if decoded.Type != "assistant" || decoded.Message.Model != "claude-sonnet-4" || decoded.Timestamp == "" {
+		t.Errorf("Line() dropped descriptive fields: %s", got)
+	}
+	if decoded.Message.Usage.InputTokens != 120 || decoded.Message.Usage.OutputTokens != -5 {
+		t.Errorf("Line() changed token counts: %s", got)
+	}
+	if decoded.CWD != "<redacted:26>" {
+		t.Errorf("cwd = %q, want <redacted:26>", decoded.CWD)
+	}
+}
+
+func TestLine_Malformed(t *testing.T) {
+	tests := []struct {
+		line string
+		want string
+	}{
+		{`{"cwd":"/home/bob","usage":{"input_tokens":12`, `{"xxx":"/xxxx/xxx","xxxxx":{"xxxxx_xxxxxx":00`},
+		{`not json at all`, `xxx xxxx xx xxx`},
+		{`{"a":1} {"b":2}`, `{"x":0} {"x":0}`},
+	}
+	for _, tt := range tests {
+		if got := Line(tt.line); got != tt.want {
+			t.Errorf("Line(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestLine_Truncates(t *testing.T) {
+	line := strings.Repeat("a", MaxLineLength*2)
+	got := Line(line)
+	if !strings.HasSuffix(got, "...(truncated, 8192 bytes)") {
+		t.Errorf("Line() did not note truncation: ...%s", got[len(got)-40:])
+	}
+	if len(got) > MaxLineLength+40 {
+		t.Errorf("len(Line()) = %d, want about %d", len(got), MaxLineLength)
+	}
+}
+
+func TestConfig(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	data := []byte("claude_config_dirs:\n  - " + filepath.Join(home, ".claude", "projects") + "\n" +
+		"notify:\n  discord_webhook: https://discord.example/hook/abc\n  api_key: sk-123\n  empty_token: \"\"\n" +
+		"logging:\n  level: info\n")
+
+	got, err := Config(data)
+	if err != nil {
+		t.Fatalf("Config() error = %v", err)
+	}
+	out := string(got)
+
+	for _, leaked := range []string{"discord.example", "sk-123", home} {
+		if strings.Contains(out, leaked) {
+			t.Errorf("Config() leaked %q:\n%s", leaked, out)
+		}
+	}
+	for _, want := range []string{"~/.claude/projects", "discord_webhook: REDACTED", "api_key: REDACTED", `empty_token: ""`, "level: info"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Config() missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestHome(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil || home == "/" {
+		t.Skip("no usable home directory")
+	}
+	if got := Home("open " + home + "/x.log failed"); got != "open ~/x.log failed" {
+		t.Errorf("Home() = %q", got)
+	}
+}
@@ -16,6 +16,16 @@ var (
 	// ErrEmptyName is returned when a session name is empty.
 	ErrEmptyName = errors.New("session name cannot be empty")
 
+	// ErrInvalidName is returned when a session name breaks the naming rules.
+	ErrInvalidName = errors.New("invalid session name")
+
+	// ErrConflict is returned when an update is based on a stale revision.
+	ErrConflict = errors.New("session was modified concurrently")
+
 	// ErrInvalidMetadata is returned when metadata is invalid.
 	ErrInvalidMetadata = errors.New("invalid metadata")
+
+	// ErrDatabaseLocked is returned when another process, typically watch,
+	// holds the session database.
+	ErrDatabaseLocked = errors.New("session database is locked by another process")
 )
@@ -0,0 +1,129 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// IndexIssue describes an inconsistency between the sessions bucket and
+// the names index.
+type IndexIssue struct {
+	// Name is the affected name index key, if any.
+	Name string
+
+	// UUID is the affected session UUID, if any.
+	UUID string
+
+	// Problem describes the inconsistency.
+	Problem string
+
+	// Repaired reports whether CheckIndex fixed the issue.
+	Repaired bool
+}
+
+// CheckIndex implements Manager.CheckIndex.
+func (m *manager) CheckIndex(repair bool) ([]IndexIssue, error) {
+	var issues []IndexIssue
+
+	check := func(tx *bolt.Tx) error {
+		sessions := tx.Bucket(bucketSessions)
+		names := tx.Bucket(bucketNames)
+
+		// Decode sessions first so both directions can be cross-checked.
+		metadata := make(map[string]Metadata)
+		var corrupt []string
+		if err := sessions.ForEach(func(k, v []byte) error {
+			var md Metadata
+			if err := json.Unmarshal(v, &md); err != nil {
+				corrupt = append(corrupt, string(k))
+				return nil
+			}
+			metadata[string(k)] = md
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		for _, uuid := range corrupt {
+			issue := IndexIssue{UUID: uuid, Problem: "session metadata cannot be decoded"}
+			if repair {
+				if err := sessions.Delete([]byte(uuid)); err != nil {
+					return fmt.Errorf("failed to delete corrupt session: %w", err)
+				}
+				issue.Repaired = true
+			}
+			issues = append(issues, issue)
+		}
+
+		// Names index -> sessions.
+		var staleNames []string
+		if err := names.ForEach(func(k, v []byte) error {
+			name, uuid := string(k), string(v)
+			md, ok := metadata[uuid]
+			switch {
+			case !ok:
+				issues = append(issues, IndexIssue{Name: name, UUID: uuid, Problem: "name points to a missing session", Repaired: repair})
+				staleNames = append(staleNames, name)
+			case string(foldName(md.Name)) != name:
+				issues = append(issues, IndexIssue{Name: name, UUID: uuid, Problem: fmt.Sprintf("name points to a session named %q", md.Name), Repaired: repair})
+				staleNames = append(staleNames, name)
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+		if repair {
+			for _, name := range staleNames {
+				if err := names.Delete([]byte(name)); err != nil {
+					return fmt.Errorf("failed to delete stale name: %w", err)
+				}
+			}
+		}
+
+		// Sessions -> names index.
+		uuids := make([]string, 0, len(metadata))
+		for uuid := range metadata {
+			uuids = append(uuids, uuid)
+		}
+		sort.Strings(uuids)
+
+		for _, uuid := range uuids {
+			md := metadata[uuid]
+			if md.Name == "" {
+				continue
+			}
+			indexed := names.Get(foldName(md.Name))
+			if string(indexed) == uuid {
+				continue
+			}
+
+			issue := IndexIssue{Name: md.Name, UUID: uuid, Problem: "session name is missing from the names index"}
+			if indexed != nil {
+				issue.Problem = fmt.Sprintf("session name is indexed to %s", string(indexed))
+			} else if repair {
+				if err := names.Put(foldName(md.Name), []byte(uuid)); err != nil {
+					return fmt.Errorf("failed to restore name index: %w", err)
+				}
+				issue.Repaired = true
+			}
+			issues = append(issues, issue)
+		}
+
+		return nil
+	}
+
+	var err error
+	if repair {
+		err = m.db.Update(check)
+	} else {
+		err = m.db.View(check)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to check names index: %w", err)
+	}
+
+	return issues, nil
+}
@@ -2,6 +2,7 @@ package session
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -14,15 +15,28 @@ import (
 
 // Bucket names.
 var (
-	bucketSessions = []byte("sessions") // UUID -> Metadata
-	bucketNames    = []byte("names")    // Name -> UUID (index)
+	bucketSessions = []byte("sessions")     // UUID -> Metadata
+	bucketNames    = []byte("names")        // Folded name -> UUID (index)
+	bucketMeta     = []byte("session_meta") // Schema markers
 )
 
+// Meta keys.
+var (
+	// metaNamesIndex records the names index key format.
+	metaNamesIndex = []byte("names_index")
+)
+
+// namesIndexFolded marks a names index keyed by foldName.
+const namesIndexFolded = "folded"
+
 // manager implements the Manager interface using BoltDB.
 type manager struct {
 	db     *bolt.DB
 	logger logger.Logger
 	config Config
+
+	// tempDir holds a temporary database, removed on Close
+	tempDir string
 }
 
 // New creates a new session manager.
@@ -40,6 +54,22 @@ func New(cfg Config, log logger.Logger) (Manager, error) {
 		cfg.Timeout = time.Second
 	}
 
+	switch cfg.NameValidation {
+	case "":
+		cfg.NameValidation = NameValidationStrict
+	case NameValidationStrict, NameValidationRelaxed:
+	default:
+		return nil, fmt.Errorf("unknown name validation mode: %s", cfg.NameValidation)
+	}
+
+	switch cfg.NameNormalization {
+	case "":
+		cfg.NameNormalization = NameNormalizeTrim
+	case NameNormalizeTrim, NameNormalizeLower, NameNormalizeNone:
+	default:
+		return nil, fmt.Errorf("unknown name normalization mode: %s", cfg.NameNormalization)
+	}
+
 	// Expand home directory in path.
 	dbPath := expandHome(cfg.DBPath)
 
@@ -53,6 +83,9 @@ func New(cfg Config, log logger.Logger) (Manager, error) {
 	db, err := bolt.Open(dbPath, 0600, &bolt.Options{
 		Timeout: cfg.Timeout,
 	})
+	if errors.Is(err, bolt.ErrTimeout) {
+		return nil, fmt.Errorf("%w: %s", ErrDatabaseLocked, dbPath)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
@@ -65,6 +98,18 @@ func New(cfg Config, log logger.Logger) (Manager, error) {
 		if _, createErr := tx.CreateBucketIfNotExists(bucketNames); createErr != nil {
 			return fmt.Errorf("failed to create names bucket: %w", createErr)
 		}
+		meta, createErr := tx.CreateBucketIfNotExists(bucketMeta)
+		if createErr != nil {
+			return fmt.Errorf("failed to create session meta bucket: %w", createErr)
+		}
+		if string(meta.Get(metaNamesIndex)) != namesIndexFolded {
+			if rebuildErr := rebuildNamesIndex(tx, log); rebuildErr != nil {
+				return rebuildErr
+			}
+			if putErr := meta.Put(metaNamesIndex, []byte(namesIndexFolded)); putErr != nil {
+				return fmt.Errorf("failed to store names index marker: %w", putErr)
+			}
+		}
 		return nil
 	}); err != nil {
 		if closeErr := db.Close(); closeErr != nil {
@@ -85,232 +130,50 @@ func New(cfg Config, log logger.Logger) (Manager, error) {
 
 // Create implements Manager.Create.
 func (m *manager) Create(metadata *Metadata) error {
-	if metadata == nil {
-		return ErrInvalidMetadata
-	}
-
-	// Validate UUID.
-	if !isValidUUID(metadata.UUID) {
-		return ErrInvalidUUID
-	}
-
-	// Validate name.
-	if metadata.Name == "" {
-		return ErrEmptyName
-	}
-
-	// Set timestamps.
-	now := time.Now()
-	metadata.CreatedAt = now
-	metadata.UpdatedAt = now
-
-	return m.db.Update(func(tx *bolt.Tx) error {
-		sessions := tx.Bucket(bucketSessions)
-		names := tx.Bucket(bucketNames)
-
-		// Check if UUID already exists.
-		if sessions.Get([]byte(metadata.UUID)) != nil {
-			return fmt.Errorf("session %s already exists", metadata.UUID)
-		}
-
-		// Check if name is already taken.
-		if names.Get([]byte(metadata.Name)) != nil {
-			return ErrNameConflict
-		}
-
-		// Marshal metadata.
-		data, err := json.Marshal(metadata)
-		if err != nil {
-			return fmt.Errorf("failed to marshal metadata: %w", err)
-		}
-
-		// Store in sessions bucket.
-		if err := sessions.Put([]byte(metadata.UUID), data); err != nil {
-			return fmt.Errorf("failed to store session: %w", err)
-		}
-
-		// Store in names index.
-		if err := names.Put([]byte(metadata.Name), []byte(metadata.UUID)); err != nil {
-			return fmt.Errorf("failed to store name index: %w", err)
-		}
-
-		m.logger.Info("session created",
-			"uuid", metadata.UUID,
-			"name", metadata.Name)
-
-		return nil
+	return m.WithTx(func(tx Tx) error {
+		return tx.Create(metadata)
 	})
 }
 
 // GetByUUID implements Manager.GetByUUID.
 func (m *manager) GetByUUID(uuid string) (*Metadata, error) {
-	if !isValidUUID(uuid) {
-		return nil, ErrInvalidUUID
-	}
-
 	var metadata *Metadata
-
 	err := m.db.View(func(tx *bolt.Tx) error {
-		sessions := tx.Bucket(bucketSessions)
-
-		data := sessions.Get([]byte(uuid))
-		if data == nil {
-			return ErrSessionNotFound
-		}
-
-		var m Metadata
-		if unmarshalErr := json.Unmarshal(data, &m); unmarshalErr != nil {
-			return fmt.Errorf("failed to unmarshal metadata: %w", unmarshalErr)
-		}
-
-		metadata = &m
-		return nil
+		var getErr error
+		metadata, getErr = m.txn(tx).GetByUUID(uuid)
+		return getErr
 	})
-
 	if err != nil {
 		return nil, err
 	}
-
 	return metadata, nil
 }
 
 // GetByName implements Manager.GetByName.
 func (m *manager) GetByName(name string) (*Metadata, error) {
-	if name == "" {
-		return nil, ErrEmptyName
-	}
-
-	var uuid string
-
-	// First, get UUID from name index.
-	if err := m.db.View(func(tx *bolt.Tx) error {
-		names := tx.Bucket(bucketNames)
-
-		uuidBytes := names.Get([]byte(name))
-		if uuidBytes == nil {
-			return ErrSessionNotFound
-		}
-
-		uuid = string(uuidBytes)
-		return nil
-	}); err != nil {
+	var metadata *Metadata
+	err := m.db.View(func(tx *bolt.Tx) error {
+		var getErr error
+		metadata, getErr = m.txn(tx).GetByName(name)
+		return getErr
+	})
+	if err != nil {
 		return nil, err
 	}
-
-	// Then, get metadata by UUID.
-	return m.GetByUUID(uuid)
+	return metadata, nil
 }
 
 // Update implements Manager.Update.
 func (m *manager) Update(uuid string, metadata *Metadata) error {
-	if metadata == nil {
-		return ErrInvalidMetadata
-	}
-
-	if !isValidUUID(uuid) {
-		return ErrInvalidUUID
-	}
-
-	if metadata.Name == "" {
-		return ErrEmptyName
-	}
-
-	return m.db.Update(func(tx *bolt.Tx) error {
-		sessions := tx.Bucket(bucketSessions)
-		names := tx.Bucket(bucketNames)
-
-		// Check if session exists.
-		existingData := sessions.Get([]byte(uuid))
-		if existingData == nil {
-			return ErrSessionNotFound
-		}
-
-		// Unmarshal existing metadata.
-		var existing Metadata
-		if err := json.Unmarshal(existingData, &existing); err != nil {
-			return fmt.Errorf("failed to unmarshal existing metadata: %w", err)
-		}
-
-		// Check if name changed and if new name is available.
-		if existing.Name != metadata.Name {
-			// Check if new name is already taken.
-			if existingUUID := names.Get([]byte(metadata.Name)); existingUUID != nil {
-				return ErrNameConflict
-			}
-
-			// Remove old name from index.
-			if err := names.Delete([]byte(existing.Name)); err != nil {
-				return fmt.Errorf("failed to delete old name index: %w", err)
-			}
-
-			// Add new name to index.
-			if err := names.Put([]byte(metadata.Name), []byte(uuid)); err != nil {
-				return fmt.Errorf("failed to store new name index: %w", err)
-			}
-		}
-
-		// Preserve creation time, update modification time.
-		metadata.UUID = uuid
-		metadata.CreatedAt = existing.CreatedAt
-		metadata.UpdatedAt = time.Now()
-
-		// Marshal updated metadata.
-		data, err := json.Marshal(metadata)
-		if err != nil {
-			return fmt.Errorf("failed to marshal metadata: %w", err)
-		}
-
-		// Store updated metadata.
-		if err := sessions.Put([]byte(uuid), data); err != nil {
-			return fmt.Errorf("failed to update session: %w", err)
-		}
-
-		m.logger.Info("session updated",
-			"uuid", uuid,
-			"name", metadata.Name)
-
-		return nil
+	return m.WithTx(func(tx Tx) error {
+		return tx.Update(uuid, metadata)
 	})
 }
 
 // Delete implements Manager.Delete.
 func (m *manager) Delete(uuid string) error {
-	if !isValidUUID(uuid) {
-		return ErrInvalidUUID
-	}
-
-	return m.db.Update(func(tx *bolt.Tx) error {
-		sessions := tx.Bucket(bucketSessions)
-		names := tx.Bucket(bucketNames)
-
-		// Get existing metadata to find name.
-		data := sessions.Get([]byte(uuid))
-		if data == nil {
-			// Session doesn't exist, no error.
-			return nil
-		}
-
-		// Unmarshal to get name.
-		var metadata Metadata
-		if err := json.Unmarshal(data, &metadata); err != nil {
-			return fmt.Errorf("failed to unmarshal metadata: %w", err)
-		}
-
-		// Delete from sessions bucket.
-		if err := sessions.Delete([]byte(uuid)); err != nil {
-			return fmt.Errorf("failed to delete session: %w", err)
-		}
-
-		// Delete from names index.
-		if err := names.Delete([]byte(metadata.Name)); err != nil {
-			return fmt.Errorf("failed to delete name index: %w", err)
-		}
-
-		m.logger.Info("session deleted",
-			"uuid", uuid,
-			"name", metadata.Name)
-
-		return nil
+	return m.WithTx(func(tx Tx) error {
+		return tx.Delete(uuid)
 	})
 }
 
@@ -344,25 +207,71 @@ func (m *manager) List() ([]*Metadata, error) {
 
 // SetName implements Manager.SetName.
 func (m *manager) SetName(uuid, name string) error {
-	if !isValidUUID(uuid) {
-		return ErrInvalidUUID
-	}
+	return m.WithTx(func(tx Tx) error {
+		return tx.SetName(uuid, name)
+	})
+}
 
-	if name == "" {
-		return ErrEmptyName
-	}
+// SetState implements Manager.SetState.
+func (m *manager) SetState(uuid string, state State, at time.Time) error {
+	return m.WithTx(func(tx Tx) error {
+		return tx.SetState(uuid, state, at)
+	})
+}
 
-	// Get existing metadata.
-	existing, err := m.GetByUUID(uuid)
+// rebuildNamesIndex recreates the names index with folded keys from the
+// sessions bucket. It upgrades databases written before names were folded.
+// When two names fold to the same key, the session with the lower UUID
+// keeps the name; CheckIndex reports the other.
+func rebuildNamesIndex(tx *bolt.Tx, log logger.Logger) error {
+	if err := tx.DeleteBucket(bucketNames); err != nil {
+		return fmt.Errorf("failed to delete names bucket: %w", err)
+	}
+	names, err := tx.CreateBucket(bucketNames)
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to create names bucket: %w", err)
 	}
 
-	// Update name.
-	existing.Name = name
+	return tx.Bucket(bucketSessions).ForEach(func(k, v []byte) error {
+		var md Metadata
+		if err := json.Unmarshal(v, &md); err != nil || md.Name == "" {
+			return nil // Reported by CheckIndex.
+		}
 
-	// Use Update to handle name change.
-	return m.Update(uuid, existing)
+		key := foldName(md.Name)
+		if owner := names.Get(key); owner != nil {
+			log.Warn("session names differ only in case or spacing",
+				"name", md.Name,
+				"uuid", string(k),
+				"indexed_uuid", string(owner))
+			return nil
+		}
+		return names.Put(key, k)
+	})
+}
+
+// NewTemporary creates a session manager backed by a new database in a
+// temporary directory, which Close removes. It stands in for the
+// configured database when that cannot be opened, e.g. on a read-only
+// file system, so commands that only read session files keep working.
+// Nothing written to it is kept.
+//
+// cfg.DBPath is ignored.
+func NewTemporary(cfg Config, log logger.Logger) (Manager, error) {
+	dir, err := os.MkdirTemp("", "token-monitor-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary database directory: %w", err)
+	}
+
+	cfg.DBPath = filepath.Join(dir, "sessions.db")
+	mgr, err := New(cfg, log)
+	if err != nil {
+		_ = os.RemoveAll(dir) //nolint:errcheck // best effort cleanup
+		return nil, err
+	}
+	m := mgr.(*manager)
+	m.tempDir = dir
+	return m, nil
 }
 
 // Close implements Manager.Close.
@@ -370,6 +279,11 @@ func (m *manager) Close() error {
 	if err := m.db.Close(); err != nil {
 		return fmt.Errorf("failed to close database: %w", err)
 	}
+	if m.tempDir != "" {
+		if err := os.RemoveAll(m.tempDir); err != nil {
+			return fmt.Errorf("failed to remove temporary database: %w", err)
+		}
+	}
 
 	m.logger.Info("session manager closed")
 	return nil
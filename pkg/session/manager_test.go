@@ -1,11 +1,15 @@
 package session
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	bolt "go.etcd.io/bbolt"
+
 	"github.com/0xmhha/token-monitor/pkg/logger"
 )
 
@@ -53,6 +57,41 @@ func TestNewWithHomeDir(t *testing.T) {
 	}
 }
 
+func TestNewLocked(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+
+	holder, err := New(Config{DBPath: dbPath}, logger.Noop())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer func() { _ = holder.Close() }() //nolint:errcheck
+
+	_, err = New(Config{DBPath: dbPath, Timeout: 50 * time.Millisecond}, logger.Noop())
+	if !errors.Is(err, ErrDatabaseLocked) {
+		t.Errorf("New() on a locked database error = %v, want ErrDatabaseLocked", err)
+	}
+}
+
+func TestNewTemporary(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+
+	mgr, err := NewTemporary(Config{DBPath: "/nonexistent/ignored.db"}, logger.Noop())
+	if err != nil {
+		t.Fatalf("NewTemporary() error = %v", err)
+	}
+	if err := mgr.Create(&Metadata{UUID: "550e8400-e29b-41d4-a716-446655440000", Name: "scratch"}); err != nil {
+		t.Errorf("Create() error = %v", err)
+	}
+
+	dbPath := mgr.DB().Path()
+	if err := mgr.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Dir(dbPath)); !os.IsNotExist(err) {
+		t.Errorf("temporary database directory left behind: %v", err)
+	}
+}
+
 func TestCreate(t *testing.T) {
 	mgr := setupTestManager(t)
 
@@ -465,6 +504,79 @@ func TestSetNameNotFound(t *testing.T) {
 	}
 }
 
+func TestSetState(t *testing.T) {
+	mgr := setupTestManager(t)
+
+	uuid := "a1b2c3d4-e5f6-7890-abcd-ef1234567890"
+	if err := mgr.Create(&Metadata{UUID: uuid, Name: "test-session"}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	created, err := mgr.GetByUUID(uuid)
+	if err != nil {
+		t.Fatalf("GetByUUID() error = %v", err)
+	}
+
+	at := time.Date(2025, 11, 3, 10, 0, 0, 0, time.UTC)
+	if err := mgr.SetState(uuid, StateIdle, at); err != nil {
+		t.Fatalf("SetState() error = %v", err)
+	}
+	// Recording the same state again keeps the first change time.
+	if err := mgr.SetState(uuid, StateIdle, at.Add(time.Hour)); err != nil {
+		t.Fatalf("SetState() error = %v", err)
+	}
+
+	got, err := mgr.GetByUUID(uuid)
+	if err != nil {
+		t.Fatalf("GetByUUID() error = %v", err)
+	}
+	if got.State != StateIdle || !got.StateChangedAt.Equal(at) {
+		t.Errorf("state = %q at %v, want idle at %v", got.State, got.StateChangedAt, at)
+	}
+	if !got.UpdatedAt.Equal(created.UpdatedAt) {
+		t.Errorf("UpdatedAt = %v, want unchanged %v", got.UpdatedAt, created.UpdatedAt)
+	}
+	if got.Revision != created.Revision+1 {
+		t.Errorf("Revision = %d, want %d", got.Revision, created.Revision+1)
+	}
+
+	if err := mgr.SetState("b1b2c3d4-e5f6-7890-abcd-ef1234567890", StateIdle, at); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("SetState() unknown session error = %v, want ErrSessionNotFound", err)
+	}
+}
+
+func TestStateAt(t *testing.T) {
+	th := Thresholds{IdleAfter: 30 * time.Minute, ArchiveAfter: 7 * 24 * time.Hour}
+	now := time.Date(2025, 11, 3, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		last time.Time
+		want State
+	}{
+		{"just written", now.Add(-time.Minute), StateActive},
+		{"at idle threshold", now.Add(-30 * time.Minute), StateActive},
+		{"quiet", now.Add(-2 * time.Hour), StateIdle},
+		{"dormant", now.Add(-8 * 24 * time.Hour), StateArchived},
+		{"never active", time.Time{}, StateArchived},
+	}
+	for _, tt := range tests {
+		if got := th.StateAt(tt.last, now); got != tt.want {
+			t.Errorf("%s: StateAt() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	if StateActive.Rank() >= StateIdle.Rank() || StateIdle.Rank() >= StateArchived.Rank() {
+		t.Error("Rank() does not order active, idle, archived")
+	}
+
+	if _, err := ParseState("paused"); err == nil {
+		t.Error("ParseState(\"paused\") error = nil, want error")
+	}
+	if st, err := ParseState("idle"); err != nil || st != StateIdle {
+		t.Errorf("ParseState(\"idle\") = %q, %v, want idle", st, err)
+	}
+}
+
 func TestIsValidUUID(t *testing.T) {
 	tests := []struct {
 		name string
@@ -597,6 +709,216 @@ func TestDataPersistence(t *testing.T) {
 }
 
 // setupTestManager creates a test manager with temp database.
+func TestCheckIndex(t *testing.T) {
+	mgr := setupTestManager(t)
+
+	uuid := "a1b2c3d4-e5f6-7890-abcd-ef1234567890"
+	if err := mgr.Create(&Metadata{UUID: uuid, Name: "work"}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	issues, err := mgr.CheckIndex(false)
+	if err != nil || len(issues) != 0 {
+		t.Fatalf("CheckIndex() on clean db = %v, %v; want no issues", issues, err)
+	}
+
+	// Corrupt the index: drop the real name and add a dangling one.
+	if err := mgr.DB().Update(func(tx *bolt.Tx) error {
+		names := tx.Bucket(bucketNames)
+		if err := names.Delete([]byte("work")); err != nil {
+			return err
+		}
+		return names.Put([]byte("ghost"), []byte("ffffffff-ffff-ffff-ffff-ffffffffffff"))
+	}); err != nil {
+		t.Fatalf("corrupt index: %v", err)
+	}
+
+	issues, err = mgr.CheckIndex(false)
+	if err != nil {
+		t.Fatalf("CheckIndex() error = %v", err)
+	}
+	if len(issues) != 2 {
+		t.Fatalf("CheckIndex() found %d issues, want 2: %+v", len(issues), issues)
+	}
+	for _, issue := range issues {
+		if issue.Repaired {
+			t.Errorf("issue %+v marked repaired without repair mode", issue)
+		}
+	}
+
+	if _, err := mgr.CheckIndex(true); err != nil {
+		t.Fatalf("CheckIndex(repair) error = %v", err)
+	}
+
+	if issues, _ = mgr.CheckIndex(false); len(issues) != 0 {
+		t.Errorf("issues remain after repair: %+v", issues)
+	}
+	if md, err := mgr.GetByName("work"); err != nil || md.UUID != uuid {
+		t.Errorf("GetByName(work) after repair = %v, %v", md, err)
+	}
+}
+
+func TestUpdateRevisionConflict(t *testing.T) {
+	mgr := setupTestManager(t)
+
+	uuid := "a1b2c3d4-e5f6-7890-abcd-ef1234567890"
+	if err := mgr.Create(&Metadata{UUID: uuid, Name: "original"}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	// Two writers read the same revision.
+	first, err := mgr.GetByUUID(uuid)
+	if err != nil {
+		t.Fatalf("GetByUUID() error = %v", err)
+	}
+	second := *first
+	if first.Revision != 1 {
+		t.Errorf("Revision after Create = %d, want 1", first.Revision)
+	}
+
+	first.Description = "first writer"
+	if err := mgr.Update(uuid, first); err != nil {
+		t.Fatalf("Update(first) error = %v", err)
+	}
+	if first.Revision != 2 {
+		t.Errorf("Revision after Update = %d, want 2", first.Revision)
+	}
+
+	// The second writer's revision is stale.
+	second.Name = "renamed"
+	if err := mgr.Update(uuid, &second); !errors.Is(err, ErrConflict) {
+		t.Fatalf("Update(stale) error = %v, want ErrConflict", err)
+	}
+
+	// Zero revision skips the check.
+	if err := mgr.Update(uuid, &Metadata{Name: "renamed"}); err != nil {
+		t.Fatalf("Update(revision 0) error = %v", err)
+	}
+	got, err := mgr.GetByUUID(uuid)
+	if err != nil {
+		t.Fatalf("GetByUUID() error = %v", err)
+	}
+	if got.Name != "renamed" || got.Revision != 3 {
+		t.Errorf("got name %q revision %d, want renamed / 3", got.Name, got.Revision)
+	}
+}
+
+func TestBatchCreate(t *testing.T) {
+	mgr := setupTestManager(t)
+
+	batch := []*Metadata{
+		{UUID: "a1b2c3d4-e5f6-7890-abcd-ef1234567890", Name: "one"},
+		{UUID: "b1b2c3d4-e5f6-7890-abcd-ef1234567890", Name: "two"},
+	}
+	if err := mgr.BatchCreate(batch); err != nil {
+		t.Fatalf("BatchCreate() error = %v", err)
+	}
+
+	sessions, err := mgr.List()
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(sessions) != 2 {
+		t.Errorf("List() returned %d sessions, want 2", len(sessions))
+	}
+}
+
+func TestBatchCreateIsAtomic(t *testing.T) {
+	mgr := setupTestManager(t)
+
+	// The second item reuses the first item's name within the same batch.
+	batch := []*Metadata{
+		{UUID: "a1b2c3d4-e5f6-7890-abcd-ef1234567890", Name: "dup"},
+		{UUID: "b1b2c3d4-e5f6-7890-abcd-ef1234567890", Name: "dup"},
+	}
+	err := mgr.BatchCreate(batch)
+	if !errors.Is(err, ErrNameConflict) {
+		t.Fatalf("BatchCreate() error = %v, want ErrNameConflict", err)
+	}
+
+	if _, getErr := mgr.GetByName("dup"); !errors.Is(getErr, ErrSessionNotFound) {
+		t.Errorf("GetByName() after failed batch error = %v, want ErrSessionNotFound", getErr)
+	}
+}
+
+func TestBatchUpdate(t *testing.T) {
+	mgr := setupTestManager(t)
+
+	uuidA := "a1b2c3d4-e5f6-7890-abcd-ef1234567890"
+	uuidB := "b1b2c3d4-e5f6-7890-abcd-ef1234567890"
+	if err := mgr.BatchCreate([]*Metadata{{UUID: uuidA, Name: "a"}, {UUID: uuidB, Name: "b"}}); err != nil {
+		t.Fatalf("BatchCreate() error = %v", err)
+	}
+
+	// Swapping names fails on the first item, so nothing changes.
+	err := mgr.BatchUpdate([]*Metadata{{UUID: uuidA, Name: "b"}, {UUID: uuidB, Name: "a"}})
+	if !errors.Is(err, ErrNameConflict) {
+		t.Fatalf("BatchUpdate(swap) error = %v, want ErrNameConflict", err)
+	}
+
+	if err := mgr.BatchUpdate([]*Metadata{
+		{UUID: uuidA, Name: "renamed-a", Description: "first"},
+		{UUID: uuidB, Name: "b", Description: "second"},
+	}); err != nil {
+		t.Fatalf("BatchUpdate() error = %v", err)
+	}
+
+	got, err := mgr.GetByName("renamed-a")
+	if err != nil {
+		t.Fatalf("GetByName() error = %v", err)
+	}
+	if got.UUID != uuidA || got.Description != "first" {
+		t.Errorf("GetByName(renamed-a) = %+v", got)
+	}
+}
+
+func TestWithTx(t *testing.T) {
+	mgr := setupTestManager(t)
+
+	uuidA := "a1b2c3d4-e5f6-7890-abcd-ef1234567890"
+	uuidB := "b1b2c3d4-e5f6-7890-abcd-ef1234567890"
+	if err := mgr.BatchCreate([]*Metadata{{UUID: uuidA, Name: "a"}, {UUID: uuidB, Name: "b"}}); err != nil {
+		t.Fatalf("BatchCreate() error = %v", err)
+	}
+
+	// Swapping names works inside one transaction via a temporary name,
+	// and reads see the uncommitted changes.
+	err := mgr.WithTx(func(tx Tx) error {
+		if err := tx.SetName(uuidA, "tmp"); err != nil {
+			return err
+		}
+		if err := tx.SetName(uuidB, "a"); err != nil {
+			return err
+		}
+		if md, err := tx.GetByName("a"); err != nil || md.UUID != uuidB {
+			t.Errorf("tx.GetByName(a) = %v, %v; want %s", md, err, uuidB)
+		}
+		return tx.SetName(uuidA, "b")
+	})
+	if err != nil {
+		t.Fatalf("WithTx() error = %v", err)
+	}
+
+	if md, err := mgr.GetByName("b"); err != nil || md.UUID != uuidA {
+		t.Errorf("GetByName(b) = %v, %v; want %s", md, err, uuidA)
+	}
+
+	// An error from fn rolls back every change.
+	rollback := errors.New("rollback")
+	err = mgr.WithTx(func(tx Tx) error {
+		if err := tx.Delete(uuidA); err != nil {
+			return err
+		}
+		return rollback
+	})
+	if !errors.Is(err, rollback) {
+		t.Fatalf("WithTx() error = %v, want rollback", err)
+	}
+	if _, err := mgr.GetByUUID(uuidA); err != nil {
+		t.Errorf("GetByUUID() after rollback error = %v", err)
+	}
+}
+
 func setupTestManager(t *testing.T) Manager {
 	t.Helper()
 
@@ -620,3 +942,159 @@ func setupTestManager(t *testing.T) Manager {
 
 	return mgr
 }
+
+func TestValidateName(t *testing.T) {
+	tests := []struct {
+		name    string
+		strict  bool
+		wantErr error
+	}{
+		{"api-work_2.0", true, nil},
+		{"cafe", true, nil},
+		{"2025-11-01", true, nil},
+		{"", true, ErrEmptyName},
+		{"   ", false, ErrEmptyName},
+		{"all", false, ErrInvalidName},
+		{"Current", false, ErrInvalidName},
+		{"a1b2c3d4", false, ErrInvalidName},
+		{"a1b2c3d4-e5f6", false, ErrInvalidName},
+		{" padded", false, ErrInvalidName},
+		{"tab\there", false, ErrInvalidName},
+		{strings.Repeat("x", MaxNameLength+1), false, ErrInvalidName},
+		{"feature/login", true, ErrInvalidName},
+		{"-leading", true, ErrInvalidName},
+		{"🚀 launch", true, ErrInvalidName},
+		{"feature/login", false, nil},
+		{"🚀 launch", false, nil},
+	}
+
+	for _, tt := range tests {
+		err := ValidateName(tt.name, tt.strict)
+		if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+			t.Errorf("ValidateName(%q, strict=%v) = %v, want %v", tt.name, tt.strict, err, tt.wantErr)
+		}
+	}
+}
+
+func TestNameValidationMode(t *testing.T) {
+	uuid := "a1b2c3d4-e5f6-7890-abcd-ef1234567890"
+
+	mgr := setupTestManager(t)
+	if err := mgr.Create(&Metadata{UUID: uuid, Name: "feature/login"}); !errors.Is(err, ErrInvalidName) {
+		t.Errorf("strict Create() error = %v, want ErrInvalidName", err)
+	}
+
+	relaxed, err := New(Config{
+		DBPath:         filepath.Join(t.TempDir(), "test.db"),
+		NameValidation: NameValidationRelaxed,
+	}, logger.Noop())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	t.Cleanup(func() { _ = relaxed.Close() })
+
+	if err := relaxed.Create(&Metadata{UUID: uuid, Name: "feature/login"}); err != nil {
+		t.Errorf("relaxed Create() error = %v", err)
+	}
+
+	if _, err := New(Config{DBPath: filepath.Join(t.TempDir(), "x.db"), NameValidation: "loose"}, logger.Noop()); err == nil {
+		t.Error("New() with unknown mode expected error")
+	}
+}
+
+func TestCaseInsensitiveNames(t *testing.T) {
+	// Relaxed validation allows spaces in names.
+	mgr, err := New(Config{
+		DBPath:         filepath.Join(t.TempDir(), "test.db"),
+		NameValidation: NameValidationRelaxed,
+	}, logger.Noop())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	t.Cleanup(func() { _ = mgr.Close() })
+
+	uuidA := "a1b2c3d4-e5f6-7890-abcd-ef1234567890"
+	uuidB := "b1b2c3d4-e5f6-7890-abcd-ef1234567890"
+	if err := mgr.Create(&Metadata{UUID: uuidA, Name: "My  Project"}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	got, err := mgr.GetByName("my project")
+	if err != nil {
+		t.Fatalf("GetByName() error = %v", err)
+	}
+	// Whitespace is normalized; display casing is preserved.
+	if got.UUID != uuidA || got.Name != "My Project" {
+		t.Errorf("GetByName() = %s %q, want %s %q", got.UUID, got.Name, uuidA, "My Project")
+	}
+
+	if err := mgr.Create(&Metadata{UUID: uuidB, Name: "MY PROJECT"}); !errors.Is(err, ErrNameConflict) {
+		t.Errorf("Create(case variant) error = %v, want ErrNameConflict", err)
+	}
+
+	// Changing only the casing of a session's own name is allowed.
+	if err := mgr.SetName(uuidA, "my project"); err != nil {
+		t.Fatalf("SetName(case change) error = %v", err)
+	}
+	if got, err = mgr.GetByName("MY PROJECT"); err != nil || got.Name != "my project" {
+		t.Errorf("GetByName() after recase = %v, %v", got, err)
+	}
+}
+
+func TestNameNormalizationLower(t *testing.T) {
+	mgr, err := New(Config{
+		DBPath:            filepath.Join(t.TempDir(), "test.db"),
+		NameNormalization: NameNormalizeLower,
+	}, logger.Noop())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	t.Cleanup(func() { _ = mgr.Close() })
+
+	md := &Metadata{UUID: "a1b2c3d4-e5f6-7890-abcd-ef1234567890", Name: "API-Work"}
+	if err := mgr.Create(md); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if md.Name != "api-work" {
+		t.Errorf("stored name = %q, want api-work", md.Name)
+	}
+}
+
+func TestLegacyNamesIndexMigration(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+
+	// Write a database in the pre-folding layout: raw-name index keys
+	// and no schema marker.
+	db, err := bolt.Open(dbPath, 0600, nil)
+	if err != nil {
+		t.Fatalf("bolt.Open() error = %v", err)
+	}
+	uuid := "a1b2c3d4-e5f6-7890-abcd-ef1234567890"
+	err = db.Update(func(tx *bolt.Tx) error {
+		sessions, _ := tx.CreateBucketIfNotExists(bucketSessions) //nolint:errcheck // test setup
+		names, _ := tx.CreateBucketIfNotExists(bucketNames)       //nolint:errcheck // test setup
+		if err := sessions.Put([]byte(uuid), []byte(`{"uuid":"`+uuid+`","name":"Legacy Name"}`)); err != nil {
+			return err
+		}
+		return names.Put([]byte("Legacy Name"), []byte(uuid))
+	})
+	if err != nil {
+		t.Fatalf("setup error = %v", err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	mgr, err := New(Config{DBPath: dbPath}, logger.Noop())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	t.Cleanup(func() { _ = mgr.Close() })
+
+	if got, err := mgr.GetByName("legacy name"); err != nil || got.UUID != uuid {
+		t.Errorf("GetByName() after migration = %v, %v", got, err)
+	}
+	if issues, err := mgr.CheckIndex(false); err != nil || len(issues) != 0 {
+		t.Errorf("CheckIndex() after migration = %v, %v; want clean", issues, err)
+	}
+}
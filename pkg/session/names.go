@@ -0,0 +1,141 @@
+package session
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Name validation modes for Config.NameValidation.
+const (
+	// NameValidationStrict allows only letters, digits, '.', '_' and '-',
+	// starting with a letter or digit. This is the default.
+	NameValidationStrict = "strict"
+
+	// NameValidationRelaxed allows any printable characters.
+	NameValidationRelaxed = "relaxed"
+)
+
+// Name normalization modes for Config.NameNormalization. Lookups are
+// case-insensitive and ignore spacing differences in every mode.
+const (
+	// NameNormalizeTrim trims names and collapses internal whitespace,
+	// keeping the user's casing. This is the default.
+	NameNormalizeTrim = "trim"
+
+	// NameNormalizeLower additionally stores names in lower case.
+	NameNormalizeLower = "lower"
+
+	// NameNormalizeNone stores names exactly as given.
+	NameNormalizeNone = "none"
+)
+
+// MaxNameLength is the maximum session name length in characters.
+const MaxNameLength = 64
+
+// reservedNames are names with special meaning in commands and filters.
+var reservedNames = map[string]bool{
+	"all":     true,
+	"current": true,
+	"latest":  true,
+	"none":    true,
+}
+
+// ValidateName checks a session name against the naming rules.
+//
+// All modes reject empty names, names longer than MaxNameLength, names with
+// control characters or surrounding whitespace, reserved words (case-
+// insensitive), and names that look like a session ID prefix. Strict mode
+// additionally restricts the character set.
+//
+// Returns ErrEmptyName or an error wrapping ErrInvalidName.
+func ValidateName(name string, strict bool) error {
+	if strings.TrimSpace(name) == "" {
+		return ErrEmptyName
+	}
+
+	if n := utf8.RuneCountInString(name); n > MaxNameLength {
+		return fmt.Errorf("%w: %d characters exceeds the limit of %d", ErrInvalidName, n, MaxNameLength)
+	}
+
+	if strings.TrimSpace(name) != name {
+		return fmt.Errorf("%w: leading or trailing whitespace", ErrInvalidName)
+	}
+
+	for _, r := range name {
+		if unicode.IsControl(r) {
+			return fmt.Errorf("%w: contains control characters", ErrInvalidName)
+		}
+	}
+
+	if reservedNames[strings.ToLower(name)] {
+		return fmt.Errorf("%w: %q is reserved", ErrInvalidName, name)
+	}
+
+	if looksLikeUUIDPrefix(name) {
+		return fmt.Errorf("%w: %q could be mistaken for a session ID prefix", ErrInvalidName, name)
+	}
+
+	if !strict {
+		return nil
+	}
+
+	for i, r := range name {
+		switch {
+		case r < utf8.RuneSelf && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+		case i > 0 && (r == '.' || r == '_' || r == '-'):
+		default:
+			return fmt.Errorf("%w: %q not allowed (use letters, digits, '.', '_' or '-', starting with a letter or digit)",
+				ErrInvalidName, r)
+		}
+	}
+
+	return nil
+}
+
+// NormalizeName applies a NameNormalize* mode to name.
+func NormalizeName(name, mode string) string {
+	switch mode {
+	case NameNormalizeNone:
+		return name
+	case NameNormalizeLower:
+		return strings.ToLower(strings.Join(strings.Fields(name), " "))
+	default:
+		return strings.Join(strings.Fields(name), " ")
+	}
+}
+
+// foldName returns the names index key for name. Names that differ only
+// in case or whitespace share a key.
+func foldName(name string) []byte {
+	return []byte(strings.ToLower(strings.Join(strings.Fields(name), " ")))
+}
+
+// looksLikeUUIDPrefix reports whether name has the shape of the start of
+// a session UUID (hex digits with dashes at the UUID positions) and contains
+// a digit, so commands resolving partial session IDs could confuse the two.
+// Plain hex words such as "cafe" pass.
+func looksLikeUUIDPrefix(name string) bool {
+	if len(name) > 36 {
+		return false
+	}
+
+	hasDigit := false
+	for i, r := range name {
+		switch i {
+		case 8, 13, 18, 23:
+			if r != '-' {
+				return false
+			}
+			continue
+		}
+		if !isHexDigit(r) {
+			return false
+		}
+		if r >= '0' && r <= '9' {
+			hasDigit = true
+		}
+	}
+	return hasDigit
+}
@@ -0,0 +1,72 @@
+package session
+
+import (
+	"fmt"
+	"time"
+)
+
+// State is the lifecycle state of a session, derived from how long ago it
+// was last active.
+type State string
+
+// Lifecycle states, from most to least recent.
+const (
+	// StateActive is a session written to within the idle threshold.
+	StateActive State = "active"
+
+	// StateIdle is a session quiet for longer than the idle threshold but
+	// not yet the archive threshold.
+	StateIdle State = "idle"
+
+	// StateArchived is a session quiet for longer than the archive
+	// threshold.
+	StateArchived State = "archived"
+)
+
+// States lists the lifecycle states from most to least recent.
+var States = []State{StateActive, StateIdle, StateArchived}
+
+// Rank orders states from most to least recent: active is 0, and unknown
+// states rank after archived.
+func (s State) Rank() int {
+	for i, st := range States {
+		if st == s {
+			return i
+		}
+	}
+	return len(States)
+}
+
+// ParseState parses a state name as accepted by -state filters.
+func ParseState(s string) (State, error) {
+	for _, st := range States {
+		if string(st) == s {
+			return st, nil
+		}
+	}
+	return "", fmt.Errorf("invalid state %q (want active, idle, or archived)", s)
+}
+
+// Thresholds are the quiet periods after which a session becomes idle and
+// then archived.
+type Thresholds struct {
+	IdleAfter    time.Duration
+	ArchiveAfter time.Duration
+}
+
+// StateAt returns the state of a session last active at lastActivity as of
+// now. A zero lastActivity is treated as archived.
+func (t Thresholds) StateAt(lastActivity, now time.Time) State {
+	if lastActivity.IsZero() {
+		return StateArchived
+	}
+	quiet := now.Sub(lastActivity)
+	switch {
+	case quiet > t.ArchiveAfter:
+		return StateArchived
+	case quiet > t.IdleAfter:
+		return StateIdle
+	default:
+		return StateActive
+	}
+}
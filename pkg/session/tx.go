@@ -0,0 +1,331 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/0xmhha/token-monitor/pkg/logger"
+	bolt "go.etcd.io/bbolt"
+)
+
+// txn implements Tx on top of a BoltDB transaction.
+type txn struct {
+	tx            *bolt.Tx
+	logger        logger.Logger
+	strictNames   bool
+	normalization string
+}
+
+// txn wraps a BoltDB transaction for metadata operations.
+func (m *manager) txn(tx *bolt.Tx) *txn {
+	return &txn{
+		tx:            tx,
+		logger:        m.logger,
+		strictNames:   m.config.NameValidation == NameValidationStrict,
+		normalization: m.config.NameNormalization,
+	}
+}
+
+// WithTx implements Manager.WithTx.
+func (m *manager) WithTx(fn func(tx Tx) error) error {
+	return m.db.Update(func(tx *bolt.Tx) error {
+		return fn(m.txn(tx))
+	})
+}
+
+// BatchCreate implements Manager.BatchCreate.
+func (m *manager) BatchCreate(metadata []*Metadata) error {
+	err := m.WithTx(func(tx Tx) error {
+		for i, md := range metadata {
+			if err := tx.Create(md); err != nil {
+				return fmt.Errorf("batch item %d: %w", i, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	m.logger.Info("sessions created", "count", len(metadata))
+	return nil
+}
+
+// BatchUpdate implements Manager.BatchUpdate.
+func (m *manager) BatchUpdate(metadata []*Metadata) error {
+	err := m.WithTx(func(tx Tx) error {
+		for i, md := range metadata {
+			if md == nil {
+				return fmt.Errorf("batch item %d: %w", i, ErrInvalidMetadata)
+			}
+			if err := tx.Update(md.UUID, md); err != nil {
+				return fmt.Errorf("batch item %d: %w", i, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	m.logger.Info("sessions updated", "count", len(metadata))
+	return nil
+}
+
+// Create implements Tx.Create.
+func (t *txn) Create(metadata *Metadata) error {
+	if metadata == nil {
+		return ErrInvalidMetadata
+	}
+
+	// Validate UUID.
+	if !isValidUUID(metadata.UUID) {
+		return ErrInvalidUUID
+	}
+
+	// Normalize and validate name.
+	metadata.Name = NormalizeName(metadata.Name, t.normalization)
+	if err := ValidateName(metadata.Name, t.strictNames); err != nil {
+		return err
+	}
+
+	sessions := t.tx.Bucket(bucketSessions)
+	names := t.tx.Bucket(bucketNames)
+
+	// Check if UUID already exists.
+	if sessions.Get([]byte(metadata.UUID)) != nil {
+		return fmt.Errorf("session %s already exists", metadata.UUID)
+	}
+
+	// Check if name is already taken.
+	if names.Get(foldName(metadata.Name)) != nil {
+		return ErrNameConflict
+	}
+
+	// Set timestamps.
+	now := time.Now()
+	metadata.CreatedAt = now
+	metadata.UpdatedAt = now
+	metadata.Revision = 1
+
+	// Marshal metadata.
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+
+	// Store in sessions bucket.
+	if err := sessions.Put([]byte(metadata.UUID), data); err != nil {
+		return fmt.Errorf("failed to store session: %w", err)
+	}
+
+	// Store in names index.
+	if err := names.Put(foldName(metadata.Name), []byte(metadata.UUID)); err != nil {
+		return fmt.Errorf("failed to store name index: %w", err)
+	}
+
+	t.logger.Info("session created",
+		"uuid", metadata.UUID,
+		"name", metadata.Name)
+
+	return nil
+}
+
+// GetByUUID implements Tx.GetByUUID.
+func (t *txn) GetByUUID(uuid string) (*Metadata, error) {
+	if !isValidUUID(uuid) {
+		return nil, ErrInvalidUUID
+	}
+
+	data := t.tx.Bucket(bucketSessions).Get([]byte(uuid))
+	if data == nil {
+		return nil, ErrSessionNotFound
+	}
+
+	var metadata Metadata
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal metadata: %w", err)
+	}
+
+	return &metadata, nil
+}
+
+// GetByName implements Tx.GetByName.
+func (t *txn) GetByName(name string) (*Metadata, error) {
+	if name == "" {
+		return nil, ErrEmptyName
+	}
+
+	uuid := t.tx.Bucket(bucketNames).Get(foldName(name))
+	if uuid == nil {
+		return nil, ErrSessionNotFound
+	}
+
+	return t.GetByUUID(string(uuid))
+}
+
+// Update implements Tx.Update.
+func (t *txn) Update(uuid string, metadata *Metadata) error {
+	if metadata == nil {
+		return ErrInvalidMetadata
+	}
+
+	if !isValidUUID(uuid) {
+		return ErrInvalidUUID
+	}
+
+	if metadata.Name == "" {
+		return ErrEmptyName
+	}
+
+	sessions := t.tx.Bucket(bucketSessions)
+	names := t.tx.Bucket(bucketNames)
+
+	// Check if session exists.
+	existingData := sessions.Get([]byte(uuid))
+	if existingData == nil {
+		return ErrSessionNotFound
+	}
+
+	// Unmarshal existing metadata.
+	var existing Metadata
+	if err := json.Unmarshal(existingData, &existing); err != nil {
+		return fmt.Errorf("failed to unmarshal existing metadata: %w", err)
+	}
+
+	// Reject updates based on stale data.
+	if metadata.Revision != 0 && metadata.Revision != existing.Revision {
+		return ErrConflict
+	}
+
+	// Check if name changed and if new name is valid and available.
+	metadata.Name = NormalizeName(metadata.Name, t.normalization)
+	if existing.Name != metadata.Name {
+		if err := ValidateName(metadata.Name, t.strictNames); err != nil {
+			return err
+		}
+
+		// Check if new name is taken by another session. A change in case
+		// or spacing only keeps the same index key.
+		newKey := foldName(metadata.Name)
+		if owner := names.Get(newKey); owner != nil && string(owner) != uuid {
+			return ErrNameConflict
+		}
+
+		// Move the index entry to the new key.
+		if err := names.Delete(foldName(existing.Name)); err != nil {
+			return fmt.Errorf("failed to delete old name index: %w", err)
+		}
+		if err := names.Put(newKey, []byte(uuid)); err != nil {
+			return fmt.Errorf("failed to store new name index: %w", err)
+		}
+	}
+
+	// Preserve creation time, update modification time.
+	metadata.UUID = uuid
+	metadata.CreatedAt = existing.CreatedAt
+	metadata.UpdatedAt = time.Now()
+	metadata.Revision = existing.Revision + 1
+
+	// Marshal updated metadata.
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+
+	// Store updated metadata.
+	if err := sessions.Put([]byte(uuid), data); err != nil {
+		return fmt.Errorf("failed to update session: %w", err)
+	}
+
+	t.logger.Info("session updated",
+		"uuid", uuid,
+		"name", metadata.Name)
+
+	return nil
+}
+
+// Delete implements Tx.Delete.
+func (t *txn) Delete(uuid string) error {
+	if !isValidUUID(uuid) {
+		return ErrInvalidUUID
+	}
+
+	sessions := t.tx.Bucket(bucketSessions)
+	names := t.tx.Bucket(bucketNames)
+
+	// Get existing metadata to find name.
+	data := sessions.Get([]byte(uuid))
+	if data == nil {
+		// Session doesn't exist, no error.
+		return nil
+	}
+
+	// Unmarshal to get name.
+	var metadata Metadata
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return fmt.Errorf("failed to unmarshal metadata: %w", err)
+	}
+
+	// Delete from sessions bucket.
+	if err := sessions.Delete([]byte(uuid)); err != nil {
+		return fmt.Errorf("failed to delete session: %w", err)
+	}
+
+	// Delete from names index.
+	if err := names.Delete(foldName(metadata.Name)); err != nil {
+		return fmt.Errorf("failed to delete name index: %w", err)
+	}
+
+	t.logger.Info("session deleted",
+		"uuid", uuid,
+		"name", metadata.Name)
+
+	return nil
+}
+
+// SetName implements Tx.SetName.
+func (t *txn) SetName(uuid, name string) error {
+	if !isValidUUID(uuid) {
+		return ErrInvalidUUID
+	}
+
+	if name == "" {
+		return ErrEmptyName
+	}
+
+	existing, err := t.GetByUUID(uuid)
+	if err != nil {
+		return err
+	}
+
+	existing.Name = name
+	return t.Update(uuid, existing)
+}
+
+// SetState implements Tx.SetState.
+func (t *txn) SetState(uuid string, state State, at time.Time) error {
+	existing, err := t.GetByUUID(uuid)
+	if err != nil {
+		return err
+	}
+	if existing.State == state {
+		return nil
+	}
+
+	// Unlike Update, UpdatedAt is kept: it is the last user edit, and
+	// the state is derived from session activity rather than edits.
+	existing.State = state
+	existing.StateChangedAt = at
+	existing.Revision++
+
+	data, err := json.Marshal(existing)
+	if err != nil {
+		return fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+	if err := t.tx.Bucket(bucketSessions).Put([]byte(uuid), data); err != nil {
+		return fmt.Errorf("failed to update session state: %w", err)
+	}
+	return nil
+}
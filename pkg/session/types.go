@@ -25,6 +25,14 @@
 //	if err := mgr.Create(metadata); err != nil {
 //	    log.Fatal(err)
 //	}
+//
+//	// Rename several sessions atomically
+//	err = mgr.WithTx(func(tx session.Tx) error {
+//	    if err := tx.SetName(uuidA, "api"); err != nil {
+//	        return err
+//	    }
+//	    return tx.SetName(uuidB, "web")
+//	})
 package session
 
 import (
@@ -55,6 +63,18 @@ type Metadata struct {
 
 	// Description is an optional session description.
 	Description string `json:"description,omitempty"`
+
+	// State is the lifecycle state last recorded for the session, empty
+	// until one is recorded.
+	State State `json:"state,omitempty"`
+
+	// StateChangedAt is when State was recorded.
+	StateChangedAt time.Time `json:"state_changed_at,omitempty"`
+
+	// Revision is incremented on every write. Pass the revision that was
+	// read back to Update to detect concurrent modification; zero skips
+	// the check.
+	Revision uint64 `json:"revision,omitempty"`
 }
 
 // Manager provides session metadata CRUD operations.
@@ -63,6 +83,7 @@ type Manager interface {
 	//
 	// Returns error if:
 	//   - UUID is invalid
+	//   - Name breaks the naming rules (see ValidateName)
 	//   - Name is already taken
 	//   - Database operation fails
 	Create(metadata *Metadata) error
@@ -75,7 +96,8 @@ type Manager interface {
 	//   - Error for database failures
 	GetByUUID(uuid string) (*Metadata, error)
 
-	// GetByName retrieves session metadata by name.
+	// GetByName retrieves session metadata by name. Matching ignores case
+	// and differences in whitespace.
 	//
 	// Returns:
 	//   - Metadata if found
@@ -91,6 +113,9 @@ type Manager interface {
 	//
 	// Returns error if:
 	//   - Session not found
+	//   - A changed name breaks the naming rules (see ValidateName)
+	//   - metadata.Revision is non-zero and differs from the stored
+	//     revision (ErrConflict); re-read and retry
 	//   - Name conflicts with another session
 	//   - Database operation fails
 	Update(uuid string, metadata *Metadata) error
@@ -119,10 +144,52 @@ type Manager interface {
 	//
 	// Returns error if:
 	//   - UUID is invalid
+	//   - Name breaks the naming rules (see ValidateName)
 	//   - Name is already taken by another session
 	//   - Database operation fails
 	SetName(uuid, name string) error
 
+	// SetState records a session's lifecycle state and when it changed.
+	// Recording the current state again is a no-op. UpdatedAt is not
+	// changed.
+	//
+	// Returns error if:
+	//   - UUID is invalid
+	//   - Session not found
+	//   - Database operation fails
+	SetState(uuid string, state State, at time.Time) error
+
+	// WithTx runs fn in a single read-write transaction.
+	//
+	// All changes made through tx are committed together when fn returns
+	// nil and discarded when it returns an error. tx must not be used
+	// after fn returns.
+	WithTx(fn func(tx Tx) error) error
+
+	// BatchCreate creates all entries in one transaction.
+	//
+	// Returns error (and creates nothing) if any entry fails validation,
+	// conflicts with an existing session or name, or the database
+	// operation fails. The error identifies the failing item index.
+	BatchCreate(metadata []*Metadata) error
+
+	// BatchUpdate updates all entries, identified by their UUID field,
+	// in one transaction.
+	//
+	// Returns error (and updates nothing) under the same conditions as
+	// Update for any entry.
+	BatchUpdate(metadata []*Metadata) error
+
+	// CheckIndex cross-checks the names index against session metadata.
+	//
+	// Parameters:
+	//   - repair: Remove stale names, restore missing index entries, and
+	//     drop undecodable metadata
+	//
+	// Returns the issues found (marked Repaired when fixed) and an error
+	// for database failures.
+	CheckIndex(repair bool) ([]IndexIssue, error)
+
 	// Close closes the database connection and releases resources.
 	//
 	// Returns error if database cannot be closed cleanly.
@@ -134,6 +201,34 @@ type Manager interface {
 	DB() *bolt.DB
 }
 
+// Tx is a transactional view of the session store passed to
+// Manager.WithTx. Methods behave like their Manager counterparts, but
+// writes become visible to other callers only when the transaction commits.
+type Tx interface {
+	// Create creates a new session metadata entry.
+	Create(metadata *Metadata) error
+
+	// GetByUUID retrieves session metadata by UUID, including
+	// uncommitted changes made in this transaction.
+	GetByUUID(uuid string) (*Metadata, error)
+
+	// GetByName retrieves session metadata by name, including
+	// uncommitted changes made in this transaction.
+	GetByName(name string) (*Metadata, error)
+
+	// Update updates existing session metadata.
+	Update(uuid string, metadata *Metadata) error
+
+	// Delete removes a session metadata entry.
+	Delete(uuid string) error
+
+	// SetName assigns or updates a session's friendly name.
+	SetName(uuid, name string) error
+
+	// SetState records a session's lifecycle state and when it changed.
+	SetState(uuid string, state State, at time.Time) error
+}
+
 // Config contains session manager configuration.
 type Config struct {
 	// DBPath is the BoltDB file path.
@@ -141,4 +236,14 @@ type Config struct {
 
 	// Timeout is the database operation timeout (default: 1 second).
 	Timeout time.Duration
+
+	// NameValidation selects the naming rules applied when a name is
+	// assigned: NameValidationStrict (default) or NameValidationRelaxed.
+	// Existing names are not re-validated.
+	NameValidation string
+
+	// NameNormalization selects how names are rewritten before they are
+	// stored: NameNormalizeTrim (default), NameNormalizeLower, or
+	// NameNormalizeNone. Lookups are case-insensitive regardless.
+	NameNormalization string
 }
@@ -29,8 +29,9 @@ type Logger interface {
 type ReaderFactory func() (reader.Reader, error)
 
 // LoadEntries reads usage entries from each of the given session files
-// using a fresh reader. Per-session read failures are logged and skipped;
-// the caller receives the union of successful reads.
+// using a fresh reader. Each entry's Source is set to its file's label,
+// attributing usage to a user or profile. Per-session read failures are
+// logged and skipped; the caller receives the union of successful reads.
 //
 // ctx is forwarded to reader.ReadFrom, allowing cancellation to short-
 // circuit a long discovery+read cycle.
@@ -53,6 +54,9 @@ func LoadEntries(
 			log.Warn("failed to read session", "session", sess.SessionID, "error", readErr)
 			continue
 		}
+		for i := range entries {
+			entries[i].Source = sess.Source
+		}
 		all = append(all, entries...)
 	}
 	return all, nil
@@ -40,6 +40,10 @@ func (r *fakeReader) ReadFrom(_ context.Context, path string, _ int64) ([]parser
 	return r.byPath[path], 0, nil
 }
 
+func (r *fakeReader) ReadTail(_ context.Context, path string, _ int) ([]parser.UsageEntry, error) {
+	return r.byPath[path], r.errs[path]
+}
+
 func (r *fakeReader) Reset(_ string) error { return nil }
 
 func (r *fakeReader) Close() error {
@@ -73,8 +77,8 @@ func TestLoadEntries_MergesAcrossSessions(t *testing.T) {
 
 	now := time.Now()
 	sessions := []discovery.SessionFile{
-		{SessionID: "a", FilePath: "/tmp/a.jsonl"},
-		{SessionID: "b", FilePath: "/tmp/b.jsonl"},
+		{SessionID: "a", FilePath: "/tmp/a.jsonl", Source: "alice"},
+		{SessionID: "b", FilePath: "/tmp/b.jsonl", Source: "bob"},
 	}
 
 	r := &fakeReader{
@@ -102,6 +106,11 @@ func TestLoadEntries_MergesAcrossSessions(t *testing.T) {
 	if len(entries) != 3 {
 		t.Errorf("entries = %d, want 3", len(entries))
 	}
+	for _, e := range entries {
+		if want := map[string]string{"a": "alice", "b": "bob"}[e.SessionID]; e.Source != want {
+			t.Errorf("session %s Source = %q, want %q", e.SessionID, e.Source, want)
+		}
+	}
 	if !r.closed {
 		t.Error("expected reader Close() called via defer")
 	}
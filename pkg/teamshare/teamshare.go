@@ -0,0 +1,285 @@
+// Package teamshare exchanges aggregate usage between the token-monitor
+// instances of a team.
+//
+// Sharing is opt-in. Each member's instance periodically pushes a Report
+// of daily totals (requests, tokens, API-equivalent cost, and session
+// count) to a team server, another token-monitor running serve -http,
+// which merges the reports in a Store for team reporting. A report never
+// contains messages, session IDs, project paths, or models; the member
+// name is whatever the member configured.
+//
+// Example usage:
+//
+//	rows, err := store.Rows(from, to)
+//	report := teamshare.Build("alice", rows, version, time.Now())
+//	err = teamshare.Send(ctx, client, endpoint, token, report)
+//
+//	// On the team server:
+//	shared, err := teamshare.Open(path)
+//	err = shared.Merge(report, time.Now())
+//	usage := shared.Usage(since)
+package teamshare
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/0xmhha/token-monitor/pkg/rollup"
+)
+
+// StoreFile is the name of the team server's store in the cache directory.
+const StoreFile = "team-share.json"
+
+// Path is the endpoint path of the team server, relative to its base URL.
+const Path = "/team/usage"
+
+// DefaultInterval is the default time between two pushes.
+const DefaultInterval = time.Hour
+
+// ReportDays is how many days, including today, a report covers. Each
+// push resends them, so a missed push or a late ingest is corrected by
+// the next one.
+const ReportDays = 35
+
+// maxMemberLength bounds member names accepted by the team server.
+const maxMemberLength = 64
+
+// ErrInvalidReport is returned when a pushed report is malformed.
+var ErrInvalidReport = errors.New("invalid team usage report")
+
+// Day is one member's aggregate usage on one day.
+type Day struct {
+	// Date is the day, YYYY-MM-DD in the member's time zone.
+	Date string `json:"date"`
+
+	// Sessions is the number of sessions with usage that day.
+	Sessions int `json:"sessions"`
+
+	rollup.Totals
+}
+
+// Report is the payload a member pushes to the team server.
+type Report struct {
+	// Member names the member on the team server.
+	Member string `json:"member"`
+
+	// Version is the member's token-monitor version.
+	Version string `json:"version,omitempty"`
+
+	// Generated is when the report was built.
+	Generated time.Time `json:"generated"`
+
+	// Days holds one entry per day with usage, oldest first.
+	Days []Day `json:"days"`
+}
+
+// Build returns the report for rollup rows, summed per day.
+func Build(member string, rows []rollup.Row, version string, now time.Time) Report {
+	days := make(map[string]*Day)
+	sessions := make(map[string]map[string]bool)
+	for _, row := range rows {
+		d := days[row.Date]
+		if d == nil {
+			d = &Day{Date: row.Date}
+			days[row.Date] = d
+			sessions[row.Date] = make(map[string]bool)
+		}
+		d.Totals.Merge(row.Totals)
+		if row.SessionID != "" {
+			sessions[row.Date][row.SessionID] = true
+		}
+	}
+
+	r := Report{Member: member, Version: version, Generated: now, Days: make([]Day, 0, len(days))}
+	for date, d := range days {
+		d.Sessions = len(sessions[date])
+		r.Days = append(r.Days, *d)
+	}
+	sort.Slice(r.Days, func(i, j int) bool { return r.Days[i].Date < r.Days[j].Date })
+	return r
+}
+
+// Validate checks that the report names a member and holds well-formed,
+// non-negative days.
+func (r Report) Validate() error {
+	member := strings.TrimSpace(r.Member)
+	if member == "" || member != r.Member || len(member) > maxMemberLength {
+		return fmt.Errorf("%w: member %q must be 1 to %d characters without surrounding spaces",
+			ErrInvalidReport, r.Member, maxMemberLength)
+	}
+	if len(r.Days) > ReportDays {
+		return fmt.Errorf("%w: %d days, at most %d", ErrInvalidReport, len(r.Days), ReportDays)
+	}
+	for _, d := range r.Days {
+		if _, err := time.Parse(rollup.DateLayout, d.Date); err != nil {
+			return fmt.Errorf("%w: date %q must be YYYY-MM-DD", ErrInvalidReport, d.Date)
+		}
+		t := d.Totals
+		if d.Sessions < 0 || t.Entries < 0 || t.InputTokens < 0 || t.OutputTokens < 0 ||
+			t.CacheCreationTokens < 0 || t.CacheReadTokens < 0 || t.CostUSD < 0 {
+			return fmt.Errorf("%w: negative usage on %s", ErrInvalidReport, d.Date)
+		}
+	}
+	return nil
+}
+
+// Send posts r as JSON to the team server at baseURL, with token as the
+// bearer token if set. Any non-2xx response is an error.
+func Send(ctx context.Context, client *http.Client, baseURL, token string, r Report) error {
+	body, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("failed to encode team usage report: %w", err)
+	}
+	endpoint := strings.TrimSuffix(baseURL, "/") + Path
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create team usage request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send team usage report: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close() //nolint:errcheck // body fully read or discarded
+	}()
+	detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512)) //nolint:errcheck // best effort detail
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("team server returned %s: %s", resp.Status, strings.TrimSpace(string(detail)))
+	}
+	return nil
+}
+
+// member is the merged state of one member in the store.
+type member struct {
+	// Received is when the member's last report arrived.
+	Received time.Time `json:"received"`
+
+	// Version is the member's token-monitor version at that report.
+	Version string `json:"version,omitempty"`
+
+	// Days maps dates to the latest usage reported for them.
+	Days map[string]Day `json:"days"`
+}
+
+// MemberUsage is one member's merged usage since a date.
+type MemberUsage struct {
+	// Member names the member.
+	Member string `json:"member"`
+
+	// Received is when the member's last report arrived.
+	Received time.Time `json:"received"`
+
+	// Sessions sums the daily session counts; a session spanning
+	// midnight counts once per day.
+	Sessions int `json:"sessions"`
+
+	rollup.Totals
+}
+
+// Store holds the reports merged by the team server in a JSON file.
+//
+// Thread-safety: Safe for concurrent use within one process.
+type Store struct {
+	mu      sync.Mutex
+	path    string
+	members map[string]*member
+}
+
+// Open reads the store at path. A missing file yields an empty store.
+func Open(path string) (*Store, error) {
+	s := &Store{path: path, members: make(map[string]*member)}
+	data, err := os.ReadFile(path) //nolint:gosec // path is the configured cache directory
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read team usage store: %w", err)
+	}
+	if err := json.Unmarshal(data, &s.members); err != nil {
+		return nil, fmt.Errorf("invalid team usage store %s: %w", path, err)
+	}
+	return s, nil
+}
+
+// Merge validates r and stores its days, replacing the days the member
+// reported before. Older days missing from r are kept. The store is
+// saved before Merge returns.
+func (s *Store) Merge(r Report, now time.Time) error {
+	if err := r.Validate(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m := s.members[r.Member]
+	if m == nil {
+		m = &member{Days: make(map[string]Day)}
+		s.members[r.Member] = m
+	}
+	m.Received, m.Version = now, r.Version
+	for _, d := range r.Days {
+		m.Days[d.Date] = d
+	}
+	return s.save()
+}
+
+// save writes the store atomically, creating its directory if needed.
+// The caller must hold mu.
+func (s *Store) save() error {
+	data, err := json.Marshal(s.members)
+	if err != nil {
+		return fmt.Errorf("failed to encode team usage store: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0750); err != nil {
+		return fmt.Errorf("failed to create team usage directory: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write team usage store: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("failed to write team usage store: %w", err)
+	}
+	return nil
+}
+
+// Usage returns each member's usage on or after since (YYYY-MM-DD; empty
+// for all days), sorted by member. Members without usage in the period
+// are included with zero totals.
+func (s *Store) Usage(since string) []MemberUsage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	usage := make([]MemberUsage, 0, len(s.members))
+	for name, m := range s.members {
+		u := MemberUsage{Member: name, Received: m.Received}
+		for date, d := range m.Days {
+			if date < since {
+				continue
+			}
+			u.Sessions += d.Sessions
+			u.Totals.Merge(d.Totals)
+		}
+		usage = append(usage, u)
+	}
+	sort.Slice(usage, func(i, j int) bool { return usage[i].Member < usage[j].Member })
+	return usage
+}
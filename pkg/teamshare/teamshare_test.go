@@ -0,0 +1,149 @@
+package teamshare
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/0xmhha/token-monitor/pkg/rollup"
+)
+
+func row(date, session string, tokens int, cost float64) rollup.Row {
+	return rollup.Row{
+		Key:    rollup.Key{Date: date, Model: "claude-sonnet-4", SessionID: session},
+		Totals: rollup.Totals{Entries: 1, InputTokens: tokens, CostUSD: cost},
+	}
+}
+
+func TestBuild(t *testing.T) {
+	now := time.Date(2025, 11, 3, 12, 0, 0, 0, time.UTC)
+	rows := []rollup.Row{
+		row("2025-11-02", "b", 300, 0.3),
+		row("2025-11-01", "a", 100, 0.1),
+		row("2025-11-01", "a", 50, 0.05),
+		row("2025-11-01", "b", 10, 0.01),
+	}
+
+	r := Build("alice", rows, "1.0.0", now)
+	if r.Member != "alice" || r.Version != "1.0.0" || !r.Generated.Equal(now) {
+		t.Errorf("Build() header = %+v", r)
+	}
+	if len(r.Days) != 2 {
+		t.Fatalf("Build() days = %+v, want 2", r.Days)
+	}
+	first := r.Days[0]
+	if first.Date != "2025-11-01" || first.Sessions != 2 || first.Entries != 3 || first.InputTokens != 160 {
+		t.Errorf("first day = %+v, want 2025-11-01 with 2 sessions, 3 requests, 160 tokens", first)
+	}
+
+	// Only aggregate fields leave the machine.
+	data, err := json.Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatal(err)
+	}
+	day := fields["days"].([]any)[0].(map[string]any)
+	for _, key := range []string{"session_id", "model"} {
+		if _, ok := day[key]; ok {
+			t.Errorf("report day contains %q", key)
+		}
+	}
+}
+
+func TestReportValidate(t *testing.T) {
+	valid := Report{Member: "alice", Days: []Day{{Date: "2025-11-01"}}}
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("Validate() = %v", err)
+	}
+
+	tests := map[string]Report{
+		"no member":     {Days: valid.Days},
+		"padded member": {Member: " alice", Days: valid.Days},
+		"bad date":      {Member: "alice", Days: []Day{{Date: "11/01/2025"}}},
+		"negative":      {Member: "alice", Days: []Day{{Date: "2025-11-01", Totals: rollup.Totals{InputTokens: -1}}}},
+		"too many days": {Member: "alice", Days: make([]Day, ReportDays+1)},
+	}
+	for name, r := range tests {
+		if err := r.Validate(); !errors.Is(err, ErrInvalidReport) {
+			t.Errorf("%s: Validate() = %v, want ErrInvalidReport", name, err)
+		}
+	}
+}
+
+func TestStoreMerge(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache", StoreFile)
+	now := time.Date(2025, 11, 3, 12, 0, 0, 0, time.UTC)
+
+	s, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Merge(Build("alice", []rollup.Row{row("2025-11-01", "a", 100, 1), row("2025-11-02", "a", 200, 2)}, "", now), now); err != nil {
+		t.Fatal(err)
+	}
+	// A later push replaces the days it covers and keeps older ones.
+	if err := s.Merge(Build("alice", []rollup.Row{row("2025-11-02", "a", 250, 2.5)}, "", now), now); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Merge(Build("bob", []rollup.Row{row("2025-10-01", "b", 999, 9)}, "", now), now); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Merge(Report{}, now); !errors.Is(err, ErrInvalidReport) {
+		t.Errorf("Merge(invalid) = %v, want ErrInvalidReport", err)
+	}
+
+	reopened, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	usage := reopened.Usage("2025-11-01")
+	if len(usage) != 2 {
+		t.Fatalf("Usage() = %+v, want alice and bob", usage)
+	}
+	alice, bob := usage[0], usage[1]
+	if alice.Member != "alice" || alice.InputTokens != 350 || alice.Sessions != 2 || !alice.Received.Equal(now) {
+		t.Errorf("alice = %+v, want 350 tokens over 2 session-days", alice)
+	}
+	if bob.Member != "bob" || bob.TotalTokens() != 0 {
+		t.Errorf("bob = %+v, want no usage since 2025-11-01", bob)
+	}
+	if all := reopened.Usage(""); all[1].InputTokens != 999 {
+		t.Errorf("Usage(\"\") bob = %+v, want all days", all[1])
+	}
+}
+
+func TestSend(t *testing.T) {
+	var got Report
+	var auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != Path || r.Method != http.MethodPost {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		auth = r.Header.Get("Authorization")
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+
+	r := Report{Member: "alice", Days: []Day{{Date: "2025-11-01", Sessions: 1}}}
+	if err := Send(context.Background(), srv.Client(), srv.URL+"/", "secret", r); err != nil {
+		t.Fatalf("Send() = %v", err)
+	}
+	if got.Member != "alice" || len(got.Days) != 1 || auth != "Bearer secret" {
+		t.Errorf("server received %+v with %q", got, auth)
+	}
+
+	if err := Send(context.Background(), srv.Client(), srv.URL+"/prefix", "", r); err == nil {
+		t.Error("Send() to a missing endpoint succeeded")
+	}
+}
@@ -0,0 +1,201 @@
+// Package telemetry collects the opt-in performance report about
+// token-monitor itself.
+//
+// Telemetry is off unless the user enables it. A report contains only
+// the tool version, the platform, and how long each command took. It
+// never contains token usage, costs, session names, project paths, or
+// anything else identifying the user or their work. Timings are
+// aggregated per command in a local state file and sent at most once
+// per SendInterval.
+//
+// Example usage:
+//
+//	state, err := telemetry.LoadState(path)
+//	state.Record("stats", elapsed)
+//	if endpoint != "" && state.Due(time.Now()) {
+//	    if err := telemetry.Send(ctx, client, endpoint, state.Report(version)); err == nil {
+//	        state.MarkSent(time.Now())
+//	    }
+//	}
+//	err = state.Save(path)
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"time"
+)
+
+// StateFile is the name of the state file in the cache directory.
+const StateFile = "telemetry.json"
+
+// SendInterval is the minimum time between two reports.
+const SendInterval = 24 * time.Hour
+
+// Timing summarizes the runs of one command.
+type Timing struct {
+	// Command is the built-in command name, e.g. "stats".
+	Command string `json:"command"`
+
+	// Count is the number of runs.
+	Count int `json:"count"`
+
+	// MeanMs is the mean run time in milliseconds.
+	MeanMs int64 `json:"mean_ms"`
+
+	// MaxMs is the longest run time in milliseconds.
+	MaxMs int64 `json:"max_ms"`
+}
+
+// Report is the payload sent to the telemetry endpoint.
+type Report struct {
+	// Version is the token-monitor version.
+	Version string `json:"version"`
+
+	// OS and Arch are the platform, e.g. linux/amd64.
+	OS   string `json:"os"`
+	Arch string `json:"arch"`
+
+	// GoVersion is the Go release the binary was built with.
+	GoVersion string `json:"go_version"`
+
+	// Timings holds one entry per command, sorted by name.
+	Timings []Timing `json:"timings"`
+}
+
+// commandTiming accumulates the runs of one command.
+type commandTiming struct {
+	Count int           `json:"count"`
+	Total time.Duration `json:"total"`
+	Max   time.Duration `json:"max"`
+}
+
+// State holds the timings recorded since the last report.
+//
+// Thread-safety: Not safe for concurrent use. Processes running at the
+// same time may overwrite each other's timings, which only loses samples.
+type State struct {
+	// Commands maps command names to their accumulated timings.
+	Commands map[string]commandTiming `json:"commands,omitempty"`
+
+	// LastSent is when the last report was accepted by the endpoint.
+	LastSent time.Time `json:"last_sent,omitempty"`
+}
+
+// LoadState reads the state at path. A missing file yields an empty state.
+func LoadState(path string) (State, error) {
+	var s State
+	data, err := os.ReadFile(path) //nolint:gosec // path is the configured cache directory
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return s, fmt.Errorf("failed to read telemetry state: %w", err)
+	}
+	if err := json.Unmarshal(data, &s); err != nil {
+		return State{}, fmt.Errorf("invalid telemetry state %s: %w", path, err)
+	}
+	return s, nil
+}
+
+// Save writes the state to path, creating its directory if needed.
+func (s State) Save(path string) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("failed to encode telemetry state: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return fmt.Errorf("failed to create telemetry directory: %w", err)
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// Record adds one run of command taking d.
+func (s *State) Record(command string, d time.Duration) {
+	if s.Commands == nil {
+		s.Commands = make(map[string]commandTiming)
+	}
+	t := s.Commands[command]
+	t.Count++
+	t.Total += d
+	if d > t.Max {
+		t.Max = d
+	}
+	s.Commands[command] = t
+}
+
+// Due reports whether there are timings and SendInterval has passed
+// since the last report.
+func (s State) Due(now time.Time) bool {
+	return len(s.Commands) > 0 && now.Sub(s.LastSent) >= SendInterval
+}
+
+// MarkSent clears the reported timings and records the send time.
+func (s *State) MarkSent(now time.Time) {
+	s.Commands = nil
+	s.LastSent = now
+}
+
+// Report returns the report for the recorded timings.
+func (s State) Report(version string) Report {
+	r := Report{
+		Version:   version,
+		OS:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+		GoVersion: runtime.Version(),
+		Timings:   make([]Timing, 0, len(s.Commands)),
+	}
+	for command, t := range s.Commands {
+		r.Timings = append(r.Timings, Timing{
+			Command: command,
+			Count:   t.Count,
+			MeanMs:  (t.Total / time.Duration(t.Count)).Milliseconds(),
+			MaxMs:   t.Max.Milliseconds(),
+		})
+	}
+	sort.Slice(r.Timings, func(i, j int) bool { return r.Timings[i].Command < r.Timings[j].Command })
+	return r
+}
+
+// Send posts r as JSON to endpoint. Any non-2xx response is an error.
+func Send(ctx context.Context, client *http.Client, endpoint string, r Report) error {
+	body, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("failed to encode telemetry report: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create telemetry request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send telemetry report: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close() //nolint:errcheck // body fully read or discarded
+	}()
+	_, _ = io.Copy(io.Discard, resp.Body) //nolint:errcheck // drain for connection reuse
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("telemetry endpoint returned %s", resp.Status)
+	}
+	return nil
+}
+
+// DoNotTrack reports whether the DO_NOT_TRACK environment variable asks
+// to disable telemetry, overriding the configuration.
+func DoNotTrack() bool {
+	v := os.Getenv("DO_NOT_TRACK")
+	return v != "" && v != "0"
+}
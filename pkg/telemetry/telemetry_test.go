@@ -0,0 +1,93 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStateRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache", StateFile)
+
+	s, err := LoadState(path)
+	if err != nil || len(s.Commands) != 0 {
+		t.Fatalf("LoadState() missing file = %+v, %v; want empty state", s, err)
+	}
+	s.Record("stats", 100*time.Millisecond)
+	s.Record("stats", 300*time.Millisecond)
+	s.Record("report", 50*time.Millisecond)
+	if err := s.Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded, err := LoadState(path)
+	if err != nil {
+		t.Fatalf("LoadState() error = %v", err)
+	}
+	r := loaded.Report("1.2.3")
+	want := []Timing{
+		{Command: "report", Count: 1, MeanMs: 50, MaxMs: 50},
+		{Command: "stats", Count: 2, MeanMs: 200, MaxMs: 300},
+	}
+	if r.Version != "1.2.3" || len(r.Timings) != len(want) {
+		t.Fatalf("Report() = %+v, want version 1.2.3 and %d timings", r, len(want))
+	}
+	for i, w := range want {
+		if r.Timings[i] != w {
+			t.Errorf("Report().Timings[%d] = %+v, want %+v", i, r.Timings[i], w)
+		}
+	}
+}
+
+func TestDue(t *testing.T) {
+	now := time.Date(2025, 12, 1, 12, 0, 0, 0, time.UTC)
+	var s State
+	if s.Due(now) {
+		t.Error("Due() with no timings = true, want false")
+	}
+	s.Record("stats", time.Second)
+	if !s.Due(now) {
+		t.Error("Due() never sent = false, want true")
+	}
+	s.MarkSent(now)
+	if len(s.Commands) != 0 {
+		t.Errorf("MarkSent() kept timings %v", s.Commands)
+	}
+	s.Record("stats", time.Second)
+	if s.Due(now.Add(time.Hour)) || !s.Due(now.Add(SendInterval)) {
+		t.Error("Due() does not wait SendInterval after the last report")
+	}
+}
+
+func TestSend(t *testing.T) {
+	var got Report
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	var s State
+	s.Record("stats", time.Second)
+	if err := Send(context.Background(), srv.Client(), srv.URL, s.Report("dev")); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if got.Version != "dev" || len(got.Timings) != 1 || got.Timings[0].Command != "stats" {
+		t.Errorf("endpoint received %+v", got)
+	}
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+	if err := Send(context.Background(), failing.Client(), failing.URL, s.Report("dev")); err == nil {
+		t.Error("Send() to failing endpoint error = nil, want error")
+	}
+}
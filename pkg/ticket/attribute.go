@@ -0,0 +1,75 @@
+package ticket
+
+import (
+	"sort"
+
+	"github.com/0xmhha/token-monitor/pkg/focus"
+	"github.com/0xmhha/token-monitor/pkg/parser"
+	"github.com/0xmhha/token-monitor/pkg/rollup"
+)
+
+// Usage is the usage attributed to one ticket.
+type Usage struct {
+	// Ticket is the ticket label, empty for usage without a ticket.
+	Ticket string `json:"ticket"`
+
+	// Sessions is the number of distinct sessions contributing usage.
+	Sessions int `json:"sessions"`
+
+	// Totals sums the attributed entries.
+	Totals rollup.Totals `json:"totals"`
+}
+
+// Attribute sums entries per ticket. An entry belongs to the ticket of
+// its session when the session has one, otherwise to the ticket of the
+// focus window it falls in. Entries with neither are summed under the
+// empty ticket. The result is ordered by cost, highest first, with the
+// empty ticket last.
+func Attribute(entries []parser.UsageEntry, sessions map[string]string, windows []focus.Window) []Usage {
+	var ticketed []focus.Window
+	for _, w := range windows {
+		if w.Ticket != "" {
+			ticketed = append(ticketed, w)
+		}
+	}
+	sort.Slice(ticketed, func(i, j int) bool { return ticketed[i].Start.Before(ticketed[j].Start) })
+
+	byTicket := make(map[string]*Usage)
+	seen := make(map[string]map[string]bool)
+	for _, entry := range entries {
+		t, ok := sessions[entry.SessionID]
+		if !ok {
+			i := sort.Search(len(ticketed), func(i int) bool {
+				return ticketed[i].Start.After(entry.Timestamp)
+			}) - 1
+			if i >= 0 && ticketed[i].Contains(entry.Timestamp) {
+				t = ticketed[i].Ticket
+			}
+		}
+
+		u := byTicket[t]
+		if u == nil {
+			u = &Usage{Ticket: t}
+			byTicket[t] = u
+			seen[t] = make(map[string]bool)
+		}
+		u.Totals.AddEntry(entry)
+		seen[t][entry.SessionID] = true
+	}
+
+	result := make([]Usage, 0, len(byTicket))
+	for t, u := range byTicket {
+		u.Sessions = len(seen[t])
+		result = append(result, *u)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if (result[i].Ticket == "") != (result[j].Ticket == "") {
+			return result[j].Ticket == ""
+		}
+		if result[i].Totals.CostUSD != result[j].Totals.CostUSD {
+			return result[i].Totals.CostUSD > result[j].Totals.CostUSD
+		}
+		return result[i].Ticket < result[j].Ticket
+	})
+	return result
+}
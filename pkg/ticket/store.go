@@ -0,0 +1,63 @@
+package ticket
+
+import (
+	"fmt"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// bucketTickets maps session IDs to tickets.
+var bucketTickets = []byte("tickets")
+
+// boltStore implements Store using BoltDB.
+type boltStore struct {
+	db *bolt.DB
+}
+
+// New creates a BoltDB-backed ticket store.
+//
+// Parameters:
+//   - db: BoltDB database instance (typically session.Manager.DB())
+//
+// Returns:
+//   - Configured Store
+//   - Error if bucket initialization fails
+func New(db *bolt.DB) (Store, error) {
+	if err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(bucketTickets)
+		return err
+	}); err != nil {
+		return nil, fmt.Errorf("failed to create ticket bucket: %w", err)
+	}
+
+	return &boltStore{db: db}, nil
+}
+
+// Set implements Store.Set.
+func (s *boltStore) Set(sessionID, ticket string) error {
+	if err := Validate(ticket); err != nil {
+		return fmt.Errorf("%w: %q", err, ticket)
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketTickets).Put([]byte(sessionID), []byte(ticket))
+	})
+}
+
+// Clear implements Store.Clear.
+func (s *boltStore) Clear(sessionID string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketTickets).Delete([]byte(sessionID))
+	})
+}
+
+// Sessions implements Store.Sessions.
+func (s *boltStore) Sessions() (map[string]string, error) {
+	tickets := make(map[string]string)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketTickets).ForEach(func(k, v []byte) error {
+			tickets[string(k)] = string(v)
+			return nil
+		})
+	})
+	return tickets, err
+}
@@ -0,0 +1,77 @@
+package ticket
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/0xmhha/token-monitor/pkg/focus"
+	"github.com/0xmhha/token-monitor/pkg/parser"
+)
+
+func TestStore(t *testing.T) {
+	db, err := bolt.Open(filepath.Join(t.TempDir(), "test.db"), 0600, nil)
+	if err != nil {
+		t.Fatalf("bolt.Open() error = %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	store, err := New(db)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if err := store.Set("s1", "JIRA 123"); !errors.Is(err, ErrInvalid) {
+		t.Errorf("Set() invalid ticket error = %v, want ErrInvalid", err)
+	}
+	for _, tc := range []struct{ session, ticket string }{{"s1", "JIRA-1"}, {"s2", "#42"}, {"s1", "JIRA-2"}} {
+		if err := store.Set(tc.session, tc.ticket); err != nil {
+			t.Fatalf("Set(%s, %s) error = %v", tc.session, tc.ticket, err)
+		}
+	}
+	if err := store.Clear("s2"); err != nil {
+		t.Fatalf("Clear() error = %v", err)
+	}
+
+	got, err := store.Sessions()
+	if err != nil || len(got) != 1 || got["s1"] != "JIRA-2" {
+		t.Errorf("Sessions() = %v, %v; want s1: JIRA-2", got, err)
+	}
+}
+
+func TestAttribute(t *testing.T) {
+	base := time.Date(2025, 11, 30, 9, 0, 0, 0, time.UTC)
+	entry := func(session string, offset time.Duration, tokens int) parser.UsageEntry {
+		return parser.UsageEntry{
+			Timestamp: base.Add(offset),
+			SessionID: session,
+			Message:   parser.Message{Model: "claude-sonnet-4", Usage: parser.Usage{InputTokens: tokens}},
+		}
+	}
+	entries := []parser.UsageEntry{
+		entry("labeled", 30*time.Minute, 1000), // session ticket wins over the window
+		entry("other", 30*time.Minute, 100),    // window ticket
+		entry("other", 2*time.Hour, 10),        // no ticket
+	}
+	sessions := map[string]string{"labeled": "JIRA-1"}
+	windows := []focus.Window{
+		{Label: "x", Ticket: "JIRA-2", Start: base, End: base.Add(time.Hour)},
+		{Label: "y", Start: base.Add(time.Hour)},
+	}
+
+	got := Attribute(entries, sessions, windows)
+	if len(got) != 3 {
+		t.Fatalf("Attribute() = %+v, want 3 tickets", got)
+	}
+	want := []struct {
+		ticket string
+		tokens int
+	}{{"JIRA-1", 1000}, {"JIRA-2", 100}, {"", 10}}
+	for i, w := range want {
+		if got[i].Ticket != w.ticket || got[i].Totals.InputTokens != w.tokens || got[i].Sessions != 1 {
+			t.Errorf("Attribute()[%d] = %+v, want %s with %d tokens", i, got[i], w.ticket, w.tokens)
+		}
+	}
+}
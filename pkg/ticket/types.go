@@ -0,0 +1,58 @@
+// Package ticket attaches ticket or issue labels to sessions and groups
+// token usage by ticket.
+//
+// A ticket is an identifier from an issue tracker, such as "JIRA-123" or
+// "#42". It can be attached to a Claude session, or to a focus window
+// (see package focus) to cover every session that ran during the window.
+// Attribute combines both to report spend per ticket for engineering
+// metrics.
+//
+// Example usage:
+//
+//	store, err := ticket.New(db)
+//	if err != nil {
+//	    return err
+//	}
+//	err = store.Set("a1b2c3d4-...", "JIRA-123")
+//
+//	sessions, err := store.Sessions()
+//	usage := ticket.Attribute(entries, sessions, windows)
+package ticket
+
+import (
+	"errors"
+	"regexp"
+)
+
+// MaxLength is the maximum length of a ticket.
+const MaxLength = 64
+
+// pattern matches valid tickets.
+var pattern = regexp.MustCompile(`^[A-Za-z0-9#][A-Za-z0-9._#/:-]*$`)
+
+// ErrInvalid is returned when a ticket is empty, too long, or contains
+// characters other than letters, digits, '.', '_', '#', '/', ':', and '-'.
+var ErrInvalid = errors.New("invalid ticket")
+
+// Validate checks that ticket is usable as a ticket label.
+func Validate(ticket string) error {
+	if len(ticket) > MaxLength || !pattern.MatchString(ticket) {
+		return ErrInvalid
+	}
+	return nil
+}
+
+// Store persists the tickets attached to sessions.
+//
+// Thread-safety: Implementations must be safe for concurrent use.
+type Store interface {
+	// Set attaches ticket to the session, replacing any previous one.
+	Set(sessionID, ticket string) error
+
+	// Clear removes the ticket of the session. It does not fail when
+	// the session has none.
+	Clear(sessionID string) error
+
+	// Sessions returns the tickets by session ID.
+	Sessions() (map[string]string, error)
+}
@@ -4,6 +4,7 @@ package tui
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -19,6 +20,7 @@ import (
 	"github.com/0xmhha/token-monitor/pkg/monitor"
 	"github.com/0xmhha/token-monitor/pkg/parser"
 	"github.com/0xmhha/token-monitor/pkg/reader"
+	"github.com/0xmhha/token-monitor/pkg/rollup"
 	"github.com/0xmhha/token-monitor/pkg/session"
 	"github.com/0xmhha/token-monitor/pkg/watcher"
 )
@@ -30,22 +32,40 @@ const (
 	TabDashboard Tab = iota
 	TabSessions
 	TabStats
+	TabCalendar
 )
 
-var tabNames = []string{"Dashboard", "Sessions", "Stats"}
+var tabNames = []string{"Dashboard", "Sessions", "Stats", "Calendar"}
+
+// Refresh settings.
+const (
+	// statsInterval is how often the stats tab is rebuilt from all sessions.
+	statsInterval = 30 * time.Second
+
+	// Low-power mode lower bounds for the refresh interval, the idle
+	// re-read backoff, and the stats rebuild (which re-discovers sessions).
+	lowPowerRefresh       = 5 * time.Second
+	lowPowerMaxPoll       = 5 * time.Minute
+	lowPowerStatsInterval = 10 * time.Minute
+)
 
 // Messages
 
 // monitorUpdateMsg carries a live monitor update.
 type monitorUpdateMsg monitor.Update
 
-// sessionsLoadedMsg carries discovered sessions.
-type sessionsLoadedMsg []discovery.SessionFile
+// sessionsLoadedMsg carries discovered sessions and their names.
+type sessionsLoadedMsg struct {
+	sessions []discovery.SessionFile
+	names    map[string]string // by session ID
+}
 
 // statsLoadedMsg carries aggregated statistics.
 type statsLoadedMsg struct {
 	stats       aggregator.Statistics
 	topSessions []aggregator.SessionStats
+	daily       map[string]rollup.Totals // by date, for the calendar
+	tokens      map[string]int           // by session file, for sorting
 }
 
 // sessionDetailLoadedMsg carries stats for a selected session (three-way split).
@@ -58,8 +78,12 @@ type sessionDetailLoadedMsg struct {
 	totalStats   aggregator.Statistics
 	burnRate     aggregator.BurnRate
 	block        aggregator.BillingBlock
+	peakBlock    int
 }
 
+// debugEventMsg carries a watcher event or file read from the monitor.
+type debugEventMsg monitor.DebugEvent
+
 // tickMsg triggers periodic refresh.
 type tickMsg time.Time
 
@@ -73,12 +97,15 @@ type Model struct {
 	// Navigation
 	activeTab Tab
 	showHelp  bool
+	showDebug bool
 	keys      KeyMap
 
 	// Views
 	dashboard dashboardView
 	sessions  sessionsView
 	statsView statsView
+	calendar  calendarView
+	debug     debugView
 
 	// Infrastructure
 	cfg        *config.Config
@@ -89,6 +116,10 @@ type Model struct {
 	mon        monitor.LiveMonitor
 	disc       discovery.Discoverer
 
+	// Refresh settings
+	lowPower      bool          // percentiles off, slower refreshes
+	statsInterval time.Duration // interval between full stats rebuilds
+
 	// State
 	startTime time.Time // TUI start time, used as past/current boundary
 	width     int
@@ -102,10 +133,18 @@ type Options struct {
 	SessionID string
 	Refresh   time.Duration
 	LogLevel  string
+	NoCache   bool // disable the discovery cache
+	Force     bool // ignore the large-file limits
+	LowPower  bool // low-power mode (also enabled by performance.low_power)
+	ASCII     bool // ASCII borders instead of box drawing
 }
 
 // New creates and runs the TUI application.
 func New(opts Options) error {
+	if opts.ASCII {
+		useASCII()
+	}
+
 	m, err := initModel(opts)
 	if err != nil {
 		return fmt.Errorf("failed to initialize TUI: %w", err)
@@ -141,9 +180,21 @@ func initModel(opts Options) (Model, error) {
 		Output: cfg.Logging.Output,
 	})
 
-	sessionMgr, err := session.New(session.Config{
-		DBPath: cfg.Storage.DBPath,
-	}, log)
+	sessionCfg := session.Config{
+		DBPath:            cfg.Storage.DBPath,
+		NameValidation:    cfg.Session.NameValidation,
+		NameNormalization: cfg.Session.NameNormalization,
+	}
+	sessionMgr, err := session.New(sessionCfg, log)
+	if err != nil && !errors.Is(err, session.ErrDatabaseLocked) {
+		// A read-only file system, for example; the TUI only reads.
+		if temp, tempErr := session.NewTemporary(sessionCfg, log); tempErr == nil {
+			log.Warn("session database unavailable, using a temporary one: session names are missing",
+				"db_path", cfg.Storage.DBPath,
+				"error", err)
+			sessionMgr, err = temp, nil
+		}
+	}
 	if err != nil {
 		return Model{}, fmt.Errorf("failed to initialize session manager: %w", err)
 	}
@@ -154,9 +205,20 @@ func initModel(opts Options) (Model, error) {
 		return Model{}, fmt.Errorf("failed to initialize position store: %w", err)
 	}
 
+	maxSize := int64(cfg.Performance.MaxFileSizeMB) * 1024 * 1024
+	maxEntries := cfg.Performance.MaxEntriesPerRead
+	if opts.Force {
+		maxSize, maxEntries = -1, 0
+	}
 	rdr, err := reader.New(reader.Config{
 		PositionStore: positionStore,
-		Parser:        parser.New(),
+		Parser: parser.NewWithConfig(parser.Config{
+			MaxFileSize:      maxSize,
+			MaxEntries:       maxEntries,
+			MissingSessionID: parser.MissingSessionIDPolicy(cfg.Discovery.MissingSessionID),
+			Attribution:      parser.Attribution(cfg.Discovery.SessionAttribution),
+		}),
+		MaxFileSize: maxSize,
 	}, log)
 	if err != nil {
 		sessionMgr.Close() //nolint:errcheck,gosec // best-effort cleanup on init failure
@@ -172,13 +234,32 @@ func initModel(opts Options) (Model, error) {
 		return Model{}, fmt.Errorf("failed to initialize watcher: %w", err)
 	}
 
-	disc := discovery.New(cfg.ClaudeConfigDirs, log)
+	discCfg := discovery.Config{
+		BaseDirs:        cfg.ClaudeDirPaths(),
+		Labels:          cfg.ClaudeDirLabels(),
+		SessionPatterns: cfg.Discovery.SessionPatterns,
+		AnyJSONL:        cfg.Discovery.AnyJSONL,
+		Files:           cfg.Discovery.Files,
+	}
+	if !opts.NoCache {
+		discCfg.CacheDir = cfg.Storage.CacheDir
+	}
+	disc := discovery.NewWithConfig(discCfg, log)
 
 	refresh := opts.Refresh
 	if refresh == 0 {
 		refresh = time.Second
 	}
 
+	lowPower := opts.LowPower || cfg.Performance.LowPower
+	maxPoll := cfg.Monitoring.MaxPollInterval
+	interval := statsInterval
+	if lowPower {
+		refresh = max(refresh, lowPowerRefresh)
+		maxPoll = max(maxPoll, lowPowerMaxPoll)
+		interval = lowPowerStatsInterval
+	}
+
 	var sessionIDs []string
 	if opts.SessionID != "" {
 		sessionIDs = []string{opts.SessionID}
@@ -187,7 +268,13 @@ func initModel(opts Options) (Model, error) {
 	mon, err := monitor.New(monitor.Config{
 		SessionIDs:      sessionIDs,
 		RefreshInterval: refresh,
+		MaxPollInterval: maxPoll,
 		ClearScreen:     false,
+		BatchWindow:     cfg.Performance.BatchWindow,
+		ReadRetryDelay:  cfg.Performance.ReadRetryDelay,
+		ReadRetries:     cfg.Performance.ReadRetries,
+		Workers:         cfg.Performance.WorkerPoolSize,
+		SkipPercentiles: lowPower,
 	}, wtch, rdr, disc, log)
 	if err != nil {
 		wtch.Close()       //nolint:errcheck,gosec // best-effort cleanup on init failure
@@ -197,19 +284,23 @@ func initModel(opts Options) (Model, error) {
 	}
 
 	return Model{
-		activeTab:  TabDashboard,
-		startTime:  time.Now(),
-		keys:       DefaultKeyMap(),
-		dashboard:  newDashboardView(),
-		sessions:   newSessionsView(),
-		statsView:  newStatsView(),
-		cfg:        cfg,
-		log:        log,
-		sessionMgr: sessionMgr,
-		rdr:        rdr,
-		wtch:       wtch,
-		mon:        mon,
-		disc:       disc,
+		activeTab:     TabDashboard,
+		startTime:     time.Now(),
+		keys:          DefaultKeyMap(),
+		dashboard:     newDashboardView(cfg.Monitoring.BlockTokenLimit),
+		sessions:      newSessionsView(cfg.Session.Thresholds()),
+		statsView:     newStatsView(),
+		calendar:      newCalendarView(),
+		debug:         newDebugView(),
+		cfg:           cfg,
+		log:           log,
+		sessionMgr:    sessionMgr,
+		rdr:           rdr,
+		wtch:          wtch,
+		mon:           mon,
+		disc:          disc,
+		lowPower:      lowPower,
+		statsInterval: interval,
 	}, nil
 }
 
@@ -219,6 +310,7 @@ func (m Model) Init() tea.Cmd {
 		m.startMonitor(),
 		m.loadSessions(),
 		m.loadStats(),
+		m.waitForDebugEvent(),
 	)
 }
 
@@ -235,6 +327,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.dashboard.setSize(msg.Width, contentHeight)
 		m.sessions.setSize(msg.Width, contentHeight)
 		m.statsView.setSize(msg.Width, contentHeight)
+		m.calendar.setSize(msg.Width, contentHeight)
+		m.debug.setSize(msg.Width, contentHeight)
 		m.ready = true
 		return m, nil
 
@@ -243,8 +337,12 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.dashboard.update(upd)
 		return m, m.waitForUpdate()
 
+	case debugEventMsg:
+		m.debug.add(monitor.DebugEvent(msg))
+		return m, m.waitForDebugEvent()
+
 	case sessionsLoadedMsg:
-		m.sessions.setSessions([]discovery.SessionFile(msg))
+		m.sessions.setSessions(msg.sessions, msg.names)
 		return m, nil
 
 	case sessionDetailLoadedMsg:
@@ -257,6 +355,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			totalStats:   msg.totalStats,
 			burnRate:     msg.burnRate,
 			block:        msg.block,
+			peakBlock:    msg.peakBlock,
 		})
 		m.activeTab = TabDashboard
 		return m, nil
@@ -264,6 +363,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case statsLoadedMsg:
 		m.statsView.setStats(msg.stats)
 		m.statsView.setTopSessions(msg.topSessions)
+		m.calendar.setDaily(msg.daily)
+		m.sessions.setTokens(msg.tokens)
 		return m, nil
 
 	case tickMsg:
@@ -294,13 +395,17 @@ func (m Model) View() string {
 	b.WriteString("\n")
 
 	// Content
-	switch m.activeTab {
-	case TabDashboard:
+	switch {
+	case m.showDebug:
+		b.WriteString(m.debug.view())
+	case m.activeTab == TabDashboard:
 		b.WriteString(m.dashboard.view())
-	case TabSessions:
+	case m.activeTab == TabSessions:
 		b.WriteString(m.sessions.view())
-	case TabStats:
+	case m.activeTab == TabStats:
 		b.WriteString(m.statsView.view())
+	case m.activeTab == TabCalendar:
+		b.WriteString(m.calendar.view())
 	}
 
 	// Status bar
@@ -319,6 +424,12 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
+	// While typing a session filter, keys other than ctrl+c edit the filter.
+	if m.activeTab == TabSessions && m.sessions.filtering && msg.Type != tea.KeyCtrlC {
+		m.sessions.filterKey(msg)
+		return m, nil
+	}
+
 	switch {
 	case key.Matches(msg, m.keys.Quit):
 		m.cleanup()
@@ -328,19 +439,38 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.showHelp = true
 		return m, nil
 
+	case key.Matches(msg, m.keys.Debug):
+		m.showDebug = !m.showDebug
+		return m, nil
+
 	case key.Matches(msg, m.keys.Tab),
 		key.Matches(msg, m.keys.ShiftTab),
 		key.Matches(msg, m.keys.Number1),
 		key.Matches(msg, m.keys.Number2),
-		key.Matches(msg, m.keys.Number3):
+		key.Matches(msg, m.keys.Number3),
+		key.Matches(msg, m.keys.Number4):
 		m.activeTab = m.resolveTab(msg)
+		m.showDebug = false
 		return m, nil
 
 	case key.Matches(msg, m.keys.Refresh):
 		return m, m.handleRefresh()
 
+	case key.Matches(msg, m.keys.Filter),
+		key.Matches(msg, m.keys.SortDate),
+		key.Matches(msg, m.keys.SortToken),
+		key.Matches(msg, m.keys.SortName):
+		if m.activeTab == TabSessions && !m.showDebug {
+			m.handleSessionsKey(msg)
+		}
+		return m, nil
+
 	case key.Matches(msg, m.keys.Up),
 		key.Matches(msg, m.keys.Down),
+		key.Matches(msg, m.keys.Left),
+		key.Matches(msg, m.keys.Right),
+		key.Matches(msg, m.keys.PrevMonth),
+		key.Matches(msg, m.keys.NextMonth),
 		key.Matches(msg, m.keys.Enter),
 		key.Matches(msg, m.keys.Escape):
 		return m.handleViewKey(msg)
@@ -360,8 +490,10 @@ func (m Model) resolveTab(msg tea.KeyMsg) Tab {
 		return TabDashboard
 	case key.Matches(msg, m.keys.Number2):
 		return TabSessions
-	default:
+	case key.Matches(msg, m.keys.Number3):
 		return TabStats
+	default:
+		return TabCalendar
 	}
 }
 
@@ -374,8 +506,14 @@ func (m Model) handleRefresh() tea.Cmd {
 	return tea.Batch(m.loadSessions(), m.loadStats())
 }
 
-// handleViewKey dispatches Up/Down/Enter/Escape to view-specific logic.
+// handleViewKey dispatches navigation, Enter, and Escape to view-specific
+// logic.
 func (m Model) handleViewKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.activeTab == TabCalendar {
+		m.handleCalendarKey(msg)
+		return m, nil
+	}
+
 	switch {
 	case key.Matches(msg, m.keys.Up):
 		if m.activeTab == TabSessions {
@@ -395,10 +533,46 @@ func (m Model) handleViewKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if m.activeTab == TabDashboard && m.dashboard.hasDetail() {
 			m.dashboard.clearDetail()
 		}
+		if m.activeTab == TabSessions {
+			m.sessions.clearFilter()
+		}
 	}
 	return m, nil
 }
 
+// handleSessionsKey starts the session filter or changes the sort order.
+func (m *Model) handleSessionsKey(msg tea.KeyMsg) {
+	switch {
+	case key.Matches(msg, m.keys.Filter):
+		m.sessions.startFilter()
+	case key.Matches(msg, m.keys.SortDate):
+		m.sessions.setSort(sortByDate)
+	case key.Matches(msg, m.keys.SortToken):
+		m.sessions.setSort(sortByTokens)
+	case key.Matches(msg, m.keys.SortName):
+		m.sessions.setSort(sortByName)
+	}
+}
+
+// handleCalendarKey moves the calendar selection. Rows are weekdays, so
+// up and down move by a day and left and right by a week.
+func (m *Model) handleCalendarKey(msg tea.KeyMsg) {
+	switch {
+	case key.Matches(msg, m.keys.Up):
+		m.calendar.moveDays(-1)
+	case key.Matches(msg, m.keys.Down):
+		m.calendar.moveDays(1)
+	case key.Matches(msg, m.keys.Left):
+		m.calendar.moveDays(-7)
+	case key.Matches(msg, m.keys.Right):
+		m.calendar.moveDays(7)
+	case key.Matches(msg, m.keys.PrevMonth):
+		m.calendar.moveMonths(-1)
+	case key.Matches(msg, m.keys.NextMonth):
+		m.calendar.moveMonths(1)
+	}
+}
+
 // Rendering
 
 func (m Model) renderTabs() string {
@@ -412,7 +586,7 @@ func (m Model) renderTabs() string {
 		}
 	}
 	tabBar := lipgloss.JoinHorizontal(lipgloss.Bottom, tabs...)
-	gap := tabGapStyle.Render(strings.Repeat("─", max(0, m.width-lipgloss.Width(tabBar))))
+	gap := tabGapStyle.Render(strings.Repeat(tabGapGlyph, max(0, m.width-lipgloss.Width(tabBar))))
 	return tabBar + gap
 }
 
@@ -420,12 +594,18 @@ func (m Model) renderStatusBar() string {
 	left := statusKeyStyle.Render("q") + statusDescStyle.Render("quit") + " " +
 		statusKeyStyle.Render("tab") + statusDescStyle.Render("switch") + " " +
 		statusKeyStyle.Render("?") + statusDescStyle.Render("help") + " " +
-		statusKeyStyle.Render("r") + statusDescStyle.Render("refresh")
+		statusKeyStyle.Render("r") + statusDescStyle.Render("refresh") + " " +
+		statusKeyStyle.Render("d") + statusDescStyle.Render("debug")
 
-	if m.activeTab == TabSessions {
+	switch {
+	case m.showDebug:
+		// The debug panel hides the active tab's keys.
+	case m.activeTab == TabSessions:
 		left += " " + statusKeyStyle.Render("enter") + statusDescStyle.Render("select")
-	}
-	if m.activeTab == TabDashboard && m.dashboard.hasDetail() {
+	case m.activeTab == TabCalendar:
+		left += " " + statusKeyStyle.Render("arrows") + statusDescStyle.Render("day") + " " +
+			statusKeyStyle.Render("[ ]") + statusDescStyle.Render("month")
+	case m.activeTab == TabDashboard && m.dashboard.hasDetail():
 		left += " " + statusKeyStyle.Render("esc") + statusDescStyle.Render("back to live")
 	}
 
@@ -482,13 +662,60 @@ func (m Model) receiveUpdate() tea.Msg {
 	}
 }
 
+// waitForDebugEvent waits for the next watcher event or file read. The
+// monitor closes the channel on shutdown, which ends the wait.
+func (m Model) waitForDebugEvent() tea.Cmd {
+	liveMonitor, ok := m.mon.(interface {
+		DebugEvents() <-chan monitor.DebugEvent
+	})
+	if !ok {
+		return nil
+	}
+	return func() tea.Msg {
+		event, ok := <-liveMonitor.DebugEvents()
+		if !ok {
+			return nil
+		}
+		return debugEventMsg(event)
+	}
+}
+
 func (m Model) loadSessions() tea.Cmd {
 	return func() tea.Msg {
 		sessions, err := m.disc.Discover()
 		if err != nil {
 			return errMsg{err}
 		}
-		return sessionsLoadedMsg(sessions)
+		names := make(map[string]string)
+		if metas, listErr := m.sessionMgr.List(); listErr == nil {
+			m.recordStates(metas, sessions)
+			for _, meta := range metas {
+				names[meta.UUID] = meta.Name
+			}
+		}
+		return sessionsLoadedMsg{sessions: sessions, names: names}
+	}
+}
+
+// recordStates saves changed lifecycle states of named sessions in their
+// metadata. Failures are only logged.
+func (m Model) recordStates(metas []*session.Metadata, files []discovery.SessionFile) {
+	th := m.cfg.Session.Thresholds()
+	now := time.Now()
+	modTimes := make(map[string]int64, len(files))
+	for _, f := range files {
+		modTimes[f.SessionID] = max(modTimes[f.SessionID], f.ModTime)
+	}
+	for _, meta := range metas {
+		modTime, ok := modTimes[meta.UUID]
+		if !ok {
+			continue
+		}
+		if st := th.StateAt(time.Unix(modTime, 0), now); st != meta.State {
+			if err := m.sessionMgr.SetState(meta.UUID, st, now); err != nil {
+				m.log.Warn("failed to record session state", "session", meta.UUID, "error", err)
+			}
+		}
 	}
 }
 
@@ -500,8 +727,10 @@ func (m Model) loadStats() tea.Cmd {
 		}
 
 		agg := aggregator.New(aggregator.Config{
-			TrackPercentiles: true,
+			TrackPercentiles: !m.lowPower,
 		})
+		daily := make(map[string]rollup.Totals)
+		tokens := make(map[string]int, len(sessions))
 
 		ctx := context.Background()
 		for _, sess := range sessions {
@@ -513,12 +742,20 @@ func (m Model) loadStats() tea.Cmd {
 			}
 			for _, entry := range entries {
 				agg.Add(entry)
+				tokens[sess.FilePath] += entry.Message.Usage.TotalTokens()
+
+				date := entry.Timestamp.Format(rollup.DateLayout)
+				totals := daily[date]
+				totals.AddEntry(entry)
+				daily[date] = totals
 			}
 		}
 
 		return statsLoadedMsg{
 			stats:       agg.Stats(),
 			topSessions: agg.TopSessions(10),
+			daily:       daily,
+			tokens:      tokens,
 		}
 	}
 }
@@ -535,9 +772,9 @@ func (m Model) loadSessionDetail(sessionID, filePath, projectPath string) tea.Cm
 		}
 
 		// Three aggregators: past, current, total
-		pastAgg := aggregator.New(aggregator.Config{TrackPercentiles: true})
-		curAgg := aggregator.New(aggregator.Config{TrackPercentiles: true})
-		totalAgg := aggregator.New(aggregator.Config{TrackPercentiles: true})
+		pastAgg := aggregator.New(aggregator.Config{TrackPercentiles: !m.lowPower})
+		curAgg := aggregator.New(aggregator.Config{TrackPercentiles: !m.lowPower})
+		totalAgg := aggregator.New(aggregator.Config{TrackPercentiles: !m.lowPower})
 
 		for _, entry := range entries {
 			totalAgg.Add(entry)
@@ -557,12 +794,13 @@ func (m Model) loadSessionDetail(sessionID, filePath, projectPath string) tea.Cm
 			totalStats:   totalAgg.Stats(),
 			burnRate:     totalAgg.BurnRate(sessionID, 5*time.Minute),
 			block:        totalAgg.CurrentBillingBlock(sessionID),
+			peakBlock:    aggregator.PeakBlockTokens(totalAgg.BillingBlocks(sessionID)),
 		}
 	}
 }
 
 func (m Model) tick() tea.Cmd {
-	return tea.Tick(30*time.Second, func(t time.Time) tea.Msg {
+	return tea.Tick(m.statsInterval, func(t time.Time) tea.Msg {
 		return tickMsg(t)
 	})
 }
@@ -0,0 +1,136 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/0xmhha/token-monitor/pkg/calendar"
+	"github.com/0xmhha/token-monitor/pkg/rollup"
+)
+
+// calendarView renders a month of daily usage as a heat map with a
+// selectable day.
+type calendarView struct {
+	daily    map[string]rollup.Totals
+	selected time.Time // selected day; its month is shown
+	width    int
+	height   int
+}
+
+func newCalendarView() calendarView {
+	now := time.Now()
+	return calendarView{
+		selected: time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()),
+	}
+}
+
+func (v *calendarView) setSize(width, height int) {
+	v.width = width
+	v.height = height
+}
+
+func (v *calendarView) setDaily(daily map[string]rollup.Totals) {
+	v.daily = daily
+}
+
+// moveDays moves the selection, switching months at the edges.
+func (v *calendarView) moveDays(n int) {
+	v.selected = v.selected.AddDate(0, 0, n)
+}
+
+// moveMonths moves the selection to the same day in another month,
+// clamped to that month's last day.
+func (v *calendarView) moveMonths(n int) {
+	first := time.Date(v.selected.Year(), v.selected.Month(), 1, 0, 0, 0, 0, v.selected.Location())
+	target := first.AddDate(0, n, 0)
+	lastDay := target.AddDate(0, 1, -1).Day()
+	v.selected = target.AddDate(0, 0, min(v.selected.Day(), lastDay)-1)
+}
+
+func (v *calendarView) view() string {
+	if v.daily == nil {
+		return lipgloss.Place(
+			v.width, v.height,
+			lipgloss.Center, lipgloss.Center,
+			mutedStyle.Render("Loading calendar..."),
+		)
+	}
+
+	month := calendar.Build(v.selected, v.daily)
+
+	title := titleStyle.Render(v.selected.Format("January 2006")) + "  " +
+		subtitleStyle.Render(fmt.Sprintf("%s tokens, $%.2f, %d active day(s)",
+			formatNum(month.Total.TotalTokens()), month.Total.CostUSD, month.ActiveDays))
+
+	sections := []string{
+		title,
+		"",
+		panelStyle.Render(v.grid(month)),
+		"",
+		v.dayPanel(&month.Days[v.selected.Day()-1]),
+	}
+	return strings.Join(sections, "\n")
+}
+
+// grid renders the heat map with the selected day highlighted.
+func (v *calendarView) grid(month calendar.Month) string {
+	grid := month.Grid()
+	labels := []string{"Mon", "Tue", "Wed", "Thu", "Fri", "Sat", "Sun"}
+
+	var b strings.Builder
+	b.WriteString("    ")
+	for week := range grid[0] {
+		for _, row := range grid {
+			if day := row[week]; day != nil {
+				b.WriteString(mutedStyle.Render(fmt.Sprintf("%2d ", day.Date.Day())))
+				break
+			}
+		}
+	}
+
+	for i, row := range grid {
+		b.WriteString("\n" + mutedStyle.Render(labels[i]) + " ")
+		for _, day := range row {
+			if day == nil {
+				b.WriteString("   ")
+				continue
+			}
+			cell := strings.Repeat(calendarGlyphs[day.Level], 2)
+			if day.Date.Equal(v.selected) {
+				b.WriteString(tableSelectedStyle.Render(cell) + " ")
+			} else {
+				b.WriteString(calendarLevelStyles[day.Level].Render(cell) + " ")
+			}
+		}
+	}
+
+	b.WriteString("\n\n    " + mutedStyle.Render("Less "))
+	for level, glyph := range calendarGlyphs {
+		b.WriteString(calendarLevelStyles[level].Render(strings.Repeat(glyph, 2)) + " ")
+	}
+	b.WriteString(mutedStyle.Render("More"))
+	return b.String()
+}
+
+// dayPanel shows the totals of the selected day.
+func (v *calendarView) dayPanel(day *calendar.Day) string {
+	title := panelTitleStyle.Render(day.Date.Format("Monday, 2006-01-02"))
+	totals := day.Totals
+	if totals.Entries == 0 {
+		return panelStyle.Render(title + "\n" + mutedStyle.Render("No usage"))
+	}
+
+	rows := []string{
+		statRow("Entries", formatNum(totals.Entries)),
+		statRow("Total Tokens", formatNum(totals.TotalTokens())),
+		statRow("Input", formatNum(totals.InputTokens)),
+		statRow("Output", formatNum(totals.OutputTokens)),
+		statRow("Cache Write", formatNum(totals.CacheCreationTokens)),
+		statRow("Cache Read", formatNum(totals.CacheReadTokens)),
+		statRow("Cost", fmt.Sprintf("$%.2f", totals.CostUSD)),
+	}
+	return panelStyle.Render(title + "\n" + strings.Join(rows, "\n"))
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 
 	"github.com/0xmhha/token-monitor/pkg/aggregator"
+	"github.com/0xmhha/token-monitor/pkg/display"
 	"github.com/0xmhha/token-monitor/pkg/monitor"
 )
 
@@ -23,20 +24,22 @@ type sessionDetail struct {
 	currentStats aggregator.Statistics // after TUI started
 	totalStats   aggregator.Statistics // all entries
 
-	burnRate aggregator.BurnRate
-	block    aggregator.BillingBlock
+	burnRate  aggregator.BurnRate
+	block     aggregator.BillingBlock
+	peakBlock int // busiest earlier block of the session
 }
 
 // dashboardView renders the real-time monitoring dashboard.
 type dashboardView struct {
 	lastUpdate *monitor.Update
 	detail     *sessionDetail // non-nil when viewing a specific session
+	blockLimit int            // monitoring.block_token_limit, 0 for none
 	width      int
 	height     int
 }
 
-func newDashboardView() dashboardView {
-	return dashboardView{}
+func newDashboardView(blockLimit int) dashboardView {
+	return dashboardView{blockLimit: blockLimit}
 }
 
 func (d *dashboardView) setSize(width, height int) {
@@ -101,7 +104,7 @@ func (d *dashboardView) view() string {
 
 	// Billing block
 	if upd.CurrentBlock.EntryCount > 0 {
-		sections = append(sections, "", d.billingPanel(upd.CurrentBlock))
+		sections = append(sections, "", d.billingPanel(upd.CurrentBlock, upd.PeakBlockTokens))
 	}
 
 	// Timeline
@@ -194,7 +197,9 @@ func (d *dashboardView) burnRatePanel(rate aggregator.BurnRate) string {
 	return panelStyle.Render(content)
 }
 
-func (d *dashboardView) billingPanel(block aggregator.BillingBlock) string {
+// billingPanel renders the current block, with a burn-down bar against the
+// block quota, or against the busiest earlier block (peak) without one.
+func (d *dashboardView) billingPanel(block aggregator.BillingBlock, peak int) string {
 	title := panelTitleStyle.Render(fmt.Sprintf("Billing Block (%s - %s UTC)",
 		block.StartTime.UTC().Format("15:04"),
 		block.EndTime.UTC().Format("15:04")))
@@ -218,16 +223,42 @@ func (d *dashboardView) billingPanel(block aggregator.BillingBlock) string {
 		}
 		rows = append(rows, statRow("Time Left", style.Render(timeLeft)))
 	}
+	rows = append(rows, d.burnDownRows(block, peak)...)
 
 	content := title + "\n" + strings.Join(rows, "\n")
 	return highlightPanelStyle.Width(d.width - 4).Render(content)
 }
 
+// burnDownRows renders the block's pace as a bar, green ahead of pace and
+// red over it, with the quota used and time elapsed. It returns nothing
+// when there is no quota to measure against.
+func (d *dashboardView) burnDownRows(block aggregator.BillingBlock, peak int) []string {
+	limit, reference := d.blockLimit, "quota"
+	if limit <= 0 {
+		limit, reference = peak, "busiest block"
+	}
+	pace, ok := block.Pace(limit, time.Now())
+	if !ok {
+		return nil
+	}
+
+	style, status := successStyle, "ahead of pace"
+	if pace.OverPace() {
+		style, status = dangerStyle, "over pace"
+	}
+	width := max(10, min(50, d.width-36))
+	return []string{
+		statRow("Burn-down", style.Render(glyphs(display.BurnDownBar(pace, width)))),
+		statRow("", mutedStyle.Render(fmt.Sprintf("%.0f%% of %s %s used, %.0f%% elapsed, ",
+			pace.Used*100, formatNum(limit), reference, pace.Elapsed*100))+style.Render(status)),
+	}
+}
+
 func (d *dashboardView) timeline(stats aggregator.Statistics) string {
 	duration := stats.LastSeen.Sub(stats.FirstSeen)
 	durationStr := ""
 	if duration > 0 {
-		durationStr = " | Duration: " + duration.Round(time.Second).String()
+		durationStr = " | Duration: " + display.FormatDurationLong(duration, 3)
 	}
 
 	return mutedStyle.Render(fmt.Sprintf("  First: %s | Last: %s%s",
@@ -272,7 +303,7 @@ func (d *dashboardView) viewSessionDetail() string {
 
 	// Billing block
 	if det.block.EntryCount > 0 {
-		sections = append(sections, "", d.billingPanel(det.block))
+		sections = append(sections, "", d.billingPanel(det.block, det.peakBlock))
 	}
 
 	// Timeline
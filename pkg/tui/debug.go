@@ -0,0 +1,103 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/0xmhha/token-monitor/pkg/monitor"
+)
+
+// debugHistory is the number of watcher events kept for the debug panel.
+const debugHistory = 50
+
+// debugView renders the most recent watcher events and file reads, for
+// diagnosing a dashboard that stops updating without restarting with
+// -log-level debug.
+type debugView struct {
+	events []monitor.DebugEvent // oldest first, at most debugHistory
+	width  int
+	height int
+}
+
+func newDebugView() debugView {
+	return debugView{}
+}
+
+func (v *debugView) setSize(width, height int) {
+	v.width = width
+	v.height = height
+}
+
+// add records an event, dropping the oldest beyond debugHistory. Events
+// are recorded while the panel is hidden, so it opens with history.
+func (v *debugView) add(event monitor.DebugEvent) {
+	if len(v.events) == debugHistory {
+		v.events = append(v.events[:0], v.events[1:]...)
+	}
+	v.events = append(v.events, event)
+}
+
+func (v *debugView) view() string {
+	var lines []string
+
+	title := titleStyle.Render("Watcher Debug") +
+		mutedStyle.Render(fmt.Sprintf("  last %d events  d to close", debugHistory))
+	lines = append(lines, title, "")
+
+	if len(v.events) == 0 {
+		lines = append(lines, mutedStyle.Render("  No watcher events or reads yet"))
+		return strings.Join(lines, "\n")
+	}
+
+	// Show the newest events that fit, newest last like a log.
+	visible := max(1, v.height-len(lines))
+	start := max(0, len(v.events)-visible)
+	for _, event := range v.events[start:] {
+		lines = append(lines, v.row(event))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// row formats one event as time, kind, path, and result.
+func (v *debugView) row(event monitor.DebugEvent) string {
+	const colTime, colOp, colResult = 10, 8, 24
+
+	op, result, style := "", "", lipgloss.NewStyle().Foreground(colorText)
+	switch event.Kind {
+	case monitor.DebugWatch:
+		op = event.Op.String()
+	case monitor.DebugRead:
+		op = "READ"
+		result = fmt.Sprintf("%d entries", event.Entries)
+		if event.Entries > 0 {
+			style = successStyle
+		}
+	case monitor.DebugWatchError:
+		op = "ERROR"
+	}
+	if event.Err != nil {
+		result = event.Err.Error()
+		style = dangerStyle
+	}
+
+	colPath := max(20, v.width-colTime-colOp-colResult-4)
+	return "  " +
+		cellLeft(event.Timestamp.Format("15:04:05"), colTime, mutedStyle) +
+		cellLeft(op, colOp, subtitleStyle) +
+		cellLeft(truncateLeft(event.Path, colPath-2), colPath, lipgloss.NewStyle().Foreground(colorText)) +
+		style.Render(truncate(result, colResult))
+}
+
+// truncateLeft shortens s to maxLen by dropping its start, which keeps
+// the file name of a long path.
+func truncateLeft(s string, maxLen int) string {
+	if len(s) <= maxLen {
+		return s
+	}
+	if maxLen <= 3 {
+		return s[len(s)-maxLen:]
+	}
+	return "..." + s[len(s)-maxLen+3:]
+}
@@ -21,8 +21,10 @@ func renderHelp(width, height int) string {
 			header: "Navigation",
 			keys: [][]string{
 				{"tab / shift+tab", "Switch tabs"},
-				{"1 / 2 / 3", "Jump to tab"},
+				{"1 - 4", "Jump to tab"},
 				{"up/k  down/j", "Navigate list"},
+				{"arrows/hjkl", "Select calendar day"},
+				{"[ / ]", "Previous/next month"},
 				{"enter", "Select item"},
 				{"esc", "Back / close"},
 			},
@@ -30,7 +32,10 @@ func renderHelp(width, height int) string {
 		{
 			header: "Actions",
 			keys: [][]string{
+				{"/", "Filter sessions"},
+				{"u / t / n", "Sort by date/tokens/name"},
 				{"r", "Refresh data"},
+				{"d", "Toggle watcher debug panel"},
 				{"?", "Toggle help"},
 				{"q / ctrl+c", "Quit"},
 			},
@@ -4,20 +4,30 @@ import "github.com/charmbracelet/bubbles/key"
 
 // KeyMap defines all key bindings for the application.
 type KeyMap struct {
-	Quit     key.Binding
-	Help     key.Binding
-	Tab      key.Binding
-	ShiftTab key.Binding
-	Refresh  key.Binding
-	Up       key.Binding
-	Down     key.Binding
-	Enter    key.Binding
-	Escape   key.Binding
-	PageUp   key.Binding
-	PageDown key.Binding
-	Number1  key.Binding
-	Number2  key.Binding
-	Number3  key.Binding
+	Quit      key.Binding
+	Help      key.Binding
+	Tab       key.Binding
+	ShiftTab  key.Binding
+	Refresh   key.Binding
+	Up        key.Binding
+	Down      key.Binding
+	Left      key.Binding
+	Right     key.Binding
+	Enter     key.Binding
+	Escape    key.Binding
+	PageUp    key.Binding
+	PageDown  key.Binding
+	PrevMonth key.Binding
+	NextMonth key.Binding
+	Filter    key.Binding
+	SortDate  key.Binding
+	SortToken key.Binding
+	SortName  key.Binding
+	Debug     key.Binding
+	Number1   key.Binding
+	Number2   key.Binding
+	Number3   key.Binding
+	Number4   key.Binding
 }
 
 // DefaultKeyMap returns the default keybindings.
@@ -51,6 +61,14 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("down", "j"),
 			key.WithHelp("down/j", "down"),
 		),
+		Left: key.NewBinding(
+			key.WithKeys("left", "h"),
+			key.WithHelp("left/h", "left"),
+		),
+		Right: key.NewBinding(
+			key.WithKeys("right", "l"),
+			key.WithHelp("right/l", "right"),
+		),
 		Enter: key.NewBinding(
 			key.WithKeys("enter"),
 			key.WithHelp("enter", "select"),
@@ -67,6 +85,34 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("pgdown"),
 			key.WithHelp("pgdn", "page down"),
 		),
+		PrevMonth: key.NewBinding(
+			key.WithKeys("["),
+			key.WithHelp("[", "previous month"),
+		),
+		NextMonth: key.NewBinding(
+			key.WithKeys("]"),
+			key.WithHelp("]", "next month"),
+		),
+		Filter: key.NewBinding(
+			key.WithKeys("/"),
+			key.WithHelp("/", "filter sessions"),
+		),
+		SortDate: key.NewBinding(
+			key.WithKeys("u"),
+			key.WithHelp("u", "sort by date"),
+		),
+		SortToken: key.NewBinding(
+			key.WithKeys("t"),
+			key.WithHelp("t", "sort by tokens"),
+		),
+		SortName: key.NewBinding(
+			key.WithKeys("n"),
+			key.WithHelp("n", "sort by name"),
+		),
+		Debug: key.NewBinding(
+			key.WithKeys("d"),
+			key.WithHelp("d", "watcher debug panel"),
+		),
 		Number1: key.NewBinding(
 			key.WithKeys("1"),
 			key.WithHelp("1", "dashboard"),
@@ -79,5 +125,9 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("3"),
 			key.WithHelp("3", "stats"),
 		),
+		Number4: key.NewBinding(
+			key.WithKeys("4"),
+			key.WithHelp("4", "calendar"),
+		),
 	}
 }
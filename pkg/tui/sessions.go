@@ -2,24 +2,65 @@ package tui
 
 import (
 	"fmt"
+	"sort"
 	"strings"
+	"time"
 
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
 	"github.com/0xmhha/token-monitor/pkg/discovery"
+	"github.com/0xmhha/token-monitor/pkg/session"
 )
 
+// sessionSort is the order of the session list.
+type sessionSort int
+
+const (
+	sortByDate   sessionSort = iota // most recently updated first
+	sortByTokens                    // most tokens first
+	sortByName                      // by name, unnamed sessions by ID
+)
+
+var sortNames = []string{"date", "tokens", "name"}
+
+// sessionRow is a session file with its name, token total, and
+// lifecycle state.
+type sessionRow struct {
+	file   discovery.SessionFile
+	name   string
+	tokens int
+	state  session.State
+}
+
+// label returns the session's name, or its ID when unnamed.
+func (r sessionRow) label() string {
+	if r.name != "" {
+		return r.name
+	}
+	return r.file.SessionID
+}
+
 // sessionsView renders the interactive session list.
 type sessionsView struct {
-	sessions []discovery.SessionFile
-	cursor   int
-	offset   int // scroll offset
-	width    int
-	height   int
+	all      []sessionRow
+	sessions []sessionRow // all, filtered and sorted
+	tokens   map[string]int
+	sortBy   sessionSort
+
+	thresholds session.Thresholds
+
+	filter    string
+	filtering bool // typing the filter
+
+	cursor int
+	offset int // scroll offset
+	width  int
+	height int
 }
 
-func newSessionsView() sessionsView {
-	return sessionsView{}
+func newSessionsView(thresholds session.Thresholds) sessionsView {
+	return sessionsView{thresholds: thresholds}
 }
 
 func (s *sessionsView) setSize(width, height int) {
@@ -27,11 +68,158 @@ func (s *sessionsView) setSize(width, height int) {
 	s.height = height
 }
 
-func (s *sessionsView) setSessions(sessions []discovery.SessionFile) {
-	s.sessions = sessions
-	if s.cursor >= len(sessions) {
-		s.cursor = max(0, len(sessions)-1)
+// setSessions replaces the session list. names maps session IDs to their
+// friendly names.
+func (s *sessionsView) setSessions(files []discovery.SessionFile, names map[string]string) {
+	now := time.Now()
+	s.all = make([]sessionRow, len(files))
+	for i, f := range files {
+		s.all[i] = sessionRow{
+			file:   f,
+			name:   names[f.SessionID],
+			tokens: s.tokens[f.FilePath],
+			state:  s.thresholds.StateAt(time.Unix(f.ModTime, 0), now),
+		}
+	}
+	s.apply()
+}
+
+// setTokens sets the token totals by session file path.
+func (s *sessionsView) setTokens(tokens map[string]int) {
+	s.tokens = tokens
+	for i := range s.all {
+		s.all[i].tokens = tokens[s.all[i].file.FilePath]
 	}
+	s.apply()
+}
+
+// setSort orders the list by sortBy.
+func (s *sessionsView) setSort(sortBy sessionSort) {
+	s.sortBy = sortBy
+	s.apply()
+}
+
+// startFilter starts typing a filter.
+func (s *sessionsView) startFilter() {
+	s.filtering = true
+}
+
+// clearFilter removes the filter. It reports whether there was one.
+func (s *sessionsView) clearFilter() bool {
+	had := s.filter != "" || s.filtering
+	s.filter = ""
+	s.filtering = false
+	s.apply()
+	return had
+}
+
+// filterKey edits the filter while typing it: enter keeps the filter,
+// esc discards it, and backspace deletes the last character.
+func (s *sessionsView) filterKey(msg tea.KeyMsg) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		s.filtering = false
+	case tea.KeyEsc:
+		s.clearFilter()
+	case tea.KeyBackspace:
+		if r := []rune(s.filter); len(r) > 0 {
+			s.filter = string(r[:len(r)-1])
+			s.apply()
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		s.filter += string(msg.Runes)
+		s.apply()
+	}
+}
+
+// matches reports whether the row contains the filter in its ID, name,
+// or project path, ignoring case.
+func (s *sessionsView) matches(r sessionRow) bool {
+	if s.filter == "" {
+		return true
+	}
+	filter := strings.ToLower(s.filter)
+	for _, field := range []string{r.file.SessionID, r.name, r.file.ProjectPath} {
+		if strings.Contains(strings.ToLower(field), filter) {
+			return true
+		}
+	}
+	return false
+}
+
+// apply recomputes the visible rows, keeping the selected session
+// selected when it is still shown.
+func (s *sessionsView) apply() {
+	var selectedPath string
+	if sel := s.selected(); sel != nil {
+		selectedPath = sel.FilePath
+	}
+
+	s.sessions = s.sessions[:0]
+	for _, r := range s.all {
+		if s.matches(r) {
+			s.sessions = append(s.sessions, r)
+		}
+	}
+	// Live sessions are listed before idle and archived ones in every
+	// sort order.
+	sort.SliceStable(s.sessions, func(i, j int) bool {
+		a, b := s.sessions[i], s.sessions[j]
+		if a.state != b.state {
+			return a.state.Rank() < b.state.Rank()
+		}
+		switch s.sortBy {
+		case sortByTokens:
+			if a.tokens != b.tokens {
+				return a.tokens > b.tokens
+			}
+		case sortByName:
+			// Named sessions first.
+			if (a.name == "") != (b.name == "") {
+				return a.name != ""
+			}
+			if a.label() != b.label() {
+				return strings.ToLower(a.label()) < strings.ToLower(b.label())
+			}
+		}
+		return a.file.ModTime > b.file.ModTime
+	})
+
+	s.cursor, s.offset = 0, 0
+	for i, r := range s.sessions {
+		if r.file.FilePath == selectedPath {
+			s.cursor = i
+			break
+		}
+	}
+	if visible := s.visibleRows(); s.cursor >= visible {
+		s.offset = s.cursor - visible + 1
+	}
+}
+
+// stateStyle returns the style of a session state.
+func stateStyle(st session.State) lipgloss.Style {
+	switch st {
+	case session.StateActive:
+		return successStyle
+	case session.StateIdle:
+		return warningStyle
+	default:
+		return mutedStyle
+	}
+}
+
+// stateCounts summarizes how many visible sessions are in each state.
+func (s *sessionsView) stateCounts() string {
+	counts := make(map[session.State]int)
+	for _, r := range s.sessions {
+		counts[r.state]++
+	}
+	parts := make([]string, 0, len(session.States))
+	for _, st := range session.States {
+		parts = append(parts, stateStyle(st).Render(fmt.Sprintf("%d %s", counts[st], st)))
+	}
+	return strings.Join(parts, mutedStyle.Render(" · "))
 }
 
 func (s *sessionsView) moveUp() {
@@ -55,13 +243,13 @@ func (s *sessionsView) moveDown() {
 
 func (s *sessionsView) selected() *discovery.SessionFile {
 	if s.cursor >= 0 && s.cursor < len(s.sessions) {
-		return &s.sessions[s.cursor]
+		return &s.sessions[s.cursor].file
 	}
 	return nil
 }
 
 func (s *sessionsView) visibleRows() int {
-	available := s.height - 6
+	available := s.height - 8
 	if available < 1 {
 		return 1
 	}
@@ -69,7 +257,7 @@ func (s *sessionsView) visibleRows() int {
 }
 
 func (s *sessionsView) view() string {
-	if len(s.sessions) == 0 {
+	if len(s.all) == 0 {
 		return lipgloss.Place(
 			s.width, s.height,
 			lipgloss.Center, lipgloss.Center,
@@ -80,17 +268,29 @@ func (s *sessionsView) view() string {
 	var lines []string
 
 	title := titleStyle.Render(fmt.Sprintf("Sessions (%d)", len(s.sessions)))
-	lines = append(lines, title, "")
+	status := fmt.Sprintf("  sort: %s", sortNames[s.sortBy])
+	if s.filtering {
+		status += fmt.Sprintf("  filter: %s_", s.filter)
+	} else if s.filter != "" {
+		status += fmt.Sprintf("  filter: %s (esc to clear)", s.filter)
+	}
+	lines = append(lines, title+mutedStyle.Render(status), "  "+s.stateCounts())
 
 	// Dynamic column widths based on terminal width
 	colNum := 5
 	colSession := 38 // full UUID
-	colProject := max(20, s.width-colNum-colSession-6)
+	colTokens := 14
+	colUpdated := 18
+	colState := 10
+	colProject := max(20, s.width-colNum-colSession-colTokens-colUpdated-colState-6)
 
 	// Header row using lipgloss cells for correct alignment
 	headerRow := "  " +
 		cellLeft("#", colNum, tableHeaderStyle) +
-		cellLeft("Session ID", colSession, tableHeaderStyle) +
+		cellLeft("Session", colSession, tableHeaderStyle) +
+		cellRight("Tokens", colTokens-2, tableHeaderStyle) + "  " +
+		cellLeft("Updated", colUpdated, tableHeaderStyle) +
+		cellLeft("State", colState, tableHeaderStyle) +
 		cellLeft("Project", colProject, tableHeaderStyle)
 	lines = append(lines, headerRow)
 
@@ -102,18 +302,36 @@ func (s *sessionsView) view() string {
 		sess := s.sessions[i]
 
 		num := fmt.Sprintf("%d", i+1)
-		project := shortenProjectPath(sess.ProjectPath)
+		label := truncate(sess.label(), colSession-2)
+		tokens := "-"
+		if sess.tokens > 0 {
+			tokens = formatNum(sess.tokens)
+		}
+		updated := time.Unix(sess.file.ModTime, 0).Format("2006-01-02 15:04")
+		project := shortenProjectPath(sess.file.ProjectPath)
+
+		// Dormant sessions are dimmed so live work stands out.
+		textStyle := lipgloss.NewStyle().Foreground(colorText)
+		if sess.state != session.StateActive {
+			textStyle = mutedStyle
+		}
 
 		row := "  " +
 			cellLeft(num, colNum, mutedStyle) +
-			cellLeft(sess.SessionID, colSession, lipgloss.NewStyle().Foreground(colorText)) +
+			cellLeft(label, colSession, textStyle) +
+			cellRight(tokens, colTokens-2, textStyle) + "  " +
+			cellLeft(updated, colUpdated, mutedStyle) +
+			cellLeft(string(sess.state), colState, stateStyle(sess.state)) +
 			cellLeft(project, colProject, subtitleStyle)
 
 		if i == s.cursor {
 			row = tableSelectedStyle.Width(s.width - 2).Render(
 				"  " +
 					cellLeft(num, colNum, tableSelectedStyle) +
-					cellLeft(sess.SessionID, colSession, tableSelectedStyle) +
+					cellLeft(label, colSession, tableSelectedStyle) +
+					cellRight(tokens, colTokens-2, tableSelectedStyle) + "  " +
+					cellLeft(updated, colUpdated, tableSelectedStyle) +
+					cellLeft(string(sess.state), colState, tableSelectedStyle) +
 					cellLeft(project, colProject, tableSelectedStyle),
 			)
 		}
@@ -121,6 +339,10 @@ func (s *sessionsView) view() string {
 		lines = append(lines, row)
 	}
 
+	if len(s.sessions) == 0 {
+		lines = append(lines, mutedStyle.Render("  No sessions match the filter"))
+	}
+
 	// Scroll indicator
 	if len(s.sessions) > visible {
 		scrollInfo := mutedStyle.Render(fmt.Sprintf("  Showing %d-%d of %d (scroll with j/k)",
@@ -128,6 +350,8 @@ func (s *sessionsView) view() string {
 		lines = append(lines, "", scrollInfo)
 	}
 
+	lines = append(lines, "", mutedStyle.Render("  / filter  u/t/n sort by date/tokens/name  enter details"))
+
 	return strings.Join(lines, "\n")
 }
 
@@ -1,6 +1,11 @@
 package tui
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/0xmhha/token-monitor/pkg/calendar"
+	"github.com/0xmhha/token-monitor/pkg/display"
+)
 
 // Color palette.
 var (
@@ -31,6 +36,13 @@ var (
 
 	tabGapStyle = lipgloss.NewStyle().
 			Foreground(colorBorder)
+
+	// tabGapGlyph draws the rule to the right of the tab bar.
+	tabGapGlyph = "─"
+
+	// glyphs maps bar and separator characters for display; identity
+	// unless ASCII output is on.
+	glyphs = func(s string) string { return s }
 )
 
 // Panel styles.
@@ -122,3 +134,29 @@ var (
 	helpDescStyle = lipgloss.NewStyle().
 			Foreground(colorSubtext)
 )
+
+// Calendar styles.
+var (
+	// calendarGlyphs shades heat map cells by level.
+	calendarGlyphs = calendar.Glyphs
+
+	// calendarLevelStyles colors heat map cells by level.
+	calendarLevelStyles = [calendar.Levels]lipgloss.Style{
+		lipgloss.NewStyle().Foreground(colorBorder),
+		lipgloss.NewStyle().Foreground(lipgloss.Color("#14532D")),
+		lipgloss.NewStyle().Foreground(lipgloss.Color("#15803D")),
+		lipgloss.NewStyle().Foreground(lipgloss.Color("#22C55E")),
+		lipgloss.NewStyle().Foreground(lipgloss.Color("#86EFAC")),
+	}
+)
+
+// useASCII switches borders, rules, bars, and calendar shades to plain
+// ASCII characters.
+func useASCII() {
+	tabGapGlyph = "-"
+	glyphs = display.ASCII
+	panelStyle = panelStyle.Border(lipgloss.ASCIIBorder())
+	highlightPanelStyle = highlightPanelStyle.Border(lipgloss.ASCIIBorder())
+	helpOverlayStyle = helpOverlayStyle.Border(lipgloss.ASCIIBorder())
+	calendarGlyphs = [calendar.Levels]string{".", ":", "+", "*", "#"}
+}